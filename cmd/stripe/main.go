@@ -13,20 +13,19 @@ import (
 func main() {
 	ctx := context.Background()
 
-	if stripe.TelemetryOptedOut(os.Getenv("STRIPE_CLI_TELEMETRY_OPTOUT")) || stripe.TelemetryOptedOut(os.Getenv("DO_NOT_TRACK")) {
-		// Proceed without the telemetry client if client opted out.
-		cmd.Execute(ctx)
-	} else {
-		// Set up the telemetry client and add it to the context
-		httpClient := &http.Client{
-			Timeout: time.Second * 3,
-		}
-		telemetryClient := &stripe.AnalyticsTelemetryClient{HTTPClient: httpClient}
-		contextWithTelemetry := stripe.WithTelemetryClient(ctx, telemetryClient)
+	// Set up the telemetry client and add it to the context. Events are still
+	// recorded locally when the client opted out, see `stripe telemetry show`.
+	httpClient := &http.Client{
+		Timeout: time.Second * 3,
+	}
+	telemetryClient := &stripe.AnalyticsTelemetryClient{
+		HTTPClient: httpClient,
+		Disabled:   stripe.TelemetryOptedOut(os.Getenv("STRIPE_CLI_TELEMETRY_OPTOUT")) || stripe.TelemetryOptedOut(os.Getenv("DO_NOT_TRACK")),
+	}
+	contextWithTelemetry := stripe.WithTelemetryClient(ctx, telemetryClient)
 
-		cmd.Execute(contextWithTelemetry)
+	cmd.Execute(contextWithTelemetry)
 
-		// Wait for all telemetry calls to finish before existing the process
-		telemetryClient.Wait()
-	}
+	// Wait for all telemetry calls to finish before existing the process
+	telemetryClient.Wait()
 }
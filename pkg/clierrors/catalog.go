@@ -0,0 +1,673 @@
+package clierrors
+
+import (
+	"strings"
+)
+
+// Code identifies a kind of error, such as CLI-1042. Codes are never reused
+// once published.
+type Code string
+
+// The codes of the catalog, grouped by tens: 100x for the configuration, 101x
+// for the usage, 102x for the network, 103x for the live mode guardrails, 104x
+// for the state and locks, 105x for the webhooks and events, 106x for the
+// plugins, 107x for the dev containers, 108x for Terminal, 109x for the
+// prompts, and 110x onwards for the other features.
+const (
+	Unclassified            Code = "CLI-1000"
+	APIKeyNotConfigured     Code = "CLI-1001"
+	DeviceNameNotConfigured Code = "CLI-1002"
+	AccountIDNotConfigured  Code = "CLI-1003"
+	ProfileExpired          Code = "CLI-1004"
+	InvalidAPIKey           Code = "CLI-1005"
+	InvalidConfigFile       Code = "CLI-1006"
+	InvalidConfigValue      Code = "CLI-1007"
+	InvalidProfileBinding   Code = "CLI-1008"
+	ConfigImportConflict    Code = "CLI-1009"
+
+	UnknownCommand      Code = "CLI-1010"
+	UnknownFlag         Code = "CLI-1011"
+	InvalidFlagValue    Code = "CLI-1012"
+	RequiredFlagMissing Code = "CLI-1013"
+	InvalidArgs         Code = "CLI-1014"
+	InvalidAmount       Code = "CLI-1015"
+	ConflictingFlags    Code = "CLI-1016"
+	TerminalRequired    Code = "CLI-1017"
+	FileExists          Code = "CLI-1018"
+	UnsupportedPlatform Code = "CLI-1019"
+
+	NetworkError        Code = "CLI-1020"
+	Timeout             Code = "CLI-1021"
+	UnexpectedResponse  Code = "CLI-1022"
+	AuthorizationFailed Code = "CLI-1023"
+
+	LiveModeNotConfirmed Code = "CLI-1030"
+	LiveModeDenied       Code = "CLI-1031"
+	LiveModeNotApproved  Code = "CLI-1032"
+	InvalidPolicyFile    Code = "CLI-1033"
+	LiveKeyNotAllowed    Code = "CLI-1034"
+
+	StateLocked          Code = "CLI-1040"
+	ProfileLockedByOther Code = "CLI-1041"
+	ProfileLocked        Code = "CLI-1042"
+	InvalidStateBackend  Code = "CLI-1043"
+	StateSyncFailed      Code = "CLI-1044"
+	StateConflict        Code = "CLI-1045"
+
+	InvalidSignatureHeader    Code = "CLI-1050"
+	SignatureMismatch         Code = "CLI-1051"
+	WebhookEndpointNotDeleted Code = "CLI-1052"
+	EventResendFailed         Code = "CLI-1053"
+	NoWebhookEndpoints        Code = "CLI-1054"
+
+	PluginNoFixtures        Code = "CLI-1060"
+	PluginNotFound          Code = "CLI-1061"
+	PluginIncompatible      Code = "CLI-1062"
+	PluginNoMatchingRelease Code = "CLI-1063"
+	PluginNotVerified       Code = "CLI-1064"
+	PluginAccessDenied      Code = "CLI-1065"
+	InvalidPluginArchive    Code = "CLI-1066"
+	PluginDownloadFailed    Code = "CLI-1067"
+	PluginConflict          Code = "CLI-1068"
+
+	DevcontainerNotJSON     Code = "CLI-1070"
+	DevcontainerNotDetected Code = "CLI-1071"
+	InvalidDevcontainer     Code = "CLI-1072"
+
+	NoReadersRegistered  Code = "CLI-1080"
+	ReaderUnreachable    Code = "CLI-1081"
+	InvalidReaderCode    Code = "CLI-1082"
+	ReaderRequestFailed  Code = "CLI-1083"
+	ReaderPaymentTimeout Code = "CLI-1084"
+
+	SelectionCanceled Code = "CLI-1090"
+
+	UnsupportedEvent          Code = "CLI-1100"
+	UnsupportedFixtureVersion Code = "CLI-1101"
+	InvalidFixture            Code = "CLI-1102"
+	InvalidCheckpoint         Code = "CLI-1103"
+	ExportNotSupported        Code = "CLI-1104"
+
+	PasskeyNotRegistered Code = "CLI-1110"
+	PasskeyNotVerified   Code = "CLI-1111"
+	PasskeyNotUsed       Code = "CLI-1112"
+
+	InvalidReplayProfile Code = "CLI-1120"
+	NoEventsToReplay     Code = "CLI-1121"
+	ReplayTriggersFailed Code = "CLI-1122"
+
+	BranchNotMerged Code = "CLI-1130"
+	CleanupFailed   Code = "CLI-1131"
+
+	InvalidCronExpression Code = "CLI-1140"
+	InvalidScheduleFile   Code = "CLI-1141"
+	ScheduledJobNotFound  Code = "CLI-1142"
+	CommandNotSchedulable Code = "CLI-1143"
+
+	InvalidPriceBook Code = "CLI-1150"
+
+	InvalidExpectations Code = "CLI-1160"
+	InvalidOpenAPISpec  Code = "CLI-1161"
+	IntegrationDrift    Code = "CLI-1162"
+
+	SampleNotFound       Code = "CLI-1170"
+	SampleCreationFailed Code = "CLI-1171"
+
+	InvalidQuery Code = "CLI-1180"
+
+	InvalidOTLPConfig Code = "CLI-1190"
+	OTLPExportFailed  Code = "CLI-1191"
+
+	PublishableKeyNotConfigured Code = "CLI-1200"
+
+	ConfigProblems Code = "CLI-1210"
+)
+
+// Entry documents a code of the catalog
+type Entry struct {
+	Code        Code   `json:"code"`
+	Title       string `json:"title"`
+	Explanation string `json:"explanation"`
+	Remediation string `json:"remediation"`
+}
+
+var catalog = []Entry{
+	{
+		Code:        Unclassified,
+		Title:       "Unclassified error",
+		Explanation: "The CLI failed with an error that has no code of its own yet. The message describes what went wrong.",
+		Remediation: "Run the command again with --verbose to print the environment it ran in, and with --log-level debug for the details of its requests.",
+	},
+	{
+		Code:        APIKeyNotConfigured,
+		Title:       "No API key configured",
+		Explanation: "The profile the command ran with has no API key, and none was passed with --api-key or STRIPE_API_KEY.",
+		Remediation: "Run `stripe login`, or pass a key with --api-key. In CI, set STRIPE_API_KEY or run `stripe login --ci`.",
+	},
+	{
+		Code:        DeviceNameNotConfigured,
+		Title:       "No device name configured",
+		Explanation: "The profile the command ran with has no device name, which identifies this machine to Stripe.",
+		Remediation: "Run `stripe login`, or pass a name with --device-name.",
+	},
+	{
+		Code:        AccountIDNotConfigured,
+		Title:       "No account ID configured",
+		Explanation: "The profile the command ran with doesn't know which account it belongs to.",
+		Remediation: "Run `stripe login` to configure the profile again.",
+	},
+	{
+		Code:        ProfileExpired,
+		Title:       "Profile expired",
+		Explanation: "The profile was created for a limited time, and its API keys were removed when it expired.",
+		Remediation: "Run `stripe login` to get new keys for the profile.",
+	},
+	{
+		Code:        InvalidAPIKey,
+		Title:       "Invalid API key",
+		Explanation: "The API key is malformed, such as because it's truncated or a legacy key, or it's a kind of key the command doesn't support, such as a publishable key.",
+		Remediation: "Copy a secret or restricted key from the Dashboard's API keys page, or run `stripe login` to get one.",
+	},
+	{
+		Code:        InvalidConfigFile,
+		Title:       "Invalid config file",
+		Explanation: "A config file the CLI was given, such as one exported with `stripe config export`, isn't valid TOML or doesn't have the expected fields.",
+		Remediation: "Fix the syntax error named by the message, or export the config again.",
+	},
+	{
+		Code:        InvalidConfigValue,
+		Title:       "Invalid config setting",
+		Explanation: "A setting of the config file, such as color or an alias, has a value the CLI doesn't support, or can only be changed with a dedicated command, such as the passkeys of a profile.",
+		Remediation: "Change the setting named by the message with `stripe config --set`, or with the command the message names. Run `stripe config doctor` to check the other settings.",
+	},
+	{
+		Code:        InvalidProfileBinding,
+		Title:       "Invalid profile binding",
+		Explanation: "The .stripe-profile file binding the directory, or one of its parents, to a profile doesn't name a valid profile, or the directory to bind or unbind isn't a directory or isn't bound.",
+		Remediation: "Fix or remove the .stripe-profile file named by the message, or bind the directory again with `stripe --project-name <profile> profile bind <directory>`.",
+	},
+	{
+		Code:        ConfigImportConflict,
+		Title:       "Config import conflicts",
+		Explanation: "The config being imported sets fields that are set to a different value in the current config.",
+		Remediation: "Pass --overwrite to replace the current values with the imported ones, or --keep to keep the current values.",
+	},
+	{
+		Code:        UnknownCommand,
+		Title:       "Unknown command",
+		Explanation: "The command isn't a command of the CLI or of an installed plugin.",
+		Remediation: "Run `stripe --help` for the list of commands, or `stripe plugin install` if the command comes from a plugin.",
+	},
+	{
+		Code:        UnknownFlag,
+		Title:       "Unknown flag",
+		Explanation: "The command doesn't have the flag that was passed.",
+		Remediation: "Run the command with --help for the list of its flags.",
+	},
+	{
+		Code:        InvalidFlagValue,
+		Title:       "Invalid flag value",
+		Explanation: "A flag is missing its value, or its value doesn't have the expected type, such as a duration or a number.",
+		Remediation: "Run the command with --help for the expected values of its flags.",
+	},
+	{
+		Code:        RequiredFlagMissing,
+		Title:       "Required flag missing",
+		Explanation: "The command can't run without a flag that wasn't passed.",
+		Remediation: "Pass the flags named by the message. Run the command with --help for their description.",
+	},
+	{
+		Code:        InvalidArgs,
+		Title:       "Invalid positional arguments",
+		Explanation: "The command was passed more or fewer positional arguments than it takes.",
+		Remediation: "Run the command with --help for its usage. Quote the arguments containing spaces.",
+	},
+	{
+		Code:        InvalidAmount,
+		Title:       "Invalid amount",
+		Explanation: "An amount such as $20.00 or 15,30EUR couldn't be converted to the smallest unit of its currency, such as because it has more decimals than the currency, or its currency doesn't match the currency of the request.",
+		Remediation: "Write the amount with the decimals of its currency and its currency code, such as 20.00USD or 1500JPY, or pass it in the smallest unit, such as 2000 for $20.00. Pass --strict-amounts to send amounts as written.",
+	},
+	{
+		Code:        ConflictingFlags,
+		Title:       "Conflicting flags",
+		Explanation: "Flags were passed together that can't be used together, such as two ways of selecting the same thing.",
+		Remediation: "Pass only one of the flags named by the message. Run the command with --help for their description.",
+	},
+	{
+		Code:        TerminalRequired,
+		Title:       "Interactive terminal required",
+		Explanation: "The command, or one of its flags, is interactive and the CLI isn't running in a terminal, such as in CI or with its input piped.",
+		Remediation: "Run the command in a terminal, or pass the choices it would prompt for with flags.",
+	},
+	{
+		Code:        FileExists,
+		Title:       "File already exists",
+		Explanation: "The command would write a file or a folder that already exists.",
+		Remediation: "Remove the file or folder named by the message, choose another path, or pass --force if the command has it.",
+	},
+	{
+		Code:        UnsupportedPlatform,
+		Title:       "Unsupported platform",
+		Explanation: "The command doesn't support the operating system the CLI runs on, such as for opening a browser.",
+		Remediation: "Run the command on macOS, Linux or Windows, or do what it would do by hand, such as opening the URL it prints.",
+	},
+	{
+		Code:        NetworkError,
+		Title:       "Network error",
+		Explanation: "The CLI couldn't connect to the server, such as because the network is down, a proxy or firewall blocks it, or the hostname doesn't resolve.",
+		Remediation: "Check the network connection and the HTTPS_PROXY environment variable, then run `stripe status` to check the status of Stripe.",
+	},
+	{
+		Code:        Timeout,
+		Title:       "Timed out",
+		Explanation: "The command didn't finish in the time it was given.",
+		Remediation: "Run the command again, with a longer timeout if it has a flag for it.",
+	},
+	{
+		Code:        UnexpectedResponse,
+		Title:       "Unexpected response",
+		Explanation: "A server, such as Stripe, a Terminal reader or the endpoint events are resent to, answered with a status or a body the CLI didn't expect.",
+		Remediation: "Run the command again. If it keeps failing, run it with --log-level debug, and run `stripe status` to check the status of Stripe.",
+	},
+	{
+		Code:        AuthorizationFailed,
+		Title:       "Session not authorized",
+		Explanation: "Stripe refused to authorize the session of listen or logs tail, such as because the API key was revoked or lacks the permissions, or reauthorizing the session once it expired kept failing.",
+		Remediation: "Run `stripe login` to get a new key, or pass a secret key with --api-key, then run the command again.",
+	},
+	{
+		Code:        LiveModeNotConfirmed,
+		Title:       "Live mode request not confirmed",
+		Explanation: "The request would have changed live mode objects, and the guardrails of the CLI require a confirmation for it.",
+		Remediation: "Pass --live to ask for live mode changes explicitly, or confirm the request when prompted.",
+	},
+	{
+		Code:        LiveModeDenied,
+		Title:       "Live mode request denied",
+		Explanation: "The policy file of the config folder denies the live mode request, so the CLI never makes it.",
+		Remediation: "Make the change in the Dashboard, or remove the pattern named by the message from the [live] deny list of policy.toml if it's no longer wanted.",
+	},
+	{
+		Code:        LiveModeNotApproved,
+		Title:       "Live mode request not approved",
+		Explanation: "The policy file of the config folder requires an environment variable to be set to approve the live mode requests that change data.",
+		Remediation: "Set the environment variable named by the message for the run that should change live mode data.",
+	},
+	{
+		Code:        InvalidPolicyFile,
+		Title:       "Invalid policy file",
+		Explanation: "The policy.toml file of the config folder isn't valid TOML, or one of its patterns isn't valid.",
+		Remediation: "Fix the error named by the message in policy.toml, or remove the file to go back to the default guardrails.",
+	},
+	{
+		Code:        LiveKeyNotAllowed,
+		Title:       "Live mode key not allowed",
+		Explanation: "The command only works with test mode objects, such as because it deletes or creates them in bulk, and was given a live mode key.",
+		Remediation: "Pass a test mode key starting with sk_test_ or rk_test_ with --api-key, or use a profile with test mode keys.",
+	},
+	{
+		Code:        StateLocked,
+		Title:       "State locked",
+		Explanation: "The state shared through a backend is being changed by another session, which holds its lock.",
+		Remediation: "Wait for the other session to finish and run the command again.",
+	},
+	{
+		Code:        ProfileLockedByOther,
+		Title:       "Profile locked by someone else",
+		Explanation: "The profile can't be unlocked because its lock is held by another owner.",
+		Remediation: "Ask the owner of the lock to run `stripe profile unlock`, or pass --force to unlock it anyway.",
+	},
+	{
+		Code:        ProfileLocked,
+		Title:       "Profile locked",
+		Explanation: "Someone locked the profile with `stripe profile lock`, so the commands changing the objects of its account, such as fixtures and trigger, are refused until the lock is released or expires.",
+		Remediation: "Wait for the lock with `stripe profile lock --wait 10m`, use another profile with --project-name, or set STRIPE_CLI_LOCK_OWNER to the owner of the lock if you're running on their behalf.",
+	},
+	{
+		Code:        InvalidStateBackend,
+		Title:       "Invalid state backend",
+		Explanation: "STRIPE_CLI_STATE_BACKEND isn't a path, or a file://, s3:// or gs:// URL with a bucket, or the credentials of the bucket aren't set.",
+		Remediation: "Set STRIPE_CLI_STATE_BACKEND to a URL such as s3://bucket/prefix, and AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY to credentials for it.",
+	},
+	{
+		Code:        StateSyncFailed,
+		Title:       "State sync failed",
+		Explanation: "The state couldn't be pulled from or pushed to the backend set in STRIPE_CLI_STATE_BACKEND, such as because the bucket can't be reached or the credentials can't access it.",
+		Remediation: "Check that the bucket exists and that the credentials can read and write it. Commands run with the local state when the state can't be pulled.",
+	},
+	{
+		Code:        StateConflict,
+		Title:       "State changed by another session",
+		Explanation: "Some state files weren't pushed because another session changed them in the backend since they were pulled. Its changes were kept.",
+		Remediation: "Run the command again to make its changes on top of the other session's.",
+	},
+	{
+		Code:        InvalidSignatureHeader,
+		Title:       "Invalid Stripe-Signature header",
+		Explanation: "The Stripe-Signature header doesn't have the expected format, a timestamp and one or more signatures.",
+		Remediation: "Pass the header exactly as Stripe sent it, such as `t=1600000000,v1=5257a869...`.",
+	},
+	{
+		Code:        SignatureMismatch,
+		Title:       "Signature mismatch",
+		Explanation: "The Stripe-Signature header wasn't computed for the payload with the signing secret, such as because the payload was changed or the secret is the one of another endpoint.",
+		Remediation: "Verify the payload exactly as it was received, with the signing secret of the endpoint it was sent to.",
+	},
+	{
+		Code:        WebhookEndpointNotDeleted,
+		Title:       "Webhook endpoint not deleted",
+		Explanation: "The webhook endpoint registered for the listen session couldn't be deleted when it ended.",
+		Remediation: "Delete the endpoint named by the message with `stripe webhook_endpoints delete`, or in the Dashboard.",
+	},
+	{
+		Code:        EventResendFailed,
+		Title:       "Event resend failed",
+		Explanation: "Some of the events couldn't be resent, or the endpoint they were resent to didn't respond with a 2xx status.",
+		Remediation: "Check the errors printed for each event, fix the endpoint if it failed to handle them, and resend the events that failed.",
+	},
+	{
+		Code:        NoWebhookEndpoints,
+		Title:       "No webhook endpoints",
+		Explanation: "listen was asked to forward events to the webhook endpoints of the account, and the account has none.",
+		Remediation: "Add an endpoint in the Dashboard, or pass the URL to forward events to with --forward-to.",
+	},
+	{
+		Code:        PluginNoFixtures,
+		Title:       "Plugin has no fixtures",
+		Explanation: "The plugin doesn't provide any fixtures to run.",
+		Remediation: "Run `stripe plugin list` for the installed plugins, and check the documentation of the plugin.",
+	},
+	{
+		Code:        PluginNotFound,
+		Title:       "Plugin not found",
+		Explanation: "No plugin of the plugin manifest has the name, or the plugin isn't installed.",
+		Remediation: "Run `stripe plugin list` for the installed plugins, and check the spelling of the name.",
+	},
+	{
+		Code:        PluginIncompatible,
+		Title:       "Plugin incompatible",
+		Explanation: "The version of the plugin can't run with this version of the CLI, because one of them is too old for the other.",
+		Remediation: "Follow the message: run `stripe plugin upgrade` for the plugin, or update the CLI to its latest version.",
+	},
+	{
+		Code:        PluginNoMatchingRelease,
+		Title:       "No plugin release for the platform",
+		Explanation: "The version of the plugin has no build for the operating system and architecture the CLI runs on.",
+		Remediation: "Install another version of the plugin, or, on arm64 macOS and Windows, pass --allow-emulation to install the amd64 build.",
+	},
+	{
+		Code:        PluginNotVerified,
+		Title:       "Plugin not verified",
+		Explanation: "The plugin binary doesn't match the checksum of the plugin manifest, or the manifest has no checksum for it, so the CLI won't run it.",
+		Remediation: "Reinstall the plugin with `stripe plugin install`. If it keeps failing, the download may have been tampered with.",
+	},
+	{
+		Code:        PluginAccessDenied,
+		Title:       "Plugin access denied",
+		Explanation: "The account of the profile doesn't have access to the plugin.",
+		Remediation: "Check that the profile belongs to an account that was given access to the plugin, or run `stripe login` with that account.",
+	},
+	{
+		Code:        InvalidPluginArchive,
+		Title:       "Invalid plugin archive",
+		Explanation: "The archive to install a plugin from isn't a tar.gz archive with a manifest.toml and the plugin binary.",
+		Remediation: "Download the archive again, or build it with the manifest.toml and the binary at its root.",
+	},
+	{
+		Code:        PluginDownloadFailed,
+		Title:       "Plugin download failed",
+		Explanation: "The plugin or its manifest couldn't be downloaded, even after retrying.",
+		Remediation: "Check the network connection, then run the command again, with a longer --timeout or more --retries if needed.",
+	},
+	{
+		Code:        PluginConflict,
+		Title:       "Plugin conflict",
+		Explanation: "A plugin provides a trigger or a fixture step type that the CLI or another plugin already provides.",
+		Remediation: "Uninstall one of the plugins named by the message with `stripe plugin uninstall`, or ask their authors to rename the trigger or step type.",
+	},
+	{
+		Code:        DevcontainerNotJSON,
+		Title:       "devcontainer.json isn't plain JSON",
+		Explanation: "devcontainer.json has comments or trailing commas, so the CLI can't update it without losing them.",
+		Remediation: "Remove the comments and trailing commas, or make the change by hand.",
+	},
+	{
+		Code:        DevcontainerNotDetected,
+		Title:       "No dev container detected",
+		Explanation: "The command configures a dev container or Codespace, and none was detected in the workspace.",
+		Remediation: "Run the command inside the dev container or Codespace, or pass --force to configure the workspace anyway.",
+	},
+	{
+		Code:        InvalidDevcontainer,
+		Title:       "Invalid devcontainer.json",
+		Explanation: "devcontainer.json isn't a JSON object, or one of the settings the CLI updates has an unexpected type.",
+		Remediation: "Fix the setting named by the message in devcontainer.json, or make the change by hand.",
+	},
+	{
+		Code:        NoReadersRegistered,
+		Title:       "No Terminal readers registered",
+		Explanation: "The account has no Terminal readers to use.",
+		Remediation: "Register a reader with `stripe terminal quickstart`, or in the Dashboard.",
+	},
+	{
+		Code:        ReaderUnreachable,
+		Title:       "Terminal reader unreachable",
+		Explanation: "The CLI couldn't reach the reader on the local network, often because of DNS.",
+		Remediation: "Make sure the reader is online and on the same network, then run `stripe terminal readers discover` to diagnose the connection.",
+	},
+	{
+		Code:        InvalidReaderCode,
+		Title:       "Invalid reader registration code",
+		Explanation: "The reader couldn't be registered with the registration code that was entered.",
+		Remediation: "Enter the key sequence 0-7-1-3-9 on the reader to display a new code, and run the command again with it.",
+	},
+	{
+		Code:        ReaderRequestFailed,
+		Title:       "Reader request failed",
+		Explanation: "A request to the Terminal reader, or to the API for the reader, failed while taking a payment, such as to set its display or to collect the payment method.",
+		Remediation: "Make sure the reader is online, then run the command again. Run `stripe terminal readers discover` to diagnose the connection.",
+	},
+	{
+		Code:        ReaderPaymentTimeout,
+		Title:       "Reader payment timed out",
+		Explanation: "No payment method was presented to the reader in time.",
+		Remediation: "Run the command again and present the card to the reader when asked.",
+	},
+	{
+		Code:        SelectionCanceled,
+		Title:       "Selection canceled",
+		Explanation: "A prompt was canceled without choosing an option.",
+		Remediation: "Run the command again and make a choice, or pass the choice with a flag.",
+	},
+	{
+		Code:        UnsupportedEvent,
+		Title:       "Unsupported event",
+		Explanation: "The event can't be triggered, as no fixture of the CLI or of the installed plugins creates it.",
+		Remediation: "Run `stripe trigger --help` for the events that can be triggered, or write a fixture creating the event and run it with `stripe fixtures`.",
+	},
+	{
+		Code:        UnsupportedFixtureVersion,
+		Title:       "Unsupported fixture version",
+		Explanation: "The fixture file was written for a version of the fixture format that this version of the CLI doesn't read.",
+		Remediation: "Update the CLI to its latest version, or change the template_version of the _meta of the fixture.",
+	},
+	{
+		Code:        InvalidFixture,
+		Title:       "Invalid fixture",
+		Explanation: "A fixture file references a step or a value that doesn't exist, or its steps depend on each other in a way that can't be run.",
+		Remediation: "Fix the reference named by the message. References are written like ${step_name:field.path} and only name earlier steps.",
+	},
+	{
+		Code:        InvalidCheckpoint,
+		Title:       "Invalid fixture checkpoint",
+		Explanation: "The checkpoint saved by the last failed run of the fixture, to resume it, can't be read.",
+		Remediation: "Run the fixture without --resume-from-last-failure to run all of its steps again.",
+	},
+	{
+		Code:        ExportNotSupported,
+		Title:       "Export not supported",
+		Explanation: "The object can't be exported as a fixture, as its kind isn't supported.",
+		Remediation: "Export a supported object such as a customer, a subscription or a payment intent, or write the fixture by hand.",
+	},
+	{
+		Code:        PasskeyNotRegistered,
+		Title:       "No passkey registered",
+		Explanation: "The profile has no passkey registered for the operation, such as when the passkey was removed or the profile requires one that wasn't registered yet.",
+		Remediation: "Register a passkey with `stripe passkey register`.",
+	},
+	{
+		Code:        PasskeyNotVerified,
+		Title:       "Passkey not verified",
+		Explanation: "The passkey used couldn't be verified, such as because it isn't the one registered for the profile, or the browser reported invalid data.",
+		Remediation: "Use the passkey registered for the profile. If it was lost, remove it from the config with `stripe passkey remove` and register a new one.",
+	},
+	{
+		Code:        PasskeyNotUsed,
+		Title:       "Passkey not used",
+		Explanation: "The passkey wasn't used in the browser, such as because the prompt was canceled or timed out.",
+		Remediation: "Run the command again and confirm with the passkey in the browser window it opens.",
+	},
+	{
+		Code:        InvalidReplayProfile,
+		Title:       "Invalid replay profile",
+		Explanation: "The file to replay isn't a replay profile built by `stripe replay-profile build`, was built by a newer CLI, or has no events.",
+		Remediation: "Build the profile again with `stripe replay-profile build`.",
+	},
+	{
+		Code:        NoEventsToReplay,
+		Title:       "No events to replay",
+		Explanation: "The window of the replay profile has no events, or none of its event types can be triggered.",
+		Remediation: "Build the profile from a longer window, or from a window in which the account received events that `stripe trigger` supports.",
+	},
+	{
+		Code:        ReplayTriggersFailed,
+		Title:       "Replay triggers failed",
+		Explanation: "Some of the events of the replay couldn't be triggered.",
+		Remediation: "Check the errors printed for each trigger. Run the command again with a lower --speed or --max-parallel if they were rate limited.",
+	},
+	{
+		Code:        BranchNotMerged,
+		Title:       "Branch not merged",
+		Explanation: "The objects of a git branch are only cleaned up once it's merged, and the branch wasn't merged, or whether it was couldn't be checked.",
+		Remediation: "Merge the branch first, or pass --force to clean it up anyway.",
+	},
+	{
+		Code:        CleanupFailed,
+		Title:       "Cleanup failed",
+		Explanation: "Some of the objects of the branch couldn't be deleted or archived.",
+		Remediation: "Check the errors printed for each object, then run the command again to retry the ones left.",
+	},
+	{
+		Code:        InvalidCronExpression,
+		Title:       "Invalid cron expression",
+		Explanation: "The schedule isn't a cron expression of 5 fields, minute, hour, day of the month, month and day of the week, with values in their range.",
+		Remediation: "Write the schedule like \"0 9 * * 1\", for every Monday at 9:00.",
+	},
+	{
+		Code:        InvalidScheduleFile,
+		Title:       "Invalid schedule file",
+		Explanation: "The file of the scheduled jobs in the config folder isn't valid.",
+		Remediation: "Fix the error named by the message in the file, or remove it and schedule the jobs again.",
+	},
+	{
+		Code:        ScheduledJobNotFound,
+		Title:       "Scheduled job not found",
+		Explanation: "No scheduled job has the ID.",
+		Remediation: "Run `stripe schedule list` for the IDs of the scheduled jobs.",
+	},
+	{
+		Code:        CommandNotSchedulable,
+		Title:       "Command not schedulable",
+		Explanation: "Only the commands of the CLI that run to completion can be scheduled, and the command isn't one of them.",
+		Remediation: "Schedule a command such as `stripe fixtures` or `stripe trigger`, written after --.",
+	},
+	{
+		Code:        InvalidPriceBook,
+		Title:       "Invalid price book",
+		Explanation: "The price book CSV doesn't have the expected columns, or one of its lines has a missing or invalid value, or names a product that doesn't exist or isn't unique.",
+		Remediation: "Fix the line named by the message. Run `stripe prices sync --help` for the columns and their values.",
+	},
+	{
+		Code:        InvalidExpectations,
+		Title:       "Invalid expectations file",
+		Explanation: "The file of the fields the integration expects isn't valid, or lists no objects nor events.",
+		Remediation: "Fix the error named by the message. Run `stripe lint-integration --help` for the format of the file.",
+	},
+	{
+		Code:        InvalidOpenAPISpec,
+		Title:       "Invalid OpenAPI specification",
+		Explanation: "The OpenAPI specification to lint against couldn't be downloaded, or isn't an OpenAPI specification with schemas.",
+		Remediation: "Pass the path or URL of the OpenAPI specification of the Stripe API with --spec, or leave it out to use the default one.",
+	},
+	{
+		Code:        IntegrationDrift,
+		Title:       "Integration drifted",
+		Explanation: "Some of the fields the integration expects are missing from the objects or events of the account, or have another type.",
+		Remediation: "Update the integration or the expectations file for the fields printed as drifted.",
+	},
+	{
+		Code:        SampleNotFound,
+		Title:       "Sample not found",
+		Explanation: "The sample, or the server or client of its integration, doesn't exist.",
+		Remediation: "Run `stripe samples list` for the samples, and create the sample without flags to pick its integration, server and client.",
+	},
+	{
+		Code:        SampleCreationFailed,
+		Title:       "Sample creation failed",
+		Explanation: "The sample couldn't be created, such as because its .env file couldn't be set up with the keys of the profile.",
+		Remediation: "Set the keys in the .env file of the sample by hand, or run `stripe login` and create the sample again.",
+	},
+	{
+		Code:        InvalidQuery,
+		Title:       "Invalid query",
+		Explanation: "The query passed with --query has a syntax error, calls a function with the wrong arguments, or was applied to a response that isn't JSON.",
+		Remediation: "Fix the query at the position named by the message. Run the command with --help for the syntax and the functions of queries.",
+	},
+	{
+		Code:        InvalidOTLPConfig,
+		Title:       "Invalid OpenTelemetry configuration",
+		Explanation: "The OTLP endpoint isn't an http or https URL, or OTEL_EXPORTER_OTLP_HEADERS isn't written as key1=value1,key2=value2.",
+		Remediation: "Pass the endpoint of the collector, such as http://localhost:4318, and fix OTEL_EXPORTER_OTLP_HEADERS.",
+	},
+	{
+		Code:        OTLPExportFailed,
+		Title:       "OpenTelemetry export failed",
+		Explanation: "The collector refused the logs it was sent.",
+		Remediation: "Check the logs of the collector for why, and that its OTLP/HTTP receiver accepts JSON.",
+	},
+	{
+		Code:        PublishableKeyNotConfigured,
+		Title:       "No publishable key configured",
+		Explanation: "The command runs a flow in the browser, which needs a test mode publishable key, and the profile has none.",
+		Remediation: "Run `stripe login` to configure the profile again, or pass a key with --publishable-key.",
+	},
+	{
+		Code:        ConfigProblems,
+		Title:       "Configuration problems",
+		Explanation: "`stripe config doctor` found problems with the configuration, which it printed.",
+		Remediation: "Fix the problems printed, with the commands they suggest.",
+	},
+}
+
+// All returns the entries of the catalog, ordered by code
+func All() []Entry {
+	return append([]Entry(nil), catalog...)
+}
+
+// Lookup returns the entry of a code, which can be written without its CLI-
+// prefix and in any case, such as 1042 or cli-1042
+func Lookup(code string) (Entry, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !strings.HasPrefix(code, "CLI-") {
+		code = "CLI-" + code
+	}
+
+	for _, entry := range catalog {
+		if string(entry.Code) == code {
+			return entry, true
+		}
+	}
+
+	return Entry{}, false
+}
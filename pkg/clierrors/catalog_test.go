@@ -0,0 +1,55 @@
+package clierrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog(t *testing.T) {
+	var previous Code
+
+	for _, entry := range All() {
+		require.Regexp(t, `^CLI-\d{4}$`, entry.Code)
+		require.Greater(t, entry.Code, previous, "codes must be unique and in order")
+		require.NotEmpty(t, entry.Title, entry.Code)
+		require.NotEmpty(t, entry.Explanation, entry.Code)
+		require.NotEmpty(t, entry.Remediation, entry.Code)
+
+		previous = entry.Code
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, code := range []string{"CLI-1042", "cli-1042", "1042", " CLI-1042\n"} {
+		entry, ok := Lookup(code)
+		require.True(t, ok, code)
+		require.Equal(t, ProfileLocked, entry.Code)
+	}
+
+	_, ok := Lookup("CLI-9999")
+	require.False(t, ok)
+}
+
+func TestCodeOf(t *testing.T) {
+	err := Errorf(ProfileLocked, "profile %s is locked", "default")
+	require.EqualError(t, err, "profile default is locked")
+
+	code, ok := CodeOf(fmt.Errorf("%w. Wait for it", err))
+	require.True(t, ok)
+	require.Equal(t, ProfileLocked, code)
+
+	sentinel := New(StateLocked, "locked")
+	require.ErrorIs(t, fmt.Errorf("saving: %w", sentinel), sentinel)
+
+	wrapped := Wrap(Timeout, errors.New("too slow"))
+	require.EqualError(t, wrapped, "too slow")
+	code, _ = CodeOf(wrapped)
+	require.Equal(t, Timeout, code)
+	require.Nil(t, Wrap(Timeout, nil))
+
+	_, ok = CodeOf(errors.New("plain"))
+	require.False(t, ok)
+}
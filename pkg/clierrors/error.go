@@ -0,0 +1,53 @@
+// Package clierrors gives the errors the CLI itself generates, as opposed to
+// the errors of the API, a stable code such as CLI-1042 that wrappers can
+// rely on instead of matching the message.
+package clierrors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error is an error with a code of the catalog
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New returns an error with a code and a message, like errors.New
+func New(code Code, message string) error {
+	return &Error{Code: code, Err: errors.New(message)}
+}
+
+// Errorf returns an error with a code and a formatted message, like
+// fmt.Errorf
+func Errorf(code Code, format string, a ...interface{}) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, a...)}
+}
+
+// Wrap gives a code to an error, keeping its message
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{Code: code, Err: err}
+}
+
+// CodeOf returns the code of the first error with one in the chain of err
+func CodeOf(err error) (Code, bool) {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// aliasCmd runs the command line an alias stands for, followed by the
+// arguments and flags the alias was run with
+type aliasCmd struct {
+	cmd *cobra.Command
+
+	expansion []string
+}
+
+func newAliasCmd(name, expansion, source string) (*aliasCmd, error) {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t") {
+		return nil, clierrors.Errorf(clierrors.InvalidConfigValue, "invalid alias name '%s'", name)
+	}
+
+	args, err := splitAliasArgs(expansion)
+	if err != nil {
+		return nil, clierrors.Errorf(clierrors.InvalidConfigValue, "invalid alias '%s': %w", name, err)
+	}
+
+	if len(args) == 0 {
+		return nil, clierrors.Errorf(clierrors.InvalidConfigValue, "the alias '%s' doesn't stand for a command", name)
+	}
+
+	ac := &aliasCmd{expansion: args}
+
+	ac.cmd = &cobra.Command{
+		Use:   name,
+		Short: fmt.Sprintf("Alias for `stripe %s` (%s)", expansion, source),
+		// the flags are those of the command the alias stands for
+		DisableFlagParsing: true,
+		Annotations:        map[string]string{"scope": "alias"},
+		// the hooks of the root command run with the command the alias
+		// stands for
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+		RunE:             ac.runAliasCmd,
+	}
+
+	return ac, nil
+}
+
+func (ac *aliasCmd) runAliasCmd(cmd *cobra.Command, args []string) error {
+	expanded := append(append([]string{}, ac.expansion...), args...)
+	root := cmd.Root()
+
+	if target, _, err := root.Find(expanded); err == nil && isAliasCmd(target) {
+		return clierrors.Errorf(clierrors.InvalidConfigValue, "the alias '%s' stands for another alias, '%s', which isn't supported", cmd.Name(), target.Name())
+	}
+
+	log.WithFields(log.Fields{
+		"prefix": "cmd.aliasCmd.runAliasCmd",
+	}).Debugf("Running `stripe %s`", strings.Join(expanded, " "))
+
+	// plugin commands read their arguments from os.Args
+	os.Args = append([]string{os.Args[0]}, expanded...)
+
+	root.SetArgs(expanded)
+
+	return root.ExecuteContext(cmd.Context())
+}
+
+func isAliasCmd(cmd *cobra.Command) bool {
+	return cmd.Annotations["scope"] == "alias"
+}
+
+// addAliasCmds adds the aliases from source to root as commands, in the order
+// of their names. Aliases can't replace an existing command or alias, so
+// those added first take precedence.
+func addAliasCmds(root *cobra.Command, aliases map[string]string, source string) {
+	logger := log.WithFields(log.Fields{
+		"prefix": "cmd.addAliasCmds",
+	})
+
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if commandExists(root, name) {
+			logger.Debugf("Skipping the alias '%s' from %s: a command with this name already exists", name, source)
+			continue
+		}
+
+		ac, err := newAliasCmd(name, aliases[name], source)
+		if err != nil {
+			logger.Debugf("Skipping an alias from %s: %s", source, err)
+			continue
+		}
+
+		root.AddCommand(ac.cmd)
+	}
+}
+
+func commandExists(root *cobra.Command, name string) bool {
+	for _, cmd := range root.Commands() {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitAliasArgs splits the command line of an alias into arguments, the way
+// a shell does: on whitespace, except within single or double quotes, and
+// where it's escaped with a backslash
+func splitAliasArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+
+	inArg := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitAliasArgs(t *testing.T) {
+	args, err := splitAliasArgs(`trigger payment_intent.succeeded --override 'payment_intent:description=Two words' --add "customer:name=Jenny \"J\" Rosen" a\ b`)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"trigger",
+		"payment_intent.succeeded",
+		"--override",
+		"payment_intent:description=Two words",
+		"--add",
+		`customer:name=Jenny "J" Rosen`,
+		"a b",
+	}, args)
+
+	args, err = splitAliasArgs(`  get ""  `)
+	require.NoError(t, err)
+	require.Equal(t, []string{"get", ""}, args)
+
+	_, err = splitAliasArgs(`trigger "payment_intent.succeeded`)
+	require.EqualError(t, err, "unterminated quote")
+}
+
+func TestAliasCmds(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	var ran []string
+	var override []string
+
+	root := &cobra.Command{Use: "stripe", SilenceErrors: true, SilenceUsage: true}
+	trigger := &cobra.Command{
+		Use: "trigger",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ran = args
+			return nil
+		},
+	}
+	trigger.Flags().StringArrayVar(&override, "override", nil, "")
+	root.AddCommand(trigger)
+
+	addAliasCmds(root, map[string]string{
+		"pi-succeed": "trigger payment_intent.succeeded --override 'payment_intent:amount=100'",
+		"trigger":    "listen",
+		"bad":        `trigger "`,
+		"loop":       "pi-succeed",
+	}, "config file")
+	addAliasCmds(root, map[string]string{
+		"pi-succeed": "trigger payment_intent.created",
+		"pi-fail":    "trigger payment_intent.payment_failed",
+	}, "plugin test")
+
+	names := []string{}
+	for _, cmd := range root.Commands() {
+		if isAliasCmd(cmd) {
+			names = append(names, cmd.Name())
+		}
+	}
+	require.ElementsMatch(t, []string{"pi-succeed", "pi-fail", "loop"}, names)
+
+	root.SetArgs([]string{"pi-succeed", "--override", "payment_intent:currency=eur"})
+	require.NoError(t, root.Execute())
+	require.Equal(t, []string{"payment_intent.succeeded"}, ran)
+	require.Equal(t, []string{"payment_intent:amount=100", "payment_intent:currency=eur"}, override)
+	require.Equal(t, []string{"trigger", "payment_intent.succeeded", "--override", "payment_intent:amount=100", "--override", "payment_intent:currency=eur"}, os.Args[1:])
+
+	root.SetArgs([]string{"loop"})
+	require.EqualError(t, root.Execute(), "the alias 'loop' stands for another alias, 'pi-succeed', which isn't supported")
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/requests"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+type cacheCmd struct {
+	cmd *cobra.Command
+}
+
+func newCacheCmd() *cacheCmd {
+	cc := &cacheCmd{}
+
+	cc.cmd = &cobra.Command{
+		Use:   "cache",
+		Args:  validators.NoArgs,
+		Short: "Manage the local cache of API responses",
+		Long: `Manage the local cache of API responses. Responses are only cached when a
+GET request is made with the --cache flag, e.g. stripe get /v1/prices --cache 5m`,
+	}
+
+	cc.cmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Args:  validators.NoArgs,
+		Short: "Remove all cached API responses",
+		RunE:  cc.runCacheClearCmd,
+	})
+
+	return cc
+}
+
+func (cc *cacheCmd) runCacheClearCmd(cmd *cobra.Command, args []string) error {
+	err := requests.NewResponseCache("").Clear()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Cleared the response cache.")
+
+	return nil
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tidwall/gjson"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/git"
+	"github.com/stripe/stripe-cli/pkg/requests"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+// cleanupResource is a kind of object that cleanup can find by metadata and
+// remove
+type cleanupResource struct {
+	path string
+	// archive deactivates objects that can't be deleted instead
+	archive bool
+	// archiveOnFailure deactivates objects that failed to be deleted, such as
+	// products that still have prices
+	archiveOnFailure bool
+}
+
+// cleanupResources are removed in order, so that objects are removed before
+// the ones they depend on
+var cleanupResources = []cleanupResource{
+	{path: "/v1/customers"},
+	{path: "/v1/prices", archive: true},
+	{path: "/v1/products", archiveOnFailure: true},
+}
+
+type cleanupCmd struct {
+	cmd *cobra.Command
+
+	branch      string
+	force       bool
+	dryRun      bool
+	autoConfirm bool
+	apiBaseURL  string
+
+	backoffs *requests.BackoffTimeline
+}
+
+func newCleanupCmd() *cleanupCmd {
+	cc := &cleanupCmd{
+		backoffs: requests.NewBackoffTimeline(os.Stderr, false),
+	}
+
+	cc.cmd = &cobra.Command{
+		Use:   "cleanup",
+		Args:  validators.NoArgs,
+		Short: "Delete the test objects created while working on a git branch",
+		Long: `Delete the customers, prices and products created by trigger and fixtures
+while working on a git branch, once it was merged. These objects are found by
+their git_branch metadata, which they are tagged with when created with
+--git-metadata or the git_metadata setting of the profile, and are deleted from
+test mode only. Prices can't be deleted, so they are archived instead.`,
+		Example: `stripe cleanup --branch feature/foo
+  stripe cleanup --branch feature/foo --dry-run`,
+		RunE: cc.runCleanupCmd,
+	}
+
+	cc.cmd.Flags().StringVar(&cc.branch, "branch", "", "The git branch to delete the objects of (required)")
+	cc.cmd.Flags().BoolVar(&cc.force, "force", false, "Delete the objects even if the branch wasn't merged")
+	cc.cmd.Flags().BoolVar(&cc.dryRun, "dry-run", false, "List the objects that would be deleted without deleting them")
+	cc.cmd.Flags().BoolVarP(&cc.autoConfirm, "confirm", "c", false, "Skip the warning prompt and automatically confirm the deletion")
+
+	// Hidden configuration flags, useful for dev/debugging
+	cc.cmd.Flags().StringVar(&cc.apiBaseURL, "api-base", stripe.DefaultAPIBaseURL, "Sets the API base URL")
+	cc.cmd.Flags().MarkHidden("api-base") // #nosec G104
+
+	return cc
+}
+
+func (cc *cleanupCmd) runCleanupCmd(cmd *cobra.Command, args []string) error {
+	if cc.branch == "" {
+		return clierrors.New(clierrors.RequiredFlagMissing, "the --branch flag is required")
+	}
+
+	if !cc.force {
+		merged, exists, err := git.IsBranchMerged(cc.branch)
+		switch {
+		case err != nil:
+			return clierrors.Errorf(clierrors.BranchNotMerged, "could not check whether %s was merged: %w. Pass --force to clean it up anyway", cc.branch, err)
+		case exists && !merged:
+			return clierrors.Errorf(clierrors.BranchNotMerged, "%s hasn't been merged yet. Pass --force to clean it up anyway", cc.branch)
+		}
+	}
+
+	apiKey, err := Config.Profile.GetAPIKey(false)
+	if err != nil {
+		return err
+	}
+
+	if err := requireTestModeKey(apiKey); err != nil {
+		return err
+	}
+
+	found := make(map[string][]string)
+	total := 0
+
+	for _, resource := range cleanupResources {
+		ids, err := cc.search(cmd.Context(), apiKey, resource)
+		if err != nil {
+			return err
+		}
+
+		found[resource.path] = ids
+		total += len(ids)
+	}
+
+	if total == 0 {
+		fmt.Printf("No objects were created on %s.\n", cc.branch)
+		return nil
+	}
+
+	fmt.Printf("Found %d object(s) created on %s:\n", total, cc.branch)
+	for _, resource := range cleanupResources {
+		for _, id := range found[resource.path] {
+			fmt.Printf("  %s\n", id)
+		}
+	}
+
+	if cc.dryRun {
+		return nil
+	}
+
+	if !cc.autoConfirm {
+		confirmed, err := confirmCleanup(bufio.NewReader(os.Stdin))
+		if err != nil {
+			return err
+		} else if !confirmed {
+			fmt.Println("Exiting without deleting anything. User did not confirm the command.")
+			return nil
+		}
+	}
+
+	failed := 0
+
+	for _, resource := range cleanupResources {
+		for _, id := range found[resource.path] {
+			action, err := cc.remove(cmd.Context(), apiKey, resource, id)
+			if err != nil {
+				failed++
+				fmt.Printf("Failed to delete %s: %v\n", id, err)
+				continue
+			}
+
+			fmt.Printf("%s %s\n", action, id)
+		}
+	}
+
+	if failed > 0 {
+		return clierrors.Errorf(clierrors.CleanupFailed, "failed to delete %d of %d object(s)", failed, total)
+	}
+
+	return nil
+}
+
+// requireTestModeKey refuses the keys that aren't test mode secret or
+// restricted keys, such as a live key passed with --api-key or STRIPE_API_KEY,
+// so that the objects of a branch are never looked for in live mode
+func requireTestModeKey(apiKey string) error {
+	if strings.HasPrefix(apiKey, "sk_test_") || strings.HasPrefix(apiKey, "rk_test_") {
+		return nil
+	}
+
+	return clierrors.New(clierrors.LiveKeyNotAllowed, "cleanup only deletes test mode objects, but the API key isn't a test mode key. Pass a key starting with sk_test_ or rk_test_ with --api-key")
+}
+
+// search returns the IDs of the objects of the resource tagged with the branch
+func (cc *cleanupCmd) search(ctx context.Context, apiKey string, resource cleanupResource) ([]string, error) {
+	query := fmt.Sprintf("metadata['%s']:'%s'", git.BranchMetadataKey, strings.ReplaceAll(cc.branch, "'", "\\'"))
+
+	var ids []string
+	page := ""
+
+	for {
+		params := &requests.RequestParameters{}
+		params.AppendData([]string{"query=" + query, "limit=100"})
+		if page != "" {
+			params.AppendData([]string{"page=" + page})
+		}
+
+		req := requests.Base{
+			Method:           http.MethodGet,
+			SuppressOutput:   true,
+			APIBaseURL:       cc.apiBaseURL,
+			RetryRateLimited: true,
+			Backoffs:         cc.backoffs,
+		}
+
+		body, err := req.MakeRequest(ctx, apiKey, resource.path+"/search", params, true)
+		if err != nil {
+			return nil, err
+		}
+
+		result := gjson.ParseBytes(body)
+		for _, id := range result.Get("data.#.id").Array() {
+			ids = append(ids, id.String())
+		}
+
+		page = result.Get("next_page").String()
+		if !result.Get("has_more").Bool() || page == "" {
+			return ids, nil
+		}
+	}
+}
+
+// remove deletes or archives an object, and returns which it did
+func (cc *cleanupCmd) remove(ctx context.Context, apiKey string, resource cleanupResource, id string) (string, error) {
+	if !resource.archive {
+		req := requests.Base{
+			Method:           http.MethodDelete,
+			SuppressOutput:   true,
+			APIBaseURL:       cc.apiBaseURL,
+			RetryRateLimited: true,
+			Worker:           id,
+			Backoffs:         cc.backoffs,
+		}
+
+		_, err := req.MakeRequest(ctx, apiKey, resource.path+"/"+id, &requests.RequestParameters{}, true)
+		if err == nil || !resource.archiveOnFailure {
+			return "Deleted", err
+		}
+	}
+
+	params := &requests.RequestParameters{}
+	params.AppendData([]string{"active=false"})
+
+	req := requests.Base{
+		Method:           http.MethodPost,
+		SuppressOutput:   true,
+		APIBaseURL:       cc.apiBaseURL,
+		RetryRateLimited: true,
+		Worker:           id,
+		Backoffs:         cc.backoffs,
+	}
+
+	_, err := req.MakeRequest(ctx, apiKey, resource.path+"/"+id, params, true)
+
+	return "Archived", err
+}
+
+func confirmCleanup(reader *bufio.Reader) (bool, error) {
+	fmt.Print("Are you sure you want to delete these objects?\nEnter 'yes' to confirm: ")
+
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+
+	return strings.ToLower(strings.TrimSpace(input)) == "yes", nil
+}
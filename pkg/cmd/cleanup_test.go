@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanupSearch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/customers/search", r.URL.Path)
+		require.Equal(t, "metadata['git_branch']:'feature/it\\'s'", r.URL.Query().Get("query"))
+
+		if r.URL.Query().Get("page") == "" {
+			w.Write([]byte(`{"data": [{"id": "cus_1"}, {"id": "cus_2"}], "has_more": true, "next_page": "page_2"}`))
+			return
+		}
+
+		require.Equal(t, "page_2", r.URL.Query().Get("page"))
+		w.Write([]byte(`{"data": [{"id": "cus_3"}], "has_more": false, "next_page": null}`))
+	}))
+	defer ts.Close()
+
+	cc := &cleanupCmd{branch: "feature/it's", apiBaseURL: ts.URL}
+
+	ids, err := cc.search(context.Background(), "sk_test_1234", cleanupResource{path: "/v1/customers"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"cus_1", "cus_2", "cus_3"}, ids)
+}
+
+func TestCleanupRemove(t *testing.T) {
+	var requests []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		if r.Method == http.MethodDelete && r.URL.Path == "/v1/products/prod_1" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"type": "invalid_request_error"}}`))
+			return
+		}
+
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	cc := &cleanupCmd{apiBaseURL: ts.URL}
+
+	action, err := cc.remove(context.Background(), "sk_test_1234", cleanupResource{path: "/v1/customers"}, "cus_1")
+	require.NoError(t, err)
+	require.Equal(t, "Deleted", action)
+
+	action, err = cc.remove(context.Background(), "sk_test_1234", cleanupResource{path: "/v1/prices", archive: true}, "price_1")
+	require.NoError(t, err)
+	require.Equal(t, "Archived", action)
+
+	action, err = cc.remove(context.Background(), "sk_test_1234", cleanupResource{path: "/v1/products", archiveOnFailure: true}, "prod_1")
+	require.NoError(t, err)
+	require.Equal(t, "Archived", action)
+
+	require.Equal(t, []string{
+		"DELETE /v1/customers/cus_1",
+		"POST /v1/prices/price_1",
+		"DELETE /v1/products/prod_1",
+		"POST /v1/products/prod_1",
+	}, requests)
+}
+
+func TestCleanupRequiresTestModeKey(t *testing.T) {
+	require.NoError(t, requireTestModeKey("sk_test_1234"))
+	require.NoError(t, requireTestModeKey("rk_test_1234"))
+
+	for _, key := range []string{"sk_live_1234", "rk_live_1234", "pk_test_1234", "1234"} {
+		require.EqualError(t, requireTestModeKey(key), "cleanup only deletes test mode objects, but the API key isn't a test mode key. Pass a key starting with sk_test_ or rk_test_ with --api-key")
+	}
+}
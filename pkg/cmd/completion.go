@@ -9,6 +9,7 @@ import (
 
 	"runtime"
 
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/validators"
 )
 
@@ -98,7 +99,7 @@ func selectShell(shell string, writeToStdout bool) error {
 	case selected == "bash":
 		return genBash(writeToStdout)
 	default:
-		return fmt.Errorf("Could not automatically detect your shell. Please run the command with the `--shell` flag for either bash or zsh")
+		return clierrors.New(clierrors.RequiredFlagMissing, "Could not automatically detect your shell. Please run the command with the `--shell` flag for either bash or zsh")
 	}
 }
 
@@ -3,6 +3,7 @@ package cmd
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
 )
 
@@ -24,10 +25,28 @@ func newConfigCmd() *configCmd {
 		Use:   "config",
 		Short: "Manually change the config values for the CLI",
 		Long: `config lets you set and unset specific configuration values for your profile if
-you need more granular control over the configuration.`,
+you need more granular control over the configuration.
+
+Fields under default_metadata are added as metadata to the objects created by
+trigger, fixtures and post. Their values can reference environment variables
+such as $USER, and ${git_branch} for the git branch of the current directory.
+
+Command aliases are set for all profiles in the aliases table of the config
+file, with stripe config --edit. An alias runs the command line it stands for,
+followed by the arguments it's given:
+
+  [aliases]
+    pi-succeed = "trigger payment_intent.succeeded --add 'payment_intent:description=Alias'"
+
+Plugins can add aliases too. Aliases can't replace commands, and the ones of
+the config file take precedence over the ones of plugins.`,
 		Example: `stripe config --list
   stripe config --set color off
-  stripe config --unset color`,
+  stripe config --set default_metadata.developer '$USER'
+  stripe config --unset color
+  stripe config doctor
+  stripe config export --no-secrets --file team-config.toml
+  stripe config import team-config.toml`,
 		RunE: cc.runConfigCmd,
 	}
 
@@ -38,18 +57,36 @@ you need more granular control over the configuration.`,
 
 	cc.cmd.Flags().SetInterspersed(false) // allow args to happen after flags to enable 2 arguments to --set
 
+	cc.cmd.AddCommand(newConfigDoctorCmd(cc.config).cmd)
+	cc.cmd.AddCommand(newConfigExportCmd(cc.config).cmd)
+	cc.cmd.AddCommand(newConfigImportCmd(cc.config).cmd)
+
 	return cc
 }
 
 func (cc *configCmd) runConfigCmd(cmd *cobra.Command, args []string) error {
 	switch ok := true; ok {
 	case cc.set && len(args) == 2:
+		if isPasskeyField(args[0]) {
+			return clierrors.Errorf(clierrors.InvalidConfigValue, "%s can't be set directly, use `stripe passkey register`", args[0])
+		}
 		return cc.config.Profile.WriteConfigField(args[0], args[1])
 	case cc.unset != "":
+		if isPasskeyField(cc.unset) {
+			return clierrors.Errorf(clierrors.InvalidConfigValue, "%s can't be unset directly, use `stripe passkey remove`", cc.unset)
+		}
 		return cc.config.Profile.DeleteConfigField(cc.unset)
 	case cc.list:
+		if err := confirmWithPasskey(cmd.Context(), config.PasskeyShowKeys, "Confirm showing the config of the Stripe CLI, which includes API keys."); err != nil {
+			return err
+		}
 		return cc.config.PrintConfig()
 	case cc.edit:
+		// the passkey settings can be changed in the editor, so editing is
+		// confirmed as soon as any operation requires the passkey
+		if err := confirmWithAnyPasskey(cmd.Context(), "Confirm editing the config of the Stripe CLI, which includes API keys and the passkey settings."); err != nil {
+			return err
+		}
 		return cc.config.EditConfig()
 	default:
 		// no flags set or unrecognized flags/args
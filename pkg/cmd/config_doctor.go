@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/guardrails"
+	"github.com/stripe/stripe-cli/pkg/plugins"
+	"github.com/stripe/stripe-cli/pkg/requests"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+// probeTimeout is how long network probes wait for a response
+const probeTimeout = 10 * time.Second
+
+type configDoctorCmd struct {
+	cmd    *cobra.Command
+	config *config.Config
+	fs     afero.Fs
+
+	skipNetwork bool
+	apiBaseURL  string
+}
+
+func newConfigDoctorCmd(cfg *config.Config) *configDoctorCmd {
+	dc := &configDoctorCmd{
+		config: cfg,
+		fs:     afero.NewOsFs(),
+	}
+
+	dc.cmd = &cobra.Command{
+		Use:   "doctor",
+		Args:  validators.NoArgs,
+		Short: "Diagnose problems with your CLI configuration",
+		Long: `Check your config file, API keys, installed plugins and network access to
+Stripe, and suggest how to fix any problem found.`,
+		Example: `stripe config doctor
+  stripe config doctor --skip-network`,
+		RunE: dc.runConfigDoctorCmd,
+	}
+
+	dc.cmd.Flags().BoolVar(&dc.skipNetwork, "skip-network", false, "Skip the checks that need network access")
+
+	// Hidden configuration flags, useful for dev/debugging
+	dc.cmd.Flags().StringVar(&dc.apiBaseURL, "api-base", stripe.DefaultAPIBaseURL, "Sets the API base URL")
+	dc.cmd.Flags().MarkHidden("api-base") // #nosec G104
+
+	return dc
+}
+
+// doctorCheck is a group of related checks, reported under a single name
+type doctorCheck struct {
+	name string
+	run  func() []config.Issue
+}
+
+func (dc *configDoctorCmd) runConfigDoctorCmd(cmd *cobra.Command, args []string) error {
+	checks := []doctorCheck{
+		{"Config file", func() []config.Issue { return config.ValidateConfigFile(dc.config.ProfilesFile, time.Now()) }},
+		{"Plugin manifest", func() []config.Issue { return plugins.ValidateManifest(dc.config, dc.fs) }},
+		{"Installed plugins", func() []config.Issue { return plugins.VerifyInstalledPlugins(dc.config, dc.fs) }},
+		{"Live mode policy", dc.checkPolicy},
+	}
+
+	if !dc.skipNetwork {
+		checks = append(checks, doctorCheck{"Network", func() []config.Issue { return dc.probeNetwork(cmd.Context()) }})
+	}
+
+	color := ansi.Color(os.Stdout)
+	errorCount := 0
+
+	for _, check := range checks {
+		issues := check.run()
+		if len(issues) == 0 {
+			fmt.Printf("%s %s\n", color.Green("✔"), check.name)
+			continue
+		}
+
+		fmt.Printf("%s %s\n", color.Red("✘"), check.name)
+		for _, issue := range issues {
+			severity := color.Yellow(issue.Severity.String())
+			if issue.Severity == config.SeverityError {
+				severity = color.Red(issue.Severity.String())
+				errorCount++
+			}
+
+			fmt.Printf("  %s %s: %s\n", severity, issue.Subject, issue.Message)
+			if issue.Fix != "" {
+				fmt.Printf("    %s %s\n", color.Faint("fix:"), issue.Fix)
+			}
+		}
+	}
+
+	if errorCount > 0 {
+		return clierrors.Errorf(clierrors.ConfigProblems, "found %d problem(s) with your configuration", errorCount)
+	}
+
+	return nil
+}
+
+// checkPolicy checks that the live mode policy file, if there's one, is valid
+func (dc *configDoctorCmd) checkPolicy() []config.Issue {
+	policyPath := filepath.Join(dc.config.GetConfigFolder(os.Getenv("XDG_CONFIG_HOME")), guardrails.PolicyFileName)
+
+	if _, err := guardrails.LoadPolicy(dc.fs, policyPath); err != nil {
+		return []config.Issue{{
+			Severity: config.SeverityError,
+			Subject:  policyPath,
+			Message:  err.Error(),
+			Fix:      "fix the policy file, live mode requests that change data fail until then",
+		}}
+	}
+
+	return nil
+}
+
+// probeNetwork checks that the API accepts the profile's test mode key, and
+// that plugins can be downloaded
+func (dc *configDoctorCmd) probeNetwork(ctx context.Context) []config.Issue {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	apiKey, err := dc.config.Profile.GetAPIKey(false)
+	if err != nil {
+		// without a key, we can only check that the API can be reached
+		if err := probeReachable(ctx, dc.apiBaseURL); err != nil {
+			return []config.Issue{unreachable("Stripe API", dc.apiBaseURL, err)}
+		}
+		return nil
+	}
+
+	account := requests.Base{
+		Profile:        &dc.config.Profile,
+		Method:         http.MethodGet,
+		SuppressOutput: true,
+		APIBaseURL:     dc.apiBaseURL,
+	}
+
+	_, err = account.MakeRequest(ctx, apiKey, "/v1/account", &requests.RequestParameters{}, true)
+
+	var requestErr requests.RequestError
+	switch {
+	case errors.As(err, &requestErr) && requestErr.StatusCode == http.StatusUnauthorized:
+		return []config.Issue{{
+			Severity: config.SeverityError,
+			Subject:  "Stripe API",
+			Message:  "the test mode key was rejected, it may have been rolled or expired",
+			Fix:      "run `stripe login`",
+		}}
+	case errors.As(err, &requestErr) && requestErr.StatusCode == http.StatusForbidden:
+		return []config.Issue{{
+			Severity: config.SeverityWarning,
+			Subject:  "Stripe API",
+			Message:  "the test mode key isn't allowed to read the account, it is likely a restricted key",
+			Fix:      "grant the missing permissions to the key in the Dashboard",
+		}}
+	case err != nil && !errors.As(err, &requestErr):
+		return []config.Issue{unreachable("Stripe API", dc.apiBaseURL, err)}
+	}
+
+	pluginData, err := requests.GetPluginData(ctx, dc.apiBaseURL, stripe.APIVersion, apiKey, &dc.config.Profile)
+	if err != nil || pluginData.PluginBaseURL == "" {
+		return []config.Issue{{
+			Severity: config.SeverityWarning,
+			Subject:  "plugin CDN",
+			Message:  "could not look up where plugins are downloaded from",
+		}}
+	}
+
+	manifestURL := pluginData.PluginBaseURL + "/plugins.toml"
+	if err := probeReachable(ctx, manifestURL); err != nil {
+		return []config.Issue{unreachable("plugin CDN", manifestURL, err)}
+	}
+
+	return nil
+}
+
+// probeReachable checks that a URL answers at all, whatever the status code
+func probeReachable(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+func unreachable(subject, url string, err error) config.Issue {
+	return config.Issue{
+		Severity: config.SeverityError,
+		Subject:  subject,
+		Message:  fmt.Sprintf("could not reach %s: %s", url, err),
+		Fix:      "check your internet connection, and that HTTPS_PROXY is set if you're behind a proxy",
+	}
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/config"
+)
+
+func TestConfigDoctorSettings(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	for _, tt := range []struct {
+		settings string
+		valid    bool
+	}{
+		{"strict_amounts = true", true},
+		// as written by `stripe config --set strict_amounts true`
+		{`strict_amounts = "true"`, true},
+		{`strict_amounts = "sometimes"`, false},
+	} {
+		profilesFile := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(profilesFile, []byte("[default]\n  "+tt.settings+"\n"), 0600))
+
+		dc := newConfigDoctorCmd(&config.Config{ProfilesFile: profilesFile})
+		dc.fs = afero.NewMemMapFs()
+		dc.skipNetwork = true
+
+		err := dc.runConfigDoctorCmd(dc.cmd, []string{})
+		if tt.valid {
+			require.NoError(t, err, tt.settings)
+		} else {
+			require.EqualError(t, err, "found 1 problem(s) with your configuration", tt.settings)
+		}
+	}
+}
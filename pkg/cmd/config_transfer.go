@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/redact"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+type configExportCmd struct {
+	cmd    *cobra.Command
+	config *config.Config
+
+	file      string
+	noSecrets bool
+	force     bool
+}
+
+func newConfigExportCmd(cfg *config.Config) *configExportCmd {
+	ec := &configExportCmd{config: cfg}
+
+	ec.cmd = &cobra.Command{
+		Use:   "export",
+		Args:  validators.NoArgs,
+		Short: "Export the config to move it to another machine or share it",
+		Long: `Export the profiles and settings of the config file, to import them with
+stripe config import on another machine. With --no-secrets, the API keys are
+left out so that the file can be shared with a team.
+
+The list of installed plugins and the passkeys are bound to this machine and
+are never exported.`,
+		Example: `stripe config export --no-secrets --file team-config.toml
+  stripe config export > config-backup.toml`,
+		RunE: ec.runConfigExportCmd,
+	}
+
+	ec.cmd.Flags().StringVarP(&ec.file, "file", "f", "", "Write the config to this file instead of stdout")
+	ec.cmd.Flags().BoolVar(&ec.noSecrets, "no-secrets", false, "Leave the API keys out")
+	ec.cmd.Flags().BoolVar(&ec.force, "force", false, "Overwrite the file if it exists")
+
+	return ec
+}
+
+func (ec *configExportCmd) runConfigExportCmd(cmd *cobra.Command, args []string) error {
+	if !ec.noSecrets {
+		if err := confirmWithPasskey(cmd.Context(), config.PasskeyShowKeys, "Confirm exporting the config of the Stripe CLI, which includes API keys."); err != nil {
+			return err
+		}
+	}
+
+	content, err := config.ReadConfigFile(ec.config.ProfilesFile)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(config.ExportConfig(content, ec.noSecrets)); err != nil {
+		return err
+	}
+
+	if ec.file == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+
+	if _, err := os.Stat(ec.file); err == nil && !ec.force {
+		return clierrors.Errorf(clierrors.FileExists, "%s already exists, pass --force to overwrite it", ec.file)
+	}
+
+	// the file can only be read by others when there's nothing secret in it
+	perm := os.FileMode(0600)
+	if ec.noSecrets {
+		perm = 0644
+	}
+
+	if err := os.WriteFile(ec.file, buf.Bytes(), perm); err != nil {
+		return err
+	}
+
+	color := ansi.Color(os.Stdout)
+	if ec.noSecrets {
+		fmt.Printf("%s Exported the config without API keys to %s\n", color.Green("✔"), ec.file)
+	} else {
+		fmt.Printf("%s Exported the config to %s. It contains API keys, don't share it.\n", color.Green("✔"), ec.file)
+	}
+
+	return nil
+}
+
+type configImportCmd struct {
+	cmd    *cobra.Command
+	config *config.Config
+
+	overwrite bool
+	keep      bool
+}
+
+func newConfigImportCmd(cfg *config.Config) *configImportCmd {
+	ic := &configImportCmd{config: cfg}
+
+	ic.cmd = &cobra.Command{
+		Use:   "import <file>",
+		Args:  validators.ExactArgs(1),
+		Short: "Import a config exported with stripe config export",
+		Long: `Merge the profiles and settings of an exported config into the config file.
+The fields that aren't set yet are added. For each field set to a different
+value, you're asked whether to replace it, unless --overwrite or --keep is
+passed.`,
+		Example: `stripe config import team-config.toml
+  stripe config import --keep team-config.toml`,
+		RunE: ic.runConfigImportCmd,
+	}
+
+	ic.cmd.Flags().BoolVar(&ic.overwrite, "overwrite", false, "Replace the fields set to a different value with the imported ones")
+	ic.cmd.Flags().BoolVar(&ic.keep, "keep", false, "Keep the current value of the fields set to a different value")
+
+	return ic
+}
+
+func (ic *configImportCmd) runConfigImportCmd(cmd *cobra.Command, args []string) error {
+	if ic.overwrite && ic.keep {
+		return clierrors.New(clierrors.ConflictingFlags, "--overwrite and --keep can't be passed together")
+	}
+
+	if _, err := os.Stat(args[0]); err != nil {
+		return err
+	}
+
+	incoming, err := config.ReadConfigFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	current, err := config.ReadConfigFile(ic.config.ProfilesFile)
+	if err != nil {
+		return err
+	}
+
+	plan := config.PlanImport(current, incoming)
+
+	resolve := resolveConflictsWith(ic.overwrite)
+	if !ic.overwrite && !ic.keep {
+		if len(plan.Conflicts) > 0 && !term.IsTerminal(int(os.Stdin.Fd())) {
+			return clierrors.Errorf(clierrors.ConfigImportConflict, "%d fields are set to a different value in the current config: %s. Pass --overwrite or --keep", len(plan.Conflicts), conflictKeys(plan.Conflicts))
+		}
+		resolve = promptConflicts(os.Stdin, os.Stderr)
+	}
+
+	replaced, err := resolve(plan.Conflicts)
+	if err != nil {
+		return err
+	}
+
+	changes := append(plan.Additions, replaced...)
+	if len(changes) > 0 {
+		config.ApplyChanges(current, changes)
+
+		if err := config.WriteConfigFile(ic.config.ProfilesFile, current); err != nil {
+			return err
+		}
+	}
+
+	printImportSummary(os.Stdout, ic.config.ProfilesFile, plan, len(replaced))
+
+	return nil
+}
+
+// conflictResolver returns the conflicting fields to replace with their
+// imported value
+type conflictResolver func(conflicts []config.Change) ([]config.Change, error)
+
+func resolveConflictsWith(overwrite bool) conflictResolver {
+	return func(conflicts []config.Change) ([]config.Change, error) {
+		if overwrite {
+			return conflicts, nil
+		}
+		return nil, nil
+	}
+}
+
+// promptConflicts asks on out whether to replace each conflicting field,
+// reading the answers from in
+func promptConflicts(in io.Reader, out io.Writer) conflictResolver {
+	return func(conflicts []config.Change) ([]config.Change, error) {
+		reader := bufio.NewReader(in)
+		var replaced []config.Change
+
+		for i, conflict := range conflicts {
+			fmt.Fprintf(out, "%s is %s, the imported config sets it to %s.\n", conflict.Key(), formatConfigValue(conflict.Current), formatConfigValue(conflict.Incoming))
+			fmt.Fprint(out, "Replace it? [y]es, [n]o, [a]ll remaining, n[o]ne remaining: ")
+
+			input, err := reader.ReadString('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				return nil, err
+			}
+
+			switch strings.ToLower(strings.TrimSpace(input)) {
+			case "y", "yes":
+				replaced = append(replaced, conflict)
+			case "a", "all":
+				return append(replaced, conflicts[i:]...), nil
+			case "o", "none":
+				return replaced, nil
+			}
+
+			if errors.Is(err, io.EOF) {
+				return replaced, nil
+			}
+		}
+
+		return replaced, nil
+	}
+}
+
+// formatConfigValue returns a value of the config as it's written in TOML,
+// with any secret redacted
+func formatConfigValue(value interface{}) string {
+	if _, ok := value.(map[string]interface{}); ok {
+		return redact.String(fmt.Sprint(value))
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string]interface{}{"v": value}); err != nil {
+		return redact.String(fmt.Sprint(value))
+	}
+
+	formatted := strings.TrimSpace(buf.String())
+	formatted = strings.TrimPrefix(formatted, "v = ")
+
+	return redact.String(formatted)
+}
+
+func conflictKeys(conflicts []config.Change) string {
+	keys := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		keys = append(keys, conflict.Key())
+	}
+
+	return strings.Join(keys, ", ")
+}
+
+func printImportSummary(out io.Writer, path string, plan config.ImportPlan, replaced int) {
+	color := ansi.Color(out)
+
+	fmt.Fprintf(out, "%s Imported into %s: %d fields added, %d replaced, %d kept\n",
+		color.Green("✔"), path, len(plan.Additions), replaced, len(plan.Conflicts)-replaced)
+
+	if len(plan.Skipped) > 0 {
+		fmt.Fprintf(out, "Skipped the fields bound to the machine they were exported from: %s\n", strings.Join(plan.Skipped, ", "))
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/config"
+)
+
+var transferConflicts = []config.Change{
+	{Path: []string{"color"}, Current: "auto", Incoming: "off"},
+	{Path: []string{"default", "device_name"}, Current: "laptop", Incoming: "desktop"},
+	{Path: []string{"default", "test_mode_api_key"}, Current: "sk_test_51HbzAbcdEFGhijklmnop", Incoming: "sk_test_51HbzZyxwVUTsrqponml"},
+}
+
+func conflictedKeys(changes []config.Change) []string {
+	keys := []string{}
+	for _, change := range changes {
+		keys = append(keys, change.Key())
+	}
+
+	return keys
+}
+
+func TestPromptConflicts(t *testing.T) {
+	for _, tt := range []struct {
+		answers  string
+		replaced []string
+	}{
+		{"y\nn\ny\n", []string{"color", "default.test_mode_api_key"}},
+		{"n\na\n", []string{"default.device_name", "default.test_mode_api_key"}},
+		{"yes\nnone\n", []string{"color"}},
+		{"y\n", []string{"color"}},
+		{"", []string{}},
+	} {
+		var out bytes.Buffer
+		replaced, err := promptConflicts(strings.NewReader(tt.answers), &out)(transferConflicts)
+		require.NoError(t, err)
+		require.Equal(t, tt.replaced, conflictedKeys(replaced), tt.answers)
+		require.NotContains(t, out.String(), "51HbzAbcdEFGhijklmnop")
+	}
+}
+
+func TestPromptConflictsOutput(t *testing.T) {
+	var out bytes.Buffer
+	_, err := promptConflicts(strings.NewReader("n\n"), &out)(transferConflicts[:1])
+	require.NoError(t, err)
+	require.Equal(t, `color is "auto", the imported config sets it to "off".
+Replace it? [y]es, [n]o, [a]ll remaining, n[o]ne remaining: `, out.String())
+}
+
+func TestResolveConflictsWith(t *testing.T) {
+	replaced, err := resolveConflictsWith(true)(transferConflicts)
+	require.NoError(t, err)
+	require.Equal(t, transferConflicts, replaced)
+
+	replaced, err = resolveConflictsWith(false)(transferConflicts)
+	require.NoError(t, err)
+	require.Empty(t, replaced)
+}
+
+func TestFormatConfigValue(t *testing.T) {
+	require.Equal(t, `"auto"`, formatConfigValue("auto"))
+	require.Equal(t, "true", formatConfigValue(true))
+	require.Equal(t, `["apps", "projects"]`, formatConfigValue([]interface{}{"apps", "projects"}))
+	require.Equal(t, `"sk_test_[REDACTED]"`, formatConfigValue("sk_test_51HbzAbcdEFGhijklmnop"))
+}
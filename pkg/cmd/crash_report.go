@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/stripe/stripe-cli/pkg/redact"
+	"github.com/stripe/stripe-cli/pkg/version"
+)
+
+// recoverFromPanic turns a panic into a crash report saved in the config
+// folder, so that users get a file they can attach to a bug report rather
+// than a raw stack trace
+func recoverFromPanic(recovered interface{}, stack []byte) {
+	crashDir := filepath.Join(Config.GetConfigFolder(os.Getenv("XDG_CONFIG_HOME")), "crash-reports")
+
+	path, err := writeCrashReport(fs, crashDir, time.Now(), recovered, stack, os.Args)
+	if err != nil {
+		// nowhere to save the report, so fall back to printing it
+		fmt.Fprintf(os.Stderr, "panic: %s\n\n%s", redact.String(fmt.Sprint(recovered)), redact.String(string(stack)))
+		os.Exit(2)
+	}
+
+	fmt.Fprintf(os.Stderr, `The Stripe CLI ran into an unexpected error and had to stop.
+
+A crash report was saved to %s
+Secrets have been redacted from it. Please open an issue at
+https://github.com/stripe/stripe-cli/issues and attach the report so that we can
+fix the problem.
+`, path)
+
+	os.Exit(2)
+}
+
+// writeCrashReport saves a redacted crash report in dir and returns its path
+func writeCrashReport(fs afero.Fs, dir string, now time.Time, recovered interface{}, stack []byte, args []string) (string, error) {
+	if err := fs.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "Time: %s\n", now.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&report, "Version: %s\n", version.Version)
+	fmt.Fprintf(&report, "OS: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&report, "Go: %s\n", runtime.Version())
+	fmt.Fprintf(&report, "Command: %s\n\n", strings.Join(args, " "))
+	fmt.Fprintf(&report, "panic: %v\n\n%s", recovered, stack)
+
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s.log", now.UTC().Format("20060102T150405Z")))
+
+	err := afero.WriteFile(fs, path, []byte(redact.String(report.String())), 0600)
+	if err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCrashReport(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	now := time.Date(2022, 8, 1, 12, 30, 0, 0, time.UTC)
+
+	path, err := writeCrashReport(
+		memFs,
+		"/config/stripe/crash-reports",
+		now,
+		"something went wrong with sk_test_51HbzAbcdEFGhijklmnop",
+		[]byte("goroutine 1 [running]:\nmain.main()\n"),
+		[]string{"stripe", "get", "/v1/charges", "--api-key", "sk_test_51HbzAbcdEFGhijklmnop"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "/config/stripe/crash-reports/crash-20220801T123000Z.log", path)
+
+	report, err := afero.ReadFile(memFs, path)
+	require.NoError(t, err)
+
+	require.Contains(t, string(report), "Time: 2022-08-01T12:30:00Z\n")
+	require.Contains(t, string(report), "Command: stripe get /v1/charges --api-key sk_test_[REDACTED]\n")
+	require.Contains(t, string(report), "panic: something went wrong with sk_test_[REDACTED]\n")
+	require.Contains(t, string(report), "goroutine 1 [running]:")
+	require.NotContains(t, string(report), "sk_test_51HbzAbcdEFGhijklmnop")
+}
@@ -1,11 +1,14 @@
 package cmd
 
 import (
+	"os"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/stripe/stripe-cli/pkg/config"
 	"github.com/stripe/stripe-cli/pkg/rpcservice"
+	"github.com/stripe/stripe-cli/pkg/schedule"
 	"github.com/stripe/stripe-cli/pkg/stripe"
 	"github.com/stripe/stripe-cli/pkg/validators"
 )
@@ -26,7 +29,7 @@ func newDaemonCmd(cfg *config.Config) *daemonCmd {
 		Args:  validators.NoArgs,
 		Short: "Run as a daemon on your localhost",
 		Long: `Start a local gRPC server, enabling you to invoke Stripe CLI commands programmatically from a gRPC
-client.
+client. The commands scheduled with stripe schedule run while the daemon runs.
 
 Currently, stripe daemon only supports a subset of CLI commands. Documentation is not yet available.`,
 		Run:    dc.runDaemonCmd,
@@ -53,5 +56,19 @@ func (dc *daemonCmd) runDaemonCmd(cmd *cobra.Command, args []string) {
 
 	go srv.Run(ctx)
 
+	// the scheduled commands run with this very executable
+	if executable, err := os.Executable(); err == nil {
+		scheduler := &schedule.Scheduler{
+			Store: scheduleStore(),
+			Run:   schedule.ExecRunner(executable, scheduleLogDir()),
+			Log:   log.StandardLogger(),
+		}
+		go scheduler.Start(ctx)
+	} else {
+		log.WithFields(log.Fields{
+			"prefix": "cmd.daemonCmd.runDaemonCmd",
+		}).Warnf("Not running the scheduled commands: %s", err)
+	}
+
 	<-ctx.Done()
 }
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/devcontainer"
+	"github.com/stripe/stripe-cli/pkg/login"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+type devcontainerCmd struct {
+	cmd *cobra.Command
+}
+
+func newDevcontainerCmd() *devcontainerCmd {
+	dc := &devcontainerCmd{}
+
+	dc.cmd = &cobra.Command{
+		Use:   "devcontainer",
+		Args:  validators.NoArgs,
+		Short: "Set up the CLI in dev containers and GitHub Codespaces",
+	}
+
+	dc.cmd.AddCommand(newDevcontainerSetupCmd().cmd)
+
+	return dc
+}
+
+type devcontainerSetupCmd struct {
+	cmd *cobra.Command
+
+	ports            []int
+	volume           string
+	skipLogin        bool
+	force            bool
+	dryRun           bool
+	dashboardBaseURL string
+}
+
+func newDevcontainerSetupCmd() *devcontainerSetupCmd {
+	sc := &devcontainerSetupCmd{}
+
+	sc.cmd = &cobra.Command{
+		Use:   "setup",
+		Args:  validators.NoArgs,
+		Short: "Configure the dev container for the CLI, and log in",
+		Long: `Configure the devcontainer.json of the workspace so that the CLI keeps working
+when the container is rebuilt, then log in if needed:
+
+  - the CLI's config folder is kept in a named volume, so you stay logged in
+  - ports are forwarded to your machine, such as the one of stripe serve and
+    of the Stripe samples
+
+Logging in from a dev container always uses a pairing code and a link to open
+in your browser, as the container can't open it.
+
+Rebuild the container for the changes to devcontainer.json to apply.`,
+		Example: `stripe devcontainer setup
+  stripe devcontainer setup --port 4242 --port 3000
+  stripe devcontainer setup --dry-run`,
+		RunE: sc.runDevcontainerSetupCmd,
+	}
+
+	sc.cmd.Flags().IntSliceVar(&sc.ports, "port", []int{4242}, "Ports to forward to your machine")
+	sc.cmd.Flags().StringVar(&sc.volume, "volume", devcontainer.DefaultVolume, "Name of the volume keeping the CLI's config folder")
+	sc.cmd.Flags().BoolVar(&sc.skipLogin, "skip-login", false, "Don't log in, even if the profile has no API key")
+	sc.cmd.Flags().BoolVar(&sc.force, "force", false, "Configure the workspace even if no dev container was detected")
+	sc.cmd.Flags().BoolVar(&sc.dryRun, "dry-run", false, "Print the updated devcontainer.json instead of writing it")
+
+	// Hidden configuration flags, useful for dev/debugging
+	sc.cmd.Flags().StringVar(&sc.dashboardBaseURL, "dashboard-base", stripe.DefaultDashboardBaseURL, "Sets the dashboard base URL")
+	sc.cmd.Flags().MarkHidden("dashboard-base") // #nosec G104
+
+	return sc
+}
+
+func (sc *devcontainerSetupCmd) runDevcontainerSetupCmd(cmd *cobra.Command, args []string) error {
+	env := devcontainer.Detect(os.Getenv)
+	if env == nil && !sc.force {
+		return clierrors.New(clierrors.DevcontainerNotDetected, "no dev container or Codespace was detected. Pass --force to configure this workspace anyway")
+	}
+
+	dir := ""
+	if env != nil {
+		dir = env.WorkspaceFolder
+		fmt.Printf("Detected %s.\n", env.Name)
+	}
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		dir = wd
+	}
+
+	settings := &devcontainer.Settings{
+		ConfigFolder: Config.GetConfigFolder(os.Getenv("XDG_CONFIG_HOME")),
+		Volume:       sc.volume,
+	}
+	for _, port := range sc.ports {
+		settings.Ports = append(settings.Ports, devcontainer.Port{Number: port, Label: "Stripe CLI"})
+	}
+
+	if err := sc.updateConfig(fs, dir, settings); err != nil {
+		return err
+	}
+
+	if sc.skipLogin || sc.dryRun {
+		return nil
+	}
+
+	if _, err := Config.Profile.GetAPIKey(false); err == nil {
+		fmt.Printf("Already logged in to %s.\n", Config.Profile.GetDisplayName())
+		return nil
+	}
+
+	return login.Login(cmd.Context(), sc.dashboardBaseURL, &Config, os.Stdin)
+}
+
+func (sc *devcontainerSetupCmd) updateConfig(fs afero.Fs, dir string, settings *devcontainer.Settings) error {
+	path, exists := devcontainer.FindConfig(fs, dir)
+
+	var data []byte
+	if exists {
+		var err error
+		data, err = afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	updated, err := settings.Apply(data)
+	if errors.Is(err, devcontainer.ErrNotPlainJSON) {
+		fmt.Printf("%s isn't plain JSON, such as because it has comments, so it wasn't changed. Merge these properties into it:\n\n%s\n", path, settings.Snippet())
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not update %s: %w", path, err)
+	}
+
+	if sc.dryRun {
+		fmt.Print(string(updated))
+		return nil
+	}
+
+	if exists && string(updated) == string(data) {
+		fmt.Printf("%s is already set up.\n", path)
+		return nil
+	}
+
+	if err := fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	if err := afero.WriteFile(fs, path, updated, 0644); err != nil {
+		return err
+	}
+
+	color := ansi.Color(os.Stdout)
+
+	changes := []string{fmt.Sprintf("keeping %s in the %s volume", settings.ConfigFolder, settings.Volume)}
+	if len(settings.Ports) > 0 {
+		ports := make([]string, 0, len(settings.Ports))
+		for _, port := range settings.Ports {
+			ports = append(ports, fmt.Sprint(port.Number))
+		}
+		changes = append(changes, "forwarding ports "+strings.Join(ports, ", "))
+	}
+
+	fmt.Printf("%s Updated %s: %s.\n", color.Green("✔"), path, strings.Join(changes, ", "))
+	fmt.Println("Rebuild the container for the changes to apply. You'll need to log in once more after the first rebuild.")
+
+	return nil
+}
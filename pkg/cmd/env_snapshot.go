@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/redact"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+	"github.com/stripe/stripe-cli/pkg/version"
+)
+
+// proxyEnvVars are the environment variables configuring the proxy of the
+// requests, as read by http.ProxyFromEnvironment
+var proxyEnvVars = []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy", "NO_PROXY", "no_proxy"}
+
+// envSnapshot is what a failed command resolved its settings to, printed
+// along with its error with --verbose so that issues can be triaged without
+// asking what the config looks like
+type envSnapshot struct {
+	Command    string
+	Version    string
+	Platform   string
+	Profile    string
+	ConfigFile string
+	APIKey     string
+	APIBase    string
+	APIVersion string
+	Proxy      []string
+	UnixSocket string
+}
+
+// verboseErrors returns whether cmd was run with --verbose
+func verboseErrors(cmd *cobra.Command) bool {
+	if cmd == nil {
+		return false
+	}
+
+	flag := cmd.Flags().Lookup("verbose")
+
+	return flag != nil && flag.Value.String() == "true"
+}
+
+// newEnvSnapshot returns the settings cmd ran with
+func newEnvSnapshot(cmd *cobra.Command, cfg *config.Config) envSnapshot {
+	snapshot := envSnapshot{
+		Command:    cmd.CommandPath(),
+		Version:    version.Version,
+		Platform:   fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+		Profile:    cfg.Profile.ProfileName,
+		ConfigFile: cfg.ProfilesFile,
+		APIKey:     describeAPIKey(&cfg.Profile),
+		APIBase:    stripe.DefaultAPIBaseURL,
+		APIVersion: stripe.APIVersion,
+		UnixSocket: os.Getenv("STRIPE_CLI_UNIX_SOCKET"),
+	}
+
+	if profileBindingPath != "" {
+		snapshot.Profile += fmt.Sprintf(" (bound by %s)", profileBindingPath)
+	}
+
+	if flag := cmd.Flags().Lookup("api-base"); flag != nil && flag.Value.String() != "" {
+		snapshot.APIBase = flag.Value.String()
+	}
+
+	if flag := cmd.Flags().Lookup("stripe-version"); flag != nil && flag.Value.String() != "" {
+		snapshot.APIVersion = flag.Value.String()
+	}
+
+	snapshot.Proxy = describeProxy(snapshot.APIBase)
+
+	return snapshot
+}
+
+// describeAPIKey returns the type of the key the profile resolves to, as its
+// prefix, and where it comes from
+func describeAPIKey(profile *config.Profile) string {
+	source := "the config file"
+
+	switch {
+	case os.Getenv("STRIPE_API_KEY") != "":
+		source = "STRIPE_API_KEY"
+	case profile.APIKey != "":
+		source = "--api-key"
+	}
+
+	key, err := profile.GetAPIKey(false)
+	if err != nil {
+		return fmt.Sprintf("none (%s)", err)
+	}
+
+	return fmt.Sprintf("%s (from %s)", apiKeyPrefix(key), source)
+}
+
+// apiKeyPrefix returns the type and mode of an API key, e.g. sk_test, without
+// any of its secret part
+func apiKeyPrefix(key string) string {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) < 3 || (parts[1] != "test" && parts[1] != "live") {
+		return "unrecognized key"
+	}
+
+	return parts[0] + "_" + parts[1]
+}
+
+// describeProxy returns the proxy settings of the environment, with their
+// credentials redacted, and the proxy the requests to apiBase go through
+func describeProxy(apiBase string) []string {
+	var settings []string
+
+	for _, name := range proxyEnvVars {
+		if value := os.Getenv(name); value != "" {
+			settings = append(settings, fmt.Sprintf("%s=%s", name, redactProxyURL(value)))
+		}
+	}
+
+	if len(settings) == 0 {
+		return []string{"none"}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, apiBase, nil)
+	if err != nil {
+		return settings
+	}
+
+	proxyURL, err := http.ProxyFromEnvironment(req)
+
+	switch {
+	case err != nil:
+		settings = append(settings, fmt.Sprintf("invalid proxy for %s: %s", apiBase, err))
+	case proxyURL == nil:
+		settings = append(settings, fmt.Sprintf("%s is not proxied", apiBase))
+	default:
+		settings = append(settings, fmt.Sprintf("%s goes through %s", apiBase, proxyURL.Redacted()))
+	}
+
+	return settings
+}
+
+// redactProxyURL removes the password from a proxy URL
+func redactProxyURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+
+	return u.Redacted()
+}
+
+// Print writes the snapshot to w, with any secret redacted
+func (s envSnapshot) Print(w io.Writer) {
+	var b strings.Builder
+
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Environment:")
+	fmt.Fprintf(tw, "  Command:\t%s\n", s.Command)
+	fmt.Fprintf(tw, "  CLI version:\t%s (%s)\n", s.Version, s.Platform)
+	fmt.Fprintf(tw, "  Profile:\t%s\n", s.Profile)
+	fmt.Fprintf(tw, "  Config file:\t%s\n", s.ConfigFile)
+	fmt.Fprintf(tw, "  API key:\t%s\n", s.APIKey)
+	fmt.Fprintf(tw, "  API base:\t%s\n", s.APIBase)
+	fmt.Fprintf(tw, "  API version:\t%s\n", s.APIVersion)
+	fmt.Fprintf(tw, "  Proxy:\t%s\n", strings.Join(s.Proxy, "\n  \t"))
+	if s.UnixSocket != "" {
+		fmt.Fprintf(tw, "  Unix socket:\t%s\n", s.UnixSocket)
+	}
+	tw.Flush()
+
+	fmt.Fprint(w, redact.String(b.String()))
+}
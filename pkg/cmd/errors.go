@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/requests"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+// cobraErrorCodes classifies the usage errors of cobra, which have no type,
+// by the start of their message
+var cobraErrorCodes = []struct {
+	prefix string
+	code   clierrors.Code
+}{
+	{"unknown command", clierrors.UnknownCommand},
+	{"unknown flag", clierrors.UnknownFlag},
+	{"unknown shorthand flag", clierrors.UnknownFlag},
+	{"invalid argument", clierrors.InvalidFlagValue},
+	{"flag needs an argument", clierrors.InvalidFlagValue},
+	{"bad flag syntax", clierrors.InvalidFlagValue},
+	{"required flag(s)", clierrors.RequiredFlagMissing},
+}
+
+type errorsCmd struct {
+	cmd *cobra.Command
+}
+
+func newErrorsCmd() *errorsCmd {
+	ec := &errorsCmd{}
+
+	ec.cmd = &cobra.Command{
+		Use:   "errors",
+		Args:  validators.NoArgs,
+		Short: "Explain the codes of the errors of the CLI",
+		Long: `Explain the codes of the errors the CLI itself generates, as opposed to the
+errors of the API. The commands run with --output json report their failures
+as a JSON object with the code, so that wrappers and editors can suggest a fix
+without matching on the message:
+
+  {"error": {"type": "cli_error", "code": "CLI-1042", "title": "Profile locked", "message": "..."}}
+
+Errors of the API are reported with the type api_error, and the status, type
+and code of the API instead.`,
+		Example: `stripe errors explain CLI-1042
+  stripe errors list --output json`,
+	}
+
+	ec.cmd.AddCommand(newErrorsExplainCmd().cmd)
+	ec.cmd.AddCommand(newErrorsListCmd().cmd)
+
+	return ec
+}
+
+type errorsExplainCmd struct {
+	cmd *cobra.Command
+
+	output string
+}
+
+func newErrorsExplainCmd() *errorsExplainCmd {
+	eec := &errorsExplainCmd{}
+
+	eec.cmd = &cobra.Command{
+		Use:   "explain <code>",
+		Args:  validators.ExactArgs(1),
+		Short: "Explain an error code and how to fix it",
+		RunE:  eec.runErrorsExplainCmd,
+	}
+
+	eec.cmd.Flags().StringVar(&eec.output, "output", "text", "The format of the explanation: text or json")
+
+	return eec
+}
+
+func (eec *errorsExplainCmd) runErrorsExplainCmd(cmd *cobra.Command, args []string) error {
+	if eec.output != "text" && eec.output != "json" {
+		return clierrors.Errorf(clierrors.InvalidFlagValue, "unsupported output %q, expected text or json", eec.output)
+	}
+
+	entry, ok := clierrors.Lookup(args[0])
+	if !ok {
+		return clierrors.Errorf(clierrors.InvalidArgs, "unknown error code %s. Run `stripe errors list` for the codes", args[0])
+	}
+
+	if eec.output == "json" {
+		return json.NewEncoder(os.Stdout).Encode(entry)
+	}
+
+	printErrorExplanation(os.Stdout, entry)
+
+	return nil
+}
+
+type errorsListCmd struct {
+	cmd *cobra.Command
+
+	output string
+}
+
+func newErrorsListCmd() *errorsListCmd {
+	elc := &errorsListCmd{}
+
+	elc.cmd = &cobra.Command{
+		Use:   "list",
+		Args:  validators.NoArgs,
+		Short: "List the error codes",
+		RunE:  elc.runErrorsListCmd,
+	}
+
+	elc.cmd.Flags().StringVar(&elc.output, "output", "text", "The format of the list: text, or json with the explanations")
+
+	return elc
+}
+
+func (elc *errorsListCmd) runErrorsListCmd(cmd *cobra.Command, args []string) error {
+	switch elc.output {
+	case "json":
+		return json.NewEncoder(os.Stdout).Encode(clierrors.All())
+	case "text":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, entry := range clierrors.All() {
+			fmt.Fprintf(w, "%s\t%s\n", entry.Code, entry.Title)
+		}
+		return w.Flush()
+	default:
+		return clierrors.Errorf(clierrors.InvalidFlagValue, "unsupported output %q, expected text or json", elc.output)
+	}
+}
+
+func printErrorExplanation(out io.Writer, entry clierrors.Entry) {
+	fmt.Fprintf(out, "%s: %s\n\n", entry.Code, entry.Title)
+	fmt.Fprintf(out, "%s\n\n", entry.Explanation)
+	fmt.Fprintf(out, "To fix it: %s\n", entry.Remediation)
+}
+
+// errorCode returns the code of an error of the CLI, or "" for an error of
+// the API. Errors without a code of their own are Unclassified.
+func errorCode(err error) clierrors.Code {
+	if code, ok := clierrors.CodeOf(err); ok {
+		return code
+	}
+
+	var requestErr requests.RequestError
+	if errors.As(err, &requestErr) {
+		return ""
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return clierrors.Timeout
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return clierrors.NetworkError
+	}
+
+	for _, c := range cobraErrorCodes {
+		if strings.HasPrefix(err.Error(), c.prefix) {
+			return c.code
+		}
+	}
+
+	return clierrors.Unclassified
+}
+
+// jsonErrors returns whether the failures of cmd are reported as JSON, when
+// it was run with --output json
+func jsonErrors(cmd *cobra.Command) bool {
+	if cmd == nil {
+		return false
+	}
+
+	flag := cmd.Flags().Lookup("output")
+
+	return flag != nil && flag.Value.String() == "json"
+}
+
+// jsonError is how a failure is reported with --output json
+type jsonError struct {
+	// Type is cli_error or api_error
+	Type    string         `json:"type"`
+	Code    clierrors.Code `json:"code,omitempty"`
+	Title   string         `json:"title,omitempty"`
+	Message string         `json:"message"`
+
+	// Status, APIErrorType and APIErrorCode describe the errors of the API
+	Status       int    `json:"status,omitempty"`
+	APIErrorType string `json:"api_error_type,omitempty"`
+	APIErrorCode string `json:"api_error_code,omitempty"`
+}
+
+// printJSONError prints err as a JSON object on a single line
+func printJSONError(out io.Writer, err error) {
+	report := jsonError{Type: "cli_error", Message: err.Error()}
+
+	var requestErr requests.RequestError
+
+	if code := errorCode(err); code != "" {
+		report.Code = code
+		if entry, ok := clierrors.Lookup(string(code)); ok {
+			report.Title = entry.Title
+		}
+	} else if errors.As(err, &requestErr) {
+		report.Type = "api_error"
+		report.Status = requestErr.StatusCode
+		report.APIErrorType = requestErr.ErrorType
+		report.APIErrorCode = requestErr.ErrorCode
+	}
+
+	line, _ := json.Marshal(struct {
+		Error jsonError `json:"error"`
+	}{report})
+
+	fmt.Fprintln(out, string(line))
+}
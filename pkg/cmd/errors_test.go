@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/requests"
+	"github.com/stripe/stripe-cli/pkg/storage"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+func TestErrorCode(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		code clierrors.Code
+	}{
+		{validators.ErrAPIKeyNotConfigured, clierrors.APIKeyNotConfigured},
+		{fmt.Errorf("loading: %w", storage.ErrLocked), clierrors.StateLocked},
+		{profileLockedError("ci", &storage.LockInfo{}), clierrors.ProfileLocked},
+		{requireTestModeKey("sk_live_123"), clierrors.LiveKeyNotAllowed},
+		{openStateBackend("ftp://bucket/prefix"), clierrors.InvalidStateBackend},
+		{errors.New(`unknown command "foo" for "stripe"`), clierrors.UnknownCommand},
+		{errors.New("unknown shorthand flag: 'z' in -z"), clierrors.UnknownFlag},
+		{errors.New(`invalid argument "soon" for "--wait" flag: time: invalid duration "soon"`), clierrors.InvalidFlagValue},
+		{fmt.Errorf("get: %w", context.DeadlineExceeded), clierrors.Timeout},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, clierrors.NetworkError},
+		{errors.New("something else"), clierrors.Unclassified},
+		{requests.RequestError{StatusCode: 402}, ""},
+	} {
+		require.Equal(t, tt.code, errorCode(tt.err), tt.err.Error())
+	}
+}
+
+func TestPrintJSONError(t *testing.T) {
+	var out bytes.Buffer
+	printJSONError(&out, profileLockedError("ci", &storage.LockInfo{}))
+	require.Equal(t, `{"error":{"type":"cli_error","code":"CLI-1042","title":"Profile locked","message":"profile ci is locked"}}`+"\n", out.String())
+
+	out.Reset()
+	printJSONError(&out, requests.RequestError{StatusCode: 402, ErrorType: "card_error", ErrorCode: "card_declined", Body: "{}"})
+	require.Contains(t, out.String(), `{"error":{"type":"api_error","message":`)
+	require.Contains(t, out.String(), `"status":402,"api_error_type":"card_error","api_error_code":"card_declined"}}`)
+}
+
+func TestPrintErrorExplanation(t *testing.T) {
+	entry, ok := clierrors.Lookup("CLI-1042")
+	require.True(t, ok)
+
+	var out bytes.Buffer
+	printErrorExplanation(&out, entry)
+	require.Contains(t, out.String(), "CLI-1042: Profile locked\n\nSomeone locked the profile")
+	require.Contains(t, out.String(), "\n\nTo fix it: Wait for the lock")
+}
+
+func openStateBackend(rawURL string) error {
+	_, err := storage.Open(rawURL, afero.NewMemMapFs())
+	return err
+}
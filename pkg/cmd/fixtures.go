@@ -1,11 +1,20 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
 	"github.com/stripe/stripe-cli/pkg/fixtures"
+	"github.com/stripe/stripe-cli/pkg/plugins"
+	"github.com/stripe/stripe-cli/pkg/requests"
 	"github.com/stripe/stripe-cli/pkg/stripe"
 	"github.com/stripe/stripe-cli/pkg/validators"
 	"github.com/stripe/stripe-cli/pkg/version"
@@ -23,6 +32,8 @@ type FixturesCmd struct {
 	override      []string
 	add           []string
 	remove        []string
+	resume        bool
+	maxParallel   int
 }
 
 func newFixturesCmd(cfg *config.Config) *FixturesCmd {
@@ -34,8 +45,24 @@ func newFixturesCmd(cfg *config.Config) *FixturesCmd {
 		Use:   "fixtures",
 		Args:  validators.ExactArgs(1),
 		Short: "Run fixtures to populate your account with data",
-		Long:  `Run fixtures to populate your account with data`,
-		RunE:  fixturesCmd.runFixturesCmd,
+		Long: `Run fixtures to populate your account with data.
+
+With --max-parallel, the steps which don't depend on each other run at the same
+time. A step depends on the steps it references with ${name:field}, and on the
+steps listed in its "depends_on", for those it needs to run after without
+referencing them.
+
+With "transactional": true in the "_meta" of the fixture file, the objects
+created by the fixture are reversed when one of its steps fails, the last
+created first: deleted, canceled, detached, voided, refunded or archived,
+depending on what they are.
+
+Amounts can be written for people, such as "amount": "$20.00" or "15,30EUR",
+and are converted to the smallest unit of their currency, unless
+--strict-amounts is passed.`,
+		Example: `stripe fixtures seed.json
+  stripe fixtures seed.json --max-parallel 8`,
+		RunE: fixturesCmd.runFixturesCmd,
 	}
 
 	fixturesCmd.Cmd.Flags().StringVar(&fixturesCmd.stripeAccount, "stripe-account", "", "Set a header identifying the connected account")
@@ -44,6 +71,10 @@ func newFixturesCmd(cfg *config.Config) *FixturesCmd {
 	fixturesCmd.Cmd.Flags().StringArrayVar(&fixturesCmd.add, "add", []string{}, "Add parameters in the fixture")
 	fixturesCmd.Cmd.Flags().StringArrayVar(&fixturesCmd.remove, "remove", []string{}, "Remove parameters from the fixture")
 	fixturesCmd.Cmd.Flags().StringVar(&fixturesCmd.apiVersion, "api-version", "", "Specify API version in the fixture")
+	fixturesCmd.Cmd.Flags().BoolVar(&fixturesCmd.resume, "resume-from-last-failure", false, "Skip the steps completed by the last run of the fixture, reusing the objects they created")
+	fixturesCmd.Cmd.Flags().IntVar(&fixturesCmd.maxParallel, "max-parallel", 1, "How many independent steps of the fixture to run at once")
+
+	fixturesCmd.Cmd.AddCommand(newFixturesExportCmd(cfg).Cmd)
 
 	return fixturesCmd
 }
@@ -51,6 +82,10 @@ func newFixturesCmd(cfg *config.Config) *FixturesCmd {
 func (fc *FixturesCmd) runFixturesCmd(cmd *cobra.Command, args []string) error {
 	version.CheckLatestVersion()
 
+	if fc.maxParallel < 1 {
+		return clierrors.Errorf(clierrors.InvalidFlagValue, "--max-parallel must be at least 1, got %d", fc.maxParallel)
+	}
+
 	apiKey, err := fc.Cfg.Profile.GetAPIKey(false)
 	if err != nil {
 		return err
@@ -60,6 +95,10 @@ func (fc *FixturesCmd) runFixturesCmd(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if err := ensureProfileUnlocked(cmd.Context()); err != nil {
+		return err
+	}
+
 	fixture, err := fixtures.NewFixtureFromFile(
 		afero.NewOsFs(),
 		apiKey,
@@ -75,7 +114,25 @@ func (fc *FixturesCmd) runFixturesCmd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	fixture.CheckpointFile = fixtures.CheckpointPath(filepath.Join(fc.Cfg.GetConfigFolder(os.Getenv("XDG_CONFIG_HOME")), "fixtures-checkpoints"), args[0])
+	fixture.Resume = fc.resume
+	fixture.Metadata = fixtureMetadata(&fc.Cfg.Profile)
+	fixture.Backoffs = requests.NewBackoffTimeline(os.Stderr, false)
+	fixture.MaxParallel = fc.maxParallel
+	fixture.StrictAmounts = fc.Cfg.Profile.GetStrictAmounts()
+
 	_, err = fixture.Execute(cmd.Context(), fc.apiVersion)
+	plugins.CleanupAllClients()
+
+	var resumeErr fixtures.ResumeError
+	if errors.As(err, &resumeErr) {
+		fmt.Printf("Fixture failed at: %s. Once fixed, run the fixture again with --resume-from-last-failure to continue from there.\n", resumeErr.Step)
+	}
+
+	var rollbackErr fixtures.RollbackError
+	if errors.As(err, &rollbackErr) {
+		printRollback(os.Stdout, rollbackErr)
+	}
 
 	if err != nil {
 		return err
@@ -88,3 +145,89 @@ func (fc *FixturesCmd) runFixturesCmd(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// FixturesExportCmd generates a fixture file that recreates existing objects
+type FixturesExportCmd struct {
+	Cmd *cobra.Command
+	Cfg *config.Config
+
+	stripeAccount string
+	withRelated   bool
+	livemode      bool
+	output        string
+	apiBaseURL    string
+}
+
+func newFixturesExportCmd(cfg *config.Config) *FixturesExportCmd {
+	exportCmd := &FixturesExportCmd{
+		Cfg: cfg,
+	}
+
+	exportCmd.Cmd = &cobra.Command{
+		Use:   "export <id>",
+		Args:  validators.ExactArgs(1),
+		Short: "Generate a fixture that recreates an existing object",
+		Long: `Generate a fixture that recreates an existing object. IDs, timestamps and
+other read-only fields are left out so that the fixture can be run to create an
+equivalent object, for example in test mode.
+
+Supported objects are customers, payment methods, payment intents, invoices,
+products and prices. Pass --live to export an object from live mode, such as to
+reproduce it in test mode.`,
+		Example: `stripe fixtures export pi_123 --with-related
+  stripe fixtures export prod_123 --output product.json
+  stripe fixtures export pi_123 --with-related --live`,
+		RunE: exportCmd.runFixturesExportCmd,
+	}
+
+	exportCmd.Cmd.Flags().StringVar(&exportCmd.stripeAccount, "stripe-account", "", "Set a header identifying the connected account")
+	exportCmd.Cmd.Flags().BoolVar(&exportCmd.withRelated, "with-related", false, "Also export the objects referenced by the object, such as its customer")
+	exportCmd.Cmd.Flags().BoolVar(&exportCmd.livemode, "live", false, "Export the object from live mode (default: test)")
+	exportCmd.Cmd.Flags().StringVarP(&exportCmd.output, "output", "o", "", "Write the fixture to a file instead of stdout")
+
+	// Hidden configuration flags, useful for dev/debugging
+	exportCmd.Cmd.Flags().StringVar(&exportCmd.apiBaseURL, "api-base", stripe.DefaultAPIBaseURL, "Sets the API base URL")
+	exportCmd.Cmd.Flags().MarkHidden("api-base") // #nosec G104
+
+	return exportCmd
+}
+
+func (ec *FixturesExportCmd) runFixturesExportCmd(cmd *cobra.Command, args []string) error {
+	apiKey, err := ec.Cfg.Profile.GetAPIKey(ec.livemode)
+	if err != nil {
+		return err
+	}
+
+	exporter := fixtures.Exporter{
+		APIKey:        apiKey,
+		StripeAccount: ec.stripeAccount,
+		BaseURL:       ec.apiBaseURL,
+		WithRelated:   ec.withRelated,
+	}
+
+	fixture, err := exporter.Export(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	if ec.output != "" {
+		return afero.WriteFile(afero.NewOsFs(), ec.output, fixture, 0644)
+	}
+
+	fmt.Println(string(fixture))
+
+	return nil
+}
+
+// printRollback reports how a transactional fixture was rolled back
+func printRollback(out io.Writer, rollbackErr fixtures.RollbackError) {
+	if len(rollbackErr.Remaining) == 0 {
+		fmt.Fprintf(out, "Fixture failed at: %s. The objects it created were rolled back.\n", rollbackErr.Step)
+		return
+	}
+
+	fmt.Fprintf(out, "Fixture failed at: %s. These objects couldn't be rolled back, and remain in your account:\n", rollbackErr.Step)
+	for _, remaining := range rollbackErr.Remaining {
+		fmt.Fprintf(out, "  %s\n", remaining)
+	}
+}
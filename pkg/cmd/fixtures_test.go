@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/config"
+)
+
+func TestFixturesExportLive(t *testing.T) {
+	t.Setenv("STRIPE_API_KEY", "")
+
+	profilesFile := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(profilesFile, []byte(`[default]
+  test_mode_api_key = "sk_test_1234567890abcdef"
+  live_mode_api_key = "sk_live_1234567890abcdef"
+`), 0600))
+
+	cfg := &config.Config{
+		LogLevel:     "info",
+		ProfilesFile: profilesFile,
+		Profile:      config.Profile{ProfileName: "default"},
+	}
+	cfg.InitConfig()
+
+	var authorization string
+	ts := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		authorization = req.Header.Get("Authorization")
+		res.Write([]byte(`{"id": "cus_123", "object": "customer", "email": "jenny@example.com"}`))
+	}))
+	defer ts.Close()
+
+	for _, tt := range []struct {
+		flags []string
+		key   string
+	}{
+		{[]string{}, "sk_test_1234567890abcdef"},
+		{[]string{"--live"}, "sk_live_1234567890abcdef"},
+	} {
+		ec := newFixturesExportCmd(cfg)
+		ec.Cmd.SetContext(context.Background())
+		require.NoError(t, ec.Cmd.ParseFlags(append(tt.flags, "--api-base", ts.URL, "--output", filepath.Join(t.TempDir(), "customer.json"))))
+		require.NoError(t, ec.runFixturesExportCmd(ec.Cmd, []string{"cus_123"}))
+		require.Equal(t, "Bearer "+tt.key, authorization)
+	}
+}
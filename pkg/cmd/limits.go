@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/requests"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+type limitsCmd struct {
+	cmd *cobra.Command
+
+	since time.Duration
+	clear bool
+}
+
+func newLimitsCmd() *limitsCmd {
+	lc := &limitsCmd{}
+
+	lc.cmd = &cobra.Command{
+		Use:   "limits",
+		Args:  validators.NoArgs,
+		Short: "Show the rate limits the CLI ran into recently",
+		Long: `Show the rate limits the CLI observed in recent API responses, to help tune
+bulk operations: the headroom left according to the last rate limit headers,
+the requests rejected by concurrency limiters, and the requests rejected or
+retried by the CLI, per endpoint.
+
+Observations are kept for 24 hours, across every command run.`,
+		Example: `stripe limits
+  stripe limits --since 10m`,
+		RunE: lc.runLimitsCmd,
+	}
+
+	lc.cmd.Flags().DurationVar(&lc.since, "since", time.Hour, "How far back to look for rate limits (e.g. 10m)")
+	lc.cmd.Flags().BoolVar(&lc.clear, "clear", false, "Forget the rate limits observed so far")
+
+	return lc
+}
+
+func (lc *limitsCmd) runLimitsCmd(cmd *cobra.Command, args []string) error {
+	log := requests.NewRateLimitLog(fs, "")
+
+	if lc.clear {
+		if err := log.Clear(); err != nil {
+			return err
+		}
+
+		fmt.Println("Cleared the observed rate limits.")
+
+		return nil
+	}
+
+	if lc.since <= 0 {
+		return clierrors.New(clierrors.InvalidFlagValue, "--since must be a positive duration")
+	}
+
+	now := time.Now()
+
+	observations, err := log.Read(now.Add(-lc.since))
+	if err != nil {
+		return err
+	}
+
+	summarizeRateLimits(observations).print(os.Stdout, now, lc.since)
+
+	return nil
+}
+
+// concurrencyLimit counts the requests a concurrency limiter rejected
+type concurrencyLimit struct {
+	reason   string
+	livemode bool
+	rejected int
+	last     time.Time
+}
+
+// endpointThrottling counts the requests to an endpoint that were rate limited
+// and the retries the CLI made because of it
+type endpointThrottling struct {
+	endpoint    string
+	rateLimited int
+	retries     int
+	waited      time.Duration
+}
+
+// rateLimitSummary is what the observed rate limits tell, by mode and endpoint
+type rateLimitSummary struct {
+	// headroom is the last observation with rate limit headers, by livemode
+	headroom    map[bool]requests.RateLimitObservation
+	concurrency []*concurrencyLimit
+	endpoints   []*endpointThrottling
+}
+
+func summarizeRateLimits(observations []requests.RateLimitObservation) rateLimitSummary {
+	summary := rateLimitSummary{headroom: make(map[bool]requests.RateLimitObservation)}
+
+	concurrency := make(map[string]*concurrencyLimit)
+	endpoints := make(map[string]*endpointThrottling)
+
+	for _, observation := range observations {
+		if observation.Limit > 0 && !observation.Time.Before(summary.headroom[observation.Livemode].Time) {
+			summary.headroom[observation.Livemode] = observation
+		}
+
+		if observation.RateLimited() && observation.Concurrency() {
+			key := fmt.Sprintf("%s %t", observation.Reason, observation.Livemode)
+			if concurrency[key] == nil {
+				concurrency[key] = &concurrencyLimit{reason: observation.Reason, livemode: observation.Livemode}
+				summary.concurrency = append(summary.concurrency, concurrency[key])
+			}
+
+			concurrency[key].rejected++
+			if observation.Time.After(concurrency[key].last) {
+				concurrency[key].last = observation.Time
+			}
+		}
+
+		if !observation.RateLimited() && observation.Backoff == 0 {
+			continue
+		}
+
+		key := observation.Method + " " + observation.Endpoint
+		if endpoints[key] == nil {
+			endpoints[key] = &endpointThrottling{endpoint: key}
+			summary.endpoints = append(summary.endpoints, endpoints[key])
+		}
+
+		if observation.RateLimited() {
+			endpoints[key].rateLimited++
+		}
+		if observation.Backoff > 0 {
+			endpoints[key].retries++
+			endpoints[key].waited += observation.Backoff
+		}
+	}
+
+	sort.Slice(summary.endpoints, func(i, j int) bool {
+		if summary.endpoints[i].rateLimited != summary.endpoints[j].rateLimited {
+			return summary.endpoints[i].rateLimited > summary.endpoints[j].rateLimited
+		}
+		return summary.endpoints[i].endpoint < summary.endpoints[j].endpoint
+	})
+
+	return summary
+}
+
+func (s rateLimitSummary) print(out io.Writer, now time.Time, since time.Duration) {
+	fmt.Fprintf(out, "Rate limits observed in the last %s\n", since)
+
+	fmt.Fprintln(out, "\nHeadroom:")
+	for _, livemode := range []bool{false, true} {
+		observation, ok := s.headroom[livemode]
+		if !ok {
+			fmt.Fprintf(out, "  %s: no rate limit headers were received\n", modeName(livemode))
+			continue
+		}
+
+		fmt.Fprintf(out, "  %s: %d of %d requests left (%d%%), %s ago on %s %s\n",
+			modeName(livemode),
+			observation.Remaining,
+			observation.Limit,
+			observation.Remaining*100/observation.Limit,
+			now.Sub(observation.Time).Round(time.Second),
+			observation.Method,
+			observation.Endpoint,
+		)
+	}
+
+	fmt.Fprintln(out, "\nConcurrency limits:")
+	if len(s.concurrency) == 0 {
+		fmt.Fprintln(out, "  none were hit")
+	}
+	for _, limit := range s.concurrency {
+		fmt.Fprintf(out, "  %s: %s rejected %d request(s), last %s ago\n",
+			modeName(limit.livemode),
+			limit.reason,
+			limit.rejected,
+			now.Sub(limit.last).Round(time.Second),
+		)
+	}
+
+	fmt.Fprintln(out, "\nThrottling by endpoint:")
+	if len(s.endpoints) == 0 {
+		fmt.Fprintln(out, "  no requests were rate limited")
+	}
+	for _, endpoint := range s.endpoints {
+		fmt.Fprintf(out, "  %s: rate limited %d time(s), retried %d time(s) after waiting %s\n",
+			endpoint.endpoint,
+			endpoint.rateLimited,
+			endpoint.retries,
+			endpoint.waited,
+		)
+	}
+}
+
+func modeName(livemode bool) string {
+	if livemode {
+		return "Live mode"
+	}
+
+	return "Test mode"
+}
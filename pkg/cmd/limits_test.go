@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/requests"
+)
+
+func TestSummarizeRateLimits(t *testing.T) {
+	now := time.Now()
+
+	summary := summarizeRateLimits([]requests.RateLimitObservation{
+		{Time: now.Add(-3 * time.Minute), Method: http.MethodGet, Endpoint: "/v1/charges", StatusCode: 200, Limit: 100, Remaining: 10},
+		{Time: now.Add(-time.Minute), Method: http.MethodGet, Endpoint: "/v1/customers", StatusCode: 200, Limit: 100, Remaining: 80},
+		{Time: now.Add(-time.Minute), Method: http.MethodGet, Endpoint: "/v1/customers", StatusCode: 429, Reason: "global-rate"},
+		{Time: now.Add(-time.Minute), Method: http.MethodGet, Endpoint: "/v1/customers", Backoff: time.Second},
+		{Time: now.Add(-time.Minute), Method: http.MethodGet, Endpoint: "/v1/customers", StatusCode: 429, Reason: "global-rate"},
+		{Time: now.Add(-time.Minute), Method: http.MethodGet, Endpoint: "/v1/customers", Backoff: 2 * time.Second},
+		{Time: now.Add(-2 * time.Minute), Method: http.MethodPost, Endpoint: "/v1/payment_intents/{id}", StatusCode: 429, Reason: "endpoint-concurrency", Livemode: true},
+	})
+
+	require.Equal(t, 80, summary.headroom[false].Remaining)
+	_, ok := summary.headroom[true]
+	require.False(t, ok)
+
+	require.Len(t, summary.concurrency, 1)
+	require.Equal(t, "endpoint-concurrency", summary.concurrency[0].reason)
+	require.True(t, summary.concurrency[0].livemode)
+	require.Equal(t, 1, summary.concurrency[0].rejected)
+
+	require.Len(t, summary.endpoints, 2)
+	require.Equal(t, &endpointThrottling{endpoint: "GET /v1/customers", rateLimited: 2, retries: 2, waited: 3 * time.Second}, summary.endpoints[0])
+	require.Equal(t, &endpointThrottling{endpoint: "POST /v1/payment_intents/{id}", rateLimited: 1}, summary.endpoints[1])
+
+	var out bytes.Buffer
+	summary.print(&out, now, time.Hour)
+
+	require.Contains(t, out.String(), "Test mode: 80 of 100 requests left (80%), 1m0s ago on GET /v1/customers")
+	require.Contains(t, out.String(), "Live mode: no rate limit headers were received")
+	require.Contains(t, out.String(), "Live mode: endpoint-concurrency rejected 1 request(s), last 2m0s ago")
+	require.Contains(t, out.String(), "GET /v1/customers: rate limited 2 time(s), retried 2 time(s) after waiting 3s")
+}
+
+func TestSummarizeRateLimits_NothingObserved(t *testing.T) {
+	var out bytes.Buffer
+	summarizeRateLimits(nil).print(&out, time.Now(), time.Hour)
+
+	require.Contains(t, out.String(), "none were hit")
+	require.Contains(t, out.String(), "no requests were rate limited")
+}
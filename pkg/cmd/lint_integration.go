@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/integrationlint"
+	"github.com/stripe/stripe-cli/pkg/requests"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+type lintIntegrationCmd struct {
+	cmd *cobra.Command
+
+	specLocation string
+	samples      int
+	livemode     bool
+	apiBaseURL   string
+}
+
+func newLintIntegrationCmd() *lintIntegrationCmd {
+	lc := &lintIntegrationCmd{}
+
+	lc.cmd = &cobra.Command{
+		Use:   "lint-integration <expectations.yaml>",
+		Args:  validators.ExactArgs(1),
+		Short: "Check that the fields your integration reads still exist",
+		Long: `Check that the fields your integration reads from objects and events still
+exist and aren't deprecated, in the OpenAPI specification of the API version it
+targets and in recent payloads of your account. The fields are declared in a
+YAML file:
+
+  api_version: 2022-08-01
+  objects:
+    charge:
+      - amount
+      - payment_method_details.card.brand
+  events:
+    invoice.paid:
+      - amount_paid
+      - customer
+
+The fields of events are those of the object of the event. The specification
+is the latest published one unless --spec is passed, and objects are sampled
+in the api_version of the file, when it's set.
+
+Exits with an error when any field drifted, to be run in CI.`,
+		Example: `stripe lint-integration expectations.yaml
+  stripe lint-integration expectations.yaml --spec openapi/spec3.json --samples 20`,
+		RunE: lc.runLintIntegrationCmd,
+	}
+
+	lc.cmd.Flags().StringVar(&lc.specLocation, "spec", integrationlint.DefaultSpecURL, "Path or URL of the OpenAPI specification of the API version to check against")
+	lc.cmd.Flags().IntVar(&lc.samples, "samples", 5, "How many recent objects and events of each type to check, 0 to only check the specification")
+	lc.cmd.Flags().BoolVar(&lc.livemode, "live", false, "Sample live mode payloads (default: test)")
+
+	// Hidden configuration flags, useful for dev/debugging
+	lc.cmd.Flags().StringVar(&lc.apiBaseURL, "api-base", stripe.DefaultAPIBaseURL, "Sets the API base URL")
+	lc.cmd.Flags().MarkHidden("api-base") // #nosec G104
+
+	return lc
+}
+
+func (lc *lintIntegrationCmd) runLintIntegrationCmd(cmd *cobra.Command, args []string) error {
+	if lc.samples < 0 {
+		return clierrors.Errorf(clierrors.InvalidFlagValue, "--samples must not be negative, got %d", lc.samples)
+	}
+
+	expectations, err := integrationlint.LoadExpectations(afero.NewOsFs(), args[0])
+	if err != nil {
+		return err
+	}
+
+	s, err := integrationlint.LoadSpec(cmd.Context(), afero.NewOsFs(), lc.specLocation)
+	if err != nil {
+		return err
+	}
+
+	if expectations.APIVersion != "" && s.Info.Version != "" && expectations.APIVersion != s.Info.Version {
+		fmt.Fprintf(os.Stderr, "Warning: %s targets API version %s, but the specification describes %s. Pass the specification of %s with --spec.\n",
+			args[0], expectations.APIVersion, s.Info.Version, expectations.APIVersion)
+	}
+
+	opts := integrationlint.Options{Samples: lc.samples}
+	if lc.samples > 0 {
+		apiKey, err := Config.Profile.GetAPIKey(lc.livemode)
+		if err != nil {
+			return err
+		}
+
+		opts.Fetch = lc.fetcher(apiKey, expectations.APIVersion)
+	}
+
+	results, err := integrationlint.Lint(cmd.Context(), s, expectations, opts)
+	if err != nil {
+		return err
+	}
+
+	drifted := printLintResults(os.Stdout, results)
+	if drifted > 0 {
+		return clierrors.Errorf(clierrors.IntegrationDrift, "%d of %d fields drifted from %s", drifted, len(results), args[0])
+	}
+
+	return nil
+}
+
+// fetcher lists objects with the API, in apiVersion when it's set
+func (lc *lintIntegrationCmd) fetcher(apiKey, apiVersion string) integrationlint.Fetch {
+	return func(ctx context.Context, path string, data []string) ([]map[string]interface{}, error) {
+		base := &requests.Base{
+			Method:         http.MethodGet,
+			SuppressOutput: true,
+			APIBaseURL:     lc.apiBaseURL,
+			Livemode:       lc.livemode,
+		}
+
+		params := &requests.RequestParameters{}
+		params.AppendData(data)
+		if apiVersion != "" {
+			params.SetVersion(apiVersion)
+		}
+
+		body, err := base.MakeRequest(ctx, apiKey, path, params, true)
+		if err != nil {
+			return nil, err
+		}
+
+		var list struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &list); err != nil {
+			return nil, err
+		}
+
+		return list.Data, nil
+	}
+}
+
+// printLintResults prints the results of a lint, and returns how many fields
+// have problems
+func printLintResults(out io.Writer, results []integrationlint.Result) int {
+	color := ansi.Color(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	drifted := 0
+	for _, result := range results {
+		if len(result.Problems) == 0 {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", color.Green("✔"), result.Subject, result.Field)
+			continue
+		}
+
+		drifted++
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", color.Red("✘"), result.Subject, result.Field, strings.Join(result.Problems, "; "))
+	}
+	w.Flush()
+
+	return drifted
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"strings"
@@ -13,11 +14,18 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"golang.org/x/term"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/otellogs"
 	"github.com/stripe/stripe-cli/pkg/proxy"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+	"github.com/stripe/stripe-cli/pkg/tui"
 	"github.com/stripe/stripe-cli/pkg/validators"
 	"github.com/stripe/stripe-cli/pkg/version"
+	"github.com/stripe/stripe-cli/pkg/webhooks"
 	"github.com/stripe/stripe-cli/pkg/websocket"
 )
 
@@ -32,6 +40,8 @@ type listenCmd struct {
 	forwardHeaders        []string
 	forwardConnectHeaders []string
 	forwardConnectURL     string
+	shadowURL             string
+	shadowSecret          string
 	events                []string
 	latestAPIVersion      bool
 	livemode              bool
@@ -44,6 +54,13 @@ type listenCmd struct {
 	apiBaseURL            string
 	noWSS                 bool
 	timeout               int64
+	tui                   bool
+	latencyReport         bool
+	reportEvery           time.Duration
+	output                string
+	registerEndpoint      string
+	cleanupOnExit         bool
+	otelLogsEndpoint      string
 }
 
 func newListenCmd() *listenCmd {
@@ -59,15 +76,25 @@ API version, filter events, or even load your saved webhook endpoints from your
 Stripe account.`,
 		Example: `stripe listen
   stripe listen --events charge.captured,charge.updated \
-    --forward-to localhost:3000/events`,
+    --forward-to localhost:3000/events
+  stripe listen --forward-to localhost:3000/events \
+    --shadow https://staging.example.com/events
+  stripe listen --forward-to localhost:3000/events \
+    --latency-report --report-every 5m
+  stripe listen --events charge.captured \
+    --register-endpoint https://ci.example.com/hooks --cleanup-on-exit
+  stripe listen --forward-to localhost:3000/events \
+    --otel-logs-endpoint http://localhost:4318`,
 		RunE: lc.runListenCmd,
 	}
 
 	lc.cmd.Flags().StringSliceVar(&lc.forwardConnectHeaders, "connect-headers", []string{}, "A comma-separated list of custom headers to forward for Connect. Ex: \"Key1:Value1, Key2:Value2\"")
-	lc.cmd.Flags().StringSliceVarP(&lc.events, "events", "e", []string{"*"}, "A comma-separated list of specific events to listen for. For a list of all possible events, see: https://stripe.com/docs/api/events/types")
+	lc.cmd.Flags().StringSliceVarP(&lc.events, "events", "e", []string{"*"}, "A comma-separated list of specific events to listen for. For a list of all possible events, see: https://stripe.com/docs/api/events/types. When omitted in a terminal, you're prompted to pick the events to listen for")
 	lc.cmd.Flags().StringVarP(&lc.forwardURL, "forward-to", "f", "", "The URL to forward webhook events to")
 	lc.cmd.Flags().StringSliceVarP(&lc.forwardHeaders, "headers", "H", []string{}, "A comma-separated list of custom headers to forward. Ex: \"Key1:Value1, Key2:Value2\"")
 	lc.cmd.Flags().StringVarP(&lc.forwardConnectURL, "forward-connect-to", "c", "", "The URL to forward Connect webhook events to (default: same as normal events)")
+	lc.cmd.Flags().StringVar(&lc.shadowURL, "shadow", "", "Also forward events to this URL, and report events for which its response differs from the one of --forward-to")
+	lc.cmd.Flags().StringVar(&lc.shadowSecret, "shadow-secret", "", "The signing secret of the --shadow endpoint, to sign the events forwarded to it with (default: the signing secret of the session)")
 	lc.cmd.Flags().BoolVarP(&lc.latestAPIVersion, "latest", "l", false, "Receive events formatted with the latest API version (default: your account's default API version)")
 	lc.cmd.Flags().BoolVar(&lc.livemode, "live", false, "Receive live events (default: test)")
 	lc.cmd.Flags().BoolVarP(&lc.printJSON, "print-json", "j", false, "Print full JSON objects to stdout.")
@@ -79,6 +106,13 @@ Stripe account.`,
 	lc.cmd.Flags().BoolVarP(&lc.skipVerify, "skip-verify", "", false, "Skip certificate verification when forwarding to HTTPS endpoints")
 	lc.cmd.Flags().BoolVar(&lc.onlyPrintSecret, "print-secret", false, "Only print the webhook signing secret and exit")
 	lc.cmd.Flags().BoolVarP(&lc.skipUpdate, "skip-update", "s", false, "Skip checking latest version of Stripe CLI")
+	lc.cmd.Flags().BoolVar(&lc.tui, "tui", false, "Show an interactive dashboard to browse, filter and re-send events")
+	lc.cmd.Flags().BoolVar(&lc.latencyReport, "latency-report", false, "When the session ends, print per event type how long events took to be received after being created, and how long your endpoints took to respond")
+	lc.cmd.Flags().DurationVar(&lc.reportEvery, "report-every", 0, "Also print the latency report of the events received in each period of this duration, such as 5m")
+	lc.cmd.Flags().StringVar(&lc.output, "output", "text", "The format of the latency report: text or json")
+	lc.cmd.Flags().StringVar(&lc.registerEndpoint, "register-endpoint", "", "Register a webhook endpoint for this public URL, to which Stripe sends the events directly instead of through the CLI")
+	lc.cmd.Flags().BoolVar(&lc.cleanupOnExit, "cleanup-on-exit", false, "Delete the webhook endpoint registered with --register-endpoint when the session ends")
+	lc.cmd.Flags().StringVar(&lc.otelLogsEndpoint, "otel-logs-endpoint", "", "Export the events received and the responses of your endpoints as logs to this OpenTelemetry collector (OTLP/HTTP), such as http://localhost:4318. Events are forwarded with a traceparent header, so that the logs are in the traces of your app")
 
 	// Hidden configuration flags, useful for dev/debugging
 	lc.cmd.Flags().StringVar(&lc.apiBaseURL, "api-base", "", "Sets the API base URL")
@@ -104,6 +138,26 @@ Stripe account.`,
 // Normally, this function would be listed alphabetically with the others declared in this file,
 // but since it's acting as the core functionality for the cmd above, I'm keeping it close.
 func (lc *listenCmd) runListenCmd(cmd *cobra.Command, args []string) error {
+	if lc.output != "text" && lc.output != "json" {
+		return clierrors.Errorf(clierrors.InvalidFlagValue, "unsupported --output %s, use text or json", lc.output)
+	}
+	if lc.tui && (lc.latencyReport || lc.reportEvery > 0) {
+		return clierrors.New(clierrors.ConflictingFlags, "the latency report isn't available with --tui")
+	}
+	if err := lc.validateRegisterEndpoint(cmd); err != nil {
+		return err
+	}
+
+	// --otel-logs-endpoint option
+	var exporter *otellogs.Exporter
+	if lc.otelLogsEndpoint != "" {
+		var err error
+		exporter, err = otellogs.NewExporter(lc.otelLogsEndpoint)
+		if err != nil {
+			return err
+		}
+	}
+
 	if !lc.printJSON && !lc.onlyPrintSecret && !lc.skipUpdate {
 		version.CheckLatestVersion()
 	}
@@ -124,8 +178,17 @@ func (lc *listenCmd) runListenCmd(cmd *cobra.Command, args []string) error {
 		}).Debug("Ctrl+C received, cleaning up...")
 	})
 
+	// --register-endpoint option
+	if lc.registerEndpoint != "" {
+		return lc.runRegisteredEndpoint(ctx, os.Stdout, deviceName, key)
+	}
+
 	// --print-secret option
 	if lc.onlyPrintSecret {
+		if err := confirmWithPasskey(ctx, config.PasskeyShowKeys, "Confirm showing the webhook signing secret."); err != nil {
+			return err
+		}
+
 		secret, err := proxy.GetSessionSecret(ctx, deviceName, key, lc.apiBaseURL)
 		if err != nil {
 			return err
@@ -134,16 +197,60 @@ func (lc *listenCmd) runListenCmd(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Without --events, let the user pick the events to listen for
+	if lc.shouldSelectEvents(cmd) {
+		events, err := selectEvents()
+		if err != nil {
+			return err
+		}
+		lc.events = events
+	}
+
 	logger := log.StandardLogger()
 	proxyVisitor := createVisitor(logger, lc.format, lc.printJSON)
 	proxyOutCh := make(chan websocket.IElement)
 
+	// --tui option
+	var dashboard *tui.Dashboard
+	var cancel context.CancelFunc
+	if lc.tui {
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+
+		dashboard = tui.NewDashboard()
+		proxyVisitor = createTUIVisitor(dashboard)
+
+		// Anything logged while the dashboard is drawn would corrupt it
+		out := logger.Out
+		logger.SetOutput(io.Discard)
+		defer logger.SetOutput(out)
+	}
+
+	if exporter != nil {
+		proxyVisitor = withOTelLogs(proxyVisitor, exporter)
+
+		exportCtx, stopExport := context.WithCancel(ctx)
+		exported := make(chan struct{})
+		go func() {
+			exporter.Run(exportCtx)
+			close(exported)
+		}()
+
+		// exports the last records before exiting
+		defer func() {
+			stopExport()
+			<-exported
+		}()
+	}
+
 	p, err := proxy.Init(ctx, &proxy.Config{
 		DeviceName:            deviceName,
 		Key:                   key,
 		ForwardURL:            lc.forwardURL,
 		ForwardHeaders:        lc.forwardHeaders,
 		ForwardConnectURL:     lc.forwardConnectURL,
+		ShadowURL:             lc.shadowURL,
+		ShadowSecret:          lc.shadowSecret,
 		ForwardConnectHeaders: lc.forwardConnectHeaders,
 		UseConfiguredWebhooks: lc.useConfiguredWebhooks,
 		APIBaseURL:            lc.apiBaseURL,
@@ -154,6 +261,7 @@ func (lc *listenCmd) runListenCmd(cmd *cobra.Command, args []string) error {
 		Log:                   logger,
 		NoWSS:                 lc.noWSS,
 		Timeout:               lc.timeout,
+		PropagateTraceContext: exporter != nil,
 		Events:                lc.events,
 		OutCh:                 proxyOutCh,
 	})
@@ -163,11 +271,88 @@ func (lc *listenCmd) runListenCmd(cmd *cobra.Command, args []string) error {
 
 	go p.Run(ctx)
 
-	for el := range proxyOutCh {
-		err := el.Accept(proxyVisitor)
+	if dashboard != nil {
+		dashboard.Client = p.EndpointHTTPClient()
+
+		errCh := make(chan error, 1)
+
+		go func() {
+			for el := range proxyOutCh {
+				if err := el.Accept(proxyVisitor); err != nil {
+					errCh <- err
+					cancel()
+					return
+				}
+			}
+			errCh <- nil
+		}()
+
+		err = dashboard.Run(ctx)
 		if err != nil {
 			return err
 		}
+
+		// Quitting the dashboard ends the session
+		cancel()
+
+		return <-errCh
+	}
+
+	session := newListenSession()
+
+	// --latency-report and --report-every options
+	var latency, period *listenLatency
+	var reportTicks <-chan time.Time
+	if lc.latencyReport || lc.reportEvery > 0 {
+		latency = newListenLatency(session.start)
+	}
+	if lc.reportEvery > 0 {
+		period = newListenLatency(session.start)
+
+		ticker := time.NewTicker(lc.reportEvery)
+		defer ticker.Stop()
+		reportTicks = ticker.C
+	}
+
+loop:
+	for {
+		select {
+		case el, ok := <-proxyOutCh:
+			if !ok {
+				break loop
+			}
+
+			session.record(el)
+			if latency != nil {
+				now := time.Now()
+				latency.record(el, now)
+				if period != nil {
+					period.record(el, now)
+				}
+			}
+
+			err := el.Accept(proxyVisitor)
+			if err != nil {
+				return err
+			}
+		case now := <-reportTicks:
+			if err := period.report(now).write(os.Stdout, lc.output); err != nil {
+				return err
+			}
+			period = newListenLatency(now)
+		}
+	}
+
+	now := time.Now()
+
+	// the summary tags the session with the git context, which is opted into
+	// like the tagging of fixture objects
+	if Config.Profile.GetGitMetadata() && !lc.printJSON && strings.ToUpper(lc.format) != outputFormatJSON {
+		fmt.Println(session.summary(now))
+	}
+
+	if latency != nil {
+		return latency.report(now).write(os.Stdout, lc.output)
 	}
 
 	return nil
@@ -188,6 +373,36 @@ func withSIGTERMCancel(ctx context.Context, onCancel func()) context.Context {
 	return ctx
 }
 
+// shouldSelectEvents returns true if the events to listen for should be picked
+// interactively: no events were given, and we're not being scripted
+func (lc *listenCmd) shouldSelectEvents(cmd *cobra.Command) bool {
+	if cmd.Flags().Changed("events") || lc.useConfiguredWebhooks || lc.printJSON || lc.tui || strings.ToUpper(lc.format) == outputFormatJSON {
+		return false
+	}
+
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// selectEvents shows a picker over every event type, and prints the flag to
+// pass to listen for the same events without picking them again
+func selectEvents() ([]string, error) {
+	events, err := tui.NewMultiSelect("Select the events to listen for", proxy.EventTypes()).Run()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(events) == 0 {
+		fmt.Println("No events selected, listening for all events.")
+		return []string{"*"}, nil
+	}
+
+	color := ansi.Color(os.Stdout)
+	fmt.Printf("Listening for %d events. To skip this selection next time, run:\n  %s\n",
+		len(events), color.Bold("stripe listen --events "+strings.Join(events, ",")))
+
+	return events, nil
+}
+
 func createVisitor(logger *log.Logger, format string, printJSON bool) *websocket.Visitor {
 	var s *spinner.Spinner
 
@@ -234,6 +449,7 @@ func createVisitor(logger *log.Logger, format string, printJSON bool) *websocket
 				ansi.StartSpinner(s, "Session expired, reconnecting...", logger.Out)
 			case websocket.Ready:
 				ansi.StopSpinner(s, fmt.Sprintf("Ready! %sYour webhook signing secret is %s (^C to quit)", se.Data[0], ansi.Bold(se.Data[1])), logger.Out)
+				warnClockSkew(logger.Out)
 			case websocket.Done:
 				ansi.StopSpinner(s, "", logger.Out)
 			}
@@ -277,9 +493,92 @@ func createVisitor(logger *log.Logger, format string, printJSON bool) *websocket
 				)
 				fmt.Println(outputStr)
 				return nil
+			case proxy.ShadowDivergence:
+				localTime := time.Now().Format(timeLayout)
+
+				color := ansi.Color(os.Stdout)
+				fmt.Printf("%s  [%s] %s responded differently than %s for [%s]: %s\n",
+					color.Faint(localTime),
+					color.Yellow("SHADOW"),
+					data.ShadowURL,
+					data.ForwardURL,
+					ansi.Linkify(data.Event.ID, data.Event.URLForEventID(), logger.Out),
+					strings.Join(data.Reasons, ", "),
+				)
+				if data.Body != data.ShadowBody {
+					fmt.Printf("  %s %s\n  %s %s\n", color.Faint("endpoint:"), data.Body, color.Faint("shadow:  "), data.ShadowBody)
+				}
+				return nil
 			default:
 				return fmt.Errorf("VisitData received unexpected type for DataElement, got %T", de)
 			}
 		},
 	}
 }
+
+func createTUIVisitor(dashboard *tui.Dashboard) *websocket.Visitor {
+	return &websocket.Visitor{
+		VisitError: func(ee websocket.ErrorElement) error {
+			switch ee.Error.(type) {
+			case proxy.FailedToPostError:
+				dashboard.SetStatus(fmt.Sprintf("Failed to POST: %v", ee.Error))
+				return nil
+			case proxy.FailedToReadResponseError:
+				dashboard.SetStatus(fmt.Sprintf("Failed to read response from endpoint, error = %v", ee.Error))
+				return nil
+			default:
+				return ee.Error
+			}
+		},
+		VisitStatus: func(se websocket.StateElement) error {
+			switch se.State {
+			case websocket.Loading:
+				dashboard.SetStatus("Getting ready...")
+			case websocket.Reconnecting:
+				dashboard.SetStatus("Session expired, reconnecting...")
+			case websocket.Ready:
+				dashboard.SetSecret(se.Data[1])
+
+				status := fmt.Sprintf("Ready! Your webhook signing secret is %s", se.Data[1])
+				if warning := clockSkewWarning(); warning != "" {
+					status += ". Warning: " + warning
+				}
+				dashboard.SetStatus(status)
+			}
+			return nil
+		},
+		VisitData: func(de websocket.DataElement) error {
+			switch data := de.Data.(type) {
+			case proxy.StripeEvent:
+				dashboard.AddEvent(data, de.Marshaled)
+				return nil
+			case proxy.EndpointResponse:
+				dashboard.AddResponse(data)
+				return nil
+			case proxy.ShadowDivergence:
+				dashboard.SetStatus(fmt.Sprintf("Shadow endpoint diverged for %s: %s", data.Event.ID, strings.Join(data.Reasons, ", ")))
+				return nil
+			default:
+				return fmt.Errorf("VisitData received unexpected type for DataElement, got %T", de)
+			}
+		},
+	}
+}
+
+// clockSkewWarning explains how the local clock breaks signature verification,
+// when the last response of Stripe showed it's too far from Stripe's
+func clockSkewWarning() string {
+	skew, ok := stripe.ClockSkew()
+	if !ok {
+		return ""
+	}
+
+	return webhooks.ClockSkewWarning(skew)
+}
+
+func warnClockSkew(out io.Writer) {
+	if warning := clockSkewWarning(); warning != "" {
+		color := ansi.Color(out)
+		fmt.Fprintf(out, "%s %s\n", color.Yellow("Warning:"), warning)
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/requests"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+)
+
+// endpointCleanupTimeout bounds how long deleting the registered endpoint may
+// take once the session has ended
+const endpointCleanupTimeout = 10 * time.Second
+
+// listenFlagsWithoutCLIDelivery are the flags that only make sense when events
+// are delivered through the CLI, which isn't the case with --register-endpoint
+var listenFlagsWithoutCLIDelivery = []string{
+	"forward-to",
+	"forward-connect-to",
+	"headers",
+	"connect-headers",
+	"shadow",
+	"use-configured-webhooks",
+	"print-secret",
+	"tui",
+	"latency-report",
+	"report-every",
+	"skip-verify",
+	"otel-logs-endpoint",
+}
+
+// validateRegisterEndpoint checks that the flags passed along with
+// --register-endpoint or --cleanup-on-exit can be honored
+func (lc *listenCmd) validateRegisterEndpoint(cmd *cobra.Command) error {
+	if lc.registerEndpoint == "" {
+		if lc.cleanupOnExit {
+			return clierrors.New(clierrors.ConflictingFlags, "--cleanup-on-exit can only be passed with --register-endpoint")
+		}
+		return nil
+	}
+
+	if !strings.HasPrefix(lc.registerEndpoint, "https://") && !strings.HasPrefix(lc.registerEndpoint, "http://") {
+		return clierrors.Errorf(clierrors.InvalidFlagValue, "--register-endpoint must be a public URL such as https://example.com/hooks, got %s", lc.registerEndpoint)
+	}
+
+	for _, name := range listenFlagsWithoutCLIDelivery {
+		if cmd.Flags().Changed(name) {
+			return clierrors.Errorf(clierrors.ConflictingFlags, "--%s can't be passed with --register-endpoint, Stripe sends the events to the endpoint directly", name)
+		}
+	}
+
+	return nil
+}
+
+// runRegisteredEndpoint creates a webhook endpoint sending the events straight
+// to --register-endpoint instead of through the CLI, and waits for the session
+// to end. With --cleanup-on-exit, the endpoint is deleted then.
+func (lc *listenCmd) runRegisteredEndpoint(ctx context.Context, out io.Writer, deviceName, key string) error {
+	apiBaseURL := lc.apiBaseURL
+	if apiBaseURL == "" {
+		apiBaseURL = stripe.DefaultAPIBaseURL
+	}
+
+	// Without --latest, the endpoint gets the account's default API version
+	apiVersion := ""
+	if lc.latestAPIVersion {
+		apiVersion = stripe.APIVersion
+	}
+
+	// the secret is shown once the endpoint is registered, so it's confirmed
+	// first, as with --print-secret
+	if err := confirmWithPasskey(ctx, config.PasskeyShowKeys, "Confirm showing the signing secret of the registered webhook endpoint."); err != nil {
+		return err
+	}
+
+	description := fmt.Sprintf("Registered by stripe listen on %s", deviceName)
+
+	endpoint, err := requests.WebhookEndpointRegister(ctx, apiBaseURL, apiVersion, key, lc.registerEndpoint, description, lc.events, lc.livemode, &Config.Profile)
+	if err != nil {
+		return fmt.Errorf("failed to register the webhook endpoint: %w", err)
+	}
+
+	color := ansi.Color(out)
+	fmt.Fprintf(out, "Ready! Registered webhook endpoint %s sending %s to %s\n", color.Bold(endpoint.ID), describeEvents(lc.events), lc.registerEndpoint)
+	warnClockSkew(out)
+	fmt.Fprintf(out, "Your webhook signing secret is %s (^C to quit)\n", color.Bold(endpoint.Secret))
+
+	<-ctx.Done()
+
+	if !lc.cleanupOnExit {
+		fmt.Fprintf(out, "The endpoint is kept, delete it with: stripe webhook_endpoints delete %s\n", endpoint.ID)
+		return nil
+	}
+
+	// The session's context is done, give the deletion its own
+	cleanupCtx, cancel := context.WithTimeout(context.Background(), endpointCleanupTimeout)
+	defer cancel()
+
+	if err := requests.WebhookEndpointDelete(cleanupCtx, apiBaseURL, "", key, endpoint.ID, lc.livemode, &Config.Profile); err != nil {
+		log.WithFields(log.Fields{
+			"prefix": "cmd.listenCmd.runRegisteredEndpoint",
+		}).Debug(err)
+
+		return clierrors.Errorf(clierrors.WebhookEndpointNotDeleted, "failed to delete webhook endpoint %s, delete it with: stripe webhook_endpoints delete %s", endpoint.ID, endpoint.ID)
+	}
+
+	fmt.Fprintf(out, "%s Deleted webhook endpoint %s\n", color.Green("✔"), endpoint.ID)
+
+	return nil
+}
+
+func describeEvents(events []string) string {
+	if len(events) == 0 || (len(events) == 1 && events[0] == "*") {
+		return "all events"
+	}
+
+	return strings.Join(events, ", ")
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+// quitWhenReady ends the session as soon as the endpoint is registered
+type quitWhenReady struct {
+	bytes.Buffer
+	cancel context.CancelFunc
+}
+
+func (q *quitWhenReady) Write(p []byte) (int, error) {
+	if bytes.Contains(p, []byte("^C to quit")) {
+		defer q.cancel()
+	}
+	return q.Buffer.Write(p)
+}
+
+func TestRunRegisteredEndpointCleansUp(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var created url.Values
+	deleted := ""
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			created, err = url.ParseQuery(string(body))
+			require.NoError(t, err)
+
+			w.Write([]byte(`{"id": "we_123", "secret": "whsec_abc", "url": "https://ci.example.com/hooks"}`))
+		case http.MethodDelete:
+			deleted = r.URL.Path
+			w.Write([]byte(`{"id": "we_123", "deleted": true}`))
+		}
+	}))
+	defer ts.Close()
+
+	lc := &listenCmd{
+		registerEndpoint: "https://ci.example.com/hooks",
+		cleanupOnExit:    true,
+		events:           []string{"charge.captured", "charge.refunded"},
+		apiBaseURL:       ts.URL,
+	}
+
+	out := &quitWhenReady{cancel: cancel}
+	require.NoError(t, lc.runRegisteredEndpoint(ctx, out, "ci-runner", "sk_test_123"))
+
+	require.Equal(t, "https://ci.example.com/hooks", created.Get("url"))
+	require.Equal(t, []string{"charge.captured", "charge.refunded"}, created["enabled_events[]"])
+	require.Equal(t, "Registered by stripe listen on ci-runner", created.Get("description"))
+	require.Equal(t, "stripe-cli", created.Get("metadata[created_by]"))
+	require.Equal(t, "/v1/webhook_endpoints/we_123", deleted)
+
+	require.Contains(t, out.String(), "Registered webhook endpoint we_123 sending charge.captured, charge.refunded to https://ci.example.com/hooks")
+	require.Contains(t, out.String(), "Your webhook signing secret is whsec_abc")
+	require.Contains(t, out.String(), "Deleted webhook endpoint we_123")
+}
+
+func TestRunRegisteredEndpointKeepsEndpoint(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		// the local clock is 10 minutes ahead of Stripe's
+		w.Header().Set("Date", time.Now().Add(-10*time.Minute).UTC().Format(http.TimeFormat))
+		w.Write([]byte(`{"id": "we_123", "secret": "whsec_abc"}`))
+	}))
+	defer ts.Close()
+
+	lc := &listenCmd{
+		registerEndpoint: "https://ci.example.com/hooks",
+		events:           []string{"*"},
+		apiBaseURL:       ts.URL,
+	}
+
+	out := &quitWhenReady{cancel: cancel}
+	require.NoError(t, lc.runRegisteredEndpoint(ctx, out, "ci-runner", "sk_test_123"))
+
+	require.Contains(t, out.String(), "sending all events to")
+	require.Contains(t, out.String(), "stripe webhook_endpoints delete we_123")
+	require.Contains(t, out.String(), "Warning: your clock is 10m0s ahead of Stripe's.")
+}
+
+func TestValidateRegisterEndpoint(t *testing.T) {
+	newCmd := func(args ...string) (*listenCmd, *cobra.Command) {
+		lc := newListenCmd()
+		require.NoError(t, lc.cmd.Flags().Parse(args))
+		return lc, lc.cmd
+	}
+
+	lc, cmd := newCmd("--register-endpoint", "https://ci.example.com/hooks", "--cleanup-on-exit", "--events", "charge.captured")
+	require.NoError(t, lc.validateRegisterEndpoint(cmd))
+
+	lc, cmd = newCmd("--cleanup-on-exit")
+	require.EqualError(t, lc.validateRegisterEndpoint(cmd), "--cleanup-on-exit can only be passed with --register-endpoint")
+
+	lc, cmd = newCmd("--register-endpoint", "https://ci.example.com/hooks", "--forward-to", "localhost:3000")
+	require.ErrorContains(t, lc.validateRegisterEndpoint(cmd), "--forward-to can't be passed with --register-endpoint")
+
+	lc, cmd = newCmd("--register-endpoint", "ci.example.com/hooks")
+	require.ErrorContains(t, lc.validateRegisterEndpoint(cmd), "must be a public URL")
+}
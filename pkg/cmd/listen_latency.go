@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/stripe/stripe-cli/pkg/proxy"
+	"github.com/stripe/stripe-cli/pkg/websocket"
+)
+
+// listenLatency times the events received while listening, per event type:
+// how long they took to reach the CLI after Stripe created them, and how long
+// the local endpoints took to respond to them
+type listenLatency struct {
+	since time.Time
+	types map[string]*latencyStats
+}
+
+type latencyStats struct {
+	delivery  []time.Duration
+	responses []time.Duration
+	non2xx    int
+}
+
+// latencySummary is the JSON form of the percentiles of a latency
+type latencySummary struct {
+	P50 int64 `json:"p50"`
+	P95 int64 `json:"p95"`
+	Max int64 `json:"max"`
+}
+
+type latencyRow struct {
+	Type       string          `json:"type"`
+	Events     int             `json:"events"`
+	DeliveryMS *latencySummary `json:"delivery_ms,omitempty"`
+	Responses  int             `json:"responses"`
+	ResponseMS *latencySummary `json:"response_ms,omitempty"`
+	Non2xx     int             `json:"non_2xx"`
+}
+
+type latencyReport struct {
+	Since      time.Time    `json:"since"`
+	Until      time.Time    `json:"until"`
+	EventTypes []latencyRow `json:"event_types"`
+}
+
+func newListenLatency(since time.Time) *listenLatency {
+	return &listenLatency{
+		since: since,
+		types: make(map[string]*latencyStats),
+	}
+}
+
+func (l *listenLatency) stats(eventType string) *latencyStats {
+	stats, ok := l.types[eventType]
+	if !ok {
+		stats = &latencyStats{}
+		l.types[eventType] = stats
+	}
+
+	return stats
+}
+
+// record times an element received from the proxy at now
+func (l *listenLatency) record(el websocket.IElement, now time.Time) {
+	de, ok := el.(websocket.DataElement)
+	if !ok {
+		return
+	}
+
+	switch data := de.Data.(type) {
+	case proxy.StripeEvent:
+		stats := l.stats(data.Type)
+
+		// events are created at a second's precision, and the clocks may
+		// disagree by a bit
+		delivery := now.Sub(time.Unix(int64(data.Created), 0))
+		if data.Created == 0 || delivery < 0 {
+			delivery = 0
+		}
+		stats.delivery = append(stats.delivery, delivery)
+	case proxy.EndpointResponse:
+		if data.Event == nil {
+			return
+		}
+
+		stats := l.stats(data.Event.Type)
+		stats.responses = append(stats.responses, data.Duration)
+		if data.Resp != nil && data.Resp.StatusCode >= 300 {
+			stats.non2xx++
+		}
+	}
+}
+
+// report summarizes the latencies recorded since l.since
+func (l *listenLatency) report(until time.Time) latencyReport {
+	report := latencyReport{
+		Since:      l.since,
+		Until:      until,
+		EventTypes: []latencyRow{},
+	}
+
+	for eventType, stats := range l.types {
+		report.EventTypes = append(report.EventTypes, latencyRow{
+			Type:       eventType,
+			Events:     len(stats.delivery),
+			DeliveryMS: summarizeLatency(stats.delivery),
+			Responses:  len(stats.responses),
+			ResponseMS: summarizeLatency(stats.responses),
+			Non2xx:     stats.non2xx,
+		})
+	}
+
+	sort.Slice(report.EventTypes, func(i, j int) bool {
+		return report.EventTypes[i].Type < report.EventTypes[j].Type
+	})
+
+	return report
+}
+
+func summarizeLatency(latencies []time.Duration) *latencySummary {
+	if len(latencies) == 0 {
+		return nil
+	}
+
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return &latencySummary{
+		P50: percentile(sorted, 50).Milliseconds(),
+		P95: percentile(sorted, 95).Milliseconds(),
+		Max: sorted[len(sorted)-1].Milliseconds(),
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// write prints the report as a table, or as a line of JSON
+func (r latencyReport) write(out io.Writer, format string) error {
+	if format == "json" {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	}
+
+	fmt.Fprintf(out, "Latency from %s to %s:\n", r.Since.Format(timeLayout), r.Until.Format(timeLayout))
+
+	if len(r.EventTypes) == 0 {
+		_, err := fmt.Fprintln(out, "No events received.")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "EVENT TYPE\tEVENTS\tDELIVERY P50\tP95\tMAX\tRESPONSES\tRESPONSE P50\tP95\tMAX\tNON-2XX")
+	for _, row := range r.EventTypes {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%s\t%d\n", row.Type, row.Events, formatLatency(row.DeliveryMS), row.Responses, formatLatency(row.ResponseMS), row.Non2xx)
+	}
+
+	return w.Flush()
+}
+
+func formatLatency(summary *latencySummary) string {
+	if summary == nil {
+		return "-\t-\t-"
+	}
+
+	ms := func(v int64) string {
+		return (time.Duration(v) * time.Millisecond).String()
+	}
+
+	return fmt.Sprintf("%s\t%s\t%s", ms(summary.P50), ms(summary.P95), ms(summary.Max))
+}
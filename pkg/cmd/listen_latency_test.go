@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/proxy"
+	"github.com/stripe/stripe-cli/pkg/websocket"
+)
+
+func TestListenLatencyReport(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	latency := newListenLatency(start)
+
+	created := int(start.Unix())
+	for i, delay := range []time.Duration{1, 2, 3, 4} {
+		event := proxy.StripeEvent{ID: "evt_" + string(rune('a'+i)), Type: "charge.succeeded", Created: created}
+		latency.record(websocket.DataElement{Data: event}, start.Add(delay*time.Second))
+		latency.record(websocket.DataElement{Data: proxy.EndpointResponse{
+			Event:    &event,
+			Resp:     &http.Response{StatusCode: 200 + 300*(i/3)},
+			Duration: time.Duration(i+1) * 100 * time.Millisecond,
+		}}, start.Add(delay*time.Second))
+	}
+	latency.record(websocket.DataElement{Data: proxy.StripeEvent{Type: "customer.created", Created: created + 60}}, start)
+	latency.record(websocket.ErrorElement{Error: proxy.FailedToPostError{}}, start)
+
+	report := latency.report(start.Add(time.Minute))
+	require.Equal(t, []latencyRow{
+		{
+			Type:       "charge.succeeded",
+			Events:     4,
+			DeliveryMS: &latencySummary{P50: 2000, P95: 4000, Max: 4000},
+			Responses:  4,
+			ResponseMS: &latencySummary{P50: 200, P95: 400, Max: 400},
+			Non2xx:     1,
+		},
+		{
+			Type:       "customer.created",
+			Events:     1,
+			DeliveryMS: &latencySummary{P50: 0, P95: 0, Max: 0},
+		},
+	}, report.EventTypes)
+
+	var out bytes.Buffer
+	require.NoError(t, report.write(&out, "text"))
+	require.Equal(t, `Latency from 2024-01-01 12:00:00 to 2024-01-01 12:01:00:
+EVENT TYPE        EVENTS  DELIVERY P50  P95  MAX  RESPONSES  RESPONSE P50  P95    MAX    NON-2XX
+charge.succeeded  4       2s            4s   4s   4          200ms         400ms  400ms  1
+customer.created  1       0s            0s   0s   0          -             -      -      0
+`, out.String())
+
+	out.Reset()
+	require.NoError(t, newListenLatency(start).report(start).write(&out, "json"))
+	require.JSONEq(t, `{"since": "2024-01-01T12:00:00Z", "until": "2024-01-01T12:00:00Z", "event_types": []}`, out.String())
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stripe/stripe-cli/pkg/otellogs"
+	"github.com/stripe/stripe-cli/pkg/proxy"
+	"github.com/stripe/stripe-cli/pkg/websocket"
+)
+
+// withOTelLogs wraps a visitor to also export the events received and the
+// responses of the endpoints as log records
+func withOTelLogs(visitor *websocket.Visitor, exporter *otellogs.Exporter) *websocket.Visitor {
+	visitData := visitor.VisitData
+	visitor.VisitData = func(de websocket.DataElement) error {
+		switch data := de.Data.(type) {
+		case proxy.StripeEvent:
+			exporter.Emit(eventRecord(data, time.Now()))
+		case proxy.EndpointResponse:
+			exporter.Emit(endpointResponseRecord(data, time.Now()))
+		}
+
+		return visitData(de)
+	}
+
+	return visitor
+}
+
+// eventAttributes are the attributes of the records about an event. The ID
+// of the API request that triggered it ties them to the request logs of
+// `stripe logs tail`.
+func eventAttributes(evt *proxy.StripeEvent) map[string]interface{} {
+	attributes := map[string]interface{}{
+		"stripe.event.id":   evt.ID,
+		"stripe.event.type": evt.Type,
+		"stripe.livemode":   evt.Livemode,
+	}
+	if evt.APIVersion != "" {
+		attributes["stripe.api_version"] = evt.APIVersion
+	}
+	if evt.Account != "" {
+		attributes["stripe.account"] = evt.Account
+	}
+	if evt.Request.ID != "" {
+		attributes["stripe.request.id"] = evt.Request.ID
+	}
+
+	return attributes
+}
+
+func eventRecord(evt proxy.StripeEvent, now time.Time) otellogs.Record {
+	return otellogs.Record{
+		Time:       now,
+		Severity:   otellogs.SeverityInfo,
+		Body:       fmt.Sprintf("Received event %s [%s]", evt.Type, evt.ID),
+		Attributes: eventAttributes(&evt),
+		Trace:      evt.Trace,
+	}
+}
+
+func endpointResponseRecord(resp proxy.EndpointResponse, now time.Time) otellogs.Record {
+	attributes := eventAttributes(resp.Event)
+	attributes["http.request.method"] = resp.Resp.Request.Method
+	attributes["url.full"] = resp.Resp.Request.URL.String()
+	attributes["http.response.status_code"] = resp.Resp.StatusCode
+	attributes["stripe.forward.duration_ms"] = resp.Duration.Milliseconds()
+
+	severity := otellogs.SeverityInfo
+	if resp.Resp.StatusCode >= 400 {
+		severity = otellogs.SeverityError
+	}
+
+	return otellogs.Record{
+		Time:       now,
+		Severity:   severity,
+		Body:       fmt.Sprintf("[%d] %s %s [%s]", resp.Resp.StatusCode, resp.Resp.Request.Method, resp.Resp.Request.URL, resp.Event.ID),
+		Attributes: attributes,
+		Trace:      resp.Event.Trace,
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/otellogs"
+	"github.com/stripe/stripe-cli/pkg/proxy"
+)
+
+func TestOTelRecords(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	trace := otellogs.NewTraceContext()
+
+	evt := proxy.StripeEvent{
+		ID:         "evt_123",
+		Type:       "charge.succeeded",
+		APIVersion: "2024-06-20",
+		Request:    proxy.StripeRequest{ID: "req_456"},
+		Trace:      trace,
+	}
+
+	record := eventRecord(evt, now)
+	require.Equal(t, otellogs.Record{
+		Time:     now,
+		Severity: otellogs.SeverityInfo,
+		Body:     "Received event charge.succeeded [evt_123]",
+		Attributes: map[string]interface{}{
+			"stripe.event.id":    "evt_123",
+			"stripe.event.type":  "charge.succeeded",
+			"stripe.livemode":    false,
+			"stripe.api_version": "2024-06-20",
+			"stripe.request.id":  "req_456",
+		},
+		Trace: trace,
+	}, record)
+
+	forwardURL, _ := url.Parse("http://localhost:3000/hooks")
+	record = endpointResponseRecord(proxy.EndpointResponse{
+		Event: &evt,
+		Resp: &http.Response{
+			StatusCode: 500,
+			Request:    &http.Request{Method: http.MethodPost, URL: forwardURL},
+		},
+		Duration: 1500 * time.Millisecond,
+	}, now)
+
+	require.Equal(t, otellogs.SeverityError, record.Severity)
+	require.Equal(t, "[500] POST http://localhost:3000/hooks [evt_123]", record.Body)
+	require.Equal(t, trace, record.Trace, "the response is in the trace of the event")
+	require.Equal(t, 500, record.Attributes["http.response.status_code"])
+	require.Equal(t, "http://localhost:3000/hooks", record.Attributes["url.full"])
+	require.Equal(t, int64(1500), record.Attributes["stripe.forward.duration_ms"])
+	require.Equal(t, "req_456", record.Attributes["stripe.request.id"])
+}
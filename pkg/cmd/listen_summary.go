@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stripe/stripe-cli/pkg/git"
+	"github.com/stripe/stripe-cli/pkg/proxy"
+	"github.com/stripe/stripe-cli/pkg/websocket"
+)
+
+// listenSession keeps count of what happened while listening, to summarize
+// the session once it ends
+type listenSession struct {
+	start time.Time
+
+	events    int
+	succeeded int
+	failed    int
+
+	git    git.Context
+	inRepo bool
+}
+
+func newListenSession() *listenSession {
+	session := &listenSession{start: time.Now()}
+	session.git, session.inRepo = git.CurrentContext()
+
+	return session
+}
+
+// record counts an element received from the proxy
+func (s *listenSession) record(el websocket.IElement) {
+	switch el := el.(type) {
+	case websocket.DataElement:
+		switch data := el.Data.(type) {
+		case proxy.StripeEvent:
+			s.events++
+		case proxy.EndpointResponse:
+			if data.Resp.StatusCode < 300 {
+				s.succeeded++
+			} else {
+				s.failed++
+			}
+		}
+	case websocket.ErrorElement:
+		switch el.Error.(type) {
+		case proxy.FailedToPostError, proxy.FailedToReadResponseError:
+			s.failed++
+		}
+	}
+}
+
+// summary describes the session in a sentence
+func (s *listenSession) summary(now time.Time) string {
+	summary := fmt.Sprintf("Received %d event(s) and forwarded %d (%d succeeded, %d failed) in %s",
+		s.events,
+		s.succeeded+s.failed,
+		s.succeeded,
+		s.failed,
+		now.Sub(s.start).Round(time.Second),
+	)
+
+	switch {
+	case s.inRepo && s.git.Branch != "":
+		summary += fmt.Sprintf(" on branch %s (%s)", s.git.Branch, s.git.ShortCommit())
+	case s.inRepo:
+		summary += fmt.Sprintf(" on commit %s", s.git.ShortCommit())
+	}
+
+	return summary + "."
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/git"
+	"github.com/stripe/stripe-cli/pkg/proxy"
+	"github.com/stripe/stripe-cli/pkg/websocket"
+)
+
+func TestListenSessionSummary(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	session := &listenSession{start: start}
+
+	session.record(websocket.DataElement{Data: proxy.StripeEvent{ID: "evt_1"}})
+	session.record(websocket.DataElement{Data: proxy.StripeEvent{ID: "evt_2"}})
+	session.record(websocket.DataElement{Data: proxy.EndpointResponse{Resp: &http.Response{StatusCode: 200}}})
+	session.record(websocket.ErrorElement{Error: proxy.FailedToPostError{}})
+
+	require.Equal(t, "Received 2 event(s) and forwarded 2 (1 succeeded, 1 failed) in 1m30s.", session.summary(start.Add(90*time.Second)))
+
+	session.inRepo = true
+	session.git = git.Context{Branch: "feature/foo", Commit: "0123456789abcdef"}
+	require.Equal(t, "Received 2 event(s) and forwarded 2 (1 succeeded, 1 failed) in 1m30s on branch feature/foo (0123456).", session.summary(start.Add(90*time.Second)))
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -14,6 +15,9 @@ type loginCmd struct {
 	cmd              *cobra.Command
 	interactive      bool
 	dashboardBaseURL string
+
+	ci        bool
+	ciOptions login.CIOptions
 }
 
 func newLoginCmd() *loginCmd {
@@ -23,10 +27,24 @@ func newLoginCmd() *loginCmd {
 		Use:   "login",
 		Args:  validators.NoArgs,
 		Short: "Login to your Stripe account",
-		Long:  `Login to your Stripe account to setup the CLI`,
-		RunE:  lc.runLoginCmd,
+		Long: `Login to your Stripe account to setup the CLI.
+
+In CI jobs, --ci prints a link and pairing code for someone to confirm the login
+with, and waits for them. The keys are kept in the profile for --ttl, then
+removed. With --print-env, the test mode key is only printed as STRIPE_API_KEY
+for the job's environment, and the config file isn't written to.`,
+		Example: `stripe login
+  stripe login --ci --ttl 30m
+  stripe login --ci --print-env >> "$GITHUB_ENV"`,
+		RunE: lc.runLoginCmd,
 	}
 	lc.cmd.Flags().BoolVarP(&lc.interactive, "interactive", "i", false, "Run interactive configuration mode if you cannot open a browser")
+	lc.cmd.Flags().BoolVar(&lc.ci, "ci", false, "Log in from a CI job, with keys that expire")
+	lc.cmd.Flags().BoolVar(&lc.ci, "token-exchange", false, "Alias of --ci")
+	lc.cmd.Flags().MarkHidden("token-exchange") // #nosec G104
+	lc.cmd.Flags().DurationVar(&lc.ciOptions.TTL, "ttl", time.Hour, "With --ci, how long to keep the keys in the profile")
+	lc.cmd.Flags().DurationVar(&lc.ciOptions.Timeout, "timeout", 10*time.Minute, "With --ci, how long to wait for the login to be confirmed")
+	lc.cmd.Flags().BoolVar(&lc.ciOptions.PrintEnv, "print-env", false, "With --ci, print the key as STRIPE_API_KEY instead of writing it to the config file")
 
 	// Hidden configuration flags, useful for dev/debugging
 	lc.cmd.Flags().StringVar(&lc.dashboardBaseURL, "dashboard-base", stripe.DefaultDashboardBaseURL, "Sets the dashboard base URL")
@@ -36,6 +54,10 @@ func newLoginCmd() *loginCmd {
 }
 
 func (lc *loginCmd) runLoginCmd(cmd *cobra.Command, args []string) error {
+	if lc.ci {
+		return login.CILogin(cmd.Context(), lc.dashboardBaseURL, &Config, lc.ciOptions, os.Stdout, os.Stderr)
+	}
+
 	if lc.interactive {
 		return login.InteractiveLogin(cmd.Context(), &Config)
 	}
@@ -0,0 +1,66 @@
+package logs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stripe/stripe-cli/pkg/logtailing"
+	"github.com/stripe/stripe-cli/pkg/otellogs"
+	"github.com/stripe/stripe-cli/pkg/websocket"
+)
+
+// withOTelLogs wraps a visitor to also export every request log as a log
+// record
+func withOTelLogs(visitor *websocket.Visitor, exporter *otellogs.Exporter) *websocket.Visitor {
+	visitData := visitor.VisitData
+	visitor.VisitData = func(de websocket.DataElement) error {
+		if payload, ok := de.Data.(logtailing.EventPayload); ok {
+			exporter.Emit(requestLogRecord(payload))
+		}
+
+		return visitData(de)
+	}
+
+	return visitor
+}
+
+// requestLogRecord returns the record of a request log. Its request ID ties it
+// to the events the request triggered, exported by `stripe listen`.
+func requestLogRecord(payload logtailing.EventPayload) otellogs.Record {
+	attributes := map[string]interface{}{
+		"stripe.request.id":         payload.RequestID,
+		"stripe.livemode":           payload.Livemode,
+		"http.request.method":       payload.Method,
+		"http.response.status_code": payload.Status,
+	}
+	if payload.URL != "" {
+		attributes["url.path"] = payload.URL
+	}
+
+	for key, value := range map[string]string{
+		"stripe.error.type":         payload.Error.Type,
+		"stripe.error.code":         payload.Error.Code,
+		"stripe.error.decline_code": payload.Error.DeclineCode,
+		"stripe.error.param":        payload.Error.Param,
+		"stripe.error.message":      payload.Error.Message,
+	} {
+		if value != "" {
+			attributes[key] = value
+		}
+	}
+
+	severity := otellogs.SeverityInfo
+	switch {
+	case payload.Status >= 500:
+		severity = otellogs.SeverityError
+	case payload.Status >= 400:
+		severity = otellogs.SeverityWarn
+	}
+
+	return otellogs.Record{
+		Time:       time.Unix(int64(payload.CreatedAt), 0),
+		Severity:   severity,
+		Body:       fmt.Sprintf("[%d] %s %s [%s]", payload.Status, payload.Method, payload.URL, payload.RequestID),
+		Attributes: attributes,
+	}
+}
@@ -11,14 +11,18 @@ import (
 
 	"github.com/briandowns/spinner"
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
 	"context"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
 	"github.com/stripe/stripe-cli/pkg/logtailing"
 	logTailing "github.com/stripe/stripe-cli/pkg/logtailing"
+	"github.com/stripe/stripe-cli/pkg/otellogs"
+	"github.com/stripe/stripe-cli/pkg/query"
 	"github.com/stripe/stripe-cli/pkg/validators"
 	"github.com/stripe/stripe-cli/pkg/version"
 	"github.com/stripe/stripe-cli/pkg/websocket"
@@ -34,6 +38,15 @@ type TailCmd struct {
 	format     string
 	LogFilters *logTailing.LogFilters
 	noWSS      bool
+
+	outputFile       string
+	outputFileFormat string
+	maxFileSize      int64
+
+	query query.Value
+	raw   bool
+
+	otelLogsEndpoint string
 }
 
 // NewTailCmd creates and initializes the tail command for the logs package
@@ -52,7 +65,10 @@ Log tailing allows you to filter data similarly to the Stripe Dashboard; filter
 HTTP methods, IP addresses, paths, response status, and more.`,
 		Example: `stripe logs tail
   stripe logs tail --filter-http-methods GET
-  stripe logs tail --filter-status-code-type 4XX`,
+  stripe logs tail --filter-status-code-type 4XX
+  stripe logs tail --output-file requests.har
+  stripe logs tail --otel-logs-endpoint http://localhost:4318
+  stripe logs tail --query 'status >= ` + "`400`" + ` && request_id || null' --raw`,
 		RunE: tailCmd.runTailCmd,
 	}
 
@@ -64,6 +80,22 @@ HTTP methods, IP addresses, paths, response status, and more.`,
 Acceptable values:
 	'JSON' - Output logs in JSON format`,
 	)
+	tailCmd.Cmd.Flags().StringVar(&tailCmd.outputFile, "output-file", "", "Also write request logs to this file")
+	tailCmd.Cmd.Flags().StringVar(
+		&tailCmd.outputFileFormat,
+		"output-file-format",
+		"",
+		`Specifies the format of the --output-file
+(default: guessed from the file extension, NDJSON otherwise)
+Acceptable values:
+	'HAR'    - HTTP Archive, to import in browser dev tools and proxies
+	'CSV'    - Comma-separated values, to import in spreadsheets
+	'NDJSON' - One JSON object per line`,
+	)
+	tailCmd.Cmd.Flags().Int64Var(&tailCmd.maxFileSize, "max-file-size", 0, "Rotate the output file once it reaches this size in MB (default: no rotation)")
+	tailCmd.Cmd.Flags().Var(&tailCmd.query, "query", "JMESPath expression applied to each request log in JSON before printing it, on one line. Logs for which it gives null aren't printed")
+	tailCmd.Cmd.Flags().BoolVar(&tailCmd.raw, "raw", false, "Print JSON compactly, strings without quotes and arrays one element per line, for use in shell scripts")
+	tailCmd.Cmd.Flags().StringVar(&tailCmd.otelLogsEndpoint, "otel-logs-endpoint", "", "Also export request logs as logs to this OpenTelemetry collector (OTLP/HTTP), such as http://localhost:4318")
 
 	// Log filters
 	tailCmd.Cmd.Flags().StringSliceVar(
@@ -154,6 +186,11 @@ func (tailCmd *TailCmd) runTailCmd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	q := tailCmd.query.Query
+	if q == nil && tailCmd.raw {
+		q, _ = query.Compile("@")
+	}
+
 	deviceName, err := tailCmd.cfg.Profile.GetDeviceName()
 	if err != nil {
 		return err
@@ -168,7 +205,7 @@ func (tailCmd *TailCmd) runTailCmd(cmd *cobra.Command, args []string) error {
 
 	logger := log.StandardLogger()
 
-	logtailingVisitor := createVisitor(logger, tailCmd.format)
+	logtailingVisitor := createVisitor(logger, tailCmd.format, q, tailCmd.raw)
 
 	logtailingOutCh := make(chan websocket.IElement)
 
@@ -188,6 +225,52 @@ func (tailCmd *TailCmd) runTailCmd(cmd *cobra.Command, args []string) error {
 		}).Debug("Ctrl+C received, cleaning up...")
 	})
 
+	// --output-file option
+	if tailCmd.outputFile != "" {
+		format := tailCmd.outputFileFormat
+		if format == "" {
+			format = logTailing.ExportFormatForPath(tailCmd.outputFile)
+		}
+
+		exportWriter, err := logTailing.NewExportWriter(afero.NewOsFs(), tailCmd.outputFile, format, tailCmd.maxFileSize*1024*1024)
+		if err != nil {
+			return err
+		}
+
+		// The tailer stops and closes its channel on Ctrl+C, so this completes
+		// the file before exiting
+		defer func() {
+			if err := exportWriter.Close(); err != nil {
+				log.Errorf("Failed to write %s: %v", tailCmd.outputFile, err)
+			}
+		}()
+
+		logtailingVisitor = withExport(logtailingVisitor, exportWriter)
+	}
+
+	// --otel-logs-endpoint option
+	if tailCmd.otelLogsEndpoint != "" {
+		exporter, err := otellogs.NewExporter(tailCmd.otelLogsEndpoint)
+		if err != nil {
+			return err
+		}
+
+		exportCtx, stopExport := context.WithCancel(ctx)
+		exported := make(chan struct{})
+		go func() {
+			exporter.Run(exportCtx)
+			close(exported)
+		}()
+
+		// exports the last records before exiting
+		defer func() {
+			stopExport()
+			<-exported
+		}()
+
+		logtailingVisitor = withOTelLogs(logtailingVisitor, exporter)
+	}
+
 	go tailer.Run(ctx)
 
 	for el := range logtailingOutCh {
@@ -201,6 +284,14 @@ func (tailCmd *TailCmd) runTailCmd(cmd *cobra.Command, args []string) error {
 }
 
 func (tailCmd *TailCmd) validateArgs() error {
+	if tailCmd.maxFileSize < 0 {
+		return clierrors.New(clierrors.InvalidFlagValue, "max-file-size must be a positive number of MB")
+	}
+
+	if tailCmd.outputFileFormat != "" && tailCmd.outputFile == "" {
+		return clierrors.New(clierrors.RequiredFlagMissing, "output-file-format requires --output-file")
+	}
+
 	err := validators.CallNonEmptyArray(validators.Account, tailCmd.LogFilters.FilterAccount)
 	if err != nil {
 		return err
@@ -245,7 +336,7 @@ func (tailCmd *TailCmd) convertArgs() error {
 	return nil
 }
 
-func createVisitor(logger *log.Logger, format string) *websocket.Visitor {
+func createVisitor(logger *log.Logger, format string, q *query.Query, raw bool) *websocket.Visitor {
 	var s *spinner.Spinner
 
 	return &websocket.Visitor{
@@ -277,6 +368,10 @@ func createVisitor(logger *log.Logger, format string) *websocket.Visitor {
 				return fmt.Errorf("VisitData received unexpected type for DataElement, got %T expected %T", de, logtailing.EventPayload{})
 			}
 
+			if q != nil {
+				return printQueried(q, raw, de.Marshaled)
+			}
+
 			if strings.ToUpper(format) == outputFormatJSON {
 				fmt.Println(ansi.ColorizeJSON(de.Marshaled, false, os.Stdout))
 				return nil
@@ -317,6 +412,50 @@ func createVisitor(logger *log.Logger, format string) *websocket.Visitor {
 	}
 }
 
+// printQueried prints the result of the query on a request log, unless it's
+// null so that queries can also filter logs
+func printQueried(q *query.Query, raw bool, marshaled string) error {
+	var output string
+
+	if raw {
+		result, err := q.Apply([]byte(marshaled), true)
+		if err != nil {
+			return err
+		}
+		output = result
+	} else {
+		result, err := q.Search([]byte(marshaled))
+		if err != nil {
+			return err
+		}
+		if string(result) != "null" {
+			output = ansi.ColorizeJSON(string(result), false, os.Stdout)
+		}
+	}
+
+	if output != "" {
+		fmt.Println(output)
+	}
+
+	return nil
+}
+
+// withExport wraps a visitor to also write every request log to the export file
+func withExport(visitor *websocket.Visitor, exportWriter *logTailing.ExportWriter) *websocket.Visitor {
+	visitData := visitor.VisitData
+	visitor.VisitData = func(de websocket.DataElement) error {
+		if payload, ok := de.Data.(logtailing.EventPayload); ok {
+			if err := exportWriter.Write(payload); err != nil {
+				return err
+			}
+		}
+
+		return visitData(de)
+	}
+
+	return visitor
+}
+
 func urlForRequestID(payload *logtailing.EventPayload) string {
 	maybeTest := ""
 	if !payload.Livemode {
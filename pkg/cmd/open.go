@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/open"
 	"github.com/stripe/stripe-cli/pkg/version"
 )
@@ -177,7 +178,7 @@ func (oc *openCmd) runOpenCmd(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	} else {
-		return fmt.Errorf("Unsupported open command, given: %s", args[0])
+		return clierrors.Errorf(clierrors.InvalidArgs, "Unsupported open command, given: %s", args[0])
 	}
 
 	return nil
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/guardrails"
+	"github.com/stripe/stripe-cli/pkg/passkey"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+// confirmPasskey is how operations are confirmed with the passkey. It's a
+// variable so tests can confirm them without a browser.
+var confirmPasskey = passkey.Confirm
+
+type passkeyCmd struct {
+	cmd *cobra.Command
+}
+
+func newPasskeyCmd() *passkeyCmd {
+	pc := &passkeyCmd{}
+
+	pc.cmd = &cobra.Command{
+		Use:   "passkey",
+		Args:  validators.NoArgs,
+		Short: "Require a passkey to confirm sensitive operations",
+		Long: `Require touching a security key or using a platform passkey, in the browser,
+before the CLI makes live mode requests that change data or shows API keys and
+secrets. This is configured per profile.
+
+Live mode writes are confirmed once per command, even when --live is passed.`,
+		Example: `stripe passkey register
+  stripe passkey register --confirm live_writes
+  stripe passkey remove`,
+	}
+
+	pc.cmd.AddCommand(newPasskeyRegisterCmd().cmd)
+	pc.cmd.AddCommand(newPasskeyRemoveCmd().cmd)
+
+	return pc
+}
+
+type passkeyRegisterCmd struct {
+	cmd *cobra.Command
+
+	confirm []string
+}
+
+func newPasskeyRegisterCmd() *passkeyRegisterCmd {
+	rc := &passkeyRegisterCmd{}
+
+	rc.cmd = &cobra.Command{
+		Use:   "register",
+		Args:  validators.NoArgs,
+		Short: "Register a passkey for the profile",
+		Long: `Register a passkey for the profile, and choose the operations it confirms:
+
+  live_writes  live mode requests that change data
+  show_keys    showing API keys and secrets, such as with config --list
+
+Registering a new passkey replaces the previous one, which must be used to
+confirm the change.`,
+		RunE: rc.runPasskeyRegisterCmd,
+	}
+
+	rc.cmd.Flags().StringSliceVar(&rc.confirm, "confirm", []string{config.PasskeyLiveWrites, config.PasskeyShowKeys}, "Operations to confirm with the passkey (live_writes, show_keys)")
+
+	return rc
+}
+
+func (rc *passkeyRegisterCmd) runPasskeyRegisterCmd(cmd *cobra.Command, args []string) error {
+	for _, operation := range rc.confirm {
+		switch operation {
+		case config.PasskeyLiveWrites, config.PasskeyShowKeys:
+		default:
+			return clierrors.Errorf(clierrors.InvalidArgs, "unknown operation '%s', expected live_writes or show_keys", operation)
+		}
+	}
+
+	if err := confirmPasskeyChange(cmd.Context(), "Confirm replacing the passkey of the Stripe CLI."); err != nil {
+		return err
+	}
+
+	credential, err := passkey.Register(cmd.Context(), fmt.Sprintf("Stripe CLI (%s)", Config.Profile.ProfileName), os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	if err := Config.Profile.WriteConfigField(config.PasskeyIDName, credential.ID); err != nil {
+		return err
+	}
+
+	if err := Config.Profile.WriteConfigField(config.PasskeyPublicKeyName, credential.PublicKey); err != nil {
+		return err
+	}
+
+	if err := Config.Profile.WriteConfigField(config.PasskeyConfirmName, strings.Join(rc.confirm, ",")); err != nil {
+		return err
+	}
+
+	fmt.Printf("Registered a passkey for the %s profile, confirming %s.\n", Config.Profile.ProfileName, strings.Join(rc.confirm, ", "))
+
+	return nil
+}
+
+type passkeyRemoveCmd struct {
+	cmd *cobra.Command
+}
+
+func newPasskeyRemoveCmd() *passkeyRemoveCmd {
+	rc := &passkeyRemoveCmd{}
+
+	rc.cmd = &cobra.Command{
+		Use:   "remove",
+		Args:  validators.NoArgs,
+		Short: "Stop requiring a passkey for the profile",
+		Long:  `Stop requiring a passkey for the profile. The passkey must be used to confirm it.`,
+		RunE:  rc.runPasskeyRemoveCmd,
+	}
+
+	return rc
+}
+
+func (rc *passkeyRemoveCmd) runPasskeyRemoveCmd(cmd *cobra.Command, args []string) error {
+	if id, _ := Config.Profile.GetPasskey(); id == "" {
+		return clierrors.Errorf(clierrors.PasskeyNotRegistered, "no passkey is registered for the %s profile", Config.Profile.ProfileName)
+	}
+
+	if err := confirmPasskeyChange(cmd.Context(), "Confirm removing the passkey of the Stripe CLI."); err != nil {
+		return err
+	}
+
+	for _, field := range []string{config.PasskeyConfirmName, config.PasskeyPublicKeyName, config.PasskeyIDName} {
+		if err := Config.Profile.DeleteConfigField(field); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Removed the passkey of the %s profile.\n", Config.Profile.ProfileName)
+
+	return nil
+}
+
+// isPasskeyField returns whether changing the config field changes which
+// passkey is required
+func isPasskeyField(field string) bool {
+	switch field {
+	case config.PasskeyIDName, config.PasskeyPublicKeyName, config.PasskeyConfirmName:
+		return true
+	default:
+		return false
+	}
+}
+
+// confirmPasskeyChange asks for the registered passkey, if any, before it's
+// replaced or removed, so it can't be turned off without it
+func confirmPasskeyChange(ctx context.Context, reason string) error {
+	id, publicKey := Config.Profile.GetPasskey()
+	if id == "" {
+		return nil
+	}
+
+	return confirmPasskey(ctx, passkey.Credential{ID: id, PublicKey: publicKey}, reason, os.Stderr)
+}
+
+// confirmWithPasskey asks for the passkey when the profile requires it for the
+// operation, such as config.PasskeyShowKeys
+func confirmWithPasskey(ctx context.Context, operation, reason string) error {
+	if !Config.Profile.RequiresPasskey(operation) {
+		return nil
+	}
+
+	id, publicKey := Config.Profile.GetPasskey()
+	if id == "" {
+		return clierrors.New(clierrors.PasskeyNotRegistered, "the profile requires a passkey but none is registered, run `stripe passkey register`")
+	}
+
+	return confirmPasskey(ctx, passkey.Credential{ID: id, PublicKey: publicKey}, reason, os.Stderr)
+}
+
+// confirmWithAnyPasskey confirms an operation with the passkey when the
+// profile requires it for any operation, for the operations that can turn the
+// confirmations off, such as editing the config file
+func confirmWithAnyPasskey(ctx context.Context, reason string) error {
+	for _, operation := range []string{config.PasskeyLiveWrites, config.PasskeyShowKeys} {
+		if Config.Profile.RequiresPasskey(operation) {
+			return confirmWithPasskey(ctx, operation, reason)
+		}
+	}
+
+	return nil
+}
+
+// configurePasskey makes live mode writes wait for the passkey when the
+// profile requires it
+func configurePasskey(cmd *cobra.Command) {
+	if !Config.Profile.RequiresPasskey(config.PasskeyLiveWrites) {
+		return
+	}
+
+	guardrails.Default.Verify = func(method, requestPath string) error {
+		reason := fmt.Sprintf("Confirm the live mode %s request to %s, which changes the data of your live account.", method, requestPath)
+		return confirmWithPasskey(cmd.Context(), config.PasskeyLiveWrites, reason)
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/passkey"
+)
+
+func TestConfirmWithAnyPasskey(t *testing.T) {
+	var confirmed []string
+	defer func(confirm func(context.Context, passkey.Credential, string, io.Writer) error) {
+		confirmPasskey = confirm
+	}(confirmPasskey)
+	confirmPasskey = func(ctx context.Context, credential passkey.Credential, reason string, out io.Writer) error {
+		confirmed = append(confirmed, credential.ID)
+		return nil
+	}
+
+	for _, tt := range []struct {
+		passkeyConfirm string
+		confirmed      []string
+	}{
+		{"", nil},
+		{"live_writes", []string{"cred_123"}},
+		{"show_keys", []string{"cred_123"}},
+		{"live_writes,show_keys", []string{"cred_123"}},
+	} {
+		confirmed = nil
+
+		profilesFile := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(profilesFile, []byte(`[default]
+  passkey_id = "cred_123"
+  passkey_public_key = "key"
+  passkey_confirm = "`+tt.passkeyConfirm+`"
+`), 0600))
+		viper.SetConfigFile(profilesFile)
+
+		require.NoError(t, confirmWithAnyPasskey(context.Background(), "Confirm editing the config."))
+		require.Equal(t, tt.confirmed, confirmed, tt.passkeyConfirm)
+	}
+}
@@ -24,6 +24,8 @@ func newPluginCmd() *pluginCmd {
 
 	pc.cmd.AddCommand(plugin.NewInstallCmd(&Config).Cmd)
 	pc.cmd.AddCommand(plugin.NewUpgradeCmd(&Config).Cmd)
+	pc.cmd.AddCommand(plugin.NewOutdatedCmd(&Config).Cmd)
+	pc.cmd.AddCommand(plugin.NewSearchCmd(&Config).Cmd)
 	pc.cmd.AddCommand(plugin.NewUninstallCmd(&Config).Cmd)
 
 	return pc
@@ -14,6 +14,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
 	"github.com/stripe/stripe-cli/pkg/plugins"
 	"github.com/stripe/stripe-cli/pkg/stripe"
@@ -50,6 +51,9 @@ func NewInstallCmd(config *config.Config) *InstallCmd {
 	ic.Cmd.Flags().StringVar(&ic.archivePath, "archive-path", "", "Install a plugin by a local archive path")
 	ic.Cmd.Flags().StringVar(&ic.localPluginDir, "local", "", "Install a development version plugin from a local development folder")
 	ic.Cmd.Flags().Bool("archive", false, "Install a plugin by archive data from stdout")
+	ic.Cmd.Flags().BoolVar(&plugins.AllowEmulation, "allow-emulation", false, "Install the amd64 build of the plugin on arm64 macOS and Windows if there is no arm64 build")
+	ic.Cmd.Flags().DurationVar(&plugins.DownloadTimeout, "timeout", plugins.DownloadTimeout, "Give up on each download attempt after this long")
+	ic.Cmd.Flags().IntVar(&plugins.DownloadRetries, "retries", plugins.DownloadRetries, "How many times to retry a download that failed because of the network or the server")
 
 	return ic
 }
@@ -68,15 +72,15 @@ func parseInstallArg(arg string) (string, string) {
 	return plugin, version
 }
 
-func (ic *InstallCmd) installPluginByName(cmd *cobra.Command, arg string) error {
+func (ic *InstallCmd) installPluginByName(ctx context.Context, arg string) error {
 	pluginName, version := parseInstallArg(arg)
 
 	if pluginName == "-" && hasPipedData() {
 		// -: reads data from stdout
-		return plugins.ExtractStdoutArchive(cmd.Context(), ic.cfg)
+		return plugins.ExtractStdoutArchive(ctx, ic.cfg)
 	}
 
-	plugin, err := plugins.LookUpPlugin(cmd.Context(), ic.cfg, ic.fs, pluginName)
+	plugin, err := plugins.LookUpPlugin(ctx, ic.cfg, ic.fs, pluginName)
 
 	if err != nil {
 		return err
@@ -86,18 +90,12 @@ func (ic *InstallCmd) installPluginByName(cmd *cobra.Command, arg string) error
 		version = plugin.LookUpLatestVersion()
 	}
 
-	ctx := withSIGTERMCancel(cmd.Context(), func() {
-		log.WithFields(log.Fields{
-			"prefix": "cmd.installCmd.runInstallCmd",
-		}).Debug("Ctrl+C received, cleaning up...")
-	})
-
 	err = plugin.Install(ctx, ic.cfg, ic.fs, version, stripe.DefaultAPIBaseURL)
 
 	return err
 }
 
-func (ic *InstallCmd) installPluginByArchive(cmd *cobra.Command) error {
+func (ic *InstallCmd) installPluginByArchive(ctx context.Context, cmd *cobra.Command) error {
 	switch {
 	case ic.archiveURL == "" && ic.archivePath == "":
 		// no arhive URL or path was provided. try to read from piped stdin
@@ -108,17 +106,17 @@ func (ic *InstallCmd) installPluginByArchive(cmd *cobra.Command) error {
 
 		if readFromArchive {
 			if !hasPipedData() {
-				return fmt.Errorf("Please pipe into stdout: curl <url> | stripe plugin install --archive")
+				return clierrors.New(clierrors.RequiredFlagMissing, "Please pipe into stdout: curl <url> | stripe plugin install --archive")
 			}
 
-			return plugins.ExtractStdoutArchive(cmd.Context(), ic.cfg)
+			return plugins.ExtractStdoutArchive(ctx, ic.cfg)
 		}
 
-		return fmt.Errorf("To install a plugin from archive, please provide archive url/path or pipe archive data into stdout")
+		return clierrors.New(clierrors.RequiredFlagMissing, "To install a plugin from archive, please provide archive url/path or pipe archive data into stdout")
 	case ic.archiveURL != "":
-		return plugins.FetchAndExtractRemoteArchive(cmd.Context(), ic.cfg, ic.archiveURL)
+		return plugins.FetchAndExtractRemoteArchive(ctx, ic.cfg, ic.archiveURL)
 	case ic.archivePath != "":
-		return plugins.ExtractLocalArchive(cmd.Context(), ic.cfg, ic.archivePath)
+		return plugins.ExtractLocalArchive(ctx, ic.cfg, ic.archivePath)
 	}
 
 	return nil
@@ -141,6 +139,16 @@ func (ic *InstallCmd) installPluginFromLocalDir() error {
 func (ic *InstallCmd) runInstallCmd(cmd *cobra.Command, args []string) error {
 	var err error
 
+	if plugins.DownloadTimeout <= 0 || plugins.DownloadRetries < 0 {
+		return clierrors.New(clierrors.InvalidFlagValue, "--timeout must be a positive duration and --retries can't be negative")
+	}
+
+	ctx := withSIGTERMCancel(cmd.Context(), func() {
+		log.WithFields(log.Fields{
+			"prefix": "cmd.installCmd.runInstallCmd",
+		}).Debug("Ctrl+C received, cleaning up...")
+	})
+
 	if len(args) == 0 {
 		if ic.localPluginDir != "" {
 			err = ic.installPluginFromLocalDir()
@@ -148,19 +156,19 @@ func (ic *InstallCmd) runInstallCmd(cmd *cobra.Command, args []string) error {
 				return err
 			}
 		} else {
-			err = ic.installPluginByArchive(cmd)
+			err = ic.installPluginByArchive(ctx, cmd)
 			if err != nil {
 				return err
 			}
 		}
 	} else {
 		// Refresh the plugin before proceeding
-		err = plugins.RefreshPluginManifest(cmd.Context(), ic.cfg, ic.fs, stripe.DefaultAPIBaseURL)
+		err = plugins.RefreshPluginManifest(ctx, ic.cfg, ic.fs, stripe.DefaultAPIBaseURL)
 		if err != nil {
 			return err
 		}
 
-		err = ic.installPluginByName(cmd, args[0])
+		err = ic.installPluginByName(ctx, args[0])
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,92 @@
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/plugins"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+// OutdatedCmd is the struct used for configuring the plugin outdated command
+type OutdatedCmd struct {
+	cfg *config.Config
+	Cmd *cobra.Command
+	fs  afero.Fs
+}
+
+// NewOutdatedCmd creates a new command for listing the plugins that can be
+// upgraded
+func NewOutdatedCmd(config *config.Config) *OutdatedCmd {
+	oc := &OutdatedCmd{}
+	oc.fs = afero.NewOsFs()
+	oc.cfg = config
+
+	oc.Cmd = &cobra.Command{
+		Use:   "outdated",
+		Args:  validators.NoArgs,
+		Short: "List the installed plugins that can be upgraded",
+		Long:  "List the installed Stripe CLI plugins that have a newer version available, with links to its release notes.",
+		RunE:  oc.runOutdatedCmd,
+	}
+
+	return oc
+}
+
+func (oc *OutdatedCmd) runOutdatedCmd(cmd *cobra.Command, args []string) error {
+	// Refresh the plugin info before proceeding
+	plugins.RefreshPluginManifest(cmd.Context(), oc.cfg, oc.fs, stripe.DefaultAPIBaseURL)
+
+	outdated, err := plugins.OutdatedPlugins(cmd.Context(), oc.cfg, oc.fs, stripe.DefaultAPIBaseURL)
+	if err != nil {
+		return err
+	}
+
+	printOutdated(os.Stdout, outdated)
+
+	return nil
+}
+
+func printOutdated(out io.Writer, outdated []plugins.PluginUpgrade) {
+	color := ansi.Color(out)
+
+	if len(outdated) == 0 {
+		fmt.Fprintln(out, color.Green("✔ all installed plugins are up to date."))
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PLUGIN\tINSTALLED\tLATEST\tRELEASE NOTES")
+
+	for _, upgrade := range outdated {
+		installed := upgrade.InstalledVersion
+		if installed == "" {
+			installed = "-"
+		}
+
+		notes := upgrade.ReleaseNotesURL
+		if notes == "" {
+			notes = "-"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", upgrade.Plugin, installed, upgrade.Version, notes)
+	}
+
+	w.Flush()
+
+	for _, upgrade := range outdated {
+		if upgrade.Deprecated != "" {
+			fmt.Fprintf(out, "%s %s is deprecated: %s\n", color.Red("!"), upgrade.Plugin, upgrade.Deprecated)
+		}
+	}
+
+	fmt.Fprintln(out, "\nRun `stripe plugin upgrade <plugin>` to upgrade a plugin.")
+}
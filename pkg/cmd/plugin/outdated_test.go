@@ -0,0 +1,29 @@
+package plugin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/plugins"
+)
+
+func TestPrintOutdated(t *testing.T) {
+	out := &bytes.Buffer{}
+	printOutdated(out, nil)
+	require.Equal(t, "✔ all installed plugins are up to date.\n", out.String())
+
+	out.Reset()
+	printOutdated(out, []plugins.PluginUpgrade{
+		{Plugin: "apps", InstalledVersion: "1.0.1", Version: "1.2.0", ReleaseNotesURL: "https://example.com/apps/1.2.0/RELEASE_NOTES.md"},
+		{Plugin: "projects", Version: "0.3.0", Deprecated: "use apps instead"},
+	})
+	require.Equal(t, `PLUGIN    INSTALLED  LATEST  RELEASE NOTES
+apps      1.0.1      1.2.0   https://example.com/apps/1.2.0/RELEASE_NOTES.md
+projects  -          0.3.0   -
+! projects is deprecated: use apps instead
+
+Run `+"`stripe plugin upgrade <plugin>`"+` to upgrade a plugin.
+`, out.String())
+}
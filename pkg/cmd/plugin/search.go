@@ -0,0 +1,137 @@
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/plugins"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+)
+
+// SearchCmd is the struct used for configuring the plugin search command
+type SearchCmd struct {
+	cfg *config.Config
+	Cmd *cobra.Command
+	fs  afero.Fs
+
+	metadataURL string
+}
+
+// NewSearchCmd creates a new command for searching the available plugins
+func NewSearchCmd(config *config.Config) *SearchCmd {
+	sc := &SearchCmd{}
+	sc.fs = afero.NewOsFs()
+	sc.cfg = config
+
+	sc.Cmd = &cobra.Command{
+		Use:   "search [keywords]",
+		Short: "Search the Stripe CLI plugins available to install",
+		Long: `Search the Stripe CLI plugins available to install by keywords, matched
+against their name, description, keywords and maintainers. Without keywords,
+all the plugins are listed.
+
+Maintainers and download counts come from the metadata published along with the
+plugin manifest, or from --metadata-url, when it's available.`,
+		Example: `stripe plugin search tax
+  stripe plugin search
+  stripe plugin search apps --metadata-url https://example.com/plugins/metadata.json`,
+		RunE: sc.runSearchCmd,
+	}
+
+	sc.Cmd.Flags().StringVar(&sc.metadataURL, "metadata-url", "", "Read the metadata of the plugins from this URL")
+
+	return sc
+}
+
+func (sc *SearchCmd) runSearchCmd(cmd *cobra.Command, args []string) error {
+	// Refresh the plugin info before proceeding
+	plugins.RefreshPluginManifest(cmd.Context(), sc.cfg, sc.fs, stripe.DefaultAPIBaseURL)
+
+	query := strings.Join(args, " ")
+
+	results, err := plugins.SearchPlugins(cmd.Context(), sc.cfg, sc.fs, stripe.DefaultAPIBaseURL, query, sc.metadataURL)
+	if err != nil {
+		return err
+	}
+
+	printSearchResults(os.Stdout, query, results)
+
+	return nil
+}
+
+func printSearchResults(out io.Writer, query string, results []plugins.SearchResult) {
+	color := ansi.Color(out)
+
+	if len(results) == 0 {
+		fmt.Fprintf(out, "No plugins match %q.\n", query)
+		return
+	}
+
+	for i, result := range results {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+
+		header := color.Bold(result.Plugin).String()
+		if result.Version != "" {
+			header += " v" + result.Version
+		}
+		if result.InstalledVersion != "" {
+			header += color.Green(fmt.Sprintf(" (v%s installed)", result.InstalledVersion)).String()
+		}
+		fmt.Fprintln(out, header)
+
+		if result.Description != "" {
+			fmt.Fprintf(out, "  %s\n", result.Description)
+		}
+
+		if result.Metadata != nil {
+			var details []string
+			if len(result.Metadata.Maintainers) > 0 {
+				details = append(details, "maintained by "+strings.Join(result.Metadata.Maintainers, ", "))
+			}
+			details = append(details, formatDownloads(result.Metadata.Downloads))
+			fmt.Fprintf(out, "  %s\n", strings.Join(details, " · "))
+
+			if result.Metadata.Homepage != "" {
+				fmt.Fprintf(out, "  %s\n", result.Metadata.Homepage)
+			}
+		}
+
+		if result.Deprecated != "" {
+			fmt.Fprintf(out, "  %s deprecated: %s\n", color.Red("!"), result.Deprecated)
+		}
+
+		if result.InstalledVersion == "" {
+			fmt.Fprintf(out, "  Install: %s\n", result.InstallCommand())
+		}
+	}
+}
+
+// formatDownloads returns a download count with thousands separators
+func formatDownloads(downloads int64) string {
+	digits := strconv.FormatInt(downloads, 10)
+
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+
+	noun := "downloads"
+	if downloads == 1 {
+		noun = "download"
+	}
+
+	return fmt.Sprintf("%s %s", b.String(), noun)
+}
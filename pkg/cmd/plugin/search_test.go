@@ -0,0 +1,44 @@
+package plugin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/plugins"
+)
+
+func TestPrintSearchResults(t *testing.T) {
+	out := &bytes.Buffer{}
+	printSearchResults(out, "tax", nil)
+	require.Equal(t, "No plugins match \"tax\".\n", out.String())
+
+	out.Reset()
+	printSearchResults(out, "tax", []plugins.SearchResult{
+		{
+			Plugin:      "taxes",
+			Description: "Manage tax settings",
+			Version:     "1.2.0",
+			Metadata:    &plugins.PluginMetadata{Maintainers: []string{"Stripe", "Acme"}, Downloads: 1234567, Homepage: "https://example.com/taxes"},
+		},
+		{Plugin: "reports", Version: "0.3.0", InstalledVersion: "0.3.0", Deprecated: "use sigma instead"},
+	})
+	require.Equal(t, `taxes v1.2.0
+  Manage tax settings
+  maintained by Stripe, Acme · 1,234,567 downloads
+  https://example.com/taxes
+  Install: stripe plugin install taxes
+
+reports v0.3.0 (v0.3.0 installed)
+  ! deprecated: use sigma instead
+`, out.String())
+}
+
+func TestFormatDownloads(t *testing.T) {
+	require.Equal(t, "0 downloads", formatDownloads(0))
+	require.Equal(t, "1 download", formatDownloads(1))
+	require.Equal(t, "999 downloads", formatDownloads(999))
+	require.Equal(t, "1,000 downloads", formatDownloads(1000))
+	require.Equal(t, "12,345 downloads", formatDownloads(12345))
+}
@@ -1,7 +1,6 @@
 package plugin
 
 import (
-	"errors"
 	"fmt"
 	"os"
 
@@ -10,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
 	"github.com/stripe/stripe-cli/pkg/plugins"
 	"github.com/stripe/stripe-cli/pkg/validators"
@@ -49,7 +49,7 @@ func (uc *UninstallCmd) runUninstallCmd(cmd *cobra.Command, args []string) error
 	plugin, err := plugins.LookUpPlugin(cmd.Context(), uc.cfg, uc.fs, args[0])
 
 	if err != nil {
-		return errors.New("this plugin doesn't seem to exist")
+		return clierrors.New(clierrors.PluginNotFound, "this plugin doesn't seem to exist")
 	}
 
 	err = plugin.Uninstall(ctx, uc.cfg, uc.fs)
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
 	"github.com/stripe/stripe-cli/pkg/plugins"
 	"github.com/stripe/stripe-cli/pkg/stripe"
@@ -36,10 +37,18 @@ func NewUpgradeCmd(config *config.Config) *UpgradeCmd {
 		RunE:  uc.runUpgradeCmd,
 	}
 
+	uc.Cmd.Flags().BoolVar(&plugins.AllowEmulation, "allow-emulation", false, "Upgrade to the amd64 build of the plugin on arm64 macOS and Windows if there is no arm64 build")
+	uc.Cmd.Flags().DurationVar(&plugins.DownloadTimeout, "timeout", plugins.DownloadTimeout, "Give up on each download attempt after this long")
+	uc.Cmd.Flags().IntVar(&plugins.DownloadRetries, "retries", plugins.DownloadRetries, "How many times to retry a download that failed because of the network or the server")
+
 	return uc
 }
 
 func (uc *UpgradeCmd) runUpgradeCmd(cmd *cobra.Command, args []string) error {
+	if plugins.DownloadTimeout <= 0 || plugins.DownloadRetries < 0 {
+		return clierrors.New(clierrors.InvalidFlagValue, "--timeout must be a positive duration and --retries can't be negative")
+	}
+
 	ctx := withSIGTERMCancel(cmd.Context(), func() {
 		log.WithFields(log.Fields{
 			"prefix": "cmd.upgradeCmd.runUpgradeCmd",
@@ -47,9 +56,9 @@ func (uc *UpgradeCmd) runUpgradeCmd(cmd *cobra.Command, args []string) error {
 	})
 
 	// Refresh the plugin info before proceeding
-	plugins.RefreshPluginManifest(cmd.Context(), uc.cfg, uc.fs, stripe.DefaultAPIBaseURL)
+	plugins.RefreshPluginManifest(ctx, uc.cfg, uc.fs, stripe.DefaultAPIBaseURL)
 
-	plugin, err := plugins.LookUpPlugin(cmd.Context(), uc.cfg, uc.fs, args[0])
+	plugin, err := plugins.LookUpPlugin(ctx, uc.cfg, uc.fs, args[0])
 
 	if err != nil {
 		return err
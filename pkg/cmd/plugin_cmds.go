@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -9,7 +10,9 @@ import (
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/fixtures"
 	"github.com/stripe/stripe-cli/pkg/plugins"
 	"github.com/stripe/stripe-cli/pkg/validators"
 )
@@ -73,7 +76,12 @@ func (ptc *pluginTemplateCmd) runPluginCmd(cmd *cobra.Command, args []string) er
 
 	if err != nil {
 		if err == validators.ErrAPIKeyNotConfigured {
-			return errors.New("Install failed due to API key not configured. Please run `stripe login` or specify the `--api-key`")
+			return clierrors.New(clierrors.APIKeyNotConfigured, "Install failed due to API key not configured. Please run `stripe login` or specify the `--api-key`")
+		}
+
+		var incompatibleErr plugins.IncompatiblePluginError
+		if errors.As(err, &incompatibleErr) {
+			return err
 		}
 
 		log.WithFields(log.Fields{
@@ -83,3 +91,36 @@ func (ptc *pluginTemplateCmd) runPluginCmd(cmd *cobra.Command, args []string) er
 
 	return nil
 }
+
+// registerPluginFixtures makes the triggers and fixture step types declared by
+// a plugin's manifest entry available to `stripe trigger` and `stripe fixtures`
+func registerPluginFixtures(config *config.Config, plugin *plugins.Plugin) {
+	logger := log.WithFields(log.Fields{
+		"prefix": "cmd.pluginCmd.registerPluginFixtures",
+	})
+
+	fs := afero.NewOsFs()
+
+	for _, event := range plugin.Triggers {
+		err := fixtures.RegisterTrigger(event, func(ctx context.Context, event string) ([]byte, error) {
+			return plugin.GetTriggerFixture(ctx, config, fs, event)
+		})
+		if err != nil {
+			logger.Debugf("Skipping trigger from plugin '%s': %s", plugin.Shortname, err)
+		}
+	}
+
+	for _, method := range plugin.StepTypes {
+		err := fixtures.RegisterStepType(method, func(ctx context.Context, step fixtures.Step) ([]byte, error) {
+			return plugin.RunFixtureStep(ctx, config, fs, plugins.FixtureStep{
+				Name:   step.Name,
+				Method: step.Method,
+				Path:   step.Path,
+				Params: step.Params,
+			})
+		})
+		if err != nil {
+			logger.Debugf("Skipping step type from plugin '%s': %s", plugin.Shortname, err)
+		}
+	}
+}
@@ -18,6 +18,7 @@ func newPostCmd() *postCmd {
 
 	gc.reqs.Method = http.MethodPost
 	gc.reqs.Profile = &Config.Profile
+	gc.reqs.DefaultMetadata = true
 	gc.reqs.Cmd = &cobra.Command{
 		Use:   "post <path>",
 		Args:  validators.ExactArgs(1),
@@ -26,6 +27,10 @@ func newPostCmd() *postCmd {
 
 The post command supports API features like idempotency keys and expand flags.
 
+The default metadata of your profile, if any, is added to the requests creating
+or updating an object, but not to the ones acting on it, such as
+/v1/invoices/{id}/pay. Pass --no-default-metadata to leave it out.
+
 For a full list of supported paths, see the API reference:
 https://stripe.com/docs/api
 `,
@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/storage"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+// lockOwnerEnv names who takes the profile locks, such as the ID of a CI job
+const lockOwnerEnv = "STRIPE_CLI_LOCK_OWNER"
+
+// profileLockAttempt is how long to try taking a profile lock that looked
+// free, without --wait
+const profileLockAttempt = 5 * time.Second
+
+type profileCmd struct {
+	cmd *cobra.Command
+}
+
+func newProfileCmd() *profileCmd {
+	pc := &profileCmd{}
+
+	pc.cmd = &cobra.Command{
+		Use:   "profile",
+		Args:  validators.NoArgs,
+		Short: "Lock profiles shared between sessions and bind them to directories",
+		Long: `Bind directories to profiles, so that the commands run in a project use the
+profile of its account without passing --project-name.
+
+Lock the profile so that sessions sharing the machine, or the state backend set
+in STRIPE_CLI_STATE_BACKEND, don't use its account at the same time. While a
+profile is locked, stripe fixtures and stripe trigger fail for anyone but the
+owner of the lock.
+
+The owner is the user and host running the CLI, or STRIPE_CLI_LOCK_OWNER when
+it's set, which should be set to the ID of the job when the machine runs
+several pipelines as the same user.`,
+		Example: `stripe --project-name acme profile bind .
+  stripe profile lock --wait 10m
+  stripe --project-name ci profile lock --ttl 30m
+  stripe profile unlock`,
+	}
+
+	pc.cmd.AddCommand(newProfileBindCmd().cmd)
+	pc.cmd.AddCommand(newProfileLockCmd().cmd)
+	pc.cmd.AddCommand(newProfileUnbindCmd().cmd)
+	pc.cmd.AddCommand(newProfileUnlockCmd().cmd)
+
+	return pc
+}
+
+type profileLockCmd struct {
+	cmd *cobra.Command
+
+	wait  time.Duration
+	ttl   time.Duration
+	owner string
+}
+
+func newProfileLockCmd() *profileLockCmd {
+	lc := &profileLockCmd{}
+
+	lc.cmd = &cobra.Command{
+		Use:   "lock",
+		Args:  validators.NoArgs,
+		Short: "Lock the profile until it's unlocked",
+		Long: `Lock the profile until it's unlocked with stripe profile unlock, or until the
+lock expires. If the profile is locked by someone else, fail right away, or wait
+for it to be unlocked with --wait. Locking a profile you already hold extends
+the lock.`,
+		RunE: lc.runProfileLockCmd,
+	}
+
+	lc.cmd.Flags().DurationVar(&lc.wait, "wait", 0, "How long to wait for the profile to be unlocked, such as 10m (default: fail right away)")
+	lc.cmd.Flags().DurationVar(&lc.ttl, "ttl", time.Hour, "How long until the lock expires, in case it's never unlocked")
+	lc.cmd.Flags().StringVar(&lc.owner, "owner", "", "Who holds the lock (default: $STRIPE_CLI_LOCK_OWNER, or the user and host)")
+
+	return lc
+}
+
+func (lc *profileLockCmd) runProfileLockCmd(cmd *cobra.Command, args []string) error {
+	if lc.wait < 0 {
+		return clierrors.Errorf(clierrors.InvalidFlagValue, "--wait must not be negative, got %s", lc.wait)
+	}
+	if lc.ttl <= 0 {
+		return clierrors.Errorf(clierrors.InvalidFlagValue, "--ttl must be positive, got %s", lc.ttl)
+	}
+
+	backend, err := profileLockBackend()
+	if err != nil {
+		return err
+	}
+
+	return lockProfile(cmd.Context(), os.Stdout, backend, Config.Profile.ProfileName, profileLockOwner(lc.owner), lc.wait, lc.ttl)
+}
+
+type profileUnlockCmd struct {
+	cmd *cobra.Command
+
+	owner string
+	force bool
+}
+
+func newProfileUnlockCmd() *profileUnlockCmd {
+	uc := &profileUnlockCmd{}
+
+	uc.cmd = &cobra.Command{
+		Use:   "unlock",
+		Args:  validators.NoArgs,
+		Short: "Unlock the profile",
+		Long: `Unlock the profile locked with stripe profile lock. A profile locked by someone
+else is only unlocked with --force.`,
+		RunE: uc.runProfileUnlockCmd,
+	}
+
+	uc.cmd.Flags().StringVar(&uc.owner, "owner", "", "Who holds the lock (default: $STRIPE_CLI_LOCK_OWNER, or the user and host)")
+	uc.cmd.Flags().BoolVar(&uc.force, "force", false, "Unlock the profile even if it's locked by someone else")
+
+	return uc
+}
+
+func (uc *profileUnlockCmd) runProfileUnlockCmd(cmd *cobra.Command, args []string) error {
+	backend, err := profileLockBackend()
+	if err != nil {
+		return err
+	}
+
+	return unlockProfile(cmd.Context(), os.Stdout, backend, Config.Profile.ProfileName, profileLockOwner(uc.owner), uc.force)
+}
+
+// lockProfile locks the profile on behalf of owner, waiting up to wait for
+// someone else to unlock it
+func lockProfile(ctx context.Context, out io.Writer, backend storage.Backend, profile, owner string, wait, ttl time.Duration) error {
+	key := profileLockKey(profile)
+	ctx = storage.WithLockOwner(ctx, owner)
+
+	info, err := storage.ReadLock(ctx, backend, key)
+	switch {
+	case err == nil && info.Owner == owner:
+		// taken again below, to extend it
+		if err := storage.Unlock(ctx, backend, key); err != nil {
+			return err
+		}
+	case err == nil && wait == 0:
+		return profileLockedError(profile, info)
+	case err == nil:
+		fmt.Fprintf(os.Stderr, "Waiting for %s to unlock profile %s...\n", info.Owner, profile)
+	case !errors.Is(err, storage.ErrNotFound):
+		return err
+	}
+
+	attempt := wait
+	if attempt == 0 {
+		attempt = profileLockAttempt
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, attempt)
+	defer cancel()
+
+	if _, err := backend.Lock(waitCtx, key, ttl); err != nil {
+		if errors.Is(err, storage.ErrLocked) {
+			if info, err := storage.ReadLock(ctx, backend, key); err == nil {
+				return profileLockedError(profile, info)
+			}
+		}
+		return err
+	}
+
+	color := ansi.Color(out)
+	fmt.Fprintf(out, "%s Locked profile %s for %s until %s. Unlock it with: stripe profile unlock\n",
+		color.Green("✔"), profile, owner, time.Now().Add(ttl).Format(timeLayout))
+
+	return nil
+}
+
+// unlockProfile unlocks the profile held by owner, or by anyone with force
+func unlockProfile(ctx context.Context, out io.Writer, backend storage.Backend, profile, owner string, force bool) error {
+	key := profileLockKey(profile)
+
+	info, err := storage.ReadLock(ctx, backend, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		fmt.Fprintf(out, "Profile %s isn't locked.\n", profile)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if info.Owner != owner && !force {
+		return clierrors.Errorf(clierrors.ProfileLockedByOther, "profile %s is locked by %s, pass --force to unlock it anyway", profile, info.Owner)
+	}
+
+	if err := storage.Unlock(ctx, backend, key); err != nil {
+		return err
+	}
+
+	color := ansi.Color(out)
+	fmt.Fprintf(out, "%s Unlocked profile %s\n", color.Green("✔"), profile)
+
+	return nil
+}
+
+// checkProfileLock returns an error if the profile is locked by someone other
+// than owner, for the commands changing the objects of its account
+func checkProfileLock(ctx context.Context, backend storage.Backend, profile, owner string) error {
+	info, err := storage.ReadLock(ctx, backend, profileLockKey(profile))
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if info.Owner == owner {
+		return nil
+	}
+
+	return fmt.Errorf("%w. Wait for it with: stripe profile lock --wait 10m", profileLockedError(profile, info))
+}
+
+// ensureProfileUnlocked checks the lock of the current profile before
+// changing the objects of its account
+func ensureProfileUnlocked(ctx context.Context) error {
+	backend, err := profileLockBackend()
+	if err != nil {
+		return err
+	}
+
+	return checkProfileLock(ctx, backend, Config.Profile.ProfileName, profileLockOwner(""))
+}
+
+func profileLockedError(profile string, info *storage.LockInfo) error {
+	if info.Owner == "" {
+		return clierrors.Errorf(clierrors.ProfileLocked, "profile %s is locked", profile)
+	}
+
+	return clierrors.Errorf(clierrors.ProfileLocked, "profile %s is locked by %s until %s", profile, info.Owner, info.ExpiresAt.Local().Format(timeLayout))
+}
+
+func profileLockKey(profile string) string {
+	return "locks/profile-" + profile
+}
+
+// profileLockBackend returns where the profile locks are kept: with the state
+// when it's shared through a backend, in the config folder otherwise
+func profileLockBackend() (storage.Backend, error) {
+	if rawURL := os.Getenv(storage.BackendEnv); rawURL != "" {
+		return storage.Open(rawURL, fs)
+	}
+
+	return &storage.Local{Fs: fs, Dir: Config.GetConfigFolder(os.Getenv("XDG_CONFIG_HOME"))}, nil
+}
+
+// profileLockOwner returns who takes the profile locks: the --owner flag,
+// STRIPE_CLI_LOCK_OWNER, or the user and host
+func profileLockOwner(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if owner := os.Getenv(lockOwnerEnv); owner != "" {
+		return owner
+	}
+
+	host, _ := os.Hostname()
+	if u, err := user.Current(); err == nil {
+		return u.Username + "@" + host
+	}
+
+	return host
+}
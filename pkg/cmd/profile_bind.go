@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+// profileBindingPath is the binding file that selected the profile, if any
+var profileBindingPath string
+
+type profileBindCmd struct {
+	cmd *cobra.Command
+}
+
+func newProfileBindCmd() *profileBindCmd {
+	bc := &profileBindCmd{}
+
+	bc.cmd = &cobra.Command{
+		Use:   "bind <directory>",
+		Args:  validators.ExactArgs(1),
+		Short: "Use the profile for the commands run in a directory",
+		Long: `Bind the directory, and the directories below, to the profile passed with
+--project-name, so that the commands run there use it without passing
+--project-name. The binding is a ` + config.ProfileBindingFile + ` file naming the profile, which
+can be committed so that everyone working on the project uses the profile of
+that name. Passing --project-name still selects another profile.`,
+		Example: `stripe --project-name acme profile bind .`,
+		RunE:    bc.runProfileBindCmd,
+	}
+
+	return bc
+}
+
+func (bc *profileBindCmd) runProfileBindCmd(cmd *cobra.Command, args []string) error {
+	// the profile bound to the current directory isn't a choice to bind
+	if !cmd.Flags().Changed("project-name") {
+		return clierrors.New(clierrors.RequiredFlagMissing, "pass the profile to bind with --project-name, such as: stripe --project-name acme profile bind .")
+	}
+
+	return bindProfile(os.Stdout, args[0], Config.Profile.ProfileName)
+}
+
+type profileUnbindCmd struct {
+	cmd *cobra.Command
+}
+
+func newProfileUnbindCmd() *profileUnbindCmd {
+	uc := &profileUnbindCmd{}
+
+	uc.cmd = &cobra.Command{
+		Use:     "unbind <directory>",
+		Args:    validators.ExactArgs(1),
+		Short:   "Stop using a profile for the commands run in a directory",
+		Example: `stripe profile unbind .`,
+		RunE:    uc.runProfileUnbindCmd,
+	}
+
+	return uc
+}
+
+func (uc *profileUnbindCmd) runProfileUnbindCmd(cmd *cobra.Command, args []string) error {
+	path, err := config.UnbindProfile(args[0])
+	if err != nil {
+		return err
+	}
+
+	color := ansi.Color(os.Stdout)
+	fmt.Printf("%s Removed %s\n", color.Green("✔"), path)
+
+	return nil
+}
+
+func bindProfile(out io.Writer, dir, profile string) error {
+	path, err := config.BindProfile(dir, profile)
+	if err != nil {
+		return err
+	}
+
+	color := ansi.Color(out)
+	fmt.Fprintf(out, "%s Bound %s to profile %s. Commands run under it now use this profile.\n", color.Green("✔"), path, profile)
+
+	return nil
+}
+
+// applyProfileBinding selects the profile bound to the working directory,
+// unless --project-name was passed
+func applyProfileBinding() {
+	if rootCmd.PersistentFlags().Changed("project-name") {
+		return
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+
+	profile, path, err := config.FindProfileBinding(wd)
+	if err != nil {
+		// falling back to the default profile would run the command on the
+		// wrong account
+		log.Fatalf("%s", err)
+	}
+
+	if path != "" {
+		Config.Profile.ProfileName = profile
+		profileBindingPath = path
+	}
+}
+
+// printProfileBinding shows which profile the binding of the directory
+// selected, so that it's never used by surprise
+func printProfileBinding(out io.Writer, cmd *cobra.Command) {
+	if profileBindingPath == "" || cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd {
+		return
+	}
+
+	color := ansi.Color(out)
+	fmt.Fprintf(out, "%s\n", color.Faint(fmt.Sprintf("Using profile %s, bound by %s", Config.Profile.ProfileName, profileBindingPath)))
+}
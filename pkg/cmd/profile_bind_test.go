@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/config"
+)
+
+func TestBindProfile(t *testing.T) {
+	dir := t.TempDir()
+
+	var out bytes.Buffer
+	require.NoError(t, bindProfile(&out, dir, "acme"))
+	require.Contains(t, out.String(), "Bound "+filepath.Join(dir, config.ProfileBindingFile)+" to profile acme")
+
+	profile, _, err := config.FindProfileBinding(dir)
+	require.NoError(t, err)
+	require.Equal(t, "acme", profile)
+}
+
+func TestPrintProfileBinding(t *testing.T) {
+	defer func(profile, path string) {
+		Config.Profile.ProfileName, profileBindingPath = profile, path
+	}(Config.Profile.ProfileName, profileBindingPath)
+
+	cmd := &cobra.Command{Use: "trigger"}
+
+	var out bytes.Buffer
+	profileBindingPath = ""
+	printProfileBinding(&out, cmd)
+	require.Empty(t, out.String())
+
+	Config.Profile.ProfileName = "acme"
+	profileBindingPath = "/src/acme/.stripe-profile"
+	printProfileBinding(&out, cmd)
+	require.Equal(t, "Using profile acme, bound by /src/acme/.stripe-profile\n", out.String())
+
+	out.Reset()
+	printProfileBinding(&out, &cobra.Command{Use: cobra.ShellCompRequestCmd})
+	require.Empty(t, out.String(), "completions aren't interrupted")
+}
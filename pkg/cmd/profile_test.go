@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/storage"
+)
+
+func TestLockProfile(t *testing.T) {
+	ctx := context.Background()
+	backend := &storage.Local{Fs: afero.NewMemMapFs(), Dir: "/config/stripe"}
+
+	var out bytes.Buffer
+	require.NoError(t, lockProfile(ctx, &out, backend, "default", "ci-job-1", 0, time.Hour))
+	require.Contains(t, out.String(), "Locked profile default for ci-job-1")
+
+	// the owner can lock it again to extend the lock, but nobody else can
+	require.NoError(t, lockProfile(ctx, &out, backend, "default", "ci-job-1", 0, time.Hour))
+	err := lockProfile(ctx, &out, backend, "default", "ci-job-2", 0, time.Hour)
+	require.ErrorContains(t, err, "profile default is locked by ci-job-1 until")
+
+	// other profiles are locked separately
+	require.NoError(t, lockProfile(ctx, &out, backend, "staging", "ci-job-2", 0, time.Hour))
+
+	require.NoError(t, checkProfileLock(ctx, backend, "default", "ci-job-1"))
+	err = checkProfileLock(ctx, backend, "default", "ci-job-2")
+	require.ErrorContains(t, err, "profile default is locked by ci-job-1")
+	require.ErrorContains(t, err, "stripe profile lock --wait")
+}
+
+func TestLockProfileWaits(t *testing.T) {
+	ctx := context.Background()
+	backend := &storage.Local{Fs: afero.NewMemMapFs(), Dir: "/config/stripe"}
+
+	var out bytes.Buffer
+	require.NoError(t, lockProfile(ctx, &out, backend, "default", "ci-job-1", 0, time.Hour))
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		storage.Unlock(ctx, backend, profileLockKey("default"))
+	}()
+
+	require.NoError(t, lockProfile(ctx, &out, backend, "default", "ci-job-2", 5*time.Second, time.Hour))
+
+	info, err := storage.ReadLock(ctx, backend, profileLockKey("default"))
+	require.NoError(t, err)
+	require.Equal(t, "ci-job-2", info.Owner)
+}
+
+func TestUnlockProfile(t *testing.T) {
+	ctx := context.Background()
+	backend := &storage.Local{Fs: afero.NewMemMapFs(), Dir: "/config/stripe"}
+
+	var out bytes.Buffer
+	require.NoError(t, unlockProfile(ctx, &out, backend, "default", "ci-job-1", false))
+	require.Contains(t, out.String(), "Profile default isn't locked.")
+
+	require.NoError(t, lockProfile(ctx, &out, backend, "default", "ci-job-1", 0, time.Hour))
+
+	err := unlockProfile(ctx, &out, backend, "default", "ci-job-2", false)
+	require.EqualError(t, err, "profile default is locked by ci-job-1, pass --force to unlock it anyway")
+
+	require.NoError(t, unlockProfile(ctx, &out, backend, "default", "ci-job-2", true))
+	require.NoError(t, checkProfileLock(ctx, backend, "default", "ci-job-2"))
+}
+
+func TestProfileLockOwner(t *testing.T) {
+	t.Setenv(lockOwnerEnv, "ci-job-7")
+
+	require.Equal(t, "ci-job-9", profileLockOwner("ci-job-9"))
+	require.Equal(t, "ci-job-7", profileLockOwner(""))
+
+	t.Setenv(lockOwnerEnv, "")
+	require.NotEmpty(t, profileLockOwner(""))
+}
@@ -0,0 +1,364 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/tidwall/gjson"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/fixtures"
+	"github.com/stripe/stripe-cli/pkg/plugins"
+	"github.com/stripe/stripe-cli/pkg/replay"
+	"github.com/stripe/stripe-cli/pkg/requests"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+type replayProfileCmd struct {
+	cmd *cobra.Command
+
+	fs         afero.Fs
+	apiBaseURL string
+
+	// build
+	fromLogs  string
+	output    string
+	maxEvents int
+	livemode  bool
+
+	// run
+	duration    time.Duration
+	speed       float64
+	maxParallel int
+	dryRun      bool
+	seed        int64
+}
+
+func newReplayProfileCmd() *replayProfileCmd {
+	rc := &replayProfileCmd{fs: afero.NewOsFs()}
+
+	rc.cmd = &cobra.Command{
+		Use:   "replay-profile",
+		Args:  validators.NoArgs,
+		Short: "Generate test mode traffic shaped like an account's events",
+		Long: `Build a profile of the events of an account, such as your live mode account,
+then trigger events in test mode with the same shape to load a staging
+environment realistically.
+
+A profile records the share of each event type, the average and peak number of
+events per minute, and how bursty the events are. It's built from the events
+the API lists, so it covers at most the last 30 days.`,
+	}
+
+	build := &cobra.Command{
+		Use:   "build",
+		Args:  validators.NoArgs,
+		Short: "Build a profile from the recent events of an account",
+		Example: `stripe replay-profile build --from-logs 7d --live
+  stripe replay-profile build --from-logs 12h --output checkout.json`,
+		RunE: rc.runBuildCmd,
+	}
+	build.Flags().StringVar(&rc.fromLogs, "from-logs", "7d", "How far back to analyze events, in days (e.g. 7d) or as a duration (e.g. 12h)")
+	build.Flags().StringVarP(&rc.output, "output", "o", "replay-profile.json", "File to write the profile to")
+	build.Flags().IntVar(&rc.maxEvents, "max-events", 100000, "Stop analyzing after this many events, starting with the most recent")
+	build.Flags().BoolVar(&rc.livemode, "live", false, "Analyze the events of live mode (default: test)")
+
+	run := &cobra.Command{
+		Use:   "run <profile>",
+		Args:  validators.ExactArgs(1),
+		Short: "Trigger test mode events following a profile",
+		Long: `Trigger test mode events following a profile, picking event types by their
+share of the profile, at its rate and with its burstiness. Event types that
+stripe trigger doesn't support are left out.
+
+Events are always triggered in test mode.`,
+		Example: `stripe replay-profile run replay-profile.json --duration 10m
+  stripe replay-profile run replay-profile.json --speed 5 --dry-run`,
+		RunE: rc.runRunCmd,
+	}
+	run.Flags().DurationVar(&rc.duration, "duration", 5*time.Minute, "How long to generate traffic for")
+	run.Flags().Float64Var(&rc.speed, "speed", 1, "Multiply the rate of the profile by this factor")
+	run.Flags().IntVar(&rc.maxParallel, "max-parallel", 10, "Maximum number of triggers running at once")
+	run.Flags().BoolVar(&rc.dryRun, "dry-run", false, "Print the events that would be triggered, without triggering them")
+	run.Flags().Int64Var(&rc.seed, "seed", 0, "Seed of the random sequence of events, to repeat a run (default: random)")
+
+	for _, sub := range []*cobra.Command{build, run} {
+		// Hidden configuration flags, useful for dev/debugging
+		sub.Flags().StringVar(&rc.apiBaseURL, "api-base", stripe.DefaultAPIBaseURL, "Sets the API base URL")
+		sub.Flags().MarkHidden("api-base") // #nosec G104
+
+		rc.cmd.AddCommand(sub)
+	}
+
+	return rc
+}
+
+func (rc *replayProfileCmd) runBuildCmd(cmd *cobra.Command, args []string) error {
+	window, err := replay.ParseWindow(rc.fromLogs)
+	if err != nil {
+		return err
+	}
+
+	apiKey, err := Config.Profile.GetAPIKey(rc.livemode)
+	if err != nil {
+		return err
+	}
+
+	until := time.Now().Truncate(time.Minute)
+	since := until.Add(-window)
+
+	events, err := rc.listEvents(cmd.Context(), apiKey, since, until)
+	if err != nil {
+		return err
+	}
+
+	// when the events were capped, only the window they cover is profiled
+	if rc.maxEvents > 0 && len(events) >= rc.maxEvents {
+		since = events[len(events)-1].Created.Truncate(time.Minute).Add(time.Minute)
+		fmt.Fprintf(os.Stderr, "Analyzed the last %d events, created since %s\n", rc.maxEvents, since.Format(timeLayout))
+	}
+
+	profile, err := replay.Build(events, since, until)
+	if err != nil {
+		return err
+	}
+
+	if err := profile.Save(rc.fs, rc.output); err != nil {
+		return err
+	}
+
+	printReplayProfile(profile)
+	fmt.Printf("Saved the profile to %s\n", rc.output)
+
+	return nil
+}
+
+// listEvents returns the types and creation times of the events created in
+// [since, until), newest first
+func (rc *replayProfileCmd) listEvents(ctx context.Context, apiKey string, since, until time.Time) ([]replay.Event, error) {
+	params := &requests.RequestParameters{}
+	params.AppendData([]string{
+		fmt.Sprintf("created[gte]=%d", since.Unix()),
+		fmt.Sprintf("created[lt]=%d", until.Unix()),
+	})
+
+	req := requests.Base{
+		Method:         http.MethodGet,
+		SuppressOutput: true,
+		APIBaseURL:     rc.apiBaseURL,
+	}
+
+	var out bytes.Buffer
+
+	err := req.MakePaginatedRequest(ctx, apiKey, "/v1/events", params, requests.PaginationOptions{PageSize: 100, LimitTotal: rc.maxEvents, NDJSON: true}, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []replay.Event
+
+	scanner := bufio.NewScanner(&out)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		event := gjson.ParseBytes(scanner.Bytes())
+		events = append(events, replay.Event{
+			Type:    event.Get("type").String(),
+			Created: time.Unix(event.Get("created").Int(), 0),
+		})
+	}
+
+	return events, scanner.Err()
+}
+
+func printReplayProfile(profile *replay.Profile) {
+	fmt.Printf("%d events from %s to %s: %.2f per minute on average, %d at peak, burstiness %.2f\n",
+		profile.Events, profile.Since.Local().Format(timeLayout), profile.Until.Local().Format(timeLayout),
+		profile.RatePerMinute, profile.PeakPerMinute, profile.Burstiness)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "EVENT TYPE\tEVENTS\tSHARE")
+	for _, weight := range profile.EventTypes {
+		fmt.Fprintf(w, "%s\t%d\t%.1f%%\n", weight.Type, weight.Count, weight.Weight*100)
+	}
+	w.Flush()
+}
+
+func (rc *replayProfileCmd) runRunCmd(cmd *cobra.Command, args []string) error {
+	if rc.duration <= 0 {
+		return clierrors.New(clierrors.InvalidFlagValue, "--duration must be a positive duration")
+	}
+	if rc.speed <= 0 {
+		return clierrors.New(clierrors.InvalidFlagValue, "--speed must be positive")
+	}
+	if rc.maxParallel < 1 {
+		return clierrors.New(clierrors.InvalidFlagValue, "--max-parallel must be at least 1")
+	}
+
+	profile, err := replay.Load(rc.fs, args[0])
+	if err != nil {
+		return err
+	}
+
+	supported := make(map[string]bool)
+	for _, name := range fixtures.EventNames() {
+		supported[name] = true
+	}
+
+	seed := rc.seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	sampler, skipped, err := profile.Sampler(func(eventType string) bool { return supported[eventType] }, rc.speed, seed)
+	if err != nil {
+		return err
+	}
+
+	if len(skipped) > 0 {
+		fmt.Fprintf(os.Stderr, "Leaving out the event types stripe trigger doesn't support: %s\n", strings.Join(skipped, ", "))
+	}
+
+	if rc.dryRun {
+		for _, event := range planReplay(sampler, rc.duration) {
+			fmt.Printf("%s\t%s\n", event.at.Truncate(time.Millisecond), event.eventType)
+		}
+
+		return nil
+	}
+
+	apiKey, err := Config.Profile.GetAPIKey(false)
+	if err != nil {
+		return err
+	}
+
+	if strings.Contains(apiKey, "live") {
+		return clierrors.New(clierrors.LiveKeyNotAllowed, "replay-profile run only triggers events in test mode, but the API key is a live mode key")
+	}
+
+	metadata := fixtureMetadata(&Config.Profile)
+	defer plugins.CleanupAllClients()
+
+	fmt.Fprintf(os.Stderr, "Triggering about %.1f events per minute for %s (seed %d)\n", profile.RatePerMinute*rc.speed, rc.duration, seed)
+
+	counts, failed := replayEvents(cmd.Context(), time.After(rc.duration), sampler, rc.maxParallel, func(ctx context.Context, eventType string) error {
+		_, err := fixtures.Trigger(ctx, eventType, "", rc.apiBaseURL, apiKey, nil, nil, nil, nil, "", "", metadata)
+		return err
+	})
+
+	printReplayCounts(counts, failed)
+
+	if failed > 0 {
+		return clierrors.Errorf(clierrors.ReplayTriggersFailed, "%d triggers failed", failed)
+	}
+
+	return nil
+}
+
+// plannedEvent is an event of a dry run, at its offset from the start
+type plannedEvent struct {
+	at        time.Duration
+	eventType string
+}
+
+// planReplay returns the events the sampler generates over duration
+func planReplay(sampler *replay.Sampler, duration time.Duration) []plannedEvent {
+	var events []plannedEvent
+	at := time.Duration(0)
+
+	for {
+		delay, eventType := sampler.Next()
+
+		at += delay
+		if at >= duration {
+			return events
+		}
+
+		events = append(events, plannedEvent{at: at, eventType: eventType})
+	}
+}
+
+// replayEvents triggers the events of the sampler until stop fires or ctx is
+// canceled, with up to concurrency triggers running at once. When the triggers
+// can't keep up, the next events are delayed. The triggers in progress when
+// stop fires are waited for. It returns the number of events triggered per
+// type, and how many triggers failed.
+func replayEvents(ctx context.Context, stop <-chan time.Time, sampler *replay.Sampler, concurrency int, trigger func(ctx context.Context, eventType string) error) (map[string]int, int) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	counts := make(map[string]int)
+	failed := 0
+	sem := make(chan struct{}, concurrency)
+
+	defer wg.Wait()
+
+	for {
+		delay, eventType := sampler.Next()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return counts, failed
+		case <-stop:
+			timer.Stop()
+			return counts, failed
+		case <-timer.C:
+		}
+
+		select {
+		case <-ctx.Done():
+			return counts, failed
+		case <-stop:
+			return counts, failed
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(eventType string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := trigger(ctx, eventType)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			counts[eventType]++
+			if err != nil {
+				failed++
+				fmt.Fprintf(os.Stderr, "Failed to trigger %s: %s\n", eventType, err)
+			}
+		}(eventType)
+	}
+}
+
+func printReplayCounts(counts map[string]int, failed int) {
+	types := make([]string, 0, len(counts))
+	total := 0
+	for eventType, count := range counts {
+		types = append(types, eventType)
+		total += count
+	}
+	sort.Strings(types)
+
+	fmt.Printf("Triggered %d events, %d failed\n", total, failed)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, eventType := range types {
+		fmt.Fprintf(w, "%s\t%d\n", eventType, counts[eventType])
+	}
+	w.Flush()
+}
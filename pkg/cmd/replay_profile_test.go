@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/replay"
+)
+
+func newTestSampler(t *testing.T, ratePerMinute float64) *replay.Sampler {
+	profile := &replay.Profile{
+		RatePerMinute: ratePerMinute,
+		Burstiness:    1,
+		EventTypes: []replay.EventTypeWeight{
+			{Type: "charge.succeeded", Weight: 0.5},
+			{Type: "charge.failed", Weight: 0.5},
+		},
+	}
+
+	sampler, _, err := profile.Sampler(func(string) bool { return true }, 1, 1)
+	require.NoError(t, err)
+
+	return sampler
+}
+
+func TestPlanReplay(t *testing.T) {
+	events := planReplay(newTestSampler(t, 60), time.Hour)
+
+	require.InDelta(t, 3600, len(events), 200)
+	for i, event := range events {
+		require.Less(t, event.at, time.Hour)
+		if i > 0 {
+			require.GreaterOrEqual(t, event.at, events[i-1].at)
+		}
+	}
+}
+
+func TestReplayEvents(t *testing.T) {
+	stop := make(chan time.Time)
+	triggered := 0
+
+	counts, failed := replayEvents(context.Background(), stop, newTestSampler(t, 60000), 1, func(ctx context.Context, eventType string) error {
+		triggered++
+		if triggered == 20 {
+			close(stop)
+		}
+		if eventType == "charge.failed" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	// an event may already be due when the run stops
+	require.GreaterOrEqual(t, triggered, 20)
+	require.Equal(t, triggered, counts["charge.succeeded"]+counts["charge.failed"])
+	require.Equal(t, counts["charge.failed"], failed)
+}
@@ -1,28 +1,481 @@
 package resource
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/tidwall/gjson"
 
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/proxy"
+	"github.com/stripe/stripe-cli/pkg/requests"
+	"github.com/stripe/stripe-cli/pkg/webhooks"
 )
 
+// webhookUserAgent is the User-Agent Stripe sends webhooks with
+const webhookUserAgent = "Stripe/1.0 (+https://stripe.com/docs/webhooks)"
+
 // EventsResendCmd represents the event resend API operation command. This
 // command is manually defined because it has a custom behavior.
 type EventsResendCmd struct {
 	opCmd *OperationCmd
+
+	eventType     string
+	createdAfter  string
+	createdBefore string
+	file          string
+	forwardTo     string
+	secret        string
+	dryRun        bool
+	concurrency   int
+	output        string
+
+	// httpClient posts the events to --forward-to
+	httpClient *http.Client
+	// backoffs reports the events waiting to be resent after being rate
+	// limited
+	backoffs *requests.BackoffTimeline
+}
+
+// resendReport is a line of the JSON output of a bulk resend
+type resendReport struct {
+	Type        string `json:"type"`
+	ID          string `json:"id"`
+	Destination string `json:"destination"`
+	Outcome     string `json:"outcome,omitempty"`
+	Error       string `json:"error,omitempty"`
 }
 
 func (erc *EventsResendCmd) runEventsResendCmd(cmd *cobra.Command, args []string) error {
-	// If the `webhook-endpoint` flag was not passed, then add
-	// `for_stripecli=true` to the request so the event is replayed to the
-	// Stripe CLI.
-	if !erc.opCmd.Cmd.Flags().Changed("webhook-endpoint") {
-		erc.opCmd.Parameters.AppendData([]string{"for_stripecli=true"})
+	// A single event resent by Stripe keeps the behavior of the API operation
+	if !erc.bulk(args) {
+		// If the `webhook-endpoint` flag was not passed, then add
+		// `for_stripecli=true` to the request so the event is replayed to the
+		// Stripe CLI.
+		if !erc.opCmd.Cmd.Flags().Changed("webhook-endpoint") {
+			erc.opCmd.Parameters.AppendData([]string{"for_stripecli=true"})
+		}
+
+		return erc.opCmd.runOperationCmd(cmd, args)
+	}
+
+	if erc.concurrency < 1 {
+		return clierrors.New(clierrors.InvalidFlagValue, "--concurrency must be at least 1")
+	}
+
+	switch erc.output {
+	case "text":
+		erc.backoffs = requests.NewBackoffTimeline(os.Stderr, false)
+	case "json":
+		erc.backoffs = requests.NewBackoffTimeline(os.Stdout, true)
+	default:
+		return clierrors.Errorf(clierrors.InvalidFlagValue, "unsupported output %q, expected text or json", erc.output)
+	}
+
+	if erc.forwardTo != "" {
+		if erc.opCmd.Cmd.Flags().Changed("webhook-endpoint") {
+			return clierrors.New(clierrors.ConflictingFlags, "--forward-to and --webhook-endpoint can't be used together")
+		}
+
+		erc.forwardTo = proxy.ParseURL(erc.forwardTo)
+	}
+
+	apiKey, err := erc.opCmd.Profile.GetAPIKey(erc.opCmd.Livemode)
+	if err != nil {
+		return err
+	}
+
+	ids, err := erc.selectEvents(cmd, apiKey, args)
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		if erc.output == "text" {
+			fmt.Println("No events matched.")
+		}
+
+		return nil
+	}
+
+	if erc.dryRun {
+		if erc.output == "text" {
+			fmt.Printf("Would resend %d event(s) to %s:\n", len(ids), erc.destination())
+		}
+
+		for _, id := range ids {
+			erc.report(resendReport{Type: "dry_run", ID: id})
+		}
+
+		return nil
+	}
+
+	resend, err := erc.resender(cmd, apiKey)
+	if err != nil {
+		return err
+	}
+
+	if erc.output == "text" {
+		fmt.Printf("Resending %d event(s) to %s\n", len(ids), erc.destination())
+	}
+
+	failed := resendEvents(cmd.Context(), ids, erc.concurrency, resend, func(id, outcome string, err error) {
+		if err != nil {
+			erc.report(resendReport{Type: "failed", ID: id, Error: err.Error()})
+		} else {
+			erc.report(resendReport{Type: "resent", ID: id, Outcome: outcome})
+		}
+	})
+	if failed > 0 {
+		return clierrors.Errorf(clierrors.EventResendFailed, "failed to resend %d of %d event(s)", failed, len(ids))
+	}
+
+	return nil
+}
+
+// report prints the outcome of resending an event, as a line of text or JSON
+func (erc *EventsResendCmd) report(report resendReport) {
+	if erc.output == "json" {
+		report.Destination = erc.destination()
+		line, _ := json.Marshal(report)
+		fmt.Println(string(line))
+
+		return
+	}
+
+	switch report.Type {
+	case "failed":
+		fmt.Printf("  %s failed: %s\n", report.ID, report.Error)
+	case "resent":
+		fmt.Printf("  %s %s\n", report.ID, report.Outcome)
+	default:
+		fmt.Printf("  %s\n", report.ID)
+	}
+}
+
+// bulk returns whether the events are selected or sent in a way only the CLI
+// supports, rather than as a single call to the API
+func (erc *EventsResendCmd) bulk(args []string) bool {
+	return len(args) != 1 || erc.eventType != "" || erc.createdAfter != "" || erc.createdBefore != "" ||
+		erc.file != "" || erc.forwardTo != "" || erc.dryRun || erc.output != "text"
+}
+
+func (erc *EventsResendCmd) destination() string {
+	switch {
+	case erc.forwardTo != "":
+		return erc.forwardTo
+	case erc.opCmd.Cmd.Flags().Changed("webhook-endpoint"):
+		return *erc.opCmd.stringFlags["webhook-endpoint"]
+	default:
+		return "the Stripe CLI"
+	}
+}
+
+// selectEvents returns the IDs of the events to resend, in the order they were
+// given and without duplicates
+func (erc *EventsResendCmd) selectEvents(cmd *cobra.Command, apiKey string, args []string) ([]string, error) {
+	ids := append([]string{}, args...)
+
+	if erc.file != "" {
+		fromFile, err := readEventIDs(erc.file)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, fromFile...)
+	}
+
+	if erc.eventType != "" || erc.createdAfter != "" || erc.createdBefore != "" {
+		listed, err := erc.listEvents(cmd, apiKey)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, listed...)
+	}
+
+	if len(ids) == 0 {
+		return nil, clierrors.New(clierrors.RequiredFlagMissing, "select the events to resend by ID, with --type, --created-after or --created-before, or with --file")
+	}
+
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		if !strings.HasPrefix(id, "evt_") {
+			return nil, clierrors.Errorf(clierrors.InvalidArgs, "%s is not an event ID", id)
+		}
+
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
 	}
 
-	return erc.opCmd.runOperationCmd(cmd, args)
+	return unique, nil
+}
+
+// listEvents returns the IDs of the events matching --type and the created
+// range, oldest first
+func (erc *EventsResendCmd) listEvents(cmd *cobra.Command, apiKey string) ([]string, error) {
+	params := &requests.RequestParameters{}
+
+	if erc.eventType != "" {
+		params.AppendData([]string{"type=" + erc.eventType})
+	}
+
+	for _, bound := range []struct{ value, param string }{
+		{erc.createdAfter, "created[gte]"},
+		{erc.createdBefore, "created[lte]"},
+	} {
+		if bound.value == "" {
+			continue
+		}
+
+		created, err := parseEventTime(bound.value)
+		if err != nil {
+			return nil, err
+		}
+
+		params.AppendData([]string{fmt.Sprintf("%s=%d", bound.param, created.Unix())})
+	}
+
+	if account, _ := cmd.Flags().GetString("stripe-account"); account != "" {
+		params.SetStripeAccount(account)
+	}
+
+	req := requests.Base{
+		Method:         http.MethodGet,
+		SuppressOutput: true,
+		APIBaseURL:     erc.opCmd.APIBaseURL,
+		Backoffs:       erc.backoffs,
+	}
+
+	var out bytes.Buffer
+
+	err := req.MakePaginatedRequest(cmd.Context(), apiKey, "/v1/events", params, requests.PaginationOptions{PageSize: 100, NDJSON: true}, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+
+	scanner := bufio.NewScanner(&out)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		ids = append(ids, gjson.GetBytes(scanner.Bytes(), "id").String())
+	}
+
+	// events are listed newest first, but are resent in the order they
+	// happened
+	for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+		ids[i], ids[j] = ids[j], ids[i]
+	}
+
+	return ids, scanner.Err()
+}
+
+// resender returns the function resending a single event to the destination,
+// which describes the outcome
+func (erc *EventsResendCmd) resender(cmd *cobra.Command, apiKey string) (func(ctx context.Context, id string) (string, error), error) {
+	if erc.forwardTo == "" {
+		params := erc.opCmd.Parameters
+
+		for _, name := range []string{"account", "webhook-endpoint"} {
+			if erc.opCmd.Cmd.Flags().Changed(name) {
+				params.AppendData([]string{strings.ReplaceAll(name, "-", "_") + "=" + *erc.opCmd.stringFlags[name]})
+			}
+		}
+
+		if !erc.opCmd.Cmd.Flags().Changed("webhook-endpoint") {
+			params.AppendData([]string{"for_stripecli=true"})
+		}
+
+		return func(ctx context.Context, id string) (string, error) {
+			req := requests.Base{
+				Method:           http.MethodPost,
+				SuppressOutput:   true,
+				APIBaseURL:       erc.opCmd.APIBaseURL,
+				Livemode:         erc.opCmd.Livemode,
+				RetryRateLimited: true,
+				Worker:           id,
+				Backoffs:         erc.backoffs,
+			}
+
+			_, err := req.MakeRequest(ctx, apiKey, "/v1/events/"+id+"/retry", &params, true)
+			if err != nil {
+				return "", err
+			}
+
+			return "resent", nil
+		}, nil
+	}
+
+	secret := erc.secret
+	if secret == "" {
+		deviceName, err := erc.opCmd.Profile.GetDeviceName()
+		if err != nil {
+			return nil, err
+		}
+
+		// sign with the secret `stripe listen` uses, which the endpoint is
+		// most likely configured with already
+		secret, err = proxy.GetSessionSecret(cmd.Context(), deviceName, apiKey, erc.opCmd.APIBaseURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	params := &requests.RequestParameters{}
+	if account, _ := cmd.Flags().GetString("stripe-account"); account != "" {
+		params.SetStripeAccount(account)
+	}
+
+	return func(ctx context.Context, id string) (string, error) {
+		req := requests.Base{
+			Method:           http.MethodGet,
+			SuppressOutput:   true,
+			APIBaseURL:       erc.opCmd.APIBaseURL,
+			RetryRateLimited: true,
+			Worker:           id,
+			Backoffs:         erc.backoffs,
+		}
+
+		payload, err := req.MakeRequest(ctx, apiKey, "/v1/events/"+id, params, true)
+		if err != nil {
+			return "", err
+		}
+
+		return erc.forward(ctx, payload, secret)
+	}, nil
+}
+
+// forward posts the event to --forward-to, signed as Stripe would
+func (erc *EventsResendCmd) forward(ctx context.Context, payload []byte, secret string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, erc.forwardTo, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set("Stripe-Signature", webhooks.GenerateHeader(payload, secret, time.Now()))
+
+	resp, err := erc.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return "", clierrors.Errorf(clierrors.EventResendFailed, "%s responded with %d", erc.forwardTo, resp.StatusCode)
+	}
+
+	return fmt.Sprintf("forwarded [%d]", resp.StatusCode), nil
+}
+
+// resendEvents resends the events with up to concurrency requests at once,
+// reporting each outcome, and returns how many failed
+func resendEvents(ctx context.Context, ids []string, concurrency int, resend func(ctx context.Context, id string) (string, error), report func(id, outcome string, err error)) int {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	failed := 0
+	sem := make(chan struct{}, concurrency)
+
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			mu.Lock()
+			failed++
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			outcome, err := resend(ctx, id)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				failed++
+			}
+
+			report(id, outcome, err)
+		}(id)
+	}
+
+	wg.Wait()
+
+	return failed
+}
+
+// readEventIDs reads the event IDs in a file, one per line. Blank lines and
+// lines starting with # are skipped. A path of - reads from stdin.
+func readEventIDs(path string) ([]string, error) {
+	var reader io.Reader
+
+	if path == "-" {
+		reader = os.Stdin
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		reader = file
+	}
+
+	var ids []string
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		ids = append(ids, line)
+	}
+
+	return ids, scanner.Err()
+}
+
+// parseEventTime parses a time given as a Unix timestamp, an RFC 3339 time or
+// a date
+func parseEventTime(value string) (time.Time, error) {
+	if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(timestamp, 0), nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, clierrors.Errorf(clierrors.InvalidFlagValue, "invalid time %q, expected a Unix timestamp, an RFC 3339 time or a date like 2006-01-02", value)
 }
 
 // NewEventsResendCmd returns a new EventsResendCmd.
@@ -32,9 +485,36 @@ func NewEventsResendCmd(parentCmd *cobra.Command, cfg *config.Config) *EventsRes
 			"account":          "string",
 			"webhook_endpoint": "string",
 		}, cfg),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
 	}
 
-	eventsResendCmd.opCmd.Cmd.RunE = eventsResendCmd.runEventsResendCmd
+	cmd := eventsResendCmd.opCmd.Cmd
+	cmd.RunE = eventsResendCmd.runEventsResendCmd
+	cmd.Args = cobra.ArbitraryArgs
+	cmd.SetUsageTemplate(operationUsageTemplate([]string{"[{event}...]"}))
+	cmd.Long = `Resend events to the Stripe CLI, to one of your webhook endpoints with
+--webhook-endpoint, or straight to a local URL with --forward-to, signed with
+the secret stripe listen uses or the one given with --secret.
+
+Events can be selected by ID, by type and creation time, or from a file listing
+one ID per line. Only the events of the last 30 days can be resent.
+
+Events that are rate limited are retried after a wait, which is reported as it
+happens, along with every other event waiting. With --output json, progress is
+reported as a stream of JSON objects instead.`
+	cmd.Example = `stripe events resend evt_123
+  stripe events resend --type invoice.paid --created-after 2024-01-01 --dry-run
+  stripe events resend --file events.txt --forward-to localhost:4242/webhook --concurrency 4`
+
+	cmd.Flags().StringVar(&eventsResendCmd.eventType, "type", "", "Resend the events of this type")
+	cmd.Flags().StringVar(&eventsResendCmd.createdAfter, "created-after", "", "Resend the events created at or after this time")
+	cmd.Flags().StringVar(&eventsResendCmd.createdBefore, "created-before", "", "Resend the events created at or before this time")
+	cmd.Flags().StringVar(&eventsResendCmd.file, "file", "", "Resend the events listed in this file, one ID per line (- to read from stdin)")
+	cmd.Flags().StringVar(&eventsResendCmd.forwardTo, "forward-to", "", "Post the events to this URL instead of having Stripe send them")
+	cmd.Flags().StringVar(&eventsResendCmd.secret, "secret", "", "The signing secret for the events posted with --forward-to (default: the secret of stripe listen)")
+	cmd.Flags().BoolVar(&eventsResendCmd.dryRun, "dry-run", false, "List the events that would be resent without resending them")
+	cmd.Flags().IntVar(&eventsResendCmd.concurrency, "concurrency", 1, "How many events to resend at once")
+	cmd.Flags().StringVar(&eventsResendCmd.output, "output", "text", "How to report progress, including the waits after being rate limited: text or json (one object per line)")
 
 	return eventsResendCmd
 }
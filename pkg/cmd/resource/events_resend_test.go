@@ -2,17 +2,24 @@ package resource
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 
 	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/webhooks"
 )
 
 func TestRunEventsResendCmd(t *testing.T) {
@@ -78,3 +85,145 @@ func TestRunEventsResendCmd_WithWebhookEndpoint(t *testing.T) {
 
 	require.NoError(t, err)
 }
+
+func TestRunEventsResendCmd_ByTypeAndFile(t *testing.T) {
+	var mu sync.Mutex
+	var retried []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/events":
+			require.Equal(t, "invoice.paid", r.URL.Query().Get("type"))
+			require.Equal(t, "1700000000", r.URL.Query().Get("created[gte]"))
+			w.Write([]byte(`{"object": "list", "data": [{"id": "evt_3"}, {"id": "evt_2"}], "has_more": false}`))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/retry"):
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.Equal(t, "for_stripecli=true", string(body))
+			retried = append(retried, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/events/"), "/retry"))
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	file := filepath.Join(t.TempDir(), "events.txt")
+	require.NoError(t, os.WriteFile(file, []byte("# from the incident\nevt_1\n\nevt_2\n"), 0600))
+
+	viper.Reset()
+
+	parentCmd := &cobra.Command{Annotations: make(map[string]string)}
+	erc := NewEventsResendCmd(parentCmd, &config.Config{Profile: config.Profile{APIKey: "sk_test_1234"}})
+	erc.opCmd.APIBaseURL = ts.URL
+
+	parentCmd.SetArgs([]string{"resend", "--file", file, "--type", "invoice.paid", "--created-after", "1700000000"})
+	err := parentCmd.ExecuteContext(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"evt_1", "evt_2", "evt_3"}, retried)
+}
+
+func TestRunEventsResendCmd_DryRun(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+	defer ts.Close()
+
+	viper.Reset()
+
+	parentCmd := &cobra.Command{Annotations: make(map[string]string)}
+	erc := NewEventsResendCmd(parentCmd, &config.Config{Profile: config.Profile{APIKey: "sk_test_1234"}})
+	erc.opCmd.APIBaseURL = ts.URL
+
+	parentCmd.SetArgs([]string{"resend", "evt_1", "evt_2", "--dry-run"})
+	err := parentCmd.ExecuteContext(context.Background())
+
+	require.NoError(t, err)
+}
+
+func TestRunEventsResendCmd_ForwardTo(t *testing.T) {
+	payload := `{"id": "evt_1", "object": "event", "type": "invoice.paid"}`
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/v1/events/evt_1", r.URL.Path)
+		w.Write([]byte(payload))
+	}))
+	defer api.Close()
+
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, payload, string(body))
+
+		verification, err := webhooks.Verify(body, r.Header.Get("Stripe-Signature"), "whsec_test", time.Now())
+		require.NoError(t, err)
+		require.True(t, verification.Valid)
+	}))
+	defer endpoint.Close()
+
+	viper.Reset()
+
+	parentCmd := &cobra.Command{Annotations: make(map[string]string)}
+	erc := NewEventsResendCmd(parentCmd, &config.Config{Profile: config.Profile{APIKey: "sk_test_1234"}})
+	erc.opCmd.APIBaseURL = api.URL
+
+	parentCmd.SetArgs([]string{"resend", "evt_1", "--forward-to", endpoint.URL, "--secret", "whsec_test"})
+	err := parentCmd.ExecuteContext(context.Background())
+
+	require.NoError(t, err)
+}
+
+func TestRunEventsResendCmd_NotAnEventID(t *testing.T) {
+	viper.Reset()
+
+	parentCmd := &cobra.Command{Annotations: make(map[string]string), SilenceUsage: true, SilenceErrors: true}
+	NewEventsResendCmd(parentCmd, &config.Config{Profile: config.Profile{APIKey: "sk_test_1234"}})
+
+	parentCmd.SetArgs([]string{"resend", "evt_1", "ch_1", "--dry-run"})
+	err := parentCmd.ExecuteContext(context.Background())
+
+	require.EqualError(t, err, "ch_1 is not an event ID")
+}
+
+func TestResendEvents(t *testing.T) {
+	var mu sync.Mutex
+	outcomes := make(map[string]string)
+
+	failed := resendEvents(context.Background(), []string{"evt_1", "evt_2", "evt_3"}, 2, func(ctx context.Context, id string) (string, error) {
+		if id == "evt_2" {
+			return "", errors.New("boom")
+		}
+		return "resent", nil
+	}, func(id, outcome string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err != nil {
+			outcome = err.Error()
+		}
+		outcomes[id] = outcome
+	})
+
+	require.Equal(t, 1, failed)
+	require.Equal(t, map[string]string{"evt_1": "resent", "evt_2": "boom", "evt_3": "resent"}, outcomes)
+}
+
+func TestParseEventTime(t *testing.T) {
+	for _, value := range []string{"1700000000", "2023-11-14T22:13:20Z"} {
+		parsed, err := parseEventTime(value)
+		require.NoError(t, err)
+		require.Equal(t, int64(1700000000), parsed.Unix())
+	}
+
+	parsed, err := parseEventTime("2023-11-14")
+	require.NoError(t, err)
+	require.Equal(t, int64(1699920000), parsed.Unix())
+
+	_, err = parseEventTime("yesterday")
+	require.Error(t, err)
+}
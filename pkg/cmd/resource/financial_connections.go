@@ -0,0 +1,34 @@
+package resource
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/config"
+)
+
+// AddFinancialConnectionsSubCmds adds custom subcommands to the
+// `financial_connections` command created automatically as a namespace
+// command.
+func AddFinancialConnectionsSubCmds(rootCmd *cobra.Command, cfg *config.Config) error {
+	found := false
+
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Use == "financial_connections" {
+			found = true
+
+			cmd.Aliases = append(cmd.Aliases, "financial-connections")
+
+			NewFinancialConnectionsSimulateCmd(cmd, cfg)
+
+			break
+		}
+	}
+
+	if !found {
+		return errors.New("Could not find financial_connections command")
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/financialconnections"
+	"github.com/stripe/stripe-cli/pkg/open"
+	"github.com/stripe/stripe-cli/pkg/requests"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+// FinancialConnectionsSimulateCmd links test accounts to a customer with
+// Financial Connections, and follows the events of the accounts
+type FinancialConnectionsSimulateCmd struct {
+	cfg *config.Config
+	cmd *cobra.Command
+
+	customer       string
+	institution    string
+	permissions    []string
+	open           bool
+	publishableKey string
+	timeout        time.Duration
+	apiBaseURL     string
+}
+
+// NewFinancialConnectionsSimulateCmd returns a new financial_connections
+// simulate command
+func NewFinancialConnectionsSimulateCmd(parentCmd *cobra.Command, cfg *config.Config) {
+	fsc := &FinancialConnectionsSimulateCmd{cfg: cfg}
+
+	fsc.cmd = &cobra.Command{
+		Use:   "simulate",
+		Args:  validators.NoArgs,
+		Short: "Link test accounts to a customer and follow their events",
+		Long: fmt.Sprintf(`Create a Financial Connections session for a customer, serve a page running
+the authentication flow of the session, and print the events of the accounts
+the customer links as they happen, until interrupted or --timeout elapses.
+
+The data of the balances, ownership and transactions permissions is fetched as
+soon as the accounts are linked, so that their refreshed events follow. Only
+test mode is supported.
+
+Test institutions: %s`, strings.Join(financialconnections.InstitutionNames(), ", ")),
+		Example: `stripe financial-connections simulate --customer cus_123 --open
+  stripe financial-connections simulate --customer cus_123 --institution ownership --permissions ownership,payment_method`,
+		RunE: fsc.runSimulateCmd,
+	}
+
+	fsc.cmd.Flags().StringVar(&fsc.customer, "customer", "", "The customer linking the accounts (required)")
+	fsc.cmd.Flags().StringVar(&fsc.institution, "institution", "test_bank", "The test institution to pick in the flow")
+	fsc.cmd.Flags().StringSliceVar(&fsc.permissions, "permissions", []string{"balances", "ownership"}, "The data the accounts give access to: balances, ownership, payment_method, transactions")
+	fsc.cmd.Flags().BoolVar(&fsc.open, "open", false, "Open the flow in the browser")
+	fsc.cmd.Flags().StringVar(&fsc.publishableKey, "publishable-key", "", "The test mode publishable key running the flow (default: the one of the profile)")
+	fsc.cmd.Flags().DurationVar(&fsc.timeout, "timeout", 10*time.Minute, "Stop following the events after this long")
+	fsc.cmd.MarkFlagRequired("customer") // #nosec G104
+
+	// Hidden configuration flags, useful for dev/debugging
+	fsc.cmd.Flags().StringVar(&fsc.apiBaseURL, "api-base", stripe.DefaultAPIBaseURL, "Sets the API base URL")
+	fsc.cmd.Flags().MarkHidden("api-base") // #nosec G104
+
+	parentCmd.AddCommand(fsc.cmd)
+}
+
+func (fsc *FinancialConnectionsSimulateCmd) runSimulateCmd(cmd *cobra.Command, args []string) error {
+	institution, ok := financialconnections.Institutions[fsc.institution]
+	if !ok {
+		return clierrors.Errorf(clierrors.InvalidFlagValue, "unknown test institution %s, expected one of %s", fsc.institution, strings.Join(financialconnections.InstitutionNames(), ", "))
+	}
+
+	if fsc.timeout <= 0 {
+		return clierrors.New(clierrors.InvalidFlagValue, "--timeout must be a positive duration")
+	}
+
+	apiKey, err := fsc.cfg.Profile.GetAPIKey(false)
+	if err != nil {
+		return err
+	}
+
+	if strings.Contains(apiKey, "live") {
+		return clierrors.New(clierrors.LiveKeyNotAllowed, "financial_connections simulate only runs in test mode, but the API key is a live mode key")
+	}
+
+	publishableKey := fsc.publishableKey
+	if publishableKey == "" {
+		publishableKey, err = fsc.cfg.Profile.GetPublishableKey(false)
+		if err != nil {
+			return clierrors.New(clierrors.PublishableKeyNotConfigured, "the flow needs a test mode publishable key, run `stripe login` or pass --publishable-key")
+		}
+	}
+
+	base := requests.Base{APIBaseURL: fsc.apiBaseURL}
+	start := time.Now()
+
+	session, err := financialconnections.CreateSession(cmd.Context(), base, apiKey, fsc.customer, fsc.permissions)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), fsc.timeout)
+	defer cancel()
+
+	url, results, err := financialconnections.ServeFlow(ctx, publishableKey, session, institution)
+	if err != nil {
+		return err
+	}
+
+	color := ansi.Color(os.Stdout)
+	fmt.Printf("Created session %s. Link accounts at %s, picking the %s institution.\n", session.ID, color.Bold(url), color.Bold(institution))
+
+	if fsc.open {
+		if err := open.Browser(url); err != nil {
+			log.WithFields(log.Fields{
+				"prefix": "resource.FinancialConnectionsSimulateCmd.runSimulateCmd",
+			}).Debugf("Failed to open the browser: %v", err)
+		}
+	}
+
+	fmt.Println("Following the events of the linked accounts. Press Ctrl+C to stop.")
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case result := <-results:
+				if result.Error != "" {
+					fmt.Printf("%s The flow failed: %s\n", color.Red("✘"), result.Error)
+				} else {
+					fmt.Printf("%s Linked %d accounts: %s\n", color.Green("✔"), len(result.Accounts), strings.Join(result.Accounts, ", "))
+				}
+			}
+		}
+	}()
+
+	return financialconnections.FollowEvents(ctx, base, apiKey, fsc.customer, start.Add(-time.Second), func(event financialconnections.Event) {
+		fmt.Printf("%s  %s  %s  %s (%s)  %s\n", event.Created.Format("2006-01-02 15:04:05"), color.Bold(event.Type), event.Account, event.Institution, event.Status, event.ID)
+	})
+}
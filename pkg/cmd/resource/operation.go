@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
 	"github.com/stripe/stripe-cli/pkg/requests"
 	"github.com/stripe/stripe-cli/pkg/validators"
@@ -58,11 +59,11 @@ func (oc *OperationCmd) runOperationCmd(cmd *cobra.Command, args []string) error
 	for _, datum := range oc.data {
 		split := strings.SplitN(datum, "=", 2)
 		if len(split) < 2 {
-			return fmt.Errorf("Invalid data argument: %s", datum)
+			return clierrors.Errorf(clierrors.InvalidFlagValue, "Invalid data argument: %s", datum)
 		}
 
 		if _, ok := oc.stringFlags[split[0]]; ok {
-			return fmt.Errorf("Flag \"%s\" already set", split[0])
+			return clierrors.Errorf(clierrors.ConflictingFlags, "Flag \"%s\" already set", split[0])
 		}
 
 		flagParams = append(flagParams, datum)
@@ -70,6 +71,12 @@ func (oc *OperationCmd) runOperationCmd(cmd *cobra.Command, args []string) error
 
 	oc.Parameters.AppendData(flagParams)
 
+	if !oc.Profile.GetStrictAmounts() {
+		if err := oc.Parameters.ConvertAmounts(); err != nil {
+			return err
+		}
+	}
+
 	if oc.HTTPVerb == http.MethodDelete {
 		// display account information and confirm whether user wants to proceed
 		var mode = "Test"
@@ -114,6 +114,35 @@ func TestRunOperationCmd_ExtraParams(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestRunOperationCmd_HumanAmounts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		vals, err := url.ParseQuery(string(body))
+		require.NoError(t, err)
+		require.Equal(t, "1530", vals.Get("amount"))
+		require.Equal(t, "eur", vals.Get("currency"))
+		require.Equal(t, "200", vals.Get("application_fee_amount"))
+	}))
+	defer ts.Close()
+
+	viper.Reset()
+
+	parentCmd := &cobra.Command{Annotations: make(map[string]string)}
+	oc := NewOperationCmd(parentCmd, "create", "/v1/payment_intents", http.MethodPost, map[string]string{
+		"amount":   "integer",
+		"currency": "string",
+	}, &config.Config{
+		Profile: config.Profile{APIKey: "sk_test_1234"},
+	})
+	oc.APIBaseURL = ts.URL
+
+	parentCmd.SetArgs([]string{"create", "--amount", "15,30EUR", "--currency", "eur", "-d", "application_fee_amount=2 EUR"})
+	require.NoError(t, parentCmd.ExecuteContext(context.Background()))
+}
+
 func TestRunOperationCmd_NoAPIKey(t *testing.T) {
 	viper.Reset()
 
@@ -0,0 +1,31 @@
+package resource
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/config"
+)
+
+// AddPricesSubCmds adds custom subcommands to the `prices` command created
+// automatically as a resource command.
+func AddPricesSubCmds(rootCmd *cobra.Command, cfg *config.Config) error {
+	found := false
+
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Use == "prices" {
+			found = true
+
+			NewPricesSyncCmd(cmd, cfg)
+
+			break
+		}
+	}
+
+	if !found {
+		return errors.New("Could not find prices command")
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package resource
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/pricebook"
+	"github.com/stripe/stripe-cli/pkg/requests"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+// PricesSyncCmd reconciles the prices of the account with a price book
+type PricesSyncCmd struct {
+	cfg *config.Config
+	cmd *cobra.Command
+
+	createMissing  bool
+	archiveRemoved bool
+	dryRun         bool
+	livemode       bool
+	apiBaseURL     string
+}
+
+// NewPricesSyncCmd returns a new prices sync command
+func NewPricesSyncCmd(parentCmd *cobra.Command, cfg *config.Config) {
+	psc := &PricesSyncCmd{cfg: cfg}
+
+	psc.cmd = &cobra.Command{
+		Use:   "sync <pricebook.csv>",
+		Args:  validators.ExactArgs(1),
+		Short: "Reconcile the prices of your account with a price book",
+		Long: `Reconcile the prices of your account with a price book: a CSV file listing
+the prices that should be on sale, with the columns product, lookup_key,
+currency and unit_amount, and optionally interval, interval_count and nickname.
+The product is a product ID, or the name of a product. Amounts are decimal,
+such as 12.50, and prices without an interval are one-time prices.
+
+Prices are matched by lookup key. Since the amount of a price can't change, a
+price whose terms changed is replaced: a new price takes its lookup key and the
+previous one is archived. Prices without a lookup key are left alone.
+
+The planned changes are printed before being made. Prices missing from the
+account are only created with --create-missing, along with the products they
+name, and prices missing from the price book are only archived with
+--archive-removed. Use --dry-run to review the plan without changing anything.`,
+		Example: `stripe prices sync pricebook.csv --dry-run
+  stripe prices sync pricebook.csv --create-missing --archive-removed`,
+		RunE: psc.runPricesSyncCmd,
+	}
+
+	psc.cmd.Flags().BoolVar(&psc.createMissing, "create-missing", false, "Create the prices, and products, of the price book missing from the account")
+	psc.cmd.Flags().BoolVar(&psc.archiveRemoved, "archive-removed", false, "Archive the prices with a lookup key missing from the price book")
+	psc.cmd.Flags().BoolVar(&psc.dryRun, "dry-run", false, "Print the planned changes without making them")
+	psc.cmd.Flags().BoolVar(&psc.livemode, "live", false, "Sync the prices of live mode (default: test)")
+
+	// Hidden configuration flags, useful for dev/debugging
+	psc.cmd.Flags().StringVar(&psc.apiBaseURL, "api-base", stripe.DefaultAPIBaseURL, "Sets the API base URL")
+	psc.cmd.Flags().MarkHidden("api-base") // #nosec G104
+
+	parentCmd.AddCommand(psc.cmd)
+}
+
+func (psc *PricesSyncCmd) runPricesSyncCmd(cmd *cobra.Command, args []string) error {
+	file, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	rows, err := pricebook.ParseCSV(file)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	key, err := psc.cfg.Profile.GetAPIKey(psc.livemode)
+	if err != nil {
+		return err
+	}
+
+	var prices []pricebook.Price
+	if err := psc.list(cmd.Context(), key, "/v1/prices", &prices); err != nil {
+		return err
+	}
+
+	var products []pricebook.Product
+	if err := psc.list(cmd.Context(), key, "/v1/products", &products); err != nil {
+		return err
+	}
+
+	plan, err := pricebook.NewPlan(rows, prices, products, pricebook.Options{
+		CreateMissing:  psc.createMissing,
+		ArchiveRemoved: psc.archiveRemoved,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	printPricesPlan(os.Stdout, plan)
+
+	if psc.dryRun || len(plan.Changes) == 0 {
+		return nil
+	}
+
+	made, err := plan.Apply(cmd.Context(), psc.post(key))
+	if err != nil {
+		return fmt.Errorf("%w (%d of %d changes were made)", err, made, len(plan.Changes))
+	}
+
+	color := ansi.Color(os.Stdout)
+	fmt.Printf("%s Made %d changes\n", color.Green("✔"), made)
+
+	return nil
+}
+
+// list decodes all the active objects of a list endpoint into out, a pointer
+// to a slice
+func (psc *PricesSyncCmd) list(ctx context.Context, key, path string, out interface{}) error {
+	req := requests.Base{
+		Method:         http.MethodGet,
+		SuppressOutput: true,
+		APIBaseURL:     psc.apiBaseURL,
+		Livemode:       psc.livemode,
+	}
+
+	params := &requests.RequestParameters{}
+	params.AppendData([]string{"active=true"})
+
+	var buf bytes.Buffer
+
+	err := req.MakePaginatedRequest(ctx, key, path, params, requests.PaginationOptions{PageSize: 100, NDJSON: true}, &buf)
+	if err != nil {
+		return err
+	}
+
+	var objects []json.RawMessage
+
+	scanner := bufio.NewScanner(&buf)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		objects = append(objects, append(json.RawMessage(nil), scanner.Bytes()...))
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	list, err := json.Marshal(objects)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(list, out)
+}
+
+func (psc *PricesSyncCmd) post(key string) pricebook.Post {
+	return func(ctx context.Context, path string, data []string) ([]byte, error) {
+		req := requests.Base{
+			Method:         http.MethodPost,
+			SuppressOutput: true,
+			APIBaseURL:     psc.apiBaseURL,
+			Livemode:       psc.livemode,
+		}
+
+		params := &requests.RequestParameters{}
+		params.AppendData(data)
+
+		return req.MakeRequest(ctx, key, path, params, true)
+	}
+}
+
+// printPricesPlan prints the changes of the plan, the prices that won't be
+// created or archived without the flags to do so, and a summary
+func printPricesPlan(out io.Writer, plan *pricebook.Plan) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	counts := make(map[pricebook.Action]int)
+
+	for _, change := range plan.Changes {
+		counts[change.Action]++
+
+		switch change.Action {
+		case pricebook.ActionCreate:
+			product := change.ProductID
+			if product == "" {
+				product = fmt.Sprintf("new product %q", change.Row.Product)
+			}
+			fmt.Fprintf(w, "+ create\t%s\t%s\t%s\n", change.Row.LookupKey, change.Row.Terms(), product)
+		case pricebook.ActionReplace:
+			fmt.Fprintf(w, "~ replace\t%s\t%s -> %s\t%s\n", change.Row.LookupKey, change.Price.Terms(), change.Row.Terms(), change.Price.ID)
+		case pricebook.ActionRename:
+			fmt.Fprintf(w, "~ rename\t%s\t%q -> %q\t%s\n", change.Row.LookupKey, change.Price.Nickname, change.Row.Nickname, change.Price.ID)
+		case pricebook.ActionArchive:
+			fmt.Fprintf(w, "- archive\t%s\t%s\t%s\n", change.Price.LookupKey, change.Price.Terms(), change.Price.ID)
+		}
+	}
+
+	w.Flush()
+
+	for _, row := range plan.Missing {
+		fmt.Fprintf(out, "%s (line %d) is missing from the account, pass --create-missing to create it\n", row.LookupKey, row.Line)
+	}
+	for _, price := range plan.Removed {
+		fmt.Fprintf(out, "%s (%s) is missing from the price book, pass --archive-removed to archive it\n", price.LookupKey, price.ID)
+	}
+
+	fmt.Fprintf(out, "Plan: %d to create, %d to replace, %d to rename, %d to archive, %d unchanged\n",
+		counts[pricebook.ActionCreate], counts[pricebook.ActionReplace], counts[pricebook.ActionRename], counts[pricebook.ActionArchive], plan.Unchanged)
+}
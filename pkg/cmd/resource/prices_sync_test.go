@@ -0,0 +1,84 @@
+package resource
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/pricebook"
+)
+
+func TestRunPricesSyncCmd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pricebook.csv")
+	require.NoError(t, os.WriteFile(path, []byte("product,lookup_key,currency,unit_amount,interval\nprod_pro,pro_monthly,usd,12.50,month\n"), 0600))
+
+	var posts []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer sk_test_1234", r.Header.Get("Authorization"))
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/prices":
+			require.Equal(t, "true", r.URL.Query().Get("active"))
+			w.Write([]byte(`{"object": "list", "has_more": false, "data": [{"id": "price_old", "active": true, "currency": "usd", "lookup_key": "pro_monthly", "product": "prod_pro", "unit_amount": 1000, "recurring": {"interval": "month", "interval_count": 1}}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/products":
+			w.Write([]byte(`{"object": "list", "has_more": false, "data": [{"id": "prod_pro", "name": "Pro", "active": true}]}`))
+		case r.Method == http.MethodPost:
+			require.NoError(t, r.ParseForm())
+			posts = append(posts, r.URL.Path+"?"+r.PostForm.Encode())
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	viper.Reset()
+
+	run := func(args ...string) {
+		parentCmd := &cobra.Command{Annotations: make(map[string]string)}
+		NewPricesSyncCmd(parentCmd, &config.Config{Profile: config.Profile{APIKey: "sk_test_1234"}})
+
+		parentCmd.SetArgs(append([]string{"sync", path, "--api-base", ts.URL}, args...))
+		require.NoError(t, parentCmd.ExecuteContext(context.Background()))
+	}
+
+	run("--dry-run")
+	require.Empty(t, posts)
+
+	run()
+	require.Equal(t, []string{
+		"/v1/prices?currency=usd&lookup_key=pro_monthly&product=prod_pro&recurring%5Binterval%5D=month&recurring%5Binterval_count%5D=1&transfer_lookup_key=true&unit_amount=1250",
+		"/v1/prices/price_old?active=false",
+	}, posts)
+}
+
+func TestPrintPricesPlan(t *testing.T) {
+	plan := &pricebook.Plan{
+		Changes: []pricebook.Change{
+			{Action: pricebook.ActionCreate, Row: &pricebook.Row{Product: "Pro", LookupKey: "pro_yearly", Currency: "usd", UnitAmount: 12000, Interval: "year", IntervalCount: 1}},
+			{Action: pricebook.ActionReplace, Row: &pricebook.Row{LookupKey: "pro_monthly", Currency: "usd", UnitAmount: 1250, Interval: "month", IntervalCount: 1}, Price: &pricebook.Price{ID: "price_old", Currency: "usd", UnitAmount: 1000, Recurring: &pricebook.Recurring{Interval: "month", IntervalCount: 1}}},
+			{Action: pricebook.ActionArchive, Price: &pricebook.Price{ID: "price_legacy", LookupKey: "legacy", Currency: "eur", UnitAmount: 900}},
+		},
+		Missing:   []pricebook.Row{{Line: 7, LookupKey: "setup_fee"}},
+		Unchanged: 3,
+	}
+
+	var out bytes.Buffer
+	printPricesPlan(&out, plan)
+
+	require.Equal(t, `+ create   pro_yearly   usd 120.00 / year                       new product "Pro"
+~ replace  pro_monthly  usd 10.00 / month -> usd 12.50 / month  price_old
+- archive  legacy       eur 9.00                                price_legacy
+setup_fee (line 7) is missing from the account, pass --create-missing to create it
+Plan: 1 to create, 1 to replace, 0 to rename, 1 to archive, 3 unchanged
+`, out.String())
+}
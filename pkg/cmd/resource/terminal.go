@@ -19,6 +19,12 @@ func AddTerminalSubCmds(rootCmd *cobra.Command, cfg *config.Config) error {
 
 			NewQuickstartCmd(cmd, cfg)
 
+			for _, sub := range cmd.Commands() {
+				if sub.Use == "readers" {
+					NewReadersDiscoverCmd(sub, cfg)
+				}
+			}
+
 			break
 		}
 	}
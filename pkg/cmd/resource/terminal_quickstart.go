@@ -1,8 +1,6 @@
 package resource
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
 
 	"github.com/stripe/stripe-cli/pkg/config"
@@ -40,26 +38,26 @@ func (cc *QuickstartCmd) runQuickstartCmd(cmd *cobra.Command, args []string) err
 	key, err := cc.cfg.Profile.GetAPIKey(false)
 
 	if err != nil {
-		return fmt.Errorf(err.Error())
+		return err
 	}
 
 	err = validators.APIKeyNotRestricted(key)
 
 	if err != nil {
-		return fmt.Errorf(err.Error())
+		return err
 	}
 
 	readers := terminal.ReaderNames()
 	reader, err := terminal.ReaderTypeSelectPrompt(readers)
 
 	if err != nil {
-		return fmt.Errorf(err.Error())
+		return err
 	}
 
 	if reader == terminal.ReaderList["verifone-p400"].Name {
 		err = terminal.QuickstartP400(cmd.Context(), cc.cfg)
 		if err != nil {
-			return fmt.Errorf(err.Error())
+			return err
 		}
 	}
 
@@ -0,0 +1,261 @@
+package resource
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/requests"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+	"github.com/stripe/stripe-cli/pkg/terminal"
+	"github.com/stripe/stripe-cli/pkg/terminal/p400"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+// ReadersDiscoverCmd lists the Terminal readers of the account and the ones
+// on the local network, and diagnoses the connection to the smart readers
+type ReadersDiscoverCmd struct {
+	cfg *config.Config
+	cmd *cobra.Command
+
+	network    bool
+	subnets    []string
+	timeout    time.Duration
+	apiBaseURL string
+}
+
+// NewReadersDiscoverCmd returns a new terminal readers discover command
+func NewReadersDiscoverCmd(parentCmd *cobra.Command, cfg *config.Config) {
+	rdc := &ReadersDiscoverCmd{cfg: cfg}
+
+	rdc.cmd = &cobra.Command{
+		Use:   "discover",
+		Args:  validators.NoArgs,
+		Short: "Find Terminal readers and diagnose the connection to them",
+		Long: `List the Terminal readers registered to your account, with their firmware
+version and status, and diagnose the connection from this machine to the smart
+readers (BBPOS WisePOS E, Stripe Reader S700 and Verifone P400): resolving
+their hostname, connecting to them and the TLS handshake.
+
+With --network, the local network is scanned for smart readers too, including
+the ones that aren't registered to the account yet. Bluetooth and USB readers
+can't be diagnosed from the CLI.`,
+		Example: `stripe terminal readers discover
+  stripe terminal readers discover --network
+  stripe terminal readers discover --network --subnet 10.0.4.0/24`,
+		RunE: rdc.runReadersDiscoverCmd,
+	}
+
+	rdc.cmd.Flags().BoolVar(&rdc.network, "network", false, "Scan the local network for smart readers")
+	rdc.cmd.Flags().StringArrayVar(&rdc.subnets, "subnet", nil, "Scan this subnet instead of the ones of the network interfaces, e.g. 192.168.1.0/24")
+	rdc.cmd.Flags().DurationVar(&rdc.timeout, "timeout", time.Second, "How long to wait for each reader to answer")
+
+	// Hidden configuration flags, useful for dev/debugging
+	rdc.cmd.Flags().StringVar(&rdc.apiBaseURL, "api-base", stripe.DefaultAPIBaseURL, "Sets the API base URL")
+	rdc.cmd.Flags().MarkHidden("api-base") // #nosec G104
+
+	parentCmd.AddCommand(rdc.cmd)
+}
+
+func (rdc *ReadersDiscoverCmd) runReadersDiscoverCmd(cmd *cobra.Command, args []string) error {
+	if rdc.timeout <= 0 {
+		return clierrors.New(clierrors.InvalidFlagValue, "--timeout must be a positive duration")
+	}
+
+	var prefixes []netip.Prefix
+	for _, subnet := range rdc.subnets {
+		prefix, err := netip.ParsePrefix(subnet)
+		if err != nil || !prefix.Addr().Is4() || prefix.Bits() < 16 {
+			return clierrors.Errorf(clierrors.InvalidFlagValue, "invalid subnet %s, expected an IPv4 subnet of at most 65536 addresses like 192.168.1.0/24", subnet)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	key, err := rdc.cfg.Profile.GetAPIKey(false)
+	if err != nil {
+		return err
+	}
+
+	readers, err := rdc.listReaders(cmd.Context(), key)
+	if err != nil {
+		return err
+	}
+
+	scanner := terminal.NewScanner()
+	scanner.Timeout = rdc.timeout
+
+	var found []netip.Addr
+
+	if rdc.network {
+		hosts := terminal.SubnetHosts(prefixes)
+		if len(prefixes) == 0 {
+			hosts, err = terminal.LocalHosts()
+			if err != nil {
+				return err
+			}
+		}
+
+		fmt.Fprintf(os.Stderr, "Scanning %d addresses for smart readers...\n", len(hosts))
+
+		found = scanner.Scan(cmd.Context(), hosts)
+	}
+
+	networkReaders := diagnoseReaders(cmd.Context(), scanner, readers, found)
+
+	printReaders(os.Stdout, readers, networkReaders)
+
+	unhealthy := 0
+	for _, reader := range networkReaders {
+		if !reader.Healthy() {
+			unhealthy++
+		}
+	}
+
+	if unhealthy > 0 {
+		return clierrors.Errorf(clierrors.ReaderUnreachable, "the connection diagnostic failed for %d readers", unhealthy)
+	}
+
+	return nil
+}
+
+// listReaders returns all the readers registered to the account
+func (rdc *ReadersDiscoverCmd) listReaders(ctx context.Context, key string) ([]p400.Reader, error) {
+	req := requests.Base{
+		Method:         http.MethodGet,
+		SuppressOutput: true,
+		APIBaseURL:     rdc.apiBaseURL,
+	}
+
+	var out bytes.Buffer
+
+	err := req.MakePaginatedRequest(ctx, key, "/v1/terminal/readers", &requests.RequestParameters{}, requests.PaginationOptions{PageSize: 100, NDJSON: true}, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	var readers []p400.Reader
+
+	scanner := bufio.NewScanner(&out)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var reader p400.Reader
+		if err := json.Unmarshal(scanner.Bytes(), &reader); err != nil {
+			return nil, err
+		}
+		readers = append(readers, reader)
+	}
+
+	return readers, scanner.Err()
+}
+
+// diagnoseReaders diagnoses the connection to the smart readers registered
+// with an IP address and to the ones found on the network, by IP address
+func diagnoseReaders(ctx context.Context, scanner *terminal.Scanner, readers []p400.Reader, found []netip.Addr) []terminal.NetworkReader {
+	byIP := make(map[string]*terminal.NetworkReader)
+	var ips []string
+
+	add := func(ip string) *terminal.NetworkReader {
+		if byIP[ip] == nil {
+			byIP[ip] = &terminal.NetworkReader{IPAddress: ip}
+			ips = append(ips, ip)
+		}
+		return byIP[ip]
+	}
+
+	for i, reader := range readers {
+		if terminal.IsNetworkReader(reader.DeviceType) && reader.IPAddress != "" {
+			add(reader.IPAddress).Reader = &readers[i]
+		}
+	}
+
+	for _, addr := range found {
+		add(addr.String())
+	}
+
+	sort.Slice(ips, func(i, j int) bool {
+		a, errA := netip.ParseAddr(ips[i])
+		b, errB := netip.ParseAddr(ips[j])
+		if errA != nil || errB != nil {
+			return ips[i] < ips[j]
+		}
+		return a.Less(b)
+	})
+
+	networkReaders := make([]terminal.NetworkReader, 0, len(ips))
+
+	for _, ip := range ips {
+		reader := byIP[ip]
+		reader.Checks = scanner.Diagnose(ctx, ip)
+
+		if reader.Reader != nil {
+			stripeCheck := terminal.Check{Name: "Stripe", OK: reader.Reader.Status == "online", Detail: "the reader is online"}
+			if !stripeCheck.OK {
+				stripeCheck.Detail = "the reader is offline according to Stripe, it can't reach Stripe's servers"
+			}
+			reader.Checks = append(reader.Checks, stripeCheck)
+		}
+
+		networkReaders = append(networkReaders, *reader)
+	}
+
+	return networkReaders
+}
+
+func printReaders(out io.Writer, readers []p400.Reader, networkReaders []terminal.NetworkReader) {
+	if len(readers) == 0 {
+		fmt.Fprintln(out, "No readers are registered to the account.")
+	} else {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tLABEL\tDEVICE TYPE\tSERIAL NUMBER\tFIRMWARE\tIP ADDRESS\tSTATUS")
+		for _, reader := range readers {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", reader.ID, dashIfEmpty(reader.Label), reader.DeviceType, dashIfEmpty(reader.SerialNumber),
+				dashIfEmpty(reader.DeviceSwVersion), dashIfEmpty(reader.IPAddress), dashIfEmpty(reader.Status))
+		}
+		w.Flush()
+	}
+
+	color := ansi.Color(out)
+
+	for _, reader := range networkReaders {
+		name := "unregistered reader"
+		if reader.Reader != nil {
+			name = reader.Reader.ID
+		}
+
+		status := color.Green("OK").String()
+		if !reader.Healthy() {
+			status = color.Red("FAILED").String()
+		}
+
+		fmt.Fprintf(out, "\nDiagnostic of %s at %s: %s\n", name, reader.IPAddress, status)
+		for _, check := range reader.Checks {
+			mark := color.Green("✔").String()
+			if !check.OK {
+				mark = color.Red("✘").String()
+			}
+			fmt.Fprintf(out, "  %s %s: %s\n", mark, check.Name, check.Detail)
+		}
+	}
+}
+
+func dashIfEmpty(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"runtime/debug"
 	"strings"
 	"unicode"
 
@@ -53,6 +54,14 @@ var rootCmd = &cobra.Command{
 		getLogin(&fs, &Config),
 	),
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// the state shared through a backend, if any, includes the config, so
+		// it's pulled once the flags selecting the config are parsed
+		pullState(cmd.Context())
+
+		printProfileBinding(os.Stderr, cmd)
+		configureTelemetry(cmd)
+		configurePasskey(cmd)
+
 		// if getting the config errors, don't fail running the command
 		merchant, _ := Config.Profile.GetAccountID()
 		telemetryMetadata := stripe.GetEventMetadata(cmd.Context())
@@ -68,6 +77,30 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// configureTelemetry turns telemetry off if the user opted out, and keeps a
+// local copy of the events of the command for `stripe telemetry show`
+func configureTelemetry(cmd *cobra.Command) {
+	if !Config.TelemetryEnabled() {
+		// plugins run in their own process and read the opt-out from the
+		// environment
+		os.Setenv("STRIPE_CLI_TELEMETRY_OPTOUT", "1")
+	}
+
+	client, ok := stripe.GetTelemetryClient(cmd.Context()).(*stripe.AnalyticsTelemetryClient)
+	if !ok {
+		return
+	}
+
+	if !Config.TelemetryEnabled() {
+		client.Disabled = true
+	}
+
+	// inspecting the log mustn't replace the events being inspected
+	if !isTelemetryCommand(cmd) {
+		client.Log = stripe.NewTelemetryLog(fs, telemetryLogPath())
+	}
+}
+
 func sendCommandInvocationEvent(ctx context.Context) {
 	telemetryClient := stripe.GetTelemetryClient(ctx)
 	if telemetryClient != nil {
@@ -91,27 +124,43 @@ func showSuggestion() {
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			recoverFromPanic(r, debug.Stack())
+		}
+	}()
+
 	telemetryMetadata := stripe.NewEventMetadata()
 	updatedCtx := stripe.WithEventMetadata(ctx, telemetryMetadata)
 
 	rootCmd.SetUsageTemplate(getUsageTemplate())
 	rootCmd.SetVersionTemplate(version.Template)
-	if err := rootCmd.ExecuteContext(updatedCtx); err != nil {
+	if executedCmd, err := rootCmd.ExecuteContextC(updatedCtx); err != nil {
 		errString := err.Error()
 
-		isLoginRequiredError := errString == validators.ErrAPIKeyNotConfigured.Error() || errString == validators.ErrDeviceNameNotConfigured.Error()
+		isLoginRequiredError := errString == validators.ErrAPIKeyNotConfigured.Error() || errString == validators.ErrDeviceNameNotConfigured.Error() || errString == validators.ErrProfileExpired.Error()
 		projectNameFlag := rootCmd.Flag("project-name").Value.String()
 
+		// capitalize first letter of error because linter
+		errRunes := []rune(errString)
+		if len(errRunes) > 0 {
+			errRunes[0] = unicode.ToUpper(errRunes[0])
+		}
+
 		switch {
+		case jsonErrors(executedCmd):
+			// wrappers parse the failure rather than the message
+			printJSONError(os.Stdout, err)
 		case requests.IsAPIKeyExpiredError(err):
 			fmt.Fprintln(os.Stderr, "The API key provided has expired. Obtain a new key from the Dashboard or run `stripe login` and try again.")
+		case isLoginRequiredError && login.IsCI():
+			// nobody would answer the login prompts of a CI job
+			fmt.Fprintf(os.Stderr, "%s. Set STRIPE_API_KEY in the job's environment, or run `stripe login --ci`.\n", string(errRunes))
+		case isLoginRequiredError && profileBindingPath != "":
+			fmt.Printf("No config was found for profile %s, bound by %s. Please run `stripe login --project-name=%s`, or select another profile with `stripe profile bind`.\n", Config.Profile.ProfileName, profileBindingPath, Config.Profile.ProfileName)
 		case isLoginRequiredError && projectNameFlag != "default":
 			fmt.Println("You provided the \"--project-name\" flag, but no config for that project was found. Please run `stripe login --project-name=`...")
 		case isLoginRequiredError:
-			// capitalize first letter of error because linter
-			errRunes := []rune(errString)
-			errRunes[0] = unicode.ToUpper(errRunes[0])
-
 			fmt.Printf("%s. Running `stripe login`...\n", string(errRunes))
 
 			err = login.Login(updatedCtx, stripe.DefaultDashboardBaseURL, &Config, os.Stdin)
@@ -127,8 +176,15 @@ func Execute(ctx context.Context) {
 			fmt.Println(err)
 		}
 
+		if verboseErrors(executedCmd) {
+			newEnvSnapshot(executedCmd, &Config).Print(os.Stderr)
+		}
+
+		pushState(stateSession)
 		os.Exit(1)
 	} else {
+		pushState(stateSession)
+
 		userInput := os.Args[1:]
 		// --color on/off/auto
 		if len(userInput) == 2 && userInput[0] == "--color" {
@@ -138,7 +194,7 @@ func Execute(ctx context.Context) {
 }
 
 func init() {
-	cobra.OnInitialize(Config.InitConfig)
+	cobra.OnInitialize(applyProfileBinding, Config.InitConfig)
 
 	rootCmd.PersistentFlags().StringVar(&Config.Profile.APIKey, "api-key", "", "Your API key to use for the command")
 	rootCmd.PersistentFlags().StringVar(&Config.Color, "color", "", "turn on/off color output (on, off, auto)")
@@ -146,29 +202,47 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&Config.Profile.DeviceName, "device-name", "", "device name")
 	rootCmd.PersistentFlags().StringVar(&Config.LogLevel, "log-level", "info", "log level (debug, info, trace, warn, error)")
 	rootCmd.PersistentFlags().StringVarP(&Config.Profile.ProfileName, "project-name", "p", "default", "the project name to read from for config")
+	rootCmd.PersistentFlags().BoolVar(&Config.NoTelemetry, "no-telemetry", false, "Don't send usage telemetry to Stripe")
+	rootCmd.PersistentFlags().Bool("strict-amounts", false, "Send amounts as written, rather than converting amounts like '$20.00' or 15,30EUR to the smallest currency unit")
+	rootCmd.PersistentFlags().Bool("git-metadata", false, "Tag the objects created by trigger and fixtures, and the summary of listen sessions, with the git branch and commit checked out")
+	rootCmd.PersistentFlags().Bool("verbose", false, "Print the environment the command ran in along with its errors")
 	rootCmd.Flags().BoolP("version", "v", false, "Get the version of the Stripe CLI")
 
 	viper.BindPFlag("color", rootCmd.PersistentFlags().Lookup("color"))
+	viper.BindPFlag("strict-amounts", rootCmd.PersistentFlags().Lookup("strict-amounts"))
+	viper.BindPFlag("git-metadata", rootCmd.PersistentFlags().Lookup("git-metadata"))
 
+	rootCmd.AddCommand(newCacheCmd().cmd)
+	rootCmd.AddCommand(newCleanupCmd().cmd)
 	rootCmd.AddCommand(newCompletionCmd().cmd)
 	rootCmd.AddCommand(newConfigCmd().cmd)
 	rootCmd.AddCommand(newDaemonCmd(&Config).cmd)
 	rootCmd.AddCommand(newDeleteCmd().reqs.Cmd)
+	rootCmd.AddCommand(newDevcontainerCmd().cmd)
+	rootCmd.AddCommand(newErrorsCmd().cmd)
 	rootCmd.AddCommand(newFeedbackdCmd().cmd)
 	rootCmd.AddCommand(newFixturesCmd(&Config).Cmd)
 	rootCmd.AddCommand(newGetCmd().reqs.Cmd)
+	rootCmd.AddCommand(newLimitsCmd().cmd)
+	rootCmd.AddCommand(newLintIntegrationCmd().cmd)
 	rootCmd.AddCommand(newListenCmd().cmd)
 	rootCmd.AddCommand(newLoginCmd().cmd)
 	rootCmd.AddCommand(newLogoutCmd().cmd)
 	rootCmd.AddCommand(newLogsCmd(&Config).Cmd)
 	rootCmd.AddCommand(newOpenCmd().cmd)
+	rootCmd.AddCommand(newPasskeyCmd().cmd)
 	rootCmd.AddCommand(newPostCmd().reqs.Cmd)
+	rootCmd.AddCommand(newProfileCmd().cmd)
+	rootCmd.AddCommand(newReplayProfileCmd().cmd)
 	rootCmd.AddCommand(newResourcesCmd().cmd)
 	rootCmd.AddCommand(newSamplesCmd().cmd)
+	rootCmd.AddCommand(newScheduleCmd().cmd)
 	rootCmd.AddCommand(newServeCmd().cmd)
 	rootCmd.AddCommand(newStatusCmd().cmd)
+	rootCmd.AddCommand(newTelemetryCmd().cmd)
 	rootCmd.AddCommand(newTriggerCmd().cmd)
 	rootCmd.AddCommand(newVersionCmd().cmd)
+	rootCmd.AddCommand(newWebhooksCmd().Cmd)
 	rootCmd.AddCommand(newPostinstallCmd(&Config).cmd)
 	rootCmd.AddCommand(newCommunityCmd().cmd)
 	rootCmd.AddCommand(newPluginCmd().cmd)
@@ -190,21 +264,44 @@ func init() {
 		log.Fatal(err)
 	}
 
+	err = resource.AddFinancialConnectionsSubCmds(rootCmd, &Config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = resource.AddPricesSubCmds(rootCmd, &Config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// remove autogenerated apps command
 	resource.RemoveAppsCmd(rootCmd)
 
 	// config is not initialized by cobra at this point, so we need to temporarily initialize it
 	Config.InitConfig()
 
+	// the state shared through a backend is only pulled once the flags are
+	// parsed, so the plugins listed here are the ones of the local config.
 	// get a list of installed plugins, validate against the manifest
-	// and finally add each validated plugin as a command
+	// and finally add each validated plugin as a command, along with any
+	// triggers and fixture step types it contributes
 	nfs := afero.NewOsFs()
 	pluginList := Config.GetInstalledPlugins()
+	var installedPlugins []plugins.Plugin
 
 	for _, p := range pluginList {
 		plugin, err := plugins.LookUpPlugin(context.Background(), &Config, nfs, p)
 		if err == nil {
 			rootCmd.AddCommand(newPluginTemplateCmd(&Config, &plugin).cmd)
+			registerPluginFixtures(&Config, &plugin)
+			installedPlugins = append(installedPlugins, plugin)
 		}
 	}
+
+	// aliases are added last so they can't replace a command, and the ones
+	// of the config file take precedence over the ones of plugins
+	addAliasCmds(rootCmd, Config.GetAliases(), "config file")
+	for _, plugin := range installedPlugins {
+		addAliasCmds(rootCmd, plugin.Aliases, fmt.Sprintf("plugin %s", plugin.Shortname))
+	}
 }
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/schedule"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+type scheduleCmd struct {
+	cmd *cobra.Command
+}
+
+func newScheduleCmd() *scheduleCmd {
+	sc := &scheduleCmd{}
+
+	sc.cmd = &cobra.Command{
+		Use:   "schedule",
+		Args:  validators.NoArgs,
+		Short: "Run stripe commands on a recurring schedule",
+		Long: `Run stripe commands on a recurring schedule, described by a cron expression,
+with the credentials of the profile they were scheduled with. The jobs run
+while stripe daemon is running, and their output is appended to a log file per
+job in the config folder.`,
+		Example: `stripe schedule add "0 9 * * 1" -- stripe fixtures weekly-seed.json
+  stripe schedule add @hourly -- stripe get /v1/balance
+  stripe schedule list
+  stripe schedule remove job_1a2b3c4d`,
+	}
+
+	sc.cmd.AddCommand(newScheduleAddCmd().cmd)
+	sc.cmd.AddCommand(newScheduleListCmd().cmd)
+	sc.cmd.AddCommand(newScheduleRemoveCmd().cmd)
+
+	return sc
+}
+
+type scheduleAddCmd struct {
+	cmd *cobra.Command
+}
+
+func newScheduleAddCmd() *scheduleAddCmd {
+	ac := &scheduleAddCmd{}
+
+	ac.cmd = &cobra.Command{
+		Use:   `add "<cron expression>" -- stripe <command>`,
+		Short: "Schedule a stripe command",
+		Long: `Schedule a stripe command with a cron expression of five fields: minute, hour,
+day of month, month and day of week, in the local time zone. Fields accept
+lists, ranges and steps, and @hourly, @daily, @weekly and @monthly can be used
+instead.`,
+		Example: `stripe schedule add "0 9 * * 1" -- stripe fixtures weekly-seed.json
+  stripe schedule add "*/15 * * * *" -- stripe get /v1/balance`,
+		RunE: ac.runScheduleAddCmd,
+	}
+
+	return ac
+}
+
+func (ac *scheduleAddCmd) runScheduleAddCmd(cmd *cobra.Command, args []string) error {
+	dash := cmd.ArgsLenAtDash()
+	if dash != 1 || len(args) == dash {
+		return clierrors.New(clierrors.InvalidArgs, `expected a cron expression and the command to run after --, as in: stripe schedule add "0 9 * * 1" -- stripe fixtures seed.json`)
+	}
+
+	commandArgs := args[dash:]
+	if commandArgs[0] == "stripe" {
+		commandArgs = commandArgs[1:]
+	}
+
+	if err := validateScheduledCommand(commandArgs); err != nil {
+		return err
+	}
+
+	job, err := scheduleStore().Add(args[0], commandArgs, Config.Profile.ProfileName)
+	if err != nil {
+		return err
+	}
+
+	color := ansi.Color(os.Stdout)
+	cron, _ := job.Cron()
+	fmt.Printf("%s Scheduled %s as %s, next run at %s\n", color.Green("✔"), job.Command(), color.Bold(job.ID), formatNextRun(cron, time.Now()))
+	fmt.Printf("It runs while stripe daemon is running, logging to %s\n", schedule.LogPath(scheduleLogDir(), job.ID))
+
+	return nil
+}
+
+// validateScheduledCommand checks that the arguments are those of a stripe
+// command other than the daemon running them
+func validateScheduledCommand(args []string) error {
+	if len(args) == 0 {
+		return clierrors.New(clierrors.InvalidArgs, "the command to schedule is missing")
+	}
+
+	found, _, err := rootCmd.Find(args)
+	if err != nil || found == rootCmd {
+		return clierrors.Errorf(clierrors.CommandNotSchedulable, "stripe %s is not a stripe command, only stripe commands can be scheduled", strings.Join(args, " "))
+	}
+
+	if found.Name() == "daemon" || found.Name() == "schedule" || (found.Parent() != nil && found.Parent().Name() == "schedule") {
+		return clierrors.Errorf(clierrors.CommandNotSchedulable, "%s can't be scheduled", found.CommandPath())
+	}
+
+	return nil
+}
+
+type scheduleListCmd struct {
+	cmd *cobra.Command
+}
+
+func newScheduleListCmd() *scheduleListCmd {
+	lc := &scheduleListCmd{}
+
+	lc.cmd = &cobra.Command{
+		Use:   "list",
+		Args:  validators.NoArgs,
+		Short: "List the scheduled commands",
+		RunE:  lc.runScheduleListCmd,
+	}
+
+	return lc
+}
+
+func (lc *scheduleListCmd) runScheduleListCmd(cmd *cobra.Command, args []string) error {
+	jobs, err := scheduleStore().Jobs()
+	if err != nil {
+		return err
+	}
+
+	printScheduledJobs(os.Stdout, jobs, time.Now())
+
+	return nil
+}
+
+type scheduleRemoveCmd struct {
+	cmd *cobra.Command
+}
+
+func newScheduleRemoveCmd() *scheduleRemoveCmd {
+	rc := &scheduleRemoveCmd{}
+
+	rc.cmd = &cobra.Command{
+		Use:   "remove <id>",
+		Args:  validators.ExactArgs(1),
+		Short: "Unschedule a command",
+		RunE:  rc.runScheduleRemoveCmd,
+	}
+
+	return rc
+}
+
+func (rc *scheduleRemoveCmd) runScheduleRemoveCmd(cmd *cobra.Command, args []string) error {
+	if err := scheduleStore().Remove(args[0]); err != nil {
+		return err
+	}
+
+	color := ansi.Color(os.Stdout)
+	fmt.Printf("%s Removed %s\n", color.Green("✔"), args[0])
+
+	return nil
+}
+
+func printScheduledJobs(out io.Writer, jobs []schedule.Job, now time.Time) {
+	if len(jobs) == 0 {
+		fmt.Fprintln(out, "No commands are scheduled.")
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSCHEDULE\tPROFILE\tNEXT RUN\tLAST RUN\tCOMMAND")
+
+	for _, job := range jobs {
+		next := "invalid schedule"
+		if cron, err := job.Cron(); err == nil {
+			next = formatNextRun(cron, now)
+		}
+
+		last := "never"
+		if !job.LastRun.IsZero() {
+			last = job.LastRun.Local().Format(timeLayout)
+			if job.LastError != "" {
+				last += " (failed: " + job.LastError + ")"
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", job.ID, job.Schedule, job.Profile, next, last, job.Command())
+	}
+	w.Flush()
+}
+
+func formatNextRun(cron *schedule.Cron, now time.Time) string {
+	next := cron.Next(now)
+	if next.IsZero() {
+		return "never"
+	}
+
+	return next.Format(timeLayout)
+}
+
+func scheduleStore() *schedule.Store {
+	return &schedule.Store{
+		Fs:   fs,
+		Path: filepath.Join(Config.GetConfigFolder(os.Getenv("XDG_CONFIG_HOME")), schedule.FileName),
+	}
+}
+
+func scheduleLogDir() string {
+	return filepath.Join(Config.GetConfigFolder(os.Getenv("XDG_CONFIG_HOME")), "schedule-logs")
+}
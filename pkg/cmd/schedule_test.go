@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/schedule"
+)
+
+func TestValidateScheduledCommand(t *testing.T) {
+	require.NoError(t, validateScheduledCommand([]string{"get", "/v1/balance"}))
+	require.NoError(t, validateScheduledCommand([]string{"fixtures", "seed.json", "--override", "customer:email=ci@example.com"}))
+
+	require.EqualError(t, validateScheduledCommand([]string{"not-a-command"}), "stripe not-a-command is not a stripe command, only stripe commands can be scheduled")
+	require.EqualError(t, validateScheduledCommand([]string{"daemon"}), "stripe daemon can't be scheduled")
+	require.EqualError(t, validateScheduledCommand([]string{"schedule", "list"}), "stripe schedule list can't be scheduled")
+	require.Error(t, validateScheduledCommand(nil))
+}
+
+func TestPrintScheduledJobs(t *testing.T) {
+	var out bytes.Buffer
+	printScheduledJobs(&out, nil, time.Now())
+	require.Equal(t, "No commands are scheduled.\n", out.String())
+
+	now := time.Date(2022, 8, 3, 14, 27, 0, 0, time.Local)
+	jobs := []schedule.Job{
+		{ID: "job_1a2b3c4d", Schedule: "0 9 * * 1", Args: []string{"fixtures", "seed.json"}, Profile: "default"},
+		{ID: "job_5e6f7a8b", Schedule: "@hourly", Args: []string{"get", "/v1/balance"}, Profile: "ci", LastRun: now.Add(-27 * time.Minute), LastError: "exit status 1"},
+	}
+
+	out.Reset()
+	printScheduledJobs(&out, jobs, now)
+
+	require.Contains(t, out.String(), "job_1a2b3c4d  0 9 * * 1  default  2022-08-08 09:00:00  never")
+	require.Contains(t, out.String(), "2022-08-03 15:00:00  2022-08-03 14:00:00 (failed: exit status 1)  stripe get /v1/balance")
+}
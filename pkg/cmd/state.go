@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/stripe/stripe-cli/pkg/storage"
+)
+
+// statePushTimeout is how long to wait for other sessions to finish pushing
+// their state before giving up on pushing ours
+const statePushTimeout = 30 * time.Second
+
+// stateSession is the state pulled from a backend before the command ran, to
+// push back once it's done
+var stateSession *storage.Session
+
+// pullState pulls the CLI state into the config folder from the backend set
+// in STRIPE_CLI_STATE_BACKEND, if any, and reloads the config. When the state
+// can't be pulled, the command runs with the local state instead, so that a
+// backend that's down doesn't keep the config from being fixed.
+func pullState(ctx context.Context) {
+	rawURL := os.Getenv(storage.BackendEnv)
+	if rawURL == "" {
+		return
+	}
+
+	backend, err := storage.Open(rawURL, fs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s. Using the local state\n", err)
+		return
+	}
+
+	session, err := storage.Pull(ctx, backend, fs, Config.GetConfigFolder(os.Getenv("XDG_CONFIG_HOME")), Config.ProfilesFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s. Using the local state\n", err)
+		return
+	}
+
+	stateSession = session
+	Config.InitConfig()
+}
+
+// pushState pushes the state changed by the command back to its backend
+func pushState(session *storage.Session) {
+	if session == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statePushTimeout)
+	defer cancel()
+
+	if err := session.Push(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+	}
+}
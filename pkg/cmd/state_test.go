@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/storage"
+)
+
+func TestPullStateFailureUsesLocalState(t *testing.T) {
+	defer func(session *storage.Session) { stateSession = session }(stateSession)
+	stateSession = nil
+
+	t.Setenv(storage.BackendEnv, "ftp://bucket/prefix")
+	pullState(context.Background())
+	require.Nil(t, stateSession)
+}
+
+func TestPullStateIntoConfigFile(t *testing.T) {
+	defer func(session *storage.Session, profilesFile string) {
+		stateSession, Config.ProfilesFile = session, profilesFile
+	}(stateSession, Config.ProfilesFile)
+
+	bucket := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bucket, "config.toml"), []byte("color = 'off'\n"), 0600))
+
+	t.Setenv(storage.BackendEnv, bucket)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	Config.ProfilesFile = filepath.Join(t.TempDir(), "stripe.toml")
+
+	pullState(context.Background())
+	require.NotNil(t, stateSession)
+
+	data, err := os.ReadFile(Config.ProfilesFile)
+	require.NoError(t, err)
+	require.Equal(t, "color = 'off'\n", string(data))
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/status"
 	"github.com/stripe/stripe-cli/pkg/validators"
 	"github.com/stripe/stripe-cli/pkg/version"
@@ -29,6 +30,8 @@ func newStatusCmd() *statusCmd {
 		Use:   "status",
 		Args:  validators.NoArgs,
 		Short: "Check the status of the Stripe API",
+		Long: `Check the status of the Stripe API. With --verbose, the status of all Stripe
+systems is shown.`,
 		Example: `stripe status
   stripe status --poll
   stripe status --poll --verbose`,
@@ -50,11 +53,11 @@ func (sc *statusCmd) runStatusCmd(cmd *cobra.Command, args []string) error {
 	}
 
 	if sc.pollRate < 5 {
-		return fmt.Errorf("poll-rate must be at least 5 seconds, received %d", sc.pollRate)
+		return clierrors.Errorf(clierrors.InvalidFlagValue, "poll-rate must be at least 5 seconds, received %d", sc.pollRate)
 	}
 
 	if sc.format != "default" && sc.format != "json" {
-		return fmt.Errorf("invalid format, must be one of 'default' or 'json', received %s", sc.format)
+		return clierrors.Errorf(clierrors.InvalidFlagValue, "invalid format, must be one of 'default' or 'json', received %s", sc.format)
 	}
 
 	for {
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+type telemetryCmd struct {
+	cmd *cobra.Command
+}
+
+func newTelemetryCmd() *telemetryCmd {
+	tc := &telemetryCmd{}
+
+	tc.cmd = &cobra.Command{
+		Use:   "telemetry",
+		Args:  validators.NoArgs,
+		Short: "Inspect the usage telemetry sent to Stripe",
+		Long: `The CLI sends usage telemetry to Stripe, such as which commands are run. You
+can turn it off for a single command with --no-telemetry, or for every command
+with:
+
+  stripe config --set telemetry off
+
+Setting the STRIPE_CLI_TELEMETRY_OPTOUT or DO_NOT_TRACK environment variables
+also turns it off.`,
+		Example: `stripe telemetry show`,
+	}
+
+	tc.cmd.AddCommand(newTelemetryShowCmd().cmd)
+
+	return tc
+}
+
+type telemetryShowCmd struct {
+	cmd *cobra.Command
+}
+
+func newTelemetryShowCmd() *telemetryShowCmd {
+	sc := &telemetryShowCmd{}
+
+	sc.cmd = &cobra.Command{
+		Use:   "show",
+		Args:  validators.NoArgs,
+		Short: "Show the telemetry events of the last command",
+		Long: `Show exactly which telemetry events the last command sent, or would have sent
+if telemetry was turned off.`,
+		RunE: sc.runTelemetryShowCmd,
+	}
+
+	return sc
+}
+
+func (sc *telemetryShowCmd) runTelemetryShowCmd(cmd *cobra.Command, args []string) error {
+	if Config.TelemetryEnabled() {
+		fmt.Println("Telemetry is on.")
+	} else {
+		fmt.Println("Telemetry is off.")
+	}
+
+	events, err := stripe.ReadTelemetryLog(fs, telemetryLogPath())
+	if os.IsNotExist(err) || (err == nil && len(events) == 0) {
+		fmt.Println("No telemetry events were recorded yet.")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	color := ansi.Color(os.Stdout)
+
+	fmt.Printf("The last command produced %d event(s):\n", len(events))
+	for _, event := range events {
+		status := color.Green("sent")
+		if !event.Sent {
+			status = color.Yellow("not sent")
+		}
+
+		fmt.Printf("\n%s (%s)\n", ansi.Bold(event.Fields["event_name"]), status)
+		for _, name := range event.FieldNames() {
+			fmt.Printf("  %s: %s\n", name, event.Fields[name])
+		}
+	}
+
+	return nil
+}
+
+// isTelemetryCommand returns whether cmd is `stripe telemetry` or one of its
+// subcommands
+func isTelemetryCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Name() == "telemetry" && c.HasParent() && !c.Parent().HasParent() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// telemetryLogPath returns where the telemetry events of the last command are
+// kept
+func telemetryLogPath() string {
+	return filepath.Join(Config.GetConfigFolder(os.Getenv("XDG_CONFIG_HOME")), "telemetry.ndjson")
+}
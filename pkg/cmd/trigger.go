@@ -7,7 +7,11 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/config"
 	"github.com/stripe/stripe-cli/pkg/fixtures"
+	"github.com/stripe/stripe-cli/pkg/git"
+	"github.com/stripe/stripe-cli/pkg/guardrails"
+	"github.com/stripe/stripe-cli/pkg/plugins"
 	"github.com/stripe/stripe-cli/pkg/stripe"
 	"github.com/stripe/stripe-cli/pkg/validators"
 	"github.com/stripe/stripe-cli/pkg/version"
@@ -25,20 +29,28 @@ type triggerCmd struct {
 	remove        []string
 	raw           string
 	apiBaseURL    string
+	list          bool
+	livemode      bool
 }
 
 func newTriggerCmd() *triggerCmd {
 	tc := &triggerCmd{}
 	tc.fs = afero.NewOsFs()
 	tc.cmd = &cobra.Command{
-		Use:       "trigger <event>",
-		Args:      validators.MaximumNArgs(1),
-		ValidArgs: fixtures.EventNames(),
-		Short:     "Trigger test webhook events",
+		Use:   "trigger <event>",
+		Args:  validators.MaximumNArgs(1),
+		Short: "Trigger test webhook events",
 		Long: fmt.Sprintf(`Trigger specific webhook events to be sent. Webhooks events created through
 the trigger command will also create all necessary side-effect events that are
 needed to create the triggered event as well as the corresponding API objects.
 
+Triggering events with a live mode key, such as one set in STRIPE_API_KEY,
+must be confirmed unless --live is passed.
+
+With --git-metadata, or the git_metadata setting of the profile, the objects
+created from a git repository are tagged with the git_branch and git_commit
+metadata, so that they can be deleted later with stripe cleanup.
+
 %s
 %s
 `,
@@ -47,6 +59,11 @@ needed to create the triggered event as well as the corresponding API objects.
 		),
 		Example: `stripe trigger payment_intent.created`,
 		RunE:    tc.runTriggerCmd,
+		// plugins register their triggers after this command is built, so
+		// completions need to be computed lazily
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return fixtures.EventNames(), cobra.ShellCompDirectiveNoFileComp
+		},
 	}
 
 	tc.cmd.Flags().StringVar(&tc.stripeAccount, "stripe-account", "", "Set a header identifying the connected account")
@@ -56,6 +73,8 @@ needed to create the triggered event as well as the corresponding API objects.
 	tc.cmd.Flags().StringArrayVar(&tc.remove, "remove", []string{}, "Remove params from the trigger")
 	tc.cmd.Flags().StringVar(&tc.raw, "raw", "", "Raw fixture in string format to replace all default fixtures")
 	tc.cmd.Flags().StringVar(&tc.apiVersion, "api-version", "", "Specify API version for trigger")
+	tc.cmd.Flags().BoolVar(&tc.list, "list", false, "List all supported events, including those provided by plugins")
+	tc.cmd.Flags().BoolVar(&tc.livemode, "live", false, "Trigger the event in live mode, without being asked to confirm (default: test)")
 
 	// Hidden configuration flags, useful for dev/debugging
 	tc.cmd.Flags().StringVar(&tc.apiBaseURL, "api-base", stripe.DefaultAPIBaseURL, "Sets the API base URL")
@@ -67,20 +86,36 @@ needed to create the triggered event as well as the corresponding API objects.
 func (tc *triggerCmd) runTriggerCmd(cmd *cobra.Command, args []string) error {
 	version.CheckLatestVersion()
 
+	if tc.list {
+		fmt.Print(fixtures.EventList())
+
+		return nil
+	}
+
 	if len(args) == 0 {
 		cmd.Help()
 
 		return nil
 	}
 
-	apiKey, err := Config.Profile.GetAPIKey(false)
+	apiKey, err := Config.Profile.GetAPIKey(tc.livemode)
 	if err != nil {
 		return err
 	}
 
+	// the requests of the fixture are made without --live, so the guard needs
+	// to know live mode was asked for
+	guardrails.Default.Explicit = tc.livemode
+
+	if err := ensureProfileUnlocked(cmd.Context()); err != nil {
+		return err
+	}
+
 	event := args[0]
 
-	_, err = fixtures.Trigger(cmd.Context(), event, tc.stripeAccount, tc.apiBaseURL, apiKey, tc.skip, tc.override, tc.add, tc.remove, tc.raw, tc.apiVersion)
+	_, err = fixtures.Trigger(cmd.Context(), event, tc.stripeAccount, tc.apiBaseURL, apiKey, tc.skip, tc.override, tc.add, tc.remove, tc.raw, tc.apiVersion, fixtureMetadata(&Config.Profile))
+	plugins.CleanupAllClients()
+
 	if err != nil {
 		return err
 	}
@@ -88,3 +123,15 @@ func (tc *triggerCmd) runTriggerCmd(cmd *cobra.Command, args []string) error {
 	fmt.Println("Trigger succeeded! Check dashboard for event details.")
 	return nil
 }
+
+// fixtureMetadata returns the metadata of the objects created by trigger and
+// fixtures: the default metadata of the profile, tagged with the git branch and
+// commit when asked for
+func fixtureMetadata(profile *config.Profile) map[string]string {
+	metadata := profile.GetDefaultMetadata()
+	if profile.GetGitMetadata() {
+		metadata = git.TagMetadata(metadata)
+	}
+
+	return metadata
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	webhooks "github.com/stripe/stripe-cli/pkg/cmd/webhooks"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+// WebhooksCmd is a wrapper for the base webhooks command
+type WebhooksCmd struct {
+	Cmd *cobra.Command
+}
+
+func newWebhooksCmd() *WebhooksCmd {
+	webhooksCmd := &WebhooksCmd{}
+
+	webhooksCmd.Cmd = &cobra.Command{
+		Use:   "webhooks",
+		Args:  validators.NoArgs,
+		Short: "Debug webhook signatures",
+		Long: `Verify the Stripe-Signature header of a webhook request, or generate one for a
+payload, to debug signature verification in your webhook handler.`,
+	}
+
+	webhooksCmd.Cmd.AddCommand(webhooks.NewVerifyCmd().Cmd)
+	webhooksCmd.Cmd.AddCommand(webhooks.NewSignCmd().Cmd)
+
+	return webhooksCmd
+}
@@ -0,0 +1,24 @@
+package webhooks
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// readPayload reads the payload from a file, or from stdin when path is empty
+// or "-"
+func readPayload(path string) ([]byte, error) {
+	if path != "" && path != "-" {
+		return os.ReadFile(path)
+	}
+
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, clierrors.New(clierrors.RequiredFlagMissing, "pass the payload with --payload, or pipe it to stdin")
+	}
+
+	return io.ReadAll(os.Stdin)
+}
@@ -0,0 +1,63 @@
+package webhooks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/validators"
+	"github.com/stripe/stripe-cli/pkg/webhooks"
+)
+
+// SignCmd generates a Stripe-Signature header for a webhook payload
+type SignCmd struct {
+	Cmd *cobra.Command
+
+	secret    string
+	payload   string
+	timestamp int64
+}
+
+// NewSignCmd creates and initializes the sign command for the webhooks package
+func NewSignCmd() *SignCmd {
+	signCmd := &SignCmd{}
+
+	signCmd.Cmd = &cobra.Command{
+		Use:   "sign",
+		Args:  validators.NoArgs,
+		Short: "Generate a valid Stripe-Signature header for a payload",
+		Long: `Generate the Stripe-Signature header Stripe would send along with a payload, to
+send signed requests to your webhook handler.`,
+		Example: `stripe webhooks sign --secret whsec_... --payload payload.json
+  curl localhost:4242/webhook -H "Stripe-Signature: $(stripe webhooks sign --secret whsec_... --payload payload.json)" --data-binary @payload.json`,
+		RunE: signCmd.runSignCmd,
+	}
+
+	signCmd.Cmd.Flags().StringVar(&signCmd.secret, "secret", "", "The webhook signing secret (whsec_...)")
+	signCmd.Cmd.Flags().StringVar(&signCmd.payload, "payload", "", "The file containing the raw payload (default: read from stdin)")
+	signCmd.Cmd.Flags().Int64Var(&signCmd.timestamp, "timestamp", 0, "The Unix timestamp to sign the payload at (default: now)")
+
+	return signCmd
+}
+
+func (sc *SignCmd) runSignCmd(cmd *cobra.Command, args []string) error {
+	if sc.secret == "" {
+		return clierrors.New(clierrors.RequiredFlagMissing, "the signing secret is required, pass it with --secret")
+	}
+
+	payload, err := readPayload(sc.payload)
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now()
+	if sc.timestamp != 0 {
+		timestamp = time.Unix(sc.timestamp, 0)
+	}
+
+	fmt.Println(webhooks.GenerateHeader(payload, sc.secret, timestamp))
+
+	return nil
+}
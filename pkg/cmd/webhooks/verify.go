@@ -0,0 +1,135 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+	"github.com/stripe/stripe-cli/pkg/validators"
+	"github.com/stripe/stripe-cli/pkg/webhooks"
+)
+
+// clockCheckTimeout is how long to wait for Stripe when measuring the skew of
+// the local clock
+const clockCheckTimeout = 3 * time.Second
+
+// VerifyCmd checks a webhook payload against its Stripe-Signature header
+type VerifyCmd struct {
+	Cmd *cobra.Command
+
+	secret     string
+	payload    string
+	signature  string
+	apiBaseURL string
+}
+
+// NewVerifyCmd creates and initializes the verify command for the webhooks package
+func NewVerifyCmd() *VerifyCmd {
+	verifyCmd := &VerifyCmd{}
+
+	verifyCmd.Cmd = &cobra.Command{
+		Use:   "verify",
+		Args:  validators.NoArgs,
+		Short: "Verify the signature of a webhook payload",
+		Long: `Compute the signature of a webhook payload and check it against the
+Stripe-Signature header it was received with. The payload must be the raw
+request body, byte for byte: parsing and re-serializing it changes the
+signature.
+
+The local clock is compared with Stripe's too, since signatures are checked
+against it: a clock a few minutes off makes every signature look too old.`,
+		Example: `stripe webhooks verify --secret whsec_... --payload payload.json --signature 't=1600000000,v1=5257a869...'
+  cat payload.json | stripe webhooks verify --secret whsec_... --signature 't=1600000000,v1=5257a869...'`,
+		RunE: verifyCmd.runVerifyCmd,
+	}
+
+	verifyCmd.Cmd.Flags().StringVar(&verifyCmd.secret, "secret", "", "The webhook signing secret (whsec_...)")
+	verifyCmd.Cmd.Flags().StringVar(&verifyCmd.payload, "payload", "", "The file containing the raw payload (default: read from stdin)")
+	verifyCmd.Cmd.Flags().StringVar(&verifyCmd.signature, "signature", "", "The value of the Stripe-Signature header")
+
+	// Hidden configuration flags, useful for dev/debugging
+	verifyCmd.Cmd.Flags().StringVar(&verifyCmd.apiBaseURL, "api-base", stripe.DefaultAPIBaseURL, "Sets the API base URL")
+	verifyCmd.Cmd.Flags().MarkHidden("api-base") // #nosec G104
+
+	return verifyCmd
+}
+
+func (vc *VerifyCmd) runVerifyCmd(cmd *cobra.Command, args []string) error {
+	if vc.secret == "" {
+		return clierrors.New(clierrors.RequiredFlagMissing, "the signing secret is required, pass it with --secret")
+	}
+	if vc.signature == "" {
+		return clierrors.New(clierrors.RequiredFlagMissing, "the Stripe-Signature header is required, pass it with --signature")
+	}
+
+	payload, err := readPayload(vc.payload)
+	if err != nil {
+		return err
+	}
+
+	verification, err := webhooks.Verify(payload, vc.signature, vc.secret, time.Now())
+	if err != nil {
+		return err
+	}
+
+	color := ansi.Color(os.Stdout)
+
+	fmt.Printf("Timestamp:          %s (%s)\n", verification.Header.Timestamp.UTC().Format(time.RFC3339), describeSkew(verification.Skew))
+	fmt.Printf("Expected signature: %s\n", verification.Expected)
+	for _, signature := range verification.Header.Signatures {
+		fmt.Printf("Header signature:   %s\n", signature)
+	}
+	fmt.Println()
+
+	if warning := vc.clockSkewWarning(cmd.Context()); warning != "" {
+		fmt.Printf("%s %s\n", color.Yellow("Warning:"), warning)
+	}
+
+	if !verification.WithinTolerance {
+		fmt.Printf("%s the timestamp is older than the %s tolerance of Stripe's libraries, which will reject it to prevent replay attacks.\n",
+			color.Yellow("Warning:"), webhooks.DefaultTolerance)
+	}
+
+	if verification.Valid {
+		fmt.Printf("%s The signature matches the payload.\n", color.Green("✔"))
+		return nil
+	}
+
+	if verification.TrailingNewline {
+		fmt.Println("The signature matches the payload without its trailing newline. Make sure the payload file contains exactly the request body.")
+	} else {
+		fmt.Println("Check that you're using the signing secret of the endpoint that received the event, and that the payload is the raw request body rather than parsed and re-serialized JSON.")
+	}
+
+	return clierrors.New(clierrors.SignatureMismatch, "the signature doesn't match the payload")
+}
+
+// clockSkewWarning measures the skew of the local clock from Stripe's. Failing
+// to measure it, such as when offline, isn't an error: the signature can be
+// verified all the same.
+func (vc *VerifyCmd) clockSkewWarning(ctx context.Context) string {
+	ctx, cancel := context.WithTimeout(ctx, clockCheckTimeout)
+	defer cancel()
+
+	skew, err := stripe.MeasureClockSkew(ctx, vc.apiBaseURL)
+	if err != nil {
+		return ""
+	}
+
+	return webhooks.ClockSkewWarning(skew)
+}
+
+func describeSkew(skew time.Duration) string {
+	skew = skew.Round(time.Second)
+	if skew < 0 {
+		return fmt.Sprintf("%s in the future", -skew)
+	}
+
+	return fmt.Sprintf("%s ago", skew)
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// ProfileBindingFile is the file binding the directory it's in, and the
+// directories below, to a profile
+const ProfileBindingFile = ".stripe-profile"
+
+// FindProfileBinding returns the profile bound to dir or to the closest of its
+// parents, and the binding file, or "" when none is bound
+func FindProfileBinding(dir string) (profile string, path string, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	for {
+		path = filepath.Join(dir, ProfileBindingFile)
+
+		profile, err = readProfileBinding(path)
+		switch {
+		case err == nil:
+			return profile, path, nil
+		case !errors.Is(err, os.ErrNotExist):
+			return "", "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", nil
+		}
+		dir = parent
+	}
+}
+
+// readProfileBinding returns the profile named by a binding file: its first
+// line that isn't empty or a # comment
+func readProfileBinding(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if err := validateProfileName(line); err != nil {
+			return "", fmt.Errorf("%s: %w", path, err)
+		}
+		return line, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", clierrors.Errorf(clierrors.InvalidProfileBinding, "%s doesn't name a profile", path)
+}
+
+// BindProfile binds dir and the directories below to a profile, returning
+// the binding file
+func BindProfile(dir, profile string) (string, error) {
+	if err := validateProfileName(profile); err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", clierrors.Errorf(clierrors.InvalidProfileBinding, "%s isn't a directory", dir)
+	}
+
+	path, err := filepath.Abs(filepath.Join(dir, ProfileBindingFile))
+	if err != nil {
+		return "", err
+	}
+
+	content := "# The profile of the Stripe CLI for the commands run in this directory\n" + profile + "\n"
+
+	// the file names a profile, not its keys, so it can be committed
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// UnbindProfile removes the binding of dir, returning the file removed
+func UnbindProfile(dir string) (string, error) {
+	path, err := filepath.Abs(filepath.Join(dir, ProfileBindingFile))
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", clierrors.Errorf(clierrors.InvalidProfileBinding, "%s isn't bound to a profile", dir)
+		}
+		return "", err
+	}
+
+	return path, nil
+}
+
+func validateProfileName(profile string) error {
+	if profile == "" || strings.ContainsAny(profile, " \t.[]\"'") {
+		return clierrors.Errorf(clierrors.InvalidProfileBinding, "invalid profile name %q", profile)
+	}
+
+	return nil
+}
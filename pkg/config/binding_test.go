@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindProfileBinding(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "services", "billing")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+
+	profile, path, err := FindProfileBinding(nested)
+	require.NoError(t, err)
+	require.Empty(t, profile)
+	require.Empty(t, path)
+
+	bound, err := BindProfile(root, "acme")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(root, ProfileBindingFile), bound)
+
+	// the directories below are bound too
+	profile, path, err = FindProfileBinding(nested)
+	require.NoError(t, err)
+	require.Equal(t, "acme", profile)
+	require.Equal(t, bound, path)
+
+	// the closest binding wins
+	_, err = BindProfile(nested, "acme-staging")
+	require.NoError(t, err)
+	profile, _, err = FindProfileBinding(nested)
+	require.NoError(t, err)
+	require.Equal(t, "acme-staging", profile)
+
+	removed, err := UnbindProfile(nested)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(nested, ProfileBindingFile), removed)
+	profile, _, err = FindProfileBinding(nested)
+	require.NoError(t, err)
+	require.Equal(t, "acme", profile)
+
+	_, err = UnbindProfile(nested)
+	require.ErrorContains(t, err, "isn't bound to a profile")
+}
+
+func TestFindProfileBinding_InvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ProfileBindingFile)
+
+	require.NoError(t, os.WriteFile(path, []byte("# comment\n\n"), 0644))
+	_, _, err := FindProfileBinding(dir)
+	require.ErrorContains(t, err, "doesn't name a profile")
+
+	require.NoError(t, os.WriteFile(path, []byte("acme staging\n"), 0644))
+	_, _, err = FindProfileBinding(dir)
+	require.ErrorContains(t, err, `invalid profile name "acme staging"`)
+}
+
+func TestBindProfile_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := BindProfile(dir, "")
+	require.Error(t, err)
+
+	_, err = BindProfile(filepath.Join(dir, "missing"), "acme")
+	require.Error(t, err)
+}
@@ -19,6 +19,8 @@ import (
 	prefixed "github.com/x-cray/logrus-prefixed-formatter"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/redact"
 )
 
 // ColorOn represnets the on-state for colors
@@ -30,6 +32,13 @@ const ColorOff = "off"
 // ColorAuto represents the auto-state for colors
 const ColorAuto = "auto"
 
+// AliasesName is the table of the config file holding the command aliases,
+// shared by all profiles
+const AliasesName = "aliases"
+
+// TelemetryOff turns telemetry off when set as the telemetry config field
+const TelemetryOff = "off"
+
 // IConfig allows us to add more implementations, such as ones for unit tests
 type IConfig interface {
 	GetProfile() *Profile
@@ -47,6 +56,7 @@ type IConfig interface {
 type Config struct {
 	Color            string
 	LogLevel         string
+	NoTelemetry      bool
 	Profile          Profile
 	ProfilesFile     string
 	InstalledPlugins []string
@@ -57,6 +67,24 @@ func (c *Config) GetProfile() *Profile {
 	return &c.Profile
 }
 
+// TelemetryEnabled returns false when telemetry was turned off with the
+// --no-telemetry flag, or with the telemetry field of the config file, either
+// globally or for the profile
+func (c *Config) TelemetryEnabled() bool {
+	if c.NoTelemetry {
+		return false
+	}
+
+	for _, field := range []string{"telemetry", c.Profile.GetConfigField("telemetry")} {
+		switch strings.ToLower(viper.GetString(field)) {
+		case TelemetryOff, "false", "0":
+			return false
+		}
+	}
+
+	return true
+}
+
 // GetConfigFolder retrieves the folder where the profiles file is stored
 // It searches for the xdg environment path first and will secondarily
 // place it in the home directory
@@ -90,7 +118,17 @@ func (c *Config) InitConfig() {
 		TimestampFormat: time.RFC1123,
 	}
 
-	log.SetFormatter(logFormatter)
+	// secrets must never end up in debug logs that users share with us
+	log.SetFormatter(&redact.Formatter{Formatter: logFormatter})
+
+	// users can list their own secrets to redact in the config folder
+	redactionsFile := filepath.Join(c.GetConfigFolder(os.Getenv("XDG_CONFIG_HOME")), "redactions")
+	if err := redact.LoadPatternsFile(redactionsFile); err != nil {
+		log.WithFields(log.Fields{
+			"prefix": "config.Config.InitConfig",
+			"path":   redactionsFile,
+		}).Warnf("Could not load redaction patterns: %s", err)
+	}
 
 	// Set log level
 	switch c.LogLevel {
@@ -195,7 +233,7 @@ func (c *Config) EditConfig() error {
 		// comparable option to $EDITOR, so default to notepad for now
 		err = exec.Command("notepad", c.ProfilesFile).Run()
 	default:
-		err = fmt.Errorf("unsupported platform")
+		err = clierrors.New(clierrors.UnsupportedPlatform, "unsupported platform")
 	}
 
 	return err
@@ -232,6 +270,14 @@ func (c *Config) GetInstalledPlugins() []string {
 	return runtimeViper.GetStringSlice("installed_plugins")
 }
 
+// GetAliases returns the command aliases set in the aliases table of the
+// config file, by name. This does not vary by profile
+func (c *Config) GetAliases() map[string]string {
+	runtimeViper := viper.GetViper()
+
+	return runtimeViper.GetStringMapString(AliasesName)
+}
+
 // RemoveProfile removes the profile whose name matches the provided
 // profileName from the config file.
 func (c *Config) RemoveProfile(profileName string) error {
@@ -239,7 +285,7 @@ func (c *Config) RemoveProfile(profileName string) error {
 	var err error
 
 	for field, value := range runtimeViper.AllSettings() {
-		if isProfile(value) && field == profileName {
+		if isProfile(field, value) && field == profileName {
 			runtimeViper, err = removeKey(runtimeViper, field)
 			if err != nil {
 				return err
@@ -256,7 +302,7 @@ func (c *Config) RemoveAllProfiles() error {
 	var err error
 
 	for field, value := range runtimeViper.AllSettings() {
-		if isProfile(value) {
+		if isProfile(field, value) {
 			runtimeViper, err = removeKey(runtimeViper, field)
 			if err != nil {
 				return err
@@ -268,8 +314,12 @@ func (c *Config) RemoveAllProfiles() error {
 }
 
 // isProfile identifies whether a value in the config pertains to a profile.
-func isProfile(value interface{}) bool {
+func isProfile(field string, value interface{}) bool {
 	// TODO: ianjabour - ideally find a better way to identify projects in config
+	if field == AliasesName {
+		return false
+	}
+
 	_, ok := value.(map[string]interface{})
 	return ok
 }
@@ -18,3 +18,21 @@ func TestRemoveKey(t *testing.T) {
 	require.EqualValues(t, []string{"stay"}, nv.AllKeys())
 	require.ElementsMatch(t, []string{"stay", "remove"}, v.AllKeys())
 }
+
+func TestTelemetryEnabled(t *testing.T) {
+	defer viper.Reset()
+
+	c := &Config{Profile: Profile{ProfileName: "tests"}}
+	require.True(t, c.TelemetryEnabled())
+
+	viper.Set("tests.telemetry", TelemetryOff)
+	require.False(t, c.TelemetryEnabled())
+
+	viper.Reset()
+	viper.Set("telemetry", false)
+	require.False(t, c.TelemetryEnabled())
+
+	viper.Reset()
+	c.NoTelemetry = true
+	require.False(t, c.TelemetryEnabled())
+}
@@ -0,0 +1,15 @@
+// Package config reads and writes the CLI's configuration file and the
+// profiles stored in it.
+//
+// The stable entry points for programs embedding the CLI are Config, Profile
+// and the IConfig interface. Call InitConfig before reading any profile so
+// that the configuration file and environment are loaded, then use
+// Profile.GetAPIKey to resolve the key for a project:
+//
+//	cfg := &config.Config{Profile: config.Profile{ProfileName: "default"}}
+//	cfg.InitConfig()
+//	apiKey, err := cfg.GetProfile().GetAPIKey(false)
+//
+// These are kept backwards compatible within a major version of the CLI.
+// The layout of the configuration file itself is not part of that guarantee.
+package config
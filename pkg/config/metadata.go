@@ -0,0 +1,26 @@
+package config
+
+import (
+	"os"
+
+	"github.com/stripe/stripe-cli/pkg/git"
+)
+
+// gitBranchVariable is replaced by the git branch checked out in the current
+// directory in default metadata values
+const gitBranchVariable = "git_branch"
+
+// ExpandMetadataValue replaces the variables in a default metadata value,
+// written as $NAME or ${NAME}, with their value. ${git_branch} is the git
+// branch checked out in the current directory; any other variable is read
+// from the environment.
+func ExpandMetadataValue(value string) string {
+	return os.Expand(value, func(name string) string {
+		if name == gitBranchVariable {
+			ctx, _ := git.CurrentContext()
+			return ctx.Branch
+		}
+
+		return os.Getenv(name)
+	})
+}
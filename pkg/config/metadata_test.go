@@ -0,0 +1,16 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandMetadataValue(t *testing.T) {
+	t.Setenv("STRIPE_TEST_DEVELOPER", "jane")
+
+	require.Equal(t, "stripe-cli", ExpandMetadataValue("stripe-cli"))
+	require.Equal(t, "jane", ExpandMetadataValue("$STRIPE_TEST_DEVELOPER"))
+	require.Equal(t, "dev-jane", ExpandMetadataValue("dev-${STRIPE_TEST_DEVELOPER}"))
+	require.Equal(t, "", ExpandMetadataValue("$STRIPE_TEST_UNSET"))
+}
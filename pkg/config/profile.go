@@ -1,7 +1,6 @@
 package config
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,6 +8,7 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/validators"
 )
 
@@ -24,6 +24,9 @@ type Profile struct {
 	TerminalPOSDeviceID    string
 	DisplayName            string
 	AccountID              string
+	// ExpiresAt makes the profile ephemeral: its keys are removed once it's
+	// past, such as for the profiles written by `stripe login --ci`
+	ExpiresAt time.Time
 }
 
 // config key names
@@ -38,6 +41,19 @@ const (
 	LiveModeAPIKeyName         = "live_mode_api_key"
 	LiveModePubKeyName         = "live_mode_pub_key"
 	LiveModeKeyExpiresAtName   = "live_mode_key_expires_at"
+	DefaultMetadataName        = "default_metadata"
+	PasskeyIDName              = "passkey_id"
+	PasskeyPublicKeyName       = "passkey_public_key"
+	PasskeyConfirmName         = "passkey_confirm"
+	ProfileExpiresAtName       = "profile_expires_at"
+	StrictAmountsName          = "strict_amounts"
+	GitMetadataName            = "git_metadata"
+)
+
+// operations that can require a passkey, listed in the passkey_confirm field
+const (
+	PasskeyLiveWrites = "live_writes"
+	PasskeyShowKeys   = "show_keys"
 )
 
 // CreateProfile creates a profile when logging in
@@ -67,10 +83,40 @@ func (p *Profile) GetColor() (string, error) {
 	case ColorOff:
 		return ColorOff, nil
 	default:
-		return "", fmt.Errorf("color value not supported: %s", color)
+		return "", clierrors.Errorf(clierrors.InvalidConfigValue, "color value not supported: %s", color)
 	}
 }
 
+// GetStrictAmounts returns whether amounts are sent as written, rather than
+// amounts like $20.00 being converted to the smallest currency unit, based on
+// the flag or the setting of the profile
+func (p *Profile) GetStrictAmounts() bool {
+	if viper.GetBool("strict-amounts") {
+		return true
+	}
+
+	if err := viper.ReadInConfig(); err == nil {
+		return viper.GetBool(p.GetConfigField(StrictAmountsName))
+	}
+
+	return false
+}
+
+// GetGitMetadata returns whether the objects created by trigger and fixtures
+// are tagged with the git branch and commit they were created on, based on the
+// flag or the setting of the profile
+func (p *Profile) GetGitMetadata() bool {
+	if viper.GetBool("git-metadata") {
+		return true
+	}
+
+	if err := viper.ReadInConfig(); err == nil {
+		return viper.GetBool(p.GetConfigField(GitMetadataName))
+	}
+
+	return false
+}
+
 // GetDeviceName returns the configured device name
 func (p *Profile) GetDeviceName() (string, error) {
 	if os.Getenv("STRIPE_DEVICE_NAME") != "" {
@@ -122,6 +168,10 @@ func (p *Profile) GetAPIKey(livemode bool) (string, error) {
 		return p.APIKey, nil
 	}
 
+	if err := p.checkExpiry(time.Now()); err != nil {
+		return "", err
+	}
+
 	var key string
 	var err error
 
@@ -165,6 +215,40 @@ func (p *Profile) GetAPIKey(livemode bool) (string, error) {
 	return "", validators.ErrAPIKeyNotConfigured
 }
 
+// checkExpiry removes the keys of an ephemeral profile once it expired
+func (p *Profile) checkExpiry(now time.Time) error {
+	if err := viper.ReadInConfig(); err != nil {
+		return nil
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, viper.GetString(p.GetConfigField(ProfileExpiresAtName)))
+	if err != nil || now.Before(expiresAt) {
+		return nil
+	}
+
+	runtimeViper := viper.GetViper()
+	for _, field := range []string{
+		TestModeAPIKeyName, TestModePubKeyName, TestModeKeyExpiresAtName,
+		LiveModeAPIKeyName, LiveModePubKeyName, LiveModeKeyExpiresAtName,
+		ProfileExpiresAtName,
+	} {
+		if !runtimeViper.IsSet(p.GetConfigField(field)) {
+			continue
+		}
+
+		runtimeViper, err = removeKey(runtimeViper, p.GetConfigField(field))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := syncConfig(runtimeViper); err != nil {
+		return err
+	}
+
+	return validators.ErrProfileExpired
+}
+
 // GetExpiresAt returns the API key expirary date
 func (p *Profile) GetExpiresAt(livemode bool) (time.Time, error) {
 	var timeString string
@@ -242,6 +326,49 @@ func (p *Profile) GetTerminalPOSDeviceID() string {
 	return ""
 }
 
+// GetDefaultMetadata returns the metadata to add to the objects created by the
+// CLI, with the variables in its values expanded. See ExpandMetadataValue.
+func (p *Profile) GetDefaultMetadata() map[string]string {
+	if err := viper.ReadInConfig(); err != nil {
+		return nil
+	}
+
+	metadata := make(map[string]string)
+	for key, value := range viper.GetStringMapString(p.GetConfigField(DefaultMetadataName)) {
+		if expanded := ExpandMetadataValue(value); expanded != "" {
+			metadata[key] = expanded
+		}
+	}
+
+	return metadata
+}
+
+// GetPasskey returns the ID and public key of the passkey registered to
+// confirm operations, which are empty if there is none
+func (p *Profile) GetPasskey() (id string, publicKey string) {
+	if err := viper.ReadInConfig(); err == nil {
+		return viper.GetString(p.GetConfigField(PasskeyIDName)), viper.GetString(p.GetConfigField(PasskeyPublicKeyName))
+	}
+
+	return "", ""
+}
+
+// RequiresPasskey returns whether the operation, such as PasskeyLiveWrites,
+// must be confirmed with a passkey
+func (p *Profile) RequiresPasskey(operation string) bool {
+	if err := viper.ReadInConfig(); err != nil {
+		return false
+	}
+
+	for _, confirmed := range strings.Split(viper.GetString(p.GetConfigField(PasskeyConfirmName)), ",") {
+		if strings.TrimSpace(confirmed) == operation {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetConfigField returns the configuration field for the specific profile
 func (p *Profile) GetConfigField(field string) string {
 	return p.ProfileName + "." + field
@@ -323,6 +450,10 @@ func (p *Profile) writeProfile(runtimeViper *viper.Viper) error {
 		runtimeViper.Set(p.GetConfigField(AccountIDName), strings.TrimSpace(p.AccountID))
 	}
 
+	if !p.ExpiresAt.IsZero() {
+		runtimeViper.Set(p.GetConfigField(ProfileExpiresAtName), p.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+
 	runtimeViper.MergeInConfig()
 
 	// Do this after we merge the old configs in
@@ -335,6 +466,11 @@ func (p *Profile) writeProfile(runtimeViper *viper.Viper) error {
 		runtimeViper = p.safeRemove(runtimeViper, "publishable_key")
 	}
 
+	// logging in again without an expiry makes the profile permanent
+	if p.TestModeAPIKey != "" && p.ExpiresAt.IsZero() {
+		runtimeViper = p.safeRemove(runtimeViper, ProfileExpiresAtName)
+	}
+
 	runtimeViper.SetConfigFile(profilesFile)
 
 	// Ensure we preserve the config file type
@@ -5,9 +5,12 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/validators"
 )
 
 func TestWriteProfile(t *testing.T) {
@@ -100,6 +103,130 @@ test_mode_key_expires_at = '` + expiresAt + `'
 	cleanUp(c.ProfilesFile)
 }
 
+func TestRequiresPasskey(t *testing.T) {
+	profilesFile := filepath.Join(os.TempDir(), "stripe", "config.toml")
+	p := Profile{
+		ProfileName:    "tests",
+		TestModeAPIKey: "sk_test_123",
+	}
+
+	c := &Config{
+		Color:        "auto",
+		LogLevel:     "info",
+		Profile:      p,
+		ProfilesFile: profilesFile,
+	}
+	c.InitConfig()
+	defer cleanUp(c.ProfilesFile)
+
+	require.NoError(t, p.writeProfile(viper.New()))
+	require.False(t, p.RequiresPasskey(PasskeyLiveWrites))
+
+	require.NoError(t, p.WriteConfigField(PasskeyIDName, "Y3JlZGVudGlhbA"))
+	require.NoError(t, p.WriteConfigField(PasskeyPublicKeyName, "MFkw"))
+	require.NoError(t, p.WriteConfigField(PasskeyConfirmName, "live_writes, show_keys"))
+
+	require.True(t, p.RequiresPasskey(PasskeyLiveWrites))
+	require.True(t, p.RequiresPasskey(PasskeyShowKeys))
+
+	id, publicKey := p.GetPasskey()
+	require.Equal(t, "Y3JlZGVudGlhbA", id)
+	require.Equal(t, "MFkw", publicKey)
+
+	require.NoError(t, p.WriteConfigField(PasskeyConfirmName, "show_keys"))
+	require.False(t, p.RequiresPasskey(PasskeyLiveWrites))
+}
+
+func TestGetStrictAmounts(t *testing.T) {
+	profilesFile := filepath.Join(os.TempDir(), "stripe", "config.toml")
+	p := Profile{
+		ProfileName:    "tests",
+		TestModeAPIKey: "sk_test_123",
+	}
+
+	c := &Config{
+		Color:        "auto",
+		LogLevel:     "info",
+		Profile:      p,
+		ProfilesFile: profilesFile,
+	}
+	c.InitConfig()
+	defer cleanUp(c.ProfilesFile)
+
+	require.NoError(t, p.writeProfile(viper.New()))
+	require.False(t, p.GetStrictAmounts())
+
+	require.NoError(t, p.WriteConfigField(StrictAmountsName, "true"))
+	require.True(t, p.GetStrictAmounts())
+
+	require.NoError(t, p.WriteConfigField(StrictAmountsName, "false"))
+	viper.Set("strict-amounts", true)
+	defer viper.Set("strict-amounts", false)
+	require.True(t, p.GetStrictAmounts())
+}
+
+func TestGetGitMetadata(t *testing.T) {
+	profilesFile := filepath.Join(os.TempDir(), "stripe", "config.toml")
+	p := Profile{
+		ProfileName:    "tests",
+		TestModeAPIKey: "sk_test_123",
+	}
+
+	c := &Config{
+		Color:        "auto",
+		LogLevel:     "info",
+		Profile:      p,
+		ProfilesFile: profilesFile,
+	}
+	c.InitConfig()
+	defer cleanUp(c.ProfilesFile)
+
+	require.NoError(t, p.writeProfile(viper.New()))
+	require.False(t, p.GetGitMetadata())
+
+	require.NoError(t, p.WriteConfigField(GitMetadataName, "true"))
+	require.True(t, p.GetGitMetadata())
+
+	require.NoError(t, p.WriteConfigField(GitMetadataName, "false"))
+	viper.Set("git-metadata", true)
+	defer viper.Set("git-metadata", false)
+	require.True(t, p.GetGitMetadata())
+}
+
+func TestEphemeralProfile(t *testing.T) {
+	t.Setenv("STRIPE_API_KEY", "")
+
+	profilesFile := filepath.Join(os.TempDir(), "stripe", "config.toml")
+	p := Profile{
+		ProfileName:    "tests",
+		TestModeAPIKey: "rk_test_1234567890",
+		ExpiresAt:      time.Now().Add(time.Hour),
+	}
+
+	c := &Config{
+		Color:        "auto",
+		LogLevel:     "info",
+		Profile:      p,
+		ProfilesFile: profilesFile,
+	}
+	c.InitConfig()
+	defer cleanUp(c.ProfilesFile)
+
+	require.NoError(t, p.writeProfile(viper.New()))
+	key, err := p.GetAPIKey(false)
+	require.NoError(t, err)
+	require.Equal(t, "rk_test_1234567890", key)
+
+	// the keys are removed once the profile expired
+	p.ExpiresAt = time.Now().Add(-time.Minute)
+	require.NoError(t, p.writeProfile(viper.New()))
+
+	_, err = p.GetAPIKey(false)
+	require.ErrorIs(t, err, validators.ErrProfileExpired)
+	_, err = p.GetAPIKey(false)
+	require.ErrorIs(t, err, validators.ErrAPIKeyNotConfigured)
+}
+
 func helperLoadBytes(t *testing.T, name string) []byte {
 	bytes, err := os.ReadFile(name)
 	if err != nil {
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// secretFields are the fields of a profile holding secrets, along with the
+// fields that only make sense with them
+var secretFields = map[string]bool{
+	TestModeAPIKeyName:       true,
+	TestModeKeyExpiresAtName: true,
+	LiveModeAPIKeyName:       true,
+	LiveModeKeyExpiresAtName: true,
+	ProfileExpiresAtName:     true,
+	"secret_key":             true,
+	"api_key":                true,
+}
+
+// machineFields are the fields of a profile bound to the machine they were
+// written on, which are never exported nor imported
+var machineFields = map[string]bool{
+	PasskeyIDName:        true,
+	PasskeyPublicKeyName: true,
+	PasskeyConfirmName:   true,
+}
+
+// machineGlobalFields are the top level fields bound to the machine they were
+// written on
+var machineGlobalFields = map[string]bool{
+	"installed_plugins": true,
+}
+
+// ReadConfigFile returns the content of the config file at path, which is
+// empty if the file doesn't exist
+func ReadConfigFile(path string) (map[string]interface{}, error) {
+	content := make(map[string]interface{})
+
+	if _, err := toml.DecodeFile(path, &content); err != nil {
+		if os.IsNotExist(err) {
+			return content, nil
+		}
+		return nil, clierrors.Errorf(clierrors.InvalidConfigFile, "%s is not a valid config file: %w", path, err)
+	}
+
+	return content, nil
+}
+
+// WriteConfigFile replaces the config file at path with content, atomically,
+// readable by its owner only
+func WriteConfigFile(path string, content map[string]interface{}) error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(content); err != nil {
+		return err
+	}
+
+	if err := makePath(path); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// ExportConfig returns the portable part of the config: everything but the
+// fields bound to this machine, and the secrets too with noSecrets
+func ExportConfig(content map[string]interface{}, noSecrets bool) map[string]interface{} {
+	exported := make(map[string]interface{})
+
+	for name, value := range content {
+		if profile, ok := value.(map[string]interface{}); ok && isProfile(name, value) {
+			exportedProfile := make(map[string]interface{})
+			for field, fieldValue := range profile {
+				if machineFields[field] || (noSecrets && secretFields[field]) {
+					continue
+				}
+				exportedProfile[field] = fieldValue
+			}
+			exported[name] = exportedProfile
+			continue
+		}
+
+		if !machineGlobalFields[name] {
+			exported[name] = value
+		}
+	}
+
+	return exported
+}
+
+// Change is a field of the config set by an import
+type Change struct {
+	// Path is the profile or table and the name of the field, e.g.
+	// ["default", "color"], or just its name for top level fields
+	Path     []string
+	Current  interface{}
+	Incoming interface{}
+}
+
+// Key returns the dotted path of the field, e.g. default.color
+func (c Change) Key() string {
+	return strings.Join(c.Path, ".")
+}
+
+// ImportPlan is how importing a config changes the current one
+type ImportPlan struct {
+	// Additions are the fields that aren't set in the current config
+	Additions []Change
+	// Conflicts are the fields set to a different value in the current config
+	Conflicts []Change
+	// Skipped are the fields bound to another machine, which aren't imported
+	Skipped []string
+}
+
+// PlanImport compares the config to import to the current one. The fields
+// set to the same value in both are left out.
+func PlanImport(current, incoming map[string]interface{}) ImportPlan {
+	var plan ImportPlan
+
+	for _, name := range sortedKeys(incoming) {
+		value := incoming[name]
+
+		if profile, ok := value.(map[string]interface{}); ok && isProfile(name, value) {
+			for _, field := range sortedKeys(profile) {
+				if machineFields[field] {
+					plan.Skipped = append(plan.Skipped, name+"."+field)
+				}
+			}
+		} else if machineGlobalFields[name] {
+			plan.Skipped = append(plan.Skipped, name)
+			continue
+		}
+
+		plan.compare([]string{name}, current[name], value)
+	}
+
+	return plan
+}
+
+func (plan *ImportPlan) compare(path []string, current, incoming interface{}) {
+	incomingTable, incomingIsTable := incoming.(map[string]interface{})
+	currentTable, currentIsTable := current.(map[string]interface{})
+
+	switch {
+	case incomingIsTable && (current == nil || currentIsTable):
+		for _, key := range sortedKeys(incomingTable) {
+			if len(path) == 1 && machineFields[key] && isProfile(path[0], incoming) {
+				continue
+			}
+
+			var currentValue interface{}
+			if currentIsTable {
+				currentValue = currentTable[key]
+			}
+
+			plan.compare(append(append([]string{}, path...), key), currentValue, incomingTable[key])
+		}
+	case current == nil:
+		plan.Additions = append(plan.Additions, Change{Path: path, Incoming: incoming})
+	case !reflect.DeepEqual(current, incoming):
+		plan.Conflicts = append(plan.Conflicts, Change{Path: path, Current: current, Incoming: incoming})
+	}
+}
+
+// ApplyChanges sets the fields of the changes to their incoming value in
+// content
+func ApplyChanges(content map[string]interface{}, changes []Change) {
+	for _, change := range changes {
+		table := content
+		for _, key := range change.Path[:len(change.Path)-1] {
+			next, ok := table[key].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				table[key] = next
+			}
+			table = next
+		}
+
+		table[change.Path[len(change.Path)-1]] = change.Incoming
+	}
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const transferConfig = `
+color = "auto"
+installed_plugins = ["apps"]
+
+[aliases]
+  pi = "trigger payment_intent.succeeded"
+
+[default]
+  device_name = "laptop"
+  test_mode_api_key = "sk_test_51HbzAbcdEFGhijklmnop"
+  test_mode_key_expires_at = "2030-01-01"
+  test_mode_pub_key = "pk_test_51HbzAbcdEFGhijklmnop"
+  passkey_id = "cred_123"
+  profile_expires_at = "2030-01-01T00:00:00Z"
+
+  [default.default_metadata]
+    developer = "$USER"
+`
+
+func writeTransferConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	return path
+}
+
+func TestExportConfig(t *testing.T) {
+	content, err := ReadConfigFile(writeTransferConfig(t, transferConfig))
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"color":   "auto",
+		"aliases": map[string]interface{}{"pi": "trigger payment_intent.succeeded"},
+		"default": map[string]interface{}{
+			"device_name":              "laptop",
+			"test_mode_api_key":        "sk_test_51HbzAbcdEFGhijklmnop",
+			"test_mode_key_expires_at": "2030-01-01",
+			"test_mode_pub_key":        "pk_test_51HbzAbcdEFGhijklmnop",
+			"profile_expires_at":       "2030-01-01T00:00:00Z",
+			"default_metadata":         map[string]interface{}{"developer": "$USER"},
+		},
+	}, ExportConfig(content, false))
+
+	require.Equal(t, map[string]interface{}{
+		"device_name":       "laptop",
+		"test_mode_pub_key": "pk_test_51HbzAbcdEFGhijklmnop",
+		"default_metadata":  map[string]interface{}{"developer": "$USER"},
+	}, ExportConfig(content, true)["default"])
+}
+
+func TestReadConfigFileMissing(t *testing.T) {
+	content, err := ReadConfigFile(filepath.Join(t.TempDir(), "config.toml"))
+	require.NoError(t, err)
+	require.Empty(t, content)
+
+	_, err = ReadConfigFile(writeTransferConfig(t, "color = "))
+	require.Error(t, err)
+}
+
+func TestPlanImport(t *testing.T) {
+	current, err := ReadConfigFile(writeTransferConfig(t, transferConfig))
+	require.NoError(t, err)
+
+	incoming, err := ReadConfigFile(writeTransferConfig(t, `
+color = "off"
+installed_plugins = ["projects"]
+
+[default]
+  device_name = "desktop"
+  test_mode_pub_key = "pk_test_51HbzAbcdEFGhijklmnop"
+  passkey_id = "cred_456"
+
+  [default.default_metadata]
+    developer = "$USER"
+    team = "payments"
+
+[sandbox]
+  test_mode_api_key = "sk_test_51HbzZyxwVUTsrqponml"
+`))
+	require.NoError(t, err)
+
+	plan := PlanImport(current, incoming)
+
+	require.Equal(t, []Change{
+		{Path: []string{"default", "default_metadata", "team"}, Incoming: "payments"},
+		{Path: []string{"sandbox", "test_mode_api_key"}, Incoming: "sk_test_51HbzZyxwVUTsrqponml"},
+	}, plan.Additions)
+	require.Equal(t, []Change{
+		{Path: []string{"color"}, Current: "auto", Incoming: "off"},
+		{Path: []string{"default", "device_name"}, Current: "laptop", Incoming: "desktop"},
+	}, plan.Conflicts)
+	require.Equal(t, []string{"default.passkey_id", "installed_plugins"}, plan.Skipped)
+	require.Equal(t, "default.device_name", plan.Conflicts[1].Key())
+
+	ApplyChanges(current, append(plan.Additions, plan.Conflicts[1]))
+
+	require.Equal(t, "auto", current["color"])
+	require.Equal(t, "desktop", current["default"].(map[string]interface{})["device_name"])
+	require.Equal(t, "cred_123", current["default"].(map[string]interface{})["passkey_id"])
+	require.Equal(t, map[string]interface{}{"developer": "$USER", "team": "payments"}, current["default"].(map[string]interface{})["default_metadata"])
+	require.Equal(t, map[string]interface{}{"test_mode_api_key": "sk_test_51HbzZyxwVUTsrqponml"}, current["sandbox"])
+}
+
+func TestWriteConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stripe", "config.toml")
+	content := map[string]interface{}{
+		"color":   "on",
+		"default": map[string]interface{}{"device_name": "laptop"},
+	}
+
+	require.NoError(t, WriteConfigFile(path, content))
+
+	read, err := ReadConfigFile(path)
+	require.NoError(t, err)
+	require.Equal(t, content, read)
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
@@ -0,0 +1,328 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+// Severity is how serious an issue found in the configuration is
+type Severity int
+
+const (
+	// SeverityWarning issues don't prevent the CLI from working but are
+	// likely to cause surprises
+	SeverityWarning Severity = iota
+	// SeverityError issues prevent some commands from working
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Issue is a problem found while validating the configuration
+type Issue struct {
+	Severity Severity
+	// Subject is what the issue is about, such as a file or a profile
+	Subject string
+	Message string
+	// Fix is an actionable suggestion to resolve the issue
+	Fix string
+}
+
+// fieldKind is the type a configuration field must be of
+type fieldKind int
+
+const (
+	stringField fieldKind = iota
+	boolField
+	stringListField
+	stringMapField
+)
+
+// globalFields are the fields allowed at the top level of the config file
+var globalFields = map[string]fieldKind{
+	AliasesName:         stringMapField,
+	"color":             stringField,
+	"installed_plugins": stringListField,
+	"telemetry":         stringField,
+}
+
+// profileFields are the fields allowed in a profile of the config file
+var profileFields = map[string]fieldKind{
+	AccountIDName:              stringField,
+	DeviceNameName:             stringField,
+	DisplayNameName:            stringField,
+	IsTermsAcceptanceValidName: boolField,
+	TestModeAPIKeyName:         stringField,
+	TestModePubKeyName:         stringField,
+	TestModeKeyExpiresAtName:   stringField,
+	LiveModeAPIKeyName:         stringField,
+	LiveModePubKeyName:         stringField,
+	LiveModeKeyExpiresAtName:   stringField,
+	DefaultMetadataName:        stringMapField,
+	PasskeyIDName:              stringField,
+	PasskeyPublicKeyName:       stringField,
+	PasskeyConfirmName:         stringField,
+	ProfileExpiresAtName:       stringField,
+	StrictAmountsName:          boolField,
+	GitMetadataName:            boolField,
+	"color":                    stringField,
+	"terminal_pos_device_id":   stringField,
+	"telemetry":                stringField,
+	// written by old versions of the CLI, see GetAPIKey
+	"secret_key":      stringField,
+	"api_key":         stringField,
+	"publishable_key": stringField,
+}
+
+// keyExpiryWarning is how long before its expiry a key is reported as expiring
+const keyExpiryWarning = 7 * 24 * time.Hour
+
+// ValidateConfigFile checks that the config file at path can be parsed, only
+// contains known fields of the expected types, is only readable by its owner,
+// and that the keys of its profiles are usable
+func ValidateConfigFile(path string, now time.Time) []Issue {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return []Issue{{
+			Severity: SeverityWarning,
+			Subject:  path,
+			Message:  "the config file doesn't exist",
+			Fix:      "run `stripe login` to create it",
+		}}
+	} else if err != nil {
+		return []Issue{{Severity: SeverityError, Subject: path, Message: err.Error()}}
+	}
+
+	var issues []Issue
+
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		issues = append(issues, Issue{
+			Severity: SeverityWarning,
+			Subject:  path,
+			Message:  fmt.Sprintf("the config file contains API keys but is readable by other users (permissions %#o)", info.Mode().Perm()),
+			Fix:      fmt.Sprintf("run `chmod 600 %s`", path),
+		})
+	}
+
+	var content map[string]interface{}
+	if _, err := toml.DecodeFile(path, &content); err != nil {
+		return append(issues, Issue{
+			Severity: SeverityError,
+			Subject:  path,
+			Message:  fmt.Sprintf("the config file is not valid TOML: %s", err),
+			Fix:      "run `stripe config --edit` to fix the syntax",
+		})
+	}
+
+	for _, name := range sortedKeys(content) {
+		value := content[name]
+
+		if profile, ok := value.(map[string]interface{}); ok && isProfile(name, value) {
+			issues = append(issues, validateFields(fmt.Sprintf("profile %s", name), profile, profileFields)...)
+			issues = append(issues, validateProfileKeys(name, profile, now)...)
+			continue
+		}
+
+		issues = append(issues, validateFields(path, map[string]interface{}{name: value}, globalFields)...)
+	}
+
+	if color, ok := content["color"].(string); ok {
+		switch color {
+		case ColorOn, ColorOff, ColorAuto:
+		default:
+			issues = append(issues, Issue{
+				Severity: SeverityError,
+				Subject:  path,
+				Message:  fmt.Sprintf("unsupported color value '%s'", color),
+				Fix:      "run `stripe config --set color auto`",
+			})
+		}
+	}
+
+	return issues
+}
+
+//
+// Private functions
+//
+
+// validateFields checks that fields are known and of the expected types
+func validateFields(subject string, fields map[string]interface{}, schema map[string]fieldKind) []Issue {
+	var issues []Issue
+
+	for _, name := range sortedKeys(fields) {
+		kind, ok := schema[name]
+		if !ok {
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Subject:  subject,
+				Message:  fmt.Sprintf("unknown field '%s'", name),
+				Fix:      "check the field name for typos, or remove it with `stripe config --edit`",
+			})
+			continue
+		}
+
+		if !isKind(fields[name], kind) {
+			issues = append(issues, Issue{
+				Severity: SeverityError,
+				Subject:  subject,
+				Message:  fmt.Sprintf("field '%s' must be a %s", name, kindName(kind)),
+				Fix:      "fix its value with `stripe config --edit`",
+			})
+		}
+	}
+
+	return issues
+}
+
+// validateProfileKeys checks that the profile's API keys are for the mode
+// they're used in, and haven't expired
+func validateProfileKeys(name string, profile map[string]interface{}, now time.Time) []Issue {
+	subject := fmt.Sprintf("profile %s", name)
+	login := "run `stripe login`"
+	if name != "default" {
+		login = fmt.Sprintf("run `stripe login --project-name %s`", name)
+	}
+
+	var issues []Issue
+
+	for _, mode := range []struct {
+		keyField, expiresAtField, expected, other string
+	}{
+		{TestModeAPIKeyName, TestModeKeyExpiresAtName, "test", "live"},
+		{LiveModeAPIKeyName, LiveModeKeyExpiresAtName, "live", "test"},
+	} {
+		key, _ := profile[mode.keyField].(string)
+		if key == "" || isRedactedAPIKey(key) {
+			continue
+		}
+
+		if err := validators.APIKey(key); err != nil {
+			issues = append(issues, Issue{Severity: SeverityError, Subject: subject, Message: fmt.Sprintf("%s: %s", mode.keyField, err), Fix: login})
+			continue
+		}
+
+		if strings.Contains(key, "_"+mode.other+"_") {
+			issues = append(issues, Issue{
+				Severity: SeverityError,
+				Subject:  subject,
+				Message:  fmt.Sprintf("%s holds a %s mode key", mode.keyField, mode.other),
+				Fix:      login,
+			})
+		}
+
+		if strings.HasPrefix(key, "rk_") {
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Subject:  subject,
+				Message:  fmt.Sprintf("%s is a restricted key, commands outside of its permissions will fail", mode.keyField),
+				Fix:      "grant the missing permissions to the key in the Dashboard, or " + login + " to use a key with full access",
+			})
+		}
+
+		expiresAt, _ := profile[mode.expiresAtField].(string)
+		if expiresAt == "" {
+			continue
+		}
+
+		expiry, err := time.Parse(DateStringFormat, expiresAt)
+		switch {
+		case err != nil:
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Subject:  subject,
+				Message:  fmt.Sprintf("%s is not a date formatted as %s", mode.expiresAtField, DateStringFormat),
+				Fix:      login,
+			})
+		case now.After(expiry):
+			issues = append(issues, Issue{
+				Severity: SeverityError,
+				Subject:  subject,
+				Message:  fmt.Sprintf("the %s mode key expired on %s", mode.expected, expiresAt),
+				Fix:      login,
+			})
+		case expiry.Sub(now) < keyExpiryWarning:
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Subject:  subject,
+				Message:  fmt.Sprintf("the %s mode key expires on %s", mode.expected, expiresAt),
+				Fix:      login,
+			})
+		}
+	}
+
+	return issues
+}
+
+func isKind(value interface{}, kind fieldKind) bool {
+	switch kind {
+	case boolField:
+		// `stripe config --set` writes booleans as strings
+		if s, ok := value.(string); ok {
+			return s == "true" || s == "false"
+		}
+		_, ok := value.(bool)
+		return ok
+	case stringListField:
+		list, ok := value.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, item := range list {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		return true
+	case stringMapField:
+		table, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for _, item := range table {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		return true
+	default:
+		_, ok := value.(string)
+		return ok
+	}
+}
+
+func kindName(kind fieldKind) string {
+	switch kind {
+	case boolField:
+		return "boolean"
+	case stringListField:
+		return "list of strings"
+	case stringMapField:
+		return "table of strings"
+	default:
+		return "string"
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
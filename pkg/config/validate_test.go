@@ -0,0 +1,138 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestValidateConfigFileValid(t *testing.T) {
+	path := writeConfigFile(t, `
+color = "auto"
+
+[aliases]
+  pi-succeed = "trigger payment_intent.succeeded"
+
+[default]
+  account_id = "acct_123"
+  test_mode_api_key = "sk_test_1234567890"
+  test_mode_key_expires_at = "2024-06-01"
+`)
+
+	issues := ValidateConfigFile(path, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.Empty(t, issues)
+}
+
+func TestValidateConfigFileMissing(t *testing.T) {
+	issues := ValidateConfigFile(filepath.Join(t.TempDir(), "config.toml"), time.Now())
+	require.Len(t, issues, 1)
+	require.Equal(t, SeverityWarning, issues[0].Severity)
+}
+
+func TestValidateConfigFileInvalidTOML(t *testing.T) {
+	path := writeConfigFile(t, "[default\n")
+
+	issues := ValidateConfigFile(path, time.Now())
+	require.Len(t, issues, 1)
+	require.Equal(t, SeverityError, issues[0].Severity)
+	require.Contains(t, issues[0].Message, "not valid TOML")
+}
+
+func TestValidateConfigFileFields(t *testing.T) {
+	path := writeConfigFile(t, `
+color = "sometimes"
+
+[default]
+  acount_id = "acct_123"
+  is_terms_acceptance_valid = "yes"
+`)
+
+	issues := ValidateConfigFile(path, time.Now())
+	require.Len(t, issues, 3)
+	require.Equal(t, "profile default", issues[0].Subject)
+	require.Equal(t, "unknown field 'acount_id'", issues[0].Message)
+	require.Equal(t, SeverityWarning, issues[0].Severity)
+	require.Equal(t, "field 'is_terms_acceptance_valid' must be a boolean", issues[1].Message)
+	require.Equal(t, SeverityError, issues[1].Severity)
+	require.Equal(t, "unsupported color value 'sometimes'", issues[2].Message)
+}
+
+func TestValidateConfigFileSettings(t *testing.T) {
+	path := writeConfigFile(t, `
+[default]
+  strict_amounts = true
+  git_metadata = "true"
+
+[other]
+  strict_amounts = "sometimes"
+`)
+
+	issues := ValidateConfigFile(path, time.Now())
+	require.Len(t, issues, 1)
+	require.Equal(t, "profile other", issues[0].Subject)
+	require.Equal(t, "field 'strict_amounts' must be a boolean", issues[0].Message)
+}
+
+func TestValidateConfigFileKeys(t *testing.T) {
+	path := writeConfigFile(t, `
+[default]
+  test_mode_api_key = "sk_live_1234567890"
+
+[other]
+  test_mode_api_key = "rk_test_1234567890"
+  test_mode_key_expires_at = "2024-01-05"
+  live_mode_api_key = "sk_live_1234567890"
+  live_mode_key_expires_at = "2023-12-01"
+`)
+
+	issues := ValidateConfigFile(path, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.Len(t, issues, 4)
+	require.Equal(t, "test_mode_api_key holds a live mode key", issues[0].Message)
+	require.Equal(t, "run `stripe login`", issues[0].Fix)
+	require.Contains(t, issues[1].Message, "restricted key")
+	require.Equal(t, "the test mode key expires on 2024-01-05", issues[2].Message)
+	require.Equal(t, SeverityWarning, issues[2].Severity)
+	require.Equal(t, "the live mode key expired on 2023-12-01", issues[3].Message)
+	require.Equal(t, SeverityError, issues[3].Severity)
+	require.Equal(t, "run `stripe login --project-name other`", issues[3].Fix)
+}
+
+func TestValidateConfigFilePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permissions aren't checked on Windows")
+	}
+
+	path := writeConfigFile(t, "color = \"on\"\n")
+	require.NoError(t, os.Chmod(path, 0644))
+
+	issues := ValidateConfigFile(path, time.Now())
+	require.Len(t, issues, 1)
+	require.Contains(t, issues[0].Message, "readable by other users")
+}
+
+func TestValidateConfigFileDefaultMetadata(t *testing.T) {
+	path := writeConfigFile(t, `
+[default]
+  [default.default_metadata]
+    created_by = "stripe-cli"
+
+[other]
+  [other.default_metadata]
+    count = 1
+`)
+
+	issues := ValidateConfigFile(path, time.Now())
+	require.Len(t, issues, 1)
+	require.Equal(t, "profile other", issues[0].Subject)
+	require.Equal(t, "field 'default_metadata' must be a table of strings", issues[0].Message)
+}
@@ -0,0 +1,84 @@
+// Package currency converts amounts between the decimal form people write and
+// the integer of the currency's smallest unit the API expects.
+package currency
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// zeroDecimal are the currencies without a minor unit
+var zeroDecimal = map[string]bool{
+	"bif": true, "clp": true, "djf": true, "gnf": true, "jpy": true, "kmf": true,
+	"krw": true, "mga": true, "pyg": true, "rwf": true, "ugx": true, "vnd": true,
+	"vuv": true, "xaf": true, "xof": true, "xpf": true,
+}
+
+// threeDecimal are the currencies with a thousandth as minor unit
+var threeDecimal = map[string]bool{
+	"bhd": true, "jod": true, "kwd": true, "omr": true, "tnd": true,
+}
+
+// Exponent returns the number of decimals of the currency, such as 2 for usd
+// and 0 for jpy
+func Exponent(code string) int {
+	code = strings.ToLower(code)
+
+	switch {
+	case zeroDecimal[code]:
+		return 0
+	case threeDecimal[code]:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// ParseAmount returns the amount in the smallest unit of the currency of a
+// decimal amount, such as 1250 for "12.50" usd
+func ParseAmount(code, amount string) (int64, error) {
+	exponent := Exponent(code)
+	amount = strings.TrimSpace(amount)
+
+	whole, fraction, hasFraction := strings.Cut(amount, ".")
+	if hasFraction && len(fraction) > exponent {
+		return 0, clierrors.Errorf(clierrors.InvalidAmount, "invalid amount %q: %s has %d decimals", amount, strings.ToUpper(code), exponent)
+	}
+	if whole == "" || strings.HasPrefix(whole, "-") || strings.HasPrefix(whole, "+") {
+		return 0, clierrors.Errorf(clierrors.InvalidAmount, "invalid amount %q", amount)
+	}
+
+	digits := whole + fraction + strings.Repeat("0", exponent-len(fraction))
+
+	value, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, clierrors.Errorf(clierrors.InvalidAmount, "invalid amount %q", amount)
+	}
+
+	return value, nil
+}
+
+// FormatAmount returns an amount in the smallest unit of the currency in
+// decimal form, such as "12.50" for 1250 usd
+func FormatAmount(code string, amount int64) string {
+	exponent := Exponent(code)
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.FormatInt(amount, 10)
+	if exponent == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
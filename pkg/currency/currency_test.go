@@ -0,0 +1,45 @@
+package currency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAmount(t *testing.T) {
+	for _, tt := range []struct {
+		code, amount string
+		want         int64
+	}{
+		{"usd", "12.50", 1250},
+		{"usd", "12.5", 1250},
+		{"usd", "12", 1200},
+		{"USD", "0.99", 99},
+		{"jpy", "1500", 1500},
+		{"kwd", "1.250", 1250},
+	} {
+		got, err := ParseAmount(tt.code, tt.amount)
+		require.NoError(t, err, tt.amount)
+		require.Equal(t, tt.want, got, tt.amount)
+	}
+
+	for _, tt := range [][2]string{
+		{"usd", "12.505"},
+		{"jpy", "1500.5"},
+		{"usd", "-1"},
+		{"usd", ".5"},
+		{"usd", "1,000"},
+		{"usd", ""},
+	} {
+		_, err := ParseAmount(tt[0], tt[1])
+		require.Error(t, err, tt[1])
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	require.Equal(t, "12.50", FormatAmount("usd", 1250))
+	require.Equal(t, "0.05", FormatAmount("eur", 5))
+	require.Equal(t, "-3.00", FormatAmount("usd", -300))
+	require.Equal(t, "1500", FormatAmount("jpy", 1500))
+	require.Equal(t, "1.250", FormatAmount("kwd", 1250))
+}
@@ -0,0 +1,184 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// symbols are the currency symbols accepted in amounts, with the currencies
+// each can stand for
+var symbols = map[string][]string{
+	"$": {"usd", "cad", "aud", "nzd", "sgd", "hkd", "mxn"},
+	"€": {"eur"},
+	"£": {"gbp"},
+	"¥": {"jpy", "cny"},
+	"₹": {"inr"},
+}
+
+var (
+	codePrefix = regexp.MustCompile(`^([A-Za-z]{3})\s*(.*)$`)
+	codeSuffix = regexp.MustCompile(`^(.*?)\s*([A-Za-z]{3})$`)
+	number     = regexp.MustCompile(`^[0-9][0-9.,]*$`)
+)
+
+// ParseHumanAmount returns the amount in the smallest unit of its currency of
+// an amount written for people, such as "$20.00", "15,30EUR" or "JPY 1500".
+// code is the currency the amount is for, if known, which the currency
+// written with the amount must match.
+//
+// Both 1,234.50 and 1.234,50 are understood: when both separators are used,
+// the last one is the decimal separator. A lone comma followed by three digits
+// separates thousands, while a lone period is always a decimal separator.
+// Whole amounts with a currency, such as "20 USD", are in the main unit, but
+// plain integers are already in the smallest unit.
+func ParseHumanAmount(amount, code string) (int64, error) {
+	value, written, symbol := splitCurrency(strings.TrimSpace(amount))
+	code = strings.ToLower(code)
+
+	if !number.MatchString(value) {
+		return 0, clierrors.Errorf(clierrors.InvalidAmount, "invalid amount %q", amount)
+	}
+
+	switch {
+	case written != "" && code != "" && written != code:
+		return 0, clierrors.Errorf(clierrors.InvalidAmount, "the amount %s is in %s, but the currency is %s", amount, strings.ToUpper(written), strings.ToUpper(code))
+	case written != "":
+		code = written
+	case symbol != "":
+		resolved, err := resolveSymbol(amount, value, symbol, code)
+		if err != nil {
+			return 0, err
+		}
+		code = resolved
+	case code == "":
+		return 0, clierrors.Errorf(clierrors.InvalidAmount, "the currency of the amount %s is unknown, write it with the amount, such as %sUSD", amount, value)
+	}
+
+	normalized, err := normalizeSeparators(value)
+	if err != nil {
+		return 0, clierrors.Errorf(clierrors.InvalidAmount, "invalid amount %q: %s", amount, err)
+	}
+
+	minor, err := ParseAmount(code, normalized)
+	if err != nil {
+		return 0, err
+	}
+
+	return minor, nil
+}
+
+// splitCurrency separates the number of an amount from the currency code or
+// symbol written before or after it
+func splitCurrency(amount string) (value, code, symbol string) {
+	for s := range symbols {
+		if strings.HasPrefix(amount, s) {
+			return strings.TrimSpace(strings.TrimPrefix(amount, s)), "", s
+		}
+		if strings.HasSuffix(amount, s) {
+			return strings.TrimSpace(strings.TrimSuffix(amount, s)), "", s
+		}
+	}
+
+	if m := codePrefix.FindStringSubmatch(amount); m != nil {
+		return m[2], strings.ToLower(m[1]), ""
+	}
+	if m := codeSuffix.FindStringSubmatch(amount); m != nil {
+		return m[1], strings.ToLower(m[2]), ""
+	}
+
+	return amount, "", ""
+}
+
+// resolveSymbol returns the currency a symbol stands for: code if it's one of
+// its currencies, or the currency all of them agree on
+func resolveSymbol(amount, value, symbol, code string) (string, error) {
+	candidates := symbols[symbol]
+
+	if code != "" {
+		for _, candidate := range candidates {
+			if candidate == code {
+				return code, nil
+			}
+		}
+
+		return "", clierrors.Errorf(clierrors.InvalidAmount, "the amount %s is in %s, but the currency is %s", amount, symbol, strings.ToUpper(code))
+	}
+
+	// without a currency, the symbol only tells the number of decimals when
+	// all of its currencies have the same
+	for _, candidate := range candidates[1:] {
+		if Exponent(candidate) != Exponent(candidates[0]) {
+			return "", clierrors.Errorf(clierrors.InvalidAmount, "%s can be %s, write the currency code with the amount instead, such as %s%s",
+				symbol, strings.Join(upper(candidates), " or "), value, strings.ToUpper(candidates[0]))
+		}
+	}
+
+	return candidates[0], nil
+}
+
+func upper(codes []string) []string {
+	upper := make([]string, len(codes))
+	for i, code := range codes {
+		upper[i] = strings.ToUpper(code)
+	}
+
+	return upper
+}
+
+// normalizeSeparators returns a number with a period as decimal separator
+// and without thousands separators
+func normalizeSeparators(value string) (string, error) {
+	lastComma := strings.LastIndex(value, ",")
+	lastPeriod := strings.LastIndex(value, ".")
+
+	var decimal, grouping string
+
+	switch {
+	case lastComma >= 0 && lastPeriod >= 0:
+		decimal, grouping = ",", "."
+		if lastPeriod > lastComma {
+			decimal, grouping = ".", ","
+		}
+	case lastComma >= 0 && strings.Count(value, ",") == 1 && len(value)-lastComma-1 != 3:
+		decimal = ","
+	case lastComma >= 0:
+		grouping = ","
+	case strings.Count(value, ".") > 1:
+		grouping = "."
+	case lastPeriod >= 0:
+		decimal = "."
+	}
+
+	whole, fraction := value, ""
+	if decimal != "" {
+		i := strings.LastIndex(value, decimal)
+		whole, fraction = value[:i], value[i+1:]
+		if strings.ContainsAny(fraction, ",.") {
+			return "", errors.New("misplaced separator")
+		}
+	}
+
+	if grouping != "" {
+		groups := strings.Split(whole, grouping)
+		for i, group := range groups {
+			if i == 0 && (len(group) == 0 || len(group) > 3) || i > 0 && len(group) != 3 {
+				return "", fmt.Errorf("thousands are separated by %q in groups of three digits", grouping)
+			}
+		}
+		whole = strings.Join(groups, "")
+	}
+
+	if strings.ContainsAny(whole, ",.") {
+		return "", errors.New("misplaced separator")
+	}
+
+	if decimal == "" {
+		return whole, nil
+	}
+
+	return whole + "." + fraction, nil
+}
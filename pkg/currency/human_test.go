@@ -0,0 +1,61 @@
+package currency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+func TestParseHumanAmount(t *testing.T) {
+	for _, tt := range []struct {
+		amount, code string
+		want         int64
+	}{
+		{"$20.00", "", 2000},
+		{"$20", "cad", 2000},
+		{"20 $", "", 2000},
+		{"15,30EUR", "", 1530},
+		{"15,30 eur", "EUR", 1530},
+		{"EUR 1.234,50", "", 123450},
+		{"€1.234,50", "", 123450},
+		{"1,234.50", "usd", 123450},
+		{"1,234", "usd", 123400},
+		{"1.234.567", "usd", 123456700},
+		{"20 USD", "", 2000},
+		{"20.5", "usd", 2050},
+		{"1500JPY", "", 1500},
+		{"¥1500", "jpy", 1500},
+		{"£0.99", "", 99},
+		{"1.250 KWD", "", 1250},
+	} {
+		got, err := ParseHumanAmount(tt.amount, tt.code)
+		require.NoError(t, err, tt.amount)
+		require.Equal(t, tt.want, got, tt.amount)
+	}
+}
+
+func TestParseHumanAmountErrors(t *testing.T) {
+	for _, tt := range []struct {
+		amount, code, err string
+	}{
+		{"20.00", "", "the currency of the amount 20.00 is unknown, write it with the amount, such as 20.00USD"},
+		{"20.00EUR", "usd", "the amount 20.00EUR is in EUR, but the currency is USD"},
+		{"$20", "eur", "the amount $20 is in $, but the currency is EUR"},
+		{"¥2000", "", "¥ can be JPY or CNY, write the currency code with the amount instead, such as 2000JPY"},
+		{"20.50", "jpy", `invalid amount "20.50": JPY has 0 decimals`},
+		{"1.234", "usd", `invalid amount "1.234": USD has 2 decimals`},
+		{"12,34,56", "usd", `invalid amount "12,34,56": thousands are separated by "," in groups of three digits`},
+		{"1.5,234.00", "usd", `invalid amount "1.5,234.00": misplaced separator`},
+		{"-$20", "", `invalid amount "-$20"`},
+		{"twenty USD", "", `invalid amount "twenty USD"`},
+	} {
+		_, err := ParseHumanAmount(tt.amount, tt.code)
+		require.EqualError(t, err, tt.err, tt.amount)
+
+		code, ok := clierrors.CodeOf(err)
+		require.True(t, ok, tt.amount)
+		require.Equal(t, clierrors.InvalidAmount, code, tt.amount)
+	}
+}
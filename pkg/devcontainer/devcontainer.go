@@ -0,0 +1,74 @@
+// Package devcontainer sets the CLI up in the dev containers of cloud IDEs,
+// such as GitHub Codespaces, which are rebuilt from their devcontainer.json
+// and lose anything not kept in it or in a volume.
+package devcontainer
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// Environment is a containerized development environment
+type Environment struct {
+	// Name is how the environment is shown to users, e.g. GitHub Codespaces
+	Name string
+	// WorkspaceFolder is the folder opened in the IDE, when it's known
+	WorkspaceFolder string
+}
+
+// Detect returns the environment the CLI runs in, from the variables set by
+// the IDEs in their containers, or nil when it's not in one
+func Detect(getenv func(string) string) *Environment {
+	switch {
+	case getenv("CODESPACES") == "true":
+		return &Environment{Name: "GitHub Codespaces", WorkspaceFolder: getenv("CODESPACE_VSCODE_FOLDER")}
+	case getenv("GITPOD_WORKSPACE_ID") != "":
+		return &Environment{Name: "Gitpod", WorkspaceFolder: getenv("GITPOD_REPO_ROOT")}
+	case getenv("DEVPOD") == "true":
+		return &Environment{Name: "DevPod"}
+	case getenv("REMOTE_CONTAINERS") == "true":
+		return &Environment{Name: "VS Code Dev Containers"}
+	default:
+		return nil
+	}
+}
+
+// InContainer returns whether the CLI runs in a detected dev container
+func InContainer() bool {
+	return Detect(os.Getenv) != nil
+}
+
+// FindConfig returns the path of the devcontainer.json of the workspace dir
+// belongs to, looking for .devcontainer/devcontainer.json then
+// .devcontainer.json in dir and its parents. When there's none, it returns
+// where to create one: at the root of the git repository, or in dir.
+func FindConfig(fs afero.Fs, dir string) (string, bool) {
+	gitRoot := ""
+
+	for current := dir; ; current = filepath.Dir(current) {
+		for _, candidate := range []string{
+			filepath.Join(current, ".devcontainer", "devcontainer.json"),
+			filepath.Join(current, ".devcontainer.json"),
+		} {
+			if exists, _ := afero.Exists(fs, candidate); exists {
+				return candidate, true
+			}
+		}
+
+		if exists, _ := afero.Exists(fs, filepath.Join(current, ".git")); exists && gitRoot == "" {
+			gitRoot = current
+		}
+
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+
+	if gitRoot == "" {
+		gitRoot = dir
+	}
+
+	return filepath.Join(gitRoot, ".devcontainer", "devcontainer.json"), false
+}
@@ -0,0 +1,83 @@
+package devcontainer
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func getenv(env map[string]string) func(string) string {
+	return func(name string) string { return env[name] }
+}
+
+func TestDetect(t *testing.T) {
+	require.Nil(t, Detect(getenv(nil)))
+
+	env := Detect(getenv(map[string]string{"CODESPACES": "true", "CODESPACE_VSCODE_FOLDER": "/workspaces/shop"}))
+	require.Equal(t, &Environment{Name: "GitHub Codespaces", WorkspaceFolder: "/workspaces/shop"}, env)
+
+	require.Equal(t, "VS Code Dev Containers", Detect(getenv(map[string]string{"REMOTE_CONTAINERS": "true"})).Name)
+}
+
+func TestFindConfig(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/workspaces/shop/.git", 0755))
+	require.NoError(t, fs.MkdirAll("/workspaces/shop/server/src", 0755))
+
+	path, exists := FindConfig(fs, "/workspaces/shop/server/src")
+	require.False(t, exists)
+	require.Equal(t, "/workspaces/shop/.devcontainer/devcontainer.json", path)
+
+	require.NoError(t, afero.WriteFile(fs, "/workspaces/shop/.devcontainer.json", []byte("{}"), 0644))
+	path, exists = FindConfig(fs, "/workspaces/shop/server/src")
+	require.True(t, exists)
+	require.Equal(t, "/workspaces/shop/.devcontainer.json", path)
+}
+
+func TestApply(t *testing.T) {
+	settings := &Settings{
+		Ports:        []Port{{Number: 4242, Label: "Stripe samples"}},
+		ConfigFolder: "/home/vscode/.config/stripe",
+		Volume:       DefaultVolume,
+	}
+
+	updated, err := settings.Apply([]byte(`{
+  "name": "shop",
+  "image": "mcr.microsoft.com/devcontainers/go:1",
+  "forwardPorts": [3000, "db:5432"],
+  "postCreateCommand": "go mod download"
+}`))
+	require.NoError(t, err)
+	require.Equal(t, `{
+	"name": "shop",
+	"image": "mcr.microsoft.com/devcontainers/go:1",
+	"forwardPorts": [
+		3000,
+		"db:5432",
+		4242
+	],
+	"postCreateCommand": {
+		"setup": "go mod download",
+		"stripe-cli-config": "sudo chown -R \"$(id -u):$(id -g)\" '/home/vscode/.config/stripe' || true"
+	},
+	"portsAttributes": {
+		"4242": {
+			"label": "Stripe samples",
+			"onAutoForward": "silent"
+		}
+	},
+	"mounts": [
+		"source=stripe-cli-config,target=/home/vscode/.config/stripe,type=volume"
+	]
+}
+`, string(updated))
+
+	// applying the settings again changes nothing
+	again, err := settings.Apply(updated)
+	require.NoError(t, err)
+	require.Equal(t, string(updated), string(again))
+
+	_, err = settings.Apply([]byte("{\n  // the app\n  \"name\": \"shop\"\n}"))
+	require.ErrorIs(t, err, ErrNotPlainJSON)
+}
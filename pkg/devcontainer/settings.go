@@ -0,0 +1,309 @@
+package devcontainer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// DefaultVolume is the named volume the config folder is kept in
+const DefaultVolume = "stripe-cli-config"
+
+// postCreateKey names the CLI's command in the postCreateCommand object
+const postCreateKey = "stripe-cli-config"
+
+// ErrNotPlainJSON is returned for a devcontainer.json which can't be
+// rewritten without losing some of its content, such as comments
+var ErrNotPlainJSON = clierrors.New(clierrors.DevcontainerNotJSON, "devcontainer.json isn't plain JSON, such as because it has comments, so it can't be updated without losing them")
+
+// Port is a port of the container forwarded to the machine running the IDE
+type Port struct {
+	Number int
+	Label  string
+}
+
+// Settings are what the CLI needs in a devcontainer.json
+type Settings struct {
+	Ports []Port
+	// ConfigFolder is the CLI's config folder, kept in Volume so that logins
+	// survive rebuilds of the container
+	ConfigFolder string
+	Volume       string
+}
+
+// Apply adds the settings to the content of a devcontainer.json, leaving the
+// rest as is. Ports already forwarded and mounts already targeting the config
+// folder are kept.
+func (s *Settings) Apply(data []byte) ([]byte, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		data = []byte("{}")
+	}
+
+	if !json.Valid(data) {
+		return nil, ErrNotPlainJSON
+	}
+
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyPorts(doc); err != nil {
+		return nil, err
+	}
+
+	if s.ConfigFolder != "" {
+		if err := s.applyMount(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	return doc.marshal("\t")
+}
+
+// Snippet returns the settings as the devcontainer.json properties to add by
+// hand
+func (s *Settings) Snippet() string {
+	snippet, err := s.Apply(nil)
+	if err != nil {
+		return ""
+	}
+
+	return string(snippet)
+}
+
+func (s *Settings) applyPorts(doc *document) error {
+	var forwarded []interface{}
+	if err := doc.get("forwardPorts", &forwarded); err != nil {
+		return err
+	}
+
+	attributes, err := doc.getDocument("portsAttributes")
+	if err != nil {
+		return err
+	}
+
+	for _, port := range s.Ports {
+		if !containsPort(forwarded, port.Number) {
+			forwarded = append(forwarded, port.Number)
+		}
+
+		key := strconv.Itoa(port.Number)
+		if _, ok := attributes.values[key]; !ok {
+			attributes.set(key, map[string]string{"label": port.Label, "onAutoForward": "silent"})
+		}
+	}
+
+	if len(s.Ports) == 0 {
+		return nil
+	}
+
+	doc.set("forwardPorts", forwarded)
+	doc.setDocument("portsAttributes", attributes)
+
+	return nil
+}
+
+func containsPort(forwarded []interface{}, port int) bool {
+	for _, entry := range forwarded {
+		switch v := entry.(type) {
+		case float64:
+			if int(v) == port {
+				return true
+			}
+		case string:
+			// ports can also be given as host:port
+			if v == strconv.Itoa(port) || v == "localhost:"+strconv.Itoa(port) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (s *Settings) applyMount(doc *document) error {
+	var mounts []interface{}
+	if err := doc.get("mounts", &mounts); err != nil {
+		return err
+	}
+
+	for _, mount := range mounts {
+		if mountTarget(mount) == s.ConfigFolder {
+			return nil
+		}
+	}
+
+	volume := s.Volume
+	if volume == "" {
+		volume = DefaultVolume
+	}
+
+	doc.set("mounts", append(mounts, fmt.Sprintf("source=%s,target=%s,type=volume", volume, s.ConfigFolder)))
+
+	// Docker creates the volumes owned by root, while IDEs mostly run as
+	// another user
+	chown := fmt.Sprintf(`sudo chown -R "$(id -u):$(id -g)" '%s' || true`, strings.ReplaceAll(s.ConfigFolder, "'", `'\''`))
+
+	var postCreate interface{}
+	if err := doc.get("postCreateCommand", &postCreate); err != nil {
+		return err
+	}
+
+	commands, err := doc.getDocument("postCreateCommand")
+	if _, isObject := postCreate.(map[string]interface{}); !isObject || err != nil {
+		commands = newDocument()
+		if postCreate != nil {
+			// the object form runs several commands
+			commands.set("setup", postCreate)
+		}
+	}
+	commands.set(postCreateKey, chown)
+	doc.setDocument("postCreateCommand", commands)
+
+	return nil
+}
+
+// mountTarget returns the target of a mount, given as a string or an object
+func mountTarget(mount interface{}) string {
+	switch v := mount.(type) {
+	case string:
+		for _, option := range strings.Split(v, ",") {
+			name, value, _ := strings.Cut(option, "=")
+			if name == "target" || name == "dst" || name == "destination" {
+				return value
+			}
+		}
+	case map[string]interface{}:
+		target, _ := v["target"].(string)
+		return target
+	}
+
+	return ""
+}
+
+// document is a JSON object which keeps the order of its keys, so that the
+// properties of a devcontainer.json aren't reordered when it's rewritten
+type document struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+func newDocument() *document {
+	return &document{values: make(map[string]json.RawMessage)}
+}
+
+func parseDocument(data []byte) (*document, error) {
+	doc := newDocument()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	if t, err := decoder.Token(); err != nil || t != json.Delim('{') {
+		return nil, clierrors.New(clierrors.InvalidDevcontainer, "devcontainer.json must contain a JSON object")
+	}
+
+	for decoder.More() {
+		t, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		key := t.(string)
+		if _, ok := doc.values[key]; !ok {
+			doc.keys = append(doc.keys, key)
+		}
+		doc.values[key] = value
+	}
+
+	return doc, nil
+}
+
+func (d *document) get(key string, v interface{}) error {
+	raw, ok := d.values[key]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return clierrors.Errorf(clierrors.InvalidDevcontainer, "unexpected value for %s in devcontainer.json: %w", key, err)
+	}
+
+	return nil
+}
+
+// getDocument returns the object at key, or an empty one when it's missing
+func (d *document) getDocument(key string) (*document, error) {
+	raw, ok := d.values[key]
+	if !ok {
+		return newDocument(), nil
+	}
+
+	doc, err := parseDocument(raw)
+	if err != nil {
+		return nil, clierrors.Errorf(clierrors.InvalidDevcontainer, "unexpected value for %s in devcontainer.json", key)
+	}
+
+	return doc, nil
+}
+
+func (d *document) set(key string, v interface{}) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	d.setRaw(key, encoded)
+}
+
+func (d *document) setDocument(key string, doc *document) {
+	encoded, err := doc.marshal("")
+	if err != nil {
+		return
+	}
+
+	d.setRaw(key, encoded)
+}
+
+func (d *document) setRaw(key string, raw json.RawMessage) {
+	if _, ok := d.values[key]; !ok {
+		d.keys = append(d.keys, key)
+	}
+	d.values[key] = raw
+}
+
+// marshal encodes the document, indented when indent isn't empty
+func (d *document) marshal(indent string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+	for i, key := range d.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodedKey, _ := json.Marshal(key)
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(d.values[key])
+	}
+	buf.WriteByte('}')
+
+	if indent == "" {
+		return buf.Bytes(), nil
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, buf.Bytes(), "", indent); err != nil {
+		return nil, err
+	}
+	indented.WriteByte('\n')
+
+	return indented.Bytes(), nil
+}
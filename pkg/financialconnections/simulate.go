@@ -0,0 +1,263 @@
+// Package financialconnections helps simulate Financial Connections in test
+// mode: it creates a session, serves a page running the authentication flow
+// for it, and follows the events of the accounts it links.
+package financialconnections
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/stripe/stripe-cli/pkg/requests"
+)
+
+// Institutions are the test institutions the flow offers in test mode, by the
+// name they're picked with on the command line
+var Institutions = map[string]string{
+	"test_bank":        "Test (Non-OAuth)",
+	"test_oauth":       "Test (OAuth)",
+	"ownership":        "Ownership accounts",
+	"invalid_payment":  "Invalid Payment Accounts",
+	"down_scheduled":   "Down Bank (Scheduled)",
+	"down_unscheduled": "Down Bank (Unscheduled)",
+	"down_error":       "Down Bank (Error)",
+}
+
+// InstitutionNames returns the names of the test institutions, sorted
+func InstitutionNames() []string {
+	names := make([]string, 0, len(Institutions))
+	for name := range Institutions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// prefetchable are the permissions whose data can be fetched as soon as an
+// account is linked
+var prefetchable = map[string]bool{
+	"balances":     true,
+	"ownership":    true,
+	"transactions": true,
+}
+
+// eventsPollInterval is how often the events are listed while following them
+var eventsPollInterval = 2 * time.Second
+
+// Session is a Financial Connections session
+type Session struct {
+	ID           string `json:"id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+// Event is an event of an account linked through a session
+type Event struct {
+	ID          string
+	Type        string
+	Created     time.Time
+	Account     string
+	Institution string
+	Status      string
+}
+
+// CreateSession creates a session for customer to link accounts with the given
+// permissions. The data of the permissions that can be prefetched is fetched
+// as soon as the accounts are linked.
+func CreateSession(ctx context.Context, base requests.Base, apiKey, customer string, permissions []string) (Session, error) {
+	params := &requests.RequestParameters{}
+	params.AppendData([]string{
+		"account_holder[type]=customer",
+		"account_holder[customer]=" + customer,
+	})
+
+	for _, permission := range permissions {
+		params.AppendData([]string{"permissions[]=" + permission})
+		if prefetchable[permission] {
+			params.AppendData([]string{"prefetch[]=" + permission})
+		}
+	}
+
+	base.Method = http.MethodPost
+	base.SuppressOutput = true
+
+	resp, err := base.MakeRequest(ctx, apiKey, "/v1/financial_connections/sessions", params, true)
+	if err != nil {
+		return Session{}, err
+	}
+
+	var session Session
+	if err := json.Unmarshal(resp, &session); err != nil {
+		return Session{}, err
+	}
+
+	return session, nil
+}
+
+// FlowResult is the outcome of the authentication flow run in the browser
+type FlowResult struct {
+	Accounts []string `json:"accounts"`
+	Error    string   `json:"error"`
+}
+
+type flowPage struct {
+	PublishableKey string
+	ClientSecret   string
+	Institution    string
+}
+
+var flowTemplate = template.Must(template.New("financial-connections").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Stripe CLI</title>
+<script src="https://js.stripe.com/v3/"></script>
+</head>
+<body style="font-family: sans-serif; max-width: 40em; margin: 4em auto;">
+<h1>Stripe CLI</h1>
+<p>Link test accounts with Financial Connections. In the flow, pick the <strong>{{.Institution}}</strong> institution.</p>
+<button id="continue">Start the flow</button>
+<p id="status"></p>
+<script>
+document.getElementById("continue").addEventListener("click", async () => {
+  const stripe = Stripe({{.PublishableKey}});
+  let result;
+  try {
+    const {financialConnectionsSession, error} = await stripe.collectFinancialConnectionsAccounts({clientSecret: {{.ClientSecret}}});
+    result = error ? {error: error.message} : {accounts: financialConnectionsSession.accounts.map(a => a.id)};
+  } catch (e) {
+    result = {error: String(e)};
+  }
+  const resp = await fetch("/result", {
+    method: "POST",
+    headers: {"Content-Type": "application/json"},
+    body: JSON.stringify(result),
+  });
+  document.getElementById("status").textContent = await resp.text();
+});
+</script>
+</body>
+</html>
+`))
+
+// ServeFlow serves the page running the authentication flow of a session from
+// localhost, until ctx is done. It returns the URL of the page, and the
+// channel the results of the flow are sent to.
+func ServeFlow(ctx context.Context, publishableKey string, session Session, institution string) (string, <-chan FlowResult, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, fmt.Errorf("could not start the server of the flow: %w", err)
+	}
+
+	origin := fmt.Sprintf("http://localhost:%d", listener.Addr().(*net.TCPAddr).Port)
+	results := make(chan FlowResult, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		flowTemplate.Execute(w, flowPage{PublishableKey: publishableKey, ClientSecret: session.ClientSecret, Institution: institution})
+	})
+	mux.HandleFunc("/result", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Origin") != origin {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		var result FlowResult
+		if err := json.NewDecoder(io.LimitReader(r.Body, 64*1024)).Decode(&result); err != nil {
+			http.Error(w, "Invalid result", http.StatusBadRequest)
+			return
+		}
+
+		if result.Error != "" {
+			fmt.Fprintf(w, "The flow failed: %s", result.Error)
+		} else {
+			fmt.Fprint(w, "Done! You may close this window and return to the Stripe CLI.")
+		}
+
+		select {
+		case results <- result:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	return origin, results, nil
+}
+
+// FollowEvents lists the events of the Financial Connections accounts of
+// customer created since since, calling handle with each new one, oldest
+// first, until ctx is done
+func FollowEvents(ctx context.Context, base requests.Base, apiKey, customer string, since time.Time, handle func(Event)) error {
+	base.Method = http.MethodGet
+	base.SuppressOutput = true
+
+	seen := make(map[string]bool)
+	ticker := time.NewTicker(eventsPollInterval)
+	defer ticker.Stop()
+
+	for {
+		params := &requests.RequestParameters{}
+		params.AppendData([]string{
+			"type=financial_connections.account.*",
+			fmt.Sprintf("created[gte]=%d", since.Unix()),
+			"limit=100",
+		})
+
+		resp, err := base.MakeRequest(ctx, apiKey, "/v1/events", params, true)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		events := gjson.GetBytes(resp, "data").Array()
+
+		// events are listed newest first
+		for i := len(events) - 1; i >= 0; i-- {
+			event := events[i]
+			account := event.Get("data.object")
+
+			if seen[event.Get("id").String()] || account.Get("account_holder.customer").String() != customer {
+				continue
+			}
+			seen[event.Get("id").String()] = true
+
+			handle(Event{
+				ID:          event.Get("id").String(),
+				Type:        event.Get("type").String(),
+				Created:     time.Unix(event.Get("created").Int(), 0),
+				Account:     account.Get("id").String(),
+				Institution: account.Get("institution_name").String(),
+				Status:      account.Get("status").String(),
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
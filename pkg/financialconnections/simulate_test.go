@@ -0,0 +1,96 @@
+package financialconnections
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/requests"
+)
+
+func TestCreateSession(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/financial_connections/sessions", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "cus_123", r.PostForm.Get("account_holder[customer]"))
+		require.Equal(t, []string{"ownership", "payment_method"}, r.PostForm["permissions[]"])
+		require.Equal(t, []string{"ownership"}, r.PostForm["prefetch[]"])
+
+		w.Write([]byte(`{"id": "fcsess_123", "client_secret": "fcsess_123_secret"}`))
+	}))
+	defer ts.Close()
+
+	session, err := CreateSession(context.Background(), requests.Base{APIBaseURL: ts.URL}, "sk_test_123", "cus_123", []string{"ownership", "payment_method"})
+	require.NoError(t, err)
+	require.Equal(t, Session{ID: "fcsess_123", ClientSecret: "fcsess_123_secret"}, session)
+}
+
+func TestServeFlow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	url, results, err := ServeFlow(ctx, "pk_test_123", Session{ClientSecret: "fcsess_123_secret"}, "Test (Non-OAuth)")
+	require.NoError(t, err)
+
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Post(url+"/result", "application/json", strings.NewReader(`{"accounts": ["fca_123"]}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodPost, url+"/result", strings.NewReader(`{"accounts": ["fca_123"]}`))
+	req.Header.Set("Origin", url)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Equal(t, FlowResult{Accounts: []string{"fca_123"}}, <-results)
+}
+
+func TestFollowEvents(t *testing.T) {
+	defer func(interval time.Duration) { eventsPollInterval = interval }(eventsPollInterval)
+	eventsPollInterval = time.Millisecond
+
+	polls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "financial_connections.account.*", r.URL.Query().Get("type"))
+		require.Equal(t, "1700000000", r.URL.Query().Get("created[gte]"))
+
+		polls++
+		events := `{"id": "evt_1", "type": "financial_connections.account.created", "created": 1700000001, "data": {"object": {"id": "fca_1", "institution_name": "Test (Non-OAuth)", "status": "active", "account_holder": {"customer": "cus_123"}}}}`
+		if polls > 1 {
+			events = `{"id": "evt_3", "type": "financial_connections.account.refreshed_ownership", "created": 1700000003, "data": {"object": {"id": "fca_1", "account_holder": {"customer": "cus_123"}}}},` +
+				`{"id": "evt_2", "type": "financial_connections.account.created", "created": 1700000002, "data": {"object": {"id": "fca_2", "account_holder": {"customer": "cus_456"}}}},` +
+				events
+		}
+		w.Write([]byte(`{"object": "list", "data": [` + events + `]}`))
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var handled []Event
+	err := FollowEvents(ctx, requests.Base{APIBaseURL: ts.URL}, "sk_test_123", "cus_123", time.Unix(1700000000, 0), func(event Event) {
+		handled = append(handled, event)
+		if len(handled) == 2 {
+			cancel()
+		}
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []Event{
+		{ID: "evt_1", Type: "financial_connections.account.created", Created: time.Unix(1700000001, 0), Account: "fca_1", Institution: "Test (Non-OAuth)", Status: "active"},
+		{ID: "evt_3", Type: "financial_connections.account.refreshed_ownership", Created: time.Unix(1700000003, 0), Account: "fca_1"},
+	}, handled)
+}
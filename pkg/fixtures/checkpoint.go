@@ -0,0 +1,108 @@
+package fixtures
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"github.com/tidwall/gjson"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// checkpoint records the responses of the steps of a fixture run that
+// completed, so that the run can be resumed after a failure without creating
+// the same objects again
+type checkpoint struct {
+	Responses map[string]json.RawMessage `json:"responses"`
+}
+
+// CheckpointPath returns where the checkpoint of runs of the fixture file is
+// kept within dir
+func CheckpointPath(dir, file string) string {
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+
+	sum := sha256.Sum256([]byte(file))
+
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// ResumeError is returned by Execute when a step fails and completed steps
+// were saved to a checkpoint
+type ResumeError struct {
+	Err  error
+	Step string
+}
+
+func (e ResumeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e ResumeError) Unwrap() error {
+	return e.Err
+}
+
+// loadCheckpoint restores the responses of the steps completed by the last
+// run, returning their names
+func (fxt *Fixture) loadCheckpoint() (map[string]bool, error) {
+	completed := make(map[string]bool)
+
+	data, err := afero.ReadFile(fxt.Fs, fxt.CheckpointFile)
+	if os.IsNotExist(err) {
+		return completed, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, clierrors.Errorf(clierrors.InvalidCheckpoint, "failed to read checkpoint %s: %w", fxt.CheckpointFile, err)
+	}
+
+	for name, resp := range cp.Responses {
+		fxt.responses[name] = gjson.ParseBytes(resp)
+		completed[name] = true
+	}
+
+	return completed, nil
+}
+
+// saveCheckpoint writes the responses of the steps completed so far
+func (fxt *Fixture) saveCheckpoint() error {
+	cp := checkpoint{
+		Responses: make(map[string]json.RawMessage),
+	}
+
+	fxt.responsesMu.RLock()
+	for name, resp := range fxt.responses {
+		if resp.Raw != "" && gjson.Valid(resp.Raw) {
+			cp.Responses[name] = json.RawMessage(resp.Raw)
+		}
+	}
+	fxt.responsesMu.RUnlock()
+
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := fxt.Fs.MkdirAll(filepath.Dir(fxt.CheckpointFile), os.ModePerm); err != nil {
+		return err
+	}
+
+	return afero.WriteFile(fxt.Fs, fxt.CheckpointFile, data, 0600)
+}
+
+// removeCheckpoint forgets about the steps completed by the last run
+func (fxt *Fixture) removeCheckpoint() error {
+	if err := fxt.Fs.Remove(fxt.CheckpointFile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumeFromCheckpoint(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	captureFails := true
+	requested := make(map[string]int)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		requested[req.URL.Path]++
+
+		switch req.URL.Path {
+		case customersPath:
+			res.Write([]byte(`{"id": "cust_12345"}`))
+		case chargePath:
+			res.Write([]byte(`{"id": "char_12345"}`))
+		case capturePath:
+			if captureFails {
+				res.WriteHeader(http.StatusBadRequest)
+				res.Write([]byte(`{"error": {"type": "invalid_request_error"}}`))
+				return
+			}
+			res.Write([]byte(`{"id": "char_12345", "captured": true}`))
+		}
+	}))
+	defer ts.Close()
+
+	afero.WriteFile(fs, file, []byte(testFixture), os.ModePerm)
+	checkpointFile := CheckpointPath("/checkpoints", file)
+
+	fxt, err := NewFixtureFromFile(fs, apiKey, "", ts.URL, file, []string{}, []string{}, []string{}, []string{})
+	require.NoError(t, err)
+	fxt.CheckpointFile = checkpointFile
+
+	_, err = fxt.Execute(context.Background(), "")
+
+	var resumeErr ResumeError
+	require.True(t, errors.As(err, &resumeErr))
+	require.Equal(t, "capt_bender", resumeErr.Step)
+
+	exists, _ := afero.Exists(fs, checkpointFile)
+	require.True(t, exists)
+
+	// resuming skips the completed steps but still resolves their references
+	captureFails = false
+
+	fxt, err = NewFixtureFromFile(fs, apiKey, "", ts.URL, file, []string{}, []string{}, []string{}, []string{})
+	require.NoError(t, err)
+	fxt.CheckpointFile = checkpointFile
+	fxt.Resume = true
+
+	_, err = fxt.Execute(context.Background(), "")
+	require.NoError(t, err)
+
+	require.Equal(t, 1, requested[customersPath])
+	require.Equal(t, 1, requested[chargePath])
+	require.Equal(t, 2, requested[capturePath])
+	require.True(t, fxt.responses["capt_bender"].Get("captured").Bool())
+
+	exists, _ = afero.Exists(fs, checkpointFile)
+	require.False(t, exists)
+}
+
+func TestRunWithoutResumeIgnoresCheckpoint(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	requested := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		requested++
+		res.Write([]byte(`{"id": "obj_12345"}`))
+	}))
+	defer ts.Close()
+
+	afero.WriteFile(fs, file, []byte(testFixture), os.ModePerm)
+	checkpointFile := CheckpointPath("/checkpoints", file)
+	afero.WriteFile(fs, checkpointFile, []byte(`{"responses": {"cust_bender": {"id": "cust_old"}}}`), os.ModePerm)
+
+	fxt, err := NewFixtureFromFile(fs, apiKey, "", ts.URL, file, []string{}, []string{}, []string{}, []string{})
+	require.NoError(t, err)
+	fxt.CheckpointFile = checkpointFile
+
+	_, err = fxt.Execute(context.Background(), "")
+	require.NoError(t, err)
+	require.Equal(t, 3, requested)
+	require.Equal(t, "obj_12345", fxt.responses["cust_bender"].Get("id").String())
+}
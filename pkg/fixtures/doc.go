@@ -0,0 +1,18 @@
+// Package fixtures runs fixture files, the JSON documents describing a
+// sequence of API requests that `stripe fixtures` and `stripe trigger` use to
+// populate an account with data.
+//
+// Programs embedding the CLI can rely on the following, which keep their
+// signatures within a major version of the CLI:
+//
+//   - NewFixtureFromFile, NewFixtureFromBytes and NewFixtureFromRawString
+//   - Fixture.Execute, along with the Metadata and StrictAmounts fields that
+//     customize the requests it makes
+//   - Trigger and EventNames, to run the built-in triggers
+//   - RegisterTrigger and RegisterStepType, with TriggerSource, Step and
+//     StepHandler, to extend them
+//
+// The format of fixture files is versioned separately, by their
+// template_version. Checkpoints, rollbacks and exports back `stripe fixtures`
+// flags and may change with them.
+package fixtures
@@ -0,0 +1,310 @@
+package fixtures
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/requests"
+)
+
+// exportableResource describes how an object is turned back into the
+// parameters needed to create an equivalent one
+type exportableResource struct {
+	// name is used to name the fixture steps created for this resource
+	name string
+	// path is the endpoint used both to retrieve and to create the object
+	path string
+	// params are the fields of the object that can be sent back on creation.
+	// Nested objects can be limited to some of their fields with a dot path.
+	params []string
+	// references are the fields pointing to other objects that need to be
+	// created first
+	references []string
+	// customize adjusts the exported params for fields that cannot be
+	// exported as-is
+	customize func(object map[string]interface{}, params map[string]interface{})
+}
+
+// exportableResources maps ID prefixes onto the resources that can be exported
+var exportableResources = map[string]exportableResource{
+	"cus": {
+		name:   "customer",
+		path:   "/v1/customers",
+		params: []string{"name", "email", "phone", "description", "address", "shipping", "preferred_locales", "metadata"},
+	},
+	"pm": {
+		name:   "payment_method",
+		path:   "/v1/payment_methods",
+		params: []string{"type", "billing_details", "metadata"},
+		customize: func(object map[string]interface{}, params map[string]interface{}) {
+			// card details can't be read back, so recreate the card from a test token
+			if object["type"] == "card" {
+				params["card"] = map[string]interface{}{"token": "tok_visa"}
+			}
+		},
+	},
+	"pi": {
+		name:       "payment_intent",
+		path:       "/v1/payment_intents",
+		params:     []string{"amount", "currency", "description", "capture_method", "payment_method_types", "receipt_email", "setup_future_usage", "shipping", "statement_descriptor", "metadata"},
+		references: []string{"customer", "payment_method"},
+	},
+	"in": {
+		name:       "invoice",
+		path:       "/v1/invoices",
+		params:     []string{"description", "collection_method", "days_until_due", "footer", "metadata"},
+		references: []string{"customer"},
+	},
+	"prod": {
+		name:   "product",
+		path:   "/v1/products",
+		params: []string{"name", "description", "active", "unit_label", "statement_descriptor", "metadata"},
+	},
+	"price": {
+		name:       "price",
+		path:       "/v1/prices",
+		params:     []string{"currency", "unit_amount", "nickname", "billing_scheme", "recurring.interval", "recurring.interval_count", "tax_behavior", "metadata"},
+		references: []string{"product"},
+	},
+}
+
+// Exporter builds fixtures that recreate existing objects
+type Exporter struct {
+	APIKey        string
+	StripeAccount string
+	BaseURL       string
+
+	// WithRelated also exports the objects the exported object references
+	WithRelated bool
+
+	steps   []fixture
+	names   map[string]string
+	counter map[string]int
+}
+
+// Export fetches the object with the given ID, and its related objects when
+// WithRelated is set, and returns a fixture file that recreates them
+func (e *Exporter) Export(ctx context.Context, id string) ([]byte, error) {
+	e.steps = []fixture{}
+	e.names = make(map[string]string)
+	e.counter = make(map[string]int)
+
+	if _, err := e.export(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(fixtureFile{
+		Meta:     metaFixture{Version: SupportedVersions},
+		Fixtures: e.steps,
+	}, "", "  ")
+}
+
+// export adds the steps needed to recreate the object, after the steps of the
+// objects it depends on, and returns the name of the object's step
+func (e *Exporter) export(ctx context.Context, id string) (string, error) {
+	if name, ok := e.names[id]; ok {
+		return name, nil
+	}
+
+	resource, ok := lookUpExportableResource(id)
+	if !ok {
+		return "", clierrors.Errorf(clierrors.ExportNotSupported, "exporting '%s' is not supported", id)
+	}
+
+	object, err := e.retrieve(ctx, resource.path, id)
+	if err != nil {
+		return "", err
+	}
+
+	// the payment intent of an invoice can't be created on its own, it's
+	// created by finalizing the invoice
+	if invoiceID := referencedID(object["invoice"]); e.WithRelated && resource.name == "payment_intent" && invoiceID != "" {
+		return e.exportInvoicePayment(ctx, id, invoiceID, object)
+	}
+
+	params := make(map[string]interface{})
+	for _, field := range resource.params {
+		copyField(object, params, strings.Split(field, "."))
+	}
+
+	if resource.customize != nil {
+		resource.customize(object, params)
+	}
+
+	if e.WithRelated {
+		for _, field := range resource.references {
+			relatedID := referencedID(object[field])
+			if relatedID == "" {
+				continue
+			}
+
+			if _, ok := lookUpExportableResource(relatedID); !ok {
+				continue
+			}
+
+			relatedName, err := e.export(ctx, relatedID)
+			if err != nil {
+				return "", err
+			}
+
+			params[field] = fmt.Sprintf("${%s:id}", relatedName)
+		}
+	}
+
+	name := e.addStep(resource.name, "post", resource.path, params)
+	e.names[id] = name
+
+	return name, nil
+}
+
+// exportInvoicePayment adds the steps recreating the payment intent of an
+// invoice: the invoice, an item for the amount of the payment intent, and the
+// finalization of the invoice, which creates the payment intent
+func (e *Exporter) exportInvoicePayment(ctx context.Context, id, invoiceID string, object map[string]interface{}) (string, error) {
+	invoiceName, err := e.export(ctx, invoiceID)
+	if err != nil {
+		return "", err
+	}
+
+	e.addStep("invoiceitem", "post", "/v1/invoiceitems", map[string]interface{}{
+		"amount":   object["amount"],
+		"currency": object["currency"],
+		"customer": fmt.Sprintf("${%s:customer}", invoiceName),
+		"invoice":  fmt.Sprintf("${%s:id}", invoiceName),
+	})
+
+	finalizeName := e.addStep(invoiceName+"_finalize", "post", fmt.Sprintf("/v1/invoices/${%s:id}/finalize", invoiceName), map[string]interface{}{})
+
+	name := e.addStep("payment_intent", "get", fmt.Sprintf("/v1/payment_intents/${%s:payment_intent}", finalizeName), map[string]interface{}{})
+	e.names[id] = name
+
+	return name, nil
+}
+
+// addStep adds a step, numbering its name when there's already a step of that
+// name, and returns its name
+func (e *Exporter) addStep(name, method, path string, params map[string]interface{}) string {
+	e.counter[name]++
+	if e.counter[name] > 1 {
+		name = fmt.Sprintf("%s_%d", name, e.counter[name])
+	}
+
+	e.steps = append(e.steps, fixture{
+		Name:   name,
+		Path:   path,
+		Method: method,
+		Params: params,
+	})
+
+	return name
+}
+
+func (e *Exporter) retrieve(ctx context.Context, path, id string) (map[string]interface{}, error) {
+	params := &requests.RequestParameters{}
+	params.SetStripeAccount(e.StripeAccount)
+
+	req := requests.Base{
+		Method:         http.MethodGet,
+		SuppressOutput: true,
+		APIBaseURL:     e.BaseURL,
+	}
+
+	resp, err := req.MakeRequest(ctx, e.APIKey, fmt.Sprintf("%s/%s", path, id), params, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(resp, &object); err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
+
+func lookUpExportableResource(id string) (exportableResource, bool) {
+	prefix := strings.SplitN(id, "_", 2)[0]
+	resource, ok := exportableResources[prefix]
+
+	return resource, ok
+}
+
+// referencedID returns the ID of a reference, whether it was expanded or not
+func referencedID(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		if id, ok := v["id"].(string); ok {
+			return id
+		}
+	}
+
+	return ""
+}
+
+// copyField copies the field at path from src to dst, leaving out empty values
+func copyField(src, dst map[string]interface{}, path []string) {
+	value, ok := src[path[0]]
+	if !ok || isEmptyValue(value) {
+		return
+	}
+
+	if len(path) == 1 {
+		dst[path[0]] = stripEmptyValues(value)
+		return
+	}
+
+	nested, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	nestedDst, ok := dst[path[0]].(map[string]interface{})
+	if !ok {
+		nestedDst = make(map[string]interface{})
+	}
+
+	copyField(nested, nestedDst, path[1:])
+
+	if len(nestedDst) > 0 {
+		dst[path[0]] = nestedDst
+	}
+}
+
+// stripEmptyValues removes the null and empty fields of nested objects, which
+// can't be sent back to the API
+func stripEmptyValues(value interface{}) interface{} {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+
+	stripped := make(map[string]interface{})
+	for k, v := range m {
+		if !isEmptyValue(v) {
+			stripped[k] = stripEmptyValues(v)
+		}
+	}
+
+	return stripped
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+
+	return false
+}
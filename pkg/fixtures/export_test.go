@@ -0,0 +1,164 @@
+package fixtures
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newExportTestServer(t *testing.T) *httptest.Server {
+	objects := map[string]string{
+		"/v1/payment_intents/pi_123": `{
+			"id": "pi_123",
+			"object": "payment_intent",
+			"amount": 2000,
+			"currency": "usd",
+			"created": 1600000000,
+			"livemode": false,
+			"description": null,
+			"customer": "cus_123",
+			"payment_method": "pm_123",
+			"payment_method_types": ["card"],
+			"metadata": {}
+		}`,
+		"/v1/payment_intents/pi_456": `{
+			"id": "pi_456",
+			"object": "payment_intent",
+			"amount": 1500,
+			"currency": "usd",
+			"customer": "cus_123",
+			"invoice": "in_123",
+			"payment_method_types": ["card"]
+		}`,
+		"/v1/invoices/in_123": `{
+			"id": "in_123",
+			"object": "invoice",
+			"customer": "cus_123",
+			"collection_method": "charge_automatically",
+			"payment_intent": "pi_456"
+		}`,
+		"/v1/customers/cus_123": `{
+			"id": "cus_123",
+			"object": "customer",
+			"created": 1600000000,
+			"email": "jenny@example.com",
+			"address": {"city": "San Francisco", "line1": null}
+		}`,
+		"/v1/payment_methods/pm_123": `{
+			"id": "pm_123",
+			"object": "payment_method",
+			"type": "card",
+			"card": {"last4": "4242"},
+			"customer": "cus_123"
+		}`,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodGet, req.Method)
+
+		object, ok := objects[req.URL.Path]
+		if !ok {
+			t.Errorf("Received an unexpected request URL: %s", req.URL.String())
+		}
+		res.Write([]byte(object))
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestExport(t *testing.T) {
+	ts := newExportTestServer(t)
+
+	exporter := Exporter{APIKey: apiKey, BaseURL: ts.URL}
+	out, err := exporter.Export(context.Background(), "pi_123")
+	require.NoError(t, err)
+
+	var exported fixtureFile
+	require.NoError(t, json.Unmarshal(out, &exported))
+	require.Len(t, exported.Fixtures, 1)
+
+	step := exported.Fixtures[0]
+	require.Equal(t, "payment_intent", step.Name)
+	require.Equal(t, "/v1/payment_intents", step.Path)
+	require.Equal(t, "post", step.Method)
+	require.Equal(t, map[string]interface{}{
+		"amount":               float64(2000),
+		"currency":             "usd",
+		"payment_method_types": []interface{}{"card"},
+	}, step.Params)
+}
+
+func TestExportWithRelated(t *testing.T) {
+	ts := newExportTestServer(t)
+
+	exporter := Exporter{APIKey: apiKey, BaseURL: ts.URL, WithRelated: true}
+	out, err := exporter.Export(context.Background(), "pi_123")
+	require.NoError(t, err)
+
+	var exported fixtureFile
+	require.NoError(t, json.Unmarshal(out, &exported))
+	require.Len(t, exported.Fixtures, 3)
+
+	require.Equal(t, "customer", exported.Fixtures[0].Name)
+	require.Equal(t, map[string]interface{}{
+		"email":   "jenny@example.com",
+		"address": map[string]interface{}{"city": "San Francisco"},
+	}, exported.Fixtures[0].Params)
+
+	require.Equal(t, "payment_method", exported.Fixtures[1].Name)
+	require.Equal(t, map[string]interface{}{
+		"type": "card",
+		"card": map[string]interface{}{"token": "tok_visa"},
+	}, exported.Fixtures[1].Params)
+
+	require.Equal(t, "payment_intent", exported.Fixtures[2].Name)
+	require.Equal(t, "${customer:id}", exported.Fixtures[2].Params["customer"])
+	require.Equal(t, "${payment_method:id}", exported.Fixtures[2].Params["payment_method"])
+}
+
+func TestExportInvoicePaymentWithRelated(t *testing.T) {
+	ts := newExportTestServer(t)
+
+	exporter := Exporter{APIKey: apiKey, BaseURL: ts.URL, WithRelated: true}
+	out, err := exporter.Export(context.Background(), "pi_456")
+	require.NoError(t, err)
+
+	var exported fixtureFile
+	require.NoError(t, json.Unmarshal(out, &exported))
+	require.Len(t, exported.Fixtures, 5)
+
+	require.Equal(t, "customer", exported.Fixtures[0].Name)
+
+	require.Equal(t, "invoice", exported.Fixtures[1].Name)
+	require.Equal(t, "${customer:id}", exported.Fixtures[1].Params["customer"])
+
+	require.Equal(t, fixture{
+		Name:   "invoiceitem",
+		Path:   "/v1/invoiceitems",
+		Method: "post",
+		Params: map[string]interface{}{
+			"amount":   float64(1500),
+			"currency": "usd",
+			"customer": "${invoice:customer}",
+			"invoice":  "${invoice:id}",
+		},
+	}, exported.Fixtures[2])
+
+	require.Equal(t, "invoice_finalize", exported.Fixtures[3].Name)
+	require.Equal(t, "/v1/invoices/${invoice:id}/finalize", exported.Fixtures[3].Path)
+
+	require.Equal(t, "payment_intent", exported.Fixtures[4].Name)
+	require.Equal(t, "get", exported.Fixtures[4].Method)
+	require.Equal(t, "/v1/payment_intents/${invoice_finalize:payment_intent}", exported.Fixtures[4].Path)
+}
+
+func TestExportUnsupportedObject(t *testing.T) {
+	exporter := Exporter{APIKey: apiKey}
+	_, err := exporter.Export(context.Background(), "acct_123")
+	require.EqualError(t, err, "exporting 'acct_123' is not supported")
+}
@@ -10,6 +10,7 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/imdario/mergo"
@@ -17,6 +18,7 @@ import (
 	"github.com/spf13/afero"
 	"github.com/tidwall/gjson"
 
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/requests"
 )
 
@@ -26,6 +28,9 @@ const SupportedVersions = 0
 type metaFixture struct {
 	Version         int  `json:"template_version"`
 	ExcludeMetadata bool `json:"exclude_metadata"`
+	// Transactional reverses the objects created by the fixture when one of
+	// its steps fails
+	Transactional bool `json:"transactional,omitempty"`
 }
 
 type fixtureFile struct {
@@ -40,6 +45,9 @@ type fixture struct {
 	Path              string                 `json:"path"`
 	Method            string                 `json:"method"`
 	Params            map[string]interface{} `json:"params"`
+	// DependsOn lists earlier steps which must complete before this one,
+	// beyond those it references, when steps run in parallel
+	DependsOn []string `json:"depends_on,omitempty"`
 }
 
 type fixtureQuery struct {
@@ -59,8 +67,36 @@ type Fixture struct {
 	Additions     map[string]interface{}
 	Removals      map[string]interface{}
 	BaseURL       string
-	responses     map[string]gjson.Result
-	fixture       fixtureFile
+
+	// Metadata is added to the objects created by the fixture, unless the
+	// fixture excludes metadata
+	Metadata map[string]string
+
+	// CheckpointFile is where the responses of completed steps are saved,
+	// until every step completed. Nothing is saved when empty.
+	CheckpointFile string
+	// Resume skips the steps completed by the last run, as saved to
+	// CheckpointFile, and reuses their responses
+	Resume bool
+
+	// Backoffs is told when a step was rate limited and waits to be retried
+	Backoffs requests.BackoffObserver
+
+	// StrictAmounts sends the amounts of the params as written, rather than
+	// converting amounts like "$20.00" to the smallest currency unit
+	StrictAmounts bool
+
+	// MaxParallel is how many steps run at once. A step still waits for the
+	// steps it depends on. 0 and 1 run the steps one after the other.
+	MaxParallel int
+
+	responsesMu sync.RWMutex
+	responses   map[string]gjson.Result
+	fixture     fixtureFile
+
+	// completed are the steps completed by this run, in the order they
+	// completed
+	completed []string
 }
 
 // NewFixtureFromFile creates a to later run steps for populating test data
@@ -94,27 +130,55 @@ func NewFixtureFromFile(fs afero.Fs, apiKey, stripeAccount, baseURL, file string
 		}
 	}
 
-	err = json.Unmarshal(filedata, &fxt.fixture)
-	if err != nil {
+	if err := fxt.load(filedata, override, add, remove); err != nil {
+		return nil, err
+	}
+
+	return &fxt, nil
+}
+
+// NewFixtureFromBytes creates a fixture from already loaded fixture content,
+// such as a trigger definition supplied by a plugin
+func NewFixtureFromBytes(fs afero.Fs, apiKey, stripeAccount, baseURL string, filedata []byte, skip, override, add, remove []string) (*Fixture, error) {
+	fxt := Fixture{
+		Fs:            fs,
+		APIKey:        apiKey,
+		StripeAccount: stripeAccount,
+		Skip:          skip,
+		BaseURL:       baseURL,
+		responses:     make(map[string]gjson.Result),
+	}
+
+	if err := fxt.load(filedata, override, add, remove); err != nil {
 		return nil, err
 	}
 
+	return &fxt, nil
+}
+
+// load parses the fixture content and applies any customizations to it
+func (fxt *Fixture) load(filedata []byte, override, add, remove []string) error {
+	err := json.Unmarshal(filedata, &fxt.fixture)
+	if err != nil {
+		return err
+	}
+
 	// Customize fixture data
 	if err := fxt.Override(override); err != nil {
-		return nil, err
+		return err
 	}
 	if err := fxt.Add(add); err != nil {
-		return nil, err
+		return err
 	}
 	if err := fxt.Remove(remove); err != nil {
-		return nil, err
+		return err
 	}
 
 	if fxt.fixture.Meta.Version > SupportedVersions {
-		return nil, fmt.Errorf("Fixture version not supported: %s", fmt.Sprint(fxt.fixture.Meta.Version))
+		return clierrors.Errorf(clierrors.UnsupportedFixtureVersion, "Fixture version not supported: %s", fmt.Sprint(fxt.fixture.Meta.Version))
 	}
 
-	return &fxt, nil
+	return nil
 }
 
 // NewFixtureFromRawString creates fixtures from user inputted string
@@ -134,7 +198,7 @@ func NewFixtureFromRawString(fs afero.Fs, apiKey, stripeAccount, baseURL, raw st
 	}
 
 	if fxt.fixture.Meta.Version > SupportedVersions {
-		return nil, fmt.Errorf("Fixture version not supported: %s", fmt.Sprint(fxt.fixture.Meta.Version))
+		return nil, clierrors.Errorf(clierrors.UnsupportedFixtureVersion, "Fixture version not supported: %s", fmt.Sprint(fxt.fixture.Meta.Version))
 	}
 
 	return &fxt, nil
@@ -253,27 +317,87 @@ func (fxt *Fixture) Remove(removals []string) error {
 // Execute takes the parsed fixture file and runs through all the requests
 // defined to populate the user's account
 func (fxt *Fixture) Execute(ctx context.Context, apiVersion string) ([]string, error) {
+	completed := make(map[string]bool)
+	if fxt.CheckpointFile != "" {
+		var err error
+		if fxt.Resume {
+			completed, err = fxt.loadCheckpoint()
+		} else {
+			err = fxt.removeCheckpoint()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out := newOrderedOutput(len(fxt.fixture.Fixtures))
+
 	requestNames := make([]string, len(fxt.fixture.Fixtures))
+	var steps []int
 	for i, data := range fxt.fixture.Fixtures {
 		if isNameIn(data.Name, fxt.Skip) {
-			fmt.Printf("Skipping fixture for: %s\n", data.Name)
+			out.print(i, fmt.Sprintf("Skipping fixture for: %s\n", data.Name))
 			continue
 		}
 
-		fmt.Printf("Setting up fixture for: %s\n", data.Name)
 		requestNames[i] = data.Name
 
-		fmt.Printf("Running fixture for: %s\n", data.Name)
-		resp, err := fxt.makeRequest(ctx, data, apiVersion)
-		if err != nil && !errWasExpected(err, data.ExpectedErrorType) {
-			return nil, err
+		if completed[data.Name] {
+			out.print(i, fmt.Sprintf("Reusing result of completed fixture for: %s\n", data.Name))
+			continue
+		}
+
+		steps = append(steps, i)
+	}
+
+	failedStep, err := fxt.runSteps(ctx, apiVersion, steps, out)
+	if err != nil && failedStep != "" && fxt.fixture.Meta.Transactional {
+		remaining := fxt.rollback(ctx, apiVersion)
+
+		// the objects of the completed steps are gone, so there is nothing
+		// to resume from
+		if fxt.CheckpointFile != "" {
+			if err := fxt.removeCheckpoint(); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, RollbackError{Err: err, Step: failedStep, Remaining: remaining}
+	}
+	if err != nil {
+		if failedStep != "" && fxt.CheckpointFile != "" && len(fxt.responses) > 0 {
+			if saveErr := fxt.saveCheckpoint(); saveErr == nil {
+				return nil, ResumeError{Err: err, Step: failedStep}
+			}
 		}
+		return nil, err
+	}
 
-		fxt.responses[data.Name] = gjson.ParseBytes(resp)
+	if fxt.CheckpointFile != "" {
+		if err := fxt.removeCheckpoint(); err != nil {
+			return nil, err
+		}
 	}
+
 	return requestNames, nil
 }
 
+// response returns the response of a completed step
+func (fxt *Fixture) response(name string) (gjson.Result, bool) {
+	fxt.responsesMu.RLock()
+	defer fxt.responsesMu.RUnlock()
+
+	resp, ok := fxt.responses[name]
+	return resp, ok
+}
+
+func (fxt *Fixture) setResponse(name string, resp gjson.Result) {
+	fxt.responsesMu.Lock()
+	defer fxt.responsesMu.Unlock()
+
+	fxt.responses[name] = resp
+}
+
 func errWasExpected(err error, expectedErrorType string) bool {
 	if rerr, ok := err.(requests.RequestError); ok {
 		return rerr.ErrorType == expectedErrorType
@@ -292,6 +416,10 @@ func (fxt *Fixture) UpdateEnv() error {
 }
 
 func (fxt *Fixture) makeRequest(ctx context.Context, data fixture, apiVersion string) ([]byte, error) {
+	if handler, ok := stepTypes[data.Method]; ok {
+		return fxt.runCustomStep(ctx, handler, data)
+	}
+
 	var rp requests.RequestParameters
 
 	if data.Method == "post" && !fxt.fixture.Meta.ExcludeMetadata {
@@ -301,10 +429,13 @@ func (fxt *Fixture) makeRequest(ctx context.Context, data fixture, apiVersion st
 	}
 
 	req := requests.Base{
-		Method:         strings.ToUpper(data.Method),
-		SuppressOutput: true,
-		APIBaseURL:     fxt.BaseURL,
-		Parameters:     rp,
+		Method:           strings.ToUpper(data.Method),
+		SuppressOutput:   true,
+		APIBaseURL:       fxt.BaseURL,
+		Parameters:       rp,
+		RetryRateLimited: true,
+		Worker:           data.Name,
+		Backoffs:         fxt.Backoffs,
 	}
 
 	path, err := fxt.parsePath(data)
@@ -319,9 +450,34 @@ func (fxt *Fixture) makeRequest(ctx context.Context, data fixture, apiVersion st
 		return make([]byte, 0), err
 	}
 
+	if data.Method == "post" && !fxt.fixture.Meta.ExcludeMetadata && requests.AcceptsMetadata(path) {
+		params.AddDefaultMetadata(fxt.Metadata)
+	}
+
 	return req.MakeRequest(ctx, fxt.APIKey, path, params, true)
 }
 
+// runCustomStep resolves the references in a step and hands it off to the
+// handler registered for its method
+func (fxt *Fixture) runCustomStep(ctx context.Context, handler StepHandler, data fixture) ([]byte, error) {
+	path, err := fxt.parsePath(data)
+	if err != nil {
+		return make([]byte, 0), err
+	}
+
+	params, err := fxt.parseInterface(data.Params)
+	if err != nil {
+		return make([]byte, 0), err
+	}
+
+	return handler(ctx, Step{
+		Name:   data.Name,
+		Method: data.Method,
+		Path:   path,
+		Params: params,
+	})
+}
+
 func (fxt *Fixture) createParams(params interface{}, apiVersion string) (*requests.RequestParameters, error) {
 	requestParams := requests.RequestParameters{}
 	parsed, err := fxt.parseInterface(params)
@@ -330,6 +486,12 @@ func (fxt *Fixture) createParams(params interface{}, apiVersion string) (*reques
 	}
 	requestParams.AppendData(parsed)
 
+	if !fxt.StrictAmounts {
+		if err := requestParams.ConvertAmounts(); err != nil {
+			return &requestParams, err
+		}
+	}
+
 	requestParams.SetStripeAccount(fxt.StripeAccount)
 
 	if apiVersion != "" {
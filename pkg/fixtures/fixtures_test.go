@@ -259,6 +259,102 @@ func TestMakeRequestWithAdd(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestMakeRequestWithMetadata(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	ts := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Errorf("Failure with request body: %s", err)
+		}
+
+		// capturing acts on the charge, which doesn't take metadata
+		require.Equal(t, req.URL.String() != capturePath, strings.Contains(string(body), "metadata[created_by]=stripe-cli"))
+
+		switch url := req.URL.String(); url {
+		case customersPath:
+			res.Write([]byte(`{"id": "cust_12345", "foo": "bar"}`))
+		case chargePath:
+			res.Write([]byte(`{"charge": true, "id": "char_12345"}`))
+		case capturePath:
+			res.Write([]byte(`{}`))
+		default:
+			t.Errorf("Received an unexpected request URL: %s", req.URL.String())
+		}
+	}))
+
+	defer func() { ts.Close() }()
+
+	afero.WriteFile(fs, file, []byte(testFixture), os.ModePerm)
+
+	fxt, err := NewFixtureFromFile(fs, apiKey, "", ts.URL, file, []string{}, []string{}, []string{}, []string{})
+	require.NoError(t, err)
+
+	fxt.Metadata = map[string]string{"created_by": "stripe-cli"}
+
+	_, err = fxt.Execute(context.Background(), "")
+	require.NoError(t, err)
+}
+
+func TestTriggerWithMetadataOnActionStep(t *testing.T) {
+	bodies := make(map[string]string)
+	ts := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		bodies[req.URL.Path] = string(body)
+
+		switch req.URL.Path {
+		case "/v1/customers":
+			res.Write([]byte(`{"id": "cus_123"}`))
+		case "/v1/invoiceitems":
+			res.Write([]byte(`{"id": "ii_123"}`))
+		case "/v1/invoices":
+			res.Write([]byte(`{"id": "in_123"}`))
+		default:
+			res.Write([]byte(`{"id": "in_123", "status": "paid"}`))
+		}
+	}))
+	defer ts.Close()
+
+	_, err := Trigger(context.Background(), "invoice.paid", "", ts.URL, apiKey, []string{}, []string{}, []string{}, []string{}, "", "", map[string]string{"created_by": "stripe-cli"})
+	require.NoError(t, err)
+
+	require.Len(t, bodies, 4)
+	for _, path := range []string{"/v1/customers", "/v1/invoiceitems", "/v1/invoices"} {
+		require.Contains(t, bodies[path], "metadata[created_by]=stripe-cli")
+	}
+	require.NotContains(t, bodies["/v1/invoices/in_123/pay"], "metadata[created_by]")
+}
+
+func TestMakeRequestConvertsAmounts(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+		res.Write([]byte(`{"id": "pi_12345"}`))
+	}))
+	defer ts.Close()
+
+	fixture := `{"_meta": {"template_version": 0, "exclude_metadata": true}, "fixtures": [{"name": "pi", "path": "/v1/payment_intents", "method": "post", "params": {"amount": "15,30EUR", "currency": "eur"}}]}`
+	afero.WriteFile(fs, file, []byte(fixture), os.ModePerm)
+
+	fxt, err := NewFixtureFromFile(fs, apiKey, "", ts.URL, file, []string{}, []string{}, []string{}, []string{})
+	require.NoError(t, err)
+
+	_, err = fxt.Execute(context.Background(), "")
+	require.NoError(t, err)
+
+	fxt.StrictAmounts = true
+	_, err = fxt.Execute(context.Background(), "")
+	require.NoError(t, err)
+
+	require.Len(t, bodies, 2)
+	require.Contains(t, bodies[0], "amount=1530")
+	require.Contains(t, bodies[1], "amount=15%2C30EUR")
+}
+
 func TestMakeRequestWithRemove(t *testing.T) {
 	fs := afero.NewMemMapFs()
 	ts := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
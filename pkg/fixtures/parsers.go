@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 )
 
 // The functions in this file are responsible for taking the JSON
@@ -253,6 +254,9 @@ func normalizeForComparison(x string) string {
 }
 
 func findSimilarQueryNames(fxt *Fixture, name string) ([]string, bool) {
+	fxt.responsesMu.RLock()
+	defer fxt.responsesMu.RUnlock()
+
 	keys := make([]string, 0, len(fxt.responses))
 	for k := range fxt.responses {
 		a := normalizeForComparison(k)
@@ -296,7 +300,8 @@ func (fxt *Fixture) parseQuery(queryString string) (string, error) {
 			return value, nil
 		}
 
-		if _, ok := fxt.responses[name]; !ok {
+		resp, ok := fxt.response(name)
+		if !ok {
 			// An undeclared fixture name is being referenced
 			var errorStrings []string
 			color := ansi.Color(os.Stdout)
@@ -318,10 +323,10 @@ func (fxt *Fixture) parseQuery(queryString string) (string, error) {
 				errorStrings = append(errorStrings, suggestions)
 			}
 
-			return "", fmt.Errorf(strings.Join(errorStrings, "\n"))
+			return "", clierrors.New(clierrors.InvalidFixture, strings.Join(errorStrings, "\n"))
 		}
 
-		result := fxt.responses[name].Get(query.Query)
+		result := resp.Get(query.Query)
 		if len(result.String()) != 0 {
 			return result.String(), nil
 		}
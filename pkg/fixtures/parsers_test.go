@@ -59,7 +59,7 @@ func TestParsePathReferenceErrorWithSuggestion(t *testing.T) {
 		ansi.Bold("char"),
 	)
 
-	assert.Equal(t, expected, err)
+	assert.EqualError(t, err, expected.Error())
 }
 
 func TestParsePathReferenceErrorNoSuggestion(t *testing.T) {
@@ -81,7 +81,7 @@ func TestParsePathReferenceErrorNoSuggestion(t *testing.T) {
 		ansi.Bold("foo"),
 	)
 
-	assert.Equal(t, expected, err)
+	assert.EqualError(t, err, expected.Error())
 }
 
 func TestParseQueryReferenceErrorWithSuggestion(t *testing.T) {
@@ -100,7 +100,7 @@ func TestParseQueryReferenceErrorWithSuggestion(t *testing.T) {
 		ansi.Bold("bender"),
 	)
 
-	assert.Equal(t, expected, err)
+	assert.EqualError(t, err, expected.Error())
 }
 
 func TestParseQueryReferenceErrorNoSuggestion(t *testing.T) {
@@ -119,7 +119,7 @@ func TestParseQueryReferenceErrorNoSuggestion(t *testing.T) {
 		ansi.Bold("foo"),
 	)
 
-	assert.Equal(t, expected, err)
+	assert.EqualError(t, err, expected.Error())
 }
 
 func TestParseTwoParam(t *testing.T) {
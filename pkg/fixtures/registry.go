@@ -0,0 +1,63 @@
+package fixtures
+
+import (
+	"context"
+	"strings"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// TriggerSource loads the fixture content for a trigger that is not built into the CLI
+type TriggerSource func(ctx context.Context, event string) ([]byte, error)
+
+// Step is a fixture step with all of its references resolved, as handed off
+// to a custom step handler
+type Step struct {
+	Name   string
+	Method string
+	Path   string
+	Params []string
+}
+
+// StepHandler runs a custom fixture step and returns its JSON response so that
+// later steps can reference it
+type StepHandler func(ctx context.Context, step Step) ([]byte, error)
+
+// externalTriggers holds the triggers contributed at runtime, e.g. by plugins
+var externalTriggers = make(map[string]TriggerSource)
+
+// stepTypes holds the custom step types contributed at runtime, keyed by method
+var stepTypes = make(map[string]StepHandler)
+
+// RegisterTrigger makes an additional event available to `stripe trigger`.
+// Built-in triggers cannot be replaced.
+func RegisterTrigger(event string, source TriggerSource) error {
+	if _, ok := Events[event]; ok {
+		return clierrors.Errorf(clierrors.PluginConflict, "the event '%s' is already supported by the Stripe CLI", event)
+	}
+
+	if _, ok := externalTriggers[event]; ok {
+		return clierrors.Errorf(clierrors.PluginConflict, "the event '%s' has already been registered", event)
+	}
+
+	externalTriggers[event] = source
+
+	return nil
+}
+
+// RegisterStepType makes an additional step method available to fixtures.
+// The built-in HTTP methods cannot be replaced.
+func RegisterStepType(method string, handler StepHandler) error {
+	switch strings.ToLower(method) {
+	case "get", "post", "delete":
+		return clierrors.Errorf(clierrors.PluginConflict, "the step type '%s' is reserved by the Stripe CLI", method)
+	}
+
+	if _, ok := stepTypes[method]; ok {
+		return clierrors.Errorf(clierrors.PluginConflict, "the step type '%s' has already been registered", method)
+	}
+
+	stepTypes[method] = handler
+
+	return nil
+}
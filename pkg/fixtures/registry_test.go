@@ -0,0 +1,89 @@
+package fixtures
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const pluginTestFixture = `
+{
+	"_meta": {
+		"template_version": 0
+	},
+	"fixtures": [
+		{
+			"name": "tax_settings",
+			"path": "/v1/tax/settings",
+			"method": "tax_update",
+			"params": {
+				"defaults": {
+					"tax_code": "txcd_10000000"
+				}
+			}
+		},
+		{
+			"name": "tax_settings_again",
+			"path": "/v1/tax/settings/${tax_settings:id}",
+			"method": "tax_update"
+		}
+	]
+}`
+
+func resetRegistry(t *testing.T) {
+	t.Cleanup(func() {
+		externalTriggers = make(map[string]TriggerSource)
+		stepTypes = make(map[string]StepHandler)
+	})
+}
+
+func TestRegisterTrigger(t *testing.T) {
+	resetRegistry(t)
+
+	source := func(ctx context.Context, event string) ([]byte, error) {
+		return []byte(pluginTestFixture), nil
+	}
+
+	require.NoError(t, RegisterTrigger("tax.settings.updated", source))
+	require.Contains(t, EventNames(), "tax.settings.updated")
+	require.Contains(t, EventList(), "  tax.settings.updated\n")
+
+	require.EqualError(t, RegisterTrigger("tax.settings.updated", source), "the event 'tax.settings.updated' has already been registered")
+	require.EqualError(t, RegisterTrigger("customer.created", source), "the event 'customer.created' is already supported by the Stripe CLI")
+}
+
+func TestRegisterStepTypeRejectsBuiltInMethods(t *testing.T) {
+	resetRegistry(t)
+
+	handler := func(ctx context.Context, step Step) ([]byte, error) {
+		return []byte(`{}`), nil
+	}
+
+	require.EqualError(t, RegisterStepType("post", handler), "the step type 'post' is reserved by the Stripe CLI")
+	require.NoError(t, RegisterStepType("tax_update", handler))
+	require.EqualError(t, RegisterStepType("tax_update", handler), "the step type 'tax_update' has already been registered")
+}
+
+func TestTriggerWithRegisteredTriggerAndStepType(t *testing.T) {
+	resetRegistry(t)
+
+	var steps []Step
+
+	RegisterTrigger("tax.settings.updated", func(ctx context.Context, event string) ([]byte, error) {
+		return []byte(pluginTestFixture), nil
+	})
+	RegisterStepType("tax_update", func(ctx context.Context, step Step) ([]byte, error) {
+		steps = append(steps, step)
+		return []byte(`{"id": "txs_123"}`), nil
+	})
+
+	names, err := Trigger(context.Background(), "tax.settings.updated", "", "", apiKey, []string{}, []string{}, []string{}, []string{}, "", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"tax_settings", "tax_settings_again"}, names)
+
+	require.Len(t, steps, 2)
+	require.Equal(t, "/v1/tax/settings", steps[0].Path)
+	require.Equal(t, []string{"defaults[tax_code]=txcd_10000000"}, steps[0].Params)
+	require.Equal(t, "/v1/tax/settings/txs_123", steps[1].Path)
+}
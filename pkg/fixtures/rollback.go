@@ -0,0 +1,262 @@
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/stripe/stripe-cli/pkg/requests"
+)
+
+// RollbackError is returned by Execute when a step of a transactional fixture
+// fails, once the objects created by the steps before it were reversed
+type RollbackError struct {
+	Err  error
+	Step string
+	// Remaining are the objects that couldn't be reversed, such as
+	// "transfer tr_123: can't be reversed"
+	Remaining []string
+}
+
+func (e RollbackError) Error() string {
+	return e.Err.Error()
+}
+
+func (e RollbackError) Unwrap() error {
+	return e.Err
+}
+
+// reversal is the request reversing the creation of an object
+type reversal struct {
+	method string
+	path   string
+	params []string
+	// verb says what the request does to the object, such as "Deleted"
+	verb string
+}
+
+// createdObject is an object created by a step, in its latest known state
+type createdObject struct {
+	id     string
+	object string
+	state  gjson.Result
+}
+
+// createdObjects returns the objects created by the steps completed by this
+// run, the last created first, so that objects are reversed before the ones
+// they depend on. An object returned by a step is created by it if no earlier
+// step returned it and the step's path doesn't name it, like updates do.
+func (fxt *Fixture) createdObjects() []createdObject {
+	var created []createdObject
+	index := make(map[string]int)
+
+	for _, name := range fxt.completed {
+		resp, _ := fxt.response(name)
+		id := resp.Get("id").String()
+		if id == "" {
+			continue
+		}
+
+		if i, ok := index[id]; ok {
+			created[i].state = resp
+			continue
+		}
+
+		data := fxt.step(name)
+		if !strings.EqualFold(data.Method, http.MethodPost) {
+			continue
+		}
+		if path, err := fxt.parsePath(data); err == nil && strings.Contains(path, id) {
+			continue
+		}
+
+		index[id] = len(created)
+		created = append(created, createdObject{id: id, object: resp.Get("object").String(), state: resp})
+	}
+
+	reversed := make([]createdObject, len(created))
+	for i, obj := range created {
+		reversed[len(created)-1-i] = obj
+	}
+
+	return reversed
+}
+
+func (fxt *Fixture) step(name string) fixture {
+	for _, data := range fxt.fixture.Fixtures {
+		if data.Name == name {
+			return data
+		}
+	}
+
+	return fixture{}
+}
+
+// reversalFor returns the request reversing the creation of an object in its
+// state. ok is false for the objects that can't be reversed, and the reversal
+// is nil when the object is already inert, such as a canceled subscription.
+func reversalFor(obj createdObject) (rev *reversal, ok bool) {
+	status := obj.state.Get("status").String()
+
+	remove := func(collection string) (*reversal, bool) {
+		return &reversal{method: http.MethodDelete, path: collection + "/" + obj.id, verb: "Deleted"}, true
+	}
+	post := func(path, verb string, params ...string) (*reversal, bool) {
+		return &reversal{method: http.MethodPost, path: path, params: params, verb: verb}, true
+	}
+	archive := func(collection string) (*reversal, bool) {
+		if !obj.state.Get("active").Bool() {
+			return nil, true
+		}
+		return post(collection+"/"+obj.id, "Archived", "active=false")
+	}
+
+	switch obj.object {
+	case "customer":
+		return remove("/v1/customers")
+	case "plan":
+		return remove("/v1/plans")
+	case "coupon":
+		return remove("/v1/coupons")
+	case "invoiceitem":
+		return remove("/v1/invoiceitems")
+	case "webhook_endpoint":
+		return remove("/v1/webhook_endpoints")
+	case "account":
+		return remove("/v1/accounts")
+	case "test_helpers.test_clock":
+		return remove("/v1/test_helpers/test_clocks")
+	// products and prices can't be deleted once they are used
+	case "product":
+		return archive("/v1/products")
+	case "price":
+		return archive("/v1/prices")
+	case "promotion_code":
+		return archive("/v1/promotion_codes")
+	case "tax_rate":
+		return archive("/v1/tax_rates")
+	case "payment_link":
+		return archive("/v1/payment_links")
+	case "subscription":
+		if status == "canceled" || status == "incomplete_expired" {
+			return nil, true
+		}
+		return &reversal{method: http.MethodDelete, path: "/v1/subscriptions/" + obj.id, verb: "Canceled"}, true
+	case "subscription_schedule":
+		if status != "not_started" && status != "active" {
+			return nil, true
+		}
+		return post("/v1/subscription_schedules/"+obj.id+"/cancel", "Canceled")
+	case "invoice":
+		switch status {
+		case "draft":
+			return remove("/v1/invoices")
+		case "open", "uncollectible":
+			return post("/v1/invoices/"+obj.id+"/void", "Voided")
+		case "void":
+			return nil, true
+		}
+	case "quote":
+		switch status {
+		case "canceled":
+			return nil, true
+		case "accepted":
+			return nil, false
+		}
+		return post("/v1/quotes/"+obj.id+"/cancel", "Canceled")
+	case "payment_intent":
+		switch status {
+		case "succeeded":
+			return post("/v1/refunds", "Refunded", "payment_intent="+obj.id)
+		case "canceled":
+			return nil, true
+		default:
+			return post("/v1/payment_intents/"+obj.id+"/cancel", "Canceled")
+		}
+	case "setup_intent":
+		if status == "succeeded" || status == "canceled" {
+			return nil, true
+		}
+		return post("/v1/setup_intents/"+obj.id+"/cancel", "Canceled")
+	case "charge":
+		if obj.state.Get("refunded").Bool() || !obj.state.Get("paid").Bool() {
+			return nil, true
+		}
+		return post("/v1/refunds", "Refunded", "charge="+obj.id)
+	case "payment_method":
+		if obj.state.Get("customer").String() == "" {
+			return nil, true
+		}
+		return post("/v1/payment_methods/"+obj.id+"/detach", "Detached")
+	case "checkout.session":
+		if status != "open" {
+			return nil, true
+		}
+		return post("/v1/checkout/sessions/"+obj.id+"/expire", "Expired")
+	}
+
+	return nil, false
+}
+
+// rollback reverses the objects created by the steps completed by this run,
+// printing what it does, and returns the objects it couldn't reverse
+func (fxt *Fixture) rollback(ctx context.Context, apiVersion string) []string {
+	// the objects are reversed even when the run was interrupted
+	if ctx.Err() != nil {
+		ctx = context.Background()
+	}
+
+	created := fxt.createdObjects()
+	if len(created) == 0 {
+		return nil
+	}
+
+	fmt.Printf("Rolling back the %d objects created by the fixture...\n", len(created))
+
+	var remaining []string
+	for _, obj := range created {
+		described := strings.TrimSpace(obj.object + " " + obj.id)
+
+		rev, ok := reversalFor(obj)
+		if !ok {
+			remaining = append(remaining, described+": can't be reversed")
+			continue
+		}
+		if rev == nil {
+			continue
+		}
+
+		if err := fxt.makeReversal(ctx, rev, apiVersion); err != nil {
+			remaining = append(remaining, fmt.Sprintf("%s: %v", described, err))
+			continue
+		}
+
+		fmt.Printf("%s %s\n", rev.verb, described)
+	}
+
+	return remaining
+}
+
+func (fxt *Fixture) makeReversal(ctx context.Context, rev *reversal, apiVersion string) error {
+	var params requests.RequestParameters
+	params.AppendData(rev.params)
+	params.SetStripeAccount(fxt.StripeAccount)
+	if apiVersion != "" {
+		params.SetVersion(apiVersion)
+	}
+
+	req := requests.Base{
+		Method:           rev.method,
+		SuppressOutput:   true,
+		APIBaseURL:       fxt.BaseURL,
+		RetryRateLimited: true,
+		Backoffs:         fxt.Backoffs,
+	}
+
+	_, err := req.MakeRequest(ctx, fxt.APIKey, rev.path, &params, true)
+
+	return err
+}
@@ -0,0 +1,149 @@
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+const transactionalFixture = `
+{
+	"_meta": {
+		"template_version": 0,
+		"transactional": %s
+	},
+	"fixtures": [
+		{"name": "customer", "path": "/v1/customers", "method": "post", "params": {"email": "jenny@example.com"}},
+		{"name": "payment_method", "path": "/v1/payment_methods/pm_card_visa/attach", "method": "post", "params": {"customer": "${customer:id}"}},
+		{"name": "customer_update", "path": "/v1/customers/${customer:id}", "method": "post", "params": {"invoice_settings": {"default_payment_method": "${payment_method:id}"}}},
+		{"name": "subscription", "path": "/v1/subscriptions", "method": "post", "params": {"customer": "${customer:id}"}},
+		{"name": "transfer", "path": "/v1/transfers", "method": "post", "params": {"amount": 100}},
+		{"name": "invoice", "path": "/v1/invoices/${subscription:latest_invoice}/pay", "method": "post"}
+	]
+}`
+
+func TestExecuteTransactionalRollsBack(t *testing.T) {
+	var mu sync.Mutex
+	var rollback []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		switch req.Method + " " + req.URL.Path {
+		case "POST /v1/customers", "POST /v1/customers/cus_1":
+			res.Write([]byte(`{"id": "cus_1", "object": "customer"}`))
+		case "POST /v1/payment_methods/pm_card_visa/attach":
+			res.Write([]byte(`{"id": "pm_1", "object": "payment_method", "customer": "cus_1"}`))
+		case "POST /v1/subscriptions":
+			res.Write([]byte(`{"id": "sub_1", "object": "subscription", "status": "active", "latest_invoice": "in_1"}`))
+		case "POST /v1/transfers":
+			res.Write([]byte(`{"id": "tr_1", "object": "transfer"}`))
+		case "POST /v1/invoices/in_1/pay":
+			res.WriteHeader(http.StatusPaymentRequired)
+			res.Write([]byte(`{"error": {"type": "card_error", "message": "Your card was declined."}}`))
+		default:
+			mu.Lock()
+			rollback = append(rollback, req.Method+" "+req.URL.Path)
+			mu.Unlock()
+			res.Write([]byte(`{}`))
+		}
+	}))
+	defer ts.Close()
+
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, file, []byte(strings.Replace(transactionalFixture, "%s", "true", 1)), os.ModePerm)
+
+	fxt, err := NewFixtureFromFile(fs, apiKey, "", ts.URL, file, []string{}, []string{}, []string{}, []string{})
+	require.NoError(t, err)
+	fxt.CheckpointFile = CheckpointPath("/checkpoints", file)
+
+	_, err = fxt.Execute(context.Background(), "")
+
+	var rollbackErr RollbackError
+	require.True(t, errors.As(err, &rollbackErr))
+	require.Equal(t, "invoice", rollbackErr.Step)
+	require.Equal(t, []string{"transfer tr_1: can't be reversed"}, rollbackErr.Remaining)
+
+	// the last created first, and the customer once, although two steps
+	// returned it
+	require.Equal(t, []string{
+		"DELETE /v1/subscriptions/sub_1",
+		"POST /v1/payment_methods/pm_1/detach",
+		"DELETE /v1/customers/cus_1",
+	}, rollback)
+
+	exists, _ := afero.Exists(fs, fxt.CheckpointFile)
+	require.False(t, exists, "there is nothing to resume")
+}
+
+func TestExecuteWithoutTransactionalKeepsObjects(t *testing.T) {
+	requests := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		requests++
+		if req.URL.Path == "/v1/subscriptions" {
+			res.WriteHeader(http.StatusBadRequest)
+			res.Write([]byte(`{"error": {"type": "invalid_request_error"}}`))
+			return
+		}
+		res.Write([]byte(`{"id": "cus_1", "object": "customer"}`))
+	}))
+	defer ts.Close()
+
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, file, []byte(strings.Replace(transactionalFixture, "%s", "false", 1)), os.ModePerm)
+
+	fxt, err := NewFixtureFromFile(fs, apiKey, "", ts.URL, file, []string{}, []string{}, []string{}, []string{})
+	require.NoError(t, err)
+
+	_, err = fxt.Execute(context.Background(), "")
+	require.Error(t, err)
+	require.False(t, errors.As(err, &RollbackError{}))
+	require.Equal(t, 4, requests, "nothing is reversed")
+}
+
+func TestReversalFor(t *testing.T) {
+	for state, want := range map[string]string{
+		`{"object": "price", "active": true}`:                   "POST /v1/prices/obj_1 [active=false]",
+		`{"object": "invoice", "status": "draft"}`:              "DELETE /v1/invoices/obj_1 []",
+		`{"object": "invoice", "status": "open"}`:               "POST /v1/invoices/obj_1/void []",
+		`{"object": "payment_intent", "status": "succeeded"}`:   "POST /v1/refunds [payment_intent=obj_1]",
+		`{"object": "payment_intent", "status": "processing"}`:  "POST /v1/payment_intents/obj_1/cancel []",
+		`{"object": "checkout.session", "status": "open"}`:      "POST /v1/checkout/sessions/obj_1/expire []",
+		`{"object": "charge", "paid": true, "refunded": false}`: "POST /v1/refunds [charge=obj_1]",
+	} {
+		obj := createdObject{id: "obj_1", object: gjson.Get(state, "object").String(), state: gjson.Parse(state)}
+
+		rev, ok := reversalFor(obj)
+		require.True(t, ok, state)
+		require.NotNil(t, rev, state)
+		require.Equal(t, want, rev.method+" "+rev.path+" ["+strings.Join(rev.params, ",")+"]", state)
+	}
+
+	// already inert
+	for _, state := range []string{
+		`{"object": "price", "active": false}`,
+		`{"object": "subscription", "status": "canceled"}`,
+		`{"object": "payment_method", "customer": null}`,
+	} {
+		rev, ok := reversalFor(createdObject{id: "obj_1", object: gjson.Get(state, "object").String(), state: gjson.Parse(state)})
+		require.True(t, ok, state)
+		require.Nil(t, rev, state)
+	}
+
+	// can't be reversed
+	for _, state := range []string{
+		`{"object": "transfer"}`,
+		`{"object": "invoice", "status": "paid"}`,
+	} {
+		_, ok := reversalFor(createdObject{id: "obj_1", object: gjson.Get(state, "object").String(), state: gjson.Parse(state)})
+		require.False(t, ok, state)
+	}
+}
@@ -0,0 +1,226 @@
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// stepResult is the outcome of a step run by runSteps
+type stepResult struct {
+	index int
+	resp  []byte
+	err   error
+}
+
+// orderedOutput prints the messages of the steps in the order of the fixture
+// file, whatever the order the steps run in. The message of a step is printed
+// once the messages of all the steps before it were.
+type orderedOutput struct {
+	messages []string
+	ready    []bool
+	next     int
+}
+
+func newOrderedOutput(steps int) *orderedOutput {
+	return &orderedOutput{
+		messages: make([]string, steps),
+		ready:    make([]bool, steps),
+	}
+}
+
+func (o *orderedOutput) print(index int, message string) {
+	o.messages[index] = message
+	o.ready[index] = true
+
+	for o.next < len(o.ready) && o.ready[o.next] {
+		fmt.Print(o.messages[o.next])
+		o.next++
+	}
+}
+
+// dependencies returns the indexes of the earlier steps each step needs to
+// wait for: the ones it references with ${name:field}, and the ones listed in
+// its depends_on
+func (fxt *Fixture) dependencies() (map[int][]int, error) {
+	indexes := make(map[string]int)
+	deps := make(map[int][]int)
+
+	for i, data := range fxt.fixture.Fixtures {
+		seen := make(map[int]bool)
+		add := func(name string) bool {
+			j, ok := indexes[name]
+			if ok && !seen[j] {
+				seen[j] = true
+				deps[i] = append(deps[i], j)
+			}
+			return ok
+		}
+
+		for _, name := range referencedNames(data) {
+			// references to later or undeclared steps fail when the step
+			// runs, as they do when steps run one after the other
+			add(name)
+		}
+
+		for _, name := range data.DependsOn {
+			if !add(name) {
+				return nil, clierrors.Errorf(clierrors.InvalidFixture, "the depends_on of %s lists %s, which isn't an earlier step of the fixture", data.Name, name)
+			}
+		}
+
+		sort.Ints(deps[i])
+		indexes[data.Name] = i
+	}
+
+	return deps, nil
+}
+
+// referencedNames returns the names of the steps referenced in the path and
+// params of a step
+func referencedNames(data fixture) []string {
+	var names []string
+
+	var visit func(value interface{})
+	visit = func(value interface{}) {
+		switch v := reflect.ValueOf(value); v.Kind() {
+		case reflect.String:
+			r, ok := matchFixtureQuery(v.String())
+			if !ok {
+				return
+			}
+			for _, match := range r.FindAllStringSubmatch(v.String(), -1) {
+				if match[1] != ".env" {
+					names = append(names, match[1])
+				}
+			}
+		case reflect.Map:
+			for _, item := range value.(map[string]interface{}) {
+				visit(item)
+			}
+		case reflect.Array, reflect.Slice:
+			for _, item := range value.([]interface{}) {
+				visit(item)
+			}
+		}
+	}
+
+	visit(data.Path)
+	visit(data.Params)
+
+	sort.Strings(names)
+
+	return names
+}
+
+// runSteps runs the steps at the given indexes, with up to fxt.MaxParallel of
+// them at once. A step starts once the steps it depends on completed, and the
+// steps ready to start are started in the order of the fixture file. With a
+// MaxParallel of 1, the steps run one after the other in that order.
+//
+// When a step fails, no more steps are started and the steps running are
+// canceled. The name of the failed step is returned along with its error.
+func (fxt *Fixture) runSteps(ctx context.Context, apiVersion string, steps []int, out *orderedOutput) (string, error) {
+	deps, err := fxt.dependencies()
+	if err != nil {
+		return "", err
+	}
+
+	maxParallel := fxt.MaxParallel
+	if maxParallel < 1 {
+		maxParallel = 1
+	}
+
+	// only the steps being run need to be waited for, the others were skipped
+	// or completed by an earlier run
+	toRun := make(map[int]bool, len(steps))
+	for _, i := range steps {
+		toRun[i] = true
+	}
+
+	waiting := make(map[int]int)
+	dependents := make(map[int][]int)
+	var ready []int
+
+	for _, i := range steps {
+		for _, j := range deps[i] {
+			if toRun[j] {
+				waiting[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+		if waiting[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// buffered so that the steps still running when a checkpoint fails to be
+	// saved don't block
+	results := make(chan stepResult, len(steps))
+	running := 0
+
+	failedStep := ""
+	var failure error
+
+	for {
+		for running < maxParallel && len(ready) > 0 && failure == nil {
+			i := ready[0]
+			ready = ready[1:]
+
+			data := fxt.fixture.Fixtures[i]
+			out.print(i, fmt.Sprintf("Setting up fixture for: %s\nRunning fixture for: %s\n", data.Name, data.Name))
+
+			running++
+			go func(i int, data fixture) {
+				resp, err := fxt.makeRequest(ctx, data, apiVersion)
+				results <- stepResult{index: i, resp: resp, err: err}
+			}(i, data)
+		}
+
+		if running == 0 {
+			break
+		}
+
+		result := <-results
+		running--
+
+		data := fxt.fixture.Fixtures[result.index]
+		if result.err != nil && !errWasExpected(result.err, data.ExpectedErrorType) {
+			// the steps canceled because of the failure aren't failures of
+			// their own
+			if failure == nil {
+				failedStep = data.Name
+				failure = result.err
+				cancel()
+			}
+			continue
+		}
+
+		fxt.setResponse(data.Name, gjson.ParseBytes(result.resp))
+		fxt.completed = append(fxt.completed, data.Name)
+
+		if fxt.CheckpointFile != "" {
+			if err := fxt.saveCheckpoint(); err != nil {
+				return "", err
+			}
+		}
+
+		for _, i := range dependents[result.index] {
+			waiting[i]--
+			if waiting[i] == 0 {
+				ready = append(ready, i)
+			}
+		}
+		sort.Ints(ready)
+	}
+
+	return failedStep, failure
+}
@@ -0,0 +1,164 @@
+package fixtures
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+const parallelFixture = `
+{
+	"_meta": {
+		"template_version": 0
+	},
+	"fixtures": [
+		{
+			"name": "cust_fry",
+			"path": "/v1/customers",
+			"method": "post",
+			"params": {"name": "Philip J. Fry"}
+		},
+		{
+			"name": "cust_leela",
+			"path": "/v1/customers",
+			"method": "post",
+			"params": {"name": "Turanga Leela"}
+		},
+		{
+			"name": "ii_fry",
+			"path": "/v1/invoiceitems",
+			"method": "post",
+			"params": {"customer": "${cust_fry:id}", "amount": 100, "currency": "usd"}
+		},
+		{
+			"name": "in_fry",
+			"path": "/v1/invoices",
+			"method": "post",
+			"params": {"customer": "${cust_fry:id}"},
+			"depends_on": ["ii_fry"]
+		}
+	]
+}`
+
+func TestDependencies(t *testing.T) {
+	fxt, err := NewFixtureFromRawString(afero.NewMemMapFs(), apiKey, "", "", parallelFixture)
+	require.NoError(t, err)
+
+	deps, err := fxt.dependencies()
+	require.NoError(t, err)
+	require.Equal(t, map[int][]int{2: {0}, 3: {0, 2}}, deps)
+
+	fxt, err = NewFixtureFromRawString(afero.NewMemMapFs(), apiKey, "", "", `{
+		"fixtures": [
+			{"name": "in_fry", "path": "/v1/invoices", "method": "post", "depends_on": ["ii_fry"]},
+			{"name": "ii_fry", "path": "/v1/invoiceitems", "method": "post"}
+		]
+	}`)
+	require.NoError(t, err)
+
+	_, err = fxt.dependencies()
+	require.EqualError(t, err, "the depends_on of in_fry lists ii_fry, which isn't an earlier step of the fixture")
+}
+
+func TestExecuteInParallel(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	inFlight, maxInFlight := 0, 0
+
+	// both customers are created before either of them completes
+	bothCustomers := make(chan struct{})
+	var customers sync.WaitGroup
+	customers.Add(2)
+	go func() {
+		customers.Wait()
+		close(bothCustomers)
+	}()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		req.ParseForm()
+
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		if req.URL.Path == customersPath {
+			customers.Done()
+			select {
+			case <-bothCustomers:
+			case <-time.After(5 * time.Second):
+			}
+		}
+
+		mu.Lock()
+		inFlight--
+		order = append(order, req.URL.Path+" "+req.PostForm.Get("customer"))
+		mu.Unlock()
+
+		switch req.URL.Path {
+		case customersPath:
+			if req.PostForm.Get("name") == "Philip J. Fry" {
+				res.Write([]byte(`{"id": "cus_fry"}`))
+			} else {
+				res.Write([]byte(`{"id": "cus_leela"}`))
+			}
+		default:
+			res.Write([]byte(`{"id": "obj_123"}`))
+		}
+	}))
+	defer ts.Close()
+
+	fxt, err := NewFixtureFromRawString(afero.NewMemMapFs(), apiKey, "", ts.URL, parallelFixture)
+	require.NoError(t, err)
+	fxt.MaxParallel = 4
+
+	names, err := fxt.Execute(context.Background(), "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"cust_fry", "cust_leela", "ii_fry", "in_fry"}, names)
+
+	require.Equal(t, 2, maxInFlight)
+	require.Equal(t, []string{"/v1/invoiceitems cus_fry", "/v1/invoices cus_fry"}, order[2:])
+}
+
+func TestExecuteInParallelStopsAtFailure(t *testing.T) {
+	requested := make(map[string]int)
+	var mu sync.Mutex
+
+	ts := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		requested[req.URL.Path]++
+		mu.Unlock()
+
+		if req.URL.Path == "/v1/invoiceitems" {
+			res.WriteHeader(http.StatusBadRequest)
+			res.Write([]byte(`{"error": {"type": "invalid_request_error"}}`))
+			return
+		}
+		res.Write([]byte(`{"id": "obj_123"}`))
+	}))
+	defer ts.Close()
+
+	fs := afero.NewMemMapFs()
+	fxt, err := NewFixtureFromRawString(fs, apiKey, "", ts.URL, parallelFixture)
+	require.NoError(t, err)
+	fxt.MaxParallel = 4
+	fxt.CheckpointFile = "/checkpoints/parallel.json"
+
+	_, err = fxt.Execute(context.Background(), "")
+
+	var resumeErr ResumeError
+	require.ErrorAs(t, err, &resumeErr)
+	require.Equal(t, "ii_fry", resumeErr.Step)
+	require.Equal(t, 0, requested["/v1/invoices"])
+
+	exists, _ := afero.Exists(fs, fxt.CheckpointFile)
+	require.True(t, exists)
+}
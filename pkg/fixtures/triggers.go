@@ -8,6 +8,7 @@ import (
 
 	"github.com/spf13/afero"
 
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/stripe"
 )
 
@@ -125,6 +126,9 @@ func EventNames() []string {
 	for name := range Events {
 		names = append(names, name)
 	}
+	for name := range externalTriggers {
+		names = append(names, name)
+	}
 
 	sort.Strings(names)
 
@@ -132,7 +136,7 @@ func EventNames() []string {
 }
 
 // Trigger triggers a Stripe event.
-func Trigger(ctx context.Context, event string, stripeAccount string, baseURL string, apiKey string, skip, override, add, remove []string, raw string, apiVersion string) ([]string, error) {
+func Trigger(ctx context.Context, event string, stripeAccount string, baseURL string, apiKey string, skip, override, add, remove []string, raw string, apiVersion string, metadata map[string]string) ([]string, error) {
 	var fixture *Fixture
 	var err error
 	fs := afero.NewOsFs()
@@ -149,10 +153,20 @@ func Trigger(ctx context.Context, event string, stripeAccount string, baseURL st
 			if err != nil {
 				return nil, err
 			}
+		} else if source, ok := externalTriggers[event]; ok {
+			filedata, err := source(ctx, event)
+			if err != nil {
+				return nil, err
+			}
+
+			fixture, err = NewFixtureFromBytes(fs, apiKey, stripeAccount, baseURL, filedata, skip, override, add, remove)
+			if err != nil {
+				return nil, err
+			}
 		} else {
 			exists, _ := afero.Exists(fs, event)
 			if !exists {
-				return nil, fmt.Errorf(fmt.Sprintf("The event ‘%s’ is not supported by the Stripe CLI.", event))
+				return nil, clierrors.Errorf(clierrors.UnsupportedEvent, "The event ‘%s’ is not supported by the Stripe CLI.", event)
 			}
 
 			fixture, err = BuildFromFixtureFile(fs, apiKey, stripeAccount, baseURL, event, skip, override, add, remove)
@@ -167,9 +181,11 @@ func Trigger(ctx context.Context, event string, stripeAccount string, baseURL st
 		}
 	}
 
+	fixture.Metadata = metadata
+
 	requestNames, err := fixture.Execute(ctx, apiVersion)
 	if err != nil {
-		return nil, fmt.Errorf(fmt.Sprintf("Trigger failed: %s\n", err))
+		return nil, fmt.Errorf("Trigger failed: %w\n", err)
 	}
 
 	return requestNames, nil
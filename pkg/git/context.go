@@ -0,0 +1,122 @@
+package git
+
+import (
+	"errors"
+	"os"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// Metadata keys the objects created from within a git repository are tagged
+// with
+const (
+	BranchMetadataKey = "git_branch"
+	CommitMetadataKey = "git_commit"
+)
+
+// Context describes what is checked out in the git repository the CLI is run
+// in
+type Context struct {
+	// Branch is empty when HEAD is detached
+	Branch string
+	Commit string
+}
+
+// CurrentContext returns what is checked out in the git repository containing
+// the current directory. ok is false outside of a git repository.
+func CurrentContext() (ctx Context, ok bool) {
+	repo, err := openCurrentRepository()
+	if err != nil {
+		return Context{}, false
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return Context{}, false
+	}
+
+	ctx.Commit = head.Hash().String()
+	if head.Name().IsBranch() {
+		ctx.Branch = head.Name().Short()
+	}
+
+	return ctx, true
+}
+
+// ShortCommit returns the abbreviated hash of the commit
+func (c Context) ShortCommit() string {
+	if len(c.Commit) > 7 {
+		return c.Commit[:7]
+	}
+
+	return c.Commit
+}
+
+// TagMetadata adds the branch and commit checked out in the current directory
+// to metadata, unless metadata sets them already
+func TagMetadata(metadata map[string]string) map[string]string {
+	ctx, ok := CurrentContext()
+	if !ok {
+		return metadata
+	}
+
+	tagged := map[string]string{
+		BranchMetadataKey: ctx.Branch,
+		CommitMetadataKey: ctx.Commit,
+	}
+	if ctx.Branch == "" {
+		delete(tagged, BranchMetadataKey)
+	}
+
+	for key, value := range metadata {
+		tagged[key] = value
+	}
+
+	return tagged
+}
+
+// IsBranchMerged returns whether the local branch was merged into what is
+// checked out in the current directory. exists is false when there's no such
+// local branch, such as after it was deleted once merged.
+func IsBranchMerged(branch string) (merged bool, exists bool, err error) {
+	repo, err := openCurrentRepository()
+	if err != nil {
+		return false, false, err
+	}
+
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName(branch), true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return false, false, nil
+	} else if err != nil {
+		return false, false, err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return false, true, err
+	}
+
+	branchCommit, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return false, true, err
+	}
+
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return false, true, err
+	}
+
+	merged, err = branchCommit.IsAncestor(headCommit)
+
+	return merged, true, err
+}
+
+func openCurrentRepository() (*git.Repository, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	return git.PlainOpenWithOptions(wd, &git.PlainOpenOptions{DetectDotGit: true})
+}
@@ -0,0 +1,86 @@
+package git
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// initRepository creates a repository with a commit on master, and a branch
+// with a commit on top of it, and moves to it
+func initRepository(t *testing.T) (repo *git.Repository, master, feature plumbing.Hash) {
+	dir := t.TempDir()
+
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	commit := func(message string) plumbing.Hash {
+		hash, err := worktree.Commit(message, &git.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+		return hash
+	}
+
+	master = commit("initial commit")
+
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature/foo"), Create: true}))
+	feature = commit("feature commit")
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	return repo, master, feature
+}
+
+func TestCurrentContext(t *testing.T) {
+	_, _, feature := initRepository(t)
+
+	ctx, ok := CurrentContext()
+	require.True(t, ok)
+	require.Equal(t, "feature/foo", ctx.Branch)
+	require.Equal(t, feature.String(), ctx.Commit)
+	require.Equal(t, feature.String()[:7], ctx.ShortCommit())
+
+	metadata := TagMetadata(map[string]string{"git_branch": "override", "developer": "jane"})
+	require.Equal(t, map[string]string{
+		"git_branch": "override",
+		"git_commit": feature.String(),
+		"developer":  "jane",
+	}, metadata)
+}
+
+func TestIsBranchMerged(t *testing.T) {
+	repo, _, feature := initRepository(t)
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.Master}))
+
+	merged, exists, err := IsBranchMerged("feature/foo")
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.False(t, merged)
+
+	// fast-forward master to the feature branch
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference(plumbing.Master, feature)))
+
+	merged, exists, err = IsBranchMerged("feature/foo")
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.True(t, merged)
+
+	_, exists, err = IsBranchMerged("feature/deleted")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
@@ -0,0 +1,252 @@
+// Package guardrails keeps the CLI from changing live mode data by accident,
+// such as when a script written against a test mode key is run with a live
+// mode one.
+//
+// Live mode requests that change data must be asked for explicitly, with the
+// --live flag, or confirmed interactively. A policy file in the config folder
+// can deny some of them outright, or require an environment variable to be set
+// to approve them. Profiles can also require them to be confirmed with a
+// passkey, even when --live is passed.
+package guardrails
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/afero"
+	"golang.org/x/term"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/config"
+)
+
+// PolicyFileName is the name of the policy file in the config folder
+const PolicyFileName = "policy.toml"
+
+// ErrNotConfirmed is returned when the user didn't confirm a live mode request
+var ErrNotConfirmed = clierrors.New(clierrors.LiveModeNotConfirmed, "the live mode request was not confirmed")
+
+// Policy restricts the requests the CLI makes
+type Policy struct {
+	Live LivePolicy `toml:"live"`
+}
+
+// LivePolicy restricts the live mode requests that change data
+type LivePolicy struct {
+	// Deny lists the requests that are never made. Each is a path pattern,
+	// where * matches a single segment, optionally preceded by a method, such
+	// as "DELETE /v1/customers/*".
+	Deny []string `toml:"deny"`
+	// ApprovalEnv is an environment variable that must be set for requests to
+	// be made
+	ApprovalEnv string `toml:"approval_env"`
+}
+
+// LoadPolicy reads the policy file at path. A missing file is an empty policy.
+func LoadPolicy(fs afero.Fs, policyPath string) (Policy, error) {
+	var policy Policy
+
+	data, err := afero.ReadFile(fs, policyPath)
+	if os.IsNotExist(err) {
+		return policy, nil
+	} else if err != nil {
+		return policy, err
+	}
+
+	if _, err := toml.Decode(string(data), &policy); err != nil {
+		return policy, clierrors.Errorf(clierrors.InvalidPolicyFile, "the policy file %s is not valid: %w", policyPath, err)
+	}
+
+	for _, pattern := range policy.Live.Deny {
+		if _, err := path.Match(patternPath(pattern), "/"); err != nil {
+			return policy, clierrors.Errorf(clierrors.InvalidPolicyFile, "the policy file %s has an invalid pattern '%s': %w", policyPath, pattern, err)
+		}
+	}
+
+	return policy, nil
+}
+
+// DefaultPolicyPath returns the location of the policy file in the CLI's
+// config folder
+func DefaultPolicyPath() string {
+	cfg := &config.Config{}
+	return filepath.Join(cfg.GetConfigFolder(os.Getenv("XDG_CONFIG_HOME")), PolicyFileName)
+}
+
+// denies returns the pattern denying the request, if any
+func (p LivePolicy) denies(method, requestPath string) (string, bool) {
+	for _, pattern := range p.Deny {
+		if patternMethod(pattern) != "" && !strings.EqualFold(patternMethod(pattern), method) {
+			continue
+		}
+
+		if matched, _ := path.Match(patternPath(pattern), requestPath); matched {
+			return pattern, true
+		}
+	}
+
+	return "", false
+}
+
+func patternMethod(pattern string) string {
+	if method, _, found := strings.Cut(strings.TrimSpace(pattern), " "); found {
+		return method
+	}
+
+	return ""
+}
+
+func patternPath(pattern string) string {
+	if _, p, found := strings.Cut(strings.TrimSpace(pattern), " "); found {
+		return strings.TrimSpace(p)
+	}
+
+	return strings.TrimSpace(pattern)
+}
+
+// Guard checks live mode requests that change data before they are made
+type Guard struct {
+	Fs afero.Fs
+	// PolicyPath is where the policy file is read from. If empty, the default
+	// location in the CLI's config folder is used.
+	PolicyPath string
+
+	// Explicit is set when live mode was asked for explicitly, such as with a
+	// --live flag, so requests don't need to be confirmed
+	Explicit bool
+
+	// Interactive is whether the user can be asked to confirm requests, on
+	// In and Out
+	Interactive bool
+	In          io.Reader
+	Out         io.Writer
+
+	// Verify, when set, confirms requests in place of the prompt, and is
+	// required even when they were asked for explicitly. It's only called once.
+	Verify func(method, requestPath string) error
+
+	mu        sync.Mutex
+	policy    *Policy
+	confirmed bool
+}
+
+// Default is the guard of the requests the CLI makes
+var Default = &Guard{
+	Fs:          afero.NewOsFs(),
+	Interactive: term.IsTerminal(int(os.Stdin.Fd())),
+	In:          os.Stdin,
+	Out:         os.Stderr,
+}
+
+// Check returns an error if a request must not be made: when the policy denies
+// it, when it wasn't approved, or when it wasn't asked for explicitly and the
+// user didn't confirm it. explicit is set when the request was made with a
+// --live flag. Only live mode requests that change data are checked, and they
+// only need to be confirmed once.
+func (g *Guard) Check(method, requestPath, apiKey string, explicit bool) error {
+	if !strings.Contains(apiKey, "live") || !isMutation(method) {
+		return nil
+	}
+
+	requestPath, _, _ = strings.Cut(requestPath, "?")
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	policy, err := g.loadPolicy()
+	if err != nil {
+		return err
+	}
+
+	if pattern, denied := policy.Live.denies(method, requestPath); denied {
+		return clierrors.Errorf(clierrors.LiveModeDenied, "live mode %s requests to %s are denied by '%s' in %s", method, requestPath, pattern, g.policyPath())
+	}
+
+	if env := policy.Live.ApprovalEnv; env != "" && os.Getenv(env) == "" {
+		return clierrors.Errorf(clierrors.LiveModeNotApproved, "live mode requests that change data must be approved by setting %s, as required by %s", env, g.policyPath())
+	}
+
+	if g.confirmed {
+		return nil
+	}
+
+	if g.Verify != nil {
+		if err := g.Verify(method, requestPath); err != nil {
+			return err
+		}
+
+		g.confirmed = true
+
+		return nil
+	}
+
+	if explicit || g.Explicit {
+		return nil
+	}
+
+	if !g.Interactive {
+		return clierrors.Errorf(clierrors.LiveModeNotConfirmed, "refusing to make a live mode %s request to %s without confirmation. Pass --live if this is intended", method, requestPath)
+	}
+
+	confirmed, err := g.confirm(method, requestPath)
+	if err != nil {
+		return err
+	} else if !confirmed {
+		return ErrNotConfirmed
+	}
+
+	g.confirmed = true
+
+	return nil
+}
+
+func (g *Guard) confirm(method, requestPath string) (bool, error) {
+	fmt.Fprintf(g.Out, "You're about to make a live mode %s request to %s, which changes the data of your live account.\n", method, requestPath)
+	fmt.Fprint(g.Out, "Pass --live to skip this prompt. Enter 'yes' to continue: ")
+
+	input, err := bufio.NewReader(g.In).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+
+	return strings.ToLower(strings.TrimSpace(input)) == "yes", nil
+}
+
+func (g *Guard) loadPolicy() (Policy, error) {
+	if g.policy == nil {
+		policy, err := LoadPolicy(g.Fs, g.policyPath())
+		if err != nil {
+			return Policy{}, err
+		}
+
+		g.policy = &policy
+	}
+
+	return *g.policy, nil
+}
+
+func (g *Guard) policyPath() string {
+	if g.PolicyPath == "" {
+		return DefaultPolicyPath()
+	}
+
+	return g.PolicyPath
+}
+
+func isMutation(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,133 @@
+package guardrails
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGuard(t *testing.T, policy string) *Guard {
+	fs := afero.NewMemMapFs()
+	if policy != "" {
+		require.NoError(t, afero.WriteFile(fs, "/stripe/policy.toml", []byte(policy), 0600))
+	}
+
+	return &Guard{Fs: fs, PolicyPath: "/stripe/policy.toml", Out: &bytes.Buffer{}}
+}
+
+func TestCheck_OnlyLiveMutations(t *testing.T) {
+	guard := newTestGuard(t, "")
+
+	require.NoError(t, guard.Check(http.MethodPost, "/v1/customers", "sk_test_1234", false))
+	require.NoError(t, guard.Check(http.MethodGet, "/v1/customers", "sk_live_1234", false))
+	require.Error(t, guard.Check(http.MethodPost, "/v1/customers", "sk_live_1234", false))
+	require.Error(t, guard.Check(http.MethodDelete, "/v1/customers/cus_1", "rk_live_1234", false))
+}
+
+func TestCheck_Explicit(t *testing.T) {
+	guard := newTestGuard(t, "")
+
+	require.NoError(t, guard.Check(http.MethodPost, "/v1/customers", "sk_live_1234", true))
+
+	guard.Explicit = true
+	require.NoError(t, guard.Check(http.MethodPost, "/v1/customers", "sk_live_1234", false))
+}
+
+func TestCheck_NotInteractive(t *testing.T) {
+	guard := newTestGuard(t, "")
+
+	err := guard.Check(http.MethodPost, "/v1/customers?expand[]=sources", "sk_live_1234", false)
+	require.EqualError(t, err, "refusing to make a live mode POST request to /v1/customers without confirmation. Pass --live if this is intended")
+}
+
+func TestCheck_Confirmed(t *testing.T) {
+	guard := newTestGuard(t, "")
+	guard.Interactive = true
+	guard.In = strings.NewReader("yes\n")
+
+	require.NoError(t, guard.Check(http.MethodPost, "/v1/customers", "sk_live_1234", false))
+	require.Contains(t, guard.Out.(*bytes.Buffer).String(), "live mode POST request to /v1/customers")
+
+	// confirming once is enough
+	guard.In = strings.NewReader("")
+	require.NoError(t, guard.Check(http.MethodPost, "/v1/products", "sk_live_1234", false))
+}
+
+func TestCheck_NotConfirmed(t *testing.T) {
+	guard := newTestGuard(t, "")
+	guard.Interactive = true
+	guard.In = strings.NewReader("no\n")
+
+	require.ErrorIs(t, guard.Check(http.MethodPost, "/v1/customers", "sk_live_1234", false), ErrNotConfirmed)
+}
+
+func TestCheck_Verify(t *testing.T) {
+	guard := newTestGuard(t, "")
+	guard.Explicit = true
+
+	calls := 0
+	guard.Verify = func(method, requestPath string) error {
+		calls++
+		return errors.New("the passkey was not used")
+	}
+
+	require.EqualError(t, guard.Check(http.MethodPost, "/v1/customers", "sk_live_1234", true), "the passkey was not used")
+
+	guard.Verify = func(method, requestPath string) error {
+		calls++
+		return nil
+	}
+
+	require.NoError(t, guard.Check(http.MethodPost, "/v1/customers", "sk_live_1234", true))
+	require.NoError(t, guard.Check(http.MethodPost, "/v1/products", "sk_live_1234", true))
+	require.Equal(t, 2, calls)
+}
+
+func TestCheck_PolicyDenies(t *testing.T) {
+	guard := newTestGuard(t, `
+[live]
+deny = ["DELETE /v1/customers/*", "/v1/refunds"]
+`)
+
+	err := guard.Check(http.MethodDelete, "/v1/customers/cus_1", "sk_live_1234", true)
+	require.EqualError(t, err, "live mode DELETE requests to /v1/customers/cus_1 are denied by 'DELETE /v1/customers/*' in /stripe/policy.toml")
+
+	require.Error(t, guard.Check(http.MethodPost, "/v1/refunds", "sk_live_1234", true))
+	require.NoError(t, guard.Check(http.MethodPost, "/v1/customers/cus_1", "sk_live_1234", true))
+}
+
+func TestCheck_PolicyRequiresApproval(t *testing.T) {
+	guard := newTestGuard(t, `
+[live]
+approval_env = "STRIPE_LIVE_APPROVED"
+`)
+
+	t.Setenv("STRIPE_LIVE_APPROVED", "")
+	err := guard.Check(http.MethodPost, "/v1/customers", "sk_live_1234", true)
+	require.EqualError(t, err, "live mode requests that change data must be approved by setting STRIPE_LIVE_APPROVED, as required by /stripe/policy.toml")
+
+	t.Setenv("STRIPE_LIVE_APPROVED", "1")
+	require.NoError(t, guard.Check(http.MethodPost, "/v1/customers", "sk_live_1234", true))
+}
+
+func TestLoadPolicy_Invalid(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	require.NoError(t, afero.WriteFile(fs, "/policy.toml", []byte(`[live`), 0600))
+	_, err := LoadPolicy(fs, "/policy.toml")
+	require.Error(t, err)
+
+	require.NoError(t, afero.WriteFile(fs, "/policy.toml", []byte(`[live]
+deny = ["/v1/[customers"]`), 0600))
+	_, err = LoadPolicy(fs, "/policy.toml")
+	require.Error(t, err)
+
+	policy, err := LoadPolicy(fs, "/missing.toml")
+	require.NoError(t, err)
+	require.Empty(t, policy.Live.Deny)
+}
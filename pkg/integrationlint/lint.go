@@ -0,0 +1,198 @@
+// Package integrationlint checks that the fields an integration reads from API
+// objects and events still exist and aren't deprecated, in the OpenAPI
+// specification of the API version it targets and in recent payloads of the
+// account, to catch contract drift before the integration breaks.
+package integrationlint
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// Expectations are the fields an integration reads, by object type and by
+// event type. Fields are dotted paths such as
+// payment_method_details.card.brand, and those of events are paths in the
+// object of the event.
+type Expectations struct {
+	APIVersion string              `yaml:"api_version"`
+	Objects    map[string][]string `yaml:"objects"`
+	Events     map[string][]string `yaml:"events"`
+}
+
+// LoadExpectations reads the expectations file at path
+func LoadExpectations(fs afero.Fs, path string) (*Expectations, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var expectations Expectations
+	if err := yaml.Unmarshal(data, &expectations); err != nil {
+		return nil, clierrors.Errorf(clierrors.InvalidExpectations, "%s is not a valid expectations file: %w", path, err)
+	}
+
+	if len(expectations.Objects) == 0 && len(expectations.Events) == 0 {
+		return nil, clierrors.Errorf(clierrors.InvalidExpectations, "%s is not a valid expectations file: it has no objects nor events", path)
+	}
+
+	return &expectations, nil
+}
+
+// Fetch returns the objects listed by a GET request to path with the given
+// parameters, such as limit=5
+type Fetch func(ctx context.Context, path string, params []string) ([]map[string]interface{}, error)
+
+// Result is the outcome of checking a field
+type Result struct {
+	// Subject is the object type, or the event type prefixed with "event "
+	Subject string
+	Field   string
+	// Problems is empty if the field is as expected
+	Problems []string
+}
+
+// Options configure a lint
+type Options struct {
+	// Samples is how many recent payloads of each object and event type to
+	// check, none if 0
+	Samples int
+	// Fetch lists the recent payloads, it's only called when Samples is set
+	Fetch Fetch
+}
+
+// Lint checks every field of the expectations against the specification, and
+// against the recent payloads of the account
+func Lint(ctx context.Context, s *Spec, expectations *Expectations, opts Options) ([]Result, error) {
+	var results []Result
+
+	for _, object := range sortedKeys(expectations.Objects) {
+		var payloads []map[string]interface{}
+		var sampleProblem string
+
+		if opts.Samples > 0 {
+			if path := s.ListPath(object); path != "" {
+				var err error
+				payloads, err = opts.Fetch(ctx, path, []string{fmt.Sprintf("limit=%d", opts.Samples)})
+				if err != nil {
+					return nil, fmt.Errorf("could not sample %s objects: %w", object, err)
+				}
+			} else {
+				sampleProblem = "not sampled: the objects can't be listed"
+			}
+		}
+
+		results = append(results, lintFields(s, object, object, expectations.Objects[object], payloads, "objects", sampleProblem)...)
+	}
+
+	for _, eventType := range sortedKeys(expectations.Events) {
+		var payloads []map[string]interface{}
+
+		if opts.Samples > 0 {
+			events, err := opts.Fetch(ctx, "/v1/events", []string{"type=" + eventType, fmt.Sprintf("limit=%d", opts.Samples)})
+			if err != nil {
+				return nil, fmt.Errorf("could not sample %s events: %w", eventType, err)
+			}
+
+			for _, event := range events {
+				if data, ok := event["data"].(map[string]interface{}); ok {
+					if object, ok := data["object"].(map[string]interface{}); ok {
+						payloads = append(payloads, object)
+					}
+				}
+			}
+		}
+
+		results = append(results, lintFields(s, "event "+eventType, s.ObjectForEvent(eventType), expectations.Events[eventType], payloads, "events", "")...)
+	}
+
+	return results, nil
+}
+
+func lintFields(s *Spec, subject, object string, fields []string, payloads []map[string]interface{}, noun, sampleProblem string) []Result {
+	schema := s.Object(object)
+
+	results := make([]Result, 0, len(fields))
+	for _, field := range fields {
+		result := Result{Subject: subject, Field: field}
+		path := strings.Split(field, ".")
+
+		switch _, deprecated, found := s.field(schema, path); {
+		case schema == nil:
+			result.Problems = append(result.Problems, fmt.Sprintf("the %s spec doesn't describe this object", s.Info.Version))
+		case !found:
+			result.Problems = append(result.Problems, fmt.Sprintf("missing from the %s spec", s.Info.Version))
+		case deprecated:
+			result.Problems = append(result.Problems, fmt.Sprintf("deprecated in the %s spec", s.Info.Version))
+		}
+
+		if sampleProblem != "" {
+			result.Problems = append(result.Problems, sampleProblem)
+		}
+
+		missing := 0
+		for _, payload := range payloads {
+			if found, known := lookup(payload, path); known && !found {
+				missing++
+			}
+		}
+		if missing > 0 {
+			result.Problems = append(result.Problems, fmt.Sprintf("missing from %d of %d sampled %s", missing, len(payloads), noun))
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// lookup returns whether the field at path is in value, and whether that can
+// be told: it can't when the path goes through a null, an unexpanded object
+// or an empty list
+func lookup(value interface{}, path []string) (bool, bool) {
+	if len(path) == 0 {
+		return true, true
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		next, ok := v[path[0]]
+		if !ok {
+			return false, true
+		}
+		if next == nil {
+			// a null field is there, but not the fields in it
+			return len(path) == 1, len(path) == 1
+		}
+		return lookup(next, path[1:])
+	case []interface{}:
+		// the fields of a list are those of its items
+		known := false
+		for _, item := range v {
+			found, itemKnown := lookup(item, path)
+			if itemKnown && !found {
+				return false, true
+			}
+			known = known || itemKnown
+		}
+		return known, known
+	default:
+		return false, false
+	}
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,172 @@
+package integrationlint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `{
+  "info": {"version": "2022-08-01"},
+  "components": {"schemas": {
+    "charge": {
+      "x-resourceId": "charge",
+      "x-stripeOperations": [
+        {"method_type": "retrieve", "operation": "get", "path": "/v1/charges/{charge}"},
+        {"method_type": "list", "operation": "get", "path": "/v1/charges"}
+      ],
+      "properties": {
+        "amount": {"type": "integer"},
+        "customer": {"anyOf": [{"type": "string"}, {"$ref": "#/components/schemas/customer"}], "nullable": true},
+        "source": {"description": "[Deprecated] The source of the charge.", "anyOf": [{"$ref": "#/components/schemas/card"}]}
+      }
+    },
+    "card": {"properties": {"brand": {"type": "string"}}},
+    "customer": {"x-resourceId": "customer", "properties": {"email": {"type": "string"}}},
+    "subscription": {
+      "x-resourceId": "subscription",
+      "properties": {
+        "items": {"properties": {"data": {"items": {"$ref": "#/components/schemas/subscription_item"}}}}
+      }
+    },
+    "subscription_item": {"properties": {"quantity": {"type": "integer"}}}
+  }}
+}`
+
+func loadTestSpec(t *testing.T) *Spec {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/spec3.json", []byte(testSpec), 0644))
+
+	s, err := LoadSpec(context.Background(), fs, "/spec3.json")
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestLoadExpectations(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/expectations.yaml", []byte(`
+api_version: 2022-08-01
+objects:
+  charge:
+    - amount
+events:
+  invoice.paid:
+    - amount_paid
+`), 0644))
+
+	expectations, err := LoadExpectations(fs, "/expectations.yaml")
+	require.NoError(t, err)
+	require.Equal(t, "2022-08-01", expectations.APIVersion)
+	require.Equal(t, []string{"amount"}, expectations.Objects["charge"])
+	require.Equal(t, []string{"amount_paid"}, expectations.Events["invoice.paid"])
+
+	require.NoError(t, afero.WriteFile(fs, "/empty.yaml", []byte("api_version: 2022-08-01\n"), 0644))
+	_, err = LoadExpectations(fs, "/empty.yaml")
+	require.ErrorContains(t, err, "it has no objects nor events")
+}
+
+func TestSpec(t *testing.T) {
+	s := loadTestSpec(t)
+
+	require.Equal(t, "/v1/charges", s.ListPath("charge"))
+	require.Equal(t, "", s.ListPath("card"))
+	require.Equal(t, "subscription", s.ObjectForEvent("customer.subscription.updated"))
+	require.Equal(t, "charge", s.ObjectForEvent("charge.succeeded"))
+	require.Equal(t, "", s.ObjectForEvent("payout.paid"))
+
+	_, deprecated, found := s.field(s.Object("charge"), []string{"customer", "email"})
+	require.True(t, found)
+	require.False(t, deprecated)
+
+	_, deprecated, found = s.field(s.Object("charge"), []string{"source", "brand"})
+	require.True(t, found)
+	require.True(t, deprecated)
+
+	_, _, found = s.field(s.Object("subscription"), []string{"items", "data", "quantity"})
+	require.True(t, found)
+
+	_, _, found = s.field(s.Object("charge"), []string{"amount_captured"})
+	require.False(t, found)
+}
+
+func TestLookup(t *testing.T) {
+	payload := map[string]interface{}{
+		"amount":   100,
+		"customer": "cus_123",
+		"source":   nil,
+		"items": map[string]interface{}{
+			"data": []interface{}{
+				map[string]interface{}{"quantity": 1},
+				map[string]interface{}{"price": "price_123"},
+			},
+		},
+	}
+
+	check := func(path ...string) [2]bool {
+		found, known := lookup(payload, path)
+		return [2]bool{found, known}
+	}
+
+	require.Equal(t, [2]bool{true, true}, check("amount"))
+	require.Equal(t, [2]bool{false, true}, check("currency"))
+	require.Equal(t, [2]bool{true, true}, check("source"))
+	// nothing can be told of the fields of nulls and unexpanded objects
+	require.Equal(t, [2]bool{false, false}, check("source", "brand"))
+	require.Equal(t, [2]bool{false, false}, check("customer", "email"))
+	// every item of a list must have the field
+	require.Equal(t, [2]bool{false, true}, check("items", "data", "quantity"))
+}
+
+func TestLint(t *testing.T) {
+	s := loadTestSpec(t)
+
+	expectations := &Expectations{
+		Objects: map[string][]string{
+			"charge":   {"amount", "source.brand", "amount_captured"},
+			"payout":   {"amount"},
+			"customer": {"email"},
+		},
+		Events: map[string][]string{
+			"customer.subscription.updated": {"items.data.quantity"},
+		},
+	}
+
+	var fetched []string
+	fetch := func(ctx context.Context, path string, params []string) ([]map[string]interface{}, error) {
+		fetched = append(fetched, path)
+
+		switch path {
+		case "/v1/charges":
+			require.Equal(t, []string{"limit=2"}, params)
+			return []map[string]interface{}{
+				{"amount": 100, "source": nil},
+				{"source": nil},
+			}, nil
+		case "/v1/events":
+			require.Equal(t, []string{"type=customer.subscription.updated", "limit=2"}, params)
+			return []map[string]interface{}{
+				{"data": map[string]interface{}{"object": map[string]interface{}{
+					"items": map[string]interface{}{"data": []interface{}{map[string]interface{}{"quantity": 2}}},
+				}}},
+			}, nil
+		}
+
+		return nil, nil
+	}
+
+	results, err := Lint(context.Background(), s, expectations, Options{Samples: 2, Fetch: fetch})
+	require.NoError(t, err)
+	require.Equal(t, []string{"/v1/charges", "/v1/events"}, fetched)
+
+	require.Equal(t, []Result{
+		{Subject: "charge", Field: "amount", Problems: []string{"missing from 1 of 2 sampled objects"}},
+		{Subject: "charge", Field: "source.brand", Problems: []string{"deprecated in the 2022-08-01 spec"}},
+		{Subject: "charge", Field: "amount_captured", Problems: []string{"missing from the 2022-08-01 spec", "missing from 2 of 2 sampled objects"}},
+		{Subject: "customer", Field: "email", Problems: []string{"not sampled: the objects can't be listed"}},
+		{Subject: "payout", Field: "amount", Problems: []string{"the 2022-08-01 spec doesn't describe this object", "not sampled: the objects can't be listed"}},
+		{Subject: "event customer.subscription.updated", Field: "items.data.quantity"},
+	}, results)
+}
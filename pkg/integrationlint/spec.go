@@ -0,0 +1,197 @@
+package integrationlint
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/spec"
+)
+
+// DefaultSpecURL is where the OpenAPI specification of the latest API version
+// is published
+const DefaultSpecURL = "https://raw.githubusercontent.com/stripe/openapi/master/openapi/spec3.json"
+
+// specDownloadTimeout bounds how long downloading the specification may take
+const specDownloadTimeout = time.Minute
+
+// Schema is the part of a JSON schema of the OpenAPI specification needed to
+// tell which fields an object has. Unlike spec.Schema, it ignores the fields
+// it doesn't know about, so that any version of the specification can be
+// read.
+type Schema struct {
+	Description string             `json:"description"`
+	Properties  map[string]*Schema `json:"properties"`
+	AnyOf       []*Schema          `json:"anyOf"`
+	Items       *Schema            `json:"items"`
+	Ref         string             `json:"$ref"`
+
+	XResourceID       string                 `json:"x-resourceId"`
+	XStripeOperations []spec.StripeOperation `json:"x-stripeOperations"`
+}
+
+// Spec is an OpenAPI specification of the Stripe API
+type Spec struct {
+	Info struct {
+		Version string `json:"version"`
+	} `json:"info"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// LoadSpec reads the specification at location, a path or an http(s) URL
+func LoadSpec(ctx context.Context, fs afero.Fs, location string) (*Spec, error) {
+	var data []byte
+	var err error
+
+	if strings.HasPrefix(location, "https://") || strings.HasPrefix(location, "http://") {
+		data, err = downloadSpec(ctx, location)
+	} else {
+		data, err = afero.ReadFile(fs, location)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s Spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, clierrors.Errorf(clierrors.InvalidOpenAPISpec, "%s is not an OpenAPI specification: %w", location, err)
+	}
+
+	if len(s.Components.Schemas) == 0 {
+		return nil, clierrors.Errorf(clierrors.InvalidOpenAPISpec, "%s is not an OpenAPI specification: it has no schemas", location)
+	}
+
+	return &s, nil
+}
+
+func downloadSpec(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, specDownloadTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, clierrors.Errorf(clierrors.InvalidOpenAPISpec, "could not download the OpenAPI specification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, clierrors.Errorf(clierrors.InvalidOpenAPISpec, "could not download the OpenAPI specification from %s: %s", url, resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// Object returns the schema of the object of the given type, such as charge
+// or checkout.session, or nil if the specification doesn't have it
+func (s *Spec) Object(name string) *Schema {
+	if schema, ok := s.Components.Schemas[name]; ok {
+		return schema
+	}
+
+	for _, schema := range s.Components.Schemas {
+		if schema.XResourceID == name {
+			return schema
+		}
+	}
+
+	return nil
+}
+
+// ObjectForEvent returns the type of the object of events of the given type,
+// such as subscription for customer.subscription.updated, or "" if the
+// specification doesn't have it
+func (s *Spec) ObjectForEvent(eventType string) string {
+	parts := strings.Split(eventType, ".")
+	if len(parts) < 2 {
+		return ""
+	}
+	parts = parts[:len(parts)-1]
+
+	// the object is named by the event type without its action, or by the
+	// end of it for objects nested in others, like customer.subscription
+	for i := range parts {
+		name := strings.Join(parts[i:], ".")
+		if s.Object(name) != nil {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// ListPath returns the path listing the objects of the given type, or "" if
+// they can't be listed
+func (s *Spec) ListPath(object string) string {
+	schema := s.Object(object)
+	if schema == nil {
+		return ""
+	}
+
+	for _, operation := range schema.XStripeOperations {
+		if operation.MethodType == "list" && operation.Operation == "get" && !strings.Contains(operation.Path, "{") {
+			return operation.Path
+		}
+	}
+
+	return ""
+}
+
+// field returns the schema of the field at path in schema, and whether any
+// field along the path is deprecated
+func (s *Spec) field(schema *Schema, path []string) (*Schema, bool, bool) {
+	schema = s.resolve(schema)
+	if schema == nil {
+		return nil, false, false
+	}
+	if len(path) == 0 {
+		return schema, false, true
+	}
+
+	// the fields of an array are those of its items
+	if schema.Items != nil {
+		return s.field(schema.Items, path)
+	}
+
+	if property, ok := schema.Properties[path[0]]; ok {
+		found, deprecated, ok := s.field(property, path[1:])
+		return found, deprecated || isDeprecated(property), ok
+	}
+
+	// nullable and expandable fields are an anyOf of what they can be
+	for _, alternative := range schema.AnyOf {
+		if found, deprecated, ok := s.field(alternative, path); ok {
+			return found, deprecated, true
+		}
+	}
+
+	return nil, false, false
+}
+
+func (s *Spec) resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = s.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+
+	return schema
+}
+
+// isDeprecated returns whether the description of a field says it's
+// deprecated, which is how the specification flags them
+func isDeprecated(schema *Schema) bool {
+	description := strings.ToLower(schema.Description)
+
+	return strings.HasPrefix(description, "[deprecated]") || strings.Contains(description, "is deprecated")
+}
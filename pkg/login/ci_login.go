@@ -0,0 +1,75 @@
+package login
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	configPkg "github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/validators"
+)
+
+// CIOptions configures CILogin
+type CIOptions struct {
+	// TTL is how long the keys are kept in the profile
+	TTL time.Duration
+	// Timeout is how long to wait for the login to be confirmed
+	Timeout time.Duration
+	// PrintEnv prints the test mode key as an environment variable to out
+	// instead of writing it to the profile, so that nothing is written to the
+	// config file
+	PrintEnv bool
+}
+
+// IsCI returns whether the CLI runs in a CI job, which can't answer prompts.
+// CI services set the CI variable in their jobs.
+func IsCI() bool {
+	return os.Getenv("CI") != "" && os.Getenv("CI") != "false"
+}
+
+// CILogin logs in from a CI job, which can't open a browser: it prints the
+// link and pairing code for someone to confirm the login with, then waits for
+// them. The keys are written to a profile which expires after opts.TTL, when
+// the CLI removes them. Messages are written to msgOut, so that out only gets
+// the environment variables of opts.PrintEnv.
+func CILogin(ctx context.Context, baseURL string, config *configPkg.Config, opts CIOptions, out, msgOut io.Writer) error {
+	links, err := GetLinks(ctx, baseURL, config.Profile.DeviceName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(msgOut, "To log in from this job, open %s\nand check that the pairing code is: %s\n", links.BrowserURL, links.VerificationCode)
+	fmt.Fprintf(msgOut, "Waiting for confirmation for up to %s...\n", opts.Timeout)
+
+	maxAttempts := int(opts.Timeout / intervalDefault)
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	response, _, err := PollForKey(ctx, links.PollURL, intervalDefault, maxAttempts)
+	if err != nil {
+		return err
+	}
+
+	if err := validators.APIKey(response.TestModeAPIKey); err != nil {
+		return err
+	}
+
+	if opts.PrintEnv {
+		fmt.Fprintf(out, "STRIPE_API_KEY=%s\n", response.TestModeAPIKey)
+		fmt.Fprintf(msgOut, "Logged in to %s. The config file wasn't written to.\n", response.AccountDisplayName)
+		return nil
+	}
+
+	config.Profile.ExpiresAt = time.Now().Add(opts.TTL)
+	if err := ConfigureProfile(config, response); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(msgOut, "Logged in to %s until %s, when the keys are removed from the %s profile.\n",
+		response.AccountDisplayName, config.Profile.ExpiresAt.UTC().Format(time.RFC3339), config.Profile.ProfileName)
+
+	return nil
+}
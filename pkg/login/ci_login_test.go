@@ -0,0 +1,70 @@
+package login
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/config"
+)
+
+func TestCILogin(t *testing.T) {
+	profilesFile := filepath.Join(os.TempDir(), "stripe", "config.toml")
+	viper.SetConfigFile(profilesFile)
+	defer viper.Reset()
+
+	c := &config.Config{
+		Color:    "auto",
+		LogLevel: "info",
+		Profile: config.Profile{
+			DeviceName:  "ci-runner",
+			ProfileName: "tests",
+		},
+		ProfilesFile: profilesFile,
+	}
+
+	var pollURL string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/stripecli/auth":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(Links{
+				BrowserURL:       "https://dashboard.stripe.com/stripecli/confirm_auth?t=cliauth_secret",
+				PollURL:          pollURL,
+				VerificationCode: "dinosaur-pineapple-polkadot",
+			})
+		case "/stripecli/auth/cliauth_123":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"redeemed": true, "account_id": "acct_123", "testmode_key_secret": "sk_test_1234", "account_display_name": "test_disp_name"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	pollURL = fmt.Sprintf("%s%s", ts.URL, "/stripecli/auth/cliauth_123?secret=cliauth_secret")
+
+	var out, msgOut bytes.Buffer
+	err := CILogin(context.Background(), ts.URL, c, CIOptions{Timeout: 5 * time.Second, PrintEnv: true}, &out, &msgOut)
+	require.NoError(t, err)
+	require.Equal(t, "STRIPE_API_KEY=sk_test_1234\n", out.String())
+	require.Contains(t, msgOut.String(), "dinosaur-pineapple-polkadot")
+	require.True(t, c.Profile.ExpiresAt.IsZero())
+
+	out.Reset()
+	err = CILogin(context.Background(), ts.URL, c, CIOptions{TTL: time.Hour, Timeout: 5 * time.Second}, &out, &msgOut)
+	require.NoError(t, err)
+	require.Empty(t, out.String())
+	require.WithinDuration(t, time.Now().Add(time.Hour), c.Profile.ExpiresAt, time.Minute)
+}
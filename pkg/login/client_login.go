@@ -12,7 +12,9 @@ import (
 	"github.com/briandowns/spinner"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	configPkg "github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/devcontainer"
 	"github.com/stripe/stripe-cli/pkg/open"
 	"github.com/stripe/stripe-cli/pkg/stripe"
 	"github.com/stripe/stripe-cli/pkg/validators"
@@ -21,6 +23,10 @@ import (
 var openBrowser = open.Browser
 var canOpenBrowser = open.CanOpenBrowser
 
+// inContainer is whether the CLI runs in the dev container of a cloud IDE,
+// whose browser is on another machine
+var inContainer = devcontainer.InContainer
+
 const stripeCLIAuthPath = "/stripecli/auth"
 
 // Links provides the URLs for the CLI to continue the login flow
@@ -51,7 +57,7 @@ func Login(ctx context.Context, baseURL string, config *configPkg.Config, input
 
 	var s *spinner.Spinner
 
-	if isSSH() || !canOpenBrowser() {
+	if isSSH() || inContainer() || !canOpenBrowser() {
 		fmt.Printf("To authenticate with Stripe, please go to: %s\n", links.BrowserURL)
 
 		s = ansi.StartNewSpinner("Waiting for confirmation...", os.Stdout)
@@ -139,7 +145,7 @@ func GetLinks(ctx context.Context, baseURL string, deviceName string) (*Links, e
 	}
 
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected http status code: %d %s", res.StatusCode, string(bodyBytes))
+		return nil, clierrors.Errorf(clierrors.UnexpectedResponse, "unexpected http status code: %d %s", res.StatusCode, string(bodyBytes))
 	}
 
 	var links Links
@@ -3,7 +3,6 @@ package login
 import (
 	"bufio"
 	"context"
-	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -14,6 +13,7 @@ import (
 	"golang.org/x/term"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
 	"github.com/stripe/stripe-cli/pkg/stripe"
 	"github.com/stripe/stripe-cli/pkg/validators"
@@ -76,7 +76,7 @@ func getConfigureAPIKey(input io.Reader) (string, error) {
 
 	apiKey = strings.TrimSpace(apiKey)
 	if apiKey == "" {
-		return "", errors.New("API key is required, please provide your API key")
+		return "", clierrors.New(clierrors.APIKeyNotConfigured, "API key is required, please provide your API key")
 	}
 
 	err = validators.APIKey(apiKey)
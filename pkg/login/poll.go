@@ -3,13 +3,12 @@ package login
 import (
 	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"time"
 
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/stripe"
 )
 
@@ -65,7 +64,7 @@ func PollForKey(ctx context.Context, pollURL string, interval time.Duration, max
 		res.Body.Close()
 
 		if res.StatusCode != http.StatusOK {
-			return nil, nil, fmt.Errorf("unexpected http status code: %d %s", res.StatusCode, string(bodyBytes))
+			return nil, nil, clierrors.Errorf(clierrors.UnexpectedResponse, "unexpected http status code: %d %s", res.StatusCode, string(bodyBytes))
 		}
 
 		jsonErr := json.Unmarshal(bodyBytes, &response)
@@ -87,5 +86,5 @@ func PollForKey(ctx context.Context, pollURL string, interval time.Duration, max
 		time.Sleep(interval)
 	}
 
-	return nil, nil, errors.New("exceeded max attempts")
+	return nil, nil, clierrors.New(clierrors.Timeout, "exceeded max attempts")
 }
@@ -0,0 +1,13 @@
+// Package logtailing streams API request logs from Stripe, as used by
+// `stripe logs tail`.
+//
+// Programs embedding the CLI can rely on New, which builds a Tailer from a
+// Config, and Tailer.Run, which streams until its context is done. Request
+// logs are delivered on Config.OutCh as EventPayload values, and can be
+// narrowed down with LogFilters. New fields may be added to EventPayload as
+// Stripe adds them to request logs, but the existing ones are kept within a
+// major version of the CLI.
+//
+// ExportWriter, which writes the files of `stripe logs tail --output-file`,
+// follows the flags of that command rather than this guarantee.
+package logtailing
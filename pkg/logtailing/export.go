@@ -0,0 +1,285 @@
+package logtailing
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/version"
+)
+
+// Export formats supported by ExportWriter
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatHAR    = "har"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// apiBaseURL is prepended to request paths in HAR files, which need absolute URLs
+const apiBaseURL = "https://api.stripe.com"
+
+var csvHeader = []string{"created_at", "livemode", "method", "url", "status", "request_id", "error_type", "error_code", "decline_code", "error_message", "error_param"}
+
+// ExportWriter writes request logs to a file, rotating it once it grows over
+// a maximum size
+type ExportWriter struct {
+	fs      afero.Fs
+	path    string
+	format  string
+	maxSize int64
+
+	file    afero.File
+	csv     *csv.Writer
+	size    int64
+	entries int
+}
+
+// ExportFormatForPath returns the export format matching the file's
+// extension, defaulting to NDJSON
+func ExportFormatForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".har":
+		return ExportFormatHAR
+	case ".csv":
+		return ExportFormatCSV
+	default:
+		return ExportFormatNDJSON
+	}
+}
+
+// NewExportWriter creates the file at path and returns a writer for request
+// logs in the given format. When maxSize is greater than zero, the file is
+// moved aside to a numbered file once it reaches maxSize bytes, and a new one
+// is started.
+func NewExportWriter(fs afero.Fs, path, format string, maxSize int64) (*ExportWriter, error) {
+	format = strings.ToLower(format)
+	switch format {
+	case ExportFormatCSV, ExportFormatHAR, ExportFormatNDJSON:
+	default:
+		return nil, clierrors.Errorf(clierrors.InvalidFlagValue, "unsupported export format '%s', must be one of har, csv or ndjson", format)
+	}
+
+	w := &ExportWriter{
+		fs:      fs,
+		path:    path,
+		format:  format,
+		maxSize: maxSize,
+	}
+
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Write appends a request log to the file
+func (w *ExportWriter) Write(payload EventPayload) error {
+	if w.maxSize > 0 && w.size >= w.maxSize && w.entries > 0 {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	switch w.format {
+	case ExportFormatCSV:
+		err = w.writeCSV(payload)
+	case ExportFormatHAR:
+		err = w.writeHAR(payload)
+	default:
+		err = w.writeNDJSON(payload)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	w.entries++
+
+	return nil
+}
+
+// Close completes and closes the file. It must be called for HAR files to be
+// valid.
+func (w *ExportWriter) Close() error {
+	if w.file == nil {
+		return nil
+	}
+
+	if w.format == ExportFormatHAR {
+		if err := w.write("]}}\n"); err != nil {
+			return err
+		}
+	}
+
+	err := w.file.Close()
+	w.file = nil
+
+	return err
+}
+
+//
+// Private functions
+//
+
+func (w *ExportWriter) open() error {
+	file, err := w.fs.Create(w.path)
+	if err != nil {
+		return err
+	}
+
+	w.file = file
+	w.size = 0
+	w.entries = 0
+
+	switch w.format {
+	case ExportFormatCSV:
+		w.csv = csv.NewWriter(&countingWriter{w: file, n: &w.size})
+		w.csv.Write(csvHeader)
+		w.csv.Flush()
+		return w.csv.Error()
+	case ExportFormatHAR:
+		creator, _ := json.Marshal(map[string]string{"name": "stripe-cli", "version": version.Version})
+		return w.write(fmt.Sprintf(`{"log":{"version":"1.2","creator":%s,"entries":[`, creator) + "\n")
+	}
+
+	return nil
+}
+
+// rotate closes the current file, moves it to the first free numbered path
+// and starts a new one
+func (w *ExportWriter) rotate() error {
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	ext := filepath.Ext(w.path)
+	base := strings.TrimSuffix(w.path, ext)
+
+	for i := 1; ; i++ {
+		rotated := fmt.Sprintf("%s.%d%s", base, i, ext)
+		if exists, err := afero.Exists(w.fs, rotated); err != nil {
+			return err
+		} else if exists {
+			continue
+		}
+
+		if err := w.fs.Rename(w.path, rotated); err != nil {
+			return err
+		}
+		break
+	}
+
+	return w.open()
+}
+
+func (w *ExportWriter) write(s string) error {
+	n, err := io.WriteString(w.file, s)
+	w.size += int64(n)
+
+	return err
+}
+
+func (w *ExportWriter) writeNDJSON(payload EventPayload) error {
+	line, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return w.write(string(line) + "\n")
+}
+
+func (w *ExportWriter) writeCSV(payload EventPayload) error {
+	w.csv.Write([]string{
+		time.Unix(int64(payload.CreatedAt), 0).UTC().Format(time.RFC3339),
+		strconv.FormatBool(payload.Livemode),
+		payload.Method,
+		payload.URL,
+		strconv.Itoa(payload.Status),
+		payload.RequestID,
+		payload.Error.Type,
+		payload.Error.Code,
+		payload.Error.DeclineCode,
+		payload.Error.Message,
+		payload.Error.Param,
+	})
+	w.csv.Flush()
+
+	return w.csv.Error()
+}
+
+func (w *ExportWriter) writeHAR(payload EventPayload) error {
+	url := payload.URL
+	if strings.HasPrefix(url, "/") {
+		url = apiBaseURL + url
+	}
+
+	entry := map[string]interface{}{
+		"startedDateTime": time.Unix(int64(payload.CreatedAt), 0).UTC().Format(time.RFC3339),
+		"time":            0,
+		"request": map[string]interface{}{
+			"method":      payload.Method,
+			"url":         url,
+			"httpVersion": "HTTP/1.1",
+			"headers":     []interface{}{},
+			"queryString": []interface{}{},
+			"cookies":     []interface{}{},
+			"headersSize": -1,
+			"bodySize":    -1,
+		},
+		"response": map[string]interface{}{
+			"status":      payload.Status,
+			"statusText":  http.StatusText(payload.Status),
+			"httpVersion": "HTTP/1.1",
+			"headers":     []interface{}{},
+			"cookies":     []interface{}{},
+			"content":     map[string]interface{}{"size": 0, "mimeType": "application/json"},
+			"redirectURL": "",
+			"headersSize": -1,
+			"bodySize":    -1,
+		},
+		"cache":      map[string]interface{}{},
+		"timings":    map[string]interface{}{"send": 0, "wait": 0, "receive": 0},
+		"_requestId": payload.RequestID,
+		"_livemode":  payload.Livemode,
+	}
+
+	if payload.Error != (RedactedError{}) {
+		entry["_error"] = payload.Error
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	separator := ""
+	if w.entries > 0 {
+		separator = ","
+	}
+
+	return w.write(separator + string(line) + "\n")
+}
+
+// countingWriter keeps track of how many bytes were written through it
+type countingWriter struct {
+	w io.Writer
+	n *int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	*c.n += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,112 @@
+package logtailing
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+var testPayloads = []EventPayload{
+	{CreatedAt: 1600000000, Method: "POST", URL: "/v1/charges", RequestID: "req_1", Status: 200},
+	{CreatedAt: 1600000001, Method: "GET", URL: "/v1/customers/cus_1", RequestID: "req_2", Status: 404, Error: RedactedError{Type: "invalid_request_error", Message: "No such customer"}},
+}
+
+func writeTestPayloads(t *testing.T, fs afero.Fs, path, format string, maxSize int64) {
+	w, err := NewExportWriter(fs, path, format, maxSize)
+	require.NoError(t, err)
+
+	for _, payload := range testPayloads {
+		require.NoError(t, w.Write(payload))
+	}
+	require.NoError(t, w.Close())
+}
+
+func TestExportNDJSON(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestPayloads(t, fs, "requests.ndjson", ExportFormatNDJSON, 0)
+
+	content, err := afero.ReadFile(fs, "requests.ndjson")
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+
+	var payload EventPayload
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &payload))
+	require.Equal(t, testPayloads[1], payload)
+}
+
+func TestExportCSV(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestPayloads(t, fs, "requests.csv", ExportFormatCSV, 0)
+
+	content, err := afero.ReadFile(fs, "requests.csv")
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 3)
+	require.True(t, strings.HasPrefix(lines[0], "created_at,livemode,method,url,status"))
+	require.Equal(t, "2020-09-13T12:26:41Z,false,GET,/v1/customers/cus_1,404,req_2,invalid_request_error,,,No such customer,", lines[2])
+}
+
+func TestExportHAR(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestPayloads(t, fs, "requests.har", ExportFormatHAR, 0)
+
+	content, err := afero.ReadFile(fs, "requests.har")
+	require.NoError(t, err)
+
+	var har struct {
+		Log struct {
+			Version string `json:"version"`
+			Entries []struct {
+				Request struct {
+					Method string `json:"method"`
+					URL    string `json:"url"`
+				} `json:"request"`
+				Response struct {
+					Status int `json:"status"`
+				} `json:"response"`
+				Error *RedactedError `json:"_error"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	require.NoError(t, json.Unmarshal(content, &har))
+	require.Equal(t, "1.2", har.Log.Version)
+	require.Len(t, har.Log.Entries, 2)
+	require.Equal(t, "https://api.stripe.com/v1/charges", har.Log.Entries[0].Request.URL)
+	require.Nil(t, har.Log.Entries[0].Error)
+	require.Equal(t, 404, har.Log.Entries[1].Response.Status)
+	require.Equal(t, "No such customer", har.Log.Entries[1].Error.Message)
+}
+
+func TestExportRotation(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestPayloads(t, fs, "requests.har", ExportFormatHAR, 1)
+
+	// every file is a complete HAR file with the entries written to it
+	for _, path := range []string{"requests.1.har", "requests.har"} {
+		content, err := afero.ReadFile(fs, path)
+		require.NoError(t, err)
+
+		var har map[string]interface{}
+		require.NoError(t, json.Unmarshal(content, &har), path)
+	}
+
+	exists, _ := afero.Exists(fs, "requests.2.har")
+	require.False(t, exists)
+}
+
+func TestExportUnsupportedFormat(t *testing.T) {
+	_, err := NewExportWriter(afero.NewMemMapFs(), "requests.xml", "xml", 0)
+	require.EqualError(t, err, "unsupported export format 'xml', must be one of har, csv or ndjson")
+}
+
+func TestExportFormatForPath(t *testing.T) {
+	require.Equal(t, ExportFormatHAR, ExportFormatForPath("requests.HAR"))
+	require.Equal(t, ExportFormatCSV, ExportFormatForPath("out/requests.csv"))
+	require.Equal(t, ExportFormatNDJSON, ExportFormatForPath("requests.log"))
+}
@@ -10,6 +10,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/stripeauth"
 	"github.com/stripe/stripe-cli/pkg/websocket"
 )
@@ -117,7 +118,7 @@ func (t *Tailer) Run(ctx context.Context) error {
 
 		if err != nil {
 			t.cfg.OutCh <- websocket.ErrorElement{
-				Error: fmt.Errorf("Error while authenticating with Stripe: %v", err),
+				Error: clierrors.Errorf(clierrors.AuthorizationFailed, "Error while authenticating with Stripe: %v", err),
 			}
 			return err
 		}
@@ -165,7 +166,7 @@ func (t *Tailer) Run(ctx context.Context) error {
 					State: websocket.Reconnecting,
 				}
 			} else {
-				err := fmt.Errorf("Session expired. Terminating after %d failed attempts to reauthorize", nAttempts)
+				err := clierrors.Errorf(clierrors.AuthorizationFailed, "Session expired. Terminating after %d failed attempts to reauthorize", nAttempts)
 				t.cfg.OutCh <- websocket.ErrorElement{
 					Error: err,
 				}
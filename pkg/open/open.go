@@ -1,10 +1,11 @@
 package open
 
 import (
-	"fmt"
 	"runtime"
 
 	exec "golang.org/x/sys/execabs"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 )
 
 var execCommand = exec.Command
@@ -21,7 +22,7 @@ func Browser(url string) error {
 	case "darwin":
 		err = execCommand("open", url).Start()
 	default:
-		err = fmt.Errorf("unsupported platform")
+		err = clierrors.New(clierrors.UnsupportedPlatform, "unsupported platform")
 	}
 
 	if err != nil {
@@ -0,0 +1,339 @@
+// Package otellogs exports log records to an OpenTelemetry collector with
+// OTLP over HTTP, encoded in JSON, so that the traffic the CLI sees can be
+// viewed along with the traces of the app receiving it.
+package otellogs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/version"
+)
+
+const (
+	logsPath = "/v1/logs"
+
+	// flushInterval is how often the pending records are exported
+	flushInterval = time.Second
+	// maxPending is how many records are kept while the collector can't be
+	// reached, newer ones being dropped
+	maxPending = 1000
+
+	exportTimeout = 5 * time.Second
+)
+
+// Severity is the severity number of a record
+type Severity int
+
+// The severities of the records of the CLI
+const (
+	SeverityInfo  Severity = 9
+	SeverityWarn  Severity = 13
+	SeverityError Severity = 17
+)
+
+func (s Severity) String() string {
+	switch {
+	case s >= SeverityError:
+		return "ERROR"
+	case s >= SeverityWarn:
+		return "WARN"
+	default:
+		return "INFO"
+	}
+}
+
+// Record is a log record
+type Record struct {
+	Time     time.Time
+	Severity Severity
+	Body     string
+	// Attributes are strings, bools, ints, int64s or float64s
+	Attributes map[string]interface{}
+	// Trace is the trace the record belongs to, if any
+	Trace TraceContext
+}
+
+// Exporter batches records and sends them to a collector
+type Exporter struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+
+	mu      sync.Mutex
+	pending []Record
+	failing bool
+}
+
+// NewExporter returns an exporter to the OTLP/HTTP endpoint of a collector,
+// such as http://localhost:4318. The logs path /v1/logs is added unless the
+// URL already ends with it. The headers of OTEL_EXPORTER_OTLP_HEADERS are sent
+// with every export, as with the OpenTelemetry SDKs.
+func NewExporter(endpoint string) (*Exporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, clierrors.Errorf(clierrors.InvalidOTLPConfig, "invalid OTLP endpoint %q, expected an http or https URL such as http://localhost:4318", endpoint)
+	}
+
+	if !strings.HasSuffix(u.Path, logsPath) {
+		u.Path = strings.TrimSuffix(u.Path, "/") + logsPath
+	}
+
+	headers, err := parseHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Exporter{
+		url:     u.String(),
+		headers: headers,
+		client:  &http.Client{Timeout: exportTimeout},
+	}, nil
+}
+
+// parseHeaders parses headers written as key1=value1,key2=value2
+func parseHeaders(value string) (map[string]string, error) {
+	headers := make(map[string]string)
+
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, clierrors.Errorf(clierrors.InvalidOTLPConfig, "invalid OTEL_EXPORTER_OTLP_HEADERS %q, expected key1=value1,key2=value2", value)
+		}
+
+		if unescaped, err := url.QueryUnescape(strings.TrimSpace(val)); err == nil {
+			val = unescaped
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+
+	return headers, nil
+}
+
+// Emit queues a record to be exported
+func (e *Exporter) Emit(record Record) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.pending) < maxPending {
+		e.pending = append(e.pending, record)
+	}
+}
+
+// Run exports the queued records every second until ctx is done, then
+// exports the last ones
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.flushAndLog(ctx)
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+			defer cancel()
+
+			e.flushAndLog(flushCtx)
+			return
+		}
+	}
+}
+
+// flushAndLog flushes the records, warning when the collector stops
+// accepting them rather than on every failed export
+func (e *Exporter) flushAndLog(ctx context.Context) {
+	err := e.Flush(ctx)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err != nil && !e.failing {
+		log.Warnf("Failed to export logs to %s: %v", e.url, err)
+	}
+	e.failing = err != nil
+}
+
+// Flush exports the queued records. The records are dropped even if the
+// export fails, so that a collector that's down doesn't hold them forever.
+func (e *Exporter) Flush(ctx context.Context) error {
+	e.mu.Lock()
+	records := e.pending
+	e.pending = nil
+	e.mu.Unlock()
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(newExportRequest(records))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return clierrors.Errorf(clierrors.OTLPExportFailed, "the collector responded %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
+
+// exportRequest is the JSON encoding of an OTLP ExportLogsServiceRequest
+type exportRequest struct {
+	ResourceLogs []resourceLogs `json:"resourceLogs"`
+}
+
+type resourceLogs struct {
+	Resource  resource    `json:"resource"`
+	ScopeLogs []scopeLogs `json:"scopeLogs"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeLogs struct {
+	Scope      scope       `json:"scope"`
+	LogRecords []logRecord `json:"logRecords"`
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type logRecord struct {
+	TimeUnixNano         string     `json:"timeUnixNano"`
+	ObservedTimeUnixNano string     `json:"observedTimeUnixNano"`
+	SeverityNumber       Severity   `json:"severityNumber"`
+	SeverityText         string     `json:"severityText"`
+	Body                 anyValue   `json:"body"`
+	Attributes           []keyValue `json:"attributes,omitempty"`
+	TraceID              string     `json:"traceId,omitempty"`
+	SpanID               string     `json:"spanId,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+// anyValue is an attribute value, with 64-bit integers encoded as strings as
+// in the JSON encoding of protobuf
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func newExportRequest(records []Record) exportRequest {
+	observed := unixNano(time.Now())
+
+	logRecords := make([]logRecord, len(records))
+	for i, record := range records {
+		logRecords[i] = logRecord{
+			TimeUnixNano:         unixNano(record.Time),
+			ObservedTimeUnixNano: observed,
+			SeverityNumber:       record.Severity,
+			SeverityText:         record.Severity.String(),
+			Body:                 newAnyValue(record.Body),
+			Attributes:           newKeyValues(record.Attributes),
+		}
+		if record.Trace.IsValid() {
+			logRecords[i].TraceID = record.Trace.TraceID
+			logRecords[i].SpanID = record.Trace.SpanID
+		}
+	}
+
+	return exportRequest{
+		ResourceLogs: []resourceLogs{{
+			Resource: resource{Attributes: newKeyValues(map[string]interface{}{
+				"service.name":    "stripe-cli",
+				"service.version": version.Version,
+			})},
+			ScopeLogs: []scopeLogs{{
+				Scope:      scope{Name: "github.com/stripe/stripe-cli", Version: version.Version},
+				LogRecords: logRecords,
+			}},
+		}},
+	}
+}
+
+func unixNano(t time.Time) string {
+	if t.IsZero() {
+		return "0"
+	}
+
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// newKeyValues returns the attributes sorted by key
+func newKeyValues(attributes map[string]interface{}) []keyValue {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	keyValues := make([]keyValue, len(keys))
+	for i, key := range keys {
+		keyValues[i] = keyValue{Key: key, Value: newAnyValue(attributes[key])}
+	}
+
+	return keyValues
+}
+
+func newAnyValue(value interface{}) anyValue {
+	switch v := value.(type) {
+	case string:
+		return anyValue{StringValue: &v}
+	case bool:
+		return anyValue{BoolValue: &v}
+	case int:
+		s := strconv.Itoa(v)
+		return anyValue{IntValue: &s}
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		return anyValue{IntValue: &s}
+	case float64:
+		return anyValue{DoubleValue: &v}
+	default:
+		s := fmt.Sprint(v)
+		return anyValue{StringValue: &s}
+	}
+}
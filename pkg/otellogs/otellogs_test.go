@@ -0,0 +1,138 @@
+package otellogs
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExporter(t *testing.T) {
+	for endpoint, want := range map[string]string{
+		"http://localhost:4318":            "http://localhost:4318/v1/logs",
+		"http://localhost:4318/":           "http://localhost:4318/v1/logs",
+		"https://otel.example.com/v1/logs": "https://otel.example.com/v1/logs",
+		"https://example.com/otlp":         "https://example.com/otlp/v1/logs",
+	} {
+		exporter, err := NewExporter(endpoint)
+		require.NoError(t, err, endpoint)
+		require.Equal(t, want, exporter.url, endpoint)
+	}
+
+	for _, endpoint := range []string{"localhost:4318", "grpc://localhost:4317", ""} {
+		_, err := NewExporter(endpoint)
+		require.Error(t, err, endpoint)
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := parseHeaders("x-api-key=abc%3D, tenant = acme,")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"x-api-key": "abc=", "tenant": "acme"}, headers)
+
+	_, err = parseHeaders("x-api-key")
+	require.Error(t, err)
+}
+
+func TestFlush(t *testing.T) {
+	var body []byte
+	var header http.Header
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/logs", r.URL.Path)
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer ts.Close()
+
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "x-api-key=secret")
+
+	exporter, err := NewExporter(ts.URL)
+	require.NoError(t, err)
+
+	require.NoError(t, exporter.Flush(context.Background()))
+	require.Nil(t, body, "nothing to export")
+
+	exporter.Emit(Record{
+		Time:     time.Unix(1700000000, 5),
+		Severity: SeverityError,
+		Body:     "[500] POST http://localhost:3000/hooks [evt_123]",
+		Attributes: map[string]interface{}{
+			"stripe.event.id":           "evt_123",
+			"http.response.status_code": 500,
+			"stripe.livemode":           false,
+		},
+		Trace: TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"},
+	})
+
+	require.NoError(t, exporter.Flush(context.Background()))
+	require.Equal(t, "application/json", header.Get("Content-Type"))
+	require.Equal(t, "secret", header.Get("x-api-key"))
+
+	var request struct {
+		ResourceLogs []struct {
+			ScopeLogs []struct {
+				LogRecords []map[string]interface{} `json:"logRecords"`
+			} `json:"scopeLogs"`
+		} `json:"resourceLogs"`
+	}
+	require.NoError(t, json.Unmarshal(body, &request))
+
+	record := request.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	require.Equal(t, "1700000000000000005", record["timeUnixNano"])
+	require.Equal(t, float64(17), record["severityNumber"])
+	require.Equal(t, "ERROR", record["severityText"])
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record["traceId"])
+	require.Equal(t, "00f067aa0ba902b7", record["spanId"])
+	require.Equal(t, map[string]interface{}{"stringValue": "[500] POST http://localhost:3000/hooks [evt_123]"}, record["body"])
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"key": "http.response.status_code", "value": map[string]interface{}{"intValue": "500"}},
+		map[string]interface{}{"key": "stripe.event.id", "value": map[string]interface{}{"stringValue": "evt_123"}},
+		map[string]interface{}{"key": "stripe.livemode", "value": map[string]interface{}{"boolValue": false}},
+	}, record["attributes"])
+
+	body = nil
+	require.NoError(t, exporter.Flush(context.Background()))
+	require.Nil(t, body, "the records are only exported once")
+}
+
+func TestFlush_CollectorError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	exporter, err := NewExporter(ts.URL)
+	require.NoError(t, err)
+
+	exporter.Emit(Record{Body: "hello"})
+
+	err = exporter.Flush(context.Background())
+	require.EqualError(t, err, "the collector responded 401: unauthorized")
+}
+
+func TestRun_ExportsOnExit(t *testing.T) {
+	exports := make(chan []byte, 1)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		exports <- body
+	}))
+	defer ts.Close()
+
+	exporter, err := NewExporter(ts.URL)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	exporter.Emit(Record{Body: "last words"})
+	exporter.Run(ctx)
+
+	require.Contains(t, string(<-exports), "last words")
+}
@@ -0,0 +1,60 @@
+package otellogs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// TraceContext identifies a span of a trace, as in the W3C traceparent header
+type TraceContext struct {
+	// TraceID is 32 lowercase hex digits
+	TraceID string
+	// SpanID is 16 lowercase hex digits
+	SpanID string
+}
+
+// NewTraceContext returns a span of a new trace
+func NewTraceContext() TraceContext {
+	return TraceContext{TraceID: randomHex(16), SpanID: randomHex(8)}
+}
+
+// IsValid returns whether tc has trace and span IDs
+func (tc TraceContext) IsValid() bool {
+	return isHexID(tc.TraceID, 32) && isHexID(tc.SpanID, 16)
+}
+
+// Traceparent returns the traceparent header of a sampled span
+func (tc TraceContext) Traceparent() string {
+	return fmt.Sprintf("00-%s-%s-01", tc.TraceID, tc.SpanID)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	for {
+		// crypto/rand doesn't fail on the platforms the CLI runs on
+		rand.Read(b) // #nosec G104
+
+		// all-zero IDs are invalid
+		for _, c := range b {
+			if c != 0 {
+				return hex.EncodeToString(b)
+			}
+		}
+	}
+}
+
+func isHexID(id string, length int) bool {
+	if len(id) != length || strings.Trim(id, "0") == "" {
+		return false
+	}
+
+	for _, c := range id {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
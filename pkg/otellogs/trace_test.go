@@ -0,0 +1,27 @@
+package otellogs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTraceContext(t *testing.T) {
+	tc := NewTraceContext()
+	require.True(t, tc.IsValid())
+	require.NotEqual(t, tc, NewTraceContext())
+	require.Regexp(t, "^00-[0-9a-f]{32}-[0-9a-f]{16}-01$", tc.Traceparent())
+}
+
+func TestIsValid(t *testing.T) {
+	require.True(t, TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}.IsValid())
+
+	for _, tc := range []TraceContext{
+		{},
+		{TraceID: "00000000000000000000000000000000", SpanID: "00f067aa0ba902b7"},
+		{TraceID: "4BF92F3577B34DA6A3CE929D0E0E4736", SpanID: "00f067aa0ba902b7"},
+		{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa"},
+	} {
+		require.False(t, tc.IsValid(), tc)
+	}
+}
@@ -0,0 +1,241 @@
+package passkey
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/open"
+)
+
+// ceremonyTimeout is how long the user has to use their passkey
+const ceremonyTimeout = 3 * time.Minute
+
+var openBrowser = open.Browser
+
+// ceremonyOptions is what the page needs to run a ceremony. Binary values are
+// base64url-encoded.
+type ceremonyOptions struct {
+	Mode         string `json:"mode"`
+	Challenge    string `json:"challenge"`
+	UserID       string `json:"userID,omitempty"`
+	UserName     string `json:"userName,omitempty"`
+	CredentialID string `json:"credentialID,omitempty"`
+}
+
+type ceremonyPage struct {
+	Reason  string
+	Options ceremonyOptions
+}
+
+var pageTemplate = template.Must(template.New("passkey").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Stripe CLI</title>
+</head>
+<body style="font-family: sans-serif; max-width: 40em; margin: 4em auto;">
+<h1>Stripe CLI</h1>
+<p>{{.Reason}}</p>
+<button id="continue">Continue with your passkey</button>
+<p id="status"></p>
+<script>
+const options = {{.Options}};
+
+function encode(buffer) {
+  return btoa(String.fromCharCode(...new Uint8Array(buffer)))
+    .replace(/\+/g, "-").replace(/\//g, "_").replace(/=+$/, "");
+}
+
+function decode(value) {
+  value = value.replace(/-/g, "+").replace(/_/g, "/");
+  while (value.length % 4) value += "=";
+  return Uint8Array.from(atob(value), c => c.charCodeAt(0));
+}
+
+async function ceremony() {
+  if (options.mode === "register") {
+    const credential = await navigator.credentials.create({publicKey: {
+      challenge: decode(options.challenge),
+      rp: {id: "localhost", name: "Stripe CLI"},
+      user: {id: decode(options.userID), name: options.userName, displayName: options.userName},
+      pubKeyCredParams: [{type: "public-key", alg: -7}, {type: "public-key", alg: -8}, {type: "public-key", alg: -257}],
+      authenticatorSelection: {userVerification: "preferred"},
+      attestation: "none",
+      timeout: 120000,
+    }});
+    return {
+      id: encode(credential.rawId),
+      clientDataJSON: encode(credential.response.clientDataJSON),
+      authenticatorData: encode(credential.response.getAuthenticatorData()),
+      publicKey: encode(credential.response.getPublicKey()),
+    };
+  }
+
+  const credential = await navigator.credentials.get({publicKey: {
+    challenge: decode(options.challenge),
+    rpId: "localhost",
+    allowCredentials: [{type: "public-key", id: decode(options.credentialID)}],
+    userVerification: "preferred",
+    timeout: 120000,
+  }});
+  return {
+    id: encode(credential.rawId),
+    clientDataJSON: encode(credential.response.clientDataJSON),
+    authenticatorData: encode(credential.response.authenticatorData),
+    signature: encode(credential.response.signature),
+  };
+}
+
+document.getElementById("continue").addEventListener("click", async () => {
+  const status = document.getElementById("status");
+  let result;
+  try {
+    result = await ceremony();
+  } catch (e) {
+    result = {error: String(e)};
+  }
+  const resp = await fetch("/result", {
+    method: "POST",
+    headers: {"Content-Type": "application/json"},
+    body: JSON.stringify(result),
+  });
+  status.textContent = await resp.text();
+});
+</script>
+</body>
+</html>
+`))
+
+// Register creates a passkey in the browser, which operations are then
+// confirmed with. name is how the passkey is shown by the authenticator.
+func Register(ctx context.Context, name string, out io.Writer) (Credential, error) {
+	userID := make([]byte, 16)
+	if _, err := rand.Read(userID); err != nil {
+		return Credential{}, err
+	}
+
+	var credential Credential
+
+	options := ceremonyOptions{
+		Mode:     "register",
+		UserID:   base64.RawURLEncoding.EncodeToString(userID),
+		UserName: name,
+	}
+
+	err := runCeremony(ctx, "Register a passkey to confirm sensitive operations of the Stripe CLI.", options, out,
+		func(resp ceremonyResponse, challenge []byte, origin string) error {
+			var err error
+			credential, err = verifyRegistration(resp, challenge, origin)
+			return err
+		})
+
+	return credential, err
+}
+
+// Confirm asks for the passkey to be used in the browser, and returns an error
+// unless it was. reason describes the operation being confirmed.
+func Confirm(ctx context.Context, credential Credential, reason string, out io.Writer) error {
+	options := ceremonyOptions{
+		Mode:         "confirm",
+		CredentialID: credential.ID,
+	}
+
+	return runCeremony(ctx, reason, options, out,
+		func(resp ceremonyResponse, challenge []byte, origin string) error {
+			return verifyAssertion(resp, credential, challenge, origin)
+		})
+}
+
+// runCeremony serves the page running the ceremony from localhost, opens it in
+// the browser and waits for the result to be verified
+func runCeremony(ctx context.Context, reason string, options ceremonyOptions, out io.Writer, verify func(ceremonyResponse, []byte, string) error) error {
+	challenge := make([]byte, 32)
+	if _, err := rand.Read(challenge); err != nil {
+		return err
+	}
+	options.Challenge = base64.RawURLEncoding.EncodeToString(challenge)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("could not start the passkey server: %w", err)
+	}
+
+	// the origin must be localhost rather than the IP for it to match the
+	// relying party ID
+	origin := fmt.Sprintf("http://localhost:%d", listener.Addr().(*net.TCPAddr).Port)
+	results := make(chan error, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		pageTemplate.Execute(w, ceremonyPage{Reason: reason, Options: options})
+	})
+	mux.HandleFunc("/result", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Origin") != origin {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		var resp ceremonyResponse
+		if err := json.NewDecoder(io.LimitReader(r.Body, 64*1024)).Decode(&resp); err != nil {
+			http.Error(w, "Invalid result", http.StatusBadRequest)
+			return
+		}
+
+		err := verify(resp, challenge, origin)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed: %s", err), http.StatusBadRequest)
+		} else {
+			fmt.Fprint(w, "Done! You may close this window and return to the Stripe CLI.")
+		}
+
+		select {
+		case results <- err:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+	defer server.Close()
+
+	color := ansi.Color(out)
+	fmt.Fprintf(out, "%s Use your passkey in the browser to continue: %s\n", color.Yellow("Passkey required."), origin)
+
+	if err := openBrowser(origin); err != nil {
+		log.WithFields(log.Fields{
+			"prefix": "passkey.runCeremony",
+		}).Debugf("Failed to open the browser: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ceremonyTimeout)
+	defer cancel()
+
+	select {
+	case err := <-results:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return clierrors.New(clierrors.PasskeyNotUsed, "timed out waiting for the passkey to be used")
+		}
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,207 @@
+// Package passkey confirms sensitive operations with a passkey or security
+// key, through a WebAuthn ceremony run in the browser and served by the CLI
+// from localhost.
+//
+// Registration only keeps the public key of the credential, reported by the
+// browser, and doesn't verify attestation: the passkey proves the user is
+// present, not which authenticator they use.
+package passkey
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// rpID is the relying party the credentials are scoped to
+const rpID = "localhost"
+
+// flagUserPresent is the bit of the authenticator data flags set when the
+// user touched the authenticator
+const flagUserPresent = 0x01
+
+// Credential is a passkey registered to confirm operations
+type Credential struct {
+	// ID is the base64url-encoded ID of the credential
+	ID string
+	// PublicKey is the base64-encoded PKIX public key of the credential
+	PublicKey string
+}
+
+// clientData is the part of the client data the browser signs that is checked
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// ceremonyResponse is what the browser reports once a ceremony completed, with
+// binary values base64url-encoded
+type ceremonyResponse struct {
+	Error             string `json:"error"`
+	ID                string `json:"id"`
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AuthenticatorData string `json:"authenticatorData"`
+	// PublicKey is only set for registrations
+	PublicKey string `json:"publicKey"`
+	// Signature is only set for assertions
+	Signature string `json:"signature"`
+}
+
+// verifyRegistration checks the response to a registration ceremony and
+// returns the credential that was created
+func verifyRegistration(resp ceremonyResponse, challenge []byte, origin string) (Credential, error) {
+	if resp.Error != "" {
+		return Credential{}, clierrors.Errorf(clierrors.PasskeyNotUsed, "the passkey could not be registered: %s", resp.Error)
+	}
+
+	if err := verifyClientData(resp.ClientDataJSON, "webauthn.create", challenge, origin); err != nil {
+		return Credential{}, err
+	}
+
+	if err := verifyAuthenticatorData(resp.AuthenticatorData); err != nil {
+		return Credential{}, err
+	}
+
+	publicKey, err := decode(resp.PublicKey)
+	if err != nil {
+		return Credential{}, err
+	}
+
+	if _, err := x509.ParsePKIXPublicKey(publicKey); err != nil {
+		return Credential{}, clierrors.Errorf(clierrors.PasskeyNotVerified, "the browser reported an unsupported public key: %w", err)
+	}
+
+	if resp.ID == "" {
+		return Credential{}, clierrors.New(clierrors.PasskeyNotVerified, "the browser didn't report the ID of the passkey")
+	}
+
+	return Credential{
+		ID:        resp.ID,
+		PublicKey: base64.StdEncoding.EncodeToString(publicKey),
+	}, nil
+}
+
+// verifyAssertion checks that the response to an authentication ceremony was
+// signed by the credential
+func verifyAssertion(resp ceremonyResponse, credential Credential, challenge []byte, origin string) error {
+	if resp.Error != "" {
+		return clierrors.Errorf(clierrors.PasskeyNotUsed, "the passkey was not used: %s", resp.Error)
+	}
+
+	if resp.ID != credential.ID {
+		return clierrors.New(clierrors.PasskeyNotVerified, "a different passkey than the registered one was used")
+	}
+
+	if err := verifyClientData(resp.ClientDataJSON, "webauthn.get", challenge, origin); err != nil {
+		return err
+	}
+
+	if err := verifyAuthenticatorData(resp.AuthenticatorData); err != nil {
+		return err
+	}
+
+	der, err := base64.StdEncoding.DecodeString(credential.PublicKey)
+	if err != nil {
+		return clierrors.Errorf(clierrors.PasskeyNotVerified, "the registered passkey is invalid, register it again: %w", err)
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return clierrors.Errorf(clierrors.PasskeyNotVerified, "the registered passkey is invalid, register it again: %w", err)
+	}
+
+	authenticatorData, _ := decode(resp.AuthenticatorData)
+	clientDataJSON, _ := decode(resp.ClientDataJSON)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+
+	signature, err := decode(resp.Signature)
+	if err != nil {
+		return err
+	}
+
+	signed := append(append([]byte{}, authenticatorData...), clientDataHash[:]...)
+	if !verifySignature(publicKey, signed, signature) {
+		return clierrors.New(clierrors.PasskeyNotVerified, "the signature of the passkey is invalid")
+	}
+
+	return nil
+}
+
+func verifySignature(publicKey interface{}, signed, signature []byte) bool {
+	digest := sha256.Sum256(signed)
+
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, digest[:], signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, signed, signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	default:
+		return false
+	}
+}
+
+func verifyClientData(encoded, ceremonyType string, challenge []byte, origin string) error {
+	raw, err := decode(encoded)
+	if err != nil {
+		return err
+	}
+
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return clierrors.Errorf(clierrors.PasskeyNotVerified, "the browser reported invalid client data: %w", err)
+	}
+
+	switch {
+	case data.Type != ceremonyType:
+		return clierrors.Errorf(clierrors.PasskeyNotVerified, "expected a %s ceremony, got %s", ceremonyType, data.Type)
+	case data.Challenge != base64.RawURLEncoding.EncodeToString(challenge):
+		return clierrors.New(clierrors.PasskeyNotVerified, "the passkey signed a different challenge")
+	case data.Origin != origin:
+		return clierrors.Errorf(clierrors.PasskeyNotVerified, "the passkey was used from %s instead of %s", data.Origin, origin)
+	}
+
+	return nil
+}
+
+func verifyAuthenticatorData(encoded string) error {
+	data, err := decode(encoded)
+	if err != nil {
+		return err
+	}
+
+	// the data starts with the hash of the relying party ID and the flags
+	if len(data) < sha256.Size+1 {
+		return clierrors.New(clierrors.PasskeyNotVerified, "the browser reported invalid authenticator data")
+	}
+
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	if !bytes.Equal(data[:sha256.Size], rpIDHash[:]) {
+		return clierrors.New(clierrors.PasskeyNotVerified, "the passkey is not scoped to the CLI")
+	}
+
+	if data[sha256.Size]&flagUserPresent == 0 {
+		return clierrors.New(clierrors.PasskeyNotVerified, "the passkey was used without the user being present")
+	}
+
+	return nil
+}
+
+func decode(value string) ([]byte, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, clierrors.Errorf(clierrors.PasskeyNotVerified, "the browser reported an invalid value: %w", err)
+	}
+
+	return decoded, nil
+}
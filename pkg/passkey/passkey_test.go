@@ -0,0 +1,206 @@
+package passkey
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// authenticator signs ceremonies like a security key would
+type authenticator struct {
+	key *ecdsa.PrivateKey
+	id  string
+}
+
+func newAuthenticator(t *testing.T) *authenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return &authenticator{key: key, id: "Y3JlZGVudGlhbA"}
+}
+
+func (a *authenticator) credential(t *testing.T) Credential {
+	der, err := x509.MarshalPKIXPublicKey(&a.key.PublicKey)
+	require.NoError(t, err)
+
+	return Credential{ID: a.id, PublicKey: base64.StdEncoding.EncodeToString(der)}
+}
+
+func authenticatorData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	// the flags are followed by a signature counter
+	return append(append(rpIDHash[:], flags), 0, 0, 0, 1)
+}
+
+func clientDataJSON(t *testing.T, ceremonyType string, challenge []byte, origin string) []byte {
+	data, err := json.Marshal(clientData{
+		Type:      ceremonyType,
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+		Origin:    origin,
+	})
+	require.NoError(t, err)
+
+	return data
+}
+
+func (a *authenticator) register(t *testing.T, challenge []byte, origin string) ceremonyResponse {
+	der, err := x509.MarshalPKIXPublicKey(&a.key.PublicKey)
+	require.NoError(t, err)
+
+	return ceremonyResponse{
+		ID:                a.id,
+		ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientDataJSON(t, "webauthn.create", challenge, origin)),
+		AuthenticatorData: base64.RawURLEncoding.EncodeToString(authenticatorData(flagUserPresent)),
+		PublicKey:         base64.RawURLEncoding.EncodeToString(der),
+	}
+}
+
+func (a *authenticator) assert(t *testing.T, challenge []byte, origin string, flags byte) ceremonyResponse {
+	authData := authenticatorData(flags)
+	clientData := clientDataJSON(t, "webauthn.get", challenge, origin)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+
+	return ceremonyResponse{
+		ID:                a.id,
+		ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientData),
+		AuthenticatorData: base64.RawURLEncoding.EncodeToString(authData),
+		Signature:         base64.RawURLEncoding.EncodeToString(signature),
+	}
+}
+
+func TestVerifyRegistration(t *testing.T) {
+	a := newAuthenticator(t)
+	challenge := []byte("challenge")
+
+	credential, err := verifyRegistration(a.register(t, challenge, "http://localhost:1234"), challenge, "http://localhost:1234")
+	require.NoError(t, err)
+	require.Equal(t, a.credential(t), credential)
+
+	_, err = verifyRegistration(a.register(t, []byte("other"), "http://localhost:1234"), challenge, "http://localhost:1234")
+	require.EqualError(t, err, "the passkey signed a different challenge")
+
+	_, err = verifyRegistration(ceremonyResponse{Error: "NotAllowedError"}, challenge, "http://localhost:1234")
+	require.EqualError(t, err, "the passkey could not be registered: NotAllowedError")
+}
+
+func TestVerifyAssertion(t *testing.T) {
+	a := newAuthenticator(t)
+	credential := a.credential(t)
+	challenge := []byte("challenge")
+	origin := "http://localhost:1234"
+
+	require.NoError(t, verifyAssertion(a.assert(t, challenge, origin, flagUserPresent), credential, challenge, origin))
+
+	err := verifyAssertion(a.assert(t, challenge, "http://evil.example", flagUserPresent), credential, challenge, origin)
+	require.EqualError(t, err, "the passkey was used from http://evil.example instead of http://localhost:1234")
+
+	err = verifyAssertion(a.assert(t, challenge, origin, 0), credential, challenge, origin)
+	require.EqualError(t, err, "the passkey was used without the user being present")
+
+	// a different key signing for the registered credential
+	other := newAuthenticator(t)
+	err = verifyAssertion(other.assert(t, challenge, origin, flagUserPresent), credential, challenge, origin)
+	require.EqualError(t, err, "the signature of the passkey is invalid")
+
+	other.id = "b3RoZXI"
+	err = verifyAssertion(other.assert(t, challenge, origin, flagUserPresent), credential, challenge, origin)
+	require.EqualError(t, err, "a different passkey than the registered one was used")
+}
+
+// browser returns a replacement for openBrowser that completes the ceremony
+// served at the URL with respond
+func browser(t *testing.T, respond func(options ceremonyOptions, origin string) ceremonyResponse) func(string) error {
+	return func(url string) error {
+		go func() {
+			resp, err := http.Get(url)
+			require.NoError(t, err)
+			page, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			// the options are embedded as a JavaScript object in the page
+			start := strings.Index(string(page), "const options = ") + len("const options = ")
+			end := strings.Index(string(page)[start:], ";\n")
+			var options ceremonyOptions
+			require.NoError(t, json.Unmarshal(page[start:start+end], &options))
+
+			body, err := json.Marshal(respond(options, url))
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, url+"/result", bytes.NewReader(body))
+			require.NoError(t, err)
+			req.Header.Set("Origin", url)
+
+			resp, err = http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+		}()
+
+		return nil
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	a := newAuthenticator(t)
+
+	defer func(original func(string) error) { openBrowser = original }(openBrowser)
+	openBrowser = browser(t, func(options ceremonyOptions, origin string) ceremonyResponse {
+		require.Equal(t, "confirm", options.Mode)
+		require.Equal(t, a.id, options.CredentialID)
+
+		challenge, err := base64.RawURLEncoding.DecodeString(options.Challenge)
+		require.NoError(t, err)
+
+		return a.assert(t, challenge, origin, flagUserPresent)
+	})
+
+	out := &bytes.Buffer{}
+	require.NoError(t, Confirm(context.Background(), a.credential(t), "Confirm the test.", out))
+	require.Contains(t, out.String(), "Use your passkey in the browser to continue: http://localhost:")
+}
+
+func TestConfirm_Cancelled(t *testing.T) {
+	a := newAuthenticator(t)
+
+	defer func(original func(string) error) { openBrowser = original }(openBrowser)
+	openBrowser = browser(t, func(options ceremonyOptions, origin string) ceremonyResponse {
+		return ceremonyResponse{Error: "NotAllowedError: The operation either timed out or was not allowed."}
+	})
+
+	err := Confirm(context.Background(), a.credential(t), "Confirm the test.", &bytes.Buffer{})
+	require.EqualError(t, err, "the passkey was not used: NotAllowedError: The operation either timed out or was not allowed.")
+}
+
+func TestRegister(t *testing.T) {
+	a := newAuthenticator(t)
+
+	defer func(original func(string) error) { openBrowser = original }(openBrowser)
+	openBrowser = browser(t, func(options ceremonyOptions, origin string) ceremonyResponse {
+		require.Equal(t, "register", options.Mode)
+		require.Equal(t, "Stripe CLI (default)", options.UserName)
+
+		challenge, err := base64.RawURLEncoding.DecodeString(options.Challenge)
+		require.NoError(t, err)
+
+		return a.register(t, challenge, origin)
+	})
+
+	credential, err := Register(context.Background(), "Stripe CLI (default)", &bytes.Buffer{})
+	require.NoError(t, err)
+	require.Equal(t, a.credential(t), credential)
+}
@@ -0,0 +1,155 @@
+package plugins
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	hcplugin "github.com/hashicorp/go-plugin"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/version"
+)
+
+// ProtocolVersion is the latest version of the RPC interface the CLI serves to
+// plugins. It must be bumped whenever a change to the interface would break
+// plugins built against a previous version.
+const ProtocolVersion = 1
+
+// IncompatiblePluginError is returned when a plugin release cannot be run by
+// the running version of the CLI
+type IncompatiblePluginError struct {
+	Plugin  string
+	Version string
+	Reason  string
+	// NeedsNewerCLI is true when the release requires a newer version of the
+	// CLI, rather than the plugin being too old for it
+	NeedsNewerCLI bool
+}
+
+func (e IncompatiblePluginError) Error() string {
+	remediation := fmt.Sprintf("Please run `stripe plugin upgrade %s`", e.Plugin)
+	if e.NeedsNewerCLI {
+		remediation = "Please update the Stripe CLI to the latest version"
+	}
+
+	return fmt.Sprintf(
+		"plugin '%s' v%s is not compatible with this version of the Stripe CLI (%s). %s",
+		e.Plugin, e.Version, e.Reason, remediation,
+	)
+}
+
+// pluginSets returns the interfaces the CLI can dispense for each protocol
+// version it still supports. The CLI advertises these versions during the
+// handshake so that go-plugin can negotiate one the plugin also speaks.
+func pluginSets() map[int]hcplugin.PluginSet {
+	return map[int]hcplugin.PluginSet{
+		1: {
+			"main": &CLIPluginV1{},
+		},
+	}
+}
+
+// compatibilityIssue returns why the release cannot be run by the given CLI
+// version, or an empty string if it can. needsNewerCLI is true when a newer
+// version of the CLI could run it.
+func (r Release) compatibilityIssue(cliVersion string) (reason string, needsNewerCLI bool) {
+	protocolVersion := r.ProtocolVersion
+	if protocolVersion == 0 {
+		protocolVersion = 1
+	}
+
+	if _, ok := pluginSets()[protocolVersion]; !ok {
+		return fmt.Sprintf("requires plugin protocol v%d, the CLI supports up to v%d", protocolVersion, ProtocolVersion), protocolVersion > ProtocolVersion
+	}
+
+	// builds from source are always assumed to be compatible
+	current, ok := parseVersion(cliVersion)
+	if !ok {
+		return "", false
+	}
+
+	if minVersion, ok := parseVersion(r.MinCLIVersion); ok && compareVersions(current, minVersion) < 0 {
+		return fmt.Sprintf("requires Stripe CLI v%s or later", strings.TrimPrefix(r.MinCLIVersion, "v")), true
+	}
+
+	if maxVersion, ok := parseVersion(r.MaxCLIVersion); ok && compareVersions(current, maxVersion) >= 0 {
+		return fmt.Sprintf("requires a Stripe CLI older than v%s", strings.TrimPrefix(r.MaxCLIVersion, "v")), false
+	}
+
+	return "", false
+}
+
+// filterCompatibleReleases drops the releases that the running CLI cannot run,
+// remembering why so that a clear error can be given if one is requested
+func (p *Plugin) filterCompatibleReleases() {
+	compatible := make([]Release, 0, len(p.Releases))
+
+	for _, release := range p.Releases {
+		if reason, needsNewerCLI := release.compatibilityIssue(version.Version); reason != "" {
+			if p.incompatibleReleases == nil {
+				p.incompatibleReleases = make(map[string]IncompatiblePluginError)
+			}
+			p.incompatibleReleases[release.Version] = IncompatiblePluginError{
+				Plugin:        p.Shortname,
+				Version:       release.Version,
+				Reason:        reason,
+				NeedsNewerCLI: needsNewerCLI,
+			}
+			continue
+		}
+
+		compatible = append(compatible, release)
+	}
+
+	p.Releases = compatible
+}
+
+// checkCompatibility returns an IncompatiblePluginError if the given version
+// of the plugin was found to be incompatible with the running CLI
+func (p *Plugin) checkCompatibility(version string) error {
+	if err, ok := p.incompatibleReleases[version]; ok {
+		return clierrors.Wrap(clierrors.PluginIncompatible, err)
+	}
+
+	return nil
+}
+
+// parseVersion parses a "major.minor.patch" version, with or without a
+// leading "v" and ignoring any pre-release suffix
+func parseVersion(v string) ([3]int, bool) {
+	var parsed [3]int
+
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return parsed, false
+	}
+
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return parsed, false
+		}
+		parsed[i] = n
+	}
+
+	return parsed, true
+}
+
+func compareVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
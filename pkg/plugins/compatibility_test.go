@@ -0,0 +1,101 @@
+package plugins
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/version"
+)
+
+var testCompatibilityManifest = `
+[[Plugin]]
+  Shortname = "appC"
+  Binary = "stripe-cli-app-c"
+
+  [[Plugin.Release]]
+    Arch = "` + runtime.GOARCH + `"
+    OS = "` + runtime.GOOS + `"
+    Version = "1.0.0"
+    MaxCLIVersion = "2.0.0"
+
+  [[Plugin.Release]]
+    Arch = "` + runtime.GOARCH + `"
+    OS = "` + runtime.GOOS + `"
+    Version = "3.0.0"
+    MinCLIVersion = "2.0.0"
+`
+
+func setCLIVersion(t *testing.T, v string) {
+	original := version.Version
+	version.Version = v
+	t.Cleanup(func() { version.Version = original })
+}
+
+func TestCompatibilityIssue(t *testing.T) {
+	release := Release{MinCLIVersion: "1.10.0", MaxCLIVersion: "2.0.0"}
+
+	reason, needsNewerCLI := release.compatibilityIssue("1.10.0")
+	require.Equal(t, "", reason)
+	require.False(t, needsNewerCLI)
+
+	reason, _ = release.compatibilityIssue("v1.12.3")
+	require.Equal(t, "", reason)
+
+	reason, needsNewerCLI = release.compatibilityIssue("1.9.9")
+	require.Equal(t, "requires Stripe CLI v1.10.0 or later", reason)
+	require.True(t, needsNewerCLI)
+
+	reason, needsNewerCLI = release.compatibilityIssue("2.0.0")
+	require.Equal(t, "requires a Stripe CLI older than v2.0.0", reason)
+	require.False(t, needsNewerCLI)
+
+	// builds from source skip the version range check
+	reason, _ = release.compatibilityIssue("master")
+	require.Equal(t, "", reason)
+
+	release = Release{ProtocolVersion: ProtocolVersion + 1}
+	reason, needsNewerCLI = release.compatibilityIssue("master")
+	require.Equal(t, "requires plugin protocol v2, the CLI supports up to v1", reason)
+	require.True(t, needsNewerCLI)
+}
+
+func TestIncompatiblePluginErrorRemediation(t *testing.T) {
+	err := IncompatiblePluginError{Plugin: "appC", Version: "3.0.0", Reason: "requires a Stripe CLI older than v2.0.0"}
+	require.EqualError(t, err, "plugin 'appC' v3.0.0 is not compatible with this version of the Stripe CLI (requires a Stripe CLI older than v2.0.0). Please run `stripe plugin upgrade appC`")
+
+	err.Reason = "requires Stripe CLI v4.0.0 or later"
+	err.NeedsNewerCLI = true
+	require.EqualError(t, err, "plugin 'appC' v3.0.0 is not compatible with this version of the Stripe CLI (requires Stripe CLI v4.0.0 or later). Please update the Stripe CLI to the latest version")
+}
+
+func TestLookUpPluginFiltersIncompatibleReleases(t *testing.T) {
+	setCLIVersion(t, "1.0.0")
+
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/plugins.toml", []byte(testCompatibilityManifest), os.ModePerm)
+	config := &TestConfig{}
+	config.InitConfig()
+
+	plugin, err := LookUpPlugin(context.Background(), config, fs, "appC")
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", plugin.LookUpLatestVersion())
+
+	for _, release := range plugin.Releases {
+		require.NotEqual(t, "3.0.0", release.Version)
+	}
+
+	err = plugin.checkCompatibility("3.0.0")
+	require.EqualError(t, err, "plugin 'appC' v3.0.0 is not compatible with this version of the Stripe CLI (requires Stripe CLI v2.0.0 or later). Please update the Stripe CLI to the latest version")
+
+	err = plugin.Install(context.Background(), config, fs, "3.0.0", "")
+	require.ErrorAs(t, err, &IncompatiblePluginError{})
+
+	code, _ := clierrors.CodeOf(err)
+	require.Equal(t, clierrors.PluginIncompatible, code)
+}
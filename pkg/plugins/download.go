@@ -0,0 +1,122 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/requests"
+)
+
+// Download settings, set by the flags of the install and upgrade commands
+var (
+	// DownloadTimeout limits each attempt at downloading a plugin resource,
+	// including reading its body
+	DownloadTimeout = 5 * time.Minute
+	// DownloadRetries is how many times a download that failed because of the
+	// network or the server is retried
+	DownloadRetries = 3
+)
+
+// downloadBackoff is the wait before the first retry of a download, doubled
+// for each following retry
+var downloadBackoff = time.Second
+
+// StatusError is returned when a plugin resource is answered with a non-2xx
+// status
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("could not download %s: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// retryable returns whether the request may succeed if made again
+func (e *StatusError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// FetchRemoteResource returns the remote resource body. Each attempt is
+// limited to DownloadTimeout, and the attempts failing because of the network
+// or of the server are retried up to DownloadRetries times with an
+// exponential backoff. It gives up as soon as ctx is done.
+func FetchRemoteResource(ctx context.Context, url string) ([]byte, error) {
+	logger := log.WithFields(log.Fields{
+		"prefix": "plugins.FetchRemoteResource",
+	})
+
+	wait := downloadBackoff
+
+	for attempt := 0; ; attempt++ {
+		body, err := fetchOnce(ctx, url)
+		if err == nil {
+			return body, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && !statusErr.retryable() {
+			return nil, clierrors.Wrap(clierrors.PluginDownloadFailed, err)
+		}
+
+		if attempt >= DownloadRetries {
+			return nil, clierrors.Errorf(clierrors.PluginDownloadFailed, "%w (gave up after %d attempts)", err, attempt+1)
+		}
+
+		logger.Debugf("Download attempt %d failed: %s, retrying in %s", attempt+1, err, wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		wait *= 2
+	}
+}
+
+// fetchOnce makes a single attempt at downloading the body of url
+func fetchOnce(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, DownloadTimeout)
+	defer cancel()
+
+	t := &requests.TracedTransport{}
+
+	trace := &httptrace.ClientTrace{
+		GotConn: t.GotConn,
+		DNSDone: t.DNSDone,
+	}
+
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Transport: t}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &StatusError{URL: url, StatusCode: resp.StatusCode}
+	}
+
+	return io.ReadAll(resp.Body)
+}
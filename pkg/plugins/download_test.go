@@ -0,0 +1,119 @@
+package plugins
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func withDownloadSettings(t *testing.T, timeout time.Duration, retries int) {
+	previousTimeout, previousRetries, previousBackoff := DownloadTimeout, DownloadRetries, downloadBackoff
+	DownloadTimeout, DownloadRetries, downloadBackoff = timeout, retries, time.Millisecond
+
+	t.Cleanup(func() {
+		DownloadTimeout, DownloadRetries, downloadBackoff = previousTimeout, previousRetries, previousBackoff
+	})
+}
+
+func TestFetchRemoteResourceRetriesServerErrors(t *testing.T) {
+	withDownloadSettings(t, time.Second, 3)
+
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("plugin"))
+	}))
+	defer ts.Close()
+
+	body, err := FetchRemoteResource(context.Background(), ts.URL)
+	require.NoError(t, err)
+	require.Equal(t, "plugin", string(body))
+	require.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+func TestFetchRemoteResourceGivesUpAfterRetries(t *testing.T) {
+	withDownloadSettings(t, time.Second, 2)
+
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	_, err := FetchRemoteResource(context.Background(), ts.URL)
+	require.ErrorContains(t, err, "502 Bad Gateway (gave up after 3 attempts)")
+	require.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+func TestFetchRemoteResourceDoesNotRetryClientErrors(t *testing.T) {
+	withDownloadSettings(t, time.Second, 3)
+
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	_, err := FetchRemoteResource(context.Background(), ts.URL)
+
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	require.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+	require.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
+
+func TestFetchRemoteResourceTimesOutHungAttempts(t *testing.T) {
+	withDownloadSettings(t, 50*time.Millisecond, 1)
+
+	var attempts int32
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-done:
+			}
+			return
+		}
+		w.Write([]byte("plugin"))
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	body, err := FetchRemoteResource(context.Background(), ts.URL)
+	require.NoError(t, err)
+	require.Equal(t, "plugin", string(body))
+	require.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+}
+
+func TestFetchRemoteResourceStopsWhenCanceled(t *testing.T) {
+	withDownloadSettings(t, time.Minute, 3)
+
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := FetchRemoteResource(ctx, ts.URL)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
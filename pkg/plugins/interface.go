@@ -4,6 +4,8 @@ import (
 	"net/rpc"
 
 	hcplugin "github.com/hashicorp/go-plugin"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 )
 
 // Server -----------------------------------------------
@@ -14,6 +16,25 @@ type Dispatcher interface {
 	RunCommand(args []string) (string, error)
 }
 
+// FixtureProvider is an optional interface a plugin can implement to contribute
+// trigger definitions and custom fixture step types to the CLI
+type FixtureProvider interface {
+	GetTriggerFixture(event string) (string, error)
+	RunFixtureStep(step FixtureStep) (string, error)
+}
+
+// FixtureStep is a fixture step handed off to a plugin, with all of its
+// references already resolved by the CLI
+type FixtureStep struct {
+	Name   string
+	Method string
+	Path   string
+	Params []string
+}
+
+// ErrFixturesNotSupported is returned when the plugin does not implement FixtureProvider
+var ErrFixturesNotSupported = clierrors.New(clierrors.PluginNoFixtures, "this plugin does not provide any fixtures")
+
 // DispatcherRPCServer is the RPC server that a plugin talks to, conforming to
 // the requirements of net/rpc
 type DispatcherRPCServer struct {
@@ -31,6 +52,30 @@ func (s *DispatcherRPCServer) RunCommand(args []string, resp *string) error {
 	return err
 }
 
+// GetTriggerFixture returns the fixture content backing one of the plugin's triggers
+func (s *DispatcherRPCServer) GetTriggerFixture(event string, resp *string) error {
+	provider, ok := s.Impl.(FixtureProvider)
+	if !ok {
+		return ErrFixturesNotSupported
+	}
+
+	var err error
+	*resp, err = provider.GetTriggerFixture(event)
+	return err
+}
+
+// RunFixtureStep runs one of the plugin's custom fixture steps and returns its JSON response
+func (s *DispatcherRPCServer) RunFixtureStep(step FixtureStep, resp *string) error {
+	provider, ok := s.Impl.(FixtureProvider)
+	if !ok {
+		return ErrFixturesNotSupported
+	}
+
+	var err error
+	*resp, err = provider.RunFixtureStep(step)
+	return err
+}
+
 // CLI Client ---------------------------------------------------
 
 // PluginClient is an implementation that talks over RPC
@@ -50,6 +95,28 @@ func (g *PluginClient) RunCommand(args []string) (string, error) {
 	return resp, nil
 }
 
+// GetTriggerFixture asks the plugin for the fixture content backing one of its triggers
+func (g *PluginClient) GetTriggerFixture(event string) (string, error) {
+	var resp string
+	err := g.client.Call("Plugin.GetTriggerFixture", event, &resp)
+	if err != nil {
+		return "", err
+	}
+
+	return resp, nil
+}
+
+// RunFixtureStep asks the plugin to run one of its custom fixture steps
+func (g *PluginClient) RunFixtureStep(step FixtureStep) (string, error) {
+	var resp string
+	err := g.client.Call("Plugin.RunFixtureStep", step, &resp)
+	if err != nil {
+		return "", err
+	}
+
+	return resp, nil
+}
+
 // Plugin --------------------------------------------------------
 
 // CLIPluginV1 is the implementation of plugin.Plugin so we can serve/consume this
@@ -0,0 +1,288 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/requests"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+)
+
+// releaseNotesFileName is the file next to the builds of a plugin version
+// that describes what changed in it
+const releaseNotesFileName = "RELEASE_NOTES.md"
+
+// maxReleaseNotesLines is how many lines of release notes are shown when the
+// plugin manifest is refreshed
+const maxReleaseNotesLines = 10
+
+// ManifestDiff is what changed in the plugin manifest when it was refreshed
+type ManifestDiff struct {
+	// NewPlugins are the plugins added to the manifest
+	NewPlugins []Plugin
+	// Upgrades are the releases added for installed plugins
+	Upgrades []PluginUpgrade
+	// Deprecated are the installed plugins newly deprecated
+	Deprecated []Plugin
+}
+
+// PluginUpgrade is a newer version of an installed plugin
+type PluginUpgrade struct {
+	Plugin           string
+	InstalledVersion string
+	Version          string
+	// ReleaseNotesURL is where the release notes of the version are published,
+	// if the plugin base URL is known
+	ReleaseNotesURL string
+	// ReleaseNotes is the start of the release notes, if they were fetched
+	ReleaseNotes string
+	Deprecated   string
+}
+
+// Empty returns whether nothing worth reporting changed
+func (d ManifestDiff) Empty() bool {
+	return len(d.NewPlugins) == 0 && len(d.Upgrades) == 0 && len(d.Deprecated) == 0
+}
+
+// DiffPluginManifests compares the manifest before and after a refresh. Only
+// the releases that run on this platform and with this version of the CLI are
+// reported, and only for the installed plugins, along with their
+// deprecations.
+func DiffPluginManifests(previous, current PluginList, installed []string) ManifestDiff {
+	var diff ManifestDiff
+
+	previousPlugins := make(map[string]Plugin)
+	for _, plugin := range previous.Plugins {
+		previousPlugins[plugin.Shortname] = plugin
+	}
+
+	isInstalled := make(map[string]bool)
+	for _, name := range installed {
+		isInstalled[name] = true
+	}
+
+	for _, plugin := range current.Plugins {
+		before, existed := previousPlugins[plugin.Shortname]
+		if !existed {
+			diff.NewPlugins = append(diff.NewPlugins, plugin)
+			continue
+		}
+
+		if !isInstalled[plugin.Shortname] {
+			continue
+		}
+
+		if plugin.Deprecated != "" && before.Deprecated == "" {
+			diff.Deprecated = append(diff.Deprecated, plugin)
+		}
+
+		known := make(map[string]bool)
+		for _, version := range before.availableVersions() {
+			known[version] = true
+		}
+
+		for _, version := range plugin.availableVersions() {
+			if !known[version] {
+				diff.Upgrades = append(diff.Upgrades, PluginUpgrade{
+					Plugin:     plugin.Shortname,
+					Version:    version,
+					Deprecated: plugin.Deprecated,
+				})
+			}
+		}
+	}
+
+	return diff
+}
+
+// availableVersions returns the versions of the plugin that can be installed,
+// oldest first
+func (p Plugin) availableVersions() []string {
+	p.filterCompatibleReleases()
+	allowEmulation := emulationAllowed()
+
+	seen := make(map[string]bool)
+	var versions []string
+
+	for _, release := range p.Releases {
+		if release.runsOn(allowEmulation) && !seen[release.Version] {
+			seen[release.Version] = true
+			versions = append(versions, release.Version)
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		a, _ := parseVersion(versions[i])
+		b, _ := parseVersion(versions[j])
+		return compareVersions(a, b) < 0
+	})
+
+	return versions
+}
+
+// InstalledVersion returns the version of the plugin installed locally, or an
+// empty string if it isn't installed
+func (p *Plugin) InstalledVersion(cfg config.IConfig, fs afero.Fs) string {
+	versionDirs, _ := afero.Glob(fs, filepath.Join(getPluginsDir(cfg), p.Shortname, "*.*.*"))
+
+	var installed string
+	for _, versionDir := range versionDirs {
+		version := filepath.Base(versionDir)
+		current, _ := parseVersion(installed)
+		if parsed, ok := parseVersion(version); ok && (installed == "" || compareVersions(parsed, current) > 0) {
+			installed = version
+		}
+	}
+
+	return installed
+}
+
+// ReleaseNotesURL returns where the release notes of a version of the plugin
+// are published under the plugin base URL
+func ReleaseNotesURL(pluginBaseURL, plugin, version string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", pluginBaseURL, plugin, version, releaseNotesFileName)
+}
+
+// fetchReleaseNotes returns the start of the release notes at url, or an
+// empty string if there are none
+func fetchReleaseNotes(ctx context.Context, url string) string {
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return ""
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 16*1024))
+	if err != nil {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	if len(lines) > maxReleaseNotesLines {
+		lines = append(lines[:maxReleaseNotesLines], "...")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// addReleaseNotes links each upgrade to its release notes, and fetches them
+func (d *ManifestDiff) addReleaseNotes(ctx context.Context, pluginBaseURL string) {
+	for i, upgrade := range d.Upgrades {
+		d.Upgrades[i].ReleaseNotesURL = ReleaseNotesURL(pluginBaseURL, upgrade.Plugin, upgrade.Version)
+		d.Upgrades[i].ReleaseNotes = fetchReleaseNotes(ctx, d.Upgrades[i].ReleaseNotesURL)
+	}
+}
+
+// Print writes the changes to w
+func (d ManifestDiff) Print(w io.Writer) {
+	if d.Empty() {
+		return
+	}
+
+	color := ansi.Color(w)
+
+	fmt.Fprintln(w, color.Bold("The plugin manifest was updated:"))
+
+	for _, plugin := range d.NewPlugins {
+		if plugin.Shortdesc != "" {
+			fmt.Fprintf(w, "  %s new plugin %s: %s\n", color.Green("+"), plugin.Shortname, plugin.Shortdesc)
+		} else {
+			fmt.Fprintf(w, "  %s new plugin %s\n", color.Green("+"), plugin.Shortname)
+		}
+	}
+
+	for _, upgrade := range d.Upgrades {
+		fmt.Fprintf(w, "  %s %s v%s is available, run `stripe plugin upgrade %s`\n", color.Yellow("↑"), upgrade.Plugin, upgrade.Version, upgrade.Plugin)
+
+		if upgrade.ReleaseNotes != "" {
+			for _, line := range strings.Split(upgrade.ReleaseNotes, "\n") {
+				if strings.TrimSpace(line) == "" {
+					fmt.Fprintln(w)
+					continue
+				}
+				fmt.Fprintf(w, "      %s\n", color.Faint(line))
+			}
+		}
+
+		if upgrade.ReleaseNotesURL != "" {
+			fmt.Fprintf(w, "      Release notes: %s\n", upgrade.ReleaseNotesURL)
+		}
+	}
+
+	for _, plugin := range d.Deprecated {
+		fmt.Fprintf(w, "  %s %s is deprecated: %s\n", color.Red("!"), plugin.Shortname, plugin.Deprecated)
+	}
+}
+
+// OutdatedPlugins returns the installed plugins that have a newer version
+// available, linked to its release notes when the plugin base URL can be
+// fetched from baseURL
+func OutdatedPlugins(ctx context.Context, cfg config.IConfig, fs afero.Fs, baseURL string) ([]PluginUpgrade, error) {
+	pluginList, err := GetPluginList(ctx, cfg, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	var pluginBaseURL string
+	if apiKey, err := cfg.GetProfile().GetAPIKey(false); err == nil {
+		if pluginData, err := requests.GetPluginData(ctx, baseURL, stripe.APIVersion, apiKey, cfg.GetProfile()); err == nil {
+			pluginBaseURL = pluginData.PluginBaseURL
+		}
+	}
+
+	var outdated []PluginUpgrade
+
+	for _, name := range cfg.GetInstalledPlugins() {
+		for _, plugin := range pluginList.Plugins {
+			if plugin.Shortname != name {
+				continue
+			}
+
+			plugin.filterCompatibleReleases()
+
+			installed := plugin.InstalledVersion(cfg, fs)
+			latest := plugin.LookUpLatestVersion()
+
+			installedVersion, installedOK := parseVersion(installed)
+			latestVersion, latestOK := parseVersion(latest)
+			if !latestOK || (installedOK && compareVersions(latestVersion, installedVersion) <= 0) {
+				continue
+			}
+
+			upgrade := PluginUpgrade{
+				Plugin:           name,
+				InstalledVersion: installed,
+				Version:          latest,
+				Deprecated:       plugin.Deprecated,
+			}
+			if pluginBaseURL != "" {
+				upgrade.ReleaseNotesURL = ReleaseNotesURL(pluginBaseURL, name, latest)
+			}
+
+			outdated = append(outdated, upgrade)
+		}
+	}
+
+	return outdated, nil
+}
@@ -0,0 +1,122 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeTestManifest(t *testing.T, path string) PluginList {
+	var pluginList PluginList
+	_, err := toml.DecodeFile(path, &pluginList)
+	require.NoError(t, err)
+
+	return pluginList
+}
+
+func TestDiffPluginManifests(t *testing.T) {
+	setCurrentPlatform(t, "linux", "amd64")
+
+	previous := decodeTestManifest(t, "./test_artifacts/plugins.toml")
+	current := decodeTestManifest(t, "./test_artifacts/plugins_updated.toml")
+
+	diff := DiffPluginManifests(previous, current, []string{"appB"})
+	require.Empty(t, diff.NewPlugins)
+	require.Empty(t, diff.Deprecated)
+	require.Equal(t, []PluginUpgrade{{Plugin: "appB", Version: "1.2.2"}}, diff.Upgrades)
+
+	// new releases of plugins that aren't installed aren't reported
+	require.True(t, DiffPluginManifests(previous, current, nil).Empty())
+
+	// nor are the releases that can't run on this platform
+	setCurrentPlatform(t, "linux", "arm64")
+	require.True(t, DiffPluginManifests(previous, current, []string{"appB"}).Empty())
+}
+
+func TestDiffPluginManifests_NewAndDeprecated(t *testing.T) {
+	previous := PluginList{Plugins: []Plugin{{Shortname: "appA"}}}
+	current := PluginList{Plugins: []Plugin{
+		{Shortname: "appA", Deprecated: "use appC instead"},
+		{Shortname: "appC", Shortdesc: "Does things"},
+	}}
+
+	diff := DiffPluginManifests(previous, current, []string{"appA"})
+	require.Len(t, diff.NewPlugins, 1)
+	require.Equal(t, "appC", diff.NewPlugins[0].Shortname)
+	require.Len(t, diff.Deprecated, 1)
+	require.Equal(t, "appA", diff.Deprecated[0].Shortname)
+
+	out := &bytes.Buffer{}
+	diff.Print(out)
+	require.Equal(t, `The plugin manifest was updated:
+  + new plugin appC: Does things
+  ! appA is deprecated: use appC instead
+`, out.String())
+}
+
+func TestRefreshPluginManifest_PrintsDiff(t *testing.T) {
+	setCurrentPlatform(t, "linux", "amd64")
+
+	fs := setUpFS()
+	config := &TestConfig{}
+	config.InitConfig()
+	config.InstalledPlugins = []string{"appB"}
+	updatedManifestContent, _ := os.ReadFile("./test_artifacts/plugins_updated.toml")
+	testServers := setUpServers(t, updatedManifestContent)
+	defer func() { testServers.CloseAll() }()
+
+	out := &bytes.Buffer{}
+	defer func() { manifestDiffOutput = os.Stderr }()
+	manifestDiffOutput = out
+
+	err := RefreshPluginManifest(context.Background(), config, fs, testServers.StripeServer.URL)
+	require.NoError(t, err)
+	require.Equal(t, `The plugin manifest was updated:
+  ↑ appB v1.2.2 is available, run `+"`stripe plugin upgrade appB`"+`
+      # appB 1.2.2
+
+      - Fixed a bug
+      Release notes: `+testServers.ArtifactoryServer.URL+`/appB/1.2.2/RELEASE_NOTES.md
+`, out.String())
+}
+
+func TestOutdatedPlugins(t *testing.T) {
+	setCurrentPlatform(t, "linux", "amd64")
+
+	fs := setUpFS()
+	config := &TestConfig{}
+	config.InitConfig()
+	config.InstalledPlugins = []string{"appA", "appB"}
+	manifestContent, _ := os.ReadFile("./test_artifacts/plugins.toml")
+	testServers := setUpServers(t, manifestContent)
+	defer func() { testServers.CloseAll() }()
+
+	require.NoError(t, fs.MkdirAll("/plugins/appA/1.0.1", os.ModePerm))
+	require.NoError(t, fs.MkdirAll("/plugins/appB/1.2.1", os.ModePerm))
+
+	outdated, err := OutdatedPlugins(context.Background(), config, fs, testServers.StripeServer.URL)
+	require.NoError(t, err)
+	require.Equal(t, []PluginUpgrade{{
+		Plugin:           "appA",
+		InstalledVersion: "1.0.1",
+		Version:          "2.0.1",
+		ReleaseNotesURL:  testServers.ArtifactoryServer.URL + "/appA/2.0.1/RELEASE_NOTES.md",
+	}}, outdated)
+}
+
+func TestInstalledVersion(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	config := &TestConfig{}
+	plugin := Plugin{Shortname: "appA"}
+
+	require.Equal(t, "", plugin.InstalledVersion(config, fs))
+
+	require.NoError(t, fs.MkdirAll("/plugins/appA/1.10.0", os.ModePerm))
+	require.NoError(t, fs.MkdirAll("/plugins/appA/1.9.0", os.ModePerm))
+	require.Equal(t, "1.10.0", plugin.InstalledVersion(config, fs))
+}
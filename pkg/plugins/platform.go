@@ -0,0 +1,142 @@
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// AllowEmulation allows installing the amd64 build of a plugin on arm64
+// machines able to emulate it when there is no arm64 build, as is also allowed
+// by setting the STRIPE_PLUGINS_ALLOW_EMULATION environment variable
+var AllowEmulation = false
+
+// Platform is an OS and architecture plugins are built for
+type Platform struct {
+	OS   string
+	Arch string
+}
+
+func (p Platform) String() string {
+	return p.OS + "/" + p.Arch
+}
+
+// currentPlatform is the platform the CLI runs on
+var currentPlatform = Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+
+// emulatedPlatforms maps platforms onto the one they can run binaries of
+// through emulation: Rosetta 2 on Apple Silicon, and the x64 emulation of
+// Windows on ARM
+var emulatedPlatforms = map[Platform]Platform{
+	{OS: "darwin", Arch: "arm64"}:  {OS: "darwin", Arch: "amd64"},
+	{OS: "windows", Arch: "arm64"}: {OS: "windows", Arch: "amd64"},
+}
+
+// NoMatchingReleaseError is returned when a version of a plugin has no build
+// this platform can run
+type NoMatchingReleaseError struct {
+	Plugin    string
+	Version   string
+	Platform  Platform
+	Available []Platform
+	// Emulated is set when a build could run through emulation if it was
+	// allowed
+	Emulated *Platform
+}
+
+func (e NoMatchingReleaseError) Error() string {
+	available := make([]string, 0, len(e.Available))
+	for _, platform := range e.Available {
+		available = append(available, platform.String())
+	}
+
+	msg := fmt.Sprintf("plugin '%s' v%s is not available for %s, only for: %s", e.Plugin, e.Version, e.Platform, strings.Join(available, ", "))
+	if e.Emulated != nil {
+		msg += fmt.Sprintf(". To run the %s build through emulation, pass --allow-emulation or set STRIPE_PLUGINS_ALLOW_EMULATION=1", e.Emulated)
+	}
+
+	return msg
+}
+
+// emulationAllowed returns whether builds for an emulated platform may be
+// installed
+func emulationAllowed() bool {
+	if AllowEmulation {
+		return true
+	}
+
+	switch strings.ToLower(os.Getenv("STRIPE_PLUGINS_ALLOW_EMULATION")) {
+	case "1", "true":
+		return true
+	default:
+		return false
+	}
+}
+
+// runsOn returns whether the release can run on the current platform, either
+// natively or, if allowEmulation is set, through emulation
+func (r Release) runsOn(allowEmulation bool) bool {
+	platform := Platform{OS: r.OS, Arch: r.Arch}
+	if platform == currentPlatform {
+		return true
+	}
+
+	emulated, ok := emulatedPlatforms[currentPlatform]
+
+	return ok && allowEmulation && platform == emulated
+}
+
+// resolveRelease returns the build of the version to install and run on the
+// current platform: the one built for it or, if allowEmulation is set, one it
+// can emulate. It returns nil when the plugin has no such version at all.
+func (p *Plugin) resolveRelease(version string, allowEmulation bool) (*Release, error) {
+	var native, emulated *Release
+	var available []Platform
+
+	emulatedPlatform, canEmulate := emulatedPlatforms[currentPlatform]
+
+	for i, release := range p.Releases {
+		if release.Version != version {
+			continue
+		}
+
+		platform := Platform{OS: release.OS, Arch: release.Arch}
+		available = append(available, platform)
+
+		switch {
+		case platform == currentPlatform:
+			native = &p.Releases[i]
+		case canEmulate && platform == emulatedPlatform:
+			emulated = &p.Releases[i]
+		}
+	}
+
+	switch {
+	case native != nil:
+		return native, nil
+	case emulated != nil && allowEmulation:
+		return emulated, nil
+	case len(available) == 0:
+		return nil, nil
+	}
+
+	sort.Slice(available, func(i, j int) bool {
+		return available[i].String() < available[j].String()
+	})
+
+	err := NoMatchingReleaseError{
+		Plugin:    p.Shortname,
+		Version:   version,
+		Platform:  currentPlatform,
+		Available: available,
+	}
+	if emulated != nil {
+		err.Emulated = &emulatedPlatform
+	}
+
+	return nil, clierrors.Wrap(clierrors.PluginNoMatchingRelease, err)
+}
@@ -0,0 +1,117 @@
+package plugins
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+func setCurrentPlatform(t *testing.T, os, arch string) {
+	previous := currentPlatform
+	currentPlatform = Platform{OS: os, Arch: arch}
+	t.Cleanup(func() { currentPlatform = previous })
+}
+
+func testPlatformPlugin() *Plugin {
+	return &Plugin{
+		Shortname: "appA",
+		Releases: []Release{
+			{OS: "darwin", Arch: "amd64", Version: "1.0.0", Sum: "a"},
+			{OS: "darwin", Arch: "arm64", Version: "1.0.0", Sum: "b"},
+			{OS: "linux", Arch: "amd64", Version: "1.0.0", Sum: "c"},
+			{OS: "linux", Arch: "arm64", Version: "1.0.0", Sum: "d"},
+			{OS: "windows", Arch: "amd64", Version: "1.0.0", Sum: "e"},
+			{OS: "darwin", Arch: "amd64", Version: "2.0.0", Sum: "f"},
+			{OS: "linux", Arch: "amd64", Version: "2.0.0", Sum: "g"},
+			{OS: "windows", Arch: "amd64", Version: "2.0.0", Sum: "h"},
+		},
+	}
+}
+
+func TestResolveReleaseNative(t *testing.T) {
+	setCurrentPlatform(t, "linux", "arm64")
+
+	release, err := testPlatformPlugin().resolveRelease("1.0.0", false)
+	require.NoError(t, err)
+	require.Equal(t, "d", release.Sum)
+}
+
+func TestResolveReleaseEmulation(t *testing.T) {
+	setCurrentPlatform(t, "darwin", "arm64")
+	plugin := testPlatformPlugin()
+
+	// the native build is preferred even if emulation is allowed
+	release, err := plugin.resolveRelease("1.0.0", true)
+	require.NoError(t, err)
+	require.Equal(t, "b", release.Sum)
+
+	_, err = plugin.resolveRelease("2.0.0", false)
+	require.EqualError(t, err, "plugin 'appA' v2.0.0 is not available for darwin/arm64, only for: darwin/amd64, linux/amd64, windows/amd64. To run the darwin/amd64 build through emulation, pass --allow-emulation or set STRIPE_PLUGINS_ALLOW_EMULATION=1")
+
+	release, err = plugin.resolveRelease("2.0.0", true)
+	require.NoError(t, err)
+	require.Equal(t, "f", release.Sum)
+}
+
+func TestResolveReleaseNoMatch(t *testing.T) {
+	setCurrentPlatform(t, "linux", "arm64")
+	plugin := testPlatformPlugin()
+
+	// linux has no emulation to fall back to
+	_, err := plugin.resolveRelease("2.0.0", true)
+	require.EqualError(t, err, "plugin 'appA' v2.0.0 is not available for linux/arm64, only for: darwin/amd64, linux/amd64, windows/amd64")
+	require.ErrorAs(t, err, &NoMatchingReleaseError{})
+
+	code, _ := clierrors.CodeOf(err)
+	require.Equal(t, clierrors.PluginNoMatchingRelease, code)
+
+	release, err := plugin.resolveRelease("3.0.0", true)
+	require.NoError(t, err)
+	require.Nil(t, release)
+}
+
+func TestLookUpLatestVersionWithEmulation(t *testing.T) {
+	setCurrentPlatform(t, "windows", "arm64")
+	plugin := testPlatformPlugin()
+
+	require.Equal(t, "", plugin.LookUpLatestVersion())
+
+	t.Setenv("STRIPE_PLUGINS_ALLOW_EMULATION", "1")
+	require.Equal(t, "2.0.0", plugin.LookUpLatestVersion())
+}
+
+func TestVerifyChecksumOfEmulatedBuild(t *testing.T) {
+	setCurrentPlatform(t, "darwin", "arm64")
+
+	nativeSum := sha256.Sum256([]byte("native"))
+	emulatedSum := sha256.Sum256([]byte("emulated"))
+	plugin := &Plugin{
+		Shortname: "appA",
+		Releases: []Release{
+			{OS: "darwin", Arch: "amd64", Version: "1.0.0", Sum: hex.EncodeToString(emulatedSum[:])},
+			{OS: "darwin", Arch: "arm64", Version: "1.0.0", Sum: hex.EncodeToString(nativeSum[:])},
+			{OS: "linux", Arch: "arm64", Version: "1.0.0", Sum: hex.EncodeToString(sha256.New().Sum(nil))},
+		},
+	}
+
+	sums, err := plugin.getChecksums("1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{nativeSum[:], emulatedSum[:]}, sums)
+
+	// the amd64 build installed before the native one was released still
+	// verifies, against its own checksum
+	sum, err := plugin.matchChecksum(bytes.NewReader([]byte("emulated")), "1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, emulatedSum[:], sum)
+	require.NoError(t, plugin.verifyChecksum(bytes.NewReader([]byte("native")), "1.0.0"))
+
+	sum, err = plugin.matchChecksum(bytes.NewReader([]byte("tampered")), "1.0.0")
+	require.NoError(t, err)
+	require.Nil(t, sum)
+	require.EqualError(t, plugin.verifyChecksum(bytes.NewReader([]byte("tampered")), "1.0.0"), "installed plugin 'appA' could not be verified, aborting installation")
+}
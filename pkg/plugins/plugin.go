@@ -5,17 +5,18 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
+	"strings"
 	"time"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/redact"
 	"github.com/stripe/stripe-cli/pkg/requests"
 	"github.com/stripe/stripe-cli/pkg/stripe"
 
@@ -38,6 +39,18 @@ type Plugin struct {
 	Binary           string    `toml:"Binary"`
 	Releases         []Release `toml:"Release"`
 	MagicCookieValue string    `toml:"MagicCookieValue"`
+	Triggers         []string  `toml:"Triggers"`
+	StepTypes        []string  `toml:"StepTypes"`
+	// Aliases are the commands the plugin adds to the CLI, by name, as the
+	// command line they stand for, e.g. "trigger payment_intent.succeeded"
+	Aliases map[string]string `toml:"Aliases,omitempty"`
+	// Deprecated explains why the plugin shouldn't be used anymore, and what
+	// to use instead
+	Deprecated string `toml:"Deprecated,omitempty"`
+
+	// incompatibleReleases maps the versions dropped by LookUpPlugin onto the
+	// error explaining why they cannot be run by this CLI
+	incompatibleReleases map[string]IncompatiblePluginError
 }
 
 // PluginList contains a list of plugins
@@ -52,22 +65,26 @@ type Release struct {
 	Version   string `toml:"Version"`
 	Sum       string `toml:"Sum"`
 	Unmanaged bool   `toml:"Unmanaged"`
+
+	// ProtocolVersion is the plugin RPC protocol the release was built against
+	ProtocolVersion int `toml:"ProtocolVersion,omitempty"`
+	// MinCLIVersion and MaxCLIVersion bound the range of CLI versions able to
+	// run the release, MaxCLIVersion being exclusive
+	MinCLIVersion string `toml:"MinCLIVersion,omitempty"`
+	MaxCLIVersion string `toml:"MaxCLIVersion,omitempty"`
 }
 
 // getPluginInterface computes the correct metadata needed for starting the hcplugin client
 func (p *Plugin) getPluginInterface() (hcplugin.HandshakeConfig, map[int]hcplugin.PluginSet) {
 	handshakeConfig := hcplugin.HandshakeConfig{
+		ProtocolVersion:  ProtocolVersion,
 		MagicCookieKey:   fmt.Sprintf("plugin_%s", p.Shortname),
 		MagicCookieValue: p.MagicCookieValue,
 	}
 
-	// pluginMap is the map of interfaces we can dispense from the plugin itself
-	// we just have one called "main" for each of our plugins for now
-	pluginSetMap := map[int]hcplugin.PluginSet{
-		1: {
-			"main": &CLIPluginV1{},
-		},
-	}
+	// pluginSetMap is the map of interfaces we can dispense from the plugin itself
+	// for each protocol version. We just have one called "main" for each of our plugins for now
+	pluginSetMap := pluginSets()
 
 	return handshakeConfig, pluginSetMap
 }
@@ -115,39 +132,72 @@ func (p *Plugin) cleanUpPluginPath(config config.IConfig, fs afero.Fs, versionTo
 	return nil
 }
 
-// getChecksum does what it says on the tin - it returns the checksum for a specific plugin version
-func (p *Plugin) getChecksum(version string) ([]byte, error) {
-	opsystem := runtime.GOOS
-	arch := runtime.GOARCH
+// getChecksums returns the checksums of the builds of a plugin version the current platform can run,
+// the native one first. Builds for an emulated platform are included whether emulation is allowed or not,
+// as the one installed may have been installed when it was, before a native build was released.
+func (p *Plugin) getChecksums(version string) ([][]byte, error) {
+	release, err := p.resolveRelease(version, true)
+	if err != nil {
+		return nil, err
+	}
 
-	var expectedSum string
-	for _, pkg := range p.Releases {
-		if pkg.OS == opsystem && pkg.Arch == arch && pkg.Version == version {
-			expectedSum = pkg.Sum
+	if release == nil || release.Sum == "" {
+		return nil, clierrors.Errorf(clierrors.PluginNotVerified, "Could not locate a valid checksum for %s version %s", p.Shortname, version)
+	}
+
+	var sums [][]byte
+	for _, r := range p.Releases {
+		if r.Version != version || !r.runsOn(true) || r.Sum == "" {
+			continue
+		}
+
+		decoded, err := hex.DecodeString(r.Sum)
+		if err != nil {
+			return nil, clierrors.Errorf(clierrors.PluginNotVerified, "Could not decode checksum for %s version %s", p.Shortname, version)
+		}
+
+		if r.OS == currentPlatform.OS && r.Arch == currentPlatform.Arch {
+			sums = append([][]byte{decoded}, sums...)
+		} else {
+			sums = append(sums, decoded)
 		}
 	}
 
-	if expectedSum == "" {
-		return nil, fmt.Errorf("Could not locate a valid checksum for %s version %s", p.Shortname, version)
+	return sums, nil
+}
+
+// matchChecksum returns the checksum of the build of a plugin version the binary is, among the ones the
+// current platform can run, or nil if it's none of them
+func (p *Plugin) matchChecksum(binary io.Reader, version string) ([]byte, error) {
+	expectedSums, err := p.getChecksums(version)
+	if err != nil {
+		return nil, err
 	}
 
-	decoded, err := hex.DecodeString(expectedSum)
+	hash := sha256.New()
+	_, err = io.Copy(hash, binary)
 	if err != nil {
-		return nil, fmt.Errorf("Could not decode checksum for %s version %s", p.Shortname, version)
+		return nil, err
 	}
 
-	return decoded, nil
+	actualSum := hash.Sum(nil)
+	for _, expectedSum := range expectedSums {
+		if bytes.Equal(actualSum, expectedSum) {
+			return expectedSum, nil
+		}
+	}
+
+	return nil, nil
 }
 
 // LookUpLatestVersion gets latest CLI version
 // note: assumes versions are listed in asc order
 func (p *Plugin) LookUpLatestVersion() string {
-	opsystem := runtime.GOOS
-	arch := runtime.GOARCH
+	allowEmulation := emulationAllowed()
 
 	var version string
 	for _, pkg := range p.Releases {
-		if pkg.OS == opsystem && pkg.Arch == arch {
+		if pkg.runsOn(allowEmulation) {
 			version = pkg.Version
 		}
 	}
@@ -157,6 +207,23 @@ func (p *Plugin) LookUpLatestVersion() string {
 
 // Install installs the plugin of the given version
 func (p *Plugin) Install(ctx context.Context, cfg config.IConfig, fs afero.Fs, version string, baseURL string) error {
+	if err := p.checkCompatibility(version); err != nil {
+		return err
+	}
+
+	// no version is found when looking up the latest one for this platform
+	if version == "" {
+		return clierrors.Errorf(clierrors.PluginNoMatchingRelease, "plugin '%s' has no release for %s", p.Shortname, currentPlatform)
+	}
+
+	platform := currentPlatform
+	release, err := p.resolveRelease(version, emulationAllowed())
+	if err != nil {
+		return err
+	} else if release != nil {
+		platform = Platform{OS: release.OS, Arch: release.Arch}
+	}
+
 	spinner := ansi.StartNewSpinner(ansi.Faint(fmt.Sprintf("installing '%s' v%s...", p.Shortname, version)), os.Stdout)
 
 	apiKey, err := cfg.GetProfile().GetAPIKey(false)
@@ -175,13 +242,13 @@ func (p *Plugin) Install(ctx context.Context, cfg config.IConfig, fs afero.Fs, v
 			"prefix": "plugins.plugin.Install",
 		}).Debugf("install error: %s", err)
 
-		return errors.New("you don't seem to have access to this plugin")
+		return clierrors.New(clierrors.PluginAccessDenied, "you don't seem to have access to this plugin")
 	}
 
-	pluginDownloadURL := fmt.Sprintf("%s/%s/%s/%s/%s/%s", pluginData.PluginBaseURL, p.Shortname, version, runtime.GOOS, runtime.GOARCH, p.Binary)
+	pluginDownloadURL := fmt.Sprintf("%s/%s/%s/%s/%s/%s", pluginData.PluginBaseURL, p.Shortname, version, platform.OS, platform.Arch, p.Binary)
 
 	// Pull down bin, verify, and save to disk
-	err = p.downloadAndSavePlugin(cfg, pluginDownloadURL, fs, version)
+	err = p.downloadAndSavePlugin(ctx, cfg, pluginDownloadURL, fs, version)
 
 	if err != nil {
 		ansi.StopSpinner(spinner, ansi.Faint(fmt.Sprintf("could not install plugin '%s': %s", p.Shortname, err)), os.Stdout)
@@ -230,7 +297,7 @@ func (p *Plugin) Uninstall(ctx context.Context, config config.IConfig, fs afero.
 	}
 
 	if pluginIdx == -1 {
-		return errors.New("this plugin doesn't seem to be installed, canceling")
+		return clierrors.New(clierrors.PluginNotFound, "this plugin doesn't seem to be installed, canceling")
 	}
 
 	pluginDir := p.getPluginInstallPath(config, "")
@@ -250,8 +317,8 @@ func (p *Plugin) Uninstall(ctx context.Context, config config.IConfig, fs afero.
 	return nil
 }
 
-func (p *Plugin) downloadAndSavePlugin(config config.IConfig, pluginDownloadURL string, fs afero.Fs, version string) error {
-	body, err := FetchRemoteResource(pluginDownloadURL)
+func (p *Plugin) downloadAndSavePlugin(ctx context.Context, config config.IConfig, pluginDownloadURL string, fs afero.Fs, version string) error {
+	body, err := FetchRemoteResource(ctx, pluginDownloadURL)
 	if err != nil {
 		return err
 	}
@@ -283,47 +350,122 @@ func (p *Plugin) verifychecksumAndSavePlugin(pluginData []byte, config config.IC
 		return err
 	}
 
+	// the plugin is written to a staging directory first, so that an
+	// interrupted install never leaves a partial binary where it's run from
+	stagingDir, err := p.makeStagingDir(config, fs)
+	if err != nil {
+		logger.Debug("could not create staging directory")
+		return err
+	}
+	defer fs.RemoveAll(stagingDir)
+
+	stagedFilePath := filepath.Join(stagingDir, filepath.Base(pluginFilePath))
+
+	err = afero.WriteFile(fs, stagedFilePath, pluginData, 0755)
+	if err != nil {
+		logger.Debug("could not save plugin to disk")
+		return err
+	}
+
 	err = fs.MkdirAll(pluginDir, 0755)
 	if err != nil {
 		logger.Debugf("could not create plugin directory: %s", pluginDir)
 		return err
 	}
 
-	err = afero.WriteFile(fs, pluginFilePath, pluginData, 0755)
+	err = fs.Rename(stagedFilePath, pluginFilePath)
 	if err != nil {
-		logger.Debug("could not save plugin to disk")
+		logger.Debug("could not move plugin into place")
 		return err
 	}
 
 	return nil
 }
 
+// makeStagingDir creates a temporary directory next to the installed versions
+// of the plugin, on the same filesystem so that files are moved out of it
+// atomically. Leftovers are removed by cleanUpPluginPath.
+func (p *Plugin) makeStagingDir(config config.IConfig, fs afero.Fs) (string, error) {
+	pluginPath := filepath.Join(getPluginsDir(config), p.Shortname)
+
+	err := fs.MkdirAll(pluginPath, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	return afero.TempDir(fs, pluginPath, ".staging-")
+}
+
 // verifyChecksum is to be used during installation only
 // hcplugins takes care of the boot time verification for us
 func (p *Plugin) verifyChecksum(binary io.Reader, version string) error {
-	expectedSum, err := p.getChecksum(version)
+	sum, err := p.matchChecksum(binary, version)
 	if err != nil {
 		return err
 	}
 
-	hash := sha256.New()
-	_, err = io.Copy(hash, binary)
+	if sum == nil {
+		return clierrors.Errorf(clierrors.PluginNotVerified, "installed plugin '%s' could not be verified, aborting installation", p.Shortname)
+	}
+
+	return nil
+}
+
+// Run boots up the binary and then sends the command to it via RPC
+func (p *Plugin) Run(ctx context.Context, config *config.Config, fs afero.Fs, args []string) error {
+	raw, err := p.dispense(ctx, config, fs)
 	if err != nil {
 		return err
 	}
 
-	actualSum := hash.Sum(nil)
-	if !bytes.Equal(actualSum, expectedSum) {
-		return fmt.Errorf("installed plugin '%s' could not be verified, aborting installation", p.Shortname)
+	// get the native golang interface for the plugin so that we can call it directly
+	dispatcher := raw.(Dispatcher)
+
+	// run the command that the user specified via args
+	_, err = dispatcher.RunCommand(args)
+
+	if err != nil {
+		return err
 	}
 
 	return nil
 }
 
-// Run boots up the binary and then sends the command to it via RPC
-func (p *Plugin) Run(ctx context.Context, config *config.Config, fs afero.Fs, args []string) error {
+// GetTriggerFixture boots up the binary and asks it for the fixture backing one of its triggers
+func (p *Plugin) GetTriggerFixture(ctx context.Context, config *config.Config, fs afero.Fs, event string) ([]byte, error) {
+	raw, err := p.dispense(ctx, config, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	fixture, err := raw.(FixtureProvider).GetTriggerFixture(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(fixture), nil
+}
+
+// RunFixtureStep boots up the binary and hands off one of its custom fixture steps to it
+func (p *Plugin) RunFixtureStep(ctx context.Context, config *config.Config, fs afero.Fs, step FixtureStep) ([]byte, error) {
+	raw, err := p.dispense(ctx, config, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := raw.(FixtureProvider).RunFixtureStep(step)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(resp), nil
+}
+
+// dispense boots up the binary, installing it first if needed, and returns
+// the plugin's main interface
+func (p *Plugin) dispense(ctx context.Context, config *config.Config, fs afero.Fs) (interface{}, error) {
 	logger := log.WithFields(log.Fields{
-		"prefix": "plugins.plugin.Run",
+		"prefix": "plugins.plugin.dispense",
 	})
 
 	var version string
@@ -335,7 +477,7 @@ func (p *Plugin) Run(ctx context.Context, config *config.Config, fs afero.Fs, ar
 		localPluginDir := filepath.Join(getPluginsDir(config), p.Shortname, "*.*.*")
 		existingLocalPlugin, err := filepath.Glob(localPluginDir)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		// if plugin is not installed locally, then we should install it first
@@ -343,13 +485,17 @@ func (p *Plugin) Run(ctx context.Context, config *config.Config, fs afero.Fs, ar
 			version = p.LookUpLatestVersion()
 			err := p.Install(ctx, config, fs, version, stripe.DefaultAPIBaseURL)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		} else {
 			version = filepath.Base(existingLocalPlugin[0])
 		}
 	}
 
+	if err := p.checkCompatibility(version); err != nil {
+		return nil, err
+	}
+
 	pluginDir := p.getPluginInstallPath(config, version)
 	pluginBinaryPath := filepath.Join(pluginDir, p.Binary)
 	pluginBinaryPath += GetBinaryExtension()
@@ -360,8 +506,9 @@ func (p *Plugin) Run(ctx context.Context, config *config.Config, fs afero.Fs, ar
 	timeout, _ := time.ParseDuration("10s")
 
 	pluginLogger := hclog.New(&hclog.LoggerOptions{
-		Name:  fmt.Sprintf("plugin.child.%s", p.Shortname),
-		Level: hclog.LevelFromString("ERROR"),
+		Name:   fmt.Sprintf("plugin.child.%s", p.Shortname),
+		Level:  hclog.LevelFromString("ERROR"),
+		Output: &redact.Writer{Out: os.Stderr},
 	})
 
 	clientConfig := &hcplugin.ClientConfig{
@@ -375,9 +522,18 @@ func (p *Plugin) Run(ctx context.Context, config *config.Config, fs afero.Fs, ar
 		StartTimeout:     timeout,
 	}
 
-	sum, err := p.getChecksum(version)
+	// hcplugin verifies the binary against the checksum of the build it is,
+	// which isn't the native one if it was installed through emulation
+	binary, err := fs.Open(pluginBinaryPath)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	sum, err := p.matchChecksum(binary, version)
+	binary.Close()
+	if err != nil {
+		return nil, err
+	} else if sum == nil {
+		return nil, clierrors.Errorf(clierrors.PluginNotVerified, "installed plugin '%s' could not be verified, run `stripe plugin install %s` to reinstall it", p.Shortname, p.Shortname)
 	}
 
 	clientConfig.SecureConfig = &hcplugin.SecureConfig{
@@ -392,25 +548,21 @@ func (p *Plugin) Run(ctx context.Context, config *config.Config, fs afero.Fs, ar
 	rpcClient, err := client.Client()
 	if err != nil {
 		logger.Debugf("Could not connect to plugin: %s", err)
-		return err
+
+		// the handshake failed to negotiate a protocol version both sides support
+		if strings.Contains(err.Error(), "Incompatible API version") {
+			return nil, clierrors.Wrap(clierrors.PluginIncompatible, IncompatiblePluginError{Plugin: p.Shortname, Version: version, Reason: "unsupported plugin protocol version"})
+		}
+
+		return nil, err
 	}
 
 	// Request the plugin's main interface
 	raw, err := rpcClient.Dispense("main")
 	if err != nil {
 		logger.Debugf("Could not dispense plugin interface: %s", err)
-		return err
+		return nil, err
 	}
 
-	// get the native golang interface for the plugin so that we can call it directly
-	dispatcher := raw.(Dispatcher)
-
-	// run the command that the user specified via args
-	_, err = dispatcher.RunCommand(args)
-
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return raw, nil
 }
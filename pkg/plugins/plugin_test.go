@@ -133,6 +133,28 @@ func TestInstallDoesNotCleanIfInstallFails(t *testing.T) {
 	require.True(t, fileExists, "Did not expect the original version of the plugin to be deleted.")
 }
 
+func TestInstallLeavesNoStagingFiles(t *testing.T) {
+	fs := setUpFS()
+	config := &TestConfig{}
+	config.InitConfig()
+	manifestContent, _ := os.ReadFile("./test_artifacts/plugins.toml")
+	testServers := setUpServers(t, manifestContent)
+
+	// a successful install leaves no staging directory behind
+	plugin, _ := LookUpPlugin(context.Background(), config, fs, "appA")
+	err := plugin.Install(context.Background(), config, fs, "2.0.1", testServers.StripeServer.URL)
+	require.Nil(t, err)
+
+	// nor does a failed one
+	err = plugin.Install(context.Background(), config, fs, "0.0.0", testServers.StripeServer.URL)
+	require.Error(t, err)
+
+	entries, err := afero.ReadDir(fs, "/plugins/appA")
+	require.Nil(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "2.0.1", entries[0].Name())
+}
+
 func TestUninstall(t *testing.T) {
 	fs := setUpFS()
 	config := &TestConfig{}
@@ -0,0 +1,194 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/requests"
+	"github.com/stripe/stripe-cli/pkg/stripe"
+)
+
+// metadataFileName is the file next to the plugin manifest describing the
+// plugins beyond what the manifest says
+const metadataFileName = "metadata.json"
+
+// PluginMetadata is what the metadata endpoint knows about a plugin
+type PluginMetadata struct {
+	Maintainers []string `json:"maintainers"`
+	Downloads   int64    `json:"downloads"`
+	Keywords    []string `json:"keywords"`
+	Homepage    string   `json:"homepage"`
+}
+
+// metadataDocument is the body of the metadata endpoint
+type metadataDocument struct {
+	Plugins map[string]PluginMetadata `json:"plugins"`
+}
+
+// SearchResult is a plugin matching a search
+type SearchResult struct {
+	Plugin           string
+	Description      string
+	Version          string
+	InstalledVersion string
+	Deprecated       string
+	// Metadata is nil when the metadata endpoint doesn't know the plugin
+	Metadata *PluginMetadata
+}
+
+// InstallCommand returns the command line installing the plugin
+func (r SearchResult) InstallCommand() string {
+	return fmt.Sprintf("stripe plugin install %s", r.Plugin)
+}
+
+// MetadataURL returns where the metadata of the plugins is published under the
+// plugin base URL
+func MetadataURL(pluginBaseURL string) string {
+	return fmt.Sprintf("%s/%s", pluginBaseURL, metadataFileName)
+}
+
+// SearchPlugins returns the plugins of the manifest matching every word of
+// query, in their name, description, keywords or maintainers. An empty query
+// matches all the plugins. The plugins are enriched with the metadata at
+// metadataURL, or the one published with the manifest if it's empty, when
+// it's available. Plugins matching by name come first, then the most
+// downloaded ones.
+func SearchPlugins(ctx context.Context, cfg config.IConfig, fs afero.Fs, baseURL, query, metadataURL string) ([]SearchResult, error) {
+	pluginList, err := GetPluginList(ctx, cfg, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadataURL == "" {
+		if apiKey, err := cfg.GetProfile().GetAPIKey(false); err == nil {
+			if pluginData, err := requests.GetPluginData(ctx, baseURL, stripe.APIVersion, apiKey, cfg.GetProfile()); err == nil {
+				metadataURL = MetadataURL(pluginData.PluginBaseURL)
+			}
+		}
+	}
+
+	var metadata map[string]PluginMetadata
+	if metadataURL != "" {
+		metadata = fetchMetadata(ctx, metadataURL)
+	}
+
+	terms := strings.Fields(strings.ToLower(query))
+
+	var results []SearchResult
+	byName := make(map[string]bool)
+
+	for _, plugin := range pluginList.Plugins {
+		plugin.filterCompatibleReleases()
+
+		result := SearchResult{
+			Plugin:           plugin.Shortname,
+			Description:      plugin.Shortdesc,
+			Version:          plugin.LookUpLatestVersion(),
+			InstalledVersion: plugin.InstalledVersion(cfg, fs),
+			Deprecated:       plugin.Deprecated,
+		}
+
+		if m, ok := metadata[plugin.Shortname]; ok {
+			result.Metadata = &m
+		}
+
+		nameMatch, ok := result.matches(terms)
+		if !ok {
+			continue
+		}
+
+		byName[result.Plugin] = nameMatch
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if byName[a.Plugin] != byName[b.Plugin] {
+			return byName[a.Plugin]
+		}
+		if a.downloads() != b.downloads() {
+			return a.downloads() > b.downloads()
+		}
+		return a.Plugin < b.Plugin
+	})
+
+	return results, nil
+}
+
+// matches returns whether every term is found in the result, and whether any
+// of them is in its name
+func (r SearchResult) matches(terms []string) (bool, bool) {
+	name := strings.ToLower(r.Plugin)
+
+	fields := []string{name, strings.ToLower(r.Description)}
+	if r.Metadata != nil {
+		fields = append(fields, strings.ToLower(strings.Join(r.Metadata.Keywords, " ")))
+		fields = append(fields, strings.ToLower(strings.Join(r.Metadata.Maintainers, " ")))
+	}
+
+	nameMatch := false
+
+	for _, term := range terms {
+		found := false
+		for _, field := range fields {
+			if strings.Contains(field, term) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, false
+		}
+
+		if strings.Contains(name, term) {
+			nameMatch = true
+		}
+	}
+
+	return nameMatch, true
+}
+
+func (r SearchResult) downloads() int64 {
+	if r.Metadata == nil {
+		return 0
+	}
+
+	return r.Metadata.Downloads
+}
+
+// fetchMetadata returns the metadata of the plugins at url by name, or nil if
+// there is none
+func fetchMetadata(ctx context.Context, url string) map[string]PluginMetadata {
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	var document metadataDocument
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1024*1024)).Decode(&document); err != nil {
+		return nil
+	}
+
+	return document.Plugins
+}
@@ -0,0 +1,78 @@
+package plugins
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func searchedPlugins(results []SearchResult) []string {
+	names := []string{}
+	for _, result := range results {
+		names = append(names, result.Plugin)
+	}
+
+	return names
+}
+
+func TestSearchPlugins(t *testing.T) {
+	setCurrentPlatform(t, "linux", "amd64")
+
+	fs := setUpFS()
+	config := &TestConfig{}
+	config.InitConfig()
+	manifestContent, _ := os.ReadFile("./test_artifacts/plugins.toml")
+	testServers := setUpServers(t, manifestContent)
+	defer func() { testServers.CloseAll() }()
+
+	require.NoError(t, fs.MkdirAll("/plugins/appA/1.0.1", os.ModePerm))
+
+	search := func(query string) []SearchResult {
+		results, err := SearchPlugins(context.Background(), config, fs, testServers.StripeServer.URL, query, "")
+		require.NoError(t, err)
+		return results
+	}
+
+	// the most downloaded plugins come first
+	results := search("")
+	require.Equal(t, []string{"appB", "appA"}, searchedPlugins(results))
+	require.Equal(t, SearchResult{
+		Plugin:           "appA",
+		Version:          "2.0.1",
+		InstalledVersion: "1.0.1",
+		Metadata:         &PluginMetadata{Maintainers: []string{"Stripe"}, Downloads: 1200, Keywords: []string{"tax", "reports"}},
+	}, results[1])
+	require.Equal(t, "stripe plugin install appA", results[1].InstallCommand())
+
+	require.Equal(t, []string{"appA"}, searchedPlugins(search("TAX")))
+	require.Equal(t, []string{"appB"}, searchedPlugins(search("acme")))
+	require.Equal(t, []string{"appA"}, searchedPlugins(search("stripe reports")))
+	require.Equal(t, []string{}, searchedPlugins(search("stripe acme")))
+
+	// matching the name ranks before matching the rest, even with fewer
+	// downloads
+	require.Equal(t, []string{"appA", "appB"}, searchedPlugins(search("appa")))
+}
+
+func TestSearchPluginsWithoutMetadata(t *testing.T) {
+	setCurrentPlatform(t, "linux", "amd64")
+
+	fs := setUpFS()
+	config := &TestConfig{}
+	config.InitConfig()
+	manifestContent, _ := os.ReadFile("./test_artifacts/plugins.toml")
+	testServers := setUpServers(t, manifestContent)
+	defer func() { testServers.CloseAll() }()
+
+	results, err := SearchPlugins(context.Background(), config, fs, testServers.StripeServer.URL, "app", "http://127.0.0.1:1/metadata.json")
+	require.NoError(t, err)
+	require.Equal(t, []string{"appA", "appB"}, searchedPlugins(results))
+	require.Nil(t, results[0].Metadata)
+
+	// keywords are only known from the metadata
+	results, err = SearchPlugins(context.Background(), config, fs, testServers.StripeServer.URL, "tax", "http://127.0.0.1:1/metadata.json")
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
@@ -69,6 +69,13 @@ func setUpServers(t *testing.T, manifestContent []byte) TestServers {
 		switch url := req.URL.String(); {
 		case url == "/plugins.toml":
 			res.Write(manifestContent)
+		case url == "/metadata.json":
+			res.Write([]byte(`{"plugins": {
+				"appA": {"maintainers": ["Stripe"], "downloads": 1200, "keywords": ["tax", "reports"]},
+				"appB": {"maintainers": ["Acme"], "downloads": 5000, "keywords": ["appA companion"], "homepage": "https://example.com/appB"}
+			}}`))
+		case url == "/appB/1.2.2/RELEASE_NOTES.md":
+			res.Write([]byte("# appB 1.2.2\n\n- Fixed a bug"))
 		case strings.Contains(url, "/appA/2.0.1"):
 			res.Write([]byte("hello, I am appA_2.0.1"))
 		case strings.Contains(url, "/appA/1.0.1"):
@@ -7,8 +7,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"net/http"
-	"net/http/httptrace"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -23,11 +21,16 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
 	"github.com/stripe/stripe-cli/pkg/requests"
 	"github.com/stripe/stripe-cli/pkg/stripe"
 )
 
+// manifestDiffOutput is where the changes to the plugin manifest are reported
+// when it's refreshed
+var manifestDiffOutput io.Writer = os.Stderr
+
 // GetBinaryExtension returns the appropriate file extension for plugin binary
 func GetBinaryExtension() string {
 	if runtime.GOOS == "windows" {
@@ -94,11 +97,13 @@ func LookUpPlugin(ctx context.Context, config config.IConfig, fs afero.Fs, plugi
 
 	for _, p := range pluginList.Plugins {
 		if pluginName == p.Shortname {
+			// only offer the releases that this version of the CLI is able to run
+			p.filterCompatibleReleases()
 			return p, nil
 		}
 	}
 
-	return plugin, fmt.Errorf("Could not find a plugin named %s", pluginName)
+	return plugin, clierrors.Errorf(clierrors.PluginNotFound, "Could not find a plugin named %s", pluginName)
 }
 
 // RefreshPluginManifest refreshes the plugin manifest
@@ -114,7 +119,7 @@ func RefreshPluginManifest(ctx context.Context, config config.IConfig, fs afero.
 	}
 
 	pluginManifestURL := fmt.Sprintf("%s/%s", pluginData.PluginBaseURL, "plugins.toml")
-	body, err := FetchRemoteResource(pluginManifestURL)
+	body, err := FetchRemoteResource(ctx, pluginManifestURL)
 	if err != nil {
 		return err
 	}
@@ -122,12 +127,25 @@ func RefreshPluginManifest(ctx context.Context, config config.IConfig, fs afero.
 	configPath := config.GetConfigFolder(os.Getenv("XDG_CONFIG_HOME"))
 	pluginManifestPath := filepath.Join(configPath, "plugins.toml")
 
+	previous, previousErr := afero.ReadFile(fs, pluginManifestPath)
+
 	err = afero.WriteFile(fs, pluginManifestPath, body, 0644)
 
 	if err != nil {
 		return err
 	}
 
+	// there's nothing to compare to the first time the manifest is downloaded
+	if previousErr == nil {
+		var previousList, currentList PluginList
+		toml.Decode(string(previous), &previousList)
+		if _, err := toml.Decode(string(body), &currentList); err == nil {
+			diff := DiffPluginManifests(previousList, currentList, config.GetInstalledPlugins())
+			diff.addReleaseNotes(ctx, pluginData.PluginBaseURL)
+			diff.Print(manifestDiffOutput)
+		}
+	}
+
 	return nil
 }
 
@@ -203,42 +221,6 @@ func AddEntryToPluginManifest(ctx context.Context, config config.IConfig, fs afe
 	return nil
 }
 
-// FetchRemoteResource returns the remote resource body
-func FetchRemoteResource(url string) ([]byte, error) {
-	t := &requests.TracedTransport{}
-
-	req, err := http.NewRequest("GET", url, nil)
-
-	if err != nil {
-		return nil, err
-	}
-
-	trace := &httptrace.ClientTrace{
-		GotConn: t.GotConn,
-		DNSDone: t.DNSDone,
-	}
-
-	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
-
-	client := &http.Client{Transport: t}
-
-	resp, err := client.Do(req)
-
-	if err != nil {
-		return nil, err
-	}
-
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return body, nil
-}
-
 // ExtractStdoutArchive extracts the archive from stdout
 func ExtractStdoutArchive(ctx context.Context, config config.IConfig) error {
 	gzf, err := gzip.NewReader(os.Stdin)
@@ -285,27 +267,12 @@ func FetchAndExtractRemoteArchive(ctx context.Context, config config.IConfig, ur
 	color := ansi.Color(os.Stdout)
 	fmt.Println(color.Yellow(fmt.Sprintf("fetching tarball at %s...", url)))
 
-	t := &requests.TracedTransport{}
-
-	req, err := http.NewRequest("GET", url, nil)
+	body, err := FetchRemoteResource(ctx, url)
 	if err != nil {
 		return err
 	}
 
-	trace := &httptrace.ClientTrace{
-		GotConn: t.GotConn,
-		DNSDone: t.DNSDone,
-	}
-	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
-	client := &http.Client{Transport: t}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-
-	defer resp.Body.Close()
-
-	archive, err := gzip.NewReader(resp.Body)
+	archive, err := gzip.NewReader(bytes.NewReader(body))
 	if err != nil {
 		return err
 	}
@@ -330,6 +297,10 @@ func extractAndInstall(ctx context.Context, config config.IConfig, tarReader *ta
 	extractedPluginName := ""
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		header, err := tarReader.Next()
 		if err == io.EOF {
 			break
@@ -358,7 +329,7 @@ func extractAndInstall(ctx context.Context, config config.IConfig, tarReader *ta
 			}
 
 		default:
-			return fmt.Errorf("unrecognized file type for file %s: %c", name, header.Typeflag)
+			return clierrors.Errorf(clierrors.InvalidPluginArchive, "unrecognized file type for file %s: %c", name, header.Typeflag)
 		}
 	}
 
@@ -368,23 +339,25 @@ func extractAndInstall(ctx context.Context, config config.IConfig, tarReader *ta
 		plugin.Releases[0].Unmanaged = true
 
 		if extractedPluginName != plugin.Binary {
-			return fmt.Errorf(
+			return clierrors.Errorf(
+				clierrors.InvalidPluginArchive,
 				"extracted plugin '%s' does not match the plugin '%s' in the manifest",
 				extractedPluginName,
 				plugin.Shortname)
 		}
 
-		err := AddEntryToPluginManifest(ctx, config, fs, plugin)
+		// the manifest only lists the release once the plugin is saved
+		err := plugin.verifychecksumAndSavePlugin(pluginData, config, fs, plugin.Releases[0].Version)
 		if err != nil {
 			return err
 		}
 
-		err = plugin.verifychecksumAndSavePlugin(pluginData, config, fs, plugin.Releases[0].Version)
+		err = AddEntryToPluginManifest(ctx, config, fs, plugin)
 		if err != nil {
 			return err
 		}
 	} else {
-		return fmt.Errorf("missing required manifest.toml or plugin in the archive")
+		return clierrors.New(clierrors.InvalidPluginArchive, "missing required manifest.toml or plugin in the archive")
 	}
 
 	return nil
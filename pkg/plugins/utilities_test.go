@@ -42,6 +42,7 @@ func TestLookUpPlugin(t *testing.T) {
 	require.Equal(t, "stripe-cli-app-b", plugin.Binary)
 	require.Equal(t, "FDBE6FB9-A149-44BD-9639-4D33D8B594E8", plugin.MagicCookieValue)
 	require.Equal(t, 4, len(plugin.Releases))
+	require.Equal(t, map[string]string{"b-succeed": "trigger payment_intent.succeeded --override 'payment_intent:description=From appB'"}, plugin.Aliases)
 }
 
 func TestRefreshPluginManifest(t *testing.T) {
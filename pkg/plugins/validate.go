@@ -0,0 +1,198 @@
+package plugins
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/afero"
+
+	"github.com/stripe/stripe-cli/pkg/config"
+)
+
+// ValidateManifest checks that the plugin manifest can be parsed, only
+// contains known fields, and describes every plugin and release completely
+func ValidateManifest(cfg config.IConfig, fs afero.Fs) []config.Issue {
+	manifestPath := filepath.Join(cfg.GetConfigFolder(os.Getenv("XDG_CONFIG_HOME")), "plugins.toml")
+
+	data, err := afero.ReadFile(fs, manifestPath)
+	if os.IsNotExist(err) {
+		if len(cfg.GetInstalledPlugins()) == 0 {
+			return nil
+		}
+
+		return []config.Issue{{
+			Severity: config.SeverityError,
+			Subject:  manifestPath,
+			Message:  "plugins are installed but the plugin manifest is missing",
+			Fix:      "run `stripe plugin upgrade <plugin>` to download it again",
+		}}
+	} else if err != nil {
+		return []config.Issue{{Severity: config.SeverityError, Subject: manifestPath, Message: err.Error()}}
+	}
+
+	var pluginList PluginList
+	md, err := toml.Decode(string(data), &pluginList)
+	if err != nil {
+		return []config.Issue{{
+			Severity: config.SeverityError,
+			Subject:  manifestPath,
+			Message:  fmt.Sprintf("the plugin manifest is not valid TOML: %s", err),
+			Fix:      fmt.Sprintf("delete %s, it is downloaded again the next time a plugin runs", manifestPath),
+		}}
+	}
+
+	var issues []config.Issue
+
+	for _, key := range md.Undecoded() {
+		issues = append(issues, config.Issue{
+			Severity: config.SeverityWarning,
+			Subject:  manifestPath,
+			Message:  fmt.Sprintf("unknown field '%s'", key),
+			Fix:      "update the CLI, the manifest may have been written for a newer version",
+		})
+	}
+
+	for i, p := range pluginList.Plugins {
+		subject := fmt.Sprintf("plugin %s", p.Shortname)
+		if p.Shortname == "" {
+			subject = fmt.Sprintf("plugin #%d", i+1)
+		}
+
+		for _, field := range []struct{ name, value string }{
+			{"Shortname", p.Shortname},
+			{"Binary", p.Binary},
+			{"MagicCookieValue", p.MagicCookieValue},
+		} {
+			if field.value == "" {
+				issues = append(issues, config.Issue{
+					Severity: config.SeverityError,
+					Subject:  subject,
+					Message:  fmt.Sprintf("missing %s in the plugin manifest", field.name),
+					Fix:      fmt.Sprintf("delete %s, it is downloaded again the next time a plugin runs", manifestPath),
+				})
+			}
+		}
+
+		for _, release := range p.Releases {
+			if release.OS == "" || release.Arch == "" || release.Version == "" {
+				issues = append(issues, config.Issue{
+					Severity: config.SeverityError,
+					Subject:  subject,
+					Message:  "a release is missing its OS, Arch or Version",
+				})
+				continue
+			}
+
+			if release.Unmanaged {
+				continue
+			}
+
+			if sum, err := hex.DecodeString(release.Sum); err != nil || len(sum) != sha256.Size {
+				issues = append(issues, config.Issue{
+					Severity: config.SeverityError,
+					Subject:  subject,
+					Message:  fmt.Sprintf("release %s for %s/%s has an invalid checksum", release.Version, release.OS, release.Arch),
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// VerifyInstalledPlugins checks that the binary of every installed plugin
+// exists and matches the checksum recorded in the plugin manifest
+func VerifyInstalledPlugins(cfg config.IConfig, fs afero.Fs) []config.Issue {
+	installed := cfg.GetInstalledPlugins()
+	if len(installed) == 0 {
+		return nil
+	}
+
+	manifestPath := filepath.Join(cfg.GetConfigFolder(os.Getenv("XDG_CONFIG_HOME")), "plugins.toml")
+
+	var pluginList PluginList
+	if data, err := afero.ReadFile(fs, manifestPath); err == nil {
+		toml.Decode(string(data), &pluginList)
+	}
+
+	var issues []config.Issue
+
+	for _, name := range installed {
+		subject := fmt.Sprintf("plugin %s", name)
+		reinstall := fmt.Sprintf("run `stripe plugin install %s`", name)
+
+		var plugin *Plugin
+		for i := range pluginList.Plugins {
+			if pluginList.Plugins[i].Shortname == name {
+				plugin = &pluginList.Plugins[i]
+			}
+		}
+
+		if plugin == nil {
+			issues = append(issues, config.Issue{
+				Severity: config.SeverityError,
+				Subject:  subject,
+				Message:  "the plugin is installed but missing from the plugin manifest",
+				Fix:      fmt.Sprintf("run `stripe plugin upgrade %s`, or `stripe plugin uninstall %s` if it was removed", name, name),
+			})
+			continue
+		}
+
+		versionDirs, _ := afero.Glob(fs, filepath.Join(getPluginsDir(cfg), name, "*.*.*"))
+		if len(versionDirs) == 0 {
+			issues = append(issues, config.Issue{
+				Severity: config.SeverityError,
+				Subject:  subject,
+				Message:  "the plugin is listed as installed but its binary is missing",
+				Fix:      reinstall,
+			})
+			continue
+		}
+
+		for _, versionDir := range versionDirs {
+			version := filepath.Base(versionDir)
+			binaryPath := filepath.Join(versionDir, plugin.Binary+GetBinaryExtension())
+
+			if issue := verifyPluginBinary(fs, plugin, version, binaryPath); issue != "" {
+				issues = append(issues, config.Issue{
+					Severity: config.SeverityError,
+					Subject:  fmt.Sprintf("%s v%s", subject, version),
+					Message:  issue,
+					Fix:      reinstall,
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// verifyPluginBinary returns what's wrong with an installed plugin binary, if
+// anything
+func verifyPluginBinary(fs afero.Fs, plugin *Plugin, version, binaryPath string) string {
+	if release, err := plugin.resolveRelease(version, true); err == nil && release != nil && release.Unmanaged {
+		return ""
+	}
+
+	if _, err := plugin.getChecksums(version); err != nil {
+		return fmt.Sprintf("the plugin manifest has no checksum for v%s on %s", version, currentPlatform)
+	}
+
+	file, err := fs.Open(binaryPath)
+	if os.IsNotExist(err) {
+		return fmt.Sprintf("the binary %s is missing", binaryPath)
+	} else if err != nil {
+		return err.Error()
+	}
+	defer file.Close()
+
+	if err := plugin.verifyChecksum(file, version); err != nil {
+		return "the binary doesn't match the checksum recorded in the plugin manifest, it may be corrupted or have been tampered with"
+	}
+
+	return ""
+}
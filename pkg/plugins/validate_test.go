@@ -0,0 +1,87 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/config"
+)
+
+func TestValidateManifest(t *testing.T) {
+	fs := setUpFS()
+	cfg := &TestConfig{}
+
+	issues := ValidateManifest(cfg, fs)
+	require.Empty(t, issues)
+}
+
+func TestValidateManifestMissing(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	cfg := &TestConfig{}
+
+	require.Empty(t, ValidateManifest(cfg, fs))
+
+	cfg.InstalledPlugins = []string{"appA"}
+	issues := ValidateManifest(cfg, fs)
+	require.Len(t, issues, 1)
+	require.Equal(t, config.SeverityError, issues[0].Severity)
+}
+
+func TestValidateManifestInvalid(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	cfg := &TestConfig{}
+	afero.WriteFile(fs, "/plugins.toml", []byte(`
+[[Plugin]]
+  Shortname = "appA"
+  Binray = "stripe-cli-app-a"
+
+  [[Plugin.Release]]
+    Arch = "amd64"
+    OS = "linux"
+    Version = "0.0.1"
+    Sum = "123"
+
+  [[Plugin.Release]]
+    Arch = "amd64"
+    Version = "0.0.2"
+`), os.ModePerm)
+
+	issues := ValidateManifest(cfg, fs)
+	require.Len(t, issues, 5)
+	require.Equal(t, "unknown field 'Plugin.Binray'", issues[0].Message)
+	require.Equal(t, "missing Binary in the plugin manifest", issues[1].Message)
+	require.Equal(t, "missing MagicCookieValue in the plugin manifest", issues[2].Message)
+	require.Equal(t, "release 0.0.1 for linux/amd64 has an invalid checksum", issues[3].Message)
+	require.Equal(t, "a release is missing its OS, Arch or Version", issues[4].Message)
+}
+
+func TestVerifyInstalledPlugins(t *testing.T) {
+	fs := setUpFS()
+	cfg := &TestConfig{}
+	cfg.InstalledPlugins = []string{"appA", "appZ"}
+
+	issues := VerifyInstalledPlugins(cfg, fs)
+	require.Len(t, issues, 2)
+	require.Equal(t, "plugin appA", issues[0].Subject)
+	require.Equal(t, "the plugin is listed as installed but its binary is missing", issues[0].Message)
+	require.Equal(t, "plugin appZ", issues[1].Subject)
+	require.Equal(t, "the plugin is installed but missing from the plugin manifest", issues[1].Message)
+}
+
+func TestVerifyInstalledPluginsChecksumMismatch(t *testing.T) {
+	fs := setUpFS()
+	cfg := &TestConfig{}
+	cfg.InstalledPlugins = []string{"appA"}
+
+	binaryPath := filepath.Join(getPluginsDir(cfg), "appA", "0.0.1", "stripe-cli-app-a"+GetBinaryExtension())
+	afero.WriteFile(fs, binaryPath, []byte("tampered"), os.ModePerm)
+
+	issues := VerifyInstalledPlugins(cfg, fs)
+	require.Len(t, issues, 1)
+	require.Equal(t, "plugin appA v0.0.1", issues[0].Subject)
+	require.Contains(t, issues[0].Message, "doesn't match the checksum")
+}
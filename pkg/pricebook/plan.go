@@ -0,0 +1,291 @@
+package pricebook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// Price is a price of the account
+type Price struct {
+	ID         string     `json:"id"`
+	Active     bool       `json:"active"`
+	Currency   string     `json:"currency"`
+	LookupKey  string     `json:"lookup_key"`
+	Nickname   string     `json:"nickname"`
+	Product    string     `json:"product"`
+	UnitAmount int64      `json:"unit_amount"`
+	Recurring  *Recurring `json:"recurring"`
+}
+
+// Recurring is the interval of a recurring price
+type Recurring struct {
+	Interval      string `json:"interval"`
+	IntervalCount int    `json:"interval_count"`
+}
+
+// Terms returns the amount and interval of the price, such as
+// "usd 12.50 / month"
+func (p Price) Terms() string {
+	if p.Recurring == nil {
+		return formatTerms(p.Currency, p.UnitAmount, "", 0)
+	}
+
+	return formatTerms(p.Currency, p.UnitAmount, p.Recurring.Interval, p.Recurring.IntervalCount)
+}
+
+// Product is a product of the account
+type Product struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
+// Action is what is done to reconcile a price with the price book
+type Action string
+
+// The actions of a plan
+const (
+	// ActionCreate creates a price of the price book missing from the account
+	ActionCreate Action = "create"
+	// ActionReplace creates a new price for a price whose terms changed, moves
+	// the lookup key to it and archives the previous one, since the terms of
+	// a price can't be changed
+	ActionReplace Action = "replace"
+	// ActionRename changes the nickname of a price
+	ActionRename Action = "rename"
+	// ActionArchive archives a price that isn't in the price book anymore
+	ActionArchive Action = "archive"
+)
+
+// Change is a change of a plan
+type Change struct {
+	Action Action
+	// Row is the price of the price book, nil to archive a price
+	Row *Row
+	// Price is the current price, nil to create one
+	Price *Price
+	// ProductID is the product of the new price, empty if the product named
+	// by the row has to be created
+	ProductID string
+}
+
+// Plan is how to reconcile the prices of the account with the price book
+type Plan struct {
+	Changes []Change
+	// Missing are the prices to create that aren't created without
+	// CreateMissing
+	Missing []Row
+	// Removed are the prices to archive that aren't archived without
+	// ArchiveRemoved
+	Removed []Price
+	// Unchanged is how many prices of the price book are already up to date
+	Unchanged int
+}
+
+// Options configure a plan
+type Options struct {
+	// CreateMissing creates the prices, and their products, that are in the
+	// price book but not in the account
+	CreateMissing bool
+	// ArchiveRemoved archives the prices that have a lookup key not in the
+	// price book
+	ArchiveRemoved bool
+}
+
+// NewPlan compares the price book with the active prices and products of the
+// account. Prices are matched by lookup key, and those without one aren't
+// managed by the price book. Products are named by ID, or by name.
+func NewPlan(rows []Row, prices []Price, products []Product, opts Options) (*Plan, error) {
+	plan := &Plan{}
+
+	byLookupKey := make(map[string]*Price)
+	for i := range prices {
+		if prices[i].Active && prices[i].LookupKey != "" {
+			byLookupKey[prices[i].LookupKey] = &prices[i]
+		}
+	}
+
+	productIDs := make(map[string]bool)
+	byName := make(map[string][]string)
+	for _, product := range products {
+		productIDs[product.ID] = true
+		if product.Active {
+			byName[product.Name] = append(byName[product.Name], product.ID)
+		}
+	}
+
+	inBook := make(map[string]bool)
+
+	for i := range rows {
+		row := &rows[i]
+		inBook[row.LookupKey] = true
+
+		productID, err := resolveProduct(row, productIDs, byName)
+		if err != nil {
+			return nil, err
+		}
+
+		price := byLookupKey[row.LookupKey]
+
+		var action Action
+		switch {
+		case price == nil:
+			action = ActionCreate
+		case productID != price.Product || !sameTerms(row, price):
+			action = ActionReplace
+		case row.Nickname != price.Nickname:
+			action = ActionRename
+		default:
+			plan.Unchanged++
+			continue
+		}
+
+		// creating prices and products is only done when asked to, and prices
+		// are only replaced with a product that exists
+		if action == ActionCreate && !opts.CreateMissing || action == ActionReplace && productID == "" && !opts.CreateMissing {
+			plan.Missing = append(plan.Missing, *row)
+			continue
+		}
+
+		plan.Changes = append(plan.Changes, Change{Action: action, Row: row, Price: price, ProductID: productID})
+	}
+
+	var removed []Price
+	for _, price := range byLookupKey {
+		if !inBook[price.LookupKey] {
+			removed = append(removed, *price)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i].LookupKey < removed[j].LookupKey })
+
+	if !opts.ArchiveRemoved {
+		plan.Removed = removed
+		return plan, nil
+	}
+
+	for i := range removed {
+		plan.Changes = append(plan.Changes, Change{Action: ActionArchive, Price: &removed[i]})
+	}
+
+	return plan, nil
+}
+
+// resolveProduct returns the ID of the product of a row, or "" if there is no
+// product with its name yet
+func resolveProduct(row *Row, productIDs map[string]bool, byName map[string][]string) (string, error) {
+	if strings.HasPrefix(row.Product, "prod_") {
+		if !productIDs[row.Product] {
+			return "", clierrors.Errorf(clierrors.InvalidPriceBook, "line %d: there is no product %s", row.Line, row.Product)
+		}
+		return row.Product, nil
+	}
+
+	switch ids := byName[row.Product]; len(ids) {
+	case 0:
+		return "", nil
+	case 1:
+		return ids[0], nil
+	default:
+		return "", clierrors.Errorf(clierrors.InvalidPriceBook, "line %d: several products are named %q (%s), use the ID of one instead", row.Line, row.Product, strings.Join(ids, ", "))
+	}
+}
+
+func sameTerms(row *Row, price *Price) bool {
+	if row.Currency != price.Currency || row.UnitAmount != price.UnitAmount {
+		return false
+	}
+
+	if price.Recurring == nil {
+		return row.Interval == ""
+	}
+
+	return row.Interval == price.Recurring.Interval && row.IntervalCount == price.Recurring.IntervalCount
+}
+
+// Post makes a POST request to the API and returns the response body
+type Post func(ctx context.Context, path string, data []string) ([]byte, error)
+
+// Apply makes the changes of the plan, in order, creating the products the
+// new prices need. It stops at the first change that fails, returning how
+// many were made.
+func (plan *Plan) Apply(ctx context.Context, post Post) (int, error) {
+	created := make(map[string]string)
+
+	for i, change := range plan.Changes {
+		if err := applyChange(ctx, post, change, created); err != nil {
+			return i, err
+		}
+	}
+
+	return len(plan.Changes), nil
+}
+
+func applyChange(ctx context.Context, post Post, change Change, createdProducts map[string]string) error {
+	switch change.Action {
+	case ActionArchive:
+		return archive(ctx, post, change.Price)
+	case ActionRename:
+		_, err := post(ctx, "/v1/prices/"+change.Price.ID, []string{"nickname=" + change.Row.Nickname})
+		return err
+	}
+
+	row := change.Row
+
+	productID := change.ProductID
+	if productID == "" {
+		productID = createdProducts[row.Product]
+	}
+	if productID == "" {
+		body, err := post(ctx, "/v1/products", []string{"name=" + row.Product})
+		if err != nil {
+			return fmt.Errorf("could not create product %s: %w", row.Product, err)
+		}
+
+		var product Product
+		if err := json.Unmarshal(body, &product); err != nil {
+			return err
+		}
+		productID = product.ID
+		createdProducts[row.Product] = productID
+	}
+
+	data := []string{
+		"product=" + productID,
+		"currency=" + row.Currency,
+		"unit_amount=" + strconv.FormatInt(row.UnitAmount, 10),
+		"lookup_key=" + row.LookupKey,
+	}
+	if row.Interval != "" {
+		data = append(data, "recurring[interval]="+row.Interval, "recurring[interval_count]="+strconv.Itoa(row.IntervalCount))
+	}
+	if row.Nickname != "" {
+		data = append(data, "nickname="+row.Nickname)
+	}
+	if change.Action == ActionReplace {
+		data = append(data, "transfer_lookup_key=true")
+	}
+
+	if _, err := post(ctx, "/v1/prices", data); err != nil {
+		return fmt.Errorf("could not create price %s: %w", row.LookupKey, err)
+	}
+
+	if change.Action == ActionReplace {
+		return archive(ctx, post, change.Price)
+	}
+
+	return nil
+}
+
+func archive(ctx context.Context, post Post, price *Price) error {
+	if _, err := post(ctx, "/v1/prices/"+price.ID, []string{"active=false"}); err != nil {
+		return fmt.Errorf("could not archive price %s: %w", price.ID, err)
+	}
+
+	return nil
+}
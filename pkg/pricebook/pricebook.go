@@ -0,0 +1,158 @@
+// Package pricebook reconciles the prices of an account with a price book: a
+// spreadsheet listing the prices that should be on sale, identified by their
+// lookup key.
+package pricebook
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/currency"
+)
+
+// columns are the columns of a price book, the first four being required
+var columns = []string{"product", "lookup_key", "currency", "unit_amount", "interval", "interval_count", "nickname"}
+
+const requiredColumns = 4
+
+var intervals = map[string]bool{"day": true, "week": true, "month": true, "year": true}
+
+// Row is a price of the price book
+type Row struct {
+	// Line is the line of the row in the file
+	Line int
+	// Product is the ID of the product, or its name
+	Product    string
+	LookupKey  string
+	Currency   string
+	UnitAmount int64
+	// Interval is empty for one-time prices
+	Interval      string
+	IntervalCount int
+	Nickname      string
+}
+
+// Terms returns the amount and interval of the row, such as "usd 12.50 / month"
+func (r Row) Terms() string {
+	return formatTerms(r.Currency, r.UnitAmount, r.Interval, r.IntervalCount)
+}
+
+// ParseCSV reads a price book with a header row naming its columns: product,
+// lookup_key, currency and unit_amount, and optionally interval,
+// interval_count and nickname. Amounts are decimal, such as 12.50.
+func ParseCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, clierrors.New(clierrors.InvalidPriceBook, "the price book is empty")
+	} else if err != nil {
+		return nil, clierrors.Wrap(clierrors.InvalidPriceBook, err)
+	}
+
+	index := make(map[string]int)
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, column := range columns[:requiredColumns] {
+		if _, ok := index[column]; !ok {
+			return nil, clierrors.Errorf(clierrors.InvalidPriceBook, "the price book has no %s column, expected the columns %s", column, strings.Join(columns, ", "))
+		}
+	}
+
+	var rows []Row
+	byLookupKey := make(map[string]int)
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, clierrors.Wrap(clierrors.InvalidPriceBook, err)
+		}
+
+		value := func(column string) string {
+			i, ok := index[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row, err := parseRow(line, value)
+		if err != nil {
+			return nil, err
+		}
+
+		if first, ok := byLookupKey[row.LookupKey]; ok {
+			return nil, clierrors.Errorf(clierrors.InvalidPriceBook, "line %d: the lookup key %s is already used on line %d", line, row.LookupKey, first)
+		}
+		byLookupKey[row.LookupKey] = line
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseRow(line int, value func(string) string) (Row, error) {
+	row := Row{
+		Line:      line,
+		Product:   value("product"),
+		LookupKey: value("lookup_key"),
+		Currency:  strings.ToLower(value("currency")),
+		Interval:  strings.ToLower(value("interval")),
+		Nickname:  value("nickname"),
+	}
+
+	for _, column := range columns[:requiredColumns] {
+		if value(column) == "" {
+			return Row{}, clierrors.Errorf(clierrors.InvalidPriceBook, "line %d: the %s is missing", line, column)
+		}
+	}
+
+	amount, err := currency.ParseAmount(row.Currency, value("unit_amount"))
+	if err != nil {
+		return Row{}, fmt.Errorf("line %d: %w", line, err)
+	}
+	row.UnitAmount = amount
+
+	if row.Interval != "" && !intervals[row.Interval] {
+		return Row{}, clierrors.Errorf(clierrors.InvalidPriceBook, "line %d: invalid interval %q, expected day, week, month or year, or nothing for one-time prices", line, row.Interval)
+	}
+
+	if count := value("interval_count"); count != "" {
+		if row.Interval == "" {
+			return Row{}, clierrors.Errorf(clierrors.InvalidPriceBook, "line %d: one-time prices have no interval_count", line)
+		}
+		row.IntervalCount, err = strconv.Atoi(count)
+		if err != nil || row.IntervalCount < 1 {
+			return Row{}, clierrors.Errorf(clierrors.InvalidPriceBook, "line %d: invalid interval_count %q", line, count)
+		}
+	}
+	if row.Interval != "" && row.IntervalCount == 0 {
+		row.IntervalCount = 1
+	}
+
+	return row, nil
+}
+
+func formatTerms(code string, amount int64, interval string, intervalCount int) string {
+	terms := fmt.Sprintf("%s %s", code, currency.FormatAmount(code, amount))
+
+	switch {
+	case interval == "":
+	case intervalCount > 1:
+		terms += fmt.Sprintf(" / %d %ss", intervalCount, interval)
+	default:
+		terms += " / " + interval
+	}
+
+	return terms
+}
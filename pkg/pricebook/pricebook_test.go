@@ -0,0 +1,153 @@
+package pricebook
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const book = `product,lookup_key,currency,unit_amount,interval,interval_count,nickname
+Pro,pro_monthly,usd,12.50,month,,Pro monthly
+Pro,pro_yearly,USD,120,year,,
+prod_setup,setup_fee,jpy,5000,,,
+`
+
+func TestParseCSV(t *testing.T) {
+	rows, err := ParseCSV(strings.NewReader(book))
+	require.NoError(t, err)
+	require.Equal(t, []Row{
+		{Line: 2, Product: "Pro", LookupKey: "pro_monthly", Currency: "usd", UnitAmount: 1250, Interval: "month", IntervalCount: 1, Nickname: "Pro monthly"},
+		{Line: 3, Product: "Pro", LookupKey: "pro_yearly", Currency: "usd", UnitAmount: 12000, Interval: "year", IntervalCount: 1},
+		{Line: 4, Product: "prod_setup", LookupKey: "setup_fee", Currency: "jpy", UnitAmount: 5000},
+	}, rows)
+
+	require.Equal(t, "usd 12.50 / month", rows[0].Terms())
+	require.Equal(t, "jpy 5000", rows[2].Terms())
+
+	// the optional columns can be left out, in any order
+	rows, err = ParseCSV(strings.NewReader("lookup_key,unit_amount,currency,product\nbasic,5,eur,Basic\n"))
+	require.NoError(t, err)
+	require.Equal(t, []Row{{Line: 2, Product: "Basic", LookupKey: "basic", Currency: "eur", UnitAmount: 500}}, rows)
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	for _, tt := range []struct {
+		csv, err string
+	}{
+		{"", "the price book is empty"},
+		{"product,lookup_key,currency\n", "the price book has no unit_amount column, expected the columns product, lookup_key, currency, unit_amount, interval, interval_count, nickname"},
+		{"product,lookup_key,currency,unit_amount\nPro,,usd,10\n", "line 2: the lookup_key is missing"},
+		{"product,lookup_key,currency,unit_amount\nPro,pro,usd,10.005\n", `line 2: invalid amount "10.005": USD has 2 decimals`},
+		{"product,lookup_key,currency,unit_amount,interval\nPro,pro,usd,10,monthly\n", `line 2: invalid interval "monthly", expected day, week, month or year, or nothing for one-time prices`},
+		{"product,lookup_key,currency,unit_amount,interval_count\nPro,pro,usd,10,3\n", "line 2: one-time prices have no interval_count"},
+		{"product,lookup_key,currency,unit_amount\nPro,pro,usd,10\nPro,pro,usd,12\n", "line 3: the lookup key pro is already used on line 2"},
+	} {
+		_, err := ParseCSV(strings.NewReader(tt.csv))
+		require.EqualError(t, err, tt.err)
+	}
+}
+
+func accountPrices() []Price {
+	return []Price{
+		{ID: "price_monthly", Active: true, Currency: "usd", LookupKey: "pro_monthly", Product: "prod_pro", UnitAmount: 1000, Recurring: &Recurring{"month", 1}},
+		{ID: "price_yearly", Active: true, Currency: "usd", LookupKey: "pro_yearly", Product: "prod_pro", UnitAmount: 12000, Recurring: &Recurring{"year", 1}, Nickname: "Yearly"},
+		{ID: "price_legacy", Active: true, Currency: "usd", LookupKey: "legacy", Product: "prod_pro", UnitAmount: 900, Recurring: &Recurring{"month", 1}},
+		{ID: "price_unmanaged", Active: true, Currency: "usd", Product: "prod_pro", UnitAmount: 100},
+	}
+}
+
+func TestNewPlan(t *testing.T) {
+	rows, err := ParseCSV(strings.NewReader(book))
+	require.NoError(t, err)
+
+	products := []Product{{ID: "prod_pro", Name: "Pro", Active: true}}
+
+	// setup_fee names a product that doesn't exist
+	_, err = NewPlan(rows, accountPrices(), products, Options{})
+	require.EqualError(t, err, "line 4: there is no product prod_setup")
+
+	rows[2].Product = "Setup"
+
+	plan, err := NewPlan(rows, accountPrices(), products, Options{})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, ActionReplace, plan.Changes[0].Action)
+	require.Equal(t, "price_monthly", plan.Changes[0].Price.ID)
+	require.Equal(t, ActionRename, plan.Changes[1].Action)
+	require.Equal(t, "price_yearly", plan.Changes[1].Price.ID)
+	require.Equal(t, []Row{rows[2]}, plan.Missing)
+	require.Len(t, plan.Removed, 1)
+	require.Equal(t, "price_legacy", plan.Removed[0].ID)
+
+	plan, err = NewPlan(rows, accountPrices(), products, Options{CreateMissing: true, ArchiveRemoved: true})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 4)
+	require.Equal(t, ActionCreate, plan.Changes[2].Action)
+	require.Equal(t, "", plan.Changes[2].ProductID)
+	require.Equal(t, ActionArchive, plan.Changes[3].Action)
+	require.Equal(t, "price_legacy", plan.Changes[3].Price.ID)
+	require.Empty(t, plan.Missing)
+	require.Empty(t, plan.Removed)
+
+	_, err = NewPlan(rows, nil, append(products, Product{ID: "prod_pro2", Name: "Pro", Active: true}), Options{})
+	require.EqualError(t, err, `line 2: several products are named "Pro" (prod_pro, prod_pro2), use the ID of one instead`)
+}
+
+type post struct {
+	path string
+	data []string
+}
+
+func TestApply(t *testing.T) {
+	rows, err := ParseCSV(strings.NewReader(book))
+	require.NoError(t, err)
+	rows[2].Product = "Setup"
+	rows = append(rows, Row{Line: 5, Product: "Setup", LookupKey: "setup_fee_eur", Currency: "eur", UnitAmount: 4000})
+
+	products := []Product{{ID: "prod_pro", Name: "Pro", Active: true}}
+
+	plan, err := NewPlan(rows, accountPrices(), products, Options{CreateMissing: true, ArchiveRemoved: true})
+	require.NoError(t, err)
+
+	var posts []post
+	made, err := plan.Apply(context.Background(), func(ctx context.Context, path string, data []string) ([]byte, error) {
+		posts = append(posts, post{path, data})
+		if path == "/v1/products" {
+			return []byte(`{"id": "prod_setup"}`), nil
+		}
+		return []byte(`{}`), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 5, made)
+
+	require.Equal(t, []post{
+		{"/v1/prices", []string{"product=prod_pro", "currency=usd", "unit_amount=1250", "lookup_key=pro_monthly", "recurring[interval]=month", "recurring[interval_count]=1", "nickname=Pro monthly", "transfer_lookup_key=true"}},
+		{"/v1/prices/price_monthly", []string{"active=false"}},
+		{"/v1/prices/price_yearly", []string{"nickname="}},
+		// the product is created once for both of its prices
+		{"/v1/products", []string{"name=Setup"}},
+		{"/v1/prices", []string{"product=prod_setup", "currency=jpy", "unit_amount=5000", "lookup_key=setup_fee"}},
+		{"/v1/prices", []string{"product=prod_setup", "currency=eur", "unit_amount=4000", "lookup_key=setup_fee_eur"}},
+		{"/v1/prices/price_legacy", []string{"active=false"}},
+	}, posts)
+}
+
+func TestApplyStopsAtFailure(t *testing.T) {
+	plan := &Plan{Changes: []Change{
+		{Action: ActionArchive, Price: &Price{ID: "price_1"}},
+		{Action: ActionArchive, Price: &Price{ID: "price_2"}},
+		{Action: ActionArchive, Price: &Price{ID: "price_3"}},
+	}}
+
+	made, err := plan.Apply(context.Background(), func(ctx context.Context, path string, data []string) ([]byte, error) {
+		if path == "/v1/prices/price_2" {
+			return nil, errors.New("boom")
+		}
+		return []byte(`{}`), nil
+	})
+	require.EqualError(t, err, "could not archive price price_2: boom")
+	require.Equal(t, 1, made)
+}
@@ -0,0 +1,14 @@
+// Package proxy implements the webhook forwarding behind `stripe listen`.
+//
+// Programs embedding the CLI can rely on Init, which builds a Proxy from a
+// Config, and Proxy.Run. Forwarded events, endpoint responses and connection
+// status are delivered on Config.OutCh, with endpoint responses sent as
+// EndpointResponse values. Custom handling of the responses can be plugged in
+// through EndpointResponseHandler, which receives the EventContext of the
+// event that was forwarded. These keep their signatures within a major version
+// of the CLI, though Config gains fields as `stripe listen` gains flags, so it
+// should be built with named fields.
+//
+// EndpointClient, the shadow mode and the helpers parsing requests and events
+// are the internals of `stripe listen`.
+package proxy
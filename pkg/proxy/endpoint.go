@@ -31,17 +31,17 @@ type EndpointConfig struct {
 
 // EndpointResponseHandler handles a response from the endpoint.
 type EndpointResponseHandler interface {
-	ProcessResponse(eventContext, string, *http.Response)
+	ProcessResponse(EventContext, string, *http.Response)
 }
 
 // EndpointResponseHandlerFunc is an adapter to allow the use of ordinary
 // functions as response handlers. If f is a function with the
 // appropriate signature, ResponseHandler(f) is a
 // ResponseHandler that calls f.
-type EndpointResponseHandlerFunc func(eventContext, string, *http.Response)
+type EndpointResponseHandlerFunc func(EventContext, string, *http.Response)
 
 // ProcessResponse calls f(evtCtx, forwardURL, resp).
-func (f EndpointResponseHandlerFunc) ProcessResponse(evtCtx eventContext, forwardURL string, resp *http.Response) {
+func (f EndpointResponseHandlerFunc) ProcessResponse(evtCtx EventContext, forwardURL string, resp *http.Response) {
 	f(evtCtx, forwardURL, resp)
 }
 
@@ -85,28 +85,17 @@ func (c *EndpointClient) SupportsEventType(connect bool, eventType string) bool
 }
 
 // Post sends a message to the local endpoint.
-func (c *EndpointClient) Post(evtCtx eventContext, body string, headers map[string]string) error {
+func (c *EndpointClient) Post(evtCtx EventContext, body string, headers map[string]string) error {
 	c.cfg.Log.WithFields(log.Fields{
 		"prefix": "proxy.EndpointClient.Post",
 	}).Debug("Forwarding event to local endpoint")
 
-	req, err := http.NewRequest(http.MethodPost, c.URL, bytes.NewBuffer([]byte(body)))
+	req, err := c.newRequest(body, headers)
 	if err != nil {
 		return err
 	}
 
-	for k, v := range headers {
-		req.Header.Add(k, v)
-	}
-
-	// add custom headers
-	for k, v := range c.headers {
-		if strings.ToLower(k) == "host" {
-			req.Host = v
-		} else {
-			req.Header.Add(k, v)
-		}
-	}
+	evtCtx.ForwardedAt = time.Now()
 
 	resp, err := c.cfg.HTTPClient.Do(req)
 	if err != nil {
@@ -123,6 +112,30 @@ func (c *EndpointClient) Post(evtCtx eventContext, body string, headers map[stri
 	return nil
 }
 
+// newRequest builds the POST request sending an event to the endpoint, with
+// the event's headers and the endpoint's custom headers
+func (c *EndpointClient) newRequest(body string, headers map[string]string) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodPost, c.URL, bytes.NewBuffer([]byte(body)))
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range headers {
+		req.Header.Add(k, v)
+	}
+
+	// add custom headers
+	for k, v := range c.headers {
+		if strings.ToLower(k) == "host" {
+			req.Host = v
+		} else {
+			req.Header.Add(k, v)
+		}
+	}
+
+	return req, nil
+}
+
 //
 // Public functions
 //
@@ -147,7 +160,7 @@ func NewEndpointClient(url string, headers []string, connect bool, events []stri
 	}
 
 	if cfg.ResponseHandler == nil {
-		cfg.ResponseHandler = EndpointResponseHandlerFunc(func(eventContext, string, *http.Response) {})
+		cfg.ResponseHandler = EndpointResponseHandlerFunc(func(EventContext, string, *http.Response) {})
 	}
 
 	return &EndpointClient{
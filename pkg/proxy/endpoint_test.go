@@ -35,7 +35,7 @@ func TestClientHandler(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	rcvCtx := eventContext{}
+	rcvCtx := EventContext{}
 	rcvBody := ""
 	rcvForwardURL := ""
 	client := NewEndpointClient(
@@ -45,7 +45,7 @@ func TestClientHandler(t *testing.T) {
 		false,
 		[]string{"*"},
 		&EndpointConfig{
-			ResponseHandler: EndpointResponseHandlerFunc(func(evtCtx eventContext, forwardURL string, resp *http.Response) {
+			ResponseHandler: EndpointResponseHandlerFunc(func(evtCtx EventContext, forwardURL string, resp *http.Response) {
 				buf, err := io.ReadAll(resp.Body)
 				require.NoError(t, err)
 
@@ -61,10 +61,10 @@ func TestClientHandler(t *testing.T) {
 	evt := &StripeEvent{
 		ID: "evt_123",
 	}
-	evtCtx := eventContext{
-		webhookID:             "wh_123",
-		webhookConversationID: "wc_123",
-		event:                 evt,
+	evtCtx := EventContext{
+		WebhookID:             "wh_123",
+		WebhookConversationID: "wc_123",
+		Event:                 evt,
 	}
 	payload := "{}"
 	headers := map[string]string{
@@ -79,9 +79,10 @@ func TestClientHandler(t *testing.T) {
 
 	require.Equal(t, "OK!", rcvBody)
 	require.Equal(t, ts.URL, rcvForwardURL)
-	require.Equal(t, "wh_123", rcvCtx.webhookID)
-	require.Equal(t, "wc_123", rcvCtx.webhookConversationID)
-	require.Equal(t, "evt_123", rcvCtx.event.ID)
+	require.Equal(t, "wh_123", rcvCtx.WebhookID)
+	require.Equal(t, "wc_123", rcvCtx.WebhookConversationID)
+	require.Equal(t, "evt_123", rcvCtx.Event.ID)
+	require.False(t, rcvCtx.ForwardedAt.IsZero())
 }
 
 func TestClientHandler_Redirects(t *testing.T) {
@@ -108,7 +109,7 @@ func TestClientHandler_Redirects(t *testing.T) {
 		false,
 		[]string{"*"},
 		&EndpointConfig{
-			ResponseHandler: EndpointResponseHandlerFunc(func(evtCtx eventContext, forwardURL string, resp *http.Response) {
+			ResponseHandler: EndpointResponseHandlerFunc(func(evtCtx EventContext, forwardURL string, resp *http.Response) {
 				require.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
 				wg.Done()
 			}),
@@ -118,10 +119,10 @@ func TestClientHandler_Redirects(t *testing.T) {
 	evt := &StripeEvent{
 		ID: "evt_123",
 	}
-	evtCtx := eventContext{
-		webhookID:             "wh_123",
-		webhookConversationID: "wc_123",
-		event:                 evt,
+	evtCtx := EventContext{
+		WebhookID:             "wh_123",
+		WebhookConversationID: "wc_123",
+		Event:                 evt,
 	}
 	payload := "{}"
 	headers := map[string]string{
@@ -4,12 +4,12 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -17,7 +17,9 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/otellogs"
 	"github.com/stripe/stripe-cli/pkg/requests"
 	"github.com/stripe/stripe-cli/pkg/stripe"
 	"github.com/stripe/stripe-cli/pkg/stripeauth"
@@ -50,6 +52,19 @@ type EndpointRoute struct {
 type EndpointResponse struct {
 	Event *StripeEvent
 	Resp  *http.Response
+
+	// RequestBody and RequestHeaders are the payload and headers that were
+	// forwarded to the endpoint
+	RequestBody    string
+	RequestHeaders map[string]string
+
+	// ResponseBody is the body returned by the endpoint, truncated to
+	// maxBodySize
+	ResponseBody string
+
+	// Duration is how long the endpoint took to respond, from sending the
+	// event until its response was read
+	Duration time.Duration
 }
 
 // FailedToReadResponseError describes a failure to read the response from an endpoint
@@ -80,6 +95,13 @@ type Config struct {
 	ForwardConnectHeaders []string
 	// UseConfiguredWebhooks loads webhooks config from user's account
 	UseConfiguredWebhooks bool
+	// URL to which events are also forwarded, to compare its responses with
+	// the ones from ForwardURL or ForwardConnectURL
+	ShadowURL string
+	// ShadowSecret is the signing secret of the endpoint at ShadowURL. When
+	// set, the events forwarded to it are signed with it rather than with the
+	// secret of the session.
+	ShadowSecret string
 
 	// EndpointsRoutes is a mapping of local webhook endpoint urls to the events they consume
 	EndpointRoutes []EndpointRoute
@@ -104,11 +126,27 @@ type Config struct {
 	NoWSS bool
 	// Override default timeout
 	Timeout int64
+	// PropagateTraceContext starts a trace for each event, sent to the
+	// endpoints in a W3C traceparent header so that their spans join it
+	PropagateTraceContext bool
 
 	// OutCh is the channel to send logs and statuses to for processing in other packages
 	OutCh chan websocket.IElement
 }
 
+// EventContext holds the details of a webhook event being forwarded to an
+// endpoint, so that the endpoint's response can be tied back to it
+type EventContext struct {
+	WebhookID             string
+	WebhookConversationID string
+	Event                 *StripeEvent
+	Payload               string
+	Headers               map[string]string
+
+	// ForwardedAt is when the event was sent to the endpoint
+	ForwardedAt time.Time
+}
+
 // A Proxy opens a websocket connection with Stripe, listens for incoming
 // webhook events, forwards them to the local endpoint and sends the response
 // back to Stripe.
@@ -116,6 +154,7 @@ type Proxy struct {
 	cfg *Config
 
 	endpointClients  []*EndpointClient
+	shadow           *shadowComparer
 	stripeAuthClient *stripeauth.Client
 	webSocketClient  *websocket.Client
 
@@ -150,7 +189,7 @@ func (p *Proxy) Run(ctx context.Context) error {
 
 		if err != nil {
 			p.cfg.OutCh <- websocket.ErrorElement{
-				Error: fmt.Errorf("Error while authenticating with Stripe: %v", err),
+				Error: clierrors.Errorf(clierrors.AuthorizationFailed, "Error while authenticating with Stripe: %v", err),
 			}
 			return err
 		}
@@ -199,7 +238,7 @@ func (p *Proxy) Run(ctx context.Context) error {
 					State: websocket.Reconnecting,
 				}
 			} else {
-				err := fmt.Errorf("Session expired. Terminating after %d failed attempts to reauthorize", nAttempts)
+				err := clierrors.Errorf(clierrors.AuthorizationFailed, "Session expired. Terminating after %d failed attempts to reauthorize", nAttempts)
 				p.cfg.OutCh <- websocket.ErrorElement{
 					Error: err,
 				}
@@ -219,6 +258,20 @@ func (p *Proxy) Run(ctx context.Context) error {
 	return nil
 }
 
+// EventTypes returns the sorted list of event types that can be listened for,
+// not including the "*" wildcard.
+func EventTypes() []string {
+	types := make([]string, 0, len(validEvents))
+	for event := range validEvents {
+		if event != "*" {
+			types = append(types, event)
+		}
+	}
+	sort.Strings(types)
+
+	return types
+}
+
 // GetSessionSecret creates a session and returns the webhook signing secret.
 func GetSessionSecret(ctx context.Context, deviceName, key, baseURL string) (string, error) {
 	p, err := Init(ctx, &Config{
@@ -372,10 +425,18 @@ func (p *Proxy) processWebhookEvent(msg websocket.IncomingMessage) {
 		return
 	}
 
-	evtCtx := eventContext{
-		webhookID:             webhookEvent.WebhookID,
-		webhookConversationID: webhookEvent.WebhookConversationID,
-		event:                 &evt,
+	headers := webhookEvent.HTTPHeaders
+	if p.cfg.PropagateTraceContext {
+		evt.Trace = otellogs.NewTraceContext()
+		headers = withTraceparent(headers, evt.Trace)
+	}
+
+	evtCtx := EventContext{
+		WebhookID:             webhookEvent.WebhookID,
+		WebhookConversationID: webhookEvent.WebhookConversationID,
+		Event:                 &evt,
+		Payload:               webhookEvent.EventPayload,
+		Headers:               headers,
 	}
 
 	if p.events["*"] || p.events[evt.Type] {
@@ -387,19 +448,43 @@ func (p *Proxy) processWebhookEvent(msg websocket.IncomingMessage) {
 		for _, endpoint := range p.endpointClients {
 			if endpoint.SupportsEventType(evt.IsConnect(), evt.Type) {
 				// TODO: handle errors returned by endpointClients
-				go endpoint.Post(
-					evtCtx,
-					webhookEvent.EventPayload,
-					webhookEvent.HTTPHeaders,
-				)
+				go func(endpoint *EndpointClient) {
+					err := endpoint.Post(
+						evtCtx,
+						webhookEvent.EventPayload,
+						headers,
+					)
+					if err != nil && p.shadow != nil {
+						p.shadow.recordPrimary(evtCtx, endpoint.URL, &shadowResponse{err: err})
+					}
+				}(endpoint)
+
+				if p.shadow != nil {
+					go p.shadow.post(evtCtx, webhookEvent.EventPayload, headers)
+				}
 			}
 		}
 	}
 }
 
-func (p *Proxy) processEndpointResponse(evtCtx eventContext, forwardURL string, resp *http.Response) {
+// withTraceparent returns a copy of the headers of an event with the
+// traceparent header of tc
+func withTraceparent(headers map[string]string, tc otellogs.TraceContext) map[string]string {
+	traced := make(map[string]string, len(headers)+1)
+	for k, v := range headers {
+		traced[k] = v
+	}
+	traced["traceparent"] = tc.Traceparent()
+
+	return traced
+}
+
+func (p *Proxy) processEndpointResponse(evtCtx EventContext, forwardURL string, resp *http.Response) {
 	buf, err := io.ReadAll(resp.Body)
 	if err != nil {
+		if p.shadow != nil {
+			p.shadow.recordPrimary(evtCtx, forwardURL, &shadowResponse{err: err})
+		}
 		p.cfg.OutCh <- websocket.ErrorElement{
 			Error: FailedToReadResponseError{Err: err},
 		}
@@ -408,10 +493,23 @@ func (p *Proxy) processEndpointResponse(evtCtx eventContext, forwardURL string,
 
 	body := truncate(string(buf), maxBodySize, true)
 
+	var duration time.Duration
+	if !evtCtx.ForwardedAt.IsZero() {
+		duration = time.Since(evtCtx.ForwardedAt)
+	}
+
+	if p.shadow != nil {
+		p.shadow.recordPrimary(evtCtx, forwardURL, &shadowResponse{statusCode: resp.StatusCode, body: body})
+	}
+
 	p.cfg.OutCh <- websocket.DataElement{
 		Data: EndpointResponse{
-			Event: evtCtx.event,
-			Resp:  resp,
+			Event:          evtCtx.Event,
+			Resp:           resp,
+			RequestBody:    evtCtx.Payload,
+			RequestHeaders: evtCtx.Headers,
+			ResponseBody:   body,
+			Duration:       duration,
 		},
 	}
 
@@ -429,8 +527,8 @@ func (p *Proxy) processEndpointResponse(evtCtx eventContext, forwardURL string,
 
 	if p.webSocketClient != nil {
 		msg := websocket.NewWebhookResponse(
-			evtCtx.webhookID,
-			evtCtx.webhookConversationID,
+			evtCtx.WebhookID,
+			evtCtx.WebhookConversationID,
 			forwardURL,
 			resp.StatusCode,
 			body,
@@ -453,13 +551,23 @@ func Init(ctx context.Context, cfg *Config) (*Proxy, error) {
 	// validate forward-urls args
 	if cfg.UseConfiguredWebhooks && len(cfg.ForwardURL) > 0 {
 		if strings.HasPrefix(cfg.ForwardURL, "/") {
-			return nil, errors.New("forward_to cannot be a relative path when loading webhook endpoints from the API")
+			return nil, clierrors.New(clierrors.ConflictingFlags, "forward_to cannot be a relative path when loading webhook endpoints from the API")
 		}
 		if strings.HasPrefix(cfg.ForwardConnectURL, "/") {
-			return nil, errors.New("forward_connect_to cannot be a relative path when loading webhook endpoints from the API")
+			return nil, clierrors.New(clierrors.ConflictingFlags, "forward_connect_to cannot be a relative path when loading webhook endpoints from the API")
 		}
 	} else if cfg.UseConfiguredWebhooks && len(cfg.ForwardURL) == 0 {
-		return nil, errors.New("load_from_webhooks_api requires a location to forward to with forward_to")
+		return nil, clierrors.New(clierrors.RequiredFlagMissing, "load_from_webhooks_api requires a location to forward to with forward_to")
+	}
+
+	// validate shadow-url arg
+	if len(cfg.ShadowURL) > 0 {
+		if cfg.UseConfiguredWebhooks {
+			return nil, clierrors.New(clierrors.ConflictingFlags, "shadow cannot be used when loading webhook endpoints from the API")
+		}
+		if len(cfg.ForwardURL) == 0 && len(cfg.ForwardConnectURL) == 0 {
+			return nil, clierrors.New(clierrors.RequiredFlagMissing, "shadow requires a location to forward to with forward_to to compare against")
+		}
 	}
 
 	// if no events are passed, listen for all events
@@ -487,10 +595,10 @@ func Init(ctx context.Context, cfg *Config) (*Proxy, error) {
 		// build from user's API config
 		endpoints := getEndpointsFromAPI(ctx, cfg.Key, cfg.APIBaseURL)
 		if len(endpoints.Data) == 0 {
-			return nil, errors.New("You have not defined any webhook endpoints on your account. Go to the Stripe Dashboard to add some: https://dashboard.stripe.com/test/webhooks")
+			return nil, clierrors.New(clierrors.NoWebhookEndpoints, "You have not defined any webhook endpoints on your account. Go to the Stripe Dashboard to add some: https://dashboard.stripe.com/test/webhooks")
 		}
 		var err error
-		endpointRoutes, err = buildEndpointRoutes(endpoints, parseURL(cfg.ForwardURL), parseURL(cfg.ForwardConnectURL), cfg.ForwardHeaders, cfg.ForwardConnectHeaders)
+		endpointRoutes, err = buildEndpointRoutes(endpoints, ParseURL(cfg.ForwardURL), ParseURL(cfg.ForwardConnectURL), cfg.ForwardHeaders, cfg.ForwardConnectHeaders)
 		if err != nil {
 			return nil, err
 		}
@@ -498,7 +606,7 @@ func Init(ctx context.Context, cfg *Config) (*Proxy, error) {
 		if len(cfg.ForwardURL) > 0 {
 			// non-connect endpoints
 			endpointRoutes = append(endpointRoutes, EndpointRoute{
-				URL:            parseURL(cfg.ForwardURL),
+				URL:            ParseURL(cfg.ForwardURL),
 				ForwardHeaders: cfg.ForwardHeaders,
 				Connect:        false,
 				EventTypes:     cfg.Events,
@@ -508,7 +616,7 @@ func Init(ctx context.Context, cfg *Config) (*Proxy, error) {
 		if len(cfg.ForwardConnectURL) > 0 {
 			// connect endpoints
 			endpointRoutes = append(endpointRoutes, EndpointRoute{
-				URL:            parseURL(cfg.ForwardConnectURL),
+				URL:            ParseURL(cfg.ForwardConnectURL),
 				ForwardHeaders: cfg.ForwardConnectHeaders,
 				Connect:        true,
 				EventTypes:     cfg.Events,
@@ -533,15 +641,7 @@ func Init(ctx context.Context, cfg *Config) (*Proxy, error) {
 			route.Connect,
 			route.EventTypes,
 			&EndpointConfig{
-				HTTPClient: &http.Client{
-					CheckRedirect: func(req *http.Request, via []*http.Request) error {
-						return http.ErrUseLastResponse
-					},
-					Timeout: time.Duration(cfg.Timeout) * time.Second,
-					Transport: &http.Transport{
-						TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.SkipVerify},
-					},
-				},
+				HTTPClient:      p.newEndpointHTTPClient(),
 				Log:             p.cfg.Log,
 				ResponseHandler: EndpointResponseHandlerFunc(p.processEndpointResponse),
 				OutCh:           p.cfg.OutCh,
@@ -549,9 +649,43 @@ func Init(ctx context.Context, cfg *Config) (*Proxy, error) {
 		))
 	}
 
+	if len(cfg.ShadowURL) > 0 {
+		shadowClient := NewEndpointClient(
+			ParseURL(cfg.ShadowURL),
+			cfg.ForwardHeaders,
+			false,
+			cfg.Events,
+			&EndpointConfig{
+				HTTPClient: p.newEndpointHTTPClient(),
+				Log:        p.cfg.Log,
+				OutCh:      p.cfg.OutCh,
+			},
+		)
+		p.shadow = newShadowComparer(shadowClient, cfg.ShadowSecret, p.cfg.Log, p.cfg.OutCh)
+	}
+
 	return p, nil
 }
 
+// EndpointHTTPClient returns a client configured like the one events are
+// forwarded with, with the timeout and TLS verification of the config
+func (p *Proxy) EndpointHTTPClient() *http.Client {
+	return p.newEndpointHTTPClient()
+}
+
+// newEndpointHTTPClient returns the client used to forward events to endpoints
+func (p *Proxy) newEndpointHTTPClient() *http.Client {
+	return &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Timeout: time.Duration(p.cfg.Timeout) * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: p.cfg.SkipVerify},
+		},
+	}
+}
+
 // ExtractRequestData takes an interface with request data from a Stripe event payload
 // and properly parses it into a StripeRequest struct before returning it
 func ExtractRequestData(data interface{}) (StripeRequest, error) {
@@ -579,17 +713,7 @@ func ExtractRequestData(data interface{}) (StripeRequest, error) {
 		return StripeRequest{}, nil
 	}
 
-	return StripeRequest{}, errors.New("Received malformed event from Stripe")
-}
-
-//
-// Private types
-//
-
-type eventContext struct {
-	webhookID             string
-	webhookConversationID string
-	event                 *StripeEvent
+	return StripeRequest{}, clierrors.New(clierrors.UnexpectedResponse, "Received malformed event from Stripe")
 }
 
 //
@@ -644,9 +768,9 @@ func isUTF8ContinuationByte(b byte) bool {
 }
 
 // TODO: move to some helper somewhere
-// parseURL parses the potentially incomplete URL provided in the configuration
+// ParseURL parses the potentially incomplete URL provided in the configuration
 // and returns a full URL
-func parseURL(url string) string {
+func ParseURL(url string) string {
 	_, err := strconv.Atoi(url)
 	if err == nil {
 		// If the input is just a number, assume it's a port number
@@ -721,7 +845,7 @@ func buildEndpointRoutes(endpoints requests.WebhookEndpointList, forwardURL, for
 func buildForwardURL(forwardURL string, destination *url.URL) (string, error) {
 	f, err := url.Parse(forwardURL)
 	if err != nil {
-		return "", fmt.Errorf("Provided forward url cannot be parsed: %s", forwardURL)
+		return "", clierrors.Errorf(clierrors.InvalidFlagValue, "Provided forward url cannot be parsed: %s", forwardURL)
 	}
 
 	newForwardURL := fmt.Sprintf(
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/stripe/stripe-cli/pkg/otellogs"
 	"github.com/stripe/stripe-cli/pkg/requests"
 	"github.com/stripe/stripe-cli/pkg/websocket"
 )
@@ -45,6 +46,19 @@ func TestTruncate(t *testing.T) {
 	require.Equal(t, "Hello, ...", truncate("Hello, 世界", 12, true))
 }
 
+func TestWithTraceparent(t *testing.T) {
+	headers := map[string]string{"Stripe-Signature": "t=1,v1=abc"}
+	tc := otellogs.TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+
+	traced := withTraceparent(headers, tc)
+
+	require.Equal(t, map[string]string{
+		"Stripe-Signature": "t=1,v1=abc",
+		"traceparent":      "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}, traced)
+	require.NotContains(t, headers, "traceparent")
+}
+
 func TestBuildEndpointRoutes(t *testing.T) {
 	localURL := "http://localhost"
 
@@ -130,14 +144,14 @@ func TestBuildForwardURL(t *testing.T) {
 }
 
 func TestParseUrl(t *testing.T) {
-	require.Equal(t, "http://example.com/foo", parseURL("http://example.com/foo"))
-	require.Equal(t, "https://example.com/foo", parseURL("https://example.com/foo"))
+	require.Equal(t, "http://example.com/foo", ParseURL("http://example.com/foo"))
+	require.Equal(t, "https://example.com/foo", ParseURL("https://example.com/foo"))
 
-	require.Equal(t, "http://example.com/foo", parseURL("example.com/foo"))
+	require.Equal(t, "http://example.com/foo", ParseURL("example.com/foo"))
 
-	require.Equal(t, "http://localhost/foo", parseURL("/foo"))
+	require.Equal(t, "http://localhost/foo", ParseURL("/foo"))
 
-	require.Equal(t, "http://localhost:3000", parseURL("3000"))
+	require.Equal(t, "http://localhost:3000", ParseURL("3000"))
 }
 
 func TestForwardToOnly(t *testing.T) {
@@ -0,0 +1,200 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/stripe/stripe-cli/pkg/webhooks"
+	"github.com/stripe/stripe-cli/pkg/websocket"
+)
+
+// ShadowDivergence describes an event for which the shadow endpoint responded
+// differently than the endpoint events are forwarded to
+type ShadowDivergence struct {
+	Event *StripeEvent
+
+	ForwardURL string
+	ShadowURL  string
+
+	// StatusCode and ShadowStatusCode are zero when the request failed
+	StatusCode       int
+	ShadowStatusCode int
+
+	// Body and ShadowBody are the response bodies, truncated to maxBodySize
+	Body       string
+	ShadowBody string
+
+	// Reasons lists how the responses differ
+	Reasons []string
+}
+
+// shadowResponse is the outcome of forwarding an event to either endpoint
+type shadowResponse struct {
+	statusCode int
+	body       string
+	err        error
+}
+
+// shadowPair collects both responses to an event until they can be compared
+type shadowPair struct {
+	evtCtx     EventContext
+	forwardURL string
+	primary    *shadowResponse
+	shadow     *shadowResponse
+}
+
+// shadowComparer forwards every event to a shadow endpoint as well, and
+// reports the events for which its response diverges from the endpoint's
+type shadowComparer struct {
+	client *EndpointClient
+	log    *log.Logger
+	outCh  chan websocket.IElement
+	// secret signs the events forwarded to the shadow endpoint, when it
+	// doesn't verify them with the secret of the session
+	secret string
+
+	mu      sync.Mutex
+	pending map[string]*shadowPair
+}
+
+func newShadowComparer(client *EndpointClient, secret string, logger *log.Logger, outCh chan websocket.IElement) *shadowComparer {
+	return &shadowComparer{
+		client:  client,
+		log:     logger,
+		outCh:   outCh,
+		secret:  secret,
+		pending: make(map[string]*shadowPair),
+	}
+}
+
+// post forwards the event to the shadow endpoint and records its response
+func (s *shadowComparer) post(evtCtx EventContext, body string, headers map[string]string) {
+	req, err := s.client.newRequest(body, headers)
+	if err != nil {
+		s.recordShadow(evtCtx, &shadowResponse{err: err})
+		return
+	}
+
+	// the signature of the session only verifies with the session's secret
+	if s.secret != "" {
+		req.Header.Set("Stripe-Signature", webhooks.GenerateHeader([]byte(body), s.secret, time.Now()))
+	}
+
+	resp, err := s.client.cfg.HTTPClient.Do(req)
+	if err != nil {
+		s.recordShadow(evtCtx, &shadowResponse{err: err})
+		return
+	}
+	defer resp.Body.Close()
+
+	buf, err := io.ReadAll(resp.Body)
+	s.recordShadow(evtCtx, &shadowResponse{
+		statusCode: resp.StatusCode,
+		body:       truncate(string(buf), maxBodySize, true),
+		err:        err,
+	})
+}
+
+func (s *shadowComparer) recordPrimary(evtCtx EventContext, forwardURL string, resp *shadowResponse) {
+	s.record(evtCtx, func(pair *shadowPair) {
+		pair.forwardURL = forwardURL
+		pair.primary = resp
+	})
+}
+
+func (s *shadowComparer) recordShadow(evtCtx EventContext, resp *shadowResponse) {
+	s.record(evtCtx, func(pair *shadowPair) {
+		pair.shadow = resp
+	})
+}
+
+// record stores one side of the responses to an event, and compares them once
+// both arrived
+func (s *shadowComparer) record(evtCtx EventContext, set func(*shadowPair)) {
+	s.mu.Lock()
+	pair, ok := s.pending[evtCtx.WebhookID]
+	if !ok {
+		pair = &shadowPair{evtCtx: evtCtx}
+		s.pending[evtCtx.WebhookID] = pair
+	}
+	set(pair)
+
+	complete := pair.primary != nil && pair.shadow != nil
+	if complete {
+		delete(s.pending, evtCtx.WebhookID)
+	}
+	s.mu.Unlock()
+
+	if !complete {
+		return
+	}
+
+	reasons := compareShadowResponses(pair.primary, pair.shadow)
+	if len(reasons) == 0 {
+		s.log.WithFields(log.Fields{
+			"prefix":   "proxy.shadowComparer.record",
+			"event_id": pair.evtCtx.Event.ID,
+		}).Debug("Shadow endpoint response matches")
+		return
+	}
+
+	s.outCh <- websocket.DataElement{
+		Data: ShadowDivergence{
+			Event:            pair.evtCtx.Event,
+			ForwardURL:       pair.forwardURL,
+			ShadowURL:        s.client.URL,
+			StatusCode:       pair.primary.statusCode,
+			ShadowStatusCode: pair.shadow.statusCode,
+			Body:             pair.primary.body,
+			ShadowBody:       pair.shadow.body,
+			Reasons:          reasons,
+		},
+	}
+}
+
+// compareShadowResponses returns how two responses differ, if they do
+func compareShadowResponses(primary, shadow *shadowResponse) []string {
+	var reasons []string
+
+	if primary.err != nil {
+		reasons = append(reasons, fmt.Sprintf("request to the endpoint failed: %v", primary.err))
+	}
+	if shadow.err != nil {
+		reasons = append(reasons, fmt.Sprintf("request to the shadow endpoint failed: %v", shadow.err))
+	}
+	if len(reasons) > 0 {
+		return reasons
+	}
+
+	if primary.statusCode != shadow.statusCode {
+		reasons = append(reasons, fmt.Sprintf("status code %d != %d", primary.statusCode, shadow.statusCode))
+	}
+	if normalizeBody(primary.body) != normalizeBody(shadow.body) {
+		reasons = append(reasons, "response bodies differ")
+	}
+
+	return reasons
+}
+
+// normalizeBody makes response bodies comparable regardless of whitespace
+// and, for JSON bodies, of the order of keys
+func normalizeBody(body string) string {
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(body), &parsed); err == nil {
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(parsed); err == nil {
+			return strings.TrimSpace(buf.String())
+		}
+	}
+
+	return strings.Join(strings.Fields(body), " ")
+}
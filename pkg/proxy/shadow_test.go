@@ -0,0 +1,81 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/webhooks"
+	"github.com/stripe/stripe-cli/pkg/websocket"
+)
+
+func TestCompareShadowResponses(t *testing.T) {
+	ok := &shadowResponse{statusCode: 200, body: `{"received": true, "id": 1}`}
+
+	require.Empty(t, compareShadowResponses(ok, &shadowResponse{statusCode: 200, body: `{"id":1,"received":true}`}))
+	require.Equal(t, []string{"status code 200 != 500"}, compareShadowResponses(ok, &shadowResponse{statusCode: 500, body: `{"id":1,"received":true}`}))
+	require.Equal(t, []string{"response bodies differ"}, compareShadowResponses(ok, &shadowResponse{statusCode: 200, body: `{"id":2,"received":true}`}))
+	require.Equal(t, []string{"request to the shadow endpoint failed: connection refused"}, compareShadowResponses(ok, &shadowResponse{err: errors.New("connection refused")}))
+}
+
+func TestNormalizeBody(t *testing.T) {
+	require.Equal(t, `{"a":1,"b":[1,2]}`, normalizeBody("{\n  \"b\": [1, 2],\n  \"a\": 1\n}\n"))
+	require.Equal(t, "OK !", normalizeBody("  OK\n!  "))
+}
+
+func TestShadowComparerReportsDivergence(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "t=123,v1=hunter2", r.Header.Get("Stripe-Signature"))
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"unknown event"}`))
+	}))
+	defer ts.Close()
+
+	outCh := make(chan websocket.IElement, 1)
+	s := newShadowComparer(NewEndpointClient(ts.URL, nil, false, []string{"*"}, nil), "", nil, outCh)
+
+	evtCtx := EventContext{WebhookID: "wh_123", Event: &StripeEvent{ID: "evt_123"}}
+	s.recordPrimary(evtCtx, "http://localhost:4242", &shadowResponse{statusCode: 200, body: `{"received":true}`})
+	s.post(evtCtx, "{}", map[string]string{"Stripe-Signature": "t=123,v1=hunter2"})
+
+	divergence := (<-outCh).(websocket.DataElement).Data.(ShadowDivergence)
+	require.Equal(t, "evt_123", divergence.Event.ID)
+	require.Equal(t, ts.URL, divergence.ShadowURL)
+	require.Equal(t, 400, divergence.ShadowStatusCode)
+	require.Equal(t, []string{"status code 200 != 400", "response bodies differ"}, divergence.Reasons)
+	require.Empty(t, s.pending)
+}
+
+func TestShadowComparerSignsWithShadowSecret(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header, err := webhooks.ParseHeader(r.Header.Get("Stripe-Signature"))
+		require.NoError(t, err)
+		require.Equal(t, webhooks.ComputeSignature([]byte("{}"), "whsec_shadow", header.Timestamp), header.Signatures[0])
+		w.Write([]byte(`{"received":true}`))
+	}))
+	defer ts.Close()
+
+	outCh := make(chan websocket.IElement, 1)
+	s := newShadowComparer(NewEndpointClient(ts.URL, nil, false, []string{"*"}, nil), "whsec_shadow", log.New(), outCh)
+
+	evtCtx := EventContext{WebhookID: "wh_123", Event: &StripeEvent{ID: "evt_123"}}
+	s.recordPrimary(evtCtx, "http://localhost:4242", &shadowResponse{statusCode: 200, body: `{"received":true}`})
+	s.post(evtCtx, "{}", map[string]string{"Stripe-Signature": "t=123,v1=hunter2"})
+
+	require.Empty(t, outCh)
+	require.Empty(t, s.pending)
+}
+
+func TestShadowRequiresForwardTo(t *testing.T) {
+	_, err := Init(context.Background(), &Config{ShadowURL: "https://staging.example.com"})
+	require.EqualError(t, err, "shadow requires a location to forward to with forward_to to compare against")
+
+	p, err := Init(context.Background(), &Config{ForwardURL: "4242", ShadowURL: "https://staging.example.com"})
+	require.NoError(t, err)
+	require.Equal(t, "https://staging.example.com", p.shadow.client.URL)
+}
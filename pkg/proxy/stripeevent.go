@@ -1,6 +1,10 @@
 package proxy
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/stripe/stripe-cli/pkg/otellogs"
+)
 
 // StripeEvent is a representation of a Stripe `event` object
 // we define RequestData as an interface for backwards compatibility
@@ -16,6 +20,10 @@ type StripeEvent struct {
 	Type            string                 `json:"type"`
 	RequestData     interface{}            `json:"request"`
 	Request         StripeRequest
+
+	// Trace is the trace started for the event when forwarding it with
+	// Config.PropagateTraceContext
+	Trace otellogs.TraceContext `json:"-"`
 }
 
 // StripeRequest is a representation of the Request field in a Stripe `event` object
@@ -0,0 +1,409 @@
+package query
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// argType is a bitmask of the types a function argument accepts
+type argType int
+
+const (
+	argNumber argType = 1 << iota
+	argString
+	argBoolean
+	argArray
+	argObject
+	argNull
+	argExpref
+	// argNumberArray and argStringArray are arrays whose elements all have
+	// that type
+	argNumberArray
+	argStringArray
+
+	argAny = argNumber | argString | argBoolean | argArray | argObject | argNull
+)
+
+type function struct {
+	args []argType
+	// variadic functions accept any number of arguments of their last type
+	variadic bool
+	call     func(args []interface{}) (interface{}, error)
+}
+
+// functions are the built-in functions of JMESPath
+var functions map[string]function
+
+func init() {
+	functions = map[string]function{
+		"abs":         {args: []argType{argNumber}, call: numberFunction(math.Abs)},
+		"avg":         {args: []argType{argNumberArray}, call: avg},
+		"ceil":        {args: []argType{argNumber}, call: numberFunction(math.Ceil)},
+		"contains":    {args: []argType{argArray | argString, argAny}, call: contains},
+		"ends_with":   {args: []argType{argString, argString}, call: endsWith},
+		"floor":       {args: []argType{argNumber}, call: numberFunction(math.Floor)},
+		"join":        {args: []argType{argString, argStringArray}, call: join},
+		"keys":        {args: []argType{argObject}, call: keys},
+		"length":      {args: []argType{argString | argArray | argObject}, call: length},
+		"map":         {args: []argType{argExpref, argArray}, call: mapFunction},
+		"max":         {args: []argType{argNumberArray | argStringArray}, call: extremum(1)},
+		"max_by":      {args: []argType{argArray, argExpref}, call: extremumBy(1)},
+		"merge":       {args: []argType{argObject}, variadic: true, call: merge},
+		"min":         {args: []argType{argNumberArray | argStringArray}, call: extremum(-1)},
+		"min_by":      {args: []argType{argArray, argExpref}, call: extremumBy(-1)},
+		"not_null":    {args: []argType{argAny}, variadic: true, call: notNull},
+		"reverse":     {args: []argType{argString | argArray}, call: reverse},
+		"sort":        {args: []argType{argNumberArray | argStringArray}, call: sortFunction},
+		"sort_by":     {args: []argType{argArray, argExpref}, call: sortBy},
+		"starts_with": {args: []argType{argString, argString}, call: startsWith},
+		"sum":         {args: []argType{argNumberArray}, call: sum},
+		"to_array":    {args: []argType{argAny}, call: toArray},
+		"to_number":   {args: []argType{argAny}, call: toNumberFunction},
+		"to_string":   {args: []argType{argAny}, call: toString},
+		"type":        {args: []argType{argAny}, call: typeFunction},
+		"values":      {args: []argType{argObject}, call: values},
+	}
+}
+
+func callFunction(name string, args []interface{}) (interface{}, error) {
+	f := functions[name]
+
+	if len(args) < len(f.args) || (!f.variadic && len(args) > len(f.args)) {
+		expected := strconv.Itoa(len(f.args))
+		if f.variadic {
+			expected = "at least " + expected
+		}
+		return nil, fmt.Errorf("%s() takes %s arguments, got %d", name, expected, len(args))
+	}
+
+	for i, arg := range args {
+		accepted := f.args[len(f.args)-1]
+		if i < len(f.args) {
+			accepted = f.args[i]
+		}
+
+		if !accepts(accepted, arg) {
+			return nil, fmt.Errorf("invalid type for argument %d of %s(): %s", i+1, name, typeOf(arg))
+		}
+	}
+
+	return f.call(args)
+}
+
+func accepts(accepted argType, arg interface{}) bool {
+	var actual argType
+
+	switch typeOf(arg) {
+	case "number":
+		actual = argNumber
+	case "string":
+		actual = argString
+	case "boolean":
+		actual = argBoolean
+	case "null":
+		actual = argNull
+	case "object":
+		actual = argObject
+	case "expref":
+		actual = argExpref
+	case "array":
+		actual = argArray
+		array := arg.([]interface{})
+		if allOfType(array, "number") {
+			actual |= argNumberArray
+		}
+		if allOfType(array, "string") {
+			actual |= argStringArray
+		}
+	}
+
+	return accepted&actual != 0
+}
+
+func allOfType(array []interface{}, typ string) bool {
+	for _, elem := range array {
+		if typeOf(elem) != typ {
+			return false
+		}
+	}
+
+	return true
+}
+
+func numberFunction(f func(float64) float64) func(args []interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		n, _ := toNumber(args[0])
+		return f(n), nil
+	}
+}
+
+func sum(args []interface{}) (interface{}, error) {
+	total := 0.0
+	for _, elem := range args[0].([]interface{}) {
+		n, _ := toNumber(elem)
+		total += n
+	}
+
+	return total, nil
+}
+
+func avg(args []interface{}) (interface{}, error) {
+	array := args[0].([]interface{})
+	if len(array) == 0 {
+		return nil, nil
+	}
+
+	total, _ := sum(args)
+
+	return total.(float64) / float64(len(array)), nil
+}
+
+func contains(args []interface{}) (interface{}, error) {
+	if s, ok := args[0].(string); ok {
+		search, ok := args[1].(string)
+		return ok && strings.Contains(s, search), nil
+	}
+
+	for _, elem := range args[0].([]interface{}) {
+		if equal(elem, args[1]) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func startsWith(args []interface{}) (interface{}, error) {
+	return strings.HasPrefix(args[0].(string), args[1].(string)), nil
+}
+
+func endsWith(args []interface{}) (interface{}, error) {
+	return strings.HasSuffix(args[0].(string), args[1].(string)), nil
+}
+
+func join(args []interface{}) (interface{}, error) {
+	var parts []string
+	for _, elem := range args[1].([]interface{}) {
+		parts = append(parts, elem.(string))
+	}
+
+	return strings.Join(parts, args[0].(string)), nil
+}
+
+func keys(args []interface{}) (interface{}, error) {
+	obj := args[0].(*object)
+
+	result := make([]interface{}, 0, len(obj.keys))
+	for _, key := range obj.keys {
+		result = append(result, key)
+	}
+
+	return result, nil
+}
+
+func values(args []interface{}) (interface{}, error) {
+	return args[0].(*object).valueList(), nil
+}
+
+func length(args []interface{}) (interface{}, error) {
+	switch v := args[0].(type) {
+	case string:
+		return float64(utf8.RuneCountInString(v)), nil
+	case []interface{}:
+		return float64(len(v)), nil
+	default:
+		return float64(len(v.(*object).keys)), nil
+	}
+}
+
+func mapFunction(args []interface{}) (interface{}, error) {
+	ref := args[0].(expref)
+
+	result := []interface{}{}
+	for _, elem := range args[1].([]interface{}) {
+		value, err := search(ref.ast, elem)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+
+	return result, nil
+}
+
+func merge(args []interface{}) (interface{}, error) {
+	merged := newObject()
+	for _, arg := range args {
+		obj := arg.(*object)
+		for _, key := range obj.keys {
+			merged.set(key, obj.values[key])
+		}
+	}
+
+	return merged, nil
+}
+
+func notNull(args []interface{}) (interface{}, error) {
+	for _, arg := range args {
+		if arg != nil {
+			return arg, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func reverse(args []interface{}) (interface{}, error) {
+	if s, ok := args[0].(string); ok {
+		runes := []rune(s)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes), nil
+	}
+
+	array := args[0].([]interface{})
+	result := make([]interface{}, len(array))
+	for i, elem := range array {
+		result[len(array)-1-i] = elem
+	}
+
+	return result, nil
+}
+
+// less orders two numbers or two strings
+func less(a, b interface{}) bool {
+	if na, ok := toNumber(a); ok {
+		nb, _ := toNumber(b)
+		return na < nb
+	}
+
+	return a.(string) < b.(string)
+}
+
+func sortFunction(args []interface{}) (interface{}, error) {
+	sorted := append([]interface{}{}, args[0].([]interface{})...)
+	sort.SliceStable(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+
+	return sorted, nil
+}
+
+// keysBy evaluates the expression of a *_by function against every element,
+// which must all give numbers or all give strings
+func keysBy(name string, array []interface{}, ref expref) ([]interface{}, error) {
+	keys := make([]interface{}, 0, len(array))
+	for _, elem := range array {
+		key, err := search(ref.ast, elem)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if !allOfType(keys, "number") && !allOfType(keys, "string") {
+		return nil, fmt.Errorf("the expression of %s() must give only numbers or only strings", name)
+	}
+
+	return keys, nil
+}
+
+func sortBy(args []interface{}) (interface{}, error) {
+	array := args[0].([]interface{})
+
+	keys, err := keysBy("sort_by", array, args[1].(expref))
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make([]int, len(array))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool { return less(keys[indexes[i]], keys[indexes[j]]) })
+
+	sorted := make([]interface{}, 0, len(array))
+	for _, i := range indexes {
+		sorted = append(sorted, array[i])
+	}
+
+	return sorted, nil
+}
+
+// extremum returns max for a direction of 1, and min for -1
+func extremum(direction int) func(args []interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		var best interface{}
+		for _, elem := range args[0].([]interface{}) {
+			if best == nil || (direction > 0 && less(best, elem)) || (direction < 0 && less(elem, best)) {
+				best = elem
+			}
+		}
+
+		return best, nil
+	}
+}
+
+// extremumBy returns max_by for a direction of 1, and min_by for -1
+func extremumBy(direction int) func(args []interface{}) (interface{}, error) {
+	name := map[int]string{1: "max_by", -1: "min_by"}[direction]
+
+	return func(args []interface{}) (interface{}, error) {
+		array := args[0].([]interface{})
+
+		keys, err := keysBy(name, array, args[1].(expref))
+		if err != nil {
+			return nil, err
+		}
+
+		best := -1
+		for i := range array {
+			if best < 0 || (direction > 0 && less(keys[best], keys[i])) || (direction < 0 && less(keys[i], keys[best])) {
+				best = i
+			}
+		}
+
+		if best < 0 {
+			return nil, nil
+		}
+
+		return array[best], nil
+	}
+}
+
+func toArray(args []interface{}) (interface{}, error) {
+	if array, ok := args[0].([]interface{}); ok {
+		return array, nil
+	}
+
+	return []interface{}{args[0]}, nil
+}
+
+func toNumberFunction(args []interface{}) (interface{}, error) {
+	if _, ok := toNumber(args[0]); ok {
+		return args[0], nil
+	}
+
+	if s, ok := args[0].(string); ok {
+		if n, err := strconv.ParseFloat(s, 64); err == nil {
+			return n, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func toString(args []interface{}) (interface{}, error) {
+	if s, ok := args[0].(string); ok {
+		return s, nil
+	}
+
+	return string(encodeJSON(args[0])), nil
+}
+
+func typeFunction(args []interface{}) (interface{}, error) {
+	return typeOf(args[0]), nil
+}
@@ -0,0 +1,266 @@
+package query
+
+func search(n node, value interface{}) (interface{}, error) {
+	switch n.typ {
+	case nodeCurrent:
+		return value, nil
+	case nodeLiteral:
+		return n.value, nil
+	case nodeField:
+		if obj, ok := value.(*object); ok {
+			return obj.values[n.value.(string)], nil
+		}
+		return nil, nil
+	case nodeSubexpression, nodeIndexExpression, nodePipe:
+		left, err := search(n.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		return search(n.children[1], left)
+	case nodeIndex:
+		array, ok := value.([]interface{})
+		if !ok {
+			return nil, nil
+		}
+		index := n.value.(int)
+		if index < 0 {
+			index += len(array)
+		}
+		if index < 0 || index >= len(array) {
+			return nil, nil
+		}
+		return array[index], nil
+	case nodeSlice:
+		array, ok := value.([]interface{})
+		if !ok {
+			return nil, nil
+		}
+		return slice(array, n.value.([]*int)), nil
+	case nodeProjection:
+		base, err := search(n.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		array, ok := base.([]interface{})
+		if !ok {
+			return nil, nil
+		}
+		return project(array, n.children[1], nil)
+	case nodeValueProjection:
+		base, err := search(n.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		obj, ok := base.(*object)
+		if !ok {
+			return nil, nil
+		}
+		return project(obj.valueList(), n.children[1], nil)
+	case nodeFilterProjection:
+		base, err := search(n.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		array, ok := base.([]interface{})
+		if !ok {
+			return nil, nil
+		}
+		return project(array, n.children[1], &n.children[2])
+	case nodeFlatten:
+		base, err := search(n.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		array, ok := base.([]interface{})
+		if !ok {
+			return nil, nil
+		}
+		flattened := []interface{}{}
+		for _, elem := range array {
+			if inner, ok := elem.([]interface{}); ok {
+				flattened = append(flattened, inner...)
+			} else {
+				flattened = append(flattened, elem)
+			}
+		}
+		return flattened, nil
+	case nodeMultiSelectList:
+		if value == nil {
+			return nil, nil
+		}
+		list := make([]interface{}, 0, len(n.children))
+		for _, child := range n.children {
+			result, err := search(child, value)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, result)
+		}
+		return list, nil
+	case nodeMultiSelectHash:
+		if value == nil {
+			return nil, nil
+		}
+		hash := newObject()
+		for _, pair := range n.children {
+			result, err := search(pair.children[0], value)
+			if err != nil {
+				return nil, err
+			}
+			hash.set(pair.value.(string), result)
+		}
+		return hash, nil
+	case nodeComparator:
+		left, err := search(n.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		right, err := search(n.children[1], value)
+		if err != nil {
+			return nil, err
+		}
+		return compare(n.value.(tokenType), left, right), nil
+	case nodeOr, nodeAnd:
+		left, err := search(n.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		if isTruthy(left) == (n.typ == nodeOr) {
+			return left, nil
+		}
+		return search(n.children[1], value)
+	case nodeNot:
+		result, err := search(n.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		return !isTruthy(result), nil
+	case nodeExpref:
+		return expref{ast: n.children[0]}, nil
+	case nodeFunction:
+		args := make([]interface{}, 0, len(n.children))
+		for _, child := range n.children {
+			arg, err := search(child, value)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+		}
+		return callFunction(n.value.(string), args)
+	}
+
+	return nil, nil
+}
+
+// project evaluates right against the elements of array matching condition,
+// dropping null results
+func project(array []interface{}, right node, condition *node) (interface{}, error) {
+	results := []interface{}{}
+
+	for _, elem := range array {
+		if condition != nil {
+			matched, err := search(*condition, elem)
+			if err != nil {
+				return nil, err
+			}
+			if !isTruthy(matched) {
+				continue
+			}
+		}
+
+		result, err := search(right, elem)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+func (o *object) valueList() []interface{} {
+	values := make([]interface{}, 0, len(o.keys))
+	for _, key := range o.keys {
+		values = append(values, o.values[key])
+	}
+
+	return values
+}
+
+// compare applies a comparator. Ordering comparators only apply to numbers,
+// and return null for anything else.
+func compare(comparator tokenType, left, right interface{}) interface{} {
+	switch comparator {
+	case tokenEQ:
+		return equal(left, right)
+	case tokenNE:
+		return !equal(left, right)
+	}
+
+	l, ok := toNumber(left)
+	if !ok {
+		return nil
+	}
+	r, ok := toNumber(right)
+	if !ok {
+		return nil
+	}
+
+	switch comparator {
+	case tokenLT:
+		return l < r
+	case tokenLTE:
+		return l <= r
+	case tokenGT:
+		return l > r
+	default:
+		return l >= r
+	}
+}
+
+func slice(array []interface{}, parts []*int) []interface{} {
+	step := 1
+	if parts[2] != nil {
+		step = *parts[2]
+	}
+
+	length := len(array)
+
+	bound := func(part *int, defaultValue int) int {
+		if part == nil {
+			return defaultValue
+		}
+		n := *part
+		if n < 0 {
+			n += length
+			if n < 0 {
+				if step < 0 {
+					return -1
+				}
+				return 0
+			}
+		} else if n >= length {
+			if step < 0 {
+				return length - 1
+			}
+			return length
+		}
+		return n
+	}
+
+	var start, stop int
+	if step > 0 {
+		start, stop = bound(parts[0], 0), bound(parts[1], length)
+	} else {
+		start, stop = bound(parts[0], length-1), bound(parts[1], -1)
+	}
+
+	result := []interface{}{}
+	for i := start; (step > 0 && i < stop) || (step < 0 && i > stop); i += step {
+		result = append(result, array[i])
+	}
+
+	return result
+}
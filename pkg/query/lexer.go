@@ -0,0 +1,264 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenIdentifier
+	tokenQuotedIdentifier
+	tokenRawString
+	tokenLiteral
+	tokenNumber
+	tokenDot
+	tokenStar
+	tokenFlatten
+	tokenFilter
+	tokenLBracket
+	tokenRBracket
+	tokenLBrace
+	tokenRBrace
+	tokenLParen
+	tokenRParen
+	tokenComma
+	tokenColon
+	tokenPipe
+	tokenOr
+	tokenAnd
+	tokenNot
+	tokenExpref
+	tokenCurrent
+	tokenEQ
+	tokenNE
+	tokenLT
+	tokenLTE
+	tokenGT
+	tokenGTE
+)
+
+var tokenNames = map[tokenType]string{
+	tokenEOF:              "the end of the expression",
+	tokenIdentifier:       "an identifier",
+	tokenQuotedIdentifier: "a quoted identifier",
+	tokenRawString:        "a raw string",
+	tokenLiteral:          "a literal",
+	tokenNumber:           "a number",
+	tokenDot:              "'.'",
+	tokenStar:             "'*'",
+	tokenFlatten:          "'[]'",
+	tokenFilter:           "'[?'",
+	tokenLBracket:         "'['",
+	tokenRBracket:         "']'",
+	tokenLBrace:           "'{'",
+	tokenRBrace:           "'}'",
+	tokenLParen:           "'('",
+	tokenRParen:           "')'",
+	tokenComma:            "','",
+	tokenColon:            "':'",
+	tokenPipe:             "'|'",
+	tokenOr:               "'||'",
+	tokenAnd:              "'&&'",
+	tokenNot:              "'!'",
+	tokenExpref:           "'&'",
+	tokenCurrent:          "'@'",
+	tokenEQ:               "'=='",
+	tokenNE:               "'!='",
+	tokenLT:               "'<'",
+	tokenLTE:              "'<='",
+	tokenGT:               "'>'",
+	tokenGTE:              "'>='",
+}
+
+func (t tokenType) String() string {
+	return tokenNames[t]
+}
+
+type token struct {
+	typ      tokenType
+	text     string
+	value    interface{}
+	position int
+}
+
+// SyntaxError is returned for an expression that can't be parsed
+type SyntaxError struct {
+	Expression string
+	Position   int
+	Message    string
+}
+
+func (e SyntaxError) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s\n  %s\n  %s^", e.Position, e.Message, e.Expression, strings.Repeat(" ", e.Position))
+}
+
+var simpleTokens = map[byte]tokenType{
+	'.': tokenDot,
+	'*': tokenStar,
+	']': tokenRBracket,
+	'{': tokenLBrace,
+	'}': tokenRBrace,
+	'(': tokenLParen,
+	')': tokenRParen,
+	',': tokenComma,
+	':': tokenColon,
+	'@': tokenCurrent,
+}
+
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+
+	syntaxError := func(position int, format string, args ...interface{}) error {
+		return SyntaxError{Expression: expression, Position: position, Message: fmt.Sprintf(format, args...)}
+	}
+
+	for i := 0; i < len(expression); {
+		c := expression[i]
+
+		if typ, ok := simpleTokens[c]; ok {
+			tokens = append(tokens, token{typ: typ, text: string(c), position: i})
+			i++
+			continue
+		}
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isIdentifierStart(c):
+			start := i
+			for i < len(expression) && (isIdentifierStart(expression[i]) || isDigit(expression[i])) {
+				i++
+			}
+			tokens = append(tokens, token{typ: tokenIdentifier, text: expression[start:i], value: expression[start:i], position: start})
+		case c == '-' || isDigit(c):
+			start := i
+			i++
+			for i < len(expression) && isDigit(expression[i]) {
+				i++
+			}
+			n, err := strconv.Atoi(expression[start:i])
+			if err != nil {
+				return nil, syntaxError(start, "invalid number %s", expression[start:i])
+			}
+			tokens = append(tokens, token{typ: tokenNumber, text: expression[start:i], value: n, position: start})
+		case c == '[':
+			switch {
+			case strings.HasPrefix(expression[i:], "[]"):
+				tokens = append(tokens, token{typ: tokenFlatten, text: "[]", position: i})
+				i += 2
+			case strings.HasPrefix(expression[i:], "[?"):
+				tokens = append(tokens, token{typ: tokenFilter, text: "[?", position: i})
+				i += 2
+			default:
+				tokens = append(tokens, token{typ: tokenLBracket, text: "[", position: i})
+				i++
+			}
+		case c == '"':
+			end, err := findClosing(expression, i, '"')
+			if err != nil {
+				return nil, syntaxError(i, "unterminated quoted identifier")
+			}
+			var value string
+			if err := json.Unmarshal([]byte(expression[i:end+1]), &value); err != nil {
+				return nil, syntaxError(i, "invalid quoted identifier: %s", err)
+			}
+			tokens = append(tokens, token{typ: tokenQuotedIdentifier, text: expression[i : end+1], value: value, position: i})
+			i = end + 1
+		case c == '\'':
+			end, err := findClosing(expression, i, '\'')
+			if err != nil {
+				return nil, syntaxError(i, "unterminated raw string")
+			}
+			value := strings.ReplaceAll(expression[i+1:end], `\'`, `'`)
+			tokens = append(tokens, token{typ: tokenRawString, text: expression[i : end+1], value: value, position: i})
+			i = end + 1
+		case c == '`':
+			end, err := findClosing(expression, i, '`')
+			if err != nil {
+				return nil, syntaxError(i, "unterminated literal")
+			}
+			value, err := decodeJSON([]byte(strings.ReplaceAll(expression[i+1:end], "\\`", "`")))
+			if err != nil {
+				return nil, syntaxError(i, "invalid literal: %s", err)
+			}
+			tokens = append(tokens, token{typ: tokenLiteral, text: expression[i : end+1], value: value, position: i})
+			i = end + 1
+		case c == '|':
+			if strings.HasPrefix(expression[i:], "||") {
+				tokens = append(tokens, token{typ: tokenOr, text: "||", position: i})
+				i += 2
+			} else {
+				tokens = append(tokens, token{typ: tokenPipe, text: "|", position: i})
+				i++
+			}
+		case c == '&':
+			if strings.HasPrefix(expression[i:], "&&") {
+				tokens = append(tokens, token{typ: tokenAnd, text: "&&", position: i})
+				i += 2
+			} else {
+				tokens = append(tokens, token{typ: tokenExpref, text: "&", position: i})
+				i++
+			}
+		case c == '!' || c == '<' || c == '>' || c == '=':
+			typ, text := comparatorToken(expression[i:])
+			if text == "" {
+				return nil, syntaxError(i, "unexpected character '%c'", c)
+			}
+			tokens = append(tokens, token{typ: typ, text: text, position: i})
+			i += len(text)
+		default:
+			return nil, syntaxError(i, "unexpected character '%c'", c)
+		}
+	}
+
+	return append(tokens, token{typ: tokenEOF, position: len(expression)}), nil
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func comparatorToken(s string) (tokenType, string) {
+	switch {
+	case strings.HasPrefix(s, "=="):
+		return tokenEQ, "=="
+	case strings.HasPrefix(s, "!="):
+		return tokenNE, "!="
+	case strings.HasPrefix(s, "<="):
+		return tokenLTE, "<="
+	case strings.HasPrefix(s, ">="):
+		return tokenGTE, ">="
+	case strings.HasPrefix(s, "<"):
+		return tokenLT, "<"
+	case strings.HasPrefix(s, ">"):
+		return tokenGT, ">"
+	case strings.HasPrefix(s, "!"):
+		return tokenNot, "!"
+	default:
+		return tokenEOF, ""
+	}
+}
+
+// findClosing returns the position of the delimiter closing the one at start,
+// skipping the ones escaped with a backslash
+func findClosing(expression string, start int, delimiter byte) (int, error) {
+	for i := start + 1; i < len(expression); i++ {
+		switch expression[i] {
+		case '\\':
+			i++
+		case delimiter:
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unterminated")
+}
@@ -0,0 +1,475 @@
+package query
+
+import "fmt"
+
+type nodeType int
+
+const (
+	nodeCurrent nodeType = iota
+	nodeField
+	nodeLiteral
+	nodeSubexpression
+	nodeIndexExpression
+	nodeIndex
+	nodeSlice
+	nodeProjection
+	nodeValueProjection
+	nodeFilterProjection
+	nodeFlatten
+	nodeMultiSelectList
+	nodeMultiSelectHash
+	nodeKeyValue
+	nodeComparator
+	nodeOr
+	nodeAnd
+	nodeNot
+	nodePipe
+	nodeFunction
+	nodeExpref
+)
+
+type node struct {
+	typ      nodeType
+	value    interface{}
+	children []node
+}
+
+// bindingPowers are the precedences of the tokens, as defined by the
+// JMESPath specification. Tokens below projectionStop end a projection.
+var bindingPowers = map[tokenType]int{
+	tokenPipe:     1,
+	tokenOr:       2,
+	tokenAnd:      3,
+	tokenEQ:       5,
+	tokenNE:       5,
+	tokenLT:       5,
+	tokenLTE:      5,
+	tokenGT:       5,
+	tokenGTE:      5,
+	tokenFlatten:  9,
+	tokenStar:     20,
+	tokenFilter:   21,
+	tokenDot:      40,
+	tokenNot:      45,
+	tokenLBrace:   50,
+	tokenLBracket: 55,
+	tokenLParen:   60,
+}
+
+const projectionStop = 10
+
+type parser struct {
+	expression string
+	tokens     []token
+	index      int
+}
+
+func parse(expression string) (node, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return node{}, err
+	}
+
+	p := &parser{expression: expression, tokens: tokens}
+
+	ast, err := p.parseExpression(0)
+	if err != nil {
+		return node{}, err
+	}
+
+	if p.current().typ != tokenEOF {
+		return node{}, p.unexpected()
+	}
+
+	return ast, nil
+}
+
+func (p *parser) current() token {
+	return p.tokens[p.index]
+}
+
+func (p *parser) lookahead(n int) tokenType {
+	if p.index+n >= len(p.tokens) {
+		return tokenEOF
+	}
+
+	return p.tokens[p.index+n].typ
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.index]
+	if t.typ != tokenEOF {
+		p.index++
+	}
+
+	return t
+}
+
+func (p *parser) match(typ tokenType) error {
+	if p.current().typ != typ {
+		return p.errorf("expected %s, got %s", typ, p.describe(p.current()))
+	}
+
+	p.advance()
+
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return p.errorAt(p.current(), format, args...)
+}
+
+func (p *parser) errorAt(t token, format string, args ...interface{}) error {
+	return SyntaxError{
+		Expression: p.expression,
+		Position:   t.position,
+		Message:    fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *parser) unexpected() error {
+	return p.unexpectedToken(p.current())
+}
+
+func (p *parser) unexpectedToken(t token) error {
+	return p.errorAt(t, "unexpected %s", p.describe(t))
+}
+
+func (p *parser) describe(t token) string {
+	if t.text == "" {
+		return t.typ.String()
+	}
+
+	return fmt.Sprintf("%s %s", t.typ, t.text)
+}
+
+func (p *parser) parseExpression(bindingPower int) (node, error) {
+	left, err := p.nud(p.advance())
+	if err != nil {
+		return node{}, err
+	}
+
+	for bindingPower < bindingPowers[p.current().typ] {
+		left, err = p.led(p.advance(), left)
+		if err != nil {
+			return node{}, err
+		}
+	}
+
+	return left, nil
+}
+
+// nud parses the expressions starting with t
+func (p *parser) nud(t token) (node, error) {
+	switch t.typ {
+	case tokenLiteral, tokenRawString:
+		return node{typ: nodeLiteral, value: t.value}, nil
+	case tokenIdentifier:
+		return node{typ: nodeField, value: t.value}, nil
+	case tokenQuotedIdentifier:
+		if p.current().typ == tokenLParen {
+			return node{}, p.errorAt(t, "quoted identifiers can't be used as function names")
+		}
+		return node{typ: nodeField, value: t.value}, nil
+	case tokenCurrent:
+		return node{typ: nodeCurrent}, nil
+	case tokenStar:
+		right, err := p.parseProjectionRHS(bindingPowers[tokenStar])
+		if err != nil {
+			return node{}, err
+		}
+		return node{typ: nodeValueProjection, children: []node{{typ: nodeCurrent}, right}}, nil
+	case tokenFilter:
+		return p.parseFilter(node{typ: nodeCurrent})
+	case tokenLBrace:
+		return p.parseMultiSelectHash()
+	case tokenLParen:
+		expression, err := p.parseExpression(0)
+		if err != nil {
+			return node{}, err
+		}
+		return expression, p.match(tokenRParen)
+	case tokenFlatten:
+		return p.parseFlatten(node{typ: nodeCurrent})
+	case tokenNot:
+		expression, err := p.parseExpression(bindingPowers[tokenNot])
+		if err != nil {
+			return node{}, err
+		}
+		return node{typ: nodeNot, children: []node{expression}}, nil
+	case tokenExpref:
+		expression, err := p.parseExpression(0)
+		if err != nil {
+			return node{}, err
+		}
+		return node{typ: nodeExpref, children: []node{expression}}, nil
+	case tokenLBracket:
+		switch {
+		case p.current().typ == tokenNumber || p.current().typ == tokenColon:
+			return p.parseIndexExpression(node{typ: nodeCurrent})
+		case p.current().typ == tokenStar && p.lookahead(1) == tokenRBracket:
+			p.advance()
+			p.advance()
+			right, err := p.parseProjectionRHS(bindingPowers[tokenStar])
+			if err != nil {
+				return node{}, err
+			}
+			return node{typ: nodeProjection, children: []node{{typ: nodeCurrent}, right}}, nil
+		default:
+			return p.parseMultiSelectList()
+		}
+	}
+
+	return node{}, p.unexpectedToken(t)
+}
+
+// led parses the expressions continuing left with t
+func (p *parser) led(t token, left node) (node, error) {
+	switch t.typ {
+	case tokenDot:
+		if p.current().typ == tokenStar {
+			p.advance()
+			right, err := p.parseProjectionRHS(bindingPowers[tokenDot])
+			if err != nil {
+				return node{}, err
+			}
+			return node{typ: nodeValueProjection, children: []node{left, right}}, nil
+		}
+		right, err := p.parseDotRHS(bindingPowers[tokenDot])
+		if err != nil {
+			return node{}, err
+		}
+		return node{typ: nodeSubexpression, children: []node{left, right}}, nil
+	case tokenPipe, tokenOr, tokenAnd:
+		right, err := p.parseExpression(bindingPowers[t.typ])
+		if err != nil {
+			return node{}, err
+		}
+		typ := map[tokenType]nodeType{tokenPipe: nodePipe, tokenOr: nodeOr, tokenAnd: nodeAnd}[t.typ]
+		return node{typ: typ, children: []node{left, right}}, nil
+	case tokenEQ, tokenNE, tokenLT, tokenLTE, tokenGT, tokenGTE:
+		right, err := p.parseExpression(bindingPowers[t.typ])
+		if err != nil {
+			return node{}, err
+		}
+		return node{typ: nodeComparator, value: t.typ, children: []node{left, right}}, nil
+	case tokenLParen:
+		if left.typ != nodeField {
+			return node{}, p.errorAt(t, "only names can be called as functions")
+		}
+		return p.parseFunction(left.value.(string), t)
+	case tokenFilter:
+		return p.parseFilter(left)
+	case tokenFlatten:
+		return p.parseFlatten(left)
+	case tokenLBracket:
+		if p.current().typ == tokenNumber || p.current().typ == tokenColon {
+			return p.parseIndexExpression(left)
+		}
+		if p.current().typ != tokenStar {
+			return node{}, p.errorf("expected an index, a slice or '*', got %s", p.describe(p.current()))
+		}
+		p.advance()
+		if err := p.match(tokenRBracket); err != nil {
+			return node{}, err
+		}
+		right, err := p.parseProjectionRHS(bindingPowers[tokenStar])
+		if err != nil {
+			return node{}, err
+		}
+		return node{typ: nodeProjection, children: []node{left, right}}, nil
+	}
+
+	return node{}, p.unexpectedToken(t)
+}
+
+func (p *parser) parseFilter(left node) (node, error) {
+	condition, err := p.parseExpression(0)
+	if err != nil {
+		return node{}, err
+	}
+
+	if err := p.match(tokenRBracket); err != nil {
+		return node{}, err
+	}
+
+	right := node{typ: nodeCurrent}
+	if p.current().typ != tokenFlatten {
+		right, err = p.parseProjectionRHS(bindingPowers[tokenFilter])
+		if err != nil {
+			return node{}, err
+		}
+	}
+
+	return node{typ: nodeFilterProjection, children: []node{left, right, condition}}, nil
+}
+
+func (p *parser) parseFlatten(left node) (node, error) {
+	right, err := p.parseProjectionRHS(bindingPowers[tokenFlatten])
+	if err != nil {
+		return node{}, err
+	}
+
+	return node{typ: nodeProjection, children: []node{{typ: nodeFlatten, children: []node{left}}, right}}, nil
+}
+
+// parseIndexExpression parses an index or a slice, after its opening bracket.
+// Slices are projected over like [*].
+func (p *parser) parseIndexExpression(left node) (node, error) {
+	if p.current().typ == tokenColon || p.lookahead(1) == tokenColon {
+		slice, err := p.parseSlice()
+		if err != nil {
+			return node{}, err
+		}
+
+		right, err := p.parseProjectionRHS(bindingPowers[tokenStar])
+		if err != nil {
+			return node{}, err
+		}
+
+		return node{typ: nodeProjection, children: []node{{typ: nodeIndexExpression, children: []node{left, slice}}, right}}, nil
+	}
+
+	index := node{typ: nodeIndex, value: p.advance().value}
+	if err := p.match(tokenRBracket); err != nil {
+		return node{}, err
+	}
+
+	return node{typ: nodeIndexExpression, children: []node{left, index}}, nil
+}
+
+func (p *parser) parseSlice() (node, error) {
+	parts := make([]*int, 3)
+	part := 0
+
+	for p.current().typ != tokenRBracket {
+		switch p.current().typ {
+		case tokenColon:
+			part++
+			if part > 2 {
+				return node{}, p.errorf("too many colons in slice")
+			}
+			p.advance()
+		case tokenNumber:
+			n := p.advance().value.(int)
+			parts[part] = &n
+		default:
+			return node{}, p.unexpected()
+		}
+	}
+
+	p.advance()
+
+	if parts[2] != nil && *parts[2] == 0 {
+		return node{}, p.errorf("slice step can't be 0")
+	}
+
+	return node{typ: nodeSlice, value: parts}, nil
+}
+
+func (p *parser) parseMultiSelectList() (node, error) {
+	var expressions []node
+
+	for {
+		expression, err := p.parseExpression(0)
+		if err != nil {
+			return node{}, err
+		}
+		expressions = append(expressions, expression)
+
+		if p.current().typ == tokenRBracket {
+			p.advance()
+			return node{typ: nodeMultiSelectList, children: expressions}, nil
+		}
+
+		if err := p.match(tokenComma); err != nil {
+			return node{}, err
+		}
+	}
+}
+
+func (p *parser) parseMultiSelectHash() (node, error) {
+	var pairs []node
+
+	for {
+		key := p.current()
+		if key.typ != tokenIdentifier && key.typ != tokenQuotedIdentifier {
+			return node{}, p.errorf("expected a key, got %s", p.describe(key))
+		}
+		p.advance()
+
+		if err := p.match(tokenColon); err != nil {
+			return node{}, err
+		}
+
+		value, err := p.parseExpression(0)
+		if err != nil {
+			return node{}, err
+		}
+		pairs = append(pairs, node{typ: nodeKeyValue, value: key.value, children: []node{value}})
+
+		if p.current().typ == tokenRBrace {
+			p.advance()
+			return node{typ: nodeMultiSelectHash, children: pairs}, nil
+		}
+
+		if err := p.match(tokenComma); err != nil {
+			return node{}, err
+		}
+	}
+}
+
+func (p *parser) parseFunction(name string, lparen token) (node, error) {
+	if _, ok := functions[name]; !ok {
+		return node{}, p.errorAt(lparen, "unknown function %s()", name)
+	}
+
+	var args []node
+
+	for p.current().typ != tokenRParen {
+		arg, err := p.parseExpression(0)
+		if err != nil {
+			return node{}, err
+		}
+		args = append(args, arg)
+
+		if p.current().typ == tokenComma {
+			p.advance()
+		} else if p.current().typ != tokenRParen {
+			return node{}, p.errorf("expected ',' or ')', got %s", p.describe(p.current()))
+		}
+	}
+
+	p.advance()
+
+	return node{typ: nodeFunction, value: name, children: args}, nil
+}
+
+func (p *parser) parseProjectionRHS(bindingPower int) (node, error) {
+	switch current := p.current().typ; {
+	case bindingPowers[current] < projectionStop:
+		return node{typ: nodeCurrent}, nil
+	case current == tokenLBracket, current == tokenFilter:
+		return p.parseExpression(bindingPower)
+	case current == tokenDot:
+		p.advance()
+		return p.parseDotRHS(bindingPower)
+	default:
+		return node{}, p.unexpected()
+	}
+}
+
+func (p *parser) parseDotRHS(bindingPower int) (node, error) {
+	switch p.current().typ {
+	case tokenIdentifier, tokenQuotedIdentifier, tokenStar:
+		return p.parseExpression(bindingPower)
+	case tokenLBracket:
+		p.advance()
+		return p.parseMultiSelectList()
+	case tokenLBrace:
+		p.advance()
+		return p.parseMultiSelectHash()
+	default:
+		return node{}, p.errorf("expected an identifier, '[' or '{' after '.', got %s", p.describe(p.current()))
+	}
+}
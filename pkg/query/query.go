@@ -0,0 +1,129 @@
+// Package query implements JMESPath (https://jmespath.org), to select and
+// reshape the JSON printed by commands with their --query flag.
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// Query is a compiled JMESPath expression
+type Query struct {
+	expression string
+	ast        node
+}
+
+// Compile parses a JMESPath expression, such as data[].{id:id,amount:amount}
+func Compile(expression string) (*Query, error) {
+	ast, err := parse(expression)
+	if err != nil {
+		return nil, clierrors.Wrap(clierrors.InvalidQuery, err)
+	}
+
+	return &Query{expression: expression, ast: ast}, nil
+}
+
+func (q *Query) String() string {
+	return q.expression
+}
+
+// Search applies the query to a JSON document and returns its result encoded
+// as compact JSON
+func (q *Query) Search(data []byte) ([]byte, error) {
+	result, err := q.search(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeJSON(result), nil
+}
+
+// Apply applies the query to a JSON document and formats its result for
+// printing: as indented JSON, or when raw is set, with strings unquoted,
+// null as nothing and the elements of arrays on their own lines, so the
+// output can be used in shell scripts.
+func (q *Query) Apply(data []byte, raw bool) (string, error) {
+	result, err := q.search(data)
+	if err != nil {
+		return "", err
+	}
+
+	if raw {
+		return formatRaw(result), nil
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, encodeJSON(result), "", "  "); err != nil {
+		return "", err
+	}
+
+	return indented.String(), nil
+}
+
+func (q *Query) search(data []byte) (interface{}, error) {
+	document, err := decodeJSON(data)
+	if err != nil {
+		return nil, clierrors.Errorf(clierrors.InvalidQuery, "could not apply the query to an invalid JSON document: %w", err)
+	}
+
+	result, err := search(q.ast, document)
+	if err != nil {
+		return nil, clierrors.Errorf(clierrors.InvalidQuery, "could not apply the query %s: %w", q.expression, err)
+	}
+
+	return result, nil
+}
+
+func formatRaw(result interface{}) string {
+	switch v := result.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		lines := make([]string, 0, len(v))
+		for _, elem := range v {
+			if s, ok := elem.(string); ok {
+				lines = append(lines, s)
+			} else {
+				lines = append(lines, string(encodeJSON(elem)))
+			}
+		}
+		return strings.Join(lines, "\n")
+	default:
+		return string(encodeJSON(v))
+	}
+}
+
+// Value holds the query given to a flag. Expressions are compiled as the flag
+// is set, so that mistakes are reported before the command runs.
+type Value struct {
+	Query *Query
+}
+
+func (v *Value) String() string {
+	if v.Query == nil {
+		return ""
+	}
+
+	return v.Query.expression
+}
+
+// Set compiles the expression given to the flag
+func (v *Value) Set(expression string) error {
+	q, err := Compile(expression)
+	if err != nil {
+		return err
+	}
+	v.Query = q
+
+	return nil
+}
+
+// Type is the type of the flag shown in usages
+func (v *Value) Type() string {
+	return "string"
+}
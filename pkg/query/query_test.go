@@ -0,0 +1,128 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const charges = `{
+  "object": "list",
+  "data": [
+    {"id": "ch_1", "amount": 2000, "currency": "usd", "paid": true, "metadata": {"order": "6735"}, "refunds": [{"id": "re_1"}]},
+    {"id": "ch_2", "amount": 500, "currency": "eur", "paid": false, "metadata": {}, "refunds": []},
+    {"id": "ch_3", "amount": 12500, "currency": "usd", "paid": true, "metadata": {"order": "6740"}, "refunds": [{"id": "re_2"}, {"id": "re_3"}]}
+  ],
+  "has_more": false,
+  "url": "/v1/charges"
+}`
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   string
+	}{
+		{"object", `"list"`},
+		{"missing", `null`},
+		{"data[0].id", `"ch_1"`},
+		{"data[-1].amount", `12500`},
+		{"data[5]", `null`},
+		{"data[].id", `["ch_1","ch_2","ch_3"]`},
+		{"data[*].metadata.order", `["6735","6740"]`},
+		{"data[].{id:id,amount:amount}", `[{"id":"ch_1","amount":2000},{"id":"ch_2","amount":500},{"id":"ch_3","amount":12500}]`},
+		{"data[0].[id, currency]", `["ch_1","usd"]`},
+		{"data[:2].id", `["ch_1","ch_2"]`},
+		{"data[::-1].id", `["ch_3","ch_2","ch_1"]`},
+		{"data[1:].id | [0]", `"ch_2"`},
+		{"data[].refunds[].id", `["re_1","re_2","re_3"]`},
+		{"data[].refunds[*].id", `[["re_1"],[],["re_2","re_3"]]`},
+		{"data[?currency=='usd'].id", "[\"ch_1\",\"ch_3\"]"},
+		{"data[?amount > `1000` && paid].id", `["ch_1","ch_3"]`},
+		{"data[?!paid || currency == `\"usd\"`] | length(@)", `3`},
+		{"data[?metadata.order].metadata.order", `["6735","6740"]`},
+		{"data[0].metadata.*", `["6735"]`},
+		{`data[0]."currency"`, `"usd"`},
+		{"length(data)", `3`},
+		{"sum(data[].amount)", `15000`},
+		{"max(data[].amount)", `12500`},
+		{"sort_by(data, &amount)[].id", `["ch_2","ch_1","ch_3"]`},
+		{"max_by(data, &amount).id", `"ch_3"`},
+		{"join(', ', data[].id)", `"ch_1, ch_2, ch_3"`},
+		{"keys(data[0].metadata)", `["order"]`},
+		{"contains(data[].currency, 'eur')", `true`},
+		{"data[?starts_with(id, 'ch_1')].id", `["ch_1"]`},
+		{"to_string(data[0].amount)", `"2000"`},
+		{"to_number('42')", `42`},
+		{"not_null(missing, url)", `"/v1/charges"`},
+		{"map(&id, data)", `["ch_1","ch_2","ch_3"]`},
+		{"`{\"a\": <b>}`", ``},
+		{"'<b> & </b>'", `"<b> & </b>"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			q, err := Compile(test.expression)
+			if test.expected == "" {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			result, err := q.Search([]byte(charges))
+			require.NoError(t, err)
+			require.Equal(t, test.expected, string(result))
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"data[",
+		"data[].{id}",
+		"data..id",
+		"data[?amount >]",
+		"unknown_function(data)",
+		"'unterminated",
+		"data[::0]",
+		"data #",
+	} {
+		_, err := Compile(expression)
+		require.Error(t, err, expression)
+	}
+
+	_, err := Compile("data[].{id:id amount:amount}")
+	require.EqualError(t, err, "invalid query at position 14: expected ',', got an identifier amount\n  data[].{id:id amount:amount}\n                ^")
+}
+
+func TestSearchErrors(t *testing.T) {
+	q, err := Compile("sum(data[].id)")
+	require.NoError(t, err)
+
+	_, err = q.Search([]byte(charges))
+	require.EqualError(t, err, "could not apply the query sum(data[].id): invalid type for argument 1 of sum(): array")
+
+	_, err = q.Search([]byte("not json"))
+	require.Error(t, err)
+}
+
+func TestApply(t *testing.T) {
+	apply := func(expression string, raw bool) string {
+		q, err := Compile(expression)
+		require.NoError(t, err)
+
+		output, err := q.Apply([]byte(charges), raw)
+		require.NoError(t, err)
+
+		return output
+	}
+
+	require.Equal(t, "{\n  \"id\": \"ch_1\",\n  \"amount\": 2000\n}", apply("data[0].{id:id,amount:amount}", false))
+	require.Equal(t, `"ch_1"`, apply("data[0].id", false))
+
+	require.Equal(t, "ch_1", apply("data[0].id", true))
+	require.Equal(t, "2000", apply("data[0].amount", true))
+	require.Equal(t, "", apply("missing", true))
+	require.Equal(t, "ch_1\nch_2\nch_3", apply("data[].id", true))
+	require.Equal(t, "{\"order\":\"6735\"}\n{}", apply("data[:2].metadata", true))
+}
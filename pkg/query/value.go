@@ -0,0 +1,229 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// object is a JSON object which keeps the order of its keys, so results print
+// with their fields in the order of the response or of the query
+type object struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newObject() *object {
+	return &object{values: make(map[string]interface{})}
+}
+
+func (o *object) set(key string, value interface{}) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// expref is a reference to an expression, passed to functions like sort_by
+type expref struct {
+	ast node
+}
+
+// decodeJSON decodes data into nil, bool, json.Number, string, []interface{}
+// and *object values
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	value, err := decodeValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+
+	return value, nil
+}
+
+func decodeValue(decoder *json.Decoder) (interface{}, error) {
+	t, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t {
+	case json.Delim('['):
+		array := []interface{}{}
+		for decoder.More() {
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err := decoder.Token()
+		return array, err
+	case json.Delim('{'):
+		obj := newObject()
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			obj.set(key.(string), value)
+		}
+		_, err := decoder.Token()
+		return obj, err
+	default:
+		return t, nil
+	}
+}
+
+// encodeJSON encodes a value compactly, without escaping HTML characters
+func encodeJSON(value interface{}) []byte {
+	var buf bytes.Buffer
+	writeJSON(&buf, value)
+
+	return buf.Bytes()
+}
+
+func writeJSON(buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		buf.WriteString(v.String())
+	case float64:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			// NaN and infinities, which JSON can't represent
+			buf.WriteString("null")
+			return
+		}
+		buf.Write(encoded)
+	case string:
+		encoder := json.NewEncoder(buf)
+		encoder.SetEscapeHTML(false)
+		encoder.Encode(v)
+		buf.Truncate(buf.Len() - 1)
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, elem := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSON(buf, elem)
+		}
+		buf.WriteByte(']')
+	case *object:
+		buf.WriteByte('{')
+		for i, key := range v.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSON(buf, key)
+			buf.WriteByte(':')
+			writeJSON(buf, v.values[key])
+		}
+		buf.WriteByte('}')
+	case expref:
+		buf.WriteString("null")
+	}
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case *object:
+		return "object"
+	default:
+		return "expref"
+	}
+}
+
+// isTruthy returns whether a value is true in the sense of JMESPath, where
+// null, false and empty strings, arrays and objects are false
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case *object:
+		return len(v.keys) > 0
+	default:
+		return true
+	}
+}
+
+func equal(a, b interface{}) bool {
+	if na, ok := toNumber(a); ok {
+		nb, ok := toNumber(b)
+		return ok && na == nb
+	}
+
+	switch a := a.(type) {
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case *object:
+		b, ok := b.(*object)
+		if !ok || len(a.keys) != len(b.keys) {
+			return false
+		}
+		for key, value := range a.values {
+			other, ok := b.values[key]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case expref:
+		return false
+	default:
+		return a == b
+	}
+}
@@ -0,0 +1,130 @@
+package redact
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Redacted replaces the secret part of any value that gets redacted
+const Redacted = "[REDACTED]"
+
+type rule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// defaultRules cover the secrets the CLI itself handles: API keys, webhook
+// signing secrets and the credentials sent in Authorization headers
+var defaultRules = []rule{
+	{regexp.MustCompile(`\b((?:sk|rk|pk)_(?:test|live))_[0-9a-zA-Z]{8,}`), "${1}_" + Redacted},
+	{regexp.MustCompile(`\b(whsec)_[0-9a-zA-Z]{8,}`), "${1}_" + Redacted},
+	{regexp.MustCompile(`(?i)(authorization["']?\s*[:=]\s*["']?\[?)(bearer|basic) [^\s"',\]]+`), "$1$2 " + Redacted},
+	{regexp.MustCompile(`(?i)\b(bearer|basic) [0-9a-zA-Z+/=._\-]{16,}`), "$1 " + Redacted},
+}
+
+var (
+	mu         sync.RWMutex
+	extraRules []rule
+)
+
+// String returns s with every known secret replaced
+func String(s string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, r := range defaultRules {
+		s = r.pattern.ReplaceAllString(s, r.replacement)
+	}
+
+	for _, r := range extraRules {
+		s = r.pattern.ReplaceAllString(s, r.replacement)
+	}
+
+	return s
+}
+
+// AddPattern registers an additional regular expression whose matches are
+// redacted in full. Registering the same pattern twice has no effect.
+func AddPattern(pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, r := range extraRules {
+		if r.pattern.String() == pattern {
+			return nil
+		}
+	}
+
+	extraRules = append(extraRules, rule{pattern: re, replacement: Redacted})
+
+	return nil
+}
+
+// LoadPatternsFile registers the patterns listed in a file, one regular
+// expression per line. Blank lines and lines starting with # are ignored, and
+// a missing file is not an error.
+func LoadPatternsFile(path string) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if err := AddPattern(line); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Formatter wraps a logrus formatter so that the entries it formats are redacted
+type Formatter struct {
+	log.Formatter
+}
+
+// Format formats the entry with the wrapped formatter and redacts the result
+func (f *Formatter) Format(entry *log.Entry) ([]byte, error) {
+	out, err := f.Formatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(String(string(out))), nil
+}
+
+// Writer redacts everything written through it before passing it on
+type Writer struct {
+	Out io.Writer
+}
+
+// Write redacts p and writes it to the underlying writer
+func (w *Writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.Out, String(string(p))); err != nil {
+		return 0, err
+	}
+
+	// report the original length so callers don't treat redaction as a short write
+	return len(p), nil
+}
@@ -0,0 +1,268 @@
+// Package replay describes the shape of an account's event traffic, so that
+// traffic of the same shape can be generated in test mode: which event types
+// happen and how often, at what rate, and how bursty they are.
+package replay
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// profileVersion is the version of the profile file format
+const profileVersion = 1
+
+// MaxWindow is how far back events can be analyzed, as the API only lists the
+// events of the last 30 days
+const MaxWindow = 30 * 24 * time.Hour
+
+// Event is an event of the analyzed traffic
+type Event struct {
+	Type    string
+	Created time.Time
+}
+
+// EventTypeWeight is the share of the traffic of an event type
+type EventTypeWeight struct {
+	Type   string  `json:"type"`
+	Count  int     `json:"count"`
+	Weight float64 `json:"weight"`
+}
+
+// Profile is the shape of the event traffic of an account over a window
+type Profile struct {
+	Version int       `json:"version"`
+	Since   time.Time `json:"since"`
+	Until   time.Time `json:"until"`
+	Events  int       `json:"events"`
+
+	// RatePerMinute is the average number of events per minute
+	RatePerMinute float64 `json:"rate_per_minute"`
+	// PeakPerMinute is the largest number of events in a minute
+	PeakPerMinute int `json:"peak_per_minute"`
+	// Burstiness is the index of dispersion of the events per minute: 1 when
+	// events arrive independently of each other, more when they come in
+	// bursts
+	Burstiness float64 `json:"burstiness"`
+
+	EventTypes []EventTypeWeight `json:"event_types"`
+}
+
+// ParseWindow parses how far back to analyze events, as a number of days such
+// as 7d, or as a Go duration such as 36h
+func ParseWindow(value string) (time.Duration, error) {
+	var window time.Duration
+
+	if strings.HasSuffix(value, "d") {
+		n, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, clierrors.Errorf(clierrors.InvalidFlagValue, "invalid window %q, expected a number of days like 7d or a duration like 36h", value)
+		}
+		window = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, clierrors.Errorf(clierrors.InvalidFlagValue, "invalid window %q, expected a number of days like 7d or a duration like 36h", value)
+		}
+		window = d
+	}
+
+	if window < time.Minute || window > MaxWindow {
+		return 0, clierrors.New(clierrors.InvalidFlagValue, "the window must be between 1 minute and 30 days")
+	}
+
+	return window, nil
+}
+
+// Build returns the profile of the events created in [since, until)
+func Build(events []Event, since, until time.Time) (*Profile, error) {
+	minutes := int(until.Sub(since) / time.Minute)
+	if minutes < 1 {
+		return nil, clierrors.New(clierrors.InvalidFlagValue, "the window must be at least a minute long")
+	}
+
+	profile := &Profile{
+		Version:    profileVersion,
+		Since:      since.UTC(),
+		Until:      until.UTC(),
+		EventTypes: []EventTypeWeight{},
+	}
+
+	perMinute := make([]int, minutes)
+	counts := make(map[string]int)
+
+	for _, event := range events {
+		minute := int(event.Created.Sub(since) / time.Minute)
+		if event.Created.Before(since) || minute >= minutes {
+			continue
+		}
+
+		perMinute[minute]++
+		counts[event.Type]++
+		profile.Events++
+	}
+
+	if profile.Events == 0 {
+		return nil, clierrors.Errorf(clierrors.NoEventsToReplay, "no events were created between %s and %s", since.Format(time.RFC3339), until.Format(time.RFC3339))
+	}
+
+	mean := float64(profile.Events) / float64(minutes)
+	variance := 0.0
+
+	for _, count := range perMinute {
+		variance += (float64(count) - mean) * (float64(count) - mean)
+		if count > profile.PeakPerMinute {
+			profile.PeakPerMinute = count
+		}
+	}
+	variance /= float64(minutes)
+
+	profile.RatePerMinute = round(mean)
+	profile.Burstiness = round(variance / mean)
+
+	for eventType, count := range counts {
+		profile.EventTypes = append(profile.EventTypes, EventTypeWeight{
+			Type:   eventType,
+			Count:  count,
+			Weight: round(float64(count) / float64(profile.Events)),
+		})
+	}
+
+	sort.Slice(profile.EventTypes, func(i, j int) bool {
+		if profile.EventTypes[i].Count != profile.EventTypes[j].Count {
+			return profile.EventTypes[i].Count > profile.EventTypes[j].Count
+		}
+		return profile.EventTypes[i].Type < profile.EventTypes[j].Type
+	})
+
+	return profile, nil
+}
+
+func round(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
+
+// Save writes the profile to path as JSON
+func (p *Profile) Save(fs afero.Fs, path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(fs, path, append(data, '\n'), 0o644)
+}
+
+// Load reads a profile written by Save
+func Load(fs afero.Fs, path string) (*Profile, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var profile Profile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, clierrors.Errorf(clierrors.InvalidReplayProfile, "%s is not a replay profile: %w", path, err)
+	}
+
+	if profile.Version != profileVersion {
+		return nil, clierrors.Errorf(clierrors.InvalidReplayProfile, "%s is a version %d replay profile, this version of the CLI reads version %d", path, profile.Version, profileVersion)
+	}
+
+	if profile.RatePerMinute <= 0 || len(profile.EventTypes) == 0 {
+		return nil, clierrors.Errorf(clierrors.InvalidReplayProfile, "%s is a replay profile without events", path)
+	}
+
+	return &profile, nil
+}
+
+// Sampler generates a sequence of events with the shape of a profile. Events
+// come in bursts whose sizes are geometrically distributed, at exponentially
+// distributed intervals, which matches both the rate and the burstiness of the
+// profile.
+type Sampler struct {
+	rand *rand.Rand
+
+	types      []string
+	cumulative []float64
+
+	// burstRate is the number of bursts per second, and burstSize their
+	// average size
+	burstRate float64
+	burstSize float64
+	left      int
+}
+
+// Sampler returns a sampler of the event types for which supported returns
+// true, with their weights scaled to add up to 1, at the profile's rate
+// multiplied by speed. The event types left out are returned too.
+func (p *Profile) Sampler(supported func(eventType string) bool, speed float64, seed int64) (*Sampler, []string, error) {
+	s := &Sampler{rand: rand.New(rand.NewSource(seed))} // #nosec G404
+	var skipped []string
+	total := 0.0
+
+	for _, weight := range p.EventTypes {
+		if !supported(weight.Type) {
+			skipped = append(skipped, weight.Type)
+			continue
+		}
+
+		total += weight.Weight
+		s.types = append(s.types, weight.Type)
+		s.cumulative = append(s.cumulative, total)
+	}
+
+	if len(s.types) == 0 {
+		return nil, skipped, clierrors.New(clierrors.NoEventsToReplay, "none of the event types of the profile can be triggered")
+	}
+
+	for i := range s.cumulative {
+		s.cumulative[i] /= total
+	}
+
+	// for bursts of geometrically distributed sizes of mean m, the index of
+	// dispersion of the events is 2m-1
+	s.burstSize = (p.Burstiness + 1) / 2
+	if s.burstSize < 1 {
+		s.burstSize = 1
+	}
+	s.burstRate = p.RatePerMinute * speed / 60 / s.burstSize
+
+	return s, skipped, nil
+}
+
+// Next returns how long to wait before the next event, and its type
+func (s *Sampler) Next() (time.Duration, string) {
+	var delay time.Duration
+
+	if s.left > 0 {
+		s.left--
+	} else {
+		delay = time.Duration(s.rand.ExpFloat64() / s.burstRate * float64(time.Second))
+		s.left = s.burstLength() - 1
+	}
+
+	i := sort.SearchFloat64s(s.cumulative, s.rand.Float64())
+	if i == len(s.types) {
+		i--
+	}
+
+	return delay, s.types[i]
+}
+
+func (s *Sampler) burstLength() int {
+	if s.burstSize <= 1 {
+		return 1
+	}
+
+	p := 1 / s.burstSize
+
+	return 1 + int(math.Log(1-s.rand.Float64())/math.Log(1-p))
+}
@@ -0,0 +1,102 @@
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWindow(t *testing.T) {
+	window, err := ParseWindow("7d")
+	require.NoError(t, err)
+	require.Equal(t, 7*24*time.Hour, window)
+
+	window, err = ParseWindow("36h")
+	require.NoError(t, err)
+	require.Equal(t, 36*time.Hour, window)
+
+	_, err = ParseWindow("31d")
+	require.EqualError(t, err, "the window must be between 1 minute and 30 days")
+
+	_, err = ParseWindow("a week")
+	require.Error(t, err)
+}
+
+func TestBuild(t *testing.T) {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var events []Event
+	// a burst of 6 events in the first minute, then one event in each of
+	// the 3 next minutes
+	for i := 0; i < 6; i++ {
+		events = append(events, Event{Type: "charge.succeeded", Created: since.Add(time.Duration(i) * time.Second)})
+	}
+	for i := 1; i < 4; i++ {
+		events = append(events, Event{Type: "customer.created", Created: since.Add(time.Duration(i) * time.Minute)})
+	}
+	events = append(events, Event{Type: "charge.failed", Created: since.Add(-time.Second)})
+
+	profile, err := Build(events, since, since.Add(4*time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, 9, profile.Events)
+	require.Equal(t, 2.25, profile.RatePerMinute)
+	require.Equal(t, 6, profile.PeakPerMinute)
+	// the variance of [6 1 1 1] is 4.6875
+	require.Equal(t, 2.0833, profile.Burstiness)
+	require.Equal(t, []EventTypeWeight{
+		{Type: "charge.succeeded", Count: 6, Weight: 0.6667},
+		{Type: "customer.created", Count: 3, Weight: 0.3333},
+	}, profile.EventTypes)
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, profile.Save(fs, "profile.json"))
+
+	loaded, err := Load(fs, "profile.json")
+	require.NoError(t, err)
+	require.Equal(t, profile, loaded)
+
+	_, err = Build(nil, since, since.Add(time.Hour))
+	require.Error(t, err)
+}
+
+func TestSampler(t *testing.T) {
+	profile := &Profile{
+		RatePerMinute: 60,
+		Burstiness:    5,
+		EventTypes: []EventTypeWeight{
+			{Type: "charge.succeeded", Weight: 0.6},
+			{Type: "invoice.paid", Weight: 0.3},
+			{Type: "unknown.event", Weight: 0.1},
+		},
+	}
+
+	sampler, skipped, err := profile.Sampler(func(eventType string) bool { return eventType != "unknown.event" }, 2, 42)
+	require.NoError(t, err)
+	require.Equal(t, []string{"unknown.event"}, skipped)
+
+	counts := make(map[string]int)
+	elapsed := time.Duration(0)
+	simultaneous := 0
+
+	const n = 20000
+	for i := 0; i < n; i++ {
+		delay, eventType := sampler.Next()
+		elapsed += delay
+		counts[eventType]++
+		if delay == 0 {
+			simultaneous++
+		}
+	}
+
+	// 2 events per second at twice the speed of the profile
+	require.InDelta(t, 2, float64(n)/elapsed.Seconds(), 0.1)
+	require.InDelta(t, 2.0/3, float64(counts["charge.succeeded"])/n, 0.02)
+	require.InDelta(t, 1.0/3, float64(counts["invoice.paid"])/n, 0.02)
+	// bursts have 3 events on average
+	require.InDelta(t, 2.0/3, float64(simultaneous)/n, 0.02)
+
+	_, _, err = profile.Sampler(func(string) bool { return false }, 1, 42)
+	require.EqualError(t, err, "none of the event types of the profile can be triggered")
+}
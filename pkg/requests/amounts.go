@@ -0,0 +1,76 @@
+package requests
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/stripe/stripe-cli/pkg/currency"
+)
+
+// ConvertAmounts converts the amounts of the data written for people, such as
+// amount=$20.00 or unit_amount=15,30EUR, to the smallest unit of their
+// currency. The currency is the one written with the amount, or else the
+// currency parameter next to it, or the one of the request. Integers are left
+// as they are, since they're already in the smallest unit.
+func (r *RequestParameters) ConvertAmounts() error {
+	values := make(map[string]string)
+	for _, datum := range r.data {
+		if key, value, ok := strings.Cut(datum, "="); ok {
+			values[key] = value
+		}
+	}
+
+	for i, datum := range r.data {
+		key, value, ok := strings.Cut(datum, "=")
+		if !ok || !isAmountParam(key) || strings.IndexFunc(value, unicode.IsDigit) < 0 {
+			continue
+		}
+
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+			continue
+		}
+
+		code, ok := values[siblingParam(key, "currency")]
+		if !ok {
+			code = values["currency"]
+		}
+
+		amount, err := currency.ParseHumanAmount(value, code)
+		if err != nil {
+			return err
+		}
+
+		r.data[i] = key + "=" + strconv.FormatInt(amount, 10)
+	}
+
+	return nil
+}
+
+// isAmountParam returns whether a parameter, such as
+// line_items[0][price_data][unit_amount], is an amount in the smallest unit of
+// a currency
+func isAmountParam(key string) bool {
+	name := key
+	if i := strings.LastIndex(key, "["); i >= 0 {
+		name = strings.TrimSuffix(key[i+1:], "]")
+	}
+
+	// the _decimal amounts are decimal strings of the smallest unit already
+	if strings.HasSuffix(name, "_decimal") {
+		return false
+	}
+
+	return name == "amount" || strings.HasSuffix(name, "_amount") || strings.HasPrefix(name, "amount_")
+}
+
+// siblingParam returns the parameter name at the same level as key, such as
+// price_data[currency] for price_data[unit_amount]
+func siblingParam(key, name string) string {
+	i := strings.LastIndex(key, "[")
+	if i < 0 {
+		return name
+	}
+
+	return key[:i] + "[" + name + "]"
+}
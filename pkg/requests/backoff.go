@@ -0,0 +1,180 @@
+package requests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+)
+
+// maxRateLimitRetries is how many times a request is retried after being rate
+// limited
+const maxRateLimitRetries = 5
+
+// rateLimitBackoff is how long to wait after the first rate limited attempt.
+// It doubles with every retry.
+var rateLimitBackoff = time.Second
+
+// Backoff is a wait before retrying a rate limited request
+type Backoff struct {
+	// Worker names who made the request, such as the step of a fixture or
+	// the event being resent. It may be empty.
+	Worker     string
+	Method     string
+	Endpoint   string
+	Retry      int
+	MaxRetries int
+	Wait       time.Duration
+	Until      time.Time
+}
+
+func (b Backoff) name() string {
+	if b.Worker != "" {
+		return b.Worker
+	}
+
+	return b.Method + " " + b.Endpoint
+}
+
+// BackoffObserver is told when a rate limited request starts waiting to be
+// retried, and when it's retried
+type BackoffObserver interface {
+	Sleeping(Backoff)
+	Retrying(Backoff)
+}
+
+// BackoffTimeline reports the waits of rate limited requests as they happen,
+// along with every worker still sleeping, so bulk operations don't look like
+// they stalled
+type BackoffTimeline struct {
+	Out io.Writer
+	// JSON writes each report as a JSON object on its own line
+	JSON bool
+
+	mu       sync.Mutex
+	sleeping map[string]Backoff
+}
+
+// backoffEvent is a report of a BackoffTimeline in JSON
+type backoffEvent struct {
+	Type       string    `json:"type"`
+	Worker     string    `json:"worker,omitempty"`
+	Method     string    `json:"method"`
+	Endpoint   string    `json:"endpoint"`
+	Retry      int       `json:"retry"`
+	MaxRetries int       `json:"max_retries"`
+	WaitMS     int64     `json:"wait_ms"`
+	Until      time.Time `json:"until"`
+	Sleeping   []string  `json:"sleeping"`
+}
+
+// NewBackoffTimeline returns a timeline writing its reports to out
+func NewBackoffTimeline(out io.Writer, json bool) *BackoffTimeline {
+	return &BackoffTimeline{
+		Out:  out,
+		JSON: json,
+	}
+}
+
+// Sleeping reports that a request waits to be retried
+func (t *BackoffTimeline) Sleeping(b Backoff) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.sleeping == nil {
+		t.sleeping = make(map[string]Backoff)
+	}
+	t.sleeping[b.name()] = b
+
+	if t.JSON {
+		t.writeJSON("backoff", b)
+		return
+	}
+
+	color := ansi.Color(t.Out)
+
+	fmt.Fprintf(t.Out, "%s %s rate limited on %s %s, retry %d/%d in %s (%s)\n",
+		color.Faint(b.Until.Add(-b.Wait).Format("15:04:05")),
+		color.Yellow(b.name()),
+		b.Method,
+		b.Endpoint,
+		b.Retry,
+		b.MaxRetries,
+		b.Wait,
+		t.describeSleeping(b.Until.Add(-b.Wait)),
+	)
+}
+
+// Retrying reports that a request is retried once it waited
+func (t *BackoffTimeline) Retrying(b Backoff) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.sleeping, b.name())
+
+	if t.JSON {
+		t.writeJSON("retry", b)
+		return
+	}
+
+	color := ansi.Color(t.Out)
+
+	fmt.Fprintf(t.Out, "%s %s retrying %s %s, retry %d/%d\n",
+		color.Faint(b.Until.Format("15:04:05")),
+		color.Green(b.name()),
+		b.Method,
+		b.Endpoint,
+		b.Retry,
+		b.MaxRetries,
+	)
+}
+
+// describeSleeping lists the workers sleeping and how long they have left
+func (t *BackoffTimeline) describeSleeping(now time.Time) string {
+	names := t.sleepingNames()
+
+	left := make([]string, 0, len(names))
+	for _, name := range names {
+		remaining := t.sleeping[name].Until.Sub(now).Round(100 * time.Millisecond)
+		if remaining < 0 {
+			remaining = 0
+		}
+		left = append(left, fmt.Sprintf("%s %s", name, remaining))
+	}
+
+	return fmt.Sprintf("%d sleeping: %s", len(names), strings.Join(left, ", "))
+}
+
+func (t *BackoffTimeline) sleepingNames() []string {
+	names := make([]string, 0, len(t.sleeping))
+	for name := range t.sleeping {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (t *BackoffTimeline) writeJSON(eventType string, b Backoff) {
+	line, err := json.Marshal(backoffEvent{
+		Type:       eventType,
+		Worker:     b.Worker,
+		Method:     b.Method,
+		Endpoint:   b.Endpoint,
+		Retry:      b.Retry,
+		MaxRetries: b.MaxRetries,
+		WaitMS:     b.Wait.Milliseconds(),
+		Until:      b.Until,
+		Sleeping:   t.sleepingNames(),
+	})
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintln(t.Out, string(line))
+}
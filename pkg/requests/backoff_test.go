@@ -0,0 +1,146 @@
+package requests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedBackoffs struct {
+	mu       sync.Mutex
+	sleeping []Backoff
+	retrying []Backoff
+}
+
+func (r *recordedBackoffs) Sleeping(b Backoff) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sleeping = append(r.sleeping, b)
+}
+
+func (r *recordedBackoffs) Retrying(b Backoff) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retrying = append(r.retrying, b)
+}
+
+func TestMakeRequest_RetryRateLimited(t *testing.T) {
+	rateLimitBackoff = time.Millisecond
+	t.Cleanup(func() { rateLimitBackoff = time.Second })
+
+	previous := rateLimitLog
+	rateLimitLog = NewRateLimitLog(afero.NewMemMapFs(), "/ratelimits.ndjson")
+	t.Cleanup(func() { rateLimitLog = previous })
+
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error": {"type": "invalid_request_error", "code": "rate_limit"}}`))
+			return
+		}
+
+		w.Write([]byte(`{"id": "cus_1"}`))
+	}))
+	defer ts.Close()
+
+	backoffs := &recordedBackoffs{}
+	rb := Base{
+		APIBaseURL:       ts.URL,
+		Method:           http.MethodPost,
+		SuppressOutput:   true,
+		RetryRateLimited: true,
+		Worker:           "customer",
+		Backoffs:         backoffs,
+	}
+
+	body, err := rb.MakeRequest(context.Background(), "sk_test_1234", "/v1/customers", &RequestParameters{}, true)
+	require.NoError(t, err)
+	require.Equal(t, `{"id": "cus_1"}`, string(body))
+
+	require.Len(t, backoffs.sleeping, 2)
+	require.Equal(t, backoffs.sleeping, backoffs.retrying)
+	require.Equal(t, Backoff{
+		Worker:     "customer",
+		Method:     http.MethodPost,
+		Endpoint:   "/v1/customers",
+		Retry:      2,
+		MaxRetries: maxRateLimitRetries,
+		Wait:       2 * time.Millisecond,
+		Until:      backoffs.sleeping[1].Until,
+	}, backoffs.sleeping[1])
+}
+
+func TestMakeRequest_RetryRateLimitedGivesUp(t *testing.T) {
+	rateLimitBackoff = time.Millisecond
+	t.Cleanup(func() { rateLimitBackoff = time.Second })
+
+	previous := rateLimitLog
+	rateLimitLog = NewRateLimitLog(afero.NewMemMapFs(), "/ratelimits.ndjson")
+	t.Cleanup(func() { rateLimitLog = previous })
+
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	rb := Base{APIBaseURL: ts.URL, Method: http.MethodGet, SuppressOutput: true, RetryRateLimited: true}
+
+	_, err := rb.MakeRequest(context.Background(), "sk_test_1234", "/v1/customers", &RequestParameters{}, true)
+	require.Error(t, err)
+	require.Equal(t, maxRateLimitRetries+1, attempts)
+}
+
+func TestBackoffTimeline(t *testing.T) {
+	var out bytes.Buffer
+	timeline := NewBackoffTimeline(&out, false)
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+	first := Backoff{Worker: "evt_1", Method: http.MethodPost, Endpoint: "/v1/events/{id}/retry", Retry: 1, MaxRetries: 5, Wait: 2 * time.Second, Until: start.Add(2 * time.Second)}
+	second := Backoff{Method: http.MethodGet, Endpoint: "/v1/events", Retry: 3, MaxRetries: 5, Wait: 4 * time.Second, Until: start.Add(time.Second + 4*time.Second)}
+
+	timeline.Sleeping(first)
+	timeline.Sleeping(second)
+	timeline.Retrying(first)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Equal(t, []string{
+		"12:00:00 evt_1 rate limited on POST /v1/events/{id}/retry, retry 1/5 in 2s (1 sleeping: evt_1 2s)",
+		"12:00:01 GET /v1/events rate limited on GET /v1/events, retry 3/5 in 4s (2 sleeping: GET /v1/events 4s, evt_1 1s)",
+		"12:00:02 evt_1 retrying POST /v1/events/{id}/retry, retry 1/5",
+	}, lines)
+}
+
+func TestBackoffTimeline_JSON(t *testing.T) {
+	var out bytes.Buffer
+	timeline := NewBackoffTimeline(&out, true)
+
+	backoff := Backoff{Worker: "evt_1", Method: http.MethodPost, Endpoint: "/v1/events/{id}/retry", Retry: 1, MaxRetries: 5, Wait: 2 * time.Second, Until: time.Now()}
+	timeline.Sleeping(backoff)
+	timeline.Retrying(backoff)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var event backoffEvent
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &event))
+	require.Equal(t, "backoff", event.Type)
+	require.Equal(t, int64(2000), event.WaitMS)
+	require.Equal(t, []string{"evt_1"}, event.Sleeping)
+
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &event))
+	require.Equal(t, "retry", event.Type)
+	require.Empty(t, event.Sleeping)
+}
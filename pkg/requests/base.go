@@ -12,10 +12,18 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/pretty"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
+	"github.com/stripe/stripe-cli/pkg/guardrails"
+	"github.com/stripe/stripe-cli/pkg/query"
 	"github.com/stripe/stripe-cli/pkg/stripe"
 
 	"github.com/spf13/cobra"
@@ -38,6 +46,79 @@ func (r *RequestParameters) AppendData(data []string) {
 	r.data = append(r.data, data...)
 }
 
+// AddDefaultMetadata adds the metadata keys that the data doesn't set already.
+func (r *RequestParameters) AddDefaultMetadata(metadata map[string]string) {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		param := fmt.Sprintf("metadata[%s]", key)
+
+		set := false
+		for _, datum := range r.data {
+			if strings.HasPrefix(datum, param+"=") {
+				set = true
+				break
+			}
+		}
+
+		if !set {
+			r.data = append(r.data, fmt.Sprintf("%s=%s", param, metadata[key]))
+		}
+	}
+}
+
+// actionSegments are the last segments of the paths acting on an object, such
+// as /v1/invoices/in_123/pay, which reject metadata
+var actionSegments = map[string]bool{
+	"accept":                  true,
+	"apply_customer_balance":  true,
+	"approve":                 true,
+	"attach":                  true,
+	"cancel":                  true,
+	"capture":                 true,
+	"close":                   true,
+	"confirm":                 true,
+	"decline":                 true,
+	"detach":                  true,
+	"expire":                  true,
+	"finalize":                true,
+	"fund_cash_balance":       true,
+	"increment_authorization": true,
+	"mark_uncollectible":      true,
+	"pay":                     true,
+	"reject":                  true,
+	"release":                 true,
+	"reopen":                  true,
+	"resume":                  true,
+	"send":                    true,
+	"verify":                  true,
+	"verify_microdeposits":    true,
+	"void":                    true,
+}
+
+// AcceptsMetadata returns whether a POST to path takes metadata: it creates an
+// object, on a collection path such as /v1/customers, or updates one, on an
+// object path such as /v1/customers/cus_123, rather than acting on an object
+// or a test helper.
+func AcceptsMetadata(path string) bool {
+	path, _, _ = strings.Cut(path, "?")
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) > 0 && segments[0] == "v1" {
+		segments = segments[1:]
+	}
+
+	if len(segments) > 0 && segments[0] == "test_helpers" {
+		return false
+	}
+
+	return len(segments) < 3 || !actionSegments[segments[len(segments)-1]]
+}
+
 // AppendExpand appends fields to the expand parameter.
 func (r *RequestParameters) AppendExpand(fields []string) {
 	r.expand = append(r.expand, fields...)
@@ -65,6 +146,9 @@ type RequestError struct {
 	ErrorType  string
 	ErrorCode  string
 	Body       interface{} // the raw response body
+	// RetryAfter is how long the response asked to wait before retrying,
+	// when it had a Retry-After header
+	RetryAfter time.Duration
 }
 
 func (e RequestError) Error() string {
@@ -101,8 +185,37 @@ type Base struct {
 
 	Livemode bool
 
-	autoConfirm bool
-	showHeaders bool
+	// CacheTTL enables the local response cache for GET requests when set to
+	// a positive duration. Cached responses older than CacheTTL are ignored.
+	CacheTTL time.Duration
+
+	// CacheDir overrides the directory used by the response cache
+	CacheDir string
+
+	// DefaultMetadata adds the profile's default metadata to the request made
+	// by RunRequestsCmd, unless --no-default-metadata is passed
+	DefaultMetadata bool
+
+	// RetryRateLimited retries the requests made with errOnStatus that were
+	// rate limited, waiting longer after each attempt
+	RetryRateLimited bool
+
+	// Worker names who makes the requests in the reports of Backoffs, such as
+	// the step of a fixture
+	Worker string
+
+	// Backoffs is told when a rate limited request waits to be retried
+	Backoffs BackoffObserver
+
+	autoConfirm       bool
+	showHeaders       bool
+	noDefaultMetadata bool
+
+	autoPaginate bool
+	pagination   PaginationOptions
+
+	query query.Value
+	raw   bool
 }
 
 var confirmationCommands = map[string]bool{http.MethodDelete: true}
@@ -110,7 +223,7 @@ var confirmationCommands = map[string]bool{http.MethodDelete: true}
 // RunRequestsCmd is the interface exposed for the CLI to run network requests through
 func (rb *Base) RunRequestsCmd(cmd *cobra.Command, args []string) error {
 	if len(args) > 1 {
-		return fmt.Errorf("this command only supports one argument. Run with the --help flag to see usage and examples")
+		return clierrors.New(clierrors.InvalidArgs, "this command only supports one argument. Run with the --help flag to see usage and examples")
 	}
 
 	if len(args) == 0 {
@@ -135,6 +248,24 @@ func (rb *Base) RunRequestsCmd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if rb.DefaultMetadata && !rb.noDefaultMetadata && AcceptsMetadata(path) {
+		rb.Parameters.AddDefaultMetadata(rb.Profile.GetDefaultMetadata())
+	}
+
+	if !rb.Profile.GetStrictAmounts() {
+		if err := rb.Parameters.ConvertAmounts(); err != nil {
+			return err
+		}
+	}
+
+	if rb.autoPaginate || rb.pagination.LimitTotal > 0 {
+		if rb.Backoffs == nil {
+			rb.Backoffs = NewBackoffTimeline(os.Stderr, false)
+		}
+
+		return rb.MakePaginatedRequest(cmd.Context(), apiKey, path, &rb.Parameters, rb.pagination, os.Stdout)
+	}
+
 	_, err = rb.MakeRequest(cmd.Context(), apiKey, path, &rb.Parameters, false)
 
 	return err
@@ -155,6 +286,15 @@ func (rb *Base) InitFlags() {
 	rb.Cmd.Flags().BoolVar(&rb.Livemode, "live", false, "Make a live request (default: test)")
 	rb.Cmd.Flags().BoolVar(&rb.DarkStyle, "dark-style", false, "Use a darker color scheme better suited for lighter command-lines")
 
+	// Search operations have a query parameter of their own
+	if rb.Cmd.Flags().Lookup("query") == nil {
+		rb.Cmd.Flags().Var(&rb.query, "query", "JMESPath expression applied to the response before printing it (e.g. 'data[].{id:id,amount:amount}')")
+	}
+
+	if rb.Cmd.Flags().Lookup("raw") == nil {
+		rb.Cmd.Flags().BoolVar(&rb.raw, "raw", false, "Print JSON compactly, strings without quotes and arrays one element per line, for use in shell scripts")
+	}
+
 	// Conditionally add flags for GET requests. I'm doing it here to keep `limit`, `start_after` and `ending_before` unexported
 	if rb.Method == http.MethodGet {
 		if rb.Cmd.Flags().Lookup("limit") == nil {
@@ -168,6 +308,17 @@ func (rb *Base) InitFlags() {
 		if rb.Cmd.Flags().Lookup("ending-before") == nil {
 			rb.Cmd.Flags().StringVarP(&rb.Parameters.endingBefore, "ending-before", "b", "", "Retrieve the previous page in the list. This is a cursor for pagination and should be an object ID")
 		}
+
+		rb.Cmd.Flags().DurationVar(&rb.CacheTTL, "cache", 0, "Serve the response from a local cache if it was fetched within the given duration (e.g. 5m)")
+
+		rb.Cmd.Flags().BoolVar(&rb.autoPaginate, "all", false, "Follow pagination to retrieve every object in the list")
+		rb.Cmd.Flags().IntVar(&rb.pagination.LimitTotal, "limit-total", 0, "Stop paginating once this many objects were retrieved (implies --all)")
+		rb.Cmd.Flags().IntVar(&rb.pagination.PageSize, "page-size", 0, "How many objects to request per page when paginating, between 1 and 100")
+		rb.Cmd.Flags().BoolVar(&rb.pagination.NDJSON, "ndjson", false, "When paginating, print each object on its own line as pages arrive")
+	}
+
+	if rb.DefaultMetadata {
+		rb.Cmd.Flags().BoolVar(&rb.noDefaultMetadata, "no-default-metadata", false, "Don't add the profile's default metadata to the request")
 	}
 
 	// Hidden configuration flags, useful for dev/debugging
@@ -198,7 +349,74 @@ func (rb *Base) MakeRequest(ctx context.Context, apiKey, path string, params *Re
 		return []byte{}, err
 	}
 
-	return rb.performRequest(ctx, apiKey, path, params, data, errOnStatus, nil)
+	if !rb.RetryRateLimited || !errOnStatus {
+		return rb.performRequest(ctx, apiKey, path, params, data, errOnStatus, nil)
+	}
+
+	backoff := rateLimitBackoff
+
+	for retry := 1; ; retry++ {
+		body, err := rb.performRequest(ctx, apiKey, path, params, data, errOnStatus, nil)
+
+		var requestErr RequestError
+		if err == nil || !errors.As(err, &requestErr) || requestErr.StatusCode != http.StatusTooManyRequests || retry > maxRateLimitRetries {
+			return body, err
+		}
+
+		wait := backoff
+		if requestErr.RetryAfter > wait {
+			wait = requestErr.RetryAfter
+		}
+
+		if err := rb.waitToRetry(ctx, apiKey, path, retry, wait); err != nil {
+			return nil, err
+		}
+
+		backoff *= 2
+	}
+}
+
+// waitToRetry waits before retrying a rate limited request, reporting the wait
+// to Backoffs
+func (rb *Base) waitToRetry(ctx context.Context, apiKey, path string, retry int, wait time.Duration) error {
+	log.WithFields(log.Fields{
+		"prefix": "requests.Base.waitToRetry",
+	}).Debugf("Rate limited, retrying in %s", wait)
+
+	now := time.Now()
+	backoff := Backoff{
+		Worker:     rb.Worker,
+		Method:     rb.Method,
+		Endpoint:   rateLimitEndpoint(path),
+		Retry:      retry,
+		MaxRetries: maxRateLimitRetries,
+		Wait:       wait,
+		Until:      now.Add(wait),
+	}
+
+	recordRateLimitObservation(RateLimitObservation{
+		Time:     now,
+		Method:   rb.Method,
+		Endpoint: backoff.Endpoint,
+		Livemode: strings.Contains(apiKey, "live"),
+		Backoff:  wait,
+	})
+
+	if rb.Backoffs != nil {
+		rb.Backoffs.Sleeping(backoff)
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+	}
+
+	if rb.Backoffs != nil {
+		rb.Backoffs.Retrying(backoff)
+	}
+
+	return nil
 }
 
 func (rb *Base) performRequest(ctx context.Context, apiKey, path string, params *RequestParameters, data string, errOnStatus bool, additionalConfigure func(req *http.Request)) ([]byte, error) {
@@ -207,6 +425,38 @@ func (rb *Base) performRequest(ctx context.Context, apiKey, path string, params
 		return []byte{}, err
 	}
 
+	// Live mode requests that change data must be asked for with --live, or
+	// confirmed
+	if err := guardrails.Default.Check(rb.Method, path, apiKey, rb.Livemode); err != nil {
+		return []byte{}, err
+	}
+
+	cache := NewResponseCache(rb.CacheDir)
+	cacheHeaders := map[string]string{
+		"Stripe-Account": params.stripeAccount,
+		"Stripe-Version": params.version,
+	}
+
+	if rb.Method != http.MethodGet {
+		// Anything other than a GET may change the objects we have cached, so
+		// never let a later read observe stale data after a write.
+		if err := cache.Invalidate(apiKey); err != nil {
+			log.WithFields(log.Fields{
+				"prefix": "requests.Base.performRequest",
+				"path":   cache.Dir,
+			}).Warnf("Failed to invalidate the response cache, later requests with --cache may return stale data: %v", err)
+		}
+	} else if rb.CacheTTL > 0 {
+		if body, ok := cache.Get(apiKey, rb.Method, path, data, cacheHeaders, rb.CacheTTL); ok {
+			log.WithFields(log.Fields{
+				"prefix": "requests.Base.performRequest",
+				"path":   path,
+			}).Debug("Serving response from cache")
+
+			return body, rb.printResponse(body, http.StatusOK)
+		}
+	}
+
 	client := &stripe.Client{
 		BaseURL: parsedBaseURL,
 		APIKey:  apiKey,
@@ -229,23 +479,88 @@ func (rb *Base) performRequest(ctx context.Context, apiKey, path string, params
 	}
 	defer resp.Body.Close()
 
+	recordRateLimit(rb.Method, path, apiKey, resp)
+
 	body, err := io.ReadAll(resp.Body)
 
 	if resp.StatusCode == 401 || (errOnStatus && resp.StatusCode >= 300) {
 		requestError := compileRequestError(body, resp.StatusCode)
+		requestError.RetryAfter = parseRetryAfter(resp.Header)
 		return []byte{}, requestError
 	}
 
-	if !rb.SuppressOutput {
+	if err != nil && !rb.SuppressOutput {
+		return []byte{}, err
+	}
+
+	if err == nil && rb.Method == http.MethodGet && rb.CacheTTL > 0 && resp.StatusCode < 300 {
+		if err := cache.Set(apiKey, rb.Method, path, data, cacheHeaders, body); err != nil {
+			log.WithFields(log.Fields{
+				"prefix": "requests.Base.performRequest",
+				"path":   cache.Dir,
+			}).Debugf("Failed to cache the response: %v", err)
+		}
+	}
+
+	return body, rb.printResponse(body, resp.StatusCode)
+}
+
+func (rb *Base) printResponse(body []byte, statusCode int) error {
+	if rb.SuppressOutput {
+		return nil
+	}
+
+	// Errors are printed whole, the query being written for the objects
+	if statusCode >= 300 {
+		fmt.Print(ansi.ColorizeJSON(string(body), rb.DarkStyle, os.Stdout))
+		return nil
+	}
+
+	return rb.writeJSON(os.Stdout, body, false)
+}
+
+// writeJSON writes a JSON document to out through the --query expression, if
+// one was given. Compact documents are written on a single line.
+func (rb *Base) writeJSON(out io.Writer, body []byte, compact bool) error {
+	q := rb.query.Query
+	if q == nil && rb.raw {
+		q, _ = query.Compile("@")
+	}
+
+	if q == nil {
+		if compact {
+			_, err := fmt.Fprintln(out, string(pretty.Ugly(body)))
+			return err
+		}
+		_, err := fmt.Fprint(out, ansi.ColorizeJSON(string(body), rb.DarkStyle, out))
+		return err
+	}
+
+	if compact && !rb.raw {
+		result, err := q.Search(body)
 		if err != nil {
-			return []byte{}, err
+			return err
 		}
+		_, err = fmt.Fprintln(out, string(result))
+		return err
+	}
 
-		result := ansi.ColorizeJSON(string(body), rb.DarkStyle, os.Stdout)
-		fmt.Print(result)
+	output, err := q.Apply(body, rb.raw)
+	if err != nil {
+		return err
 	}
 
-	return body, nil
+	if rb.raw {
+		if output == "" {
+			return nil
+		}
+		_, err = fmt.Fprintln(out, output)
+		return err
+	}
+
+	_, err = fmt.Fprint(out, ansi.ColorizeJSON(output+"\n", rb.DarkStyle, out))
+
+	return err
 }
 
 func compileRequestError(body []byte, statusCode int) RequestError {
@@ -289,7 +604,7 @@ func (rb *Base) buildDataForRequest(params *RequestParameters) (string, error) {
 			splitDatum := strings.SplitN(datum, "=", 2)
 
 			if len(splitDatum) < 2 {
-				return "", fmt.Errorf("Invalid data argument: %s", datum)
+				return "", clierrors.Errorf(clierrors.InvalidFlagValue, "Invalid data argument: %s", datum)
 			}
 
 			keys = append(keys, splitDatum[0])
@@ -330,7 +645,7 @@ func (rb *Base) buildMultiPartRequest(params *RequestParameters) (*bytes.Buffer,
 		splitDatum := strings.SplitN(datum, "=", 2)
 
 		if len(splitDatum) < 2 {
-			return nil, "", fmt.Errorf("Invalid data argument: %s", datum)
+			return nil, "", clierrors.Errorf(clierrors.InvalidFlagValue, "Invalid data argument: %s", datum)
 		}
 
 		key := splitDatum[0]
@@ -445,7 +760,7 @@ func createOrNormalizePath(arg string) (string, error) {
 			return path + arg, nil
 		}
 
-		return "", fmt.Errorf("Unrecognized object id: %s", arg)
+		return "", clierrors.Errorf(clierrors.InvalidArgs, "Unrecognized object id: %s", arg)
 	}
 
 	return normalizePath(arg), nil
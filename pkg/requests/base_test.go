@@ -11,6 +11,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 )
 
@@ -41,6 +42,64 @@ func TestBuildDataForRequestExpand(t *testing.T) {
 	require.Equal(t, expected, output)
 }
 
+func TestAddDefaultMetadata(t *testing.T) {
+	params := &RequestParameters{data: []string{"email=fry@planetexpress.com", "metadata[developer]=bender"}}
+	params.AddDefaultMetadata(map[string]string{
+		"developer":  "fry",
+		"created_by": "stripe-cli",
+	})
+
+	require.Equal(t, []string{
+		"email=fry@planetexpress.com",
+		"metadata[developer]=bender",
+		"metadata[created_by]=stripe-cli",
+	}, params.data)
+}
+
+func TestAcceptsMetadata(t *testing.T) {
+	require.True(t, AcceptsMetadata("/v1/customers"))
+	require.True(t, AcceptsMetadata("/v1/customers/cus_123"))
+	require.True(t, AcceptsMetadata("/v1/customers/cus_123/sources"))
+	require.True(t, AcceptsMetadata("/v1/checkout/sessions"))
+	require.True(t, AcceptsMetadata("/v1/invoices/pay"))
+
+	require.False(t, AcceptsMetadata("/v1/invoices/in_123/pay"))
+	require.False(t, AcceptsMetadata("/v1/payment_intents/pi_123/cancel?expand[]=customer"))
+	require.False(t, AcceptsMetadata("/v1/payment_methods/pm_123/attach"))
+	require.False(t, AcceptsMetadata("/v1/test_helpers/test_clocks/clock_123/advance"))
+}
+
+func TestConvertAmounts(t *testing.T) {
+	params := &RequestParameters{data: []string{
+		"amount=$20.00",
+		"currency=usd",
+		"application_fee_amount=150",
+		"line_items[0][price_data][currency]=eur",
+		"line_items[0][price_data][unit_amount]=15,30",
+		"line_items[0][price_data][unit_amount_decimal]=1530.5",
+		"payment_method_options[card][mandate_options][amount_type]=fixed",
+		"description=$20.00",
+	}}
+
+	require.NoError(t, params.ConvertAmounts())
+	require.Equal(t, []string{
+		"amount=2000",
+		"currency=usd",
+		"application_fee_amount=150",
+		"line_items[0][price_data][currency]=eur",
+		"line_items[0][price_data][unit_amount]=1530",
+		"line_items[0][price_data][unit_amount_decimal]=1530.5",
+		"payment_method_options[card][mandate_options][amount_type]=fixed",
+		"description=$20.00",
+	}, params.data)
+
+	params = &RequestParameters{data: []string{"amount=2000", "amount_to_capture=20.00"}}
+	require.EqualError(t, params.ConvertAmounts(), "the currency of the amount 20.00 is unknown, write it with the amount, such as 20.00USD")
+
+	params = &RequestParameters{data: []string{"currency=jpy", "amount=$20.00"}}
+	require.EqualError(t, params.ConvertAmounts(), "the amount $20.00 is in $, but the currency is JPY")
+}
+
 func TestBuildDataForRequestPagination(t *testing.T) {
 	rb := Base{}
 	rb.Method = http.MethodGet
@@ -113,6 +172,23 @@ func TestMakeRequest(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestInitFlags_Query(t *testing.T) {
+	rb := Base{Cmd: &cobra.Command{}, Method: http.MethodGet}
+	rb.InitFlags()
+
+	require.Error(t, rb.Cmd.ParseFlags([]string{"--query", "data[].{id:id"}))
+	require.NoError(t, rb.Cmd.ParseFlags([]string{"--query", "data[].{id:id}", "--raw"}))
+	require.Equal(t, "data[].{id:id}", rb.query.String())
+
+	// search operations keep their own query parameter
+	cmd := &cobra.Command{}
+	cmd.Flags().String("query", "", "")
+	rb = Base{Cmd: cmd, Method: http.MethodGet}
+	rb.InitFlags()
+	require.NoError(t, rb.Cmd.ParseFlags([]string{"--query", "email:'a@example.com'"}))
+	require.Nil(t, rb.query.Query)
+}
+
 func TestMakeRequest_ErrOnStatus(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
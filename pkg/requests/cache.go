@@ -0,0 +1,124 @@
+package requests
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/stripe/stripe-cli/pkg/config"
+)
+
+// ResponseCache is a local, file-based cache for the bodies of read-only API
+// requests. Entries are grouped by a fingerprint of the API key so that the
+// key itself is never written to disk and so that all the entries for a key
+// can be invalidated at once.
+type ResponseCache struct {
+	// Dir is the directory the cache entries are stored in
+	Dir string
+}
+
+type cacheEntry struct {
+	CreatedAt time.Time `json:"created_at"`
+	Body      []byte    `json:"body"`
+}
+
+// NewResponseCache returns a ResponseCache stored in the given directory. If
+// dir is empty, the default cache directory in the CLI's config folder is used.
+func NewResponseCache(dir string) *ResponseCache {
+	if dir == "" {
+		dir = DefaultCacheDir()
+	}
+
+	return &ResponseCache{Dir: dir}
+}
+
+// DefaultCacheDir returns the default location of the response cache
+func DefaultCacheDir() string {
+	cfg := &config.Config{}
+	return filepath.Join(cfg.GetConfigFolder(os.Getenv("XDG_CONFIG_HOME")), "cache", "requests")
+}
+
+// Get returns the cached body for the request if an entry exists and is not
+// older than ttl.
+func (c *ResponseCache) Get(apiKey, method, path, data string, headers map[string]string, ttl time.Duration) ([]byte, bool) {
+	raw, err := os.ReadFile(c.entryPath(apiKey, method, path, data, headers))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, false
+	}
+
+	if time.Since(entry.CreatedAt) > ttl {
+		return nil, false
+	}
+
+	return entry.Body, true
+}
+
+// Set stores the body for the request.
+func (c *ResponseCache) Set(apiKey, method, path, data string, headers map[string]string, body []byte) error {
+	entryPath := c.entryPath(apiKey, method, path, data, headers)
+
+	err := os.MkdirAll(filepath.Dir(entryPath), 0700)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(cacheEntry{CreatedAt: time.Now(), Body: body})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(entryPath, raw, 0600)
+}
+
+// Invalidate removes all of the cached entries for an API key. It's a no-op
+// when nothing was cached for the key, which is the case unless --cache was
+// used.
+func (c *ResponseCache) Invalidate(apiKey string) error {
+	dir := filepath.Join(c.Dir, keyFingerprint(apiKey))
+
+	if _, err := os.Lstat(dir); os.IsNotExist(err) {
+		return nil
+	}
+
+	return os.RemoveAll(dir)
+}
+
+// Clear removes every entry from the cache.
+func (c *ResponseCache) Clear() error {
+	log.WithFields(log.Fields{
+		"prefix": "requests.ResponseCache.Clear",
+		"path":   c.Dir,
+	}).Debug("Clearing response cache")
+
+	return os.RemoveAll(c.Dir)
+}
+
+func (c *ResponseCache) entryPath(apiKey, method, path, data string, headers map[string]string) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + "\n" + path + "\n" + data))
+
+	// Headers like Stripe-Account and Stripe-Version change the response, so
+	// they need to be part of the key. Only a fixed set is considered, in a
+	// fixed order, to keep the key stable.
+	for _, name := range []string{"Stripe-Account", "Stripe-Version"} {
+		hash.Write([]byte("\n" + name + ":" + headers[name]))
+	}
+
+	return filepath.Join(c.Dir, keyFingerprint(apiKey), hex.EncodeToString(hash.Sum(nil))+".json")
+}
+
+// keyFingerprint returns a short, non-reversible identifier for an API key
+func keyFingerprint(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,101 @@
+package requests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeRequest_UsesCache(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "price_123"}`))
+	}))
+	defer ts.Close()
+
+	rb := Base{APIBaseURL: ts.URL, SuppressOutput: true, CacheTTL: time.Minute, CacheDir: t.TempDir()}
+	rb.Method = http.MethodGet
+
+	for i := 0; i < 2; i++ {
+		body, err := rb.MakeRequest(context.Background(), "sk_test_1234", "/v1/prices/price_123", &RequestParameters{}, true)
+		require.NoError(t, err)
+		require.Equal(t, `{"id": "price_123"}`, string(body))
+	}
+	require.Equal(t, 1, hits)
+
+	// A request made with a different key must not see the cached entry
+	_, err := rb.MakeRequest(context.Background(), "sk_test_5678", "/v1/prices/price_123", &RequestParameters{}, true)
+	require.NoError(t, err)
+	require.Equal(t, 2, hits)
+}
+
+func TestMakeRequest_WriteInvalidatesCache(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hits++
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	get := Base{Method: http.MethodGet, APIBaseURL: ts.URL, SuppressOutput: true, CacheTTL: time.Minute, CacheDir: dir}
+	del := Base{Method: http.MethodDelete, APIBaseURL: ts.URL, SuppressOutput: true, CacheDir: dir, Livemode: true}
+
+	_, err := get.MakeRequest(context.Background(), "sk_live_1234", "/v1/products/prod_123", &RequestParameters{}, true)
+	require.NoError(t, err)
+
+	_, err = del.MakeRequest(context.Background(), "sk_live_1234", "/v1/products/prod_123", &RequestParameters{}, true)
+	require.NoError(t, err)
+
+	_, err = get.MakeRequest(context.Background(), "sk_live_1234", "/v1/products/prod_123", &RequestParameters{}, true)
+	require.NoError(t, err)
+	require.Equal(t, 2, hits)
+}
+
+func TestResponseCacheExpires(t *testing.T) {
+	cache := NewResponseCache(t.TempDir())
+
+	err := cache.Set("sk_test_1234", http.MethodGet, "/v1/prices", "limit=3", nil, []byte("{}"))
+	require.NoError(t, err)
+
+	_, ok := cache.Get("sk_test_1234", http.MethodGet, "/v1/prices", "limit=3", nil, time.Minute)
+	require.True(t, ok)
+
+	_, ok = cache.Get("sk_test_1234", http.MethodGet, "/v1/prices", "limit=3", nil, 0)
+	require.False(t, ok)
+
+	_, ok = cache.Get("sk_test_1234", http.MethodGet, "/v1/prices", "limit=3", map[string]string{"Stripe-Account": "acct_123"}, time.Minute)
+	require.False(t, ok)
+
+	require.NoError(t, cache.Clear())
+	_, ok = cache.Get("sk_test_1234", http.MethodGet, "/v1/prices", "limit=3", nil, time.Minute)
+	require.False(t, ok)
+}
+
+func TestResponseCacheInvalidate(t *testing.T) {
+	cache := NewResponseCache(filepath.Join(t.TempDir(), "requests"))
+
+	// nothing was cached yet, so there's nothing to remove
+	require.NoError(t, cache.Invalidate("sk_test_1234"))
+	require.NoDirExists(t, cache.Dir)
+
+	require.NoError(t, cache.Set("sk_test_1234", http.MethodGet, "/v1/prices", "", nil, []byte("{}")))
+	require.NoError(t, cache.Set("sk_test_5678", http.MethodGet, "/v1/prices", "", nil, []byte("{}")))
+
+	require.NoError(t, cache.Invalidate("sk_test_1234"))
+
+	_, ok := cache.Get("sk_test_1234", http.MethodGet, "/v1/prices", "", nil, time.Minute)
+	require.False(t, ok)
+	_, ok = cache.Get("sk_test_5678", http.MethodGet, "/v1/prices", "", nil, time.Minute)
+	require.True(t, ok)
+}
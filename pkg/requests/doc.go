@@ -0,0 +1,21 @@
+// Package requests makes authenticated requests to the Stripe API the same way
+// the `stripe get`, `stripe post` and `stripe delete` commands do.
+//
+// Programs embedding the CLI can rely on the following, which keep their
+// signatures within a major version of the CLI:
+//
+//   - Base, with its Method, SuppressOutput, APIBaseURL and Livemode fields,
+//     and its MakeRequest and MakeMultiPartRequest methods
+//   - RequestParameters, with AppendData, AppendExpand, SetIdempotency,
+//     SetStripeAccount and SetVersion
+//   - RequestError, returned for the responses with an error status
+//
+// Base can be used without a cobra command:
+//
+//	req := requests.Base{Method: http.MethodGet, SuppressOutput: true}
+//	body, err := req.MakeRequest(ctx, apiKey, "/v1/customers", &requests.RequestParameters{}, true)
+//
+// The flags and RunRequestsCmd, the response cache, the rate limit log and
+// the webhook endpoint helpers serve the CLI's own commands and change with
+// them.
+package requests
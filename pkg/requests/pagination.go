@@ -0,0 +1,120 @@
+package requests
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/pretty"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// maxPageSize is the largest page the API returns for list requests
+const maxPageSize = 100
+
+// PaginationOptions configures how MakePaginatedRequest pages through a list
+type PaginationOptions struct {
+	// LimitTotal stops paginating once this many objects were returned.
+	// Zero means no limit.
+	LimitTotal int
+	// PageSize is how many objects to request per page, up to 100.
+	// Zero uses the limit set on the parameters, or the API's default.
+	PageSize int
+	// NDJSON writes each object on its own line as pages arrive, instead of
+	// writing a single array once all pages were fetched
+	NDJSON bool
+}
+
+// MakePaginatedRequest follows `has_more` on a list request, writing every
+// object returned to out. Search requests are followed through `next_page`.
+// The --query expression applies to each object with NDJSON, and otherwise to
+// the array of all the objects.
+// Pages that are rate limited are retried with an exponential backoff, which is
+// reported to rb.Backoffs.
+func (rb *Base) MakePaginatedRequest(ctx context.Context, apiKey, path string, params *RequestParameters, opts PaginationOptions, out io.Writer) error {
+	if rb.Method != http.MethodGet {
+		return clierrors.New(clierrors.ConflictingFlags, "pagination is only supported for GET requests")
+	}
+
+	if opts.PageSize < 0 || opts.PageSize > maxPageSize {
+		return clierrors.Errorf(clierrors.InvalidFlagValue, "page size must be between 1 and %d", maxPageSize)
+	}
+
+	// pages are printed by us once they were all received, not as they come in
+	pageRequest := *rb
+	pageRequest.SuppressOutput = true
+	pageRequest.RetryRateLimited = true
+
+	pageParams := *params
+	pageParams.data = append([]string{}, params.data...)
+	backwards := params.endingBefore != ""
+
+	var objects []string
+	written := 0
+
+	for {
+		pageSize := opts.PageSize
+		if pageSize == 0 && pageParams.limit != "" {
+			pageSize, _ = strconv.Atoi(pageParams.limit)
+		}
+		if opts.LimitTotal > 0 && (pageSize == 0 || opts.LimitTotal-written < pageSize) {
+			pageSize = opts.LimitTotal - written
+			if pageSize > maxPageSize {
+				pageSize = maxPageSize
+			}
+		}
+		if pageSize > 0 {
+			pageParams.limit = strconv.Itoa(pageSize)
+		}
+
+		body, err := pageRequest.MakeRequest(ctx, apiKey, path, &pageParams, true)
+		if err != nil {
+			return err
+		}
+
+		page := gjson.ParseBytes(body)
+		if page.Get("object").String() != "list" && page.Get("object").String() != "search_result" {
+			return clierrors.New(clierrors.ConflictingFlags, "pagination is only supported for list and search requests")
+		}
+
+		data := page.Get("data").Array()
+		for _, object := range data {
+			if opts.LimitTotal > 0 && written >= opts.LimitTotal {
+				break
+			}
+
+			if opts.NDJSON {
+				if err := rb.writeJSON(out, []byte(object.Raw), true); err != nil {
+					return err
+				}
+			} else {
+				objects = append(objects, object.Raw)
+			}
+			written++
+		}
+
+		if !page.Get("has_more").Bool() || len(data) == 0 || (opts.LimitTotal > 0 && written >= opts.LimitTotal) {
+			break
+		}
+
+		switch {
+		case page.Get("next_page").String() != "":
+			pageParams.data = append(append([]string{}, params.data...), "page="+page.Get("next_page").String())
+		case backwards:
+			pageParams.endingBefore = data[0].Get("id").String()
+		default:
+			pageParams.startingAfter = data[len(data)-1].Get("id").String()
+		}
+	}
+
+	if !opts.NDJSON {
+		merged := pretty.Pretty([]byte("[" + strings.Join(objects, ",") + "]"))
+		return rb.writeJSON(out, merged, false)
+	}
+
+	return nil
+}
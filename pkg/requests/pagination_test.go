@@ -0,0 +1,164 @@
+package requests
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// newListServer serves a list of 5 charges, one page at a time
+func newListServer(t *testing.T, queries *[]string) *httptest.Server {
+	ids := []string{"ch_1", "ch_2", "ch_3", "ch_4", "ch_5"}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*queries = append(*queries, r.URL.RawQuery)
+
+		limit := 10
+		fmt.Sscan(r.URL.Query().Get("limit"), &limit)
+
+		start := 0
+		if after := r.URL.Query().Get("starting_after"); after != "" {
+			for i, id := range ids {
+				if id == after {
+					start = i + 1
+				}
+			}
+		}
+
+		end := start + limit
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		data := ""
+		for i, id := range ids[start:end] {
+			if i > 0 {
+				data += ","
+			}
+			data += fmt.Sprintf(`{"id": "%s", "object": "charge"}`, id)
+		}
+
+		fmt.Fprintf(w, `{"object": "list", "data": [%s], "has_more": %t}`, data, end < len(ids))
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestMakePaginatedRequest(t *testing.T) {
+	var queries []string
+	ts := newListServer(t, &queries)
+
+	rb := Base{APIBaseURL: ts.URL, Method: http.MethodGet}
+	var out bytes.Buffer
+
+	err := rb.MakePaginatedRequest(context.Background(), "sk_test_1234", "/v1/charges", &RequestParameters{}, PaginationOptions{PageSize: 2, NDJSON: true}, &out)
+	require.NoError(t, err)
+
+	require.Equal(t, `{"id":"ch_1","object":"charge"}
+{"id":"ch_2","object":"charge"}
+{"id":"ch_3","object":"charge"}
+{"id":"ch_4","object":"charge"}
+{"id":"ch_5","object":"charge"}
+`, out.String())
+	require.Equal(t, []string{"limit=2", "limit=2&starting_after=ch_2", "limit=2&starting_after=ch_4"}, queries)
+}
+
+func TestMakePaginatedRequest_LimitTotal(t *testing.T) {
+	var queries []string
+	ts := newListServer(t, &queries)
+
+	rb := Base{APIBaseURL: ts.URL, Method: http.MethodGet}
+	var out bytes.Buffer
+
+	err := rb.MakePaginatedRequest(context.Background(), "sk_test_1234", "/v1/charges", &RequestParameters{}, PaginationOptions{LimitTotal: 3, PageSize: 2}, &out)
+	require.NoError(t, err)
+
+	require.JSONEq(t, `[{"id":"ch_1","object":"charge"},{"id":"ch_2","object":"charge"},{"id":"ch_3","object":"charge"}]`, out.String())
+	require.Equal(t, []string{"limit=2", "limit=1&starting_after=ch_2"}, queries)
+}
+
+func TestMakePaginatedRequest_Query(t *testing.T) {
+	var queries []string
+	ts := newListServer(t, &queries)
+
+	rb := Base{APIBaseURL: ts.URL, Method: http.MethodGet, raw: true}
+	require.NoError(t, rb.query.Set("[?id != 'ch_2'].id"))
+	var out bytes.Buffer
+
+	err := rb.MakePaginatedRequest(context.Background(), "sk_test_1234", "/v1/charges", &RequestParameters{}, PaginationOptions{LimitTotal: 3}, &out)
+	require.NoError(t, err)
+	require.Equal(t, "ch_1\nch_3\n", out.String())
+
+	// with NDJSON, the query applies to each object
+	rb.raw = false
+	require.NoError(t, rb.query.Set("{id: id}"))
+	out.Reset()
+
+	err = rb.MakePaginatedRequest(context.Background(), "sk_test_1234", "/v1/charges", &RequestParameters{}, PaginationOptions{LimitTotal: 2, NDJSON: true}, &out)
+	require.NoError(t, err)
+	require.Equal(t, "{\"id\":\"ch_1\"}\n{\"id\":\"ch_2\"}\n", out.String())
+}
+
+func TestMakePaginatedRequest_RetriesWhenRateLimited(t *testing.T) {
+	rateLimitBackoff = time.Millisecond
+	t.Cleanup(func() { rateLimitBackoff = time.Second })
+
+	previous := rateLimitLog
+	rateLimitLog = NewRateLimitLog(afero.NewMemMapFs(), "/ratelimits.ndjson")
+	t.Cleanup(func() { rateLimitLog = previous })
+
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error": {"type": "invalid_request_error", "code": "rate_limit"}}`))
+			return
+		}
+
+		w.Write([]byte(`{"object": "list", "data": [{"id": "ch_1"}], "has_more": false}`))
+	}))
+	defer ts.Close()
+
+	rb := Base{APIBaseURL: ts.URL, Method: http.MethodGet}
+	var out bytes.Buffer
+
+	err := rb.MakePaginatedRequest(context.Background(), "sk_test_1234", "/v1/charges", &RequestParameters{}, PaginationOptions{NDJSON: true}, &out)
+	require.NoError(t, err)
+	require.Equal(t, 3, attempts)
+	require.Equal(t, "{\"id\":\"ch_1\"}\n", out.String())
+
+	observations, err := rateLimitLog.Read(time.Time{})
+	require.NoError(t, err)
+
+	rateLimited, backoffs := 0, time.Duration(0)
+	for _, observation := range observations {
+		require.Equal(t, "/v1/charges", observation.Endpoint)
+		if observation.RateLimited() {
+			rateLimited++
+		}
+		backoffs += observation.Backoff
+	}
+	require.Equal(t, 2, rateLimited)
+	require.Equal(t, 3*time.Millisecond, backoffs)
+}
+
+func TestMakePaginatedRequest_NotAList(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "ch_1", "object": "charge"}`))
+	}))
+	defer ts.Close()
+
+	rb := Base{APIBaseURL: ts.URL, Method: http.MethodGet}
+
+	err := rb.MakePaginatedRequest(context.Background(), "sk_test_1234", "/v1/charges/ch_1", &RequestParameters{}, PaginationOptions{}, &bytes.Buffer{})
+	require.EqualError(t, err, "pagination is only supported for list and search requests")
+}
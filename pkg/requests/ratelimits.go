@@ -0,0 +1,305 @@
+package requests
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+
+	"github.com/stripe/stripe-cli/pkg/config"
+)
+
+// RateLimitLogRetention is how long rate limit observations are kept for
+const RateLimitLogRetention = 24 * time.Hour
+
+// maxRateLimitLogSize is how large the rate limit log may grow before the
+// observations older than RateLimitLogRetention are dropped from it
+const maxRateLimitLogSize = 512 * 1024
+
+// RateLimitObservation is what the CLI learned about rate limits from a
+// response, or a wait it applied before retrying a rate limited request
+type RateLimitObservation struct {
+	Time     time.Time `json:"time"`
+	Method   string    `json:"method"`
+	Endpoint string    `json:"endpoint"`
+	Livemode bool      `json:"livemode"`
+
+	StatusCode int `json:"status_code,omitempty"`
+	// Reason is the Stripe-Rate-Limited-Reason header of rate limited
+	// responses, e.g. global-rate or endpoint-concurrency
+	Reason string `json:"reason,omitempty"`
+	// Limit and Remaining are only set when the response had rate limit
+	// headers
+	Limit      int           `json:"limit,omitempty"`
+	Remaining  int           `json:"remaining,omitempty"`
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
+
+	// Backoff is how long the CLI waited before retrying the request
+	Backoff time.Duration `json:"backoff,omitempty"`
+}
+
+// RateLimited returns whether the request was rejected by a rate limiter
+func (o RateLimitObservation) RateLimited() bool {
+	return o.StatusCode == http.StatusTooManyRequests
+}
+
+// Concurrency returns whether the request was rejected by a concurrency
+// limiter rather than a rate limiter
+func (o RateLimitObservation) Concurrency() bool {
+	return strings.HasSuffix(o.Reason, "-concurrency")
+}
+
+// RateLimitLog keeps the rate limits observed across invocations of the CLI,
+// so they can be reported by `stripe limits`
+type RateLimitLog struct {
+	Fs afero.Fs
+	// Path is the file the log is kept in. If empty, the default location in
+	// the CLI's config folder is used.
+	Path string
+
+	mu sync.Mutex
+}
+
+// rateLimitLog is where requests record the rate limits they observe. It's a
+// variable so tests can record them in memory instead.
+var rateLimitLog = &RateLimitLog{Fs: afero.NewOsFs()}
+
+// NewRateLimitLog returns the log of rate limits kept at path. If path is
+// empty, the default location in the CLI's config folder is used.
+func NewRateLimitLog(fs afero.Fs, path string) *RateLimitLog {
+	return &RateLimitLog{Fs: fs, Path: path}
+}
+
+// DefaultRateLimitLogPath returns the default location of the rate limit log
+func DefaultRateLimitLogPath() string {
+	cfg := &config.Config{}
+	return filepath.Join(cfg.GetConfigFolder(os.Getenv("XDG_CONFIG_HOME")), "ratelimits.ndjson")
+}
+
+func (l *RateLimitLog) path() string {
+	if l.Path == "" {
+		return DefaultRateLimitLogPath()
+	}
+
+	return l.Path
+}
+
+// Record adds an observation to the log
+func (l *RateLimitLog) Record(observation RateLimitObservation) error {
+	line, err := json.Marshal(observation)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	path := l.path()
+
+	if err := l.Fs.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	if info, err := l.Fs.Stat(path); err == nil && info.Size() > maxRateLimitLogSize {
+		if err := l.prune(path, observation.Time.Add(-RateLimitLogRetention)); err != nil {
+			return err
+		}
+	}
+
+	file, err := l.Fs.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+
+	return err
+}
+
+// Read returns the observations recorded since the given time, oldest first
+func (l *RateLimitLog) Read(since time.Time) ([]RateLimitObservation, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.read(l.path(), since)
+}
+
+// Clear removes every observation from the log
+func (l *RateLimitLog) Clear() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	err := l.Fs.Remove(l.path())
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (l *RateLimitLog) read(path string, since time.Time) ([]RateLimitObservation, error) {
+	file, err := l.Fs.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var observations []RateLimitObservation
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var observation RateLimitObservation
+		// a line may have been cut short by an invocation that was
+		// interrupted while writing it, so skip the ones that can't be read
+		if err := json.Unmarshal(scanner.Bytes(), &observation); err != nil {
+			continue
+		}
+
+		if !observation.Time.Before(since) {
+			observations = append(observations, observation)
+		}
+	}
+
+	return observations, scanner.Err()
+}
+
+// prune drops the observations older than before from the log
+func (l *RateLimitLog) prune(path string, before time.Time) error {
+	observations, err := l.read(path, before)
+	if err != nil {
+		return err
+	}
+
+	file, err := l.Fs.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for _, observation := range observations {
+		line, err := json.Marshal(observation)
+		if err != nil {
+			return err
+		}
+		writer.Write(append(line, '\n'))
+	}
+
+	return writer.Flush()
+}
+
+// observeRateLimit returns what the response tells about rate limits. ok is
+// false when the response was not rate limited and had no rate limit headers.
+func observeRateLimit(method, path, apiKey string, resp *http.Response) (observation RateLimitObservation, ok bool) {
+	observation = RateLimitObservation{
+		Time:     time.Now(),
+		Method:   method,
+		Endpoint: rateLimitEndpoint(path),
+		Livemode: strings.Contains(apiKey, "live"),
+		Reason:   resp.Header.Get("Stripe-Rate-Limited-Reason"),
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		observation.StatusCode = resp.StatusCode
+		ok = true
+	}
+
+	limit, hasLimit := rateLimitHeader(resp.Header, "Limit")
+	remaining, hasRemaining := rateLimitHeader(resp.Header, "Remaining")
+	if hasLimit && hasRemaining {
+		observation.Limit = limit
+		observation.Remaining = remaining
+		observation.StatusCode = resp.StatusCode
+		ok = true
+	}
+
+	observation.RetryAfter = parseRetryAfter(resp.Header)
+
+	return observation, ok
+}
+
+// parseRetryAfter returns how long the Retry-After header asks to wait, or
+// zero if there's no such header
+func parseRetryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// rateLimitHeader reads the leading number of the RateLimit-<name> header, or
+// of its X-RateLimit-<name> variant. The standard header may be followed by a
+// policy, as in "100, 100;w=1".
+func rateLimitHeader(header http.Header, name string) (int, bool) {
+	value := header.Get("RateLimit-" + name)
+	if value == "" {
+		value = header.Get("X-RateLimit-" + name)
+	}
+
+	if i := strings.IndexAny(value, ",;"); i >= 0 {
+		value = value[:i]
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+
+	return n, err == nil
+}
+
+// rateLimitEndpoint returns the path without its query and with object IDs
+// replaced, so the requests for different objects are counted together
+func rateLimitEndpoint(path string) string {
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isObjectID(segment) {
+			segments[i] = "{id}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// isObjectID returns whether a path segment looks like an object ID, such as
+// cus_NffrFeUfNV2Hib, rather than a resource name like payment_intents
+func isObjectID(segment string) bool {
+	prefix, rest, found := strings.Cut(segment, "_")
+	if !found || prefix == "" || rest == "" {
+		return false
+	}
+
+	return strings.ContainsAny(rest, "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+}
+
+// recordRateLimit records the rate limits the response tells about, if any
+func recordRateLimit(method, path, apiKey string, resp *http.Response) {
+	observation, ok := observeRateLimit(method, path, apiKey, resp)
+	if !ok {
+		return
+	}
+
+	recordRateLimitObservation(observation)
+}
+
+func recordRateLimitObservation(observation RateLimitObservation) {
+	if err := rateLimitLog.Record(observation); err != nil {
+		log.WithFields(log.Fields{
+			"prefix": "requests.recordRateLimit",
+		}).Debugf("Failed to record rate limit: %v", err)
+	}
+}
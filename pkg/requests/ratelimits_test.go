@@ -0,0 +1,96 @@
+package requests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveRateLimit(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Stripe-Rate-Limited-Reason", "endpoint-concurrency")
+	resp.Header.Set("Retry-After", "2")
+
+	observation, ok := observeRateLimit(http.MethodPost, "/v1/customers/cus_NffrFeUfNV2Hib", "sk_live_1234", resp)
+	require.True(t, ok)
+	require.Equal(t, "/v1/customers/{id}", observation.Endpoint)
+	require.True(t, observation.Livemode)
+	require.True(t, observation.RateLimited())
+	require.True(t, observation.Concurrency())
+	require.Equal(t, 2*time.Second, observation.RetryAfter)
+}
+
+func TestObserveRateLimit_Headers(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	resp.Header.Set("RateLimit-Limit", "100, 100;w=1")
+	resp.Header.Set("X-RateLimit-Remaining", "42")
+
+	observation, ok := observeRateLimit(http.MethodGet, "/v1/charges?limit=3", "sk_test_1234", resp)
+	require.True(t, ok)
+	require.Equal(t, "/v1/charges", observation.Endpoint)
+	require.False(t, observation.RateLimited())
+	require.Equal(t, 100, observation.Limit)
+	require.Equal(t, 42, observation.Remaining)
+}
+
+func TestObserveRateLimit_NothingToObserve(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+
+	_, ok := observeRateLimit(http.MethodGet, "/v1/charges", "sk_test_1234", resp)
+	require.False(t, ok)
+}
+
+func TestRateLimitEndpoint(t *testing.T) {
+	require.Equal(t, "/v1/payment_intents/{id}/confirm", rateLimitEndpoint("/v1/payment_intents/pi_3MtwBwLkdIwHu7ix28a3tqPa/confirm"))
+	require.Equal(t, "/v1/payment_method_domains", rateLimitEndpoint("/v1/payment_method_domains"))
+	require.Equal(t, "/v1/billing_portal/sessions", rateLimitEndpoint("/v1/billing_portal/sessions"))
+}
+
+func TestRateLimitLog(t *testing.T) {
+	log := NewRateLimitLog(afero.NewMemMapFs(), "/stripe/ratelimits.ndjson")
+	now := time.Now()
+
+	require.NoError(t, log.Record(RateLimitObservation{Time: now.Add(-2 * time.Hour), Endpoint: "/v1/old"}))
+	require.NoError(t, log.Record(RateLimitObservation{Time: now, Endpoint: "/v1/new"}))
+
+	observations, err := log.Read(now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, observations, 1)
+	require.Equal(t, "/v1/new", observations[0].Endpoint)
+
+	require.NoError(t, log.Clear())
+	observations, err = log.Read(time.Time{})
+	require.NoError(t, err)
+	require.Empty(t, observations)
+}
+
+func TestRateLimitLog_Prunes(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	log := NewRateLimitLog(fs, "/ratelimits.ndjson")
+	now := time.Now()
+
+	endpoint := "/v1/" + strings.Repeat("a", 1024)
+	for fileSize(t, fs, "/ratelimits.ndjson") <= maxRateLimitLogSize {
+		require.NoError(t, log.Record(RateLimitObservation{Time: now.Add(-48 * time.Hour), Endpoint: endpoint}))
+	}
+
+	require.NoError(t, log.Record(RateLimitObservation{Time: now, Endpoint: "/v1/new"}))
+
+	observations, err := log.Read(time.Time{})
+	require.NoError(t, err)
+	require.Len(t, observations, 1)
+	require.Equal(t, "/v1/new", observations[0].Endpoint)
+}
+
+func fileSize(t *testing.T, fs afero.Fs, path string) int64 {
+	info, err := fs.Stat(path)
+	if err != nil {
+		return 0
+	}
+
+	return info.Size()
+}
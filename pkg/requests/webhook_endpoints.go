@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
 )
 
@@ -17,10 +18,13 @@ type WebhookEndpointList struct {
 
 // WebhookEndpoint contains the data for each webhook endpoint
 type WebhookEndpoint struct {
+	ID            string   `json:"id"`
 	Application   string   `json:"application"`
 	EnabledEvents []string `json:"enabled_events"`
 	URL           string   `json:"url"`
 	Status        string   `json:"status"`
+	// Secret is only returned when the endpoint is created
+	Secret string `json:"secret"`
 }
 
 // WebhookEndpointsList returns all the webhook endpoints on a users' account
@@ -46,7 +50,7 @@ func WebhookEndpointsList(ctx context.Context, baseURL, apiVersion, apiKey strin
 // WebhookEndpointCreate creates a new webhook endpoint
 func WebhookEndpointCreate(ctx context.Context, baseURL, apiVersion, apiKey, url, description string, connect bool, profile *config.Profile) error {
 	if strings.TrimSpace(url) == "" {
-		return fmt.Errorf("url cannot be empty")
+		return clierrors.New(clierrors.InvalidArgs, "url cannot be empty")
 	}
 
 	data := []string{
@@ -77,3 +81,63 @@ func WebhookEndpointCreate(ctx context.Context, baseURL, apiVersion, apiKey, url
 	}
 	return nil
 }
+
+// WebhookEndpointRegister creates a webhook endpoint receiving the given
+// events, tagged as created by the CLI, and returns it along with its signing
+// secret
+func WebhookEndpointRegister(ctx context.Context, baseURL, apiVersion, apiKey, url, description string, events []string, livemode bool, profile *config.Profile) (*WebhookEndpoint, error) {
+	if strings.TrimSpace(url) == "" {
+		return nil, clierrors.New(clierrors.InvalidArgs, "url cannot be empty")
+	}
+
+	data := []string{fmt.Sprintf("url=%s", url)}
+	for _, event := range events {
+		data = append(data, fmt.Sprintf("enabled_events[]=%s", event))
+	}
+	if description != "" {
+		data = append(data, fmt.Sprintf("description=%s", description))
+	}
+	data = append(data, "metadata[created_by]=stripe-cli")
+
+	params := &RequestParameters{
+		data:    data,
+		version: apiVersion,
+	}
+
+	base := &Base{
+		Profile:        profile,
+		Method:         http.MethodPost,
+		SuppressOutput: true,
+		APIBaseURL:     baseURL,
+		Livemode:       livemode,
+	}
+	resp, err := base.MakeRequest(ctx, apiKey, "/v1/webhook_endpoints", params, true)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &WebhookEndpoint{}
+	if err := json.Unmarshal(resp, endpoint); err != nil {
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+// WebhookEndpointDelete deletes a webhook endpoint
+func WebhookEndpointDelete(ctx context.Context, baseURL, apiVersion, apiKey, id string, livemode bool, profile *config.Profile) error {
+	params := &RequestParameters{
+		version: apiVersion,
+	}
+
+	base := &Base{
+		Profile:        profile,
+		Method:         http.MethodDelete,
+		SuppressOutput: true,
+		APIBaseURL:     baseURL,
+		Livemode:       livemode,
+	}
+	_, err := base.MakeRequest(ctx, apiKey, "/v1/webhook_endpoints/"+id, params, true)
+
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
@@ -44,8 +45,25 @@ func (srv *RPCService) Listen(req *rpc.ListenRequest, stream rpc.StripeCLI_Liste
 		return status.Error(codes.Unauthenticated, err.Error())
 	}
 
+	// With an ack consumer, events are redelivered until they're acked
+	var consumer *ackConsumer
+	var ackTicks <-chan time.Time
+	var redeliver <-chan struct{}
+	if req.AckConsumer != "" {
+		consumer, err = srv.acks.connect(req)
+		if err != nil {
+			return err
+		}
+		defer consumer.disconnect()
+
+		ticker := time.NewTicker(ackCheckInterval)
+		defer ticker.Stop()
+		ackTicks = ticker.C
+		redeliver = consumer.wake
+	}
+
 	logger := log.StandardLogger()
-	proxyVisitor := createProxyVisitor(&stream)
+	proxyVisitor := createProxyVisitor(&stream, consumer)
 	proxyOutCh := make(chan websocket.IElement)
 
 	ctx, cancel := context.WithCancel(stream.Context())
@@ -83,13 +101,32 @@ func (srv *RPCService) Listen(req *rpc.ListenRequest, stream rpc.StripeCLI_Liste
 			if err != nil {
 				return err
 			}
+		case <-ackTicks:
+			if err := redeliverEvents(stream, consumer); err != nil {
+				return err
+			}
+		case <-redeliver:
+			if err := redeliverEvents(stream, consumer); err != nil {
+				return err
+			}
 		case <-stream.Context().Done():
 			return stream.Context().Err()
 		}
 	}
 }
 
-func createProxyVisitor(stream *rpc.StripeCLI_ListenServer) *websocket.Visitor {
+func redeliverEvents(stream rpc.StripeCLI_ListenServer, consumer *ackConsumer) error {
+	for _, resp := range consumer.due(time.Now()) {
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createProxyVisitor sends what the proxy outputs to the stream. The events
+// sent are recorded for consumer to ack, when it isn't nil.
+func createProxyVisitor(stream *rpc.StripeCLI_ListenServer, consumer *ackConsumer) *websocket.Visitor {
 	return &websocket.Visitor{
 		VisitError: func(ee websocket.ErrorElement) error {
 			switch ee.Error.(type) {
@@ -108,6 +145,9 @@ func createProxyVisitor(stream *rpc.StripeCLI_ListenServer) *websocket.Visitor {
 				if err != nil {
 					return err
 				}
+				if consumer != nil {
+					consumer.deliver(resp)
+				}
 				(*stream).Send(resp)
 				return nil
 			case proxy.EndpointResponse:
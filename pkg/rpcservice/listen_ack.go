@@ -0,0 +1,305 @@
+package rpcservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/stripe/stripe-cli/rpc"
+)
+
+const (
+	defaultMaxDeliveryAttempts = 3
+	defaultAckTimeout          = 30 * time.Second
+)
+
+// ackCheckInterval is how often the events which weren't acked in time are
+// redelivered
+var ackCheckInterval = time.Second
+
+// ackConsumers keeps the events delivered to the Listen streams of each ack
+// consumer until they're acked. Consumers outlive their streams, so that the
+// events of a consumer which crashed are redelivered once it reconnects.
+type ackConsumers struct {
+	log *log.Logger
+
+	mu        sync.Mutex
+	consumers map[string]*ackConsumer
+}
+
+type ackConsumer struct {
+	name string
+	log  *log.Logger
+
+	// wake tells the stream of the consumer that events are due for
+	// redelivery
+	wake chan struct{}
+
+	mu             sync.Mutex
+	connected      bool
+	maxAttempts    int
+	timeout        time.Duration
+	deadLetterFile string
+	pending        map[string]*pendingEvent
+	// order keeps the IDs of the pending events in delivery order
+	order []string
+}
+
+type pendingEvent struct {
+	event    *rpc.StripeEvent
+	attempts int
+	deadline time.Time
+}
+
+// deadLetter is a line of the dead letter file
+type deadLetter struct {
+	Consumer       string          `json:"consumer"`
+	Attempts       int             `json:"attempts"`
+	Reason         string          `json:"reason"`
+	DeadLetteredAt string          `json:"dead_lettered_at"`
+	Event          json.RawMessage `json:"event"`
+}
+
+func newAckConsumers(logger *log.Logger) *ackConsumers {
+	return &ackConsumers{
+		log:       logger,
+		consumers: make(map[string]*ackConsumer),
+	}
+}
+
+// connect attaches a Listen stream to the consumer of req. The events the
+// consumer didn't ack before its previous stream ended are redelivered first.
+func (a *ackConsumers) connect(req *rpc.ListenRequest) (*ackConsumer, error) {
+	if req.MaxDeliveryAttempts < 0 {
+		return nil, status.Error(codes.InvalidArgument, "max_delivery_attempts can't be negative")
+	}
+	if req.AckTimeoutSeconds < 0 {
+		return nil, status.Error(codes.InvalidArgument, "ack_timeout_seconds can't be negative")
+	}
+
+	a.mu.Lock()
+	c, ok := a.consumers[req.AckConsumer]
+	if !ok {
+		c = &ackConsumer{
+			name:    req.AckConsumer,
+			log:     a.log,
+			wake:    make(chan struct{}, 1),
+			pending: make(map[string]*pendingEvent),
+		}
+		a.consumers[req.AckConsumer] = c
+	}
+	a.mu.Unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.connected {
+		return nil, status.Errorf(codes.AlreadyExists, "consumer %s is already listening", c.name)
+	}
+	c.connected = true
+
+	c.maxAttempts = defaultMaxDeliveryAttempts
+	if req.MaxDeliveryAttempts > 0 {
+		c.maxAttempts = int(req.MaxDeliveryAttempts)
+	}
+	c.timeout = defaultAckTimeout
+	if req.AckTimeoutSeconds > 0 {
+		c.timeout = time.Duration(req.AckTimeoutSeconds) * time.Second
+	}
+	c.deadLetterFile = req.DeadLetterFile
+
+	if len(c.pending) > 0 {
+		for _, pe := range c.pending {
+			pe.deadline = time.Time{}
+		}
+		c.signal()
+	}
+
+	return c, nil
+}
+
+// disconnect detaches the stream of the consumer, keeping its pending events
+func (c *ackConsumer) disconnect() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.connected = false
+}
+
+// deliver records that the event of resp is being sent to the consumer
+func (c *ackConsumer) deliver(resp *rpc.ListenResponse) {
+	event := resp.GetStripeEvent()
+	if event == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pe, ok := c.pending[event.Id]
+	if !ok {
+		pe = &pendingEvent{event: event}
+		c.pending[event.Id] = pe
+		c.order = append(c.order, event.Id)
+	}
+	pe.attempts++
+	pe.deadline = time.Now().Add(c.timeout)
+
+	resp.DeliveryAttempt = int32(pe.attempts)
+}
+
+// due returns the events to redeliver at now, dead-lettering those which were
+// delivered too many times already
+func (c *ackConsumer) due(now time.Time) []*rpc.ListenResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var redeliveries []*rpc.ListenResponse
+
+	for _, id := range append([]string(nil), c.order...) {
+		pe := c.pending[id]
+		if pe.deadline.After(now) {
+			continue
+		}
+
+		if pe.attempts >= c.maxAttempts {
+			c.deadLetter(pe, fmt.Sprintf("not acked after %d deliveries", pe.attempts))
+			continue
+		}
+
+		pe.attempts++
+		pe.deadline = now.Add(c.timeout)
+		redeliveries = append(redeliveries, &rpc.ListenResponse{
+			Content:         &rpc.ListenResponse_StripeEvent{StripeEvent: pe.event},
+			DeliveryAttempt: int32(pe.attempts),
+		})
+	}
+
+	return redeliveries
+}
+
+// ack acknowledges or rejects a pending event. It returns whether a rejected
+// event was dead-lettered.
+func (c *ackConsumer) ack(eventID string, nack bool, reason string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pe, ok := c.pending[eventID]
+	if !ok {
+		return false, status.Errorf(codes.NotFound, "event %s isn't waiting for an ack from consumer %s", eventID, c.name)
+	}
+
+	if !nack {
+		c.remove(eventID)
+		return false, nil
+	}
+
+	if reason == "" {
+		reason = "rejected by the consumer"
+	}
+
+	if pe.attempts >= c.maxAttempts {
+		c.deadLetter(pe, reason)
+		return true, nil
+	}
+
+	pe.deadline = time.Time{}
+	c.signal()
+
+	return false, nil
+}
+
+// deadLetter removes the event and appends it to the dead letter file
+func (c *ackConsumer) deadLetter(pe *pendingEvent, reason string) {
+	c.remove(pe.event.Id)
+
+	if c.deadLetterFile == "" {
+		c.log.WithFields(log.Fields{
+			"prefix": "rpcservice.ackConsumer.deadLetter",
+		}).Warnf("Dropped event %s of consumer %s: %s", pe.event.Id, c.name, reason)
+		return
+	}
+
+	if err := c.writeDeadLetter(pe, reason); err != nil {
+		c.log.WithFields(log.Fields{
+			"prefix": "rpcservice.ackConsumer.deadLetter",
+		}).Errorf("Failed to dead-letter event %s of consumer %s: %v", pe.event.Id, c.name, err)
+	}
+}
+
+func (c *ackConsumer) writeDeadLetter(pe *pendingEvent, reason string) error {
+	event, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(pe.event)
+	if err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(deadLetter{
+		Consumer:       c.name,
+		Attempts:       pe.attempts,
+		Reason:         reason,
+		DeadLetteredAt: time.Now().UTC().Format(time.RFC3339),
+		Event:          event,
+	})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(c.deadLetterFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+func (c *ackConsumer) remove(eventID string) {
+	delete(c.pending, eventID)
+
+	for i, id := range c.order {
+		if id == eventID {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (c *ackConsumer) signal() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// ListenAck acknowledges or rejects an event received from a Listen stream
+func (srv *RPCService) ListenAck(ctx context.Context, req *rpc.ListenAckRequest) (*rpc.ListenAckResponse, error) {
+	if req.Consumer == "" {
+		return nil, status.Error(codes.InvalidArgument, "Consumer is required")
+	}
+	if req.EventId == "" {
+		return nil, status.Error(codes.InvalidArgument, "Event ID is required")
+	}
+
+	srv.acks.mu.Lock()
+	c, ok := srv.acks.consumers[req.Consumer]
+	srv.acks.mu.Unlock()
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown consumer %s", req.Consumer)
+	}
+
+	deadLettered, err := c.ack(req.EventId, req.Nack, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rpc.ListenAckResponse{DeadLettered: deadLettered}, nil
+}
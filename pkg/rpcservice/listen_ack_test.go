@@ -0,0 +1,129 @@
+package rpcservice
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/stripe/stripe-cli/pkg/proxy"
+	"github.com/stripe/stripe-cli/pkg/websocket"
+	"github.com/stripe/stripe-cli/rpc"
+)
+
+func TestListenAckRedeliversNackedEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(withAuth(context.Background()))
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+	defer conn.Close()
+	client := rpc.NewStripeCLIClient(conn)
+
+	createProxy = func(ctx context.Context, cfg *proxy.Config) (IProxy, error) {
+		runProxy = func(ctx context.Context) error {
+			cfg.OutCh <- websocket.DataElement{
+				Data: proxy.StripeEvent{
+					ID:   "evt_12345",
+					Type: "checkout.session.completed",
+				},
+			}
+			return nil
+		}
+		return &mockProxy{
+			OutCh: cfg.OutCh,
+		}, nil
+	}
+
+	listenClient, err := client.Listen(ctx, &rpc.ListenRequest{AckConsumer: "test-nack"})
+	assert.Nil(t, err)
+
+	resp, err := listenClient.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, "evt_12345", resp.GetStripeEvent().Id)
+	assert.Equal(t, int32(1), resp.DeliveryAttempt)
+
+	ackResp, err := client.ListenAck(ctx, &rpc.ListenAckRequest{Consumer: "test-nack", EventId: "evt_12345", Nack: true})
+	assert.Nil(t, err)
+	assert.False(t, ackResp.DeadLettered)
+
+	resp, err = listenClient.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, "evt_12345", resp.GetStripeEvent().Id)
+	assert.Equal(t, int32(2), resp.DeliveryAttempt)
+
+	_, err = client.ListenAck(ctx, &rpc.ListenAckRequest{Consumer: "test-nack", EventId: "evt_12345"})
+	assert.Nil(t, err)
+
+	_, err = client.ListenAck(ctx, &rpc.ListenAckRequest{Consumer: "test-nack", EventId: "evt_12345"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestListenAckUnknownConsumer(t *testing.T) {
+	ctx, cancel := context.WithCancel(withAuth(context.Background()))
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+	defer conn.Close()
+	client := rpc.NewStripeCLIClient(conn)
+
+	_, err = client.ListenAck(ctx, &rpc.ListenAckRequest{Consumer: "nobody", EventId: "evt_12345"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestAckConsumerRedeliversAfterReconnect(t *testing.T) {
+	acks := newAckConsumers(&log.Logger{Out: io.Discard})
+	deadLetterFile := filepath.Join(t.TempDir(), "dead_letters.jsonl")
+	req := &rpc.ListenRequest{AckConsumer: "crashy", MaxDeliveryAttempts: 2, DeadLetterFile: deadLetterFile}
+
+	c, err := acks.connect(req)
+	assert.Nil(t, err)
+
+	_, err = acks.connect(req)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	resp := &rpc.ListenResponse{
+		Content: &rpc.ListenResponse_StripeEvent{StripeEvent: &rpc.StripeEvent{Id: "evt_12345"}},
+	}
+	c.deliver(resp)
+	assert.Equal(t, int32(1), resp.DeliveryAttempt)
+	assert.Empty(t, c.due(time.Now()))
+
+	// the consumer crashes, then listens again
+	c.disconnect()
+	c, err = acks.connect(req)
+	assert.Nil(t, err)
+
+	redeliveries := c.due(time.Now())
+	assert.Len(t, redeliveries, 1)
+	assert.Equal(t, int32(2), redeliveries[0].DeliveryAttempt)
+
+	// it isn't acked in time on the last attempt either
+	assert.Empty(t, c.due(time.Now().Add(defaultAckTimeout+time.Second)))
+	assert.Empty(t, c.pending)
+
+	data, err := os.ReadFile(deadLetterFile)
+	assert.Nil(t, err)
+
+	var letter deadLetter
+	assert.Nil(t, json.Unmarshal(data, &letter))
+	assert.Equal(t, "crashy", letter.Consumer)
+	assert.Equal(t, 2, letter.Attempts)
+	assert.Equal(t, "not acked after 2 deliveries", letter.Reason)
+	assert.JSONEq(t, `{"id": "evt_12345"}`, string(letter.Event))
+}
@@ -36,6 +36,10 @@ type RPCService struct {
 
 	grpcServer *grpc.Server
 
+	// acks keeps the events of the Listen streams with an ack consumer until
+	// they're acked
+	acks *ackConsumers
+
 	// TelemetryClient to use for sending telemetry events
 	TelemetryClient stripe.TelemetryClient
 }
@@ -67,6 +71,7 @@ func New(
 	return &RPCService{
 		cfg:             cfg,
 		grpcServer:      grpcServer,
+		acks:            newAckConsumers(cfg.Log),
 		TelemetryClient: telemetryClient,
 	}
 }
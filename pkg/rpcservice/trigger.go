@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/stripe/stripe-cli/pkg/fixtures"
+	"github.com/stripe/stripe-cli/pkg/git"
 	"github.com/stripe/stripe-cli/pkg/stripe"
 	"github.com/stripe/stripe-cli/rpc"
 
@@ -20,6 +21,11 @@ func (srv *RPCService) Trigger(ctx context.Context, req *rpc.TriggerRequest) (*r
 		return nil, status.Error(codes.Unauthenticated, err.Error())
 	}
 
+	metadata := srv.cfg.UserCfg.Profile.GetDefaultMetadata()
+	if srv.cfg.UserCfg.Profile.GetGitMetadata() {
+		metadata = git.TagMetadata(metadata)
+	}
+
 	requestNames, err := fixtures.Trigger(
 		ctx,
 		req.Event,
@@ -32,6 +38,7 @@ func (srv *RPCService) Trigger(ctx context.Context, req *rpc.TriggerRequest) (*r
 		req.Remove,
 		req.Raw,
 		req.ApiVersion,
+		metadata,
 	)
 	if err != nil {
 		return nil, err
@@ -2,13 +2,13 @@ package samples
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"os/signal"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
 	gitpkg "github.com/stripe/stripe-cli/pkg/git"
 
@@ -69,7 +69,7 @@ func Create(
 
 	exists, _ := afero.DirExists(sample.Fs, destination)
 	if exists {
-		resultChan <- CreationResult{Err: fmt.Errorf("Path already exists for: %s", destination)}
+		resultChan <- CreationResult{Err: clierrors.Errorf(clierrors.FileExists, "Path already exists for: %s", destination)}
 		return
 	}
 
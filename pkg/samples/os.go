@@ -1,12 +1,13 @@
 package samples
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/spf13/afero"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 )
 
 // cacheFolder is the local directory where we place local copies of samples
@@ -48,7 +49,7 @@ func (s *Samples) MakeFolder(name string) (string, error) {
 			return "", err
 		}
 	} else {
-		return "", fmt.Errorf("Path already exists, aborting: %s", appFolder)
+		return "", clierrors.Errorf(clierrors.FileExists, "Path already exists, aborting: %s", appFolder)
 	}
 
 	return appFolder, nil
@@ -3,7 +3,6 @@ package samples
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,6 +14,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/config"
 	g "github.com/stripe/stripe-cli/pkg/git"
 	gitpkg "github.com/stripe/stripe-cli/pkg/git"
@@ -123,7 +123,7 @@ type Samples struct {
 // 5. parse the sample cli config file
 func (s *Samples) Initialize(app string) error {
 	if app == "" {
-		return errors.New("Sample name is empty")
+		return clierrors.New(clierrors.InvalidArgs, "Sample name is empty")
 	}
 
 	s.name = app
@@ -145,7 +145,7 @@ func (s *Samples) Initialize(app string) error {
 	if _, err := s.Fs.Stat(appPath); os.IsNotExist(err) {
 		sampleData, ok := list[app]
 		if !ok {
-			return fmt.Errorf("Sample %s does not exist", app)
+			return clierrors.Errorf(clierrors.SampleNotFound, "Sample %s does not exist", app)
 		}
 		err = s.Git.Clone(appPath, sampleData.GitRepo())
 		if err != nil {
@@ -204,7 +204,7 @@ func (s *Samples) Copy(target string) error {
 	if s.SelectedConfig.Integration.hasServers() {
 		// empty string is a valid option
 		if s.SelectedConfig.Server != "" && !contains(s.SelectedConfig.Integration.Servers, s.SelectedConfig.Server) {
-			return fmt.Errorf(
+			return clierrors.Errorf(clierrors.SampleNotFound,
 				"Server %s doesn't exist for sample integration %s. Available servers: %v",
 				s.SelectedConfig.Server,
 				integration,
@@ -224,7 +224,7 @@ func (s *Samples) Copy(target string) error {
 	if s.SelectedConfig.Integration.hasClients() {
 		// empty string is a valid option
 		if s.SelectedConfig.Client != "" && !contains(s.SelectedConfig.Integration.Clients, s.SelectedConfig.Client) {
-			return fmt.Errorf(
+			return clierrors.Errorf(clierrors.SampleNotFound,
 				"Client %s doesn't exist for sample integration %s. Available clients: %v",
 				s.SelectedConfig.Client,
 				integration,
@@ -292,7 +292,7 @@ func (s *Samples) ConfigureDotEnv(ctx context.Context, sampleLocation string) er
 
 		publishableKey, _ := s.Config.Profile.GetPublishableKey(false)
 		if publishableKey == "" {
-			return fmt.Errorf("we could not set the publishable key in the .env file; please set this manually or login again to set it automatically next time")
+			return clierrors.New(clierrors.SampleCreationFailed, "we could not set the publishable key in the .env file; please set this manually or login again to set it automatically next time")
 		}
 
 		apiKey, err := s.Config.Profile.GetAPIKey(false)
@@ -395,7 +395,7 @@ func GetSampleConfig(sampleName string, forceRefresh bool) (*SampleConfig, error
 	if _, ok := samplesList[sampleName]; !ok {
 		errorMessage := fmt.Sprintf(`The sample provided is not currently supported by the CLI: %s
 To see supported samples, run 'stripe samples list'`, sampleName)
-		return nil, fmt.Errorf(errorMessage)
+		return nil, clierrors.New(clierrors.SampleNotFound, errorMessage)
 	}
 
 	err = sample.Initialize(sampleName)
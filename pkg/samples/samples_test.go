@@ -1,7 +1,6 @@
 package samples
 
 import (
-	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -94,7 +93,7 @@ func TestInitializeFailsWithEmptyName(t *testing.T) {
 	}
 
 	err := sample.Initialize(name)
-	assert.Equal(t, errors.New("Sample name is empty"), err)
+	assert.EqualError(t, err, "Sample name is empty")
 }
 
 func TestInitializeFailsWithNonexistentSample(t *testing.T) {
@@ -116,5 +115,5 @@ func TestInitializeFailsWithNonexistentSample(t *testing.T) {
 	}
 
 	err := sample.Initialize(name)
-	assert.Equal(t, errors.New("Sample foo does not exist"), err)
+	assert.EqualError(t, err, "Sample foo does not exist")
 }
@@ -0,0 +1,202 @@
+// Package schedule runs CLI commands on a recurring schedule described by cron
+// expressions, from the daemon, so that recurring jobs use the credentials of
+// the CLI instead of needing their own.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// macros are the shorthands accepted in place of the five fields
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field is the allowed values of a field of a cron expression
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+// Cron is a parsed cron expression: minute, hour, day of month, month and day
+// of week, in the local time zone
+type Cron struct {
+	expr string
+
+	minutes, hours, days, months, weekdays uint64
+
+	// when both days of month and days of week are restricted, a time matches
+	// if either does, as in cron
+	daysRestricted, weekdaysRestricted bool
+}
+
+// ParseCron parses a standard five fields cron expression, such as
+// "0 9 * * 1" for every Monday at 9:00, or one of @hourly, @daily, @weekly,
+// @monthly and @yearly. Fields accept lists, ranges and steps, like
+// "0,30 9-17/2 * * 1-5". Sunday is 0 or 7.
+func ParseCron(expr string) (*Cron, error) {
+	spec := strings.TrimSpace(expr)
+	if macro, ok := macros[spec]; ok {
+		spec = macro
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, clierrors.Errorf(clierrors.InvalidCronExpression, "invalid cron expression %q: expected 5 fields (minute hour day-of-month month day-of-week), got %d", expr, len(parts))
+	}
+
+	c := &Cron{expr: expr}
+	sets := []*uint64{&c.minutes, &c.hours, &c.days, &c.months, &c.weekdays}
+
+	for i, part := range parts {
+		f := fields[i]
+		if i == 4 {
+			// Sunday can be written 7
+			f.max = 7
+		}
+
+		set, err := parseField(part, f)
+		if err != nil {
+			return nil, clierrors.Errorf(clierrors.InvalidCronExpression, "invalid cron expression %q: %w", expr, err)
+		}
+		*sets[i] = set
+	}
+
+	if c.weekdays&(1<<7) != 0 {
+		c.weekdays = c.weekdays&^(1<<7) | 1
+	}
+
+	c.daysRestricted = parts[2] != "*"
+	c.weekdaysRestricted = parts[4] != "*"
+
+	return c, nil
+}
+
+func parseField(part string, f field) (uint64, error) {
+	var set uint64
+
+	for _, item := range strings.Split(part, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q in the %s field", stepPart, f.name)
+			}
+		}
+
+		low, high := f.min, f.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			lowPart, highPart, _ := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = parseValue(lowPart, f); err != nil {
+				return 0, err
+			}
+			if high, err = parseValue(highPart, f); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q in the %s field", rangePart, f.name)
+			}
+		default:
+			value, err := parseValue(rangePart, f)
+			if err != nil {
+				return 0, err
+			}
+			low = value
+			if !hasStep {
+				high = value
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			set |= 1 << uint(value)
+		}
+	}
+
+	return set, nil
+}
+
+func parseValue(s string, f field) (int, error) {
+	value, err := strconv.Atoi(s)
+	if err != nil || value < f.min || value > f.max {
+		return 0, fmt.Errorf("invalid value %q in the %s field, expected %d to %d", s, f.name, f.min, f.max)
+	}
+
+	return value, nil
+}
+
+// String returns the expression the schedule was parsed from
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// Matches returns whether the schedule runs at the minute of t
+func (c *Cron) Matches(t time.Time) bool {
+	return has(c.minutes, t.Minute()) && has(c.hours, t.Hour()) && has(c.months, int(t.Month())) && c.matchesDay(t)
+}
+
+func (c *Cron) matchesDay(t time.Time) bool {
+	day := has(c.days, t.Day())
+	weekday := has(c.weekdays, int(t.Weekday()))
+
+	if c.daysRestricted && c.weekdaysRestricted {
+		return day || weekday
+	}
+
+	return day && weekday
+}
+
+// Next returns the first minute after t at which the schedule runs, or the
+// zero time if it never does, such as on February 30
+func (c *Cron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	// every day the schedule can run on comes back within 8 years, counting
+	// leap years
+	end := t.AddDate(8, 0, 0)
+
+	for t.Before(end) {
+		switch {
+		case !has(c.months, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !has(c.hours, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !has(c.minutes, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func has(set uint64, value int) bool {
+	return set&(1<<uint(value)) != 0
+}
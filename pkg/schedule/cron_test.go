@@ -0,0 +1,74 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	} {
+		_, err := ParseCron(expr)
+		require.Error(t, err, expr)
+	}
+}
+
+func TestCronMatches(t *testing.T) {
+	// Monday 9:00
+	monday := time.Date(2022, 8, 1, 9, 0, 0, 0, time.UTC)
+
+	cron, err := ParseCron("0 9 * * 1")
+	require.NoError(t, err)
+	require.True(t, cron.Matches(monday))
+	require.False(t, cron.Matches(monday.Add(time.Minute)))
+	require.False(t, cron.Matches(monday.AddDate(0, 0, 1)))
+
+	cron, err = ParseCron("0,30 9-17/2 * * 1-5")
+	require.NoError(t, err)
+	require.True(t, cron.Matches(monday.Add(30*time.Minute)))
+	require.True(t, cron.Matches(monday.Add(2*time.Hour)))
+	require.False(t, cron.Matches(monday.Add(time.Hour)))
+	require.False(t, cron.Matches(monday.AddDate(0, 0, 5)))
+
+	// Sunday can be written 7
+	cron, err = ParseCron("0 9 * * 7")
+	require.NoError(t, err)
+	require.True(t, cron.Matches(monday.AddDate(0, 0, 6)))
+
+	// with both days restricted, either matches
+	cron, err = ParseCron("0 9 15 * 1")
+	require.NoError(t, err)
+	require.True(t, cron.Matches(monday))
+	require.True(t, cron.Matches(time.Date(2022, 8, 15, 9, 0, 0, 0, time.UTC)))
+	require.False(t, cron.Matches(time.Date(2022, 8, 16, 9, 0, 0, 0, time.UTC)))
+}
+
+func TestCronNext(t *testing.T) {
+	now := time.Date(2022, 8, 3, 14, 27, 45, 0, time.UTC)
+
+	next := func(expr string) time.Time {
+		cron, err := ParseCron(expr)
+		require.NoError(t, err)
+		return cron.Next(now)
+	}
+
+	require.Equal(t, time.Date(2022, 8, 8, 9, 0, 0, 0, time.UTC), next("0 9 * * 1"))
+	require.Equal(t, time.Date(2022, 8, 3, 14, 28, 0, 0, time.UTC), next("* * * * *"))
+	require.Equal(t, time.Date(2022, 8, 3, 14, 30, 0, 0, time.UTC), next("*/15 * * * *"))
+	require.Equal(t, time.Date(2022, 8, 3, 15, 0, 0, 0, time.UTC), next("@hourly"))
+	require.Equal(t, time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC), next("@monthly"))
+	require.Equal(t, time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), next("0 12 29 2 *"))
+	require.True(t, next("0 0 30 2 *").IsZero())
+}
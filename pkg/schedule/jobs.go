@@ -0,0 +1,151 @@
+package schedule
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/uuid"
+	"github.com/spf13/afero"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// FileName is the file of the config folder the jobs are kept in
+const FileName = "schedule.toml"
+
+// Job is a command run on a schedule
+type Job struct {
+	ID       string `toml:"id"`
+	Schedule string `toml:"schedule"`
+	// Args are the arguments of the stripe command, without "stripe"
+	Args []string `toml:"args"`
+	// Profile is the profile the command runs with
+	Profile string    `toml:"profile"`
+	Created time.Time `toml:"created"`
+
+	LastRun time.Time `toml:"last_run,omitempty"`
+	// LastError is empty if the last run succeeded
+	LastError string `toml:"last_error,omitempty"`
+}
+
+// Command returns the command line of the job
+func (j Job) Command() string {
+	return "stripe " + strings.Join(j.Args, " ")
+}
+
+// Cron returns the parsed schedule of the job
+func (j Job) Cron() (*Cron, error) {
+	return ParseCron(j.Schedule)
+}
+
+// Store keeps the jobs in a file
+type Store struct {
+	Fs   afero.Fs
+	Path string
+}
+
+type jobsFile struct {
+	Jobs []Job `toml:"jobs"`
+}
+
+// Jobs returns the scheduled jobs
+func (s *Store) Jobs() ([]Job, error) {
+	data, err := afero.ReadFile(s.Fs, s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var file jobsFile
+	if _, err := toml.Decode(string(data), &file); err != nil {
+		return nil, clierrors.Errorf(clierrors.InvalidScheduleFile, "%s is not a valid schedule file: %w", s.Path, err)
+	}
+
+	return file.Jobs, nil
+}
+
+// Add schedules a new job and returns it
+func (s *Store) Add(schedule string, args []string, profile string) (Job, error) {
+	if _, err := ParseCron(schedule); err != nil {
+		return Job{}, err
+	}
+
+	jobs, err := s.Jobs()
+	if err != nil {
+		return Job{}, err
+	}
+
+	job := Job{
+		ID:       "job_" + strings.ReplaceAll(uuid.NewString(), "-", "")[:8],
+		Schedule: schedule,
+		Args:     args,
+		Profile:  profile,
+		Created:  time.Now().UTC().Truncate(time.Second),
+	}
+
+	return job, s.save(append(jobs, job))
+}
+
+// Remove unschedules the job with the given ID
+func (s *Store) Remove(id string) error {
+	jobs, err := s.Jobs()
+	if err != nil {
+		return err
+	}
+
+	for i, job := range jobs {
+		if job.ID == id {
+			return s.save(append(jobs[:i], jobs[i+1:]...))
+		}
+	}
+
+	return clierrors.Errorf(clierrors.ScheduledJobNotFound, "no scheduled job has the ID %s", id)
+}
+
+// RecordRun saves the outcome of a run of the job, if it's still scheduled
+func (s *Store) RecordRun(id string, at time.Time, runErr error) error {
+	jobs, err := s.Jobs()
+	if err != nil {
+		return err
+	}
+
+	for i := range jobs {
+		if jobs[i].ID != id {
+			continue
+		}
+
+		jobs[i].LastRun = at.UTC().Truncate(time.Second)
+		jobs[i].LastError = ""
+		if runErr != nil {
+			jobs[i].LastError = runErr.Error()
+		}
+
+		return s.save(jobs)
+	}
+
+	return nil
+}
+
+func (s *Store) save(jobs []Job) error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(jobsFile{Jobs: jobs}); err != nil {
+		return err
+	}
+
+	if err := s.Fs.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return err
+	}
+
+	// written to the side then renamed, so the daemon never reads half of it
+	tmp := s.Path + ".tmp"
+	if err := afero.WriteFile(s.Fs, tmp, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+
+	return s.Fs.Rename(tmp, s.Path)
+}
@@ -0,0 +1,162 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Runner runs the command of a job
+type Runner func(ctx context.Context, job Job) error
+
+// Scheduler runs the jobs of a store when they're due. The store is read
+// every minute, so jobs added or removed while it runs are picked up.
+type Scheduler struct {
+	Store *Store
+	Run   Runner
+	Log   *log.Logger
+
+	// Now returns the current time, it's time.Now if nil
+	Now func() time.Time
+
+	mu      sync.Mutex
+	running map[string]bool
+	wg      sync.WaitGroup
+}
+
+// Start runs the jobs as they're due until ctx is done, then waits for the
+// running ones to stop
+func (s *Scheduler) Start(ctx context.Context) {
+	defer s.wg.Wait()
+
+	for {
+		now := s.now()
+		next := now.Truncate(time.Minute).Add(time.Minute)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(next.Sub(now)):
+			s.tick(ctx, next)
+		}
+	}
+}
+
+// tick starts the jobs due at the minute of at. A job still running from a
+// previous run is skipped rather than run twice at once.
+func (s *Scheduler) tick(ctx context.Context, at time.Time) {
+	jobs, err := s.Store.Jobs()
+	if err != nil {
+		s.Log.WithFields(log.Fields{
+			"prefix": "schedule.Scheduler.tick",
+		}).Error(err)
+		return
+	}
+
+	for _, job := range jobs {
+		cron, err := job.Cron()
+		if err != nil || !cron.Matches(at) {
+			continue
+		}
+
+		if !s.start(job.ID) {
+			s.Log.WithFields(log.Fields{
+				"prefix": "schedule.Scheduler.tick",
+				"job":    job.ID,
+			}).Warn("Skipping the job, its previous run hasn't finished")
+			continue
+		}
+
+		s.wg.Add(1)
+		go func(job Job) {
+			defer s.wg.Done()
+			defer s.finish(job.ID)
+
+			s.Log.WithFields(log.Fields{
+				"prefix": "schedule.Scheduler.tick",
+				"job":    job.ID,
+			}).Infof("Running %s", job.Command())
+
+			runErr := s.Run(ctx, job)
+			if runErr != nil {
+				s.Log.WithFields(log.Fields{
+					"prefix": "schedule.Scheduler.tick",
+					"job":    job.ID,
+				}).Errorf("%s failed: %s", job.Command(), runErr)
+			}
+
+			if err := s.Store.RecordRun(job.ID, at, runErr); err != nil {
+				s.Log.WithFields(log.Fields{
+					"prefix": "schedule.Scheduler.tick",
+					"job":    job.ID,
+				}).Error(err)
+			}
+		}(job)
+	}
+}
+
+func (s *Scheduler) start(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running == nil {
+		s.running = make(map[string]bool)
+	}
+	if s.running[id] {
+		return false
+	}
+	s.running[id] = true
+
+	return true
+}
+
+func (s *Scheduler) finish(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.running, id)
+}
+
+func (s *Scheduler) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+
+	return time.Now()
+}
+
+// ExecRunner runs jobs with the given stripe executable, with the profile of
+// the job, appending their output to a log file per job in logDir
+func ExecRunner(executable, logDir string) Runner {
+	return func(ctx context.Context, job Job) error {
+		if err := os.MkdirAll(logDir, 0700); err != nil {
+			return err
+		}
+
+		logFile, err := os.OpenFile(LogPath(logDir, job.ID), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		defer logFile.Close()
+
+		fmt.Fprintf(logFile, "=== %s %s\n", time.Now().Format(time.RFC3339), job.Command())
+
+		args := append([]string{"--project-name", job.Profile}, job.Args...)
+		cmd := exec.CommandContext(ctx, executable, args...) // #nosec G204
+		cmd.Stdout = logFile
+		cmd.Stderr = logFile
+
+		return cmd.Run()
+	}
+}
+
+// LogPath returns the log file of the job with the given ID
+func LogPath(logDir, id string) string {
+	return filepath.Join(logDir, id+".log")
+}
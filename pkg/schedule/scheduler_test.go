@@ -0,0 +1,126 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore() *Store {
+	return &Store{Fs: afero.NewMemMapFs(), Path: "/config/stripe/schedule.toml"}
+}
+
+func TestStore(t *testing.T) {
+	store := newTestStore()
+
+	jobs, err := store.Jobs()
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+
+	_, err = store.Add("0 9 * *", []string{"get", "/v1/balance"}, "default")
+	require.Error(t, err)
+
+	weekly, err := store.Add("0 9 * * 1", []string{"fixtures", "seed.json"}, "default")
+	require.NoError(t, err)
+	require.Regexp(t, "^job_[0-9a-f]{8}$", weekly.ID)
+	require.Equal(t, "stripe fixtures seed.json", weekly.Command())
+
+	hourly, err := store.Add("@hourly", []string{"get", "/v1/balance"}, "ci")
+	require.NoError(t, err)
+
+	at := time.Date(2022, 8, 1, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, store.RecordRun(hourly.ID, at, errors.New("exit status 1")))
+
+	jobs, err = store.Jobs()
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	require.Equal(t, weekly.ID, jobs[0].ID)
+	require.Equal(t, []string{"fixtures", "seed.json"}, jobs[0].Args)
+	require.True(t, jobs[0].LastRun.IsZero())
+	require.Equal(t, "ci", jobs[1].Profile)
+	require.True(t, at.Equal(jobs[1].LastRun))
+	require.Equal(t, "exit status 1", jobs[1].LastError)
+
+	require.NoError(t, store.Remove(weekly.ID))
+	require.EqualError(t, store.Remove(weekly.ID), "no scheduled job has the ID "+weekly.ID)
+
+	jobs, err = store.Jobs()
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+}
+
+func TestSchedulerTick(t *testing.T) {
+	store := newTestStore()
+	weekly, err := store.Add("0 9 * * 1", []string{"fixtures", "seed.json"}, "default")
+	require.NoError(t, err)
+	_, err = store.Add("0 10 * * *", []string{"get", "/v1/balance"}, "default")
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var ran []string
+	release := make(chan struct{})
+
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	s := &Scheduler{
+		Store: store,
+		Log:   logger,
+		Run: func(ctx context.Context, job Job) error {
+			mu.Lock()
+			ran = append(ran, job.ID)
+			mu.Unlock()
+			<-release
+			return nil
+		},
+	}
+
+	monday := time.Date(2022, 8, 1, 9, 0, 0, 0, time.UTC)
+	s.tick(context.Background(), monday)
+
+	// a job still running isn't run again
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(ran) == 1
+	}, time.Second, time.Millisecond)
+	s.tick(context.Background(), monday.AddDate(0, 0, 7))
+
+	close(release)
+	s.wg.Wait()
+
+	require.Equal(t, []string{weekly.ID}, ran)
+
+	jobs, err := store.Jobs()
+	require.NoError(t, err)
+	require.True(t, monday.Equal(jobs[0].LastRun))
+	require.Empty(t, jobs[0].LastError)
+	require.True(t, jobs[1].LastRun.IsZero())
+}
+
+func TestSchedulerStartStops(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Scheduler{Store: newTestStore(), Log: log.New()}
+
+	done := make(chan struct{})
+	go func() {
+		s.Start(ctx)
+		close(done)
+	}()
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the scheduler didn't stop")
+	}
+}
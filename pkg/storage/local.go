@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// Local keeps the state in a folder of the filesystem
+type Local struct {
+	Fs  afero.Fs
+	Dir string
+}
+
+func (l *Local) path(key string) string {
+	return filepath.Join(l.Dir, filepath.FromSlash(key))
+}
+
+// Read returns the content of the file at key
+func (l *Local) Read(ctx context.Context, key string) ([]byte, error) {
+	data, err := afero.ReadFile(l.Fs, l.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+
+	return data, err
+}
+
+// Write replaces the content of the file at key, creating its folder if needed
+func (l *Local) Write(ctx context.Context, key string, data []byte) error {
+	path := l.path(key)
+
+	if err := l.Fs.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return afero.WriteFile(l.Fs, path, data, 0600)
+}
+
+// Delete removes the file at key
+func (l *Local) Delete(ctx context.Context, key string) error {
+	err := l.Fs.Remove(l.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+// Lock creates a lock file next to the state, failing if it already exists
+func (l *Local) Lock(ctx context.Context, key string, ttl time.Duration) (func() error, error) {
+	path := l.path(key + ".lock")
+
+	if err := l.Fs.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+
+	for {
+		file, err := l.Fs.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_, err = file.Write(lockContent(ctx, ttl))
+			file.Close()
+			if err != nil {
+				return nil, err
+			}
+
+			return func() error { return l.Delete(context.Background(), key+".lock") }, nil
+		}
+
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if l.lockExpired(path, ttl) {
+			l.Fs.Remove(path)
+			continue
+		}
+
+		if err := waitToRetryLock(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// lockExpired returns whether the lock file was abandoned, including by a
+// session that stopped before it could write it
+func (l *Local) lockExpired(path string, ttl time.Duration) bool {
+	content, err := afero.ReadFile(l.Fs, path)
+	if err != nil {
+		return false
+	}
+
+	if lockExpired(content) {
+		return true
+	}
+
+	info, err := l.Fs.Stat(path)
+
+	return err == nil && len(content) == 0 && time.Since(info.ModTime()) > ttl
+}
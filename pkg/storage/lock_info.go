@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// LockInfo is who holds a lock and until when
+type LockInfo struct {
+	Owner     string
+	ExpiresAt time.Time
+}
+
+// ReadLock returns who holds the lock named key, or ErrNotFound if nobody
+// does, including when the lock was abandoned
+func ReadLock(ctx context.Context, backend Backend, key string) (*LockInfo, error) {
+	content, err := backend.Read(ctx, key+".lock")
+	if err != nil {
+		return nil, err
+	}
+
+	expiry, owner, _ := strings.Cut(string(content), " ")
+
+	expiresAt, err := time.Parse(time.RFC3339, expiry)
+	if err != nil {
+		// the lock is still being written, so it's held by someone
+		return &LockInfo{}, nil
+	}
+
+	if time.Now().After(expiresAt) {
+		return nil, ErrNotFound
+	}
+
+	return &LockInfo{Owner: owner, ExpiresAt: expiresAt}, nil
+}
+
+// Unlock releases the lock named key, whoever holds it
+func Unlock(ctx context.Context, backend Backend, key string) error {
+	return backend.Delete(ctx, key+".lock")
+}
@@ -0,0 +1,291 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// Remote keeps the state in a bucket of S3, or of a storage service with an
+// S3-compatible API such as Google Cloud Storage. Requests are signed with
+// AWS Signature Version 4.
+type Remote struct {
+	// Endpoint is the base URL of the service, such as
+	// https://s3.us-east-1.amazonaws.com. Buckets are addressed by path.
+	Endpoint string
+	Bucket   string
+	// Prefix is prepended to every key, so several states can share a bucket
+	Prefix string
+	Region string
+
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	Client *http.Client
+}
+
+func newRemoteFromEnv(scheme, bucket, prefix string) (*Remote, error) {
+	r := &Remote{
+		Bucket:          bucket,
+		Prefix:          prefix,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		Client:          &http.Client{Timeout: 30 * time.Second},
+	}
+
+	if r.AccessKeyID == "" || r.SecretAccessKey == "" {
+		return nil, clierrors.Errorf(clierrors.InvalidStateBackend, "AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to keep the state in %s://%s", scheme, bucket)
+	}
+
+	switch scheme {
+	case "gs":
+		r.Endpoint = "https://storage.googleapis.com"
+		r.Region = "auto"
+	default:
+		r.Region = os.Getenv("AWS_REGION")
+		if r.Region == "" {
+			r.Region = os.Getenv("AWS_DEFAULT_REGION")
+		}
+		if r.Region == "" {
+			r.Region = "us-east-1"
+		}
+		r.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", r.Region)
+	}
+
+	if endpoint := os.Getenv(EndpointEnv); endpoint != "" {
+		r.Endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+
+	return r, nil
+}
+
+// Read downloads the object at key
+func (r *Remote) Read(ctx context.Context, key string) ([]byte, error) {
+	resp, body, err := r.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, r.errorFor(resp, body, key)
+	}
+}
+
+// Write uploads the object at key
+func (r *Remote) Write(ctx context.Context, key string, data []byte) error {
+	resp, body, err := r.do(ctx, http.MethodPut, key, data, nil)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return r.errorFor(resp, body, key)
+	}
+
+	return nil
+}
+
+// Delete removes the object at key
+func (r *Remote) Delete(ctx context.Context, key string) error {
+	resp, body, err := r.do(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return r.errorFor(resp, body, key)
+	}
+}
+
+// Lock creates a lock object next to the state with a conditional write, which
+// fails if the object already exists
+func (r *Remote) Lock(ctx context.Context, key string, ttl time.Duration) (func() error, error) {
+	lockKey := key + ".lock"
+
+	headers := map[string]string{
+		"If-None-Match": "*",
+		// Google Cloud Storage's equivalent of If-None-Match
+		"x-goog-if-generation-match": "0",
+	}
+
+	for {
+		resp, body, err := r.do(ctx, http.MethodPut, lockKey, lockContent(ctx, ttl), headers)
+		if err != nil {
+			return nil, err
+		}
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return func() error { return r.Delete(context.Background(), lockKey) }, nil
+		case http.StatusPreconditionFailed, http.StatusConflict:
+			content, err := r.Read(ctx, lockKey)
+			if err == nil && lockExpired(content) {
+				r.Delete(ctx, lockKey)
+				continue
+			}
+
+			if err := waitToRetryLock(ctx); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, r.errorFor(resp, body, lockKey)
+		}
+	}
+}
+
+func (r *Remote) objectURL(key string) *url.URL {
+	segments := strings.Split(path.Join(r.Bucket, r.Prefix, key), "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+
+	u, _ := url.Parse(r.Endpoint)
+	u.RawPath = u.Path + "/" + strings.Join(segments, "/")
+	u.Path, _ = url.PathUnescape(u.RawPath)
+
+	return u
+}
+
+func (r *Remote) do(ctx context.Context, method, key string, data []byte, headers map[string]string) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, r.objectURL(key).String(), bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	r.sign(req, data, time.Now())
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+
+	return resp, body, err
+}
+
+func (r *Remote) errorFor(resp *http.Response, body []byte, key string) error {
+	message := strings.TrimSpace(string(body))
+	if message == "" {
+		message = resp.Status
+	}
+
+	return clierrors.Errorf(clierrors.StateSyncFailed, "could not access %s in the state bucket %s: %s", key, r.Bucket, message)
+}
+
+// sign adds the AWS Signature Version 4 of the request to its headers
+func (r *Remote) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	if r.SessionToken != "" {
+		req.Header.Set("x-amz-security-token", r.SessionToken)
+	}
+
+	signed := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-amz-") {
+			signed[lower] = strings.TrimSpace(req.Header.Get(name))
+		}
+	}
+
+	names := make([]string, 0, len(signed))
+	for name := range signed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + signed[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, r.Region, "s3", "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+r.SecretAccessKey), date)
+	key = hmacSHA256(key, r.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		r.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// uriEncode encodes a path segment the way AWS Signature Version 4 expects,
+// leaving only unreserved characters as is
+func uriEncode(segment string) string {
+	var encoded strings.Builder
+
+	for _, b := range []byte(segment) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+
+	return encoded.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,132 @@
+// Package storage persists the state of the CLI, such as its config file and
+// plugin manifest, so that it can be shared across sessions.
+//
+// By default the state lives in the local config folder. Setting
+// STRIPE_CLI_STATE_BACKEND to a bucket, as in s3://bucket/prefix or
+// gs://bucket/prefix, shares it through that bucket instead, which is useful
+// for ephemeral CI runners and cloud IDEs: the state is pulled into the config
+// folder before a command runs, and the files it changed are pushed back
+// after. The plugin commands are added before the state is pulled, so the
+// plugins installed in the shared state are only available from the next
+// command run on a new runner.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// BackendEnv is the environment variable selecting where the state is kept
+const BackendEnv = "STRIPE_CLI_STATE_BACKEND"
+
+// EndpointEnv overrides the endpoint of an s3:// backend, for S3-compatible
+// storage such as MinIO
+const EndpointEnv = "STRIPE_CLI_STATE_ENDPOINT"
+
+// ErrNotFound is returned when reading a key that doesn't exist
+var ErrNotFound = errors.New("not found")
+
+// ErrLocked is returned when a lock is still held by someone else once the
+// context is done
+var ErrLocked = clierrors.New(clierrors.StateLocked, "the state is locked by another session")
+
+// Backend stores the state of the CLI by key. Keys are slash-separated paths
+// relative to the config folder, such as "config.toml".
+type Backend interface {
+	// Read returns the content of the key, or ErrNotFound
+	Read(ctx context.Context, key string) ([]byte, error)
+	// Write replaces the content of the key
+	Write(ctx context.Context, key string, data []byte) error
+	// Delete removes the key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// Lock waits until the lock named key is acquired, and returns how to
+	// release it. Locks not released after ttl are considered abandoned.
+	Lock(ctx context.Context, key string, ttl time.Duration) (unlock func() error, err error)
+}
+
+// Open returns the backend described by rawURL: a path or file:// URL for a
+// local folder, an s3:// URL for an S3 bucket or a gs:// URL for a Google
+// Cloud Storage bucket. The buckets are accessed with the credentials in the
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables, which
+// must be HMAC keys for Google Cloud Storage.
+func Open(rawURL string, fs afero.Fs) (Backend, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, clierrors.Errorf(clierrors.InvalidStateBackend, "%s is not a valid state backend: %w", rawURL, err)
+	}
+
+	switch u.Scheme {
+	case "", "file":
+		if u.Path == "" {
+			return nil, clierrors.Errorf(clierrors.InvalidStateBackend, "%s is not a valid state backend: missing path", rawURL)
+		}
+		return &Local{Fs: fs, Dir: filepath.FromSlash(u.Path)}, nil
+	case "s3", "gs":
+		if u.Host == "" {
+			return nil, clierrors.Errorf(clierrors.InvalidStateBackend, "%s is not a valid state backend: missing bucket", rawURL)
+		}
+		return newRemoteFromEnv(u.Scheme, u.Host, strings.Trim(u.Path, "/"))
+	default:
+		return nil, clierrors.Errorf(clierrors.InvalidStateBackend, "%s is not a valid state backend: expected a path, or a file://, s3:// or gs:// URL", rawURL)
+	}
+}
+
+type lockOwnerKey struct{}
+
+// WithLockOwner returns a copy of ctx in which the locks are taken on behalf
+// of owner, such as a CI job, rather than of the running process
+func WithLockOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, lockOwnerKey{}, owner)
+}
+
+// lockOwner identifies the session holding a lock, to tell who to wait for
+func lockOwner(ctx context.Context) string {
+	if owner, ok := ctx.Value(lockOwnerKey{}).(string); ok && owner != "" {
+		return owner
+	}
+
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// lockRetryInterval is how long to wait before trying to acquire a lock again
+var lockRetryInterval = 500 * time.Millisecond
+
+// lockContent is what's stored in a lock: who holds it and until when
+func lockContent(ctx context.Context, ttl time.Duration) []byte {
+	return []byte(fmt.Sprintf("%s %s", time.Now().Add(ttl).UTC().Format(time.RFC3339), lockOwner(ctx)))
+}
+
+// lockExpired returns whether the lock with the given content was abandoned
+func lockExpired(content []byte) bool {
+	expiry, _, _ := strings.Cut(string(content), " ")
+
+	expiresAt, err := time.Parse(time.RFC3339, expiry)
+	if err != nil {
+		// the lock may still be being written
+		return false
+	}
+
+	return time.Now().After(expiresAt)
+}
+
+// waitToRetryLock waits before trying to acquire a lock again, or returns
+// ErrLocked if the context is done first
+func waitToRetryLock(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ErrLocked
+	case <-time.After(lockRetryInterval):
+		return nil
+	}
+}
@@ -0,0 +1,247 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	backend, err := Open("/shared/stripe", fs)
+	require.NoError(t, err)
+	require.Equal(t, &Local{Fs: fs, Dir: "/shared/stripe"}, backend)
+
+	backend, err = Open("file:///shared/stripe", fs)
+	require.NoError(t, err)
+	require.Equal(t, &Local{Fs: fs, Dir: "/shared/stripe"}, backend)
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv(EndpointEnv, "")
+
+	backend, err = Open("s3://ci-state/stripe/", fs)
+	require.NoError(t, err)
+	remote := backend.(*Remote)
+	require.Equal(t, "https://s3.eu-west-1.amazonaws.com", remote.Endpoint)
+	require.Equal(t, "ci-state", remote.Bucket)
+	require.Equal(t, "stripe", remote.Prefix)
+
+	backend, err = Open("gs://ci-state", fs)
+	require.NoError(t, err)
+	require.Equal(t, "https://storage.googleapis.com", backend.(*Remote).Endpoint)
+
+	_, err = Open("ftp://ci-state", fs)
+	require.Error(t, err)
+
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	_, err = Open("s3://ci-state", fs)
+	require.EqualError(t, err, "AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to keep the state in s3://ci-state")
+}
+
+// testBackend checks the behavior every backend must have
+func testBackend(t *testing.T, backend Backend) {
+	ctx := context.Background()
+
+	_, err := backend.Read(ctx, "config.toml")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, backend.Write(ctx, "config.toml", []byte("color = 'on'")))
+	data, err := backend.Read(ctx, "config.toml")
+	require.NoError(t, err)
+	require.Equal(t, "color = 'on'", string(data))
+
+	require.NoError(t, backend.Delete(ctx, "config.toml"))
+	require.NoError(t, backend.Delete(ctx, "config.toml"))
+	_, err = backend.Read(ctx, "config.toml")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	unlock, err := backend.Lock(ctx, "state", time.Minute)
+	require.NoError(t, err)
+
+	// the lock can't be taken twice
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = backend.Lock(waitCtx, "state", time.Minute)
+	require.ErrorIs(t, err, ErrLocked)
+
+	require.NoError(t, unlock())
+	unlock, err = backend.Lock(ctx, "state", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, unlock())
+
+	// abandoned locks are taken over
+	_, err = backend.Lock(ctx, "abandoned", -time.Minute)
+	require.NoError(t, err)
+	_, err = backend.Lock(ctx, "abandoned", time.Minute)
+	require.NoError(t, err)
+
+	// who holds a lock can be told, and anyone can release it
+	_, err = ReadLock(ctx, backend, "locks/profile-default")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = backend.Lock(WithLockOwner(ctx, "ci-job-42"), "locks/profile-default", time.Minute)
+	require.NoError(t, err)
+	info, err := ReadLock(ctx, backend, "locks/profile-default")
+	require.NoError(t, err)
+	require.Equal(t, "ci-job-42", info.Owner)
+	require.WithinDuration(t, time.Now().Add(time.Minute), info.ExpiresAt, 5*time.Second)
+
+	require.NoError(t, Unlock(ctx, backend, "locks/profile-default"))
+	_, err = ReadLock(ctx, backend, "locks/profile-default")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = backend.Lock(ctx, "locks/profile-stale", -time.Minute)
+	require.NoError(t, err)
+	_, err = ReadLock(ctx, backend, "locks/profile-stale")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocal(t *testing.T) {
+	lockRetryInterval = 10 * time.Millisecond
+	defer func() { lockRetryInterval = 500 * time.Millisecond }()
+
+	testBackend(t, &Local{Fs: afero.NewMemMapFs(), Dir: "/shared/stripe"})
+}
+
+// fakeS3 serves objects like S3, including conditional writes
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") || r.Header.Get("x-amz-date") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		data, ok := s.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case http.MethodPut:
+		if _, ok := s.objects[r.URL.Path]; ok && r.Header.Get("If-None-Match") == "*" {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		s.objects[r.URL.Path] = data
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestRemote(t *testing.T) {
+	lockRetryInterval = 10 * time.Millisecond
+	defer func() { lockRetryInterval = 500 * time.Millisecond }()
+
+	s3 := &fakeS3{objects: make(map[string][]byte)}
+	server := httptest.NewServer(s3)
+	defer server.Close()
+
+	remote := &Remote{
+		Endpoint:        server.URL,
+		Bucket:          "ci-state",
+		Prefix:          "stripe",
+		Region:          "us-east-1",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		Client:          server.Client(),
+	}
+
+	testBackend(t, remote)
+
+	require.NoError(t, remote.Write(context.Background(), "fixtures checkpoints/a.json", []byte("{}")))
+	require.Contains(t, s3.objects, "/ci-state/stripe/fixtures checkpoints/a.json")
+}
+
+func TestSign(t *testing.T) {
+	remote := &Remote{Region: "us-east-1", AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}
+
+	req := httptest.NewRequest(http.MethodGet, "https://s3.amazonaws.com/ci-state/config.toml", nil)
+	remote.sign(req, nil, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	require.Equal(t, "20240102T030405Z", req.Header.Get("x-amz-date"))
+	require.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", req.Header.Get("x-amz-content-sha256"))
+	require.True(t, strings.HasPrefix(req.Header.Get("Authorization"),
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240102/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="))
+}
+
+func TestSession(t *testing.T) {
+	ctx := context.Background()
+	backend := &Local{Fs: afero.NewMemMapFs(), Dir: "/bucket"}
+	require.NoError(t, backend.Write(ctx, "config.toml", []byte("color = 'on'")))
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/stripe/plugins.toml", []byte("[[Plugin]]"), 0600))
+
+	session, err := Pull(ctx, backend, fs, "/stripe", "")
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, "/stripe/config.toml")
+	require.NoError(t, err)
+	require.Equal(t, "color = 'on'", string(data))
+
+	// nothing changed, but the local plugin manifest seeds the backend
+	require.NoError(t, session.Push(ctx))
+	data, err = backend.Read(ctx, "plugins.toml")
+	require.NoError(t, err)
+	require.Equal(t, "[[Plugin]]", string(data))
+
+	// changes are pushed, unless another session changed the file first
+	other, err := Pull(ctx, backend, afero.NewMemMapFs(), "/stripe", "")
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(other.Fs, "/stripe/config.toml", []byte("color = 'off'"), 0600))
+	require.NoError(t, other.Push(ctx))
+
+	require.NoError(t, afero.WriteFile(fs, "/stripe/config.toml", []byte("color = 'auto'"), 0600))
+	require.EqualError(t, session.Push(ctx), "not pushing [config.toml], which another session changed since they were pulled")
+
+	data, err = backend.Read(ctx, "config.toml")
+	require.NoError(t, err)
+	require.Equal(t, "color = 'off'", string(data))
+}
+
+func TestSessionConfigFile(t *testing.T) {
+	ctx := context.Background()
+	backend := &Local{Fs: afero.NewMemMapFs(), Dir: "/bucket"}
+	require.NoError(t, backend.Write(ctx, "config.toml", []byte("color = 'on'")))
+
+	fs := afero.NewMemMapFs()
+	session, err := Pull(ctx, backend, fs, "/stripe", "/work/stripe.toml")
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, "/work/stripe.toml")
+	require.NoError(t, err)
+	require.Equal(t, "color = 'on'", string(data))
+
+	exists, err := afero.Exists(fs, "/stripe/config.toml")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.NoError(t, afero.WriteFile(fs, "/work/stripe.toml", []byte("color = 'off'"), 0600))
+	require.NoError(t, session.Push(ctx))
+
+	data, err = backend.Read(ctx, "config.toml")
+	require.NoError(t, err)
+	require.Equal(t, "color = 'off'", string(data))
+}
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// StateFiles are the files of the config folder kept in the backend. Plugin
+// binaries aren't, as they are specific to the platform and reinstalled from
+// the plugin manifest when needed.
+var StateFiles = []string{
+	"config.toml",
+	"plugins.toml",
+	"policy.toml",
+	"redactions",
+	"ratelimits.ndjson",
+}
+
+// stateLock is the name of the lock taken while pushing the state
+const stateLock = "state"
+
+// stateLockTTL is how long a lock is honored before it's considered abandoned
+const stateLockTTL = time.Minute
+
+// Session copies the state from a backend into the local config folder, and
+// back once the command is done
+type Session struct {
+	Backend Backend
+	Fs      afero.Fs
+	// Dir is the local config folder
+	Dir string
+	// ConfigFile is where config.toml is kept locally, when it isn't in Dir
+	// such as with --config
+	ConfigFile string
+
+	// pulled has the hash of every state file as it was last pulled or
+	// pushed, nil for the ones that didn't exist
+	pulled map[string]*[sha256.Size]byte
+}
+
+// Pull copies the state files from the backend into the local config folder,
+// replacing the local ones. Files missing from the backend are left alone, so
+// the first session to use a backend seeds it with its local state.
+// configFile is where config.toml is kept, or "" for the one in dir.
+func Pull(ctx context.Context, backend Backend, fs afero.Fs, dir string, configFile string) (*Session, error) {
+	s := &Session{
+		Backend:    backend,
+		Fs:         fs,
+		Dir:        dir,
+		ConfigFile: configFile,
+		pulled:     make(map[string]*[sha256.Size]byte),
+	}
+
+	if err := fs.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	for _, key := range StateFiles {
+		data, err := backend.Read(ctx, key)
+		if errors.Is(err, ErrNotFound) {
+			s.pulled[key] = nil
+			continue
+		} else if err != nil {
+			return nil, clierrors.Errorf(clierrors.StateSyncFailed, "could not pull the CLI state: %w", err)
+		}
+
+		if err := afero.WriteFile(fs, s.localPath(key), data, 0600); err != nil {
+			return nil, err
+		}
+
+		sum := sha256.Sum256(data)
+		s.pulled[key] = &sum
+	}
+
+	return s, nil
+}
+
+// Push copies the state files changed since they were pulled back to the
+// backend, while holding a lock. A file changed in the backend by another
+// session in the meantime is left as is, rather than overwriting its changes,
+// and reported in the returned error.
+func (s *Session) Push(ctx context.Context) error {
+	changed := s.changedFiles()
+	if len(changed) == 0 {
+		return nil
+	}
+
+	unlock, err := s.Backend.Lock(ctx, stateLock, stateLockTTL)
+	if err != nil {
+		return fmt.Errorf("could not push the CLI state: %w", err)
+	}
+	defer unlock()
+
+	var conflicts []string
+
+	for _, key := range changed {
+		current, err := s.Backend.Read(ctx, key)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return clierrors.Errorf(clierrors.StateSyncFailed, "could not push the CLI state: %w", err)
+		}
+
+		if !sameHash(s.pulled[key], current, err == nil) {
+			conflicts = append(conflicts, key)
+			continue
+		}
+
+		data, err := afero.ReadFile(s.Fs, s.localPath(key))
+		switch {
+		case os.IsNotExist(err):
+			err = s.Backend.Delete(ctx, key)
+			s.pulled[key] = nil
+		case err == nil:
+			err = s.Backend.Write(ctx, key, data)
+			sum := sha256.Sum256(data)
+			s.pulled[key] = &sum
+		}
+		if err != nil {
+			return clierrors.Errorf(clierrors.StateSyncFailed, "could not push the CLI state: %w", err)
+		}
+
+		log.WithFields(log.Fields{
+			"prefix": "storage.Session.Push",
+		}).Debugf("Pushed %s", key)
+	}
+
+	if len(conflicts) > 0 {
+		return clierrors.Errorf(clierrors.StateConflict, "not pushing %v, which another session changed since they were pulled", conflicts)
+	}
+
+	return nil
+}
+
+// changedFiles returns the state files that differ from what was pulled
+func (s *Session) changedFiles() []string {
+	var changed []string
+
+	for _, key := range StateFiles {
+		data, err := afero.ReadFile(s.Fs, s.localPath(key))
+		if !sameHash(s.pulled[key], data, err == nil) {
+			changed = append(changed, key)
+		}
+	}
+
+	return changed
+}
+
+func (s *Session) localPath(key string) string {
+	if key == "config.toml" && s.ConfigFile != "" {
+		return s.ConfigFile
+	}
+
+	return filepath.Join(s.Dir, filepath.FromSlash(key))
+}
+
+// sameHash returns whether data, if it exists, matches the hash of a pulled
+// file, nil if it didn't exist
+func sameHash(pulled *[sha256.Size]byte, data []byte, exists bool) bool {
+	if pulled == nil || !exists {
+		return pulled == nil && !exists
+	}
+
+	return sha256.Sum256(data) == *pulled
+}
@@ -55,6 +55,11 @@ type AnalyticsTelemetryClient struct {
 	BaseURL    *url.URL
 	wg         sync.WaitGroup
 	HTTPClient *http.Client
+
+	// Disabled keeps events from being sent. They are still recorded to Log.
+	Disabled bool
+	// Log, when set, keeps a local copy of every event
+	Log *TelemetryLog
 }
 
 // NoOpTelemetryClient does not call any endpoint and returns an empty response
@@ -182,6 +187,16 @@ func (a *AnalyticsTelemetryClient) SendEvent(ctx context.Context, eventName stri
 func (a *AnalyticsTelemetryClient) sendData(ctx context.Context, data url.Values) (*http.Response, error) {
 	a.wg.Add(1)
 	defer a.wg.Done()
+	if a.Log != nil {
+		if err := a.Log.Record(data, !a.Disabled); err != nil {
+			log.Debugf("Error while recording telemetry data: %v\n", err)
+		}
+	}
+
+	if a.Disabled {
+		return nil, nil
+	}
+
 	if a.BaseURL == nil {
 		analyticsURL, err := url.Parse(DefaultTelemetryEndpoint)
 		if err != nil {
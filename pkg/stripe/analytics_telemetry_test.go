@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 
@@ -178,6 +179,28 @@ func TestSkipsSendEventWhenMetadataIsEmpty(t *testing.T) {
 	analyticsClient.SendEvent(context.Background(), "foo", "bar")
 }
 
+func TestSendEventWhenDisabled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Fail(t, "Did not expect an event to be sent")
+	}))
+	defer ts.Close()
+	baseURL, _ := url.Parse(ts.URL)
+
+	fs := afero.NewMemMapFs()
+	telemetryLog := stripe.NewTelemetryLog(fs, "/stripe/telemetry.ndjson")
+
+	processCtx := stripe.WithEventMetadata(context.Background(), &stripe.CLIAnalyticsEventMetadata{InvocationID: "123456"})
+	analyticsClient := stripe.AnalyticsTelemetryClient{BaseURL: baseURL, HTTPClient: &http.Client{}, Disabled: true, Log: telemetryLog}
+	analyticsClient.SendEvent(processCtx, "foo", "bar")
+
+	events, err := stripe.ReadTelemetryLog(fs, "/stripe/telemetry.ndjson")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.False(t, events[0].Sent)
+	require.Equal(t, "foo", events[0].Fields["event_name"])
+	require.Equal(t, "123456", events[0].Fields["invocation_id"])
+}
+
 // Utility function
 func TestTelemetryOptedOut(t *testing.T) {
 	require.False(t, stripe.TelemetryOptedOut(""))
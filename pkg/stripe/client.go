@@ -92,11 +92,15 @@ func (c *Client) PerformRequest(ctx context.Context, method, path string, params
 		req = req.WithContext(ctx)
 	}
 
+	sent := time.Now()
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 
+	recordClockSkew(resp, sent, time.Now())
+
 	// RequestID of the API Request
 	requestID := resp.Header.Get("Request-Id")
 	livemode := strings.Contains(c.APIKey, "live")
@@ -0,0 +1,79 @@
+package stripe
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// lastClockSkew is the skew measured from the last response of the API
+var lastClockSkew struct {
+	sync.Mutex
+	skew     time.Duration
+	measured bool
+}
+
+// ClockSkew returns how far ahead of Stripe's clock the local clock is, and
+// negative when it's behind, as measured from the Date header of the last
+// response of the API. ok is false when no response was received yet.
+func ClockSkew() (skew time.Duration, ok bool) {
+	lastClockSkew.Lock()
+	defer lastClockSkew.Unlock()
+
+	return lastClockSkew.skew, lastClockSkew.measured
+}
+
+// MeasureClockSkew makes a request to baseURL to measure the skew of the local
+// clock, as returned by ClockSkew. The request doesn't need an API key.
+func MeasureClockSkew(ctx context.Context, baseURL string) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, baseURL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := time.Now()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	skew, ok := clockSkew(resp, sent, time.Now())
+	if !ok {
+		return 0, clierrors.New(clierrors.UnexpectedResponse, "the response has no Date header")
+	}
+
+	return skew, nil
+}
+
+// recordClockSkew saves the skew measured from a response of the API
+func recordClockSkew(resp *http.Response, sent, received time.Time) {
+	skew, ok := clockSkew(resp, sent, received)
+	if !ok {
+		return
+	}
+
+	lastClockSkew.Lock()
+	defer lastClockSkew.Unlock()
+
+	lastClockSkew.skew = skew
+	lastClockSkew.measured = true
+}
+
+// clockSkew compares the local clock with the Date header of a response,
+// which the server set halfway through the request, give or take the second
+// it's rounded to
+func clockSkew(resp *http.Response, sent, received time.Time) (time.Duration, bool) {
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, false
+	}
+
+	local := sent.Add(received.Sub(sent) / 2)
+
+	return local.Sub(date).Truncate(time.Second), true
+}
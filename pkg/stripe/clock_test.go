@@ -0,0 +1,50 @@
+package stripe
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// skewedServer answers with a Date header offset from the local clock
+func skewedServer(offset time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(offset).UTC().Format(http.TimeFormat))
+	}))
+}
+
+func TestMeasureClockSkew(t *testing.T) {
+	// the local clock is 7 minutes ahead of the server's
+	ts := skewedServer(-7 * time.Minute)
+	defer ts.Close()
+
+	skew, err := MeasureClockSkew(context.Background(), ts.URL)
+	require.NoError(t, err)
+	require.InDelta(t, 7*time.Minute, skew, float64(2*time.Second))
+}
+
+func TestPerformRequestRecordsClockSkew(t *testing.T) {
+	ts := skewedServer(3 * time.Minute)
+	defer ts.Close()
+
+	baseURL, _ := url.Parse(ts.URL)
+	client := Client{BaseURL: baseURL}
+
+	resp, err := client.PerformRequest(context.Background(), http.MethodGet, "/v1/balance", "", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	skew, ok := ClockSkew()
+	require.True(t, ok)
+	require.InDelta(t, -3*time.Minute, skew, float64(2*time.Second))
+}
+
+func TestClockSkewWithoutDate(t *testing.T) {
+	_, ok := clockSkew(&http.Response{Header: http.Header{}}, time.Now(), time.Now())
+	require.False(t, ok)
+}
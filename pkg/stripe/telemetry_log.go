@@ -0,0 +1,113 @@
+package stripe
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// TelemetryLogEvent is an event kept in the telemetry log
+type TelemetryLogEvent struct {
+	// Sent is false when telemetry was disabled, and the event was only logged
+	Sent bool `json:"sent"`
+	// Fields are the form fields of the event, as sent to the telemetry
+	// endpoint
+	Fields map[string]string `json:"fields"`
+}
+
+// TelemetryLog keeps a local copy of the telemetry events of the last
+// invocation of the CLI, so users can inspect exactly what is sent
+type TelemetryLog struct {
+	Fs   afero.Fs
+	Path string
+
+	mu      sync.Mutex
+	started bool
+}
+
+// NewTelemetryLog returns a log of the telemetry events kept at path
+func NewTelemetryLog(fs afero.Fs, path string) *TelemetryLog {
+	return &TelemetryLog{
+		Fs:   fs,
+		Path: path,
+	}
+}
+
+// Record adds an event to the log. The events of previous invocations are
+// discarded when the first event of this one is recorded.
+func (l *TelemetryLog) Record(data url.Values, sent bool) error {
+	event := TelemetryLogEvent{
+		Sent:   sent,
+		Fields: make(map[string]string, len(data)),
+	}
+	for key := range data {
+		event.Fields[key] = data.Get(key)
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if !l.started {
+		if err := l.Fs.MkdirAll(filepath.Dir(l.Path), os.ModePerm); err != nil {
+			return err
+		}
+		flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		l.started = true
+	}
+
+	file, err := l.Fs.OpenFile(l.Path, flags, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+
+	return err
+}
+
+// ReadTelemetryLog returns the events kept in the telemetry log at path, in
+// the order they were recorded
+func ReadTelemetryLog(fs afero.Fs, path string) ([]TelemetryLogEvent, error) {
+	file, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var events []TelemetryLogEvent
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event TelemetryLogEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, scanner.Err()
+}
+
+// FieldNames returns the names of the fields of the event, sorted
+func (e TelemetryLogEvent) FieldNames() []string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,31 @@
+package stripe
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelemetryLogKeepsLastInvocation(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	previous := NewTelemetryLog(fs, "/stripe/telemetry.ndjson")
+	require.NoError(t, previous.Record(url.Values{"event_name": {"Command Invoked"}}, true))
+
+	current := NewTelemetryLog(fs, "/stripe/telemetry.ndjson")
+	require.NoError(t, current.Record(url.Values{"event_name": {"Command Invoked"}, "command_path": {"stripe get"}}, true))
+	require.NoError(t, current.Record(url.Values{"event_name": {"API Request"}}, false))
+
+	events, err := ReadTelemetryLog(fs, "/stripe/telemetry.ndjson")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	require.True(t, events[0].Sent)
+	require.Equal(t, []string{"command_path", "event_name"}, events[0].FieldNames())
+	require.Equal(t, "stripe get", events[0].Fields["command_path"])
+
+	require.False(t, events[1].Sent)
+	require.Equal(t, "API Request", events[1].Fields["event_name"])
+}
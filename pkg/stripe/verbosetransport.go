@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"regexp"
 	"strings"
 
 	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/redact"
 )
 
 // inspectHeaders is the default list of headers that will be printed.
@@ -61,11 +61,6 @@ func (t *verboseTransport) dumpHeaders(header http.Header, indent string) {
 
 			for _, v := range vv {
 				if v != "" {
-					r := regexp.MustCompile("(?i)^(basic|bearer) (.+)")
-					if r.MatchString(v) {
-						v = r.ReplaceAllString(v, "$1 [REDACTED]")
-					}
-
 					info := fmt.Sprintf("%s %s: %s", indent, name, v)
 					t.verbosePrintln(info)
 				}
@@ -76,5 +71,5 @@ func (t *verboseTransport) dumpHeaders(header http.Header, indent string) {
 
 func (t *verboseTransport) verbosePrintln(msg string) {
 	color := ansi.Color(t.Out)
-	fmt.Fprintln(t.Out, color.Cyan(msg))
+	fmt.Fprintln(t.Out, color.Cyan(redact.String(msg)))
 }
@@ -3,13 +3,13 @@ package stripeauth
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 	"github.com/stripe/stripe-cli/pkg/stripe"
 )
 
@@ -88,7 +88,7 @@ func (c *Client) Authorize(ctx context.Context, deviceName string, websocketFeat
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		err := fmt.Errorf("Authorization failed, status=%d, body=%s", resp.StatusCode, body)
+		err := clierrors.Errorf(clierrors.AuthorizationFailed, "Authorization failed, status=%d, body=%s", resp.StatusCode, body)
 		return nil, err
 	}
 
@@ -0,0 +1,294 @@
+package terminal
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stripe/stripe-cli/pkg/terminal/p400"
+)
+
+// DefaultReaderDomain is the domain under which the smart readers on a local
+// network are addressed, as <ip with dashes>.<domain>
+const DefaultReaderDomain = "device.stripe-terminal-local-reader.net"
+
+// DefaultReaderPort is the port smart readers serve their local API on
+const DefaultReaderPort = 4443
+
+// networkDeviceTypes are the device types of the readers that connect to the
+// local network, as opposed to Bluetooth and USB readers
+var networkDeviceTypes = map[string]bool{
+	"bbpos_wisepos_e": true,
+	"stripe_s700":     true,
+	"verifone_P400":   true,
+}
+
+// IsNetworkReader returns whether readers of a device type are reachable on
+// the local network
+func IsNetworkReader(deviceType string) bool {
+	return networkDeviceTypes[deviceType]
+}
+
+// Check is the outcome of one step of a reader's connection diagnostic
+type Check struct {
+	Name   string
+	OK     bool
+	Detail string
+}
+
+// NetworkReader is a reader found on the local network, or registered to the
+// account with an IP address, along with its connection diagnostic
+type NetworkReader struct {
+	IPAddress string
+	// Reader is the reader as registered to the account, if it is
+	Reader *p400.Reader
+	Checks []Check
+}
+
+// Healthy returns whether all the checks of the diagnostic passed
+func (r NetworkReader) Healthy() bool {
+	for _, check := range r.Checks {
+		if !check.OK {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ReaderHostname returns the hostname a reader is reached at
+func ReaderHostname(ip, domain string) string {
+	return fmt.Sprintf("%s.%s", strings.ReplaceAll(ip, ".", "-"), domain)
+}
+
+// Scanner looks for smart readers on the local network and diagnoses the
+// connection to them
+type Scanner struct {
+	Domain string
+	Port   int
+	// Timeout limits each connection attempt
+	Timeout time.Duration
+	// Concurrency is how many hosts are probed at once
+	Concurrency int
+
+	// LookupHost resolves hostnames, net.DefaultResolver.LookupHost if nil
+	LookupHost func(ctx context.Context, host string) ([]string, error)
+	// TLSConfig is the base configuration of the TLS handshakes, e.g. to
+	// trust other certificate authorities
+	TLSConfig *tls.Config
+}
+
+// NewScanner returns a scanner of the readers at their default domain and
+// port
+func NewScanner() *Scanner {
+	return &Scanner{
+		Domain:      DefaultReaderDomain,
+		Port:        DefaultReaderPort,
+		Timeout:     time.Second,
+		Concurrency: 64,
+	}
+}
+
+// Scan returns the hosts that answer as smart readers: they accept
+// connections on the reader port, with a certificate valid for the reader
+// hostname of their IP address. The hosts are returned in order.
+func (s *Scanner) Scan(ctx context.Context, hosts []netip.Addr) []netip.Addr {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	var found []netip.Addr
+	sem := make(chan struct{}, s.Concurrency)
+
+	for _, host := range hosts {
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(host netip.Addr) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if _, err := s.handshake(ctx, host.String()); err == nil {
+				mu.Lock()
+				found = append(found, host)
+				mu.Unlock()
+			}
+		}(host)
+	}
+
+	wg.Wait()
+
+	sort.Slice(found, func(i, j int) bool { return found[i].Less(found[j]) })
+
+	return found
+}
+
+// Diagnose checks the steps of connecting to the reader at ip: resolving its
+// hostname, connecting to its port, and the TLS handshake
+func (s *Scanner) Diagnose(ctx context.Context, ip string) []Check {
+	hostname := ReaderHostname(ip, s.Domain)
+
+	dns := Check{Name: "DNS"}
+	lookupHost := s.LookupHost
+	if lookupHost == nil {
+		lookupHost = net.DefaultResolver.LookupHost
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, s.Timeout)
+	addrs, err := lookupHost(lookupCtx, hostname)
+	cancel()
+
+	switch {
+	case err != nil:
+		dns.Detail = fmt.Sprintf("%s doesn't resolve: %s. Routers protecting against DNS rebinding block it, allow %s or use another DNS server such as 8.8.8.8", hostname, err, s.Domain)
+	case !contains(addrs, ip):
+		dns.Detail = fmt.Sprintf("%s resolves to %s instead of %s", hostname, strings.Join(addrs, ", "), ip)
+	default:
+		dns.OK = true
+		dns.Detail = fmt.Sprintf("%s resolves to %s", hostname, ip)
+	}
+
+	tcp := Check{Name: "TCP"}
+	start := time.Now()
+
+	dialer := &net.Dialer{Timeout: s.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.address(ip))
+	if err != nil {
+		tcp.Detail = fmt.Sprintf("can't connect to port %d: %s. Check that the reader is on and on the same network", s.Port, err)
+		return []Check{dns, tcp, {Name: "TLS", Detail: "skipped, the reader can't be connected to"}}
+	}
+	conn.Close()
+
+	tcp.OK = true
+	tcp.Detail = fmt.Sprintf("connected to port %d in %s", s.Port, time.Since(start).Round(time.Millisecond))
+
+	tlsCheck := Check{Name: "TLS"}
+	state, err := s.handshake(ctx, ip)
+	if err != nil {
+		tlsCheck.Detail = fmt.Sprintf("the TLS handshake failed: %s", err)
+	} else {
+		tlsCheck.OK = true
+		tlsCheck.Detail = fmt.Sprintf("certificate valid until %s", state.PeerCertificates[0].NotAfter.Format("2006-01-02"))
+	}
+
+	return []Check{dns, tcp, tlsCheck}
+}
+
+// handshake connects to the reader port of ip and verifies its certificate
+// is valid for the reader hostname
+func (s *Scanner) handshake(ctx context.Context, ip string) (tls.ConnectionState, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if s.TLSConfig != nil {
+		config = s.TLSConfig.Clone()
+	}
+	config.ServerName = ReaderHostname(ip, s.Domain)
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: s.Timeout},
+		Config:    config,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*s.Timeout)
+	defer cancel()
+
+	conn, err := dialer.DialContext(ctx, "tcp", s.address(ip))
+	if err != nil {
+		return tls.ConnectionState{}, err
+	}
+	defer conn.Close()
+
+	return conn.(*tls.Conn).ConnectionState(), nil
+}
+
+func (s *Scanner) address(ip string) string {
+	return net.JoinHostPort(ip, strconv.Itoa(s.Port))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LocalHosts returns the addresses of the hosts on the private IPv4 networks
+// of the network interfaces, other than the interfaces' own. Networks larger
+// than a /24 are narrowed down to the /24 of the interface.
+func LocalHosts() ([]netip.Addr, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+
+	var prefixes []netip.Prefix
+	own := make(map[netip.Addr]bool)
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+
+		ip, ok := netip.AddrFromSlice(ipNet.IP.To4())
+		if !ok || !ip.IsPrivate() {
+			continue
+		}
+
+		bits, _ := ipNet.Mask.Size()
+		if bits < 24 {
+			bits = 24
+		}
+
+		own[ip] = true
+		prefixes = append(prefixes, netip.PrefixFrom(ip, bits).Masked())
+	}
+
+	var hosts []netip.Addr
+	for _, host := range SubnetHosts(prefixes) {
+		if !own[host] {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts, nil
+}
+
+// SubnetHosts returns the host addresses of IPv4 subnets, without their
+// network and broadcast addresses, and without duplicates
+func SubnetHosts(prefixes []netip.Prefix) []netip.Addr {
+	seen := make(map[netip.Addr]bool)
+	var hosts []netip.Addr
+
+	for _, prefix := range prefixes {
+		prefix = prefix.Masked()
+
+		for addr := prefix.Addr(); prefix.Contains(addr); addr = addr.Next() {
+			if seen[addr] {
+				continue
+			}
+			seen[addr] = true
+
+			// /31 and /32 subnets have no network or broadcast address
+			if prefix.Bits() < 31 && (addr == prefix.Addr() || !prefix.Contains(addr.Next())) {
+				continue
+			}
+
+			hosts = append(hosts, addr)
+		}
+	}
+
+	return hosts
+}
@@ -0,0 +1,93 @@
+package terminal
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubnetHosts(t *testing.T) {
+	hosts := SubnetHosts([]netip.Prefix{
+		netip.MustParsePrefix("192.168.1.0/30"),
+		netip.MustParsePrefix("192.168.1.1/32"),
+		netip.MustParsePrefix("10.0.0.4/31"),
+	})
+
+	require.Equal(t, []netip.Addr{
+		netip.MustParseAddr("192.168.1.1"),
+		netip.MustParseAddr("192.168.1.2"),
+		netip.MustParseAddr("10.0.0.4"),
+		netip.MustParseAddr("10.0.0.5"),
+	}, hosts)
+}
+
+func TestReaderHostname(t *testing.T) {
+	require.Equal(t, "192-168-1-20.device.stripe-terminal-local-reader.net", ReaderHostname("192.168.1.20", DefaultReaderDomain))
+}
+
+func newTestScanner(t *testing.T) *Scanner {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(ts.Close)
+
+	port, err := strconv.Atoi(ts.URL[len("https://127.0.0.1:"):])
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ts.Certificate())
+
+	return &Scanner{
+		// the test certificate is valid for *.example.com
+		Domain:      "example.com",
+		Port:        port,
+		Timeout:     time.Second,
+		Concurrency: 4,
+		TLSConfig:   &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12},
+		LookupHost: func(ctx context.Context, host string) ([]string, error) {
+			if host == "127-0-0-1.example.com" {
+				return []string{"127.0.0.1"}, nil
+			}
+			return nil, errors.New("no such host")
+		},
+	}
+}
+
+func TestScan(t *testing.T) {
+	scanner := newTestScanner(t)
+
+	found := scanner.Scan(context.Background(), []netip.Addr{
+		netip.MustParseAddr("127.0.0.2"),
+		netip.MustParseAddr("127.0.0.1"),
+	})
+	require.Equal(t, []netip.Addr{netip.MustParseAddr("127.0.0.1")}, found)
+
+	// a host serving a certificate that isn't valid for its reader hostname
+	// isn't a reader
+	scanner.Domain = "stripe.com"
+	require.Empty(t, scanner.Scan(context.Background(), []netip.Addr{netip.MustParseAddr("127.0.0.1")}))
+}
+
+func TestDiagnose(t *testing.T) {
+	scanner := newTestScanner(t)
+
+	checks := scanner.Diagnose(context.Background(), "127.0.0.1")
+	require.Len(t, checks, 3)
+	for _, check := range checks {
+		require.True(t, check.OK, check.Detail)
+	}
+	require.True(t, NetworkReader{Checks: checks}.Healthy())
+
+	checks = scanner.Diagnose(context.Background(), "127.0.0.2")
+	require.Equal(t, "DNS", checks[0].Name)
+	require.False(t, checks[0].OK)
+	require.Contains(t, checks[0].Detail, "127-0-0-2.example.com doesn't resolve")
+	require.False(t, NetworkReader{Checks: checks}.Healthy())
+}
@@ -20,7 +20,7 @@ type Metadata struct{}
 type Reader struct {
 	ID              string   `json:"id"`
 	Object          string   `json:"object"`
-	DeviceSwVersion string   `json:"device_software_version"`
+	DeviceSwVersion string   `json:"device_sw_version"`
 	DeviceType      string   `json:"device_type"`
 	IPAddress       string   `json:"ip_address"`
 	Label           string   `json:"label"`
@@ -0,0 +1,520 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+	"github.com/stripe/stripe-cli/pkg/proxy"
+	"github.com/stripe/stripe-cli/pkg/webhooks"
+)
+
+const (
+	timeLayout = "15:04:05"
+
+	defaultWidth  = 80
+	defaultHeight = 24
+
+	// the number of lines used by the header, separator and help bar
+	chromeLines = 3
+)
+
+// Entry is a single webhook event received during a listen session, along
+// with the response from the local endpoint if there was one.
+type Entry struct {
+	Event      proxy.StripeEvent
+	Payload    string
+	ReceivedAt time.Time
+	Response   *proxy.EndpointResponse
+}
+
+// Dashboard is an interactive terminal UI for a listen session. It keeps
+// every event received so that bursts of events can be browsed, filtered
+// and re-sent to the local endpoint.
+//
+// Like MultiSelect, it's drawn with golang.org/x/term and escape sequences
+// rather than a TUI framework such as bubbletea: a list, a filter and a
+// handful of keys don't justify the dependencies such a framework brings
+// into the CLI and into every plugin built against it.
+type Dashboard struct {
+	// Out is where the dashboard is drawn. Defaults to os.Stdout.
+	Out io.Writer
+
+	// In is where keystrokes are read from. Defaults to os.Stdin.
+	In *os.File
+
+	// Client is used to re-send events. It should be the client the events
+	// are forwarded with, so that resent events get the same timeout and TLS
+	// verification. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	mu        sync.Mutex
+	secret    string
+	entries   []*Entry
+	selected  int
+	filter    string
+	filtering bool
+	status    string
+	width     int
+	height    int
+
+	redrawCh chan struct{}
+}
+
+// NewDashboard returns a new Dashboard drawing to stdout
+func NewDashboard() *Dashboard {
+	return &Dashboard{
+		Out:      os.Stdout,
+		In:       os.Stdin,
+		Client:   http.DefaultClient,
+		width:    defaultWidth,
+		height:   defaultHeight,
+		redrawCh: make(chan struct{}, 1),
+	}
+}
+
+// AddEvent records an event received from Stripe
+func (d *Dashboard) AddEvent(evt proxy.StripeEvent, payload string) {
+	d.mu.Lock()
+	d.entries = append(d.entries, &Entry{
+		Event:      evt,
+		Payload:    payload,
+		ReceivedAt: time.Now(),
+	})
+	d.mu.Unlock()
+
+	d.redraw()
+}
+
+// AddResponse attaches the endpoint's response to the event it was for
+func (d *Dashboard) AddResponse(resp proxy.EndpointResponse) {
+	if resp.Event == nil {
+		return
+	}
+
+	d.mu.Lock()
+	for i := len(d.entries) - 1; i >= 0; i-- {
+		if d.entries[i].Event.ID == resp.Event.ID {
+			d.entries[i].Response = &resp
+			break
+		}
+	}
+	d.mu.Unlock()
+
+	d.redraw()
+}
+
+// SetSecret sets the webhook signing secret of the session, which re-sent
+// events are signed with
+func (d *Dashboard) SetSecret(secret string) {
+	d.mu.Lock()
+	d.secret = secret
+	d.mu.Unlock()
+}
+
+// SetStatus sets the message shown in the header
+func (d *Dashboard) SetStatus(status string) {
+	d.mu.Lock()
+	d.status = status
+	d.mu.Unlock()
+
+	d.redraw()
+}
+
+// Run takes over the terminal and draws the dashboard until the user quits
+// or the context is canceled.
+func (d *Dashboard) Run(ctx context.Context) error {
+	fd := int(d.In.Fd())
+	if !term.IsTerminal(fd) {
+		return clierrors.New(clierrors.TerminalRequired, "--tui requires an interactive terminal")
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, state)
+
+	// Switch to the alternate screen and hide the cursor, then put everything
+	// back the way it was on the way out.
+	fmt.Fprint(d.Out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(d.Out, "\x1b[?25h\x1b[?1049l")
+
+	keyCh := make(chan []byte)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	defer func() {
+		close(done)
+
+		// unblock the read in progress where the terminal supports deadlines,
+		// so that it doesn't swallow a keystroke typed after the dashboard is
+		// closed. Elsewhere the read only ends with the next keystroke.
+		if d.In.SetReadDeadline(time.Now()) == nil {
+			<-exited
+			d.In.SetReadDeadline(time.Time{})
+		}
+	}()
+
+	go func() {
+		defer close(exited)
+
+		buf := make([]byte, 16)
+		for {
+			n, err := d.In.Read(buf)
+			if err != nil {
+				close(keyCh)
+				return
+			}
+			if n == 0 {
+				continue
+			}
+			key := make([]byte, n)
+			copy(key, buf[:n])
+
+			// nothing reads the keys once the dashboard is closed
+			select {
+			case keyCh <- key:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for {
+		if w, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+			d.setSize(w, h)
+		}
+
+		fmt.Fprint(d.Out, d.Render())
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-d.redrawCh:
+		case key, ok := <-keyCh:
+			if !ok {
+				return nil
+			}
+			if quit := d.HandleKey(key); quit {
+				return nil
+			}
+		case <-time.After(time.Second):
+			// redraw periodically to pick up terminal resizes
+		}
+	}
+}
+
+// HandleKey updates the dashboard for a keystroke. It returns true if the
+// user asked to quit.
+func (d *Dashboard) HandleKey(key []byte) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.filtering {
+		switch {
+		case len(key) == 1 && (key[0] == '\r' || key[0] == '\n'):
+			d.filtering = false
+		case len(key) == 1 && key[0] == 0x1b:
+			d.filtering = false
+			d.filter = ""
+		case len(key) == 1 && (key[0] == 0x7f || key[0] == 0x08):
+			if len(d.filter) > 0 {
+				_, size := utf8.DecodeLastRuneInString(d.filter)
+				d.filter = d.filter[:len(d.filter)-size]
+			}
+		case len(key) == 1 && key[0] == 0x03:
+			return true
+		case len(key) > 0 && key[0] >= 0x20:
+			d.filter += string(key)
+		}
+
+		d.selected = 0
+
+		return false
+	}
+
+	switch string(key) {
+	case "q", "\x03":
+		return true
+	case "j", "\x1b[B":
+		d.selected++
+	case "k", "\x1b[A":
+		d.selected--
+	case "g", "\x1b[H":
+		d.selected = 0
+	case "G", "\x1b[F":
+		d.selected = len(d.visible()) - 1
+	case "/":
+		d.filtering = true
+	case "\x1b":
+		d.filter = ""
+	case "r":
+		visible := d.visible()
+		if len(visible) > 0 {
+			entry := visible[d.clampSelected(len(visible))]
+			go d.resend(entry)
+		}
+	}
+
+	d.selected = d.clampSelected(len(d.visible()))
+
+	return false
+}
+
+// Render returns the escape sequences and text to draw the whole dashboard
+func (d *Dashboard) Render() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	color := ansi.Color(d.Out)
+	visible := d.visible()
+	selected := d.clampSelected(len(visible))
+
+	listHeight := (d.height - chromeLines) / 2
+	detailHeight := d.height - chromeLines - listHeight
+
+	lines := make([]string, 0, d.height)
+
+	header := fmt.Sprintf("stripe listen — %d events", len(d.entries))
+	if d.filter != "" || d.filtering {
+		header += fmt.Sprintf(" (%d matching \"%s\")", len(visible), d.filter)
+	}
+	if d.status != "" {
+		header += " — " + d.status
+	}
+	lines = append(lines, color.Bold(fit(header, d.width)).String())
+
+	// Scroll the list so that the selected event is always in view
+	start := 0
+	if selected >= listHeight {
+		start = selected - listHeight + 1
+	}
+
+	for i := start; i < start+listHeight; i++ {
+		if i >= len(visible) {
+			lines = append(lines, "")
+			continue
+		}
+
+		line := fit(formatEntry(visible[i]), d.width)
+		if i == selected {
+			lines = append(lines, color.Reverse(line).String())
+		} else {
+			lines = append(lines, line)
+		}
+	}
+
+	lines = append(lines, color.Faint(strings.Repeat("─", d.width)).String())
+
+	var detail []string
+	if len(visible) > 0 {
+		detail = formatDetail(visible[selected])
+	}
+
+	for i := 0; i < detailHeight; i++ {
+		if i < len(detail) {
+			lines = append(lines, fit(detail[i], d.width))
+		} else {
+			lines = append(lines, "")
+		}
+	}
+
+	var help string
+	if d.filtering {
+		help = fmt.Sprintf("filter: %s█  (enter to apply, esc to clear)", d.filter)
+	} else {
+		help = "j/k: move  /: filter  esc: clear filter  r: resend  q: quit"
+	}
+	lines = append(lines, color.Faint(fit(help, d.width)).String())
+
+	return "\x1b[H\x1b[2J" + strings.Join(lines, "\r\n")
+}
+
+//
+// Private functions
+//
+
+func (d *Dashboard) redraw() {
+	select {
+	case d.redrawCh <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Dashboard) setSize(width, height int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if width > 0 {
+		d.width = width
+	}
+	if height > chromeLines {
+		d.height = height
+	}
+}
+
+// visible returns the entries matching the current filter. The caller must
+// hold the lock.
+func (d *Dashboard) visible() []*Entry {
+	if d.filter == "" {
+		return d.entries
+	}
+
+	filter := strings.ToLower(d.filter)
+	visible := make([]*Entry, 0, len(d.entries))
+
+	for _, entry := range d.entries {
+		if strings.Contains(strings.ToLower(entry.Event.Type), filter) ||
+			strings.Contains(strings.ToLower(entry.Event.ID), filter) {
+			visible = append(visible, entry)
+		}
+	}
+
+	return visible
+}
+
+func (d *Dashboard) clampSelected(n int) int {
+	if d.selected < 0 || n == 0 {
+		return 0
+	}
+	if d.selected >= n {
+		return n - 1
+	}
+	return d.selected
+}
+
+func (d *Dashboard) resend(entry *Entry) {
+	d.mu.Lock()
+	resp := entry.Response
+	secret := d.secret
+	d.mu.Unlock()
+
+	if resp == nil || resp.Resp == nil || resp.Resp.Request == nil {
+		d.SetStatus(fmt.Sprintf("%s has not been forwarded to an endpoint", entry.Event.ID))
+		return
+	}
+
+	orig := resp.Resp.Request
+
+	req, err := http.NewRequest(http.MethodPost, orig.URL.String(), bytes.NewBufferString(resp.RequestBody))
+	if err != nil {
+		d.SetStatus(fmt.Sprintf("Failed to resend %s: %v", entry.Event.ID, err))
+		return
+	}
+
+	// Reuse the headers from the original request so that custom forwarded
+	// headers are sent again. The original signature is re-generated, as it's
+	// rejected once it's older than the tolerance of Stripe's libraries.
+	req.Header = orig.Header.Clone()
+	req.Host = orig.Host
+	if secret != "" {
+		req.Header.Set("Stripe-Signature", webhooks.GenerateHeader([]byte(resp.RequestBody), secret, time.Now()))
+	}
+
+	newResp, err := d.Client.Do(req)
+	if err != nil {
+		d.SetStatus(fmt.Sprintf("Failed to resend %s: %v", entry.Event.ID, err))
+		return
+	}
+	defer newResp.Body.Close()
+
+	body, _ := io.ReadAll(newResp.Body)
+
+	d.mu.Lock()
+	entry.Response = &proxy.EndpointResponse{
+		Event:          resp.Event,
+		Resp:           newResp,
+		RequestBody:    resp.RequestBody,
+		RequestHeaders: resp.RequestHeaders,
+		ResponseBody:   string(body),
+	}
+	d.mu.Unlock()
+
+	d.SetStatus(fmt.Sprintf("Resent %s [%d]", entry.Event.ID, newResp.StatusCode))
+}
+
+func formatEntry(entry *Entry) string {
+	status := "   "
+	if entry.Response != nil && entry.Response.Resp != nil {
+		status = fmt.Sprintf("%d", entry.Response.Resp.StatusCode)
+	}
+
+	maybeConnect := ""
+	if entry.Event.IsConnect() {
+		maybeConnect = "connect "
+	}
+
+	return fmt.Sprintf("%s  [%s]  %s%s  %s",
+		entry.ReceivedAt.Format(timeLayout),
+		status,
+		maybeConnect,
+		entry.Event.Type,
+		entry.Event.ID,
+	)
+}
+
+func formatDetail(entry *Entry) []string {
+	lines := []string{
+		fmt.Sprintf("%s  %s", entry.Event.Type, entry.Event.ID),
+		entry.Event.URLForEventID(),
+		"",
+	}
+
+	resp := entry.Response
+	if resp == nil || resp.Resp == nil {
+		lines = append(lines, "Request: not forwarded")
+		lines = append(lines, splitLines(entry.Payload)...)
+		return lines
+	}
+
+	if resp.Resp.Request != nil {
+		lines = append(lines, fmt.Sprintf("Request: %s %s", resp.Resp.Request.Method, resp.Resp.Request.URL))
+	}
+	names := make([]string, 0, len(resp.RequestHeaders))
+	for k := range resp.RequestHeaders {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		lines = append(lines, fmt.Sprintf("  %s: %s", k, resp.RequestHeaders[k]))
+	}
+	lines = append(lines, splitLines(resp.RequestBody)...)
+
+	lines = append(lines, "", fmt.Sprintf("Response: %s", resp.Resp.Status))
+	lines = append(lines, splitLines(resp.ResponseBody)...)
+
+	return lines
+}
+
+func splitLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.Split(strings.TrimRight(s, "\n"), "\n")
+}
+
+// fit truncates or pads s so that it takes up exactly width columns
+func fit(s string, width int) string {
+	s = strings.ReplaceAll(s, "\t", "  ")
+
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		runes := []rune(s)
+		if width < 1 {
+			return ""
+		}
+		return string(runes[:width-1]) + "…"
+	}
+
+	return s + strings.Repeat(" ", width-n)
+}
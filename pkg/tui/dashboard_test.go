@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/stripe-cli/pkg/proxy"
+	"github.com/stripe/stripe-cli/pkg/webhooks"
+)
+
+func newTestDashboard() *Dashboard {
+	d := NewDashboard()
+	d.Out = &bytes.Buffer{}
+	return d
+}
+
+func TestDashboardFilter(t *testing.T) {
+	d := newTestDashboard()
+	d.AddEvent(proxy.StripeEvent{ID: "evt_1", Type: "charge.succeeded"}, "{}")
+	d.AddEvent(proxy.StripeEvent{ID: "evt_2", Type: "customer.created"}, "{}")
+	d.AddEvent(proxy.StripeEvent{ID: "evt_3", Type: "charge.refunded"}, "{}")
+
+	require.Len(t, d.visible(), 3)
+
+	d.HandleKey([]byte("/"))
+	for _, c := range "charge" {
+		d.HandleKey([]byte(string(c)))
+	}
+	// an empty read is ignored
+	require.False(t, d.HandleKey([]byte{}))
+	d.HandleKey([]byte("\r"))
+
+	require.Len(t, d.visible(), 2)
+	require.Contains(t, d.Render(), `2 matching "charge"`)
+
+	d.HandleKey([]byte("\x1b"))
+	require.Len(t, d.visible(), 3)
+}
+
+func TestDashboardSelection(t *testing.T) {
+	d := newTestDashboard()
+	d.AddEvent(proxy.StripeEvent{ID: "evt_1", Type: "charge.succeeded"}, "{}")
+	d.AddEvent(proxy.StripeEvent{ID: "evt_2", Type: "customer.created"}, "{}")
+
+	d.HandleKey([]byte("k"))
+	require.Equal(t, 0, d.selected)
+
+	d.HandleKey([]byte("j"))
+	d.HandleKey([]byte("j"))
+	require.Equal(t, 1, d.selected)
+
+	out := d.Render()
+	require.Contains(t, out, "customer.created  evt_2")
+	require.Contains(t, out, "Request: not forwarded")
+}
+
+func TestDashboardResend(t *testing.T) {
+	received := make(chan string, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		// the stale signature of the original request is replaced
+		verification, err := webhooks.Verify(body, r.Header.Get("Stripe-Signature"), "whsec_123", time.Now())
+		require.NoError(t, err)
+		require.True(t, verification.Valid)
+		require.True(t, verification.WithinTolerance)
+		require.Equal(t, "bar", r.Header.Get("X-Foo"))
+
+		received <- string(body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	evt := proxy.StripeEvent{ID: "evt_1", Type: "charge.succeeded"}
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"id":"evt_1"}`))
+	require.NoError(t, err)
+	req.Header.Set("Stripe-Signature", "t=123,v1=hunter2")
+	req.Header.Set("X-Foo", "bar")
+
+	d := newTestDashboard()
+	d.SetSecret("whsec_123")
+	d.AddEvent(evt, `{"id":"evt_1"}`)
+	d.AddResponse(proxy.EndpointResponse{
+		Event:        &evt,
+		Resp:         &http.Response{StatusCode: http.StatusInternalServerError, Status: "500 Internal Server Error", Request: req},
+		RequestBody:  `{"id":"evt_1"}`,
+		ResponseBody: "oops",
+	})
+
+	require.Contains(t, d.Render(), "Response: 500 Internal Server Error")
+
+	d.HandleKey([]byte("r"))
+
+	select {
+	case body := <-received:
+		require.Equal(t, `{"id":"evt_1"}`, body)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "event was not resent")
+	}
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(d.Render(), "Resent evt_1 [202]")
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,282 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+
+	"github.com/stripe/stripe-cli/pkg/ansi"
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// ErrSelectionCanceled is returned when the user quits the picker without
+// confirming a selection
+var ErrSelectionCanceled = clierrors.New(clierrors.SelectionCanceled, "selection canceled")
+
+// MultiSelect is an interactive picker to choose any number of items from a
+// long list. Items are grouped by the part of their name before the first
+// dot, and can be narrowed down by typing a fuzzy filter.
+type MultiSelect struct {
+	// Out is where the picker is drawn. Defaults to os.Stdout.
+	Out io.Writer
+
+	// In is where keystrokes are read from. Defaults to os.Stdin.
+	In *os.File
+
+	// Label is shown above the list
+	Label string
+
+	items    []string
+	chosen   map[string]bool
+	filter   string
+	selected int
+	height   int
+}
+
+// row is a line of the picker, either a group header or an item
+type row struct {
+	group string
+	item  string
+}
+
+// NewMultiSelect returns a picker for the given items
+func NewMultiSelect(label string, items []string) *MultiSelect {
+	sorted := append([]string{}, items...)
+	sort.Strings(sorted)
+
+	return &MultiSelect{
+		Out:    os.Stdout,
+		In:     os.Stdin,
+		Label:  label,
+		items:  sorted,
+		chosen: make(map[string]bool),
+		height: defaultHeight,
+	}
+}
+
+// Run takes over the terminal until the user confirms or cancels, and returns
+// the chosen items in sorted order
+func (m *MultiSelect) Run() ([]string, error) {
+	fd := int(m.In.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, clierrors.New(clierrors.TerminalRequired, "selecting interactively requires a terminal")
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	defer term.Restore(fd, state)
+
+	fmt.Fprint(m.Out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(m.Out, "\x1b[?25h\x1b[?1049l")
+
+	buf := make([]byte, 16)
+	for {
+		if _, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil && h > chromeLines {
+			m.height = h
+		}
+
+		fmt.Fprint(m.Out, m.Render())
+
+		n, err := m.In.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		done, err := m.HandleKey(buf[:n])
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return m.Chosen(), nil
+		}
+	}
+}
+
+// HandleKey updates the picker for a keystroke. It returns true once the user
+// confirmed the selection, and ErrSelectionCanceled if they quit.
+func (m *MultiSelect) HandleKey(key []byte) (bool, error) {
+	rows := m.rows()
+
+	switch string(key) {
+	case "\x03", "\x1b":
+		return false, ErrSelectionCanceled
+	case "\r", "\n":
+		return true, nil
+	case "\x1b[B", "\x0e":
+		m.selected++
+	case "\x1b[A", "\x10":
+		m.selected--
+	case " ", "\t":
+		if len(rows) > 0 {
+			m.toggle(rows[m.clampSelected(len(rows))])
+		}
+	case "\x7f", "\x08":
+		if len(m.filter) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.filter)
+			m.filter = m.filter[:len(m.filter)-size]
+			m.selected = 0
+		}
+	default:
+		if key[0] >= 0x20 && key[0] != 0x7f {
+			m.filter += string(key)
+			m.selected = 0
+		}
+	}
+
+	m.selected = m.clampSelected(len(m.rows()))
+
+	return false, nil
+}
+
+// Chosen returns the chosen items in sorted order
+func (m *MultiSelect) Chosen() []string {
+	chosen := make([]string, 0, len(m.chosen))
+	for _, item := range m.items {
+		if m.chosen[item] {
+			chosen = append(chosen, item)
+		}
+	}
+
+	return chosen
+}
+
+// Render returns the escape sequences and text to draw the whole picker
+func (m *MultiSelect) Render() string {
+	color := ansi.Color(m.Out)
+	rows := m.rows()
+	selected := m.clampSelected(len(rows))
+	listHeight := m.height - chromeLines
+
+	lines := []string{
+		color.Bold(fmt.Sprintf("%s (%d selected)", m.Label, len(m.chosen))).String(),
+		fmt.Sprintf("filter: %s█", m.filter),
+	}
+
+	// Scroll the list so that the selected row is always in view
+	start := 0
+	if selected >= listHeight {
+		start = selected - listHeight + 1
+	}
+
+	for i := start; i < start+listHeight && i < len(rows); i++ {
+		var line string
+		if rows[i].item == "" {
+			line = fmt.Sprintf("%s %s", m.checkbox(m.groupChosen(rows[i].group)), color.Bold(rows[i].group))
+		} else {
+			line = fmt.Sprintf("    %s %s", m.checkbox(m.chosen[rows[i].item]), rows[i].item)
+		}
+
+		if i == selected {
+			line = color.Reverse(line).String()
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, color.Faint("type to filter  ↑/↓: move  space: toggle  enter: confirm  esc: cancel").String())
+
+	return "\x1b[H\x1b[2J" + strings.Join(lines, "\r\n")
+}
+
+//
+// Private functions
+//
+
+// rows returns the group headers and items matching the current filter
+func (m *MultiSelect) rows() []row {
+	var rows []row
+	lastGroup := ""
+
+	for _, item := range m.items {
+		if !fuzzyMatch(item, m.filter) {
+			continue
+		}
+
+		group := groupOf(item)
+		if group != lastGroup {
+			rows = append(rows, row{group: group})
+			lastGroup = group
+		}
+		rows = append(rows, row{group: group, item: item})
+	}
+
+	return rows
+}
+
+// toggle flips an item, or every visible item of a group
+func (m *MultiSelect) toggle(r row) {
+	if r.item != "" {
+		if m.chosen[r.item] {
+			delete(m.chosen, r.item)
+		} else {
+			m.chosen[r.item] = true
+		}
+		return
+	}
+
+	choose := !m.groupChosen(r.group)
+	for _, other := range m.rows() {
+		if other.group != r.group || other.item == "" {
+			continue
+		}
+
+		if choose {
+			m.chosen[other.item] = true
+		} else {
+			delete(m.chosen, other.item)
+		}
+	}
+}
+
+// groupChosen returns true if every visible item of the group is chosen
+func (m *MultiSelect) groupChosen(group string) bool {
+	for _, r := range m.rows() {
+		if r.group == group && r.item != "" && !m.chosen[r.item] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *MultiSelect) checkbox(checked bool) string {
+	if checked {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+func (m *MultiSelect) clampSelected(n int) int {
+	if m.selected < 0 || n == 0 {
+		return 0
+	}
+	if m.selected >= n {
+		return n - 1
+	}
+	return m.selected
+}
+
+func groupOf(item string) string {
+	return strings.SplitN(item, ".", 2)[0]
+}
+
+// fuzzyMatch returns true if every character of the filter appears in s, in
+// order
+func fuzzyMatch(s, filter string) bool {
+	s = strings.ToLower(s)
+	for _, c := range strings.ToLower(filter) {
+		i := strings.IndexRune(s, c)
+		if i < 0 {
+			return false
+		}
+		s = s[i+utf8.RuneLen(c):]
+	}
+
+	return true
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMultiSelect() *MultiSelect {
+	m := NewMultiSelect("Pick", []string{"customer.created", "charge.succeeded", "charge.refunded", "customer.deleted"})
+	m.Out = &bytes.Buffer{}
+	return m
+}
+
+func TestMultiSelectGroupsItems(t *testing.T) {
+	m := newTestMultiSelect()
+
+	require.Equal(t, []row{
+		{group: "charge"},
+		{group: "charge", item: "charge.refunded"},
+		{group: "charge", item: "charge.succeeded"},
+		{group: "customer"},
+		{group: "customer", item: "customer.created"},
+		{group: "customer", item: "customer.deleted"},
+	}, m.rows())
+}
+
+func TestMultiSelectToggle(t *testing.T) {
+	m := newTestMultiSelect()
+
+	m.HandleKey([]byte("\x1b[B"))
+	m.HandleKey([]byte(" "))
+	require.Equal(t, []string{"charge.refunded"}, m.Chosen())
+
+	m.HandleKey([]byte(" "))
+	require.Empty(t, m.Chosen())
+
+	// toggling a group header chooses every item of the group
+	m.HandleKey([]byte("\x1b[B"))
+	m.HandleKey([]byte("\x1b[B"))
+	m.HandleKey([]byte(" "))
+	require.Equal(t, []string{"customer.created", "customer.deleted"}, m.Chosen())
+
+	done, err := m.HandleKey([]byte("\r"))
+	require.NoError(t, err)
+	require.True(t, done)
+}
+
+func TestMultiSelectFilter(t *testing.T) {
+	m := newTestMultiSelect()
+
+	for _, c := range "chrf" {
+		m.HandleKey([]byte(string(c)))
+	}
+	require.Equal(t, []row{
+		{group: "charge"},
+		{group: "charge", item: "charge.refunded"},
+	}, m.rows())
+
+	// toggling a group while filtering only chooses the matching items
+	m.HandleKey([]byte(" "))
+	require.Equal(t, []string{"charge.refunded"}, m.Chosen())
+	require.Contains(t, m.Render(), "filter: chrf")
+
+	m.HandleKey([]byte("\x7f"))
+	require.Len(t, m.rows(), 3)
+}
+
+func TestMultiSelectCancel(t *testing.T) {
+	m := newTestMultiSelect()
+
+	_, err := m.HandleKey([]byte("\x1b"))
+	require.Equal(t, ErrSelectionCanceled, err)
+}
@@ -1,10 +1,11 @@
 package validators
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/spf13/cobra"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 )
 
 func getCommandPath(cmd *cobra.Command) string {
@@ -28,7 +29,7 @@ func NoArgs(cmd *cobra.Command, args []string) error {
 	)
 
 	if len(args) > 0 {
-		return errors.New(errorMessage)
+		return clierrors.New(clierrors.InvalidArgs, errorMessage)
 	}
 
 	return nil
@@ -53,7 +54,7 @@ func ExactArgs(num int) cobra.PositionalArgs {
 		)
 
 		if len(args) != num {
-			return errors.New(errorMessage)
+			return clierrors.New(clierrors.InvalidArgs, errorMessage)
 		}
 		return nil
 	}
@@ -78,7 +79,7 @@ func MaximumNArgs(num int) cobra.PositionalArgs {
 		)
 
 		if len(args) > num {
-			return errors.New(errorMessage)
+			return clierrors.New(clierrors.InvalidArgs, errorMessage)
 		}
 		return nil
 	}
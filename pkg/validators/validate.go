@@ -1,11 +1,11 @@
 package validators
 
 import (
-	"errors"
-	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 )
 
 // ArgValidator is an argument validator. It accepts a string and returns an
@@ -14,11 +14,13 @@ type ArgValidator func(string) error
 
 var (
 	// ErrAPIKeyNotConfigured is the error returned when the loaded profile is missing the api key property
-	ErrAPIKeyNotConfigured = errors.New("you have not configured API keys yet")
+	ErrAPIKeyNotConfigured = clierrors.New(clierrors.APIKeyNotConfigured, "you have not configured API keys yet")
 	// ErrDeviceNameNotConfigured is the error returned when the loaded profile is missing the device name property
-	ErrDeviceNameNotConfigured = errors.New("you have not configured your device name yet")
+	ErrDeviceNameNotConfigured = clierrors.New(clierrors.DeviceNameNotConfigured, "you have not configured your device name yet")
 	// ErrAccountIDNotConfigured is the error returned when the loaded profile is missing the account_id property
-	ErrAccountIDNotConfigured = errors.New("you have not configured your accountID yet")
+	ErrAccountIDNotConfigured = clierrors.New(clierrors.AccountIDNotConfigured, "you have not configured your accountID yet")
+	// ErrProfileExpired is the error returned when the loaded profile was ephemeral and expired, which removed its keys
+	ErrProfileExpired = clierrors.New(clierrors.ProfileExpired, "the API keys of this profile expired and were removed")
 )
 
 // CallNonEmptyArray calls an argument validator on all non-empty elements of
@@ -53,16 +55,16 @@ func APIKey(input string) error {
 	if len(input) == 0 {
 		return ErrAPIKeyNotConfigured
 	} else if len(input) < 12 {
-		return errors.New("the API key provided is too short, it must be at least 12 characters long")
+		return clierrors.New(clierrors.InvalidAPIKey, "the API key provided is too short, it must be at least 12 characters long")
 	}
 
 	keyParts := strings.Split(input, "_")
 	if len(keyParts) < 3 {
-		return errors.New("you are using a legacy-style API key which is unsupported by the CLI. Please generate a new test mode API key")
+		return clierrors.New(clierrors.InvalidAPIKey, "you are using a legacy-style API key which is unsupported by the CLI. Please generate a new test mode API key")
 	}
 
 	if keyParts[0] != "sk" && keyParts[0] != "rk" {
-		return errors.New("the CLI only supports using a secret or restricted key")
+		return clierrors.New(clierrors.InvalidAPIKey, "the CLI only supports using a secret or restricted key")
 	}
 
 	return nil
@@ -73,16 +75,16 @@ func APIKeyNotRestricted(input string) error {
 	if len(input) == 0 {
 		return ErrAPIKeyNotConfigured
 	} else if len(input) < 12 {
-		return errors.New("the API key provided is too short, it must be at least 12 characters long")
+		return clierrors.New(clierrors.InvalidAPIKey, "the API key provided is too short, it must be at least 12 characters long")
 	}
 
 	keyParts := strings.Split(input, "_")
 	if len(keyParts) < 3 {
-		return errors.New("you are using a legacy-style API key which is unsupported by the CLI. Please generate a new test mode API key")
+		return clierrors.New(clierrors.InvalidAPIKey, "you are using a legacy-style API key which is unsupported by the CLI. Please generate a new test mode API key")
 	}
 
 	if keyParts[0] != "sk" || keyParts[0] == "rk" {
-		return errors.New("this CLI command only supports using a secret key. Please re-run using the --api-key flag override with your secret API key")
+		return clierrors.New(clierrors.InvalidAPIKey, "this CLI command only supports using a secret key. Please re-run using the --api-key flag override with your secret API key")
 	}
 
 	return nil
@@ -96,7 +98,7 @@ func Account(account string) error {
 		return nil
 	}
 
-	return fmt.Errorf("%s is not an acceptable account filter (CONNECT_IN, CONNECT_OUT, SELF)", account)
+	return clierrors.Errorf(clierrors.InvalidFlagValue, "%s is not an acceptable account filter (CONNECT_IN, CONNECT_OUT, SELF)", account)
 }
 
 // HTTPMethod validates that a string is an acceptable HTTP method.
@@ -107,7 +109,7 @@ func HTTPMethod(method string) error {
 		return nil
 	}
 
-	return fmt.Errorf("%s is not an acceptable HTTP method (GET, POST, DELETE)", method)
+	return clierrors.Errorf(clierrors.InvalidFlagValue, "%s is not an acceptable HTTP method (GET, POST, DELETE)", method)
 }
 
 // RequestSource validates that a string is an acceptable request source.
@@ -118,7 +120,7 @@ func RequestSource(source string) error {
 		return nil
 	}
 
-	return fmt.Errorf("%s is not an acceptable source (API, DASHBOARD)", source)
+	return clierrors.Errorf(clierrors.InvalidFlagValue, "%s is not an acceptable source (API, DASHBOARD)", source)
 }
 
 // RequestStatus validates that a string is an acceptable request status.
@@ -129,7 +131,7 @@ func RequestStatus(status string) error {
 		return nil
 	}
 
-	return fmt.Errorf("%s is not an acceptable request status (SUCCEEDED, FAILED)", status)
+	return clierrors.Errorf(clierrors.InvalidFlagValue, "%s is not an acceptable request status (SUCCEEDED, FAILED)", status)
 }
 
 // StatusCode validates that a provided status code is within the range of
@@ -148,7 +150,7 @@ func StatusCode(code string) error {
 		return nil
 	}
 
-	return fmt.Errorf("Provided status code %s is not in the range of acceptable status codes (200's, 400's, 500's)", code)
+	return clierrors.Errorf(clierrors.InvalidFlagValue, "Provided status code %s is not in the range of acceptable status codes (200's, 400's, 500's)", code)
 }
 
 // StatusCodeType validates that a provided status code type is one of those
@@ -157,7 +159,7 @@ func StatusCodeType(code string) error {
 	codeUpper := strings.ToUpper(code)
 
 	if codeUpper != "2XX" && codeUpper != "4XX" && codeUpper != "5XX" {
-		return fmt.Errorf("Provided status code type %s is not a valid type (2XX, 4XX, 5XX)", code)
+		return clierrors.Errorf(clierrors.InvalidFlagValue, "Provided status code type %s is not a valid type (2XX, 4XX, 5XX)", code)
 	}
 
 	return nil
@@ -167,12 +169,12 @@ func StatusCodeType(code string) error {
 func OneDollar(number string) error {
 	num, err := strconv.Atoi(number)
 	if err != nil {
-		return fmt.Errorf("Provided amount %v to charge should be an integer (eg. 100)", number)
+		return clierrors.Errorf(clierrors.InvalidFlagValue, "Provided amount %v to charge should be an integer (eg. 100)", number)
 	}
 
 	if num >= 100 {
 		return nil
 	}
 
-	return fmt.Errorf("Provided amount %v to charge is not at least 100", number)
+	return clierrors.Errorf(clierrors.InvalidFlagValue, "Provided amount %v to charge is not at least 100", number)
 }
@@ -0,0 +1,28 @@
+package webhooks
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClockSkewTolerance is how far the local clock can be from Stripe's before
+// it's worth warning about, well within DefaultTolerance but above the
+// precision of the measure
+const ClockSkewTolerance = time.Minute
+
+// ClockSkewWarning explains how a skew of the local clock from Stripe's, as
+// measured by stripe.ClockSkew, breaks signature verification. It's empty when
+// the skew is within ClockSkewTolerance.
+func ClockSkewWarning(skew time.Duration) string {
+	if skew > -ClockSkewTolerance && skew < ClockSkewTolerance {
+		return ""
+	}
+
+	if skew > 0 {
+		return fmt.Sprintf("your clock is %s ahead of Stripe's. Webhook signatures are checked against your clock, so they look %s old, and Stripe's libraries reject signatures older than %s. Sync your clock, such as by turning on automatic time (NTP).",
+			skew, skew, DefaultTolerance)
+	}
+
+	return fmt.Sprintf("your clock is %s behind Stripe's. Webhook signatures are checked against your clock, so they look %s in the future, which some libraries reject. Sync your clock, such as by turning on automatic time (NTP).",
+		-skew, -skew)
+}
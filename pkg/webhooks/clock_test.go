@@ -0,0 +1,18 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockSkewWarning(t *testing.T) {
+	require.Empty(t, ClockSkewWarning(0))
+	require.Empty(t, ClockSkewWarning(59*time.Second))
+	require.Empty(t, ClockSkewWarning(-59*time.Second))
+
+	require.Equal(t, "your clock is 7m12s ahead of Stripe's. Webhook signatures are checked against your clock, so they look 7m12s old, and Stripe's libraries reject signatures older than 5m0s. Sync your clock, such as by turning on automatic time (NTP).",
+		ClockSkewWarning(7*time.Minute+12*time.Second))
+	require.Contains(t, ClockSkewWarning(-2*time.Minute), "your clock is 2m0s behind Stripe's.")
+}
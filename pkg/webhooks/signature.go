@@ -0,0 +1,139 @@
+// Package webhooks computes and verifies the signatures Stripe sends in the
+// Stripe-Signature header of webhook requests.
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
+)
+
+// DefaultTolerance is how old a signature can be before Stripe's libraries
+// reject it, to protect against replay attacks
+const DefaultTolerance = 5 * time.Minute
+
+// signingScheme is the only scheme Stripe signs webhooks with. Test mode
+// events also carry a v0 signature, which is ignored by the libraries.
+const signingScheme = "v1"
+
+// ErrInvalidHeader is returned when a Stripe-Signature header can't be parsed
+var ErrInvalidHeader = clierrors.New(clierrors.InvalidSignatureHeader, "invalid Stripe-Signature header, expected a format like 't=1600000000,v1=5257a869...'")
+
+// SignatureHeader is the parsed content of a Stripe-Signature header
+type SignatureHeader struct {
+	Timestamp  time.Time
+	Signatures []string
+}
+
+// Verification is the outcome of checking a payload against a signature header
+type Verification struct {
+	// Header is the parsed signature header
+	Header SignatureHeader
+	// Expected is the v1 signature computed from the payload and secret
+	Expected string
+	// Valid is true if one of the header's signatures matches Expected
+	Valid bool
+	// Skew is how long ago the header was signed. It's negative when the
+	// timestamp is in the future.
+	Skew time.Duration
+	// WithinTolerance is true if the skew is small enough for Stripe's
+	// libraries to accept the signature
+	WithinTolerance bool
+	// TrailingNewline is true if the signature doesn't match the payload as
+	// given, but matches once trailing newlines are removed, which happens
+	// when a payload is copied to a file by hand
+	TrailingNewline bool
+}
+
+// ComputeSignature returns the hex-encoded v1 signature of a payload signed at
+// the given time
+func ComputeSignature(payload []byte, secret string, timestamp time.Time) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%d.", timestamp.Unix())))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateHeader returns a Stripe-Signature header for the payload, as Stripe
+// would send it
+func GenerateHeader(payload []byte, secret string, timestamp time.Time) string {
+	return fmt.Sprintf("t=%d,%s=%s", timestamp.Unix(), signingScheme, ComputeSignature(payload, secret, timestamp))
+}
+
+// ParseHeader parses a Stripe-Signature header. Only v1 signatures are kept.
+func ParseHeader(header string) (SignatureHeader, error) {
+	parsed := SignatureHeader{}
+	hasTimestamp := false
+
+	for _, pair := range strings.Split(header, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 {
+			return SignatureHeader{}, ErrInvalidHeader
+		}
+
+		switch parts[0] {
+		case "t":
+			seconds, err := strconv.ParseInt(parts[1], 10, 64)
+			if err != nil {
+				return SignatureHeader{}, ErrInvalidHeader
+			}
+			parsed.Timestamp = time.Unix(seconds, 0)
+			hasTimestamp = true
+		case signingScheme:
+			parsed.Signatures = append(parsed.Signatures, parts[1])
+		}
+	}
+
+	if !hasTimestamp || len(parsed.Signatures) == 0 {
+		return SignatureHeader{}, ErrInvalidHeader
+	}
+
+	return parsed, nil
+}
+
+// Verify checks the payload against a Stripe-Signature header. An error is
+// only returned if the header is malformed: whether the signature matches is
+// reported in the Verification.
+func Verify(payload []byte, header, secret string, now time.Time) (*Verification, error) {
+	parsed, err := ParseHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	skew := now.Sub(parsed.Timestamp)
+
+	v := &Verification{
+		Header:          parsed,
+		Expected:        ComputeSignature(payload, secret, parsed.Timestamp),
+		Skew:            skew,
+		WithinTolerance: skew <= DefaultTolerance,
+	}
+	v.Valid = matchesAny(v.Expected, parsed.Signatures)
+
+	if !v.Valid {
+		trimmed := bytes.TrimRight(payload, "\r\n")
+		if len(trimmed) != len(payload) {
+			v.TrailingNewline = matchesAny(ComputeSignature(trimmed, secret, parsed.Timestamp), parsed.Signatures)
+		}
+	}
+
+	return v, nil
+}
+
+func matchesAny(expected string, signatures []string) bool {
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(expected), []byte(signature)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,70 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "whsec_test_secret"
+
+var testPayload = []byte(`{"id":"evt_123","object":"event"}`)
+
+func TestGenerateHeaderRoundTrip(t *testing.T) {
+	signedAt := time.Unix(1600000000, 0)
+	header := GenerateHeader(testPayload, testSecret, signedAt)
+
+	v, err := Verify(testPayload, header, testSecret, signedAt.Add(30*time.Second))
+	require.NoError(t, err)
+	require.True(t, v.Valid)
+	require.True(t, v.WithinTolerance)
+	require.Equal(t, 30*time.Second, v.Skew)
+}
+
+func TestComputeSignature(t *testing.T) {
+	// computed with: echo -n '1600000000.{"id":"evt_123","object":"event"}' | openssl dgst -sha256 -hmac whsec_test_secret
+	require.Equal(t, "41a72e1d671948e5d99efcc6024563d8e7cdc708aa8bb188a5614cac1ec775d5", ComputeSignature(testPayload, testSecret, time.Unix(1600000000, 0)))
+}
+
+func TestVerifyMismatch(t *testing.T) {
+	signedAt := time.Unix(1600000000, 0)
+	header := GenerateHeader(testPayload, "whsec_other", signedAt)
+
+	v, err := Verify(testPayload, header, testSecret, signedAt)
+	require.NoError(t, err)
+	require.False(t, v.Valid)
+	require.False(t, v.TrailingNewline)
+}
+
+func TestVerifyTrailingNewline(t *testing.T) {
+	signedAt := time.Unix(1600000000, 0)
+	header := GenerateHeader(testPayload, testSecret, signedAt)
+
+	v, err := Verify(append(testPayload, '\n'), header, testSecret, signedAt)
+	require.NoError(t, err)
+	require.False(t, v.Valid)
+	require.True(t, v.TrailingNewline)
+}
+
+func TestVerifyOutsideTolerance(t *testing.T) {
+	signedAt := time.Unix(1600000000, 0)
+	header := GenerateHeader(testPayload, testSecret, signedAt)
+
+	v, err := Verify(testPayload, header, testSecret, signedAt.Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, v.Valid)
+	require.False(t, v.WithinTolerance)
+}
+
+func TestParseHeader(t *testing.T) {
+	header, err := ParseHeader("t=1600000000,v1=abc,v0=ignored,v1=def")
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(1600000000, 0), header.Timestamp)
+	require.Equal(t, []string{"abc", "def"}, header.Signatures)
+
+	for _, invalid := range []string{"", "v1=abc", "t=1600000000", "t=now,v1=abc", "garbage"} {
+		_, err := ParseHeader(invalid)
+		require.Equal(t, ErrInvalidHeader, err, invalid)
+	}
+}
@@ -2,7 +2,8 @@ package websocket
 
 import (
 	"encoding/json"
-	"fmt"
+
+	"github.com/stripe/stripe-cli/pkg/clierrors"
 )
 
 // IncomingMessage represents any incoming message sent by Stripe.
@@ -37,7 +38,7 @@ func (m *IncomingMessage) UnmarshalJSON(data []byte) error {
 
 		m.RequestLogEvent = &evt
 	default:
-		return fmt.Errorf("Unexpected message type: %s", incomingMessageTypeOnly.Type)
+		return clierrors.Errorf(clierrors.UnexpectedResponse, "Unexpected message type: %s", incomingMessageTypeOnly.Type)
 	}
 
 	return nil
@@ -28,6 +28,9 @@ type StripeCLIClient interface {
 	Fixture(ctx context.Context, in *FixtureRequest, opts ...grpc.CallOption) (*FixtureResponse, error)
 	// Receive webhook events from the Stripe API to your local machine. Like `stripe listen`.
 	Listen(ctx context.Context, in *ListenRequest, opts ...grpc.CallOption) (StripeCLI_ListenClient, error)
+	// Acknowledge an event received from a `Listen` stream with an `ack_consumer`, or reject it so
+	// that it's redelivered.
+	ListenAck(ctx context.Context, in *ListenAckRequest, opts ...grpc.CallOption) (*ListenAckResponse, error)
 	// Get a link to log in to the Stripe CLI. The client will have to open the browser to complete
 	// the login. Use `LoginStatus` after this method to wait for success. Like `stripe login`.
 	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
@@ -112,6 +115,15 @@ func (x *stripeCLIListenClient) Recv() (*ListenResponse, error) {
 	return m, nil
 }
 
+func (c *stripeCLIClient) ListenAck(ctx context.Context, in *ListenAckRequest, opts ...grpc.CallOption) (*ListenAckResponse, error) {
+	out := new(ListenAckResponse)
+	err := c.cc.Invoke(ctx, "/rpc.StripeCLI/ListenAck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *stripeCLIClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
 	out := new(LoginResponse)
 	err := c.cc.Invoke(ctx, "/rpc.StripeCLI/Login", in, out, opts...)
@@ -244,6 +256,9 @@ type StripeCLIServer interface {
 	Fixture(context.Context, *FixtureRequest) (*FixtureResponse, error)
 	// Receive webhook events from the Stripe API to your local machine. Like `stripe listen`.
 	Listen(*ListenRequest, StripeCLI_ListenServer) error
+	// Acknowledge an event received from a `Listen` stream with an `ack_consumer`, or reject it so
+	// that it's redelivered.
+	ListenAck(context.Context, *ListenAckRequest) (*ListenAckResponse, error)
 	// Get a link to log in to the Stripe CLI. The client will have to open the browser to complete
 	// the login. Use `LoginStatus` after this method to wait for success. Like `stripe login`.
 	Login(context.Context, *LoginRequest) (*LoginResponse, error)
@@ -283,6 +298,9 @@ func (UnimplementedStripeCLIServer) Fixture(context.Context, *FixtureRequest) (*
 func (UnimplementedStripeCLIServer) Listen(*ListenRequest, StripeCLI_ListenServer) error {
 	return status.Errorf(codes.Unimplemented, "method Listen not implemented")
 }
+func (UnimplementedStripeCLIServer) ListenAck(context.Context, *ListenAckRequest) (*ListenAckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListenAck not implemented")
+}
 func (UnimplementedStripeCLIServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
 }
@@ -385,6 +403,24 @@ func (x *stripeCLIListenServer) Send(m *ListenResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _StripeCLI_ListenAck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListenAckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StripeCLIServer).ListenAck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.StripeCLI/ListenAck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StripeCLIServer).ListenAck(ctx, req.(*ListenAckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _StripeCLI_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(LoginRequest)
 	if err := dec(in); err != nil {
@@ -601,6 +637,10 @@ var StripeCLI_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Fixture",
 			Handler:    _StripeCLI_Fixture_Handler,
 		},
+		{
+			MethodName: "ListenAck",
+			Handler:    _StripeCLI_ListenAck_Handler,
+		},
 		{
 			MethodName: "Login",
 			Handler:    _StripeCLI_Login_Handler,
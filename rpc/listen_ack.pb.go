@@ -0,0 +1,242 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.21.2
+// source: listen_ack.proto
+
+package rpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListenAckRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The ack_consumer of the `Listen` stream which received the event
+	Consumer string `protobuf:"bytes,1,opt,name=consumer,proto3" json:"consumer,omitempty"`
+	// ID of the event
+	EventId string `protobuf:"bytes,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// Reject the event instead of acknowledging it. It's redelivered right away, or dead-lettered once
+	// it was delivered max_delivery_attempts times.
+	Nack bool `protobuf:"varint,3,opt,name=nack,proto3" json:"nack,omitempty"`
+	// Why the event was rejected, written to the dead letter file
+	Reason string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *ListenAckRequest) Reset() {
+	*x = ListenAckRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_listen_ack_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListenAckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListenAckRequest) ProtoMessage() {}
+
+func (x *ListenAckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_listen_ack_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListenAckRequest.ProtoReflect.Descriptor instead.
+func (*ListenAckRequest) Descriptor() ([]byte, []int) {
+	return file_listen_ack_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListenAckRequest) GetConsumer() string {
+	if x != nil {
+		return x.Consumer
+	}
+	return ""
+}
+
+func (x *ListenAckRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *ListenAckRequest) GetNack() bool {
+	if x != nil {
+		return x.Nack
+	}
+	return false
+}
+
+func (x *ListenAckRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ListenAckResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Whether the rejected event was dead-lettered instead of being redelivered
+	DeadLettered bool `protobuf:"varint,1,opt,name=dead_lettered,json=deadLettered,proto3" json:"dead_lettered,omitempty"`
+}
+
+func (x *ListenAckResponse) Reset() {
+	*x = ListenAckResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_listen_ack_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListenAckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListenAckResponse) ProtoMessage() {}
+
+func (x *ListenAckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_listen_ack_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListenAckResponse.ProtoReflect.Descriptor instead.
+func (*ListenAckResponse) Descriptor() ([]byte, []int) {
+	return file_listen_ack_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListenAckResponse) GetDeadLettered() bool {
+	if x != nil {
+		return x.DeadLettered
+	}
+	return false
+}
+
+var File_listen_ack_proto protoreflect.FileDescriptor
+
+var file_listen_ack_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x5f, 0x61, 0x63, 0x6b, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x03, 0x72, 0x70, 0x63, 0x22, 0x75, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x65,
+	0x6e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x63,
+	0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63,
+	0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x04, 0x6e, 0x61, 0x63, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x38,
+	0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x65, 0x61, 0x64, 0x5f, 0x6c, 0x65, 0x74, 0x74,
+	0x65, 0x72, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x64, 0x65, 0x61, 0x64,
+	0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x65, 0x64, 0x42, 0x22, 0x5a, 0x20, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x74, 0x72, 0x69, 0x70, 0x65, 0x2f, 0x73, 0x74,
+	0x72, 0x69, 0x70, 0x65, 0x2d, 0x63, 0x6c, 0x69, 0x2f, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_listen_ack_proto_rawDescOnce sync.Once
+	file_listen_ack_proto_rawDescData = file_listen_ack_proto_rawDesc
+)
+
+func file_listen_ack_proto_rawDescGZIP() []byte {
+	file_listen_ack_proto_rawDescOnce.Do(func() {
+		file_listen_ack_proto_rawDescData = protoimpl.X.CompressGZIP(file_listen_ack_proto_rawDescData)
+	})
+	return file_listen_ack_proto_rawDescData
+}
+
+var file_listen_ack_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_listen_ack_proto_goTypes = []interface{}{
+	(*ListenAckRequest)(nil),  // 0: rpc.ListenAckRequest
+	(*ListenAckResponse)(nil), // 1: rpc.ListenAckResponse
+}
+var file_listen_ack_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_listen_ack_proto_init() }
+func file_listen_ack_proto_init() {
+	if File_listen_ack_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_listen_ack_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListenAckRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_listen_ack_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListenAckResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_listen_ack_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_listen_ack_proto_goTypes,
+		DependencyIndexes: file_listen_ack_proto_depIdxs,
+		MessageInfos:      file_listen_ack_proto_msgTypes,
+	}.Build()
+	File_listen_ack_proto = out.File
+	file_listen_ack_proto_rawDesc = nil
+	file_listen_ack_proto_goTypes = nil
+	file_listen_ack_proto_depIdxs = nil
+}